@@ -18,33 +18,56 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"runtime/debug"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/spf13/cobra"
+	"github.com/scttfrdmn/bagboy/pkg/attest"
+	"github.com/scttfrdmn/bagboy/pkg/audit"
 	"github.com/scttfrdmn/bagboy/pkg/benchmark"
+	"github.com/scttfrdmn/bagboy/pkg/build"
+	"github.com/scttfrdmn/bagboy/pkg/buildenv"
+	"github.com/scttfrdmn/bagboy/pkg/changelog"
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/delta"
 	"github.com/scttfrdmn/bagboy/pkg/deploy"
 	"github.com/scttfrdmn/bagboy/pkg/deps"
+	"github.com/scttfrdmn/bagboy/pkg/encrypt"
 	"github.com/scttfrdmn/bagboy/pkg/errors"
-	"github.com/scttfrdmn/bagboy/pkg/requirements"
-	"github.com/scttfrdmn/bagboy/pkg/signing"
-	"github.com/scttfrdmn/bagboy/pkg/ui"
+	"github.com/scttfrdmn/bagboy/pkg/export"
 	"github.com/scttfrdmn/bagboy/pkg/github"
+	"github.com/scttfrdmn/bagboy/pkg/gitlab"
+	"github.com/scttfrdmn/bagboy/pkg/gomodule"
+	"github.com/scttfrdmn/bagboy/pkg/hooks"
+	"github.com/scttfrdmn/bagboy/pkg/i18n"
 	initpkg "github.com/scttfrdmn/bagboy/pkg/init"
+	"github.com/scttfrdmn/bagboy/pkg/interrupt"
+	"github.com/scttfrdmn/bagboy/pkg/keys"
+	"github.com/scttfrdmn/bagboy/pkg/monorepo"
 	"github.com/scttfrdmn/bagboy/pkg/packager"
 	"github.com/scttfrdmn/bagboy/pkg/packager/appimage"
 	"github.com/scttfrdmn/bagboy/pkg/packager/apptainer"
 	"github.com/scttfrdmn/bagboy/pkg/packager/brew"
 	"github.com/scttfrdmn/bagboy/pkg/packager/cargo"
 	"github.com/scttfrdmn/bagboy/pkg/packager/chocolatey"
+	"github.com/scttfrdmn/bagboy/pkg/packager/conda"
 	"github.com/scttfrdmn/bagboy/pkg/packager/deb"
 	"github.com/scttfrdmn/bagboy/pkg/packager/dmg"
 	"github.com/scttfrdmn/bagboy/pkg/packager/docker"
+	"github.com/scttfrdmn/bagboy/pkg/packager/dotnet"
 	"github.com/scttfrdmn/bagboy/pkg/packager/flatpak"
+	"github.com/scttfrdmn/bagboy/pkg/packager/freebsd"
+	"github.com/scttfrdmn/bagboy/pkg/packager/gem"
+	"github.com/scttfrdmn/bagboy/pkg/packager/ghext"
 	"github.com/scttfrdmn/bagboy/pkg/packager/installer"
+	"github.com/scttfrdmn/bagboy/pkg/packager/maven"
 	"github.com/scttfrdmn/bagboy/pkg/packager/msi"
 	"github.com/scttfrdmn/bagboy/pkg/packager/msix"
 	"github.com/scttfrdmn/bagboy/pkg/packager/nix"
@@ -54,10 +77,68 @@ import (
 	"github.com/scttfrdmn/bagboy/pkg/packager/scoop"
 	"github.com/scttfrdmn/bagboy/pkg/packager/snap"
 	"github.com/scttfrdmn/bagboy/pkg/packager/spack"
+	"github.com/scttfrdmn/bagboy/pkg/packager/termux"
+	"github.com/scttfrdmn/bagboy/pkg/packager/webi"
 	"github.com/scttfrdmn/bagboy/pkg/packager/winget"
+	"github.com/scttfrdmn/bagboy/pkg/packager/xbps"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+	"github.com/scttfrdmn/bagboy/pkg/policy"
+	"github.com/scttfrdmn/bagboy/pkg/prebuilt"
+	"github.com/scttfrdmn/bagboy/pkg/preflight"
+	"github.com/scttfrdmn/bagboy/pkg/release"
+	"github.com/scttfrdmn/bagboy/pkg/requirements"
+	"github.com/scttfrdmn/bagboy/pkg/sbom"
+	"github.com/scttfrdmn/bagboy/pkg/schedule"
+	"github.com/scttfrdmn/bagboy/pkg/secrets"
+	"github.com/scttfrdmn/bagboy/pkg/selfupdate"
+	"github.com/scttfrdmn/bagboy/pkg/semver"
+	"github.com/scttfrdmn/bagboy/pkg/signing"
+	"github.com/scttfrdmn/bagboy/pkg/snippets"
+	"github.com/scttfrdmn/bagboy/pkg/spdx"
+	"github.com/scttfrdmn/bagboy/pkg/throttle"
+	"github.com/scttfrdmn/bagboy/pkg/ui"
+	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
+// Set by release builds with -ldflags -X, as the builds: section of
+// bagboy.yaml does
+var (
+	version string
+	commit  string
+	date    string
+)
+
+// buildInfo returns bagboy's version, commit and build date: those set at
+// release, else what go build or go install recorded, else dev
+func buildInfo() (string, string, string) {
+	v, c, d := version, commit, date
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if v == "" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			v = strings.TrimPrefix(info.Main.Version, "v")
+		}
+		for _, setting := range info.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && c == "":
+				c = setting.Value
+			case setting.Key == "vcs.time" && d == "":
+				d = setting.Value
+			}
+		}
+	}
+	if v == "" {
+		v = "dev"
+	}
+	return v, c, d
+}
+
+// bagboyVersion is reported by bagboy version and recorded in build
+// environment manifests
+func bagboyVersion() string {
+	v, _, _ := buildInfo()
+	return v
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "bagboy",
 	Short: "Universal software packager",
@@ -79,6 +160,24 @@ Examples:
 Learn more: https://bagboy.dev`,
 	SilenceErrors: true,  // We handle errors ourselves
 	SilenceUsage:  true,  // Don't show usage on errors
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if a11y, _ := cmd.Flags().GetBool("a11y"); a11y || os.Getenv("BAGBOY_A11Y") != "" {
+			if err := ui.EnableAccessible(); err != nil {
+				return err
+			}
+		}
+		if lang, _ := cmd.Flags().GetString("lang"); lang != "" {
+			if err := i18n.SetLanguage(lang); err != nil {
+				return err
+			}
+		}
+		if timeout, _ := cmd.Flags().GetDuration("timeout"); timeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			cmd.SetContext(ctx)
+			cobra.OnFinalize(cancel)
+		}
+		return nil
+	},
 }
 
 var initCmd = &cobra.Command{
@@ -92,13 +191,34 @@ Automatically detects:
 • Project metadata (name, version, description)
 • GitHub repository information
 • Existing binary locations
+• Packaging already in the repository: debian/control, snapcraft.yaml
+  and a handcrafted Homebrew formula
 
 Examples:
   bagboy init                    # Auto-detect project settings
   bagboy init --interactive      # Interactive configuration
-  bagboy init --name myapp       # Override detected name`,
+  bagboy init --name myapp       # Override detected name
+  bagboy init --from-goreleaser  # Import .goreleaser.yaml
+  bagboy init --from-goreleaser=ci/goreleaser.yml
+  bagboy init --with-make        # Also generate a Makefile
+  bagboy init --with-make=task   # Also generate a Taskfile.yml
+  bagboy init --no-import        # Ignore existing packaging configs
+
+With --from-goreleaser the builds, archives, brews, scoops, nfpms, dockers
+and release sections are translated. Archives become prebuilt globs, so
+bagboy packages and publishes what goreleaser built.
+
+The maintainer, dependencies, description, license and categories of
+existing packaging configs are imported, so curated metadata is kept.
+Dependencies go to dependencies.package_managers under apt or homebrew.
+
+With --with-make, build, pack, sign and publish targets are generated that
+pass signing and GitHub credentials through to bagboy. An existing Makefile
+or Taskfile.yml is never overwritten.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		interactive, _ := cmd.Flags().GetBool("interactive")
+		fromGoreleaser, _ := cmd.Flags().GetString("from-goreleaser")
+		withMake, _ := cmd.Flags().GetString("with-make")
 
 		ui.PrintBanner()
 		ui.Info("Initializing bagboy project...")
@@ -154,6 +274,45 @@ Examples:
 			},
 		}
 
+		if noImport, _ := cmd.Flags().GetBool("no-import"); !noImport {
+			for _, file := range initpkg.FindPackagingFiles(cfg.Name) {
+				notes, err := initpkg.ImportPackaging(file, cfg)
+				if err != nil {
+					ui.Warning(fmt.Sprintf("Could not import %s: %v", file.Path, err))
+					continue
+				}
+				ui.Success(fmt.Sprintf("Imported %s", file.Path))
+				for _, note := range notes {
+					ui.Warning(note)
+				}
+			}
+		}
+
+		if fromGoreleaser != "" {
+			path := fromGoreleaser
+			if path == "auto" {
+				if path, err = initpkg.FindGoreleaserConfig(); err != nil {
+					return err
+				}
+			}
+			notes, err := initpkg.FromGoreleaser(path, cfg)
+			if err != nil {
+				return err
+			}
+			ui.Success(fmt.Sprintf("Imported %s", path))
+			for _, note := range notes {
+				ui.Warning(note)
+			}
+		}
+
+		var workflowFile string
+		var workflowData []byte
+		if withMake != "" {
+			if workflowFile, workflowData, err = initpkg.Workflow(withMake, info, cfg); err != nil {
+				return err
+			}
+		}
+
 		data, err := yaml.Marshal(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to marshal config: %w", err)
@@ -164,6 +323,25 @@ Examples:
 		}
 
 		fmt.Println("✅ Created bagboy.yaml")
+
+		// Find out now, not at the first publish, if the name is taken
+		if offline, _ := cmd.Flags().GetBool("offline"); !offline {
+			for _, check := range preflight.NewNameChecker().CheckNames(cmd.Context(), cfg) {
+				if check.Status != preflight.StatusPass {
+					printChecks([]preflight.Check{check})
+				}
+			}
+		}
+
+		if workflowFile != "" {
+			if _, err := os.Stat(workflowFile); err == nil {
+				ui.Warning(fmt.Sprintf("%s already exists; not overwriting it", workflowFile))
+			} else if err := os.WriteFile(workflowFile, workflowData, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", workflowFile, err)
+			} else {
+				ui.Success(fmt.Sprintf("Created %s", workflowFile))
+			}
+		}
 		
 		ui.Header("Next Steps")
 		fmt.Println("1. Review and customize bagboy.yaml")
@@ -181,6 +359,149 @@ Examples:
 	},
 }
 
+// packFormat is a format that can be selected with its own pack flag
+type packFormat struct {
+	Name  string // registry name and flag name
+	Usage string // flag help
+	Label string // what the format creates, for the summary line
+}
+
+// packFormats lists every format in flag order
+var packFormats = []packFormat{
+	{"brew", "Create Homebrew formula", "brew formula"},
+	{"scoop", "Create Scoop manifest", "scoop manifest"},
+	{"deb", "Create DEB package", "deb package"},
+	{"rpm", "Create RPM package", "rpm package"},
+	{"chocolatey", "Create Chocolatey package", "chocolatey package"},
+	{"winget", "Create Winget manifests", "winget manifests"},
+	{"snap", "Create Snap package", "snap package"},
+	{"appimage", "Create AppImage", "appimage"},
+	{"flatpak", "Create Flatpak manifest", "flatpak manifest"},
+	{"npm", "Create npm package", "npm package"},
+	{"pypi", "Create PyPI package", "pypi package"},
+	{"docker", "Create Docker files", "docker files"},
+	{"apptainer", "Create Apptainer container", "apptainer container"},
+	{"dmg", "Create macOS DMG installer", "dmg installer"},
+	{"msi", "Create Windows MSI installer", "msi installer"},
+	{"msix", "Create Windows MSIX package", "msix package"},
+	{"cargo", "Create Rust Cargo package", "cargo package"},
+	{"nix", "Create Nix package", "nix package"},
+	{"spack", "Create Spack package", "spack package"},
+	{"conda", "Create conda recipe and package", "conda recipe"},
+	{"freebsd", "Create FreeBSD package and ports skeleton", "freebsd package"},
+	{"termux", "Create Termux build.sh and .deb", "termux package"},
+	{"xbps", "Create Void Linux xbps-src template", "xbps template"},
+	{"webi", "Create webi installer and eget/ubi-friendly release assets", "webi package and release assets"},
+	{"gh-extension", "Create gh CLI extension release assets", "gh extension assets"},
+	{"maven", "Create Maven artifact bundle and Gradle plugin wrapper", "maven bundle"},
+	{"dotnet", "Create .NET global tool package", ".NET tool package"},
+	{"gem", "Create Ruby gems", "gem"},
+	{"installer", "Create curl|bash installer", "installer script"},
+}
+
+// newRegistry returns a registry with every packager registered
+func newRegistry() *packager.Registry {
+	registry := packager.NewRegistry()
+	registry.Register(brew.New())
+	registry.Register(scoop.New())
+	registry.Register(deb.New())
+	registry.Register(rpm.New())
+	registry.Register(chocolatey.New())
+	registry.Register(winget.New())
+	registry.Register(snap.New())
+	registry.Register(appimage.New())
+	registry.Register(flatpak.New())
+	registry.Register(npm.New())
+	registry.Register(pypi.New())
+	registry.Register(docker.New())
+	registry.Register(apptainer.New())
+	registry.Register(dmg.New())
+	registry.Register(msi.New())
+	registry.Register(msix.New())
+	registry.Register(cargo.New())
+	registry.Register(nix.New())
+	registry.Register(spack.New())
+	registry.Register(conda.New())
+	registry.Register(freebsd.New())
+	registry.Register(termux.New())
+	registry.Register(xbps.New())
+	registry.Register(webi.New())
+	registry.Register(ghext.New())
+	registry.Register(maven.New())
+	registry.Register(dotnet.New())
+	registry.Register(gem.New())
+	registry.Register(installer.New())
+	return registry
+}
+
+// selectedFormats returns the formats named with --format followed by
+// those whose own flag is set, without duplicates
+func selectedFormats(cmd *cobra.Command, named []string) []string {
+	var formats []string
+	seen := make(map[string]bool)
+	add := func(format string) {
+		if !seen[format] {
+			seen[format] = true
+			formats = append(formats, format)
+		}
+	}
+	for _, format := range named {
+		add(format)
+	}
+	for _, f := range packFormats {
+		if set, _ := cmd.Flags().GetBool(f.Name); set {
+			add(f.Name)
+		}
+	}
+	return formats
+}
+
+// formatLabel describes what packing format creates
+func formatLabel(format string) string {
+	for _, f := range packFormats {
+		if f.Name == format {
+			return f.Label
+		}
+	}
+	return format
+}
+
+// cleanupInterrupted removes the files a cancelled command left
+// half-written and prints how to pick up where it stopped
+func cleanupInterrupted(ctx context.Context, guard *interrupt.Guard, resume string) {
+	if ctx.Err() == nil {
+		return
+	}
+	removed, err := guard.Cleanup()
+	for _, path := range removed {
+		fmt.Printf("🧹 %s\n", i18n.T("Removed partial %s", path))
+	}
+	if err != nil {
+		fmt.Printf("⚠️  %s\n", i18n.T("Cleanup incomplete: %v", err))
+	}
+	if resume != "" {
+		ui.Info("💡 " + resume)
+	}
+}
+
+// packResumeHint names the command that creates the formats not done yet
+func packResumeHint(formats, done []string) string {
+	finished := make(map[string]bool)
+	for _, format := range done {
+		finished[format] = true
+	}
+	var remaining []string
+	for _, format := range formats {
+		if !finished[format] {
+			remaining = append(remaining, format)
+		}
+	}
+	if len(remaining) == 0 {
+		return ""
+	}
+	return i18n.T("Finished packages were kept; run 'bagboy pack --format %s' to create the rest", strings.Join(remaining, ","))
+}
+
 var packCmd = &cobra.Command{
 	Use:     "pack",
 	Aliases: []string{"p", "package", "build"},
@@ -196,32 +517,32 @@ Supports 20+ package formats including:
 
 Examples:
   bagboy pack --all              # Create all supported formats
+  bagboy pack --format brew,scoop,deb
+                                 # Create formats by name (repeatable)
   bagboy pack --brew --scoop     # Create Homebrew and Scoop packages
   bagboy pack --deb --rpm        # Create Linux packages
-  bagboy pack --docker --sign    # Create Docker image with signing`,
+  bagboy pack --docker --sign    # Create Docker image with signing
+
+Pre-built archives:
+  With prebuilt.archives set, binaries are extracted from archives built
+  elsewhere (goreleaser, make) and matched by glob per platform:
+
+    prebuilt:
+      dir: dist
+      archives:
+        linux-amd64: "*_linux_amd64.tar.gz"
+        windows-amd64: "*_windows_amd64.zip"`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		all, _ := cmd.Flags().GetBool("all")
 		sign, _ := cmd.Flags().GetBool("sign")
-		brewFlag, _ := cmd.Flags().GetBool("brew")
-		scoopFlag, _ := cmd.Flags().GetBool("scoop")
-		debFlag, _ := cmd.Flags().GetBool("deb")
-		rpmFlag, _ := cmd.Flags().GetBool("rpm")
-		chocolateyFlag, _ := cmd.Flags().GetBool("chocolatey")
-		wingetFlag, _ := cmd.Flags().GetBool("winget")
-		snapFlag, _ := cmd.Flags().GetBool("snap")
-		appimageFlag, _ := cmd.Flags().GetBool("appimage")
-		flatpakFlag, _ := cmd.Flags().GetBool("flatpak")
-		npmFlag, _ := cmd.Flags().GetBool("npm")
-		pypiFlag, _ := cmd.Flags().GetBool("pypi")
-		dockerFlag, _ := cmd.Flags().GetBool("docker")
-		apptainerFlag, _ := cmd.Flags().GetBool("apptainer")
-		dmgFlag, _ := cmd.Flags().GetBool("dmg")
-		msiFlag, _ := cmd.Flags().GetBool("msi")
-		msixFlag, _ := cmd.Flags().GetBool("msix")
-		cargoFlag, _ := cmd.Flags().GetBool("cargo")
-		nixFlag, _ := cmd.Flags().GetBool("nix")
-		spackFlag, _ := cmd.Flags().GetBool("spack")
-		installerFlag, _ := cmd.Flags().GetBool("installer")
+		reportPath, _ := cmd.Flags().GetString("report")
+		formats, _ := cmd.Flags().GetStringSlice("format")
+		strict, _ := cmd.Flags().GetBool("strict")
+		withSBOM, _ := cmd.Flags().GetBool("sbom")
+		output, err := outputFlag(cmd)
+		if err != nil {
+			return err
+		}
 
 		configPath, err := config.FindConfigFile()
 		if err != nil {
@@ -232,34 +553,65 @@ Examples:
 		if err != nil {
 			return err
 		}
+		if withSBOM {
+			cfg.SBOM.Enabled = true
+		}
 
 		if err := cfg.Validate(); err != nil {
-			return fmt.Errorf("config validation failed: %w", err)
-		}
-
-		registry := packager.NewRegistry()
-		registry.Register(brew.New())
-		registry.Register(scoop.New())
-		registry.Register(deb.New())
-		registry.Register(rpm.New())
-		registry.Register(chocolatey.New())
-		registry.Register(winget.New())
-		registry.Register(snap.New())
-		registry.Register(appimage.New())
-		registry.Register(flatpak.New())
-		registry.Register(npm.New())
-		registry.Register(pypi.New())
-		registry.Register(docker.New())
-		registry.Register(apptainer.New())
-		registry.Register(dmg.New())
-		registry.Register(msi.New())
-		registry.Register(msix.New())
-		registry.Register(cargo.New())
-		registry.Register(nix.New())
-		registry.Register(spack.New())
-		registry.Register(installer.New())
-
-		ctx := context.Background()
+			return fmt.Errorf(i18n.T("config validation failed: %w"), err)
+		}
+		if err := throttle.Apply(cfg.Performance); err != nil {
+			return err
+		}
+
+		registry := newRegistry()
+		registry.SetParallelism(packParallelism(cmd, cfg))
+		registry.SetStrict(strict)
+
+		formats = selectedFormats(cmd, formats)
+		for _, format := range formats {
+			if _, ok := registry.Get(format); !ok {
+				return fmt.Errorf(i18n.T("unknown format %q (available: %s)"), format, strings.Join(registry.List(), ", "))
+			}
+		}
+		if err := checkHookFormats(registry, cfg); err != nil {
+			return err
+		}
+		lintFormats := formats
+		if all {
+			lintFormats = nil
+		}
+		if err := lintNames(cfg, lintFormats); err != nil {
+			return err
+		}
+
+		if err := runBuilds(cmd.Context(), cfg); err != nil {
+			return err
+		}
+		_, cleanup, err := loadPrebuilt(cfg)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		for _, warning := range packager.DuplicateBinaries(cfg) {
+			ui.Warning(warning)
+		}
+
+		ctx := cmd.Context()
+
+		// An interrupted run removes what it left half-written in dist/
+		// and says which formats are still to do
+		guard := interrupt.Watch("dist")
+		guard.Keep(deploy.S3StateDir)
+		var done []string
+		defer func() {
+			remaining := formats
+			if all {
+				remaining = registry.List()
+			}
+			cleanupInterrupted(ctx, guard, packResumeHint(remaining, done))
+		}()
 
 		// Sign binaries first if requested
 		if sign {
@@ -294,268 +646,2585 @@ Examples:
 			}
 		}
 
+		if err := hooks.Stage(ctx, cfg, hooks.BeforePack); err != nil {
+			return err
+		}
+
 		if all {
-			ui.Header("Creating All Package Formats")
-			
-			// Get total count for progress
-			totalPackagers := registry.Count()
-			progress := ui.NewProgressBar(totalPackagers, "📦 Packaging")
-			
+			ui.Header(i18n.T("Creating All Package Formats"))
+
+			progress := packProgress(registry.Count())
+			registry.SetProgress(func(result packager.Result) {
+				progress(result)
+				if result.Status != packager.StatusFailed {
+					done = append(done, result.Format)
+				}
+			})
 			results, err := registry.PackAll(ctx, cfg)
-			progress.Finish()
-			
+			guard.Keep(results.Paths()...)
+			linkIdentical(results)
+			warnMissingTools(results)
+
+			if err == nil {
+				ui.Success(i18n.T("Created %d packages, skipped %d", results.Succeeded(), results.Skipped()))
+			}
+			printPackResults(results, output)
+			if reportErr := writePackReport(reportPath, results); reportErr != nil {
+				return reportErr
+			}
 			if err != nil {
 				return err
 			}
-
-			ui.Success(fmt.Sprintf("Created %d packages", len(results)))
-			
-			// Display results in a table
-			table := ui.NewTable([]string{"Format", "Output Path", "Status"})
-			for name, path := range results {
-				status := "✅ Success"
-				if path == "" {
-					status = "⚠️  Skipped"
-				}
-				table.AddRow([]string{name, path, status})
-			}
-			table.Print()
-			
-			return nil
-		}
-
-		// Individual packagers
-		if brewFlag {
-			if p, ok := registry.Get("brew"); ok {
-				output, err := p.Pack(ctx, cfg)
+			if cfg.SBOM.Enabled {
+				sboms, err := writeSBOMs(cfg, results.Artifacts())
 				if err != nil {
 					return err
 				}
-				fmt.Printf("✅ Created brew formula: %s\n", output)
+				guard.Keep(sboms...)
 			}
+			return hooks.Stage(ctx, cfg, hooks.AfterPack)
 		}
 
-		if scoopFlag {
-			if p, ok := registry.Get("scoop"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created scoop manifest: %s\n", output)
+		// Individual formats, selected by name or flag
+		var artifacts []packager.Artifact
+		for _, format := range formats {
+			p, _ := registry.Get(format)
+			packed, err := packager.PackFormat(ctx, cfg, p)
+			for _, artifact := range packed {
+				guard.Keep(artifact.Path)
 			}
-		}
-
-		if debFlag {
-			if p, ok := registry.Get("deb"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", format, err)
+			}
+			done = append(done, format)
+			artifacts = append(artifacts, packed...)
+			for i, artifact := range packed {
+				label := formatLabel(format)
+				if i > 0 {
+					label += " (" + artifact.Label() + ")"
 				}
-				fmt.Printf("✅ Created deb package: %s\n", output)
+				fmt.Printf("✅ %s\n", i18n.T("Created %s: %s", label, artifact.Path))
 			}
 		}
 
-		if rpmFlag {
-			if p, ok := registry.Get("rpm"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created rpm package: %s\n", output)
+		if cfg.SBOM.Enabled {
+			sboms, err := writeSBOMs(cfg, artifacts)
+			if err != nil {
+				return err
 			}
+			guard.Keep(sboms...)
 		}
+		return hooks.Stage(ctx, cfg, hooks.AfterPack)
+	},
+}
 
-		if chocolateyFlag {
-			if p, ok := registry.Get("chocolatey"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created chocolatey package: %s\n", output)
+var publishCmd = &cobra.Command{
+	Use:     "publish",
+	Aliases: []string{"pub", "release", "deploy"},
+	Short:   "Pack all formats and create GitHub release",
+	Long: `Complete publishing workflow: pack, release, and distribute.
+
+This command will:
+• Create all package formats
+• Create GitHub release with assets
+• Update Homebrew tap (if configured)
+• Update Scoop bucket (if configured)
+• Submit Winget PR (if configured)
+• Push the container image to packages.docker.registries (if configured)
+• Submit the MSIX to the Microsoft Store or a flight (if configured)
+• Verify go install module@version reports the version (if configured)
+
+Examples:
+  bagboy publish                # Full publish workflow
+  bagboy publish --dry-run      # Preview what would happen
+  bagboy publish --skip-github  # Skip GitHub operations
+  bagboy publish --provider gitlab  # Release on GitLab (see gitlab: in bagboy.yaml)
+
+Before packaging, publish checks the GitHub token, write access to the
+release repository, tap and bucket, and docker credentials for every
+configured registry, and reports all problems at once. Use
+--skip-preflight to skip these checks.
+
+Publish refuses a version that is not above the latest GitHub release, so
+an old version number on a stale branch is not released again. With
+github.release.require_checks it also refuses a commit whose listed
+workflows or checks have not passed. Use --force to publish anyway.
+
+Scheduled releases:
+  bagboy publish --at "2026-03-01T09:00Z"             # Stage now, publish at 09:00 UTC
+  bagboy publish --at "2026-03-01T09:00Z" --workflow  # Publish from a generated Actions workflow
+  bagboy publish --finalize                           # Publish a staged release now
+
+With --at the release is created as a draft with all assets uploaded, and
+tap and bucket updates are committed to a staging branch. At the scheduled
+time the draft is published and the staging branches are merged.
+
+With github.manifest_prs, a draft or prerelease opens its tap and bucket
+updates as draft pull requests instead; bagboy promote merges them once
+the release is published. Container images and the Microsoft Store
+submission wait until the release is published, by publish, publish
+--finalize or promote.
+
+Monorepos:
+  bagboy publish --recursive            # Publish every project under .
+  bagboy publish --recursive tools/ --jobs 6
+
+With --recursive every bagboy.yaml under the directory is published by its
+own bagboy publish, several at once. A project whose builds embed another
+project's version (builds[].embeds) waits for that project and is skipped
+if it fails. A summary of all projects is printed at the end.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		skipGitHub, _ := cmd.Flags().GetBool("skip-github")
+		atFlag, _ := cmd.Flags().GetString("at")
+		workflow, _ := cmd.Flags().GetBool("workflow")
+		finalize, _ := cmd.Flags().GetBool("finalize")
+		skipPreflight, _ := cmd.Flags().GetBool("skip-preflight")
+		force, _ := cmd.Flags().GetBool("force")
+		reportPath, _ := cmd.Flags().GetString("report")
+		strict, _ := cmd.Flags().GetBool("strict")
+		withSBOM, _ := cmd.Flags().GetBool("sbom")
+		withBuildEnv, _ := cmd.Flags().GetBool("build-env")
+		provider, _ := cmd.Flags().GetString("provider")
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		output, err := outputFlag(cmd)
+		if err != nil {
+			return err
+		}
+		if recursive {
+			if atFlag != "" || finalize || reportPath != "" {
+				return fmt.Errorf("--at, --finalize and --report cannot be used with --recursive")
 			}
+			root := "."
+			if len(args) > 0 {
+				root = args[0]
+			}
+			jobs, _ := cmd.Flags().GetInt("jobs")
+			return publishRecursive(cmd, root, jobs)
 		}
-
-		if wingetFlag {
-			if p, ok := registry.Get("winget"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created winget manifests: %s\n", output)
+		switch provider {
+		case "github":
+		case "gitlab":
+			if atFlag != "" || finalize {
+				return fmt.Errorf("scheduled publishing is only supported with --provider github")
 			}
+		default:
+			return fmt.Errorf("unknown provider %q (github or gitlab)", provider)
 		}
 
-		if snapFlag {
-			if p, ok := registry.Get("snap"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created snap package: %s\n", output)
+		var scheduledAt time.Time
+		if atFlag != "" {
+			at, err := schedule.ParseTime(atFlag, time.Now(), time.Local)
+			if err != nil {
+				return err
 			}
+			scheduledAt = at
+		} else if workflow {
+			return fmt.Errorf("--workflow requires --at")
 		}
 
-		if appimageFlag {
-			if p, ok := registry.Get("appimage"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created appimage: %s\n", output)
-			}
+		if dryRun {
+			ui.Warning("DRY RUN MODE - No changes will be made")
 		}
 
-		if flatpakFlag {
-			if p, ok := registry.Get("flatpak"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created flatpak manifest: %s\n", output)
-			}
+		ui.PrintBanner()
+		ui.Header(i18n.T("Publishing Workflow"))
+
+		configPath, err := config.FindConfigFile()
+		if err != nil {
+			return err
 		}
 
-		if npmFlag {
-			if p, ok := registry.Get("npm"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created npm package: %s\n", output)
-			}
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
 		}
 
-		if pypiFlag {
-			if p, ok := registry.Get("pypi"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created pypi package: %s\n", output)
-			}
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf(i18n.T("config validation failed: %w"), err)
+		}
+		if err := throttle.Apply(cfg.Performance); err != nil {
+			return err
+		}
+		if err := audit.Configure(cfg.Audit); err != nil {
+			return err
 		}
 
-		if dockerFlag {
-			if p, ok := registry.Get("docker"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created docker files: %s\n", output)
-			}
+		if finalize {
+			return finalizeRelease(cmd.Context(), cfg)
+		}
+		if provider == "gitlab" && !cfg.GitLab.Release.Enabled {
+			return fmt.Errorf("--provider gitlab requires gitlab.release.enabled")
+		}
+		if withSBOM {
+			cfg.SBOM.Enabled = true
+		}
+		if withBuildEnv {
+			cfg.BuildEnv.Enabled = true
 		}
 
-		if apptainerFlag {
-			if p, ok := registry.Get("apptainer"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created apptainer container: %s\n", output)
+		if cfg.Snapshot() {
+			if !dryRun {
+				return fmt.Errorf("version %s is a snapshot - check out a release tag to publish", cfg.Version)
 			}
+			ui.Warning(fmt.Sprintf("Version %s is a snapshot and cannot be published", cfg.Version))
 		}
 
-		if dmgFlag {
-			if p, ok := registry.Get("dmg"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created dmg installer: %s\n", output)
+		if !scheduledAt.IsZero() {
+			if skipGitHub || !cfg.GitHub.Release.Enabled {
+				return fmt.Errorf("scheduled publishing requires github.release.enabled")
 			}
+			cfg.GitHub.Release.Draft = true
+			ui.Info(fmt.Sprintf("Scheduled for %s", scheduledAt.Format(time.RFC1123)))
 		}
 
-		if msiFlag {
-			if p, ok := registry.Get("msi"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created msi installer: %s\n", output)
+		if dryRun {
+			ui.Info("Would create packages for:")
+			for _, format := range []string{"brew", "scoop", "deb", "rpm", "docker"} {
+				ui.Info(fmt.Sprintf("  • %s", format))
+			}
+			if provider == "gitlab" {
+				ui.Info(fmt.Sprintf("Would create GitLab release in %s and update repositories", cfg.GitLab.Project))
+			} else if !skipGitHub && cfg.GitHub.Owner != "" {
+				ui.Info("Would create GitHub release and update repositories")
 			}
+			return nil
 		}
 
-		if msixFlag {
-			if p, ok := registry.Get("msix"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created msix package: %s\n", output)
+		if err := lintNames(cfg, nil); err != nil {
+			return err
+		}
+		if !skipPreflight {
+			if err := runPreflight(cmd.Context(), cfg, skipGitHub || provider == "gitlab"); err != nil {
+				return err
 			}
 		}
-
-		if cargoFlag {
-			if p, ok := registry.Get("cargo"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created cargo package: %s\n", output)
+		if !force && !skipGitHub && provider == "github" && cfg.GitHub.Release.Enabled {
+			if err := checkVersionGuard(cmd.Context(), cfg); err != nil {
+				return err
 			}
 		}
-
-		if nixFlag {
-			if p, ok := registry.Get("nix"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("✅ Created nix package: %s\n", output)
+		if !force && !skipGitHub && provider == "github" && len(cfg.GitHub.Release.RequireChecks) > 0 {
+			if err := checkCIStatus(cmd.Context(), cfg); err != nil {
+				return err
 			}
 		}
-
-		if spackFlag {
-			if p, ok := registry.Get("spack"); ok {
-				output, err := p.Pack(ctx, cfg)
-				if err != nil {
+		// --force does not get past a published immutable release
+		if !skipGitHub && provider == "github" && cfg.GitHub.Release.Enabled && cfg.GitHub.Release.Immutable {
+			client, err := github.NewClient(&cfg.GitHub)
+			if err != nil {
+				return err
+			}
+			if err := client.CheckImmutable(cmd.Context(), cfg); err != nil {
+				return err
+			}
+		}
+
+		registry := newRegistry()
+		if err := checkHookFormats(registry, cfg); err != nil {
+			return err
+		}
+		if err := hooks.Stage(cmd.Context(), cfg, hooks.BeforePublish); err != nil {
+			return err
+		}
+
+		if err := runBuilds(cmd.Context(), cfg); err != nil {
+			return err
+		}
+		prebuiltArtifacts, cleanup, err := loadPrebuilt(cfg)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		for _, warning := range packager.DuplicateBinaries(cfg) {
+			ui.Warning(warning)
+		}
+
+		fmt.Println("🚀 " + i18n.T("Publishing %s %s", cfg.Name, cfg.Version))
+
+		// Create packages
+		registry.SetParallelism(packParallelism(cmd, cfg))
+		registry.SetStrict(strict)
+		registry.SetProgress(packProgress(registry.Count()))
+		ctx := cmd.Context()
+		if err := hooks.Stage(ctx, cfg, hooks.BeforePack); err != nil {
+			return err
+		}
+		guard := interrupt.Watch("dist")
+		guard.Keep(deploy.S3StateDir)
+		defer cleanupInterrupted(ctx, guard, "Run 'bagboy publish' again to start over from the packages")
+		results, err := registry.PackAll(ctx, cfg)
+		guard.Keep(results.Paths()...)
+		linkIdentical(results)
+		warnMissingTools(results)
+		if reportErr := writePackReport(reportPath, results); reportErr != nil {
+			return reportErr
+		}
+		if err != nil {
+			printPackResults(results, output)
+			return err
+		}
+
+		fmt.Println("✅ " + i18n.T("Created packages:"))
+		printPackResults(results, output)
+		if err := hooks.Stage(ctx, cfg, hooks.AfterPack); err != nil {
+			return err
+		}
+		assets := results.Paths()
+
+		// Ship the pre-built archives alongside the packages
+		for _, artifact := range prebuiltArtifacts {
+			assets = append(assets, artifact.Archive)
+		}
+
+		// And an SBOM for every binary and package
+		if cfg.SBOM.Enabled {
+			sboms, err := writeSBOMs(cfg, results.Artifacts())
+			if err != nil {
+				return err
+			}
+			guard.Keep(sboms...)
+			assets = append(assets, sboms...)
+		}
+
+		// And the machine they were built on
+		if cfg.BuildEnv.Enabled {
+			manifest := filepath.Join("dist", buildenv.File)
+			if err := buildenv.Capture(ctx, cfg, bagboyVersion()).Write(manifest); err != nil {
+				return fmt.Errorf("failed to write %s: %w", buildenv.File, err)
+			}
+			guard.Keep(manifest)
+			assets = append(assets, manifest)
+		}
+
+		// COPR builds from the source RPM attached to the release
+		if cfg.Packages.RPM.COPR.Source == "release" {
+			srpm, err := rpm.New().BuildSRPM(ctx, cfg)
+			if err != nil {
+				return fmt.Errorf("failed to build SRPM for COPR: %w", err)
+			}
+			fmt.Printf("  srpm: %s\n", srpm)
+			assets = append(assets, srpm)
+		}
+
+		// Generate delta updates against the previous release
+		if cfg.Delta.Enabled {
+			artifacts, err := delta.NewGenerator(cfg).Generate(ctx, "")
+			if err != nil {
+				fmt.Printf("⚠️  Delta generation incomplete: %v\n", err)
+			}
+			for _, artifact := range artifacts {
+				fmt.Printf("  %s: %s\n", artifact.Format, artifact.Path)
+				assets = append(assets, artifact.Path)
+			}
+		}
+
+		// Catch leaked secrets before anything is encrypted or signed
+		if err := checkSecrets(cfg, append(assets, "dist")); err != nil {
+			return err
+		}
+
+		// Upload byte-identical assets once
+		if cfg.GitHub.Release.DedupeAssets {
+			var aliases string
+			assets, aliases, err = dedupeAssets(assets)
+			if err != nil {
+				return err
+			}
+			if aliases != "" {
+				guard.Keep(aliases)
+			}
+		}
+
+		// Encrypt assets for private distribution
+		if cfg.Encryption.Enabled {
+			encrypted, err := encrypt.NewEncryptor(&cfg.Encryption).EncryptAll(ctx, assets)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt assets: %w", err)
+			}
+			assets = encrypted
+			ui.Success(fmt.Sprintf("Encrypted assets for %d recipient(s)", len(cfg.Encryption.Recipients)))
+		}
+
+		// Sign the release and enforce the policy on the signed assets
+		// before anything leaves the machine
+		image := ""
+		if _, ok := results.Get("docker"); ok {
+			image = fmt.Sprintf("%s:%s", cfg.Name, cfg.Version)
+		}
+		signed, err := signAndCheckRelease(ctx, cfg, assets, image)
+		guard.Keep(signed...)
+		if err != nil {
+			return err
+		}
+		assets = append(assets, signed...)
+
+		if dryRun {
+			fmt.Println("🔍 Dry run - would create GitHub release with assets:", assets)
+			return nil
+		}
+
+		if provider == "gitlab" {
+			if err := publishGitLab(ctx, cfg, results, assets); err != nil {
+				return err
+			}
+			distributeRelease(ctx, cfg)
+			if err := hooks.Stage(ctx, cfg, hooks.AfterPublish); err != nil {
+				return err
+			}
+			fmt.Println("\n🎉 Publish complete!")
+			return nil
+		}
+
+		// Tag the release with an SSH signature before GitHub creates an
+		// unsigned tag. Staged releases are tagged when they are published.
+		if cfg.Signing.SSH.Enabled && cfg.Signing.SSH.Tags && scheduledAt.IsZero() {
+			tag := cfg.Tag()
+			if err := signing.NewSigner(cfg).SignTagWithSSH(ctx, tag); err != nil {
+				return err
+			}
+			ui.Success(fmt.Sprintf("Signed git tag %s with SSH key", tag))
+		}
+
+		// Create GitHub release
+		if cfg.GitHub.Release.Enabled {
+			client, err := github.NewClient(&cfg.GitHub)
+			if err != nil {
+				fmt.Printf("⚠️  GitHub integration disabled: %v\n", err)
+				return nil
+			}
+
+			// Scheduled releases and, with github.manifest_prs, drafts
+			// and prereleases hold tap and bucket updates on a branch
+			holdManifests := scheduledAt.IsZero() && github.HoldManifests(cfg)
+			if !scheduledAt.IsZero() || holdManifests {
+				client.SetStagingBranch(schedule.StagingBranch(cfg))
+			}
+
+			release, err := client.CreateRelease(ctx, cfg, assets)
+			if err != nil {
+				return fmt.Errorf("failed to create GitHub release: %w", err)
+			}
+
+			fmt.Printf("✅ %s\n", i18n.T("Created GitHub release: %s", release.GetHTMLURL()))
+
+			// gh extension search only lists repositories with the topic
+			if _, ok := results.Get("gh-extension"); ok {
+				if err := client.EnsureTopic(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo, ghext.Topic); err != nil {
+					fmt.Printf("⚠️  Failed to tag gh extension: %v\n", err)
+				} else {
+					fmt.Printf("✅ Published gh extension: gh extension install %s/%s\n", cfg.GitHub.Owner, cfg.GitHub.Repo)
+				}
+			}
+
+			// Go users install from the tag, so check that works before
+			// the release is announced anywhere else. Staged releases
+			// have no tag yet.
+			if cfg.GoModule.Enabled && scheduledAt.IsZero() {
+				if err := runGoModuleChecks(ctx, cfg); err != nil {
 					return err
 				}
-				fmt.Printf("✅ Created spack package: %s\n", output)
 			}
-		}
 
-		if installerFlag {
-			if p, ok := registry.Get("installer"); ok {
-				output, err := p.Pack(ctx, cfg)
+			// Update tap and bucket
+			if cfg.GitHub.Tap.Enabled {
+				formulaPath, _ := results.Get("brew")
+				formula, err := os.ReadFile(formulaPath)
 				if err != nil {
-					return err
+					fmt.Printf("⚠️  Failed to update tap: %v\n", err)
+				} else if err := client.UpdateTap(ctx, cfg, string(formula)); err != nil {
+					fmt.Printf("⚠️  Failed to update tap: %v\n", err)
+				} else {
+					fmt.Printf("✅ Updated Homebrew tap: %s\n", cfg.GitHub.Tap.Repo)
+				}
+				if cask, ok := tapCask(cfg, results); ok {
+					if err := client.UpdateTapCask(ctx, cfg, brew.CaskToken(cfg), cask); err != nil {
+						fmt.Printf("⚠️  Failed to update tap cask: %v\n", err)
+					} else {
+						fmt.Printf("✅ Updated Homebrew cask: brew install --cask %s\n", brew.CaskToken(cfg))
+					}
+				}
+			}
+
+			if cfg.GitHub.Bucket.Enabled {
+				manifestPath, _ := results.Get("scoop")
+				manifest, err := os.ReadFile(manifestPath)
+				if err != nil {
+					fmt.Printf("⚠️  Failed to update bucket: %v\n", err)
+				} else if err := client.UpdateBucket(ctx, cfg, string(manifest)); err != nil {
+					fmt.Printf("⚠️  Failed to update bucket: %v\n", err)
+				} else {
+					fmt.Printf("✅ Updated Scoop bucket: %s\n", cfg.GitHub.Bucket.Repo)
+				}
+			}
+
+			if holdManifests {
+				prs, err := client.OpenManifestPRs(ctx, cfg, schedule.StagingBranch(cfg))
+				if err != nil {
+					fmt.Printf("⚠️  Failed to open manifest pull requests: %v\n", err)
+				}
+				for _, pr := range prs {
+					fmt.Printf("✅ Opened draft pull request: %s\n", pr.GetHTMLURL())
+				}
+				if len(prs) > 0 {
+					ui.Info("Run 'bagboy promote' once the release is published to merge them")
+				}
+			}
+
+			// Submit Winget PR
+			if cfg.GitHub.Winget.Enabled && cfg.GitHub.Winget.AutoPR {
+				fmt.Println("Submitting Winget PR...")
+				wingetResult, exists := results.Get("winget")
+				if exists && wingetResult != "" {
+					if manifests := wingetManifests(cfg, wingetResult); len(manifests) > 0 {
+						if err := client.SubmitWingetPR(ctx, cfg, manifests); err != nil {
+							fmt.Printf("⚠️  Failed to submit Winget PR: %v\n", err)
+						}
+					}
 				}
-				fmt.Printf("✅ Created installer script: %s\n", output)
 			}
 		}
 
+		if !scheduledAt.IsZero() {
+			if workflow {
+				path := schedule.WorkflowPath(cfg)
+				if err := schedule.WriteWorkflow(path, cfg, scheduledAt); err != nil {
+					return fmt.Errorf("failed to write scheduled workflow: %w", err)
+				}
+				ui.Success(fmt.Sprintf("Release staged. Commit and push %s to publish at %s", path, scheduledAt.UTC().Format(time.RFC3339)))
+				return nil
+			}
+
+			ui.Info(fmt.Sprintf("Release staged. Waiting until %s (Ctrl+C to stop; resume with 'bagboy publish --finalize')", scheduledAt.Format(time.RFC1123)))
+			if err := schedule.Wait(ctx, scheduledAt, time.Hour); err != nil {
+				return err
+			}
+			return finalizeRelease(ctx, cfg)
+		}
+
+		// Drafts and held releases are distributed by promote once public
+		if !cfg.GitHub.Release.Draft && !github.HoldManifests(cfg) {
+			distributeRelease(ctx, cfg)
+		}
+
+		if err := hooks.Stage(ctx, cfg, hooks.AfterPublish); err != nil {
+			return err
+		}
+		fmt.Println("\n🎉 Publish complete!")
+		return nil
+	},
+}
+
+// publishGitLab creates the GitLab release and updates the tap and
+// bucket projects
+func publishGitLab(ctx context.Context, cfg *config.Config, results packager.Results, assets []string) error {
+	client, err := gitlab.NewClient(&cfg.GitLab)
+	if err != nil {
+		return err
+	}
+
+	release, err := client.CreateRelease(ctx, cfg, assets)
+	if err != nil {
+		return fmt.Errorf("failed to create GitLab release: %w", err)
+	}
+	ui.Success(fmt.Sprintf("Created GitLab release: %s", release.URL()))
+
+	if cfg.GitLab.Tap.Enabled {
+		formulaPath, _ := results.Get("brew")
+		formula, err := os.ReadFile(formulaPath)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to update tap: %v\n", err)
+		} else if err := client.UpdateTap(ctx, cfg, string(formula)); err != nil {
+			fmt.Printf("⚠️  Failed to update tap: %v\n", err)
+		}
+		if cask, ok := tapCask(cfg, results); ok {
+			if err := client.UpdateTapCask(ctx, cfg, brew.CaskToken(cfg), cask); err != nil {
+				fmt.Printf("⚠️  Failed to update tap cask: %v\n", err)
+			}
+		}
+	}
+
+	if cfg.GitLab.Bucket.Enabled {
+		manifestPath, _ := results.Get("scoop")
+		manifest, err := os.ReadFile(manifestPath)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to update bucket: %v\n", err)
+		} else if err := client.UpdateBucket(ctx, cfg, string(manifest)); err != nil {
+			fmt.Printf("⚠️  Failed to update bucket: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// wingetManifests reads the manifest files from the winget output
+// directory, keyed by file name
+func wingetManifests(cfg *config.Config, dir string) map[string]string {
+	manifests := make(map[string]string)
+	manifestFiles := []string{
+		fmt.Sprintf("%s.yaml", cfg.Packages.Winget.PackageIdentifier),
+		fmt.Sprintf("%s.installer.yaml", cfg.Packages.Winget.PackageIdentifier),
+		fmt.Sprintf("%s.locale.en-US.yaml", cfg.Packages.Winget.PackageIdentifier),
+	}
+	for _, filename := range manifestFiles {
+		if content, err := os.ReadFile(filepath.Join(dir, filename)); err == nil {
+			manifests[filename] = string(content)
+		}
+	}
+	return manifests
+}
+
+// tapCask renders the cask again against the DMG now that it is built,
+// since brew and dmg are packed in parallel
+func tapCask(cfg *config.Config, results packager.Results) (string, bool) {
+	if !cfg.Packages.Brew.Cask.Enabled {
+		return "", false
+	}
+	dmgPath, ok := results.Get("dmg")
+	if !ok {
+		fmt.Println("⚠️  brew.cask is enabled but no DMG was built; skipping the cask")
+		return "", false
+	}
+	cask, err := brew.Cask(cfg, dmgPath)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to write cask: %v\n", err)
+		return "", false
+	}
+	return cask, true
+}
+
+// publishRecursive runs bagboy publish in every project under root, in
+// parallel and in dependency order, and prints a summary of them all
+func publishRecursive(cmd *cobra.Command, root string, jobs int) error {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	projects, err := monorepo.Discover(root)
+	if err != nil {
+		return err
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	// Each project gets the same publish flags, and the parent's
+	// context handles --timeout for all of them
+	args := []string{"publish"}
+	for _, name := range []string{"dry-run", "skip-github", "provider", "skip-preflight", "force", "parallel", "strict", "output", "sbom", "build-env", "a11y", "lang"} {
+		if flag := cmd.Flags().Lookup(name); flag != nil && flag.Changed {
+			args = append(args, "--"+name+"="+flag.Value.String())
+		}
+	}
+
+	ui.Header(fmt.Sprintf("Publishing %d projects", len(projects)))
+	for _, project := range monorepo.Order(projects) {
+		line := fmt.Sprintf("  • %s %s", project.Config.Name, project.Config.Version)
+		if len(project.Needs) > 0 {
+			var needs []string
+			for _, need := range project.Needs {
+				needs = append(needs, need.Config.Name)
+			}
+			line += " (after " + strings.Join(needs, ", ") + ")"
+		}
+		fmt.Println(line)
+	}
+
+	// Output is printed a project at a time so parallel runs don't interleave
+	var mu sync.Mutex
+	results := monorepo.Run(cmd.Context(), projects, jobs, func(ctx context.Context, project *monorepo.Project) error {
+		child := exec.CommandContext(ctx, executable, args...)
+		child.Dir = project.Dir
+		child.Cancel = func() error { return child.Process.Signal(os.Interrupt) }
+		child.WaitDelay = time.Minute
+		output, err := child.CombinedOutput()
+
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Printf("\n── %s %s ──\n%s", project.Config.Name, project.Config.Version, output)
+		if err != nil {
+			return fmt.Errorf("bagboy publish failed: %w", err)
+		}
+		return nil
+	})
+
+	fmt.Println()
+	table := ui.NewTable([]string{"Project", "Version", "Directory", "Duration", "Status"})
+	success := "✅ Published"
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		success = "✅ Dry run passed"
+	}
+	failed := 0
+	for _, result := range results {
+		status := success
+		switch {
+		case result.Skipped:
+			status = "⚠️  Skipped: " + result.Err.Error()
+			failed++
+		case result.Err != nil:
+			status = "❌ " + result.Err.Error()
+			failed++
+		}
+		dir, err := filepath.Rel(root, result.Project.Dir)
+		if err != nil {
+			dir = result.Project.Dir
+		}
+		table.AddRow([]string{result.Project.Config.Name, result.Project.Config.Version, dir, result.Duration.Round(time.Second).String(), status})
+	}
+	table.Print()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d projects were not published", failed, len(results))
+	}
+	fmt.Println("\n🎉 Publish complete!")
+	return nil
+}
+
+// finalizeRelease publishes a release staged with publish --at
+func finalizeRelease(ctx context.Context, cfg *config.Config) error {
+	client, err := github.NewClient(&cfg.GitHub)
+	if err != nil {
+		return err
+	}
+
+	release, err := client.FinalizeRelease(ctx, cfg, schedule.StagingBranch(cfg))
+	if err != nil {
+		return err
+	}
+
+	ui.Success(fmt.Sprintf("Published GitHub release: %s", release.GetHTMLURL()))
+	distributeRelease(ctx, cfg)
+	if err := hooks.Stage(ctx, cfg, hooks.AfterPublish); err != nil {
+		return err
+	}
+	fmt.Println("\n🎉 Publish complete!")
+	return nil
+}
+
+// distributeRelease pushes a release that just went public to the
+// channels users install from without going through the release page.
+// Like the tap and bucket, these wait for scheduled and held releases.
+func distributeRelease(ctx context.Context, cfg *config.Config) {
+	// Push the container image to every configured registry
+	if _, err := os.Stat(filepath.Join("dist", "docker")); err == nil && len(cfg.Packages.Docker.Registries) > 0 {
+		if err := deploy.NewDeployer(cfg).Deploy(ctx, []string{"docker"}, false); err != nil {
+			fmt.Printf("⚠️  Container push incomplete: %v\n", err)
+		}
+	}
+
+	// Submit the MSIX to the Microsoft Store or one of its flights
+	if msixPath := msix.OutputPath(cfg); cfg.Packages.MSIX.Store.ProductID != "" {
+		if _, err := os.Stat(msixPath); err != nil {
+			fmt.Printf("⚠️  Microsoft Store submission skipped: %v\n", err)
+		} else if flightID, err := msix.SubmitToStore(ctx, cfg, msixPath); err != nil {
+			fmt.Printf("⚠️  Microsoft Store submission failed: %v\n", err)
+		} else if flightID != "" {
+			fmt.Printf("✅ Submitted to Microsoft Store flight %s (%s)\n", cfg.Packages.MSIX.Store.Flight, flightID)
+		} else {
+			fmt.Printf("✅ Submitted to Microsoft Store: %s\n", cfg.Packages.MSIX.Store.ProductID)
+		}
+	}
+}
+
+var promoteCmd = &cobra.Command{
+	Use:   "promote",
+	Short: "Merge the tap and bucket pull requests held back for a release",
+	Long: `Merge the draft tap and bucket pull requests opened by publish.
+
+With github.manifest_prs, publishing a draft or prerelease opens its tap
+and bucket updates as draft pull requests instead of committing them, so
+users don't see the new version before it is announced. Once the release
+is published as a full release, promote marks the pull requests ready
+and enables auto-merge, or merges them when the repository does not
+allow auto-merge.
+
+Examples:
+  bagboy promote                  # Promote now if the release is published
+  bagboy promote --wait           # Poll until the release is published
+  bagboy promote --workflow       # Promote from an Actions workflow on release`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wait, _ := cmd.Flags().GetBool("wait")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		workflow, _ := cmd.Flags().GetBool("workflow")
+
+		configPath, err := config.FindConfigFile()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+		if err := audit.Configure(cfg.Audit); err != nil {
+			return err
+		}
+
+		if workflow {
+			path := schedule.PromoteWorkflowPath()
+			if err := schedule.WritePromoteWorkflow(path, cfg); err != nil {
+				return fmt.Errorf("failed to write promote workflow: %w", err)
+			}
+			ui.Success(fmt.Sprintf("Commit and push %s to promote manifests when a release is published", path))
+			return nil
+		}
+
+		client, err := github.NewClient(&cfg.GitHub)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		tag := cfg.Tag()
+		published := func(ctx context.Context) (bool, error) {
+			return client.ReleasePublished(ctx, cfg)
+		}
+		ok, err := published(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if !wait {
+				return fmt.Errorf("release %s is still a draft or prerelease (use --wait to wait for it)", tag)
+			}
+			ui.Info(fmt.Sprintf("Waiting for release %s to be published (checking every %s)", tag, interval))
+			if err := schedule.Poll(ctx, interval, published); err != nil {
+				return err
+			}
+		}
+
+		ui.Header(fmt.Sprintf("Promoting manifests for %s", tag))
+		if err := client.PromoteManifestPRs(ctx, cfg, schedule.StagingBranch(cfg)); err != nil {
+			return err
+		}
+		distributeRelease(ctx, cfg)
+		return nil
+	},
+}
+
+var backfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "Regenerate and commit manifests for earlier releases",
+	Long: `Regenerate the brew, scoop, winget and nix manifests of earlier releases,
+so versions released before a format was adopted become installable too.
+
+Each published release from --from to --to is processed oldest first: its
+binaries are downloaded from the release, the manifests are generated with
+the current bagboy.yaml and kept under dist/backfill/<tag>/, and then
+
+• the formula is committed to the tap as a versioned formula,
+  Formula/<name>@<major>.<minor>.rb (the newest patch of each minor wins)
+• the scoop manifest is committed to the bucket's archive/<name>/
+• a winget PR is opened for the version (github.winget.auto_pr)
+
+The current formula and bucket manifest are never replaced. Nix
+expressions are only written locally. Releases are processed --delay
+apart, and backfill waits for the API rate limit to reset when fewer than
+--reserve requests are left.
+
+Examples:
+  bagboy backfill --from v1.0.0 --to v1.9.0
+  bagboy backfill --from v1.0.0 --to v1.9.0 --formats brew,scoop
+  bagboy backfill --from v1.0.0 --to v1.9.0 --dry-run   # Generate only`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		formats, _ := cmd.Flags().GetStringSlice("formats")
+		delay, _ := cmd.Flags().GetDuration("delay")
+		reserve, _ := cmd.Flags().GetInt("reserve")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if from == "" || to == "" {
+			return fmt.Errorf("--from and --to are required")
+		}
+		for _, format := range formats {
+			switch format {
+			case "brew", "scoop", "winget", "nix":
+			default:
+				return fmt.Errorf("unknown format %q (brew, scoop, winget or nix)", format)
+			}
+		}
+
+		configPath, err := config.FindConfigFile()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf(i18n.T("config validation failed: %w"), err)
+		}
+		if err := audit.Configure(cfg.Audit); err != nil {
+			return err
+		}
+
+		client, err := github.NewClient(&cfg.GitHub)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		releases, err := client.ListReleases(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo)
+		if err != nil {
+			return err
+		}
+		tags, err := github.BackfillTags(cfg, releases, from, to)
+		if err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			ui.Warning(fmt.Sprintf("No published releases from %s to %s", from, to))
+			return nil
+		}
+
+		ui.Header(fmt.Sprintf("Backfilling %d releases", len(tags)))
+		if dryRun {
+			ui.Warning("DRY RUN MODE - manifests are generated but not committed")
+		}
+
+		failed := 0
+		for i, tag := range tags {
+			if i > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(delay):
+				}
+			}
+			if err := client.WaitForRateLimit(ctx, reserve); err != nil {
+				return err
+			}
+
+			fmt.Printf("\n── %s ──\n", tag)
+			if err := backfillRelease(ctx, client, cfg, tag, formats, dryRun); err != nil {
+				fmt.Printf("❌ %s: %v\n", tag, err)
+				failed++
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d releases were not backfilled", failed, len(tags))
+		}
+		fmt.Println("\n🎉 Backfill complete!")
+		return nil
+	},
+}
+
+// backfillRelease regenerates the manifests of one earlier release from
+// its downloaded binaries and commits them, unless dryRun
+func backfillRelease(ctx context.Context, client *github.Client, cfg *config.Config, tag string, formats []string, dryRun bool) error {
+	at, err := cfg.AtTag(tag)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "bagboy-backfill-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := client.DownloadBinaries(ctx, at, dir); err != nil {
+		return err
+	}
+
+	keep := filepath.Join("dist", "backfill", tag)
+	for _, format := range formats {
+		var p packager.Packager
+		switch format {
+		case "brew":
+			p = brew.New()
+		case "scoop":
+			p = scoop.New()
+		case "winget":
+			p = winget.New()
+		case "nix":
+			p = nix.New()
+		}
+		if err := p.Validate(at); err != nil {
+			fmt.Printf("⚠️  Skipping %s: %v\n", format, err)
+			continue
+		}
+		output, err := p.Pack(ctx, at)
+		if err != nil {
+			return fmt.Errorf("%s: %w", format, err)
+		}
+
+		kept := filepath.Join(keep, format)
+		if info, err := os.Stat(output); err == nil && info.IsDir() {
+			err = paths.CopyTree(output, kept, paths.CopyOptions{})
+		} else {
+			kept = filepath.Join(keep, filepath.Base(output))
+			err = paths.CopyFile(output, kept, paths.CopyOptions{})
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", format, err)
+		}
+		fmt.Printf("✅ Generated %s: %s\n", format, kept)
+		if dryRun {
+			continue
+		}
+
+		switch {
+		case format == "brew" && cfg.GitHub.Tap.Enabled && cfg.GitHub.Tap.AutoCommit:
+			formula, err := os.ReadFile(output)
+			if err == nil {
+				err = client.BackfillTap(ctx, at, string(formula))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to update tap: %w", err)
+			}
+		case format == "scoop" && cfg.GitHub.Bucket.Enabled && cfg.GitHub.Bucket.AutoCommit:
+			manifest, err := os.ReadFile(output)
+			if err == nil {
+				err = client.BackfillBucket(ctx, at, string(manifest))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to update bucket: %w", err)
+			}
+		case format == "winget":
+			if manifests := wingetManifests(at, output); len(manifests) > 0 {
+				if err := client.SubmitWingetPR(ctx, at, manifests); err != nil {
+					return fmt.Errorf("failed to submit winget PR: %w", err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check system requirements for package formats",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		formats, _ := cmd.Flags().GetStringSlice("formats")
+		if len(formats) == 0 {
+			formats = []string{"brew", "scoop", "deb", "rpm", "dmg", "msi", "docker", "snap", "appimage"}
+		}
+		
+		checker := requirements.NewRequirementChecker()
+		results := checker.CheckRequirements(formats)
+		checker.PrintRequirementReport(results)
+		
+		return nil
+	},
+}
+
+var deployCmd = &cobra.Command{
+	Use:   "deploy",
+	Short: "Deploy packages to repositories and registries",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targets, _ := cmd.Flags().GetStringSlice("targets")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		
+		if len(targets) == 0 {
+			// Show available targets
+			deployer := deploy.NewDeployer(nil)
+			deploymentTargets := deployer.GetDeploymentTargets()
+			
+			fmt.Println("📦 Available Deployment Targets:")
+			fmt.Println("================================")
+			for _, target := range deploymentTargets {
+				fmt.Printf("\n🎯 %s (%s)\n", target.Name, target.Format)
+				fmt.Printf("   %s\n", target.Description)
+			}
+			fmt.Println("\nUsage: bagboy deploy --targets brew,npm,docker")
+			return nil
+		}
+		
+		configPath, err := config.FindConfigFile()
+		if err != nil {
+			return err
+		}
+		
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+		if err := throttle.Apply(cfg.Performance); err != nil {
+			return err
+		}
+		if err := audit.Configure(cfg.Audit); err != nil {
+			return err
+		}
+		
+		if !dryRun {
+			if err := checkSecrets(cfg, []string{"dist"}); err != nil {
+				return err
+			}
+		}
+
+		deployer := deploy.NewDeployer(cfg)
+		ctx := cmd.Context()
+		
+		err = deployer.Deploy(ctx, targets, dryRun)
+		if ctx.Err() != nil {
+			ui.Info(fmt.Sprintf("💡 Run 'bagboy deploy --targets %s' again to resume; S3 uploads continue from %s", strings.Join(targets, ","), deploy.S3StateDir))
+		}
+		return err
+	},
+}
+
+var signCmd = &cobra.Command{
+	Use:   "sign",
+	Short: "Check code signing setup and sign binaries",
+	Long: `Check the code signing setup, or sign one binary with --binary.
+
+macOS notarization submissions are remembered by the SHA-256 of the signed
+binary, so signing or publishing again does not resubmit an unchanged
+binary to Apple. --notarize-status shows the recorded submissions and
+refreshes those still in progress.
+
+Examples:
+  bagboy sign --check
+  bagboy sign --binary dist/myapp-darwin-arm64
+  bagboy sign --notarize-status`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		checkOnly, _ := cmd.Flags().GetBool("check")
+		binaryPath, _ := cmd.Flags().GetString("binary")
+		notarizeStatus, _ := cmd.Flags().GetBool("notarize-status")
+
+		if notarizeStatus {
+			submissions, err := signing.NewSigner(nil).NotarizationStatus(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if len(submissions) == 0 {
+				ui.Info("No notarization submissions recorded")
+				return nil
+			}
+			table := ui.NewTable([]string{"Artifact", "Submission", "Status", "Submitted"})
+			for _, submission := range submissions {
+				table.AddRow([]string{submission.Artifact, submission.ID, submission.Status, submission.Submitted.Local().Format(time.RFC822)})
+			}
+			table.Print()
+			return nil
+		}
+		
+		configPath, err := config.FindConfigFile()
+		if err != nil && !checkOnly {
+			return err
+		}
+		
+		var cfg *config.Config
+		if configPath != "" {
+			cfg, err = config.Load(configPath)
+			if err != nil {
+				return err
+			}
+			if err := audit.Configure(cfg.Audit); err != nil {
+				return err
+			}
+		}
+		
+		signer := signing.NewSigner(cfg)
+		
+		if checkOnly || binaryPath == "" {
+			// Check signing setup
+			results := signer.CheckSigningSetup()
+			signer.PrintSigningReport(results)
+			return nil
+		}
+		
+		// Sign specific binary
+		ctx := cmd.Context()
+		return signer.SignBinary(ctx, binaryPath)
+	},
+}
+
+var validateCmd = &cobra.Command{
+	Use:     "validate",
+	Aliases: []string{"v", "check", "verify"},
+	Short:   "Validate bagboy configuration",
+	Long: `Validate your bagboy.yaml configuration file.
+
+Checks for:
+• Valid YAML syntax
+• Required fields (name, version, binaries)
+• License is a valid SPDX expression
+• No unknown keys under packages, such as a misspelled format
+• Binary file existence
+• GitHub repository access (if configured)
+• Package format compatibility
+• Package names follow the deb, snap, npm and winget naming rules
+• Package name availability on PyPI, npm, crates.io and Chocolatey
+
+Examples:
+  bagboy validate               # Validate current configuration
+  bagboy validate --verbose     # Show detailed validation info
+  bagboy validate --offline     # Skip the registry name checks
+
+A package name someone else holds on a registry is a warning. Names held
+by the usernames listed under accounts: are not:
+
+  accounts:
+    pypi: myname
+    npm: myname`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		offline, _ := cmd.Flags().GetBool("offline")
+		
+		ui.Header(i18n.T("Validating Configuration"))
+		
+		configPath, err := config.FindConfigFile()
+		if err != nil {
+			ui.Error(i18n.T("No bagboy configuration file found"))
+			ui.Info(i18n.T("Run 'bagboy init' to create a new configuration"))
+			return errors.NewConfigurationError("CONFIG_NOT_FOUND", i18n.T("No bagboy configuration file found"), 
+				i18n.T("Run 'bagboy init' to create a new configuration"),
+				"Ensure bagboy.yaml exists in the current directory")
+		}
+
+		if verbose {
+			ui.Info(fmt.Sprintf("Found config file: %s", configPath))
+		}
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			ui.Error(i18n.T("Failed to load configuration file"))
+			return errors.WrapError(err, i18n.T("Failed to load configuration file"), 
+				"Check the syntax of your bagboy.yaml file",
+				"Run 'bagboy init' to regenerate the configuration")
+		}
+
+		if err := cfg.Validate(); err != nil {
+			ui.Error(i18n.T("Configuration validation failed"))
+			return errors.WrapError(err, i18n.T("Configuration validation failed"), 
+				"Fix the issues in your bagboy.yaml file",
+				"Run 'bagboy init' to regenerate with correct structure")
+		}
+		if err := lintNames(cfg, nil); err != nil {
+			return err
+		}
+
+		ui.Success(i18n.T("Configuration is valid"))
+
+		if cfg.License != "" {
+			warnings, _ := spdx.Check(cfg.License)
+			for _, warning := range warnings {
+				ui.Warning(warning)
+			}
+		}
+		for _, warning := range packager.DuplicateBinaries(cfg) {
+			ui.Warning(warning)
+		}
+		if !offline {
+			printChecks(preflight.NewNameChecker().CheckNames(cmd.Context(), cfg))
+		}
+		
+		if verbose {
+			ui.Info(fmt.Sprintf("Project: %s v%s", cfg.Name, cfg.Version))
+			ui.Info(fmt.Sprintf("Binaries: %d configured", len(cfg.Binaries)))
+			if cfg.GitHub.Owner != "" {
+				ui.Info(fmt.Sprintf("GitHub: %s/%s", cfg.GitHub.Owner, cfg.GitHub.Repo))
+			}
+		}
+		
+		return nil
+	},
+}
+
+var deltaCmd = &cobra.Command{
+	Use:   "delta",
+	Short: "Generate MSP patches and deltarpms against a previous release",
+	Long: `Generate differential update artifacts between a previous release and
+the packages in dist/.
+
+Supported formats:
+• rpm - deltarpm via makedeltarpm, one per architecture
+• msi - MSP patch via wix build (WiX v4 and later), or torch, candle,
+  light and pyro (WiX v3)
+
+MSP generation needs the .wixpdb files produced alongside both MSIs.
+
+Examples:
+  bagboy delta --previous releases/v1.0.0   # Diff against an older release
+  bagboy delta --formats rpm                # Only generate deltarpms`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		previousDir, _ := cmd.Flags().GetString("previous")
+		formats, _ := cmd.Flags().GetStringSlice("formats")
+
+		configPath, err := config.FindConfigFile()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+
+		if len(formats) > 0 {
+			cfg.Delta.Formats = formats
+		}
+
+		ui.Header("Generating Delta Artifacts")
+
+		generator := delta.NewGenerator(cfg)
+		artifacts, err := generator.Generate(cmd.Context(), previousDir)
+		for _, artifact := range artifacts {
+			ui.Success(fmt.Sprintf("%s %s → %s: %s", artifact.Format, artifact.From, artifact.To, artifact.Path))
+		}
+		return err
+	},
+}
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove stale releases, drafts and nightly assets",
+	Long: `Delete stale GitHub release artifacts according to github.prune:
+
+• Draft releases older than draft_max_age_days, when it is set (the draft
+  for the current version is kept, since it may be a scheduled release)
+• Prereleases superseded by a stable release, with prune_prereleases
+• Assets on the nightly_tag release older than nightly_max_age_days
+
+Nothing is deleted under an empty policy. The releases and assets to
+delete are listed and confirmed first; --yes skips the prompt, and is
+required when not running in a terminal.
+
+Examples:
+  bagboy prune --dry-run          # Show what would be deleted
+  bagboy prune --draft-age 14     # Delete drafts older than two weeks
+  bagboy prune --yes              # Delete without confirming, e.g. in CI
+  bagboy prune images             # Prune container image tags instead`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		configPath, err := config.FindConfigFile()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+		if err := audit.Configure(cfg.Audit); err != nil {
+			return err
+		}
+
+		policy := cfg.GitHub.Prune
+		if cmd.Flags().Changed("draft-age") {
+			policy.DraftMaxAgeDays, _ = cmd.Flags().GetInt("draft-age")
+		}
+		if cmd.Flags().Changed("nightly-age") {
+			policy.NightlyMaxAgeDays, _ = cmd.Flags().GetInt("nightly-age")
+		}
+		if cmd.Flags().Changed("delete-tags") {
+			policy.DeleteTags, _ = cmd.Flags().GetBool("delete-tags")
+		}
+
+		client, err := github.NewClient(&cfg.GitHub)
+		if err != nil {
+			return err
+		}
+
+		ui.Header("Pruning Releases")
+
+		ctx := cmd.Context()
+		releases, err := client.ListReleases(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo)
+		if err != nil {
+			return err
+		}
+
+		plan := github.PlanPrune(releases, policy, cfg.Tag(), time.Now())
+		if cfg.GitHub.Release.Immutable {
+			if kept := len(plan.Prereleases) + len(plan.Assets); kept > 0 {
+				ui.Info(fmt.Sprintf("Keeping %d published prereleases and nightly assets (github.release.immutable)", kept))
+			}
+			plan = plan.DraftsOnly()
+		}
+		if plan.Empty() {
+			ui.Success("Nothing to prune")
+			return nil
+		}
+
+		for _, release := range plan.Drafts {
+			ui.Info(fmt.Sprintf("Would delete draft %s (created %s)", release.GetName(), release.GetCreatedAt().Format("2006-01-02")))
+		}
+		for _, release := range plan.Prereleases {
+			ui.Info(fmt.Sprintf("Would delete superseded prerelease %s", release.GetTagName()))
+		}
+		for _, asset := range plan.Assets {
+			ui.Info(fmt.Sprintf("Would delete nightly asset %s (created %s)", asset.GetName(), asset.GetCreatedAt().Format("2006-01-02")))
+		}
+		if dryRun {
+			return nil
+		}
+
+		count := len(plan.Drafts) + len(plan.Prereleases) + len(plan.Assets)
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			if !ui.IsInteractive() {
+				return fmt.Errorf("refusing to delete %d releases and assets without --yes", count)
+			}
+			if !ui.Confirm(fmt.Sprintf("Delete %d releases and assets?", count)) {
+				return fmt.Errorf("prune cancelled")
+			}
+		}
+
+		return client.Prune(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo, plan, policy.DeleteTags)
+	},
+}
+
+var pruneImagesCmd = &cobra.Command{
+	Use:   "images",
+	Short: "Delete old prerelease image tags from container registries",
+	Long: `Delete prerelease image tags beyond the newest
+packages.docker.retention.keep_prereleases from every registry in
+packages.docker.registries. Release tags are never deleted.
+
+Retention is opt-in: nothing is deleted unless keep_prereleases is set.
+The tags to delete are listed and confirmed first; --yes skips the
+prompt, and is required when not running in a terminal.
+
+Requires crane (github.com/google/go-containerregistry) and registry
+credentials with delete permission.
+
+Examples:
+  bagboy prune images --dry-run   # Show which tags would be deleted
+  bagboy prune images             # Delete them after confirming
+  bagboy prune images --yes       # Delete without confirming, e.g. in CI`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		configPath, err := config.FindConfigFile()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+		if err := audit.Configure(cfg.Audit); err != nil {
+			return err
+		}
+
+		ui.Header("Pruning Container Images")
+		if cfg.Packages.Docker.Retention.KeepPrereleases <= 0 {
+			ui.Info("packages.docker.retention.keep_prereleases is not set - nothing to prune")
+			return nil
+		}
+
+		deployer := deploy.NewDeployer(cfg)
+		images, err := deployer.PlanImagePrune(cmd.Context())
+		if err != nil {
+			return err
+		}
+		if len(images) == 0 {
+			ui.Success("Nothing to prune")
+			return nil
+		}
+		for _, image := range images {
+			ui.Info(fmt.Sprintf("Would delete %s", image))
+		}
+		if dryRun {
+			return nil
+		}
+
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			if !ui.IsInteractive() {
+				return fmt.Errorf("refusing to delete %d image tags without --yes", len(images))
+			}
+			if !ui.Confirm(fmt.Sprintf("Delete %d image tags?", len(images))) {
+				return fmt.Errorf("prune cancelled")
+			}
+		}
+		return deployer.PruneImages(cmd.Context(), images)
+	},
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [<from-tag> <to-tag>]",
+	Short: "Compare two published releases, or dist/ with a release",
+	Long: `Show what changed between two published GitHub releases:
+
+• Assets added, removed or changed, with sizes and checksums (from a
+  checksums.txt or SHA256SUMS asset when the release has one)
+• Line diffs of manifest assets (Homebrew formula, Scoop JSON, Winget YAML)
+• Dependency changes declared in the config file at each tag
+• With --contents, files added, removed or resized inside deb, rpm, zip
+  and tar.gz packages, and the dependencies the packages declare
+
+Asset names are matched with the version removed, so myapp-1.0.0.msi and
+myapp-1.1.0.msi compare as the same artifact.
+
+With --local the packages in dist/ are compared against a release, the
+latest one by default, before they are published. --max-growth fails when
+a package grew by more than the given percentage, to catch accidental
+bloat in CI.
+
+Examples:
+  bagboy diff v1.0.0 v1.1.0
+  bagboy diff v1.0.0 v1.1.0 --json > audit.json
+  bagboy diff --local                   # dist/ against the latest release
+  bagboy diff --local v1.0.0 --max-growth 10`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		contents, _ := cmd.Flags().GetBool("contents")
+		local, _ := cmd.Flags().GetBool("local")
+		maxGrowth, _ := cmd.Flags().GetFloat64("max-growth")
+		switch {
+		case local && len(args) > 1:
+			return fmt.Errorf("--local compares dist/ against one release")
+		case !local && len(args) != 2:
+			return fmt.Errorf("diff needs two release tags, or --local")
+		}
+
+		configPath, err := config.FindConfigFile()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+
+		client, err := github.NewClient(&cfg.GitHub)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		var from, to *release.Snapshot
+		if local {
+			tag := ""
+			if len(args) == 1 {
+				tag = args[0]
+			} else if tag, err = client.LatestVersion(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo); err != nil {
+				return err
+			} else if tag == "" {
+				return fmt.Errorf("no published release to compare dist/ against")
+			}
+
+			files, err := distArtifacts()
+			if err != nil {
+				return err
+			}
+			if from, err = client.ReleaseSnapshot(ctx, cfg, tag, true); err != nil {
+				return err
+			}
+			if to, err = release.LocalSnapshot(cfg.Tag(), files, cfg.Dependencies); err != nil {
+				return err
+			}
+		} else {
+			if from, err = client.ReleaseSnapshot(ctx, cfg, args[0], contents); err != nil {
+				return err
+			}
+			if to, err = client.ReleaseSnapshot(ctx, cfg, args[1], contents); err != nil {
+				return err
+			}
+		}
+
+		report := release.Compare(*from, *to)
+		if asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				return err
+			}
+		} else {
+			report.Write(os.Stdout)
+			if !report.Changed() {
+				ui.Info("No differences found")
+			}
+		}
+		if name, growth := report.Growth(); maxGrowth > 0 && growth*100 > maxGrowth {
+			return fmt.Errorf("%s grew by %.1f%%, more than --max-growth %g%%", name, growth*100, maxGrowth)
+		}
+		return nil
+	},
+}
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Evaluate release policy rules",
+}
+
+var policyCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check packages in dist/ against the release policy",
+	Long: `Evaluate the rules in the policy section of the config against the
+packages in dist/. The same check runs automatically in publish, once
+the release is signed and before anything is uploaded.
+
+Built-in rules:
+  require_signed     every artifact has a signature (.sig, .asc, .minisig,
+                     .sigstore.bundle, or a verifiable embedded signature)
+                     or is listed in a signed checksums file
+  version_increase   version is greater than the latest GitHub release
+  allowed_licenses   license is in the approved list
+  no_critical_cves   the container image has no critical CVEs (trivy)
+
+Generated files are also scanned for credentials - private keys, GitHub,
+AWS, npm and PyPI tokens, passwords in URLs, and values of secret-looking
+environment variables. Matches block publish and deploy; accept false
+positives with policy.secrets.allow.
+
+Rego policies in policy.rego.files are evaluated with opa; the query
+(default data.bagboy.deny) must return the list of violations.
+
+Examples:
+  bagboy policy check
+  bagboy policy check --image myapp:1.2.0`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		image, _ := cmd.Flags().GetString("image")
+
+		configPath, err := config.FindConfigFile()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+
+		artifacts, err := distArtifacts()
+		if err != nil {
+			return err
+		}
+
+		ui.Header("Release Policy")
+		if err := checkSecrets(cfg, []string{"dist"}); err != nil {
+			return err
+		}
+		_, err = checkPolicy(cmd.Context(), cfg, artifacts, image)
+		return err
+	},
+}
+
+// distArtifacts lists the package files at the top level of dist/
+func distArtifacts() ([]string, error) {
+	entries, err := os.ReadDir("dist")
+	if err != nil {
+		return nil, fmt.Errorf("no packages found - run 'bagboy pack' first: %w", err)
+	}
+
+	var artifacts []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			artifacts = append(artifacts, filepath.Join("dist", entry.Name()))
+		}
+	}
+	return artifacts, nil
+}
+
+// checkPolicy evaluates the configured policy. The results are returned
+// with an error listing every failed rule; both are nil when no rules are
+// configured.
+// loadPrebuilt extracts binaries from pre-built archives into cfg.Binaries
+// when prebuilt.archives is configured. cleanup removes the extracted
+// binaries once packaging is done.
+func loadPrebuilt(cfg *config.Config) ([]prebuilt.Artifact, func(), error) {
+	if !prebuilt.Enabled(cfg) {
+		return nil, func() {}, nil
+	}
+
+	workDir, err := os.MkdirTemp("", "bagboy-prebuilt-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.RemoveAll(workDir) }
+
+	artifacts, err := prebuilt.Extract(cfg, workDir)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("pre-built archives: %w", err)
+	}
+	prebuilt.Apply(cfg, artifacts)
+
+	ui.Info(fmt.Sprintf("Using %d pre-built archive(s) from %s", len(artifacts), prebuilt.Dir(cfg)))
+	return artifacts, cleanup, nil
+}
+
+// runBuilds cross-compiles the binaries in builds: into dist/build and
+// adds them to cfg.Binaries
+func runBuilds(ctx context.Context, cfg *config.Config) error {
+	if !build.Enabled(cfg) {
+		return nil
+	}
+	artifacts, err := build.Run(ctx, cfg, filepath.Join("dist", "build"))
+	if err != nil {
+		return err
+	}
+	build.Apply(cfg, artifacts)
+
+	ui.Info(fmt.Sprintf("Built %d binaries with go build", len(artifacts)))
+	return nil
+}
+
+// writeSBOMs writes SBOMs for the binaries into dist and for each package,
+// installer and image file among artifacts next to it
+func writeSBOMs(cfg *config.Config, artifacts []packager.Artifact) ([]string, error) {
+	var subjects []sbom.Subject
+	for _, artifact := range artifacts {
+		if artifact.Kind == packager.KindManifest {
+			continue
+		}
+		if info, err := os.Stat(artifact.Path); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		subjects = append(subjects, sbom.Subject{Path: artifact.Path, Binary: cfg.Binaries[artifact.Platform()]})
+	}
+
+	files, err := sbom.Generate(cfg, "dist", subjects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write SBOMs: %w", err)
+	}
+	ui.Success(fmt.Sprintf("Wrote %d SBOMs for %d binaries and %d packages", len(files), len(cfg.Binaries), len(subjects)))
+	return files, nil
+}
+
+// checkHookFormats rejects hooks for formats the registry does not know,
+// which would otherwise never run
+func checkHookFormats(registry *packager.Registry, cfg *config.Config) error {
+	for format := range cfg.Hooks.Formats {
+		if _, ok := registry.Get(format); !ok {
+			return fmt.Errorf("hooks.formats: unknown format %q (available: %s)", format, strings.Join(registry.List(), ", "))
+		}
+	}
+	return nil
+}
+
+// runPreflight checks credentials and repository access before any
+// packages are built, reporting every problem instead of the first
+func runPreflight(ctx context.Context, cfg *config.Config, skipGitHub bool) error {
+	var checks []preflight.Check
+
+	if !skipGitHub && preflight.NeedsGitHub(cfg) {
+		// A nil *github.Client must reach CheckGitHub as a nil interface
+		var client preflight.GitHubAccess
+		if c, err := github.NewClient(&cfg.GitHub); err == nil {
+			client = c
+		}
+		repos := preflight.Repos{Tap: github.TapRepo(cfg), Bucket: github.BucketRepo(cfg)}
+		checks = append(checks, preflight.CheckGitHub(ctx, cfg, client, repos)...)
+	}
+	checks = append(checks, preflight.CheckRegistries(cfg)...)
+
+	if len(checks) == 0 {
+		return nil
+	}
+
+	ui.Header("Preflight")
+	printChecks(checks)
+
+	if preflight.Failed(checks) {
+		return fmt.Errorf("preflight failed - fix the problems above or use --skip-preflight")
+	}
+	return nil
+}
+
+// checkVersionGuard refuses to publish a version that is not above the
+// latest release, such as an old version number from a stale branch
+func checkVersionGuard(ctx context.Context, cfg *config.Config) error {
+	client, err := github.NewClient(&cfg.GitHub)
+	if err != nil {
+		return fmt.Errorf("cannot compare with the latest release: %w (use --force to skip)", err)
+	}
+	latest, err := client.LatestVersion(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo)
+	if err != nil {
+		return fmt.Errorf("cannot compare with the latest release: %w (use --force to skip)", err)
+	}
+	if result := policy.CheckVersionIncrease(cfg.Version, latest); !result.Passed {
+		return fmt.Errorf("%s - use --force to publish anyway", result.Message)
+	}
+	return nil
+}
+
+// checkCIStatus refuses to publish a commit whose required workflows and
+// checks have not passed on GitHub
+func checkCIStatus(ctx context.Context, cfg *config.Config) error {
+	client, err := github.NewClient(&cfg.GitHub)
+	if err != nil {
+		return fmt.Errorf("cannot check CI status: %w (use --force to skip)", err)
+	}
+	sha := build.Commit(ctx)
+	if sha == "none" {
+		return fmt.Errorf("cannot check CI status outside a git checkout (use --force to skip)")
+	}
+
+	ui.Header("CI Status")
+	checks := preflight.CheckCI(ctx, client, cfg.GitHub.Owner+"/"+cfg.GitHub.Repo, sha, cfg.GitHub.Release.RequireChecks)
+	printChecks(checks)
+	if preflight.Failed(checks) {
+		return fmt.Errorf("required checks have not passed on %s - use --force to publish anyway", sha)
+	}
+	return nil
+}
+
+// runGoModuleChecks verifies go install module@version works for the
+// release tag and reports the released version
+func runGoModuleChecks(ctx context.Context, cfg *config.Config) error {
+	ui.Header("Go Module")
+	checks := gomodule.NewVerifier().Verify(ctx, cfg)
+	printChecks(checks)
+
+	if preflight.Failed(checks) {
+		return fmt.Errorf("go module verification failed - fix the problems above before announcing the release")
+	}
+	return nil
+}
+
+// linkIdentical hard-links byte-identical packages in dist/ to one copy
+// and reports the local disk space saved
+func linkIdentical(results packager.Results) {
+	linked, saved, err := packager.LinkIdentical(results.Paths())
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to link identical packages: %v", err))
+		return
+	}
+	if linked > 0 {
+		ui.Info(fmt.Sprintf("Linked %d identical packages, saving %.1f MiB of local disk", linked, float64(saved)/(1<<20)))
+	}
+}
+
+// dedupeAssets leaves out assets that are byte-identical to an earlier
+// one, listing them in aliases.txt, which it adds to the assets and
+// returns the path of
+func dedupeAssets(assets []string) ([]string, string, error) {
+	duplicates, err := packager.Identical(assets)
+	if err != nil || len(duplicates) == 0 {
+		return assets, "", err
+	}
+
+	unique := make([]string, 0, len(assets)-len(duplicates)+1)
+	for _, asset := range assets {
+		if original, ok := duplicates[asset]; ok {
+			ui.Info(fmt.Sprintf("Uploading %s once for the identical %s", filepath.Base(original), filepath.Base(asset)))
+			continue
+		}
+		unique = append(unique, asset)
+	}
+	aliases := filepath.Join("dist", release.AliasesFile)
+	if err := release.WriteAliases(aliases, duplicates); err != nil {
+		return assets, "", fmt.Errorf("failed to write %s: %w", release.AliasesFile, err)
+	}
+	return append(unique, aliases), aliases, nil
+}
+
+// printPackResults shows PackAll results as a table, one row per format
+// in the order PackAll returns them and one more for each extra artifact,
+// such as another architecture's package. With output "markdown" the table is
+// printed in full as markdown, ready to paste into a release PR.
+func printPackResults(results packager.Results, output string) {
+	table := ui.NewTable([]string{i18n.T("Format"), i18n.T("Output Path"), i18n.T("Duration"), i18n.T("Status")})
+	for _, result := range results {
+		status := "✅ " + i18n.T("Success")
+		switch result.Status {
+		case packager.StatusFailed:
+			status = "❌ " + i18n.T("Failed: %s", result.Error)
+		case packager.StatusSkipped:
+			status = "⚠️  " + i18n.T("Skipped: %s", i18n.T(string(result.Reason)))
+			if result.Error != "" {
+				status += " (" + result.Error + ")"
+			}
+		}
+		table.AddRow([]string{result.Format, result.Path, result.Duration.Round(time.Millisecond).String(), status})
+		for _, artifact := range result.Artifacts {
+			if artifact.Path != result.Path {
+				table.AddRow([]string{result.Format + " (" + artifact.Label() + ")", artifact.Path, "", status})
+			}
+		}
+	}
+	if output == "markdown" {
+		table.Markdown(os.Stdout)
+		return
+	}
+	table.Print()
+}
+
+// outputFlag returns --output, which picks how result tables are printed
+func outputFlag(cmd *cobra.Command) (string, error) {
+	output, _ := cmd.Flags().GetString("output")
+	switch output {
+	case "table", "markdown":
+		return output, nil
+	}
+	return "", fmt.Errorf("unknown output %q (use table or markdown)", output)
+}
+
+// packParallelism returns how many formats to build at once: --parallel
+// when given, otherwise performance.max_parallel_packagers or one per CPU
+func packParallelism(cmd *cobra.Command, cfg *config.Config) int {
+	if cmd.Flags().Changed("parallel") {
+		parallel, _ := cmd.Flags().GetInt("parallel")
+		return max(parallel, 1)
+	}
+	return throttle.Packagers(cfg.Performance)
+}
+
+// packProgress returns a PackAll progress function printing one line per
+// format as it finishes
+func packProgress(total int) func(packager.Result) {
+	finished := 0
+	return func(result packager.Result) {
+		finished++
+		status := "✅ " + i18n.T("Success") + " (" + result.Duration.Round(time.Millisecond).String() + ")"
+		switch result.Status {
+		case packager.StatusFailed:
+			status = "❌ " + i18n.T("Failed: %s", result.Error)
+		case packager.StatusSkipped:
+			status = "⚠️  " + i18n.T("Skipped: %s", i18n.T(string(result.Reason)))
+		}
+		fmt.Printf("  [%d/%d] %s: %s\n", finished, total, result.Format, status)
+	}
+}
+
+// warnMissingTools warns about the formats PackAll skipped because this
+// machine lacks the tools to build them
+func warnMissingTools(results packager.Results) {
+	var formats []string
+	for _, result := range results {
+		if result.Status == packager.StatusSkipped && result.Reason == packager.SkipToolMissing {
+			formats = append(formats, result.Format)
+		}
+	}
+	if len(formats) > 0 {
+		ui.Warning(i18n.T("Skipped %s: missing build tools on this machine (see 'bagboy check'; use --strict to fail instead)", strings.Join(formats, ", ")))
+	}
+}
+
+// writePackReport writes the PackAll results as JSON when --report is set
+func writePackReport(path string, results packager.Results) error {
+	if path == "" {
+		return nil
+	}
+	if err := results.WriteJSON(path); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// lintNames reports package names the registries of formats would reject
+// or change, all enabled formats when formats is nil, and fails before
+// anything is built if a name cannot be used
+func lintNames(cfg *config.Config, formats []string) error {
+	checks := preflight.LintNames(cfg, formats)
+	printChecks(checks)
+	if preflight.Failed(checks) {
+		return fmt.Errorf("package names do not meet registry rules - fix the problems above")
+	}
+	return nil
+}
+
+func printChecks(checks []preflight.Check) {
+	for _, check := range checks {
+		message := fmt.Sprintf("%s: %s", check.Name, check.Message)
+		switch check.Status {
+		case preflight.StatusPass:
+			ui.Success(message)
+		case preflight.StatusWarn:
+			ui.Warning(message)
+		default:
+			ui.Error(message)
+		}
+	}
+}
+
+// checkSecrets refuses to go on when generated files contain credentials
+func checkSecrets(cfg *config.Config, paths []string) error {
+	if cfg.Policy.Secrets.Disabled {
+		return nil
+	}
+	scanner, err := secrets.NewScanner(&cfg.Policy.Secrets, cfg.GitHub.TokenEnv)
+	if err != nil {
+		return err
+	}
+	findings, err := scanner.Scan(paths)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("secrets scan failed: %w", err)
+	}
+	if len(findings) == 0 {
+		ui.Success("secrets: no credentials found in generated files")
+		return nil
+	}
+	for _, finding := range findings {
+		ui.Error("secrets: " + finding.String())
+	}
+	return errors.NewValidationError(errors.CodeSecretFound,
+		fmt.Sprintf("release blocked: %d possible secret(s) in generated files", len(findings)),
+		"Remove the secret from the template or environment it was expanded from and pack again, or add a pattern to policy.secrets.allow if it is not a secret")
+}
+
+// signAndCheckRelease writes checksums.txt for the released assets, signs
+// it, and the assets if configured, with every enabled signer, and then
+// enforces the release policy on the signed assets. It returns the files
+// to attach alongside the assets; checksums.txt is only attached once.
+func signAndCheckRelease(ctx context.Context, cfg *config.Config, released []string, image string) ([]string, error) {
+	checksums := filepath.Join("dist", signing.ChecksumsFile)
+	if err := release.WriteChecksums(checksums, released); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", signing.ChecksumsFile, err)
+	}
+	files := []string{checksums}
+
+	// With GPG and cosign
+	signatures, err := signing.NewSigner(cfg).SignChecksums(ctx, checksums)
+	files = append(files, signatures...)
+	if err != nil {
+		return files, fmt.Errorf("failed to sign %s: %w", signing.ChecksumsFile, err)
+	}
+	ui.Success(fmt.Sprintf("Wrote %s for %d assets", checksums, len(released)))
+
+	// With minisign or signify, publishing the public key with them
+	if cfg.Signing.Minisign.Enabled {
+		signed, err := signing.NewSigner(cfg).SignRelease(ctx, released, "dist")
+		if err != nil {
+			return files, fmt.Errorf("failed to sign release: %w", err)
+		}
+		files = append(files, signed[1:]...)
+		ui.Success(fmt.Sprintf("Signed %s with %s", signing.ChecksumsFile, signing.MinisignTool(cfg)))
+	}
+
+	// And an SSH key, publishing allowed_signers to verify with
+	if cfg.Signing.SSH.Enabled {
+		signed, err := signing.NewSigner(cfg).SignReleaseSSH(ctx, released, "dist")
+		if err != nil {
+			return files, fmt.Errorf("failed to sign release: %w", err)
+		}
+		files = append(files, signed[1:]...)
+		ui.Success(fmt.Sprintf("Signed %s with SSH key", signing.ChecksumsFile))
+	}
+
+	artifacts := append(append([]string{}, released...), checksums)
+	if _, err := checkPolicy(ctx, cfg, artifacts, image); err != nil {
+		return files, err
+	}
+	return files, nil
+}
+
+func checkPolicy(ctx context.Context, cfg *config.Config, artifacts []string, image string) ([]policy.Result, error) {
+	engine := policy.NewEngine(&cfg.Policy)
+	if !engine.Enabled() {
+		ui.Info("No policy rules configured")
+		return nil, nil
+	}
+
+	input := policy.Input{
+		Name:      cfg.Name,
+		Version:   cfg.Version,
+		License:   cfg.License,
+		Artifacts: artifacts,
+		Image:     image,
+	}
+
+	if cfg.Policy.VersionIncrease {
+		client, err := github.NewClient(&cfg.GitHub)
+		if err != nil {
+			return nil, fmt.Errorf("version_increase needs GitHub access: %w", err)
+		}
+		input.LatestVersion, err = client.LatestVersion(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results, err := engine.Evaluate(ctx, input)
+	for _, result := range results {
+		if result.Passed {
+			ui.Success(fmt.Sprintf("%s: %s", result.Rule, result.Message))
+		} else {
+			ui.Error(fmt.Sprintf("%s: %s", result.Rule, result.Message))
+		}
+	}
+	if err != nil {
+		return results, err
+	}
+
+	if failed := policy.Failed(results); len(failed) > 0 {
+		var rules []string
+		for _, result := range failed {
+			rules = append(rules, result.Rule)
+		}
+		return results, fmt.Errorf("release blocked by policy: %s", strings.Join(rules, ", "))
+	}
+	return results, nil
+}
+
+var sbomCmd = &cobra.Command{
+	Use:   "sbom [file...]",
+	Short: "Write SPDX and CycloneDX SBOMs for the binaries and packages",
+	Long: `Write a software bill of materials for every configured binary, as
+dist/<name>-<platform>.spdx.json and .cdx.json, and for each file given,
+next to the file. The modules compiled into Go binaries are read from
+their build info.
+
+pack --sbom and publish --sbom do the same for every package they build,
+embed the binary's SPDX SBOM in deb and rpm packages and docker images,
+and publish attaches the SBOMs to the release.
+
+Examples:
+  bagboy sbom
+  bagboy sbom dist/*.deb dist/*.rpm
+  bagboy sbom --format spdx`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		formats, _ := cmd.Flags().GetStringSlice("format")
+
+		configPath, err := config.FindConfigFile()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+		if len(formats) > 0 {
+			cfg.SBOM.Formats = formats
+		}
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf(i18n.T("config validation failed: %w"), err)
+		}
+
+		var artifacts []packager.Artifact
+		for _, path := range args {
+			artifacts = append(artifacts, packager.Artifact{Path: path})
+		}
+		files, err := writeSBOMs(cfg, artifacts)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			fmt.Printf("  %s\n", file)
+		}
+		return nil
+	},
+}
+
+var buildEnvCmd = &cobra.Command{
+	Use:   "buildenv",
+	Short: "Show or compare the build environment manifest",
+	Long: `Describe this machine as publish --build-env records it: the OS and
+its version, the versions of go, git, docker and the package builders
+found, and the environment variables that change what they produce
+(GO*, CGO_*, CC, SOURCE_DATE_EPOCH, LANG, CI settings and build_env.env).
+Variables whose names look like secrets are never recorded.
+
+--diff compares with the build-environment.json attached to another
+release, to see why two maintainers' packages of one version differ.
+
+Examples:
+  bagboy buildenv
+  bagboy buildenv --diff build-environment.json
+  bagboy buildenv --diff v1.0.json --against v1.1.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputPath, _ := cmd.Flags().GetString("output")
+		diffPath, _ := cmd.Flags().GetString("diff")
+		againstPath, _ := cmd.Flags().GetString("against")
+		if againstPath != "" && diffPath == "" {
+			return fmt.Errorf("--against requires --diff")
+		}
+
+		var current *buildenv.Manifest
+		if againstPath != "" {
+			m, err := buildenv.Load(againstPath)
+			if err != nil {
+				return err
+			}
+			current = m
+		} else {
+			cfg := &config.Config{}
+			if configPath, err := config.FindConfigFile(); err == nil {
+				if cfg, err = config.Load(configPath); err != nil {
+					return err
+				}
+			}
+			current = buildenv.Capture(cmd.Context(), cfg, bagboyVersion())
+		}
+
+		if diffPath != "" {
+			other, err := buildenv.Load(diffPath)
+			if err != nil {
+				return err
+			}
+			diffs := buildenv.Compare(other, current)
+			if len(diffs) == 0 {
+				ui.Info("No differences found")
+				return nil
+			}
+			for _, diff := range diffs {
+				fmt.Printf("  %s\n", diff)
+			}
+			return nil
+		}
+
+		if outputPath != "" {
+			if err := current.Write(outputPath); err != nil {
+				return err
+			}
+			ui.Success(fmt.Sprintf("Wrote %s", outputPath))
+			return nil
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(current)
+	},
+}
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "selfupdate",
+	Short: "Update bagboy to the latest release",
+	Long: `Replace this bagboy with the latest official release, or --version.
+
+bagboy is released by bagboy itself. Each release's checksums.txt is
+signed with the project's signify key, whose public half is built into
+release binaries; the new binary is only installed once that signature and
+its SHA-256 check out.
+
+Installs managed by Homebrew, Scoop and other package managers are left
+to them unless --force is given.
+
+Examples:
+  bagboy selfupdate --check
+  bagboy selfupdate
+  bagboy selfupdate --version v0.8.0`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		check, _ := cmd.Flags().GetBool("check")
+		target, _ := cmd.Flags().GetString("version")
+		publicKey, _ := cmd.Flags().GetString("public-key")
+		force, _ := cmd.Flags().GetBool("force")
+
+		updater := selfupdate.New()
+		if publicKey != "" {
+			key, err := os.ReadFile(publicKey)
+			if err != nil {
+				return err
+			}
+			updater.PublicKey = string(key)
+		}
+
+		ctx := cmd.Context()
+		current := bagboyVersion()
+		if target == "" {
+			latest, err := updater.Latest(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to find the latest release: %w", err)
+			}
+			target = latest
+		}
+		if !strings.HasPrefix(target, "v") {
+			target = "v" + target
+		}
+
+		_, currentErr := semver.Parse(current)
+		if check {
+			if currentErr == nil && !semver.Less(current, target) {
+				ui.Success(fmt.Sprintf("bagboy %s is up to date", current))
+				return nil
+			}
+			ui.Info(fmt.Sprintf("bagboy %s is available (running %s); run 'bagboy selfupdate'", target, current))
+			return nil
+		}
+		if !force {
+			switch {
+			case currentErr != nil:
+				return fmt.Errorf("this is a development build (%s) - use --force to replace it with %s", current, target)
+			case !cmd.Flags().Changed("version") && !semver.Less(current, target):
+				ui.Success(fmt.Sprintf("bagboy %s is up to date", current))
+				return nil
+			}
+		}
+
+		exe, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		if exe, err = filepath.EvalSymlinks(exe); err != nil {
+			return err
+		}
+		if manager := selfupdate.Managed(exe); manager != "" && !force {
+			return fmt.Errorf("%s was installed by %s - update it there, or use --force", exe, manager)
+		}
+
+		binary, err := updater.Download(ctx, target, selfupdate.Platform())
+		if err != nil {
+			return err
+		}
+		ui.Success(fmt.Sprintf("Verified the signature of bagboy %s", target))
+		if selfupdate.Current(exe, binary) {
+			ui.Success(fmt.Sprintf("%s is already bagboy %s", exe, target))
+			return nil
+		}
+		if err := selfupdate.Replace(exe, binary); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", exe, err)
+		}
+		ui.Success(fmt.Sprintf("Updated %s from %s to %s", exe, current, target))
+		return nil
+	},
+}
+
+var attestCmd = &cobra.Command{
+	Use:   "attest",
+	Short: "Export an attestation bundle for the release",
+	Long: `Bundle everything an auditor needs to check the release offline into
+one tarball: checksums, detached signatures, SBOMs, an in-toto statement
+with SLSA provenance, and the release policy results.
+
+Signatures (.sig, .asc, .minisig, .sigstore.bundle) and SBOMs (.spdx.json,
+.cdx.json, ...) are collected from dist/ and from files next to each
+package. Verify the bundle with 'bagboy attest verify --bundle'.
+
+Examples:
+  bagboy attest
+  bagboy attest --output audit/myapp-1.2.0.tar.gz
+  bagboy attest --oci ghcr.io/me/myapp-attestations:1.2.0`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		ociRef, _ := cmd.Flags().GetString("oci")
+
+		configPath, err := config.FindConfigFile()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+		if err := audit.Configure(cfg.Audit); err != nil {
+			return err
+		}
+
+		if output == "" {
+			output = attest.DefaultBundlePath(cfg)
+		}
+
+		artifacts, err := distArtifacts()
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		ui.Header("Attestation Bundle")
+
+		// Policy failures are recorded in the bundle rather than aborting
+		results, err := checkPolicy(ctx, cfg, artifacts, "")
+		if err != nil && results == nil {
+			return err
+		}
+
+		if err := attest.Export(cfg, artifacts, results, output, time.Now()); err != nil {
+			return err
+		}
+		ui.Success(fmt.Sprintf("Wrote %s", output))
+
+		if ociRef != "" {
+			if err := attest.Push(ctx, output, ociRef); err != nil {
+				return err
+			}
+			ui.Success(fmt.Sprintf("Pushed %s", ociRef))
+		}
+		return nil
+	},
+}
+
+var attestVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify an attestation bundle offline",
+	Long: `Verify an attestation bundle created by 'bagboy attest' without network
+access:
+
+• Every file in the bundle matches the digest in its manifest
+• Provenance subjects match checksums.txt
+• Recorded policy results all passed
+• Packages in --artifacts match the attested digests
+• GPG signatures verify against the local keyring
+
+Examples:
+  bagboy attest verify --bundle myapp-1.2.0.attestations.tar.gz
+  bagboy attest verify --bundle bundle.tar.gz --artifacts downloads/`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bundle, _ := cmd.Flags().GetString("bundle")
+		artifactsDir, _ := cmd.Flags().GetString("artifacts")
+
+		if bundle == "" {
+			return fmt.Errorf("--bundle is required")
+		}
+
+		report, err := attest.Verify(cmd.Context(), bundle, artifactsDir)
+		if err != nil {
+			return err
+		}
+
+		ui.Header(fmt.Sprintf("Verifying %s %s", report.Manifest.Name, report.Manifest.Release))
+		for _, check := range report.Checks {
+			message := fmt.Sprintf("%s: %s", check.Name, check.Message)
+			switch check.Status {
+			case attest.StatusPass:
+				ui.Success(message)
+			case attest.StatusFail:
+				ui.Error(message)
+			default:
+				ui.Info(message)
+			}
+		}
+
+		if !report.Passed() {
+			return fmt.Errorf("bundle verification failed")
+		}
+		ui.Success("Bundle verified")
+		return nil
+	},
+}
+
+var gomodCmd = &cobra.Command{
+	Use:   "gomod",
+	Short: "Verify the release installs with go install",
+	Long: `Check that go install module@version works for the configured version
+of a Go project:
+
+• The module path's /vN suffix matches the version
+• The release tag has been pushed to origin
+• The module proxy (first entry of GOPROXY) serves the version
+• The binary go install builds prints the released version
+• pkg.go.dev has indexed the version and the README links to it
+
+go install does not pass -ldflags, so a binary that only gets its version
+from -X reports its default. publish runs these checks after creating the
+release when go_module.enabled is set.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, err := config.FindConfigFile()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+
+		if err := runGoModuleChecks(cmd.Context(), cfg); err != nil {
+			return err
+		}
+		ui.Success("go install works for " + cfg.Version)
+		return nil
+	},
+}
+
+var snippetsCmd = &cobra.Command{
+	Use:   "snippets",
+	Short: "Print install instructions for every published format",
+	Long: `Print ready-to-paste install instructions in markdown for each channel
+the configuration publishes to: Homebrew tap, Scoop bucket, Winget, PPA or
+COPR repositories, Docker registries, go install and the install scripts.
+Registries such as npm, PyPI, Cargo and Snap are included when listed in
+packages.enabled. deb and rpm download links use the packages in dist/.
+
+With --update, replace the section of a file between
+<!-- bagboy snippets start --> and <!-- bagboy snippets end --> so the
+README stays in sync with the configuration.
+
+Examples:
+  bagboy snippets
+  bagboy snippets --update README.md`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		update, _ := cmd.Flags().GetString("update")
+
+		configPath, err := config.FindConfigFile()
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+
+		var assets []string
+		for _, ext := range []string{".deb", ".rpm"} {
+			matches, _ := filepath.Glob(filepath.Join("dist", cfg.Name+"*"+cfg.Version+"*"+ext))
+			assets = append(assets, matches...)
+		}
+		list := snippets.Generate(cfg, assets)
+		if len(list) == 0 {
+			return fmt.Errorf("no published formats configured - set up a tap, bucket, repository or packages.enabled")
+		}
+
+		if update == "" {
+			snippets.Markdown(os.Stdout, list)
+			return nil
+		}
+		if err := snippets.Update(update, list); err != nil {
+			return err
+		}
+		ui.Success(fmt.Sprintf("Updated install instructions in %s", update))
+		return nil
+	},
+}
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Maintain CHANGELOG.md",
+	Long:  `Maintain the changelog in the Keep a Changelog format. The entry for the
+current version is used for the deb and rpm package changelogs.`,
+}
+
+var changelogUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Add the release to CHANGELOG.md and commit it",
+	Long: `Add an entry for the configured version below the Unreleased heading of
+CHANGELOG.md (or the file set by changelog:), creating the file if needed.
+
+The entry takes the changes listed under Unreleased. When there are none,
+it is generated from the commit subjects since the previous tag: feat
+commits go under Added, fix under Fixed, security under Security and
+anything else under Changed, while chore, docs, test, ci and build commits
+are left out.
+
+Examples:
+  bagboy changelog update
+  bagboy changelog update --no-commit`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		noCommit, _ := cmd.Flags().GetBool("no-commit")
+
+		configPath, err := config.FindConfigFile()
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return err
+		}
+
+		release, err := changelog.Update(cmd.Context(), cfg, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		ui.Success(fmt.Sprintf("Added %s to %s with %d changes", release.Version, changelog.Path(cfg), len(release.Items())))
+
+		if noCommit {
+			return nil
+		}
+		if err := changelog.Commit(cmd.Context(), cfg); err != nil {
+			return err
+		}
+		ui.Success("Committed " + changelog.Path(cfg))
 		return nil
 	},
 }
 
-var publishCmd = &cobra.Command{
-	Use:     "publish",
-	Aliases: []string{"pub", "release", "deploy"},
-	Short:   "Pack all formats and create GitHub release",
-	Long: `Complete publishing workflow: pack, release, and distribute.
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the configuration for nfpm or goreleaser",
+	Long: `Render bagboy.yaml as an equivalent nfpm or goreleaser config, for
+teams that need to interoperate with those tools or migrate to them.
 
-This command will:
-• Create all package formats
-• Create GitHub release with assets
-• Update Homebrew tap (if configured)
-• Update Scoop bucket (if configured)
-• Submit Winget PR (if configured)
+nfpm builds one architecture per config; use --arch to export a Linux
+binary other than the primary one. Anything that cannot be translated is
+listed as a warning.
 
 Examples:
-  bagboy publish                # Full publish workflow
-  bagboy publish --dry-run      # Preview what would happen
-  bagboy publish --skip-github  # Skip GitHub operations`,
+  bagboy export --format goreleaser > .goreleaser.yaml
+  bagboy export --format nfpm --arch arm64 --output nfpm-arm64.yaml`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		dryRun, _ := cmd.Flags().GetBool("dry-run")
-		skipGitHub, _ := cmd.Flags().GetBool("skip-github")
-
-		if dryRun {
-			ui.Warning("DRY RUN MODE - No changes will be made")
-		}
-
-		ui.PrintBanner()
-		ui.Header("Publishing Workflow")
+		format, _ := cmd.Flags().GetString("format")
+		arch, _ := cmd.Flags().GetString("arch")
+		output, _ := cmd.Flags().GetString("output")
 
 		configPath, err := config.FindConfigFile()
 		if err != nil {
@@ -568,320 +3237,359 @@ Examples:
 		}
 
 		if err := cfg.Validate(); err != nil {
-			return fmt.Errorf("config validation failed: %w", err)
-		}
-
-		if dryRun {
-			ui.Info("Would create packages for:")
-			for _, format := range []string{"brew", "scoop", "deb", "rpm", "docker"} {
-				ui.Info(fmt.Sprintf("  • %s", format))
-			}
-			if !skipGitHub && cfg.GitHub.Owner != "" {
-				ui.Info("Would create GitHub release and update repositories")
-			}
-			return nil
+			return fmt.Errorf(i18n.T("config validation failed: %w"), err)
 		}
 
-		fmt.Println("🚀 Publishing", cfg.Name, cfg.Version)
-
-		// Create packages
-		registry := packager.NewRegistry()
-		registry.Register(brew.New())
-		registry.Register(scoop.New())
-		registry.Register(deb.New())
-		registry.Register(rpm.New())
-		registry.Register(chocolatey.New())
-		registry.Register(winget.New())
-		registry.Register(snap.New())
-		registry.Register(appimage.New())
-		registry.Register(flatpak.New())
-		registry.Register(npm.New())
-		registry.Register(pypi.New())
-		registry.Register(docker.New())
-		registry.Register(apptainer.New())
-		registry.Register(dmg.New())
-		registry.Register(msi.New())
-		registry.Register(msix.New())
-		registry.Register(cargo.New())
-		registry.Register(nix.New())
-		registry.Register(installer.New())
-		registry.Register(spack.New())
-		ctx := context.Background()
-		results, err := registry.PackAll(ctx, cfg)
+		result, err := export.Export(cfg, format, arch)
 		if err != nil {
 			return err
 		}
 
-		fmt.Println("✅ Created packages:")
-		var assets []string
-		for name, path := range results {
-			fmt.Printf("  %s: %s\n", name, path)
-			assets = append(assets, path)
+		// Notes go to stderr so stdout can be redirected to a file
+		for _, note := range result.Notes {
+			fmt.Fprintf(os.Stderr, "⚠️  %s\n", note)
 		}
 
-		if dryRun {
-			fmt.Println("🔍 Dry run - would create GitHub release with assets:", assets)
-			return nil
+		if output == "" {
+			_, err := os.Stdout.Write(result.Data)
+			return err
+		}
+		if err := os.WriteFile(output, result.Data, 0644); err != nil {
+			return err
 		}
+		ui.Success(fmt.Sprintf("Wrote %s", output))
+		return nil
+	},
+}
 
-		// Create GitHub release
-		if cfg.GitHub.Release.Enabled {
-			client, err := github.NewClient(&cfg.GitHub)
-			if err != nil {
-				fmt.Printf("⚠️  GitHub integration disabled: %v\n", err)
-				return nil
-			}
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage the GPG release signing key",
+	Long: `Generate, export, publish and rotate a dedicated GPG key for signing
+releases. Settings are read from signing.keys in bagboy.yaml and the key
+history is kept in keys/keys.yaml next to the exported public keys:
 
-			release, err := client.CreateRelease(ctx, cfg, assets)
-			if err != nil {
-				return fmt.Errorf("failed to create GitHub release: %w", err)
-			}
+  keys/<name>.asc                  armored public key for users
+  keys/RPM-GPG-KEY-<name>          gpgkey for YUM/DNF repositories
+  keys/<name>-archive-keyring.gpg  keyring for APT signed-by
 
-			fmt.Printf("✅ Created GitHub release: %s\n", release.GetHTMLURL())
+Set BAGBOY_GPG_PASSPHRASE to protect generated keys with a passphrase.
 
-			// Update tap and bucket
-			if cfg.GitHub.Tap.Enabled {
-				if err := client.UpdateTap(ctx, cfg, results["brew"]); err != nil {
-					fmt.Printf("⚠️  Failed to update tap: %v\n", err)
-				} else {
-					fmt.Printf("✅ Updated Homebrew tap: %s\n", cfg.GitHub.Tap.Repo)
-				}
-			}
+Examples:
+  bagboy keys generate
+  bagboy keys publish
+  bagboy keys rotate`,
+}
 
-			if cfg.GitHub.Bucket.Enabled {
-				if err := client.UpdateBucket(ctx, cfg, results["scoop"]); err != nil {
-					fmt.Printf("⚠️  Failed to update bucket: %v\n", err)
-				} else {
-					fmt.Printf("✅ Updated Scoop bucket: %s\n", cfg.GitHub.Bucket.Repo)
-				}
-			}
+var keysGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate the release signing key and export it",
+	Long: `Generate the release signing key and export it.
 
-			// Submit Winget PR
-			if cfg.GitHub.Winget.Enabled && cfg.GitHub.Winget.AutoPR {
-				fmt.Println("Submitting Winget PR...")
-				wingetResult, exists := results["winget"]
-				if exists && wingetResult != "" {
-					// Read all manifest files from the winget output directory
-					manifests := make(map[string]string)
-					manifestFiles := []string{
-						fmt.Sprintf("%s.yaml", cfg.Packages.Winget.PackageIdentifier),
-						fmt.Sprintf("%s.installer.yaml", cfg.Packages.Winget.PackageIdentifier),
-						fmt.Sprintf("%s.locale.en-US.yaml", cfg.Packages.Winget.PackageIdentifier),
-					}
-					
-					for _, filename := range manifestFiles {
-						manifestPath := filepath.Join(wingetResult, filename)
-						if content, err := os.ReadFile(manifestPath); err == nil {
-							manifests[filename] = string(content)
-						}
-					}
-					
-					if len(manifests) > 0 {
-						if err := client.SubmitWingetPR(ctx, cfg, manifests); err != nil {
-							fmt.Printf("⚠️  Failed to submit Winget PR: %v\n", err)
-						}
-					}
-				}
-			}
+With --tool minisign or --tool signify, generate a key pair for
+signing.minisign instead of a GPG key. The public key is written to
+keys/<name>.pub (signing.minisign.public_key) to commit and is attached to
+every release; the secret key goes to ~/.minisign or ~/.signify. Set
+BAGBOY_MINISIGN_PASSWORD to protect a minisign key with a password.
+
+With --tool ssh, add your existing SSH key to keys/allowed_signers
+(signing.ssh.allowed_signers) for signing.ssh. Commit the file; it is
+attached to every release for ssh-keygen -Y verify.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tool, _ := cmd.Flags().GetString("tool")
+		if tool == signing.ToolMinisign || tool == signing.ToolSignify {
+			return generateMinisignKey(cmd.Context(), tool)
+		}
+		if tool == "ssh" {
+			return generateAllowedSigners(cmd.Context())
+		}
+		if tool != "gpg" {
+			return fmt.Errorf("unknown key tool %q (use gpg, minisign, signify or ssh)", tool)
 		}
 
-		fmt.Println("\n🎉 Publish complete!")
-		return nil
+		_, manager, manifest, err := loadKeys()
+		if err != nil {
+			return err
+		}
+		if manifest.Current != "" {
+			return fmt.Errorf("release key %s already exists - use 'bagboy keys rotate' to replace it", manifest.Current)
+		}
+
+		userID, err := manager.UserID()
+		if err != nil {
+			return err
+		}
+		ui.Info(fmt.Sprintf("Generating release key for %s...", userID))
+
+		ctx := cmd.Context()
+		fingerprint, err := manager.Rotate(ctx, manifest)
+		if err != nil {
+			return err
+		}
+		return saveKeys(ctx, manager, manifest, fingerprint)
 	},
 }
 
-var checkCmd = &cobra.Command{
-	Use:   "check",
-	Short: "Check system requirements for package formats",
+var keysExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the public release keys into the repository",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		formats, _ := cmd.Flags().GetStringSlice("formats")
-		if len(formats) == 0 {
-			formats = []string{"brew", "scoop", "deb", "rpm", "dmg", "msi", "docker", "snap", "appimage"}
+		_, manager, manifest, err := loadKeys()
+		if err != nil {
+			return err
 		}
-		
-		checker := requirements.NewRequirementChecker()
-		results := checker.CheckRequirements(formats)
-		checker.PrintRequirementReport(results)
-		
-		return nil
+		return saveKeys(cmd.Context(), manager, manifest, "")
 	},
 }
 
-var deployCmd = &cobra.Command{
-	Use:   "deploy",
-	Short: "Deploy packages to repositories and registries",
+var keysPublishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Upload the public release keys to keyservers",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		targets, _ := cmd.Flags().GetStringSlice("targets")
-		dryRun, _ := cmd.Flags().GetBool("dry-run")
-		
-		if len(targets) == 0 {
-			// Show available targets
-			deployer := deploy.NewDeployer(nil)
-			deploymentTargets := deployer.GetDeploymentTargets()
-			
-			fmt.Println("📦 Available Deployment Targets:")
-			fmt.Println("================================")
-			for _, target := range deploymentTargets {
-				fmt.Printf("\n🎯 %s (%s)\n", target.Name, target.Format)
-				fmt.Printf("   %s\n", target.Description)
-			}
-			fmt.Println("\nUsage: bagboy deploy --targets brew,npm,docker")
-			return nil
-		}
-		
-		configPath, err := config.FindConfigFile()
+		cfg, manager, manifest, err := loadKeys()
 		if err != nil {
 			return err
 		}
-		
-		cfg, err := config.Load(configPath)
-		if err != nil {
+		if err := audit.Configure(cfg.Audit); err != nil {
 			return err
 		}
-		
-		deployer := deploy.NewDeployer(cfg)
-		ctx := context.Background()
-		
-		return deployer.Deploy(ctx, targets, dryRun)
+		if err := manager.Publish(cmd.Context(), manifest); err != nil {
+			return err
+		}
+		ui.Success(fmt.Sprintf("Uploaded to %s", strings.Join(manager.Keyservers(), ", ")))
+		return nil
 	},
 }
 
-var signCmd = &cobra.Command{
-	Use:   "sign",
-	Short: "Check code signing setup and sign binaries",
+var keysRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Replace the release key, keeping the old key published for an overlap period",
+	Long: `Generate a new release key, certify it with the current key, and retire
+the current key. The retired key stays in the exported public keys for
+signing.keys.overlap (default 90d) so users can verify releases signed
+with either key while they pick up the new one.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		checkOnly, _ := cmd.Flags().GetBool("check")
-		binaryPath, _ := cmd.Flags().GetString("binary")
-		
-		configPath, err := config.FindConfigFile()
-		if err != nil && !checkOnly {
+		_, manager, manifest, err := loadKeys()
+		if err != nil {
 			return err
 		}
-		
-		var cfg *config.Config
-		if configPath != "" {
-			cfg, err = config.Load(configPath)
-			if err != nil {
-				return err
-			}
+		if manifest.Current == "" {
+			return fmt.Errorf("no release key to rotate - run 'bagboy keys generate' first")
 		}
-		
-		signer := signing.NewSigner(cfg)
-		
-		if checkOnly || binaryPath == "" {
-			// Check signing setup
-			results := signer.CheckSigningSetup()
-			signer.PrintSigningReport(results)
-			return nil
+
+		ctx := cmd.Context()
+		fingerprint, err := manager.Rotate(ctx, manifest)
+		if err != nil {
+			return err
 		}
-		
-		// Sign specific binary
-		ctx := context.Background()
-		return signer.SignBinary(ctx, binaryPath)
+		retired := manifest.Retired[len(manifest.Retired)-1]
+		ui.Info(fmt.Sprintf("Retired %s; it stays published until %s", retired.Fingerprint, retired.Until.Format("2006-01-02")))
+		return saveKeys(ctx, manager, manifest, fingerprint)
 	},
 }
 
-var validateCmd = &cobra.Command{
-	Use:     "validate",
-	Aliases: []string{"v", "check", "verify"},
-	Short:   "Validate bagboy configuration",
-	Long: `Validate your bagboy.yaml configuration file.
+// generateMinisignKey creates the minisign or signify key pair and shows
+// the public key for the README
+func generateMinisignKey(ctx context.Context, tool string) error {
+	configPath, err := config.FindConfigFile()
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
 
-Checks for:
-• Valid YAML syntax
-• Required fields (name, version, binaries)
-• Binary file existence
-• GitHub repository access (if configured)
-• Package format compatibility
+	publicKey, secretKey, err := signing.GenerateMinisignKey(ctx, cfg, tool)
+	if err != nil {
+		return err
+	}
+	ui.Success(fmt.Sprintf("Secret key: %s", secretKey))
+	ui.Success(fmt.Sprintf("Public key: %s", publicKey))
+	if data, err := os.ReadFile(publicKey); err == nil {
+		fmt.Println()
+		fmt.Print(string(data))
+		fmt.Println()
+	}
+	ui.Info(fmt.Sprintf("Commit %s, set signing.minisign.enabled (tool: %s), and keep the secret key out of the repository", publicKey, tool))
+	return nil
+}
 
-Examples:
-  bagboy validate               # Validate current configuration
-  bagboy validate --verbose     # Show detailed validation info`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		verbose, _ := cmd.Flags().GetBool("verbose")
-		
-		ui.Header("Validating Configuration")
-		
-		configPath, err := config.FindConfigFile()
-		if err != nil {
-			ui.Error("No bagboy configuration file found")
-			ui.Info("Run 'bagboy init' to create a new configuration")
-			return errors.NewConfigurationError("CONFIG_NOT_FOUND", "No bagboy configuration file found", 
-				"Run 'bagboy init' to create a new configuration",
-				"Ensure bagboy.yaml exists in the current directory")
-		}
+// generateAllowedSigners adds the SSH signing key to allowed_signers
+func generateAllowedSigners(ctx context.Context) error {
+	configPath, err := config.FindConfigFile()
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
 
-		if verbose {
-			ui.Info(fmt.Sprintf("Found config file: %s", configPath))
-		}
+	path, err := signing.WriteAllowedSigners(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	ui.Success(fmt.Sprintf("Allowed signers: %s", path))
+	ui.Info(fmt.Sprintf("Commit %s, set signing.ssh.enabled, and add the key to GitHub as a signing key for verified tags", path))
+	return nil
+}
 
-		cfg, err := config.Load(configPath)
-		if err != nil {
-			ui.Error("Failed to load configuration file")
-			return errors.WrapError(err, "Failed to load configuration file", 
-				"Check the syntax of your bagboy.yaml file",
-				"Run 'bagboy init' to regenerate the configuration")
-		}
+// loadKeys returns the key manager and key history for the project
+func loadKeys() (*config.Config, *keys.Manager, *keys.Manifest, error) {
+	configPath, err := config.FindConfigFile()
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-		if err := cfg.Validate(); err != nil {
-			ui.Error("Configuration validation failed")
-			return errors.WrapError(err, "Configuration validation failed", 
-				"Fix the issues in your bagboy.yaml file",
-				"Run 'bagboy init' to regenerate with correct structure")
-		}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-		ui.Success("Configuration is valid")
-		
-		if verbose {
-			ui.Info(fmt.Sprintf("Project: %s v%s", cfg.Name, cfg.Version))
-			ui.Info(fmt.Sprintf("Binaries: %d configured", len(cfg.Binaries)))
-			if cfg.GitHub.Owner != "" {
-				ui.Info(fmt.Sprintf("GitHub: %s/%s", cfg.GitHub.Owner, cfg.GitHub.Repo))
-			}
-		}
-		
-		return nil
-	},
+	manager := keys.NewManager(cfg)
+	manifest, err := manager.LoadManifest()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return cfg, manager, manifest, nil
+}
+
+// saveKeys records the key history and exports the public keys. A new
+// fingerprint is reported so it can be configured for signing.
+func saveKeys(ctx context.Context, manager *keys.Manager, manifest *keys.Manifest, fingerprint string) error {
+	if err := manager.SaveManifest(manifest); err != nil {
+		return err
+	}
+	files, err := manager.Export(ctx, manifest)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		ui.Success(fmt.Sprintf("Exported %s", file))
+	}
+
+	if fingerprint != "" {
+		ui.Success(fmt.Sprintf("Release key: %s", fingerprint))
+		ui.Info("Set signing.linux.gpg_key_id and GPG_KEY_ID to the new key, then run 'bagboy keys publish'")
+	}
+	return nil
 }
 
 func init() {
 	initCmd.Flags().BoolP("interactive", "i", false, "Interactive mode")
+	initCmd.Flags().Bool("no-import", false, "Do not import debian/control, snapcraft.yaml or a Homebrew formula")
+	initCmd.Flags().Bool("offline", false, "Skip checking package name availability on registries")
+	initCmd.Flags().String("from-goreleaser", "", "Import settings from a goreleaser config (default: find .goreleaser.yaml)")
+	initCmd.Flags().Lookup("from-goreleaser").NoOptDefVal = "auto"
+	initCmd.Flags().String("with-make", "", "Generate build, pack, sign and publish targets (make or task)")
+	initCmd.Flags().Lookup("with-make").NoOptDefVal = initpkg.WorkflowMake
+
+	// Export command flags
+	snippetsCmd.Flags().String("update", "", "Replace the marked install section of this file instead of printing")
+
+	changelogUpdateCmd.Flags().Bool("no-commit", false, "Update the file without committing it")
+
+	exportCmd.Flags().String("format", "", "Export format: "+strings.Join(export.Formats, ", "))
+	exportCmd.Flags().String("arch", "", "Linux architecture to export for nfpm (default: primary binary)")
+	exportCmd.Flags().StringP("output", "o", "", "Write to file instead of stdout")
+	exportCmd.MarkFlagRequired("format")
 
 	validateCmd.Flags().BoolP("verbose", "v", false, "Show detailed validation information")
+	validateCmd.Flags().Bool("offline", false, "Skip checking package name availability on registries")
 
 	packCmd.Flags().Bool("all", false, "Create all package types")
 	packCmd.Flags().Bool("sign", false, "Sign binaries before packaging")
-	packCmd.Flags().Bool("brew", false, "Create Homebrew formula")
-	packCmd.Flags().Bool("scoop", false, "Create Scoop manifest")
-	packCmd.Flags().Bool("deb", false, "Create DEB package")
-	packCmd.Flags().Bool("rpm", false, "Create RPM package")
-	packCmd.Flags().Bool("chocolatey", false, "Create Chocolatey package")
-	packCmd.Flags().Bool("winget", false, "Create Winget manifests")
-	packCmd.Flags().Bool("snap", false, "Create Snap package")
-	packCmd.Flags().Bool("appimage", false, "Create AppImage")
-	packCmd.Flags().Bool("flatpak", false, "Create Flatpak manifest")
-	packCmd.Flags().Bool("npm", false, "Create npm package")
-	packCmd.Flags().Bool("pypi", false, "Create PyPI package")
-	packCmd.Flags().Bool("docker", false, "Create Docker files")
-	packCmd.Flags().Bool("apptainer", false, "Create Apptainer container")
-	packCmd.Flags().Bool("dmg", false, "Create macOS DMG installer")
-	packCmd.Flags().Bool("msi", false, "Create Windows MSI installer")
-	packCmd.Flags().Bool("msix", false, "Create Windows MSIX package")
-	packCmd.Flags().Bool("cargo", false, "Create Rust Cargo package")
-	packCmd.Flags().Bool("nix", false, "Create Nix package")
-	packCmd.Flags().Bool("spack", false, "Create Spack package")
-	packCmd.Flags().Bool("installer", false, "Create curl|bash installer")
+	packCmd.Flags().StringSlice("format", nil, "Formats to create by name, e.g. brew,scoop,deb (repeatable)")
+	for _, f := range packFormats {
+		packCmd.Flags().Bool(f.Name, false, f.Usage)
+	}
+	packCmd.Flags().String("report", "", "With --all, write the results as JSON to this file")
+	packCmd.Flags().Int("parallel", runtime.NumCPU(), "With --all, how many formats to build at once")
+	packCmd.Flags().Bool("strict", false, "With --all, fail formats whose build tools are missing and stop at the first failure")
+	packCmd.Flags().String("output", "table", "How to print the results: table or markdown")
+	packCmd.Flags().Bool("sbom", false, "Write SPDX and CycloneDX SBOMs and embed them in deb, rpm and docker (or set sbom.enabled)")
 
 	publishCmd.Flags().Bool("dry-run", false, "Show what would be done without executing")
 	publishCmd.Flags().Bool("skip-github", false, "Skip GitHub operations (release, tap, bucket)")
+	publishCmd.Flags().String("provider", "github", "Where to release: github or gitlab")
+	publishCmd.Flags().Bool("recursive", false, "Publish every bagboy project under the given directory (default .)")
+	publishCmd.Flags().Int("jobs", 4, "With --recursive, how many projects to publish at once")
+	publishCmd.Flags().String("at", "", "Stage the release now and publish it at this time (RFC 3339)")
+	publishCmd.Flags().Bool("workflow", false, "With --at, generate a GitHub Actions workflow that publishes at the scheduled time")
+	publishCmd.Flags().Bool("finalize", false, "Publish a release staged with --at")
+	publishCmd.Flags().Bool("skip-preflight", false, "Skip credential and access checks before packaging")
+	publishCmd.Flags().Bool("force", false, "Publish even if the version is not above the latest release or required checks have not passed")
+	publishCmd.Flags().String("report", "", "Write the packaging results as JSON to this file")
+	publishCmd.Flags().Int("parallel", runtime.NumCPU(), "How many formats to build at once")
+	publishCmd.Flags().Bool("strict", false, "Fail formats whose build tools are missing and stop at the first failure")
+	publishCmd.Flags().String("output", "table", "How to print the results: table or markdown")
+	publishCmd.Flags().Bool("sbom", false, "Write SPDX and CycloneDX SBOMs, embed them in deb, rpm and docker and attach them to the release (or set sbom.enabled)")
+	publishCmd.Flags().Bool("build-env", false, "Attach a manifest of the OS, tool versions and build environment variables to the release (or set build_env.enabled)")
 	
 	checkCmd.Flags().StringSlice("formats", []string{}, "Package formats to check (default: all)")
 	
 	deployCmd.Flags().StringSlice("targets", []string{}, "Deployment targets (brew,npm,docker,etc)")
 	deployCmd.Flags().Bool("dry-run", false, "Show deployment instructions without executing")
 	
+	keysGenerateCmd.Flags().String("tool", "gpg", "Key to generate: gpg, minisign, signify or ssh")
+
 	signCmd.Flags().Bool("check", false, "Check signing setup only")
 	signCmd.Flags().String("binary", "", "Path to binary to sign")
+	signCmd.Flags().Bool("notarize-status", false, "Show recorded notarization submissions and refresh pending ones")
+
+	deltaCmd.Flags().String("previous", "", "Directory containing the previous release packages")
+	deltaCmd.Flags().StringSlice("formats", []string{}, "Delta formats to generate (default: rpm,msi)")
+
+	diffCmd.Flags().Bool("json", false, "Output the comparison as JSON")
+	diffCmd.Flags().Bool("contents", false, "Also compare the files and dependencies inside packages")
+	diffCmd.Flags().Bool("local", false, "Compare the packages in dist/ against a release (implies --contents)")
+	diffCmd.Flags().Float64("max-growth", 0, "Fail when a package grew by more than this percentage")
+
+	policyCheckCmd.Flags().String("image", "", "Container image to scan for no_critical_cves")
+
+	sbomCmd.Flags().StringSlice("format", nil, "Formats to write: spdx, cyclonedx (default both, or sbom.formats)")
+
+	buildEnvCmd.Flags().String("output", "", "Write the manifest to this file instead of printing it")
+	buildEnvCmd.Flags().String("diff", "", "Compare this machine, or --against, with a manifest from another build")
+	buildEnvCmd.Flags().String("against", "", "Manifest to compare --diff with instead of this machine")
+	selfUpdateCmd.Flags().Bool("check", false, "Only report whether a newer release is available")
+	selfUpdateCmd.Flags().String("version", "", "Install this release instead of the latest, e.g. v0.8.0")
+	selfUpdateCmd.Flags().String("public-key", "", "signify public key to verify with, for builds without one")
+	selfUpdateCmd.Flags().Bool("force", false, "Update development builds and package-manager installs too")
+
+	attestCmd.Flags().String("output", "", "Bundle path (default dist/<name>-<version>.attestations.tar.gz)")
+	attestCmd.Flags().String("oci", "", "Also push the bundle to this OCI reference with oras")
+
+	attestVerifyCmd.Flags().String("bundle", "", "Attestation bundle to verify")
+	attestVerifyCmd.Flags().String("artifacts", "dist", "Directory with the packages to check against the bundle")
+	attestCmd.AddCommand(attestVerifyCmd)
+
+	backfillCmd.Flags().String("from", "", "Oldest release to backfill, e.g. v1.0.0")
+	backfillCmd.Flags().String("to", "", "Newest release to backfill, e.g. v1.9.0")
+	backfillCmd.Flags().StringSlice("formats", []string{"brew", "scoop", "winget", "nix"}, "Manifests to regenerate")
+	backfillCmd.Flags().Duration("delay", 5*time.Second, "Pause between releases")
+	backfillCmd.Flags().Int("reserve", 100, "Wait for the rate limit to reset below this many API requests")
+	backfillCmd.Flags().Bool("dry-run", false, "Generate the manifests without committing them")
+
+	promoteCmd.Flags().Bool("wait", false, "Poll until the release is published instead of failing")
+	promoteCmd.Flags().Duration("interval", 5*time.Minute, "How often to check the release with --wait")
+	promoteCmd.Flags().Bool("workflow", false, "Write a GitHub Actions workflow that promotes on release instead")
+
+	pruneCmd.Flags().Bool("dry-run", false, "Show what would be deleted without deleting")
+	pruneCmd.Flags().Int("draft-age", 0, "Delete drafts older than this many days (overrides github.prune.draft_max_age_days)")
+	pruneCmd.Flags().Int("nightly-age", 0, "Delete nightly assets older than this many days (overrides github.prune.nightly_max_age_days)")
+	pruneCmd.Flags().Bool("delete-tags", false, "Also delete git tags of pruned prereleases")
+	pruneCmd.Flags().BoolP("yes", "y", false, "Delete without asking for confirmation")
+	pruneImagesCmd.Flags().Bool("dry-run", false, "Show what would be deleted without deleting")
+	pruneImagesCmd.Flags().BoolP("yes", "y", false, "Delete without asking for confirmation")
+	pruneCmd.AddCommand(pruneImagesCmd)
+	policyCmd.AddCommand(policyCheckCmd)
+	keysCmd.AddCommand(keysGenerateCmd)
+	keysCmd.AddCommand(keysExportCmd)
+	keysCmd.AddCommand(keysPublishCmd)
+	keysCmd.AddCommand(keysRotateCmd)
+	changelogCmd.AddCommand(changelogUpdateCmd)
 
 	var benchmarkCmd = &cobra.Command{
 		Use:   "benchmark",
@@ -937,7 +3645,7 @@ Examples:
 			}
 
 			manager := deps.NewManager(cfg)
-			ctx := context.Background()
+			ctx := cmd.Context()
 			
 			ui.Header("Checking Dependencies")
 			
@@ -966,6 +3674,11 @@ Examples:
 			}
 			
 			table.Print()
+			for name, status := range results {
+				if status.Error != "" {
+					ui.Warning(fmt.Sprintf("%s: %s", name, status.Error))
+				}
+			}
 			
 			if allAvailable {
 				ui.Success("All dependencies are satisfied")
@@ -1027,6 +3740,12 @@ Examples:
 	var depsInstallCmd = &cobra.Command{
 		Use:   "install",
 		Short: "Install missing dependencies",
+		Long: `Install missing dependencies with the platform package manager.
+
+Tools under dependencies.tools are downloaded instead. Each download must
+match its pinned sha256, and its sigstore bundle when one is configured,
+before it is installed into ~/.bagboy/bin (or $BAGBOY_TOOLS_DIR). An
+installed tool that no longer matches its pin is downloaded again.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			configPath, err := config.FindConfigFile()
 			if err != nil {
@@ -1039,7 +3758,7 @@ Examples:
 			}
 
 			manager := deps.NewManager(cfg)
-			ctx := context.Background()
+			ctx := cmd.Context()
 			
 			ui.Header("Installing Dependencies")
 			
@@ -1068,6 +3787,9 @@ Examples:
 			}
 			
 			ui.Success("Dependencies installed successfully")
+			if len(cfg.Dependencies.Tools) > 0 {
+				ui.Info(fmt.Sprintf("Pinned tools are installed in %s; add it to PATH", deps.ToolsDir()))
+			}
 			return nil
 		},
 	}
@@ -1087,7 +3809,7 @@ Examples:
 			}
 
 			manager := deps.NewManager(cfg)
-			ctx := context.Background()
+			ctx := cmd.Context()
 			
 			ui.Header("Resolving Dependencies")
 			
@@ -1135,25 +3857,65 @@ Examples:
 		Aliases: []string{"v", "--version"},
 		Short:   "Show version information",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ui.PrintVersion("0.7.0-dev", "", "")
+			ui.PrintVersion(buildInfo())
 			return nil
 		},
 	}
 
+	rootCmd.PersistentFlags().Bool("a11y", false, "Screen-reader friendly output: no spinners, box drawing or emoji (or set BAGBOY_A11Y=1)")
+	rootCmd.PersistentFlags().String("lang", "", "Language for messages: "+strings.Join(i18n.Supported(), ", ")+" (default from LANG)")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Cancel the command after this long, e.g. 30m (default no limit)")
+
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(packCmd)
 	rootCmd.AddCommand(publishCmd)
+	rootCmd.AddCommand(exportCmd)
 	rootCmd.AddCommand(checkCmd)
 	rootCmd.AddCommand(deployCmd)
 	rootCmd.AddCommand(signCmd)
+	rootCmd.AddCommand(keysCmd)
 	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(deltaCmd)
+	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(promoteCmd)
+	rootCmd.AddCommand(backfillCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(policyCmd)
+	rootCmd.AddCommand(sbomCmd)
+	rootCmd.AddCommand(buildEnvCmd)
+	rootCmd.AddCommand(selfUpdateCmd)
+	rootCmd.AddCommand(attestCmd)
+	rootCmd.AddCommand(gomodCmd)
+	rootCmd.AddCommand(snippetsCmd)
+	rootCmd.AddCommand(changelogCmd)
 	rootCmd.AddCommand(benchmarkCmd)
 	rootCmd.AddCommand(depsCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	i18n.SetLanguage(i18n.Detect())
+	code := run()
+	ui.CloseAccessible()
+	os.Exit(code)
+}
+
+// run executes the command line and returns the exit status
+func run() int {
+	ctx, stop := interrupt.Context(context.Background())
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	interrupted := ctx.Err() != nil
+	stop()
+	if interrupted {
+		ui.Error(i18n.T("Interrupted"))
+		return interrupt.ExitCode
+	}
+	if err != nil && cmd.Context().Err() == context.DeadlineExceeded {
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		ui.Error(i18n.T("Timed out after %s", timeout))
+		return 1
+	}
+	if err != nil {
 		// Enhanced error handling with recovery suggestions
 		if bagboyErr, ok := err.(*errors.BagboyError); ok {
 			ui.Error(bagboyErr.Message)
@@ -1165,8 +3927,9 @@ func main() {
 			}
 		} else {
 			ui.Error(err.Error())
-			ui.Info("💡 Run 'bagboy --help' for usage information")
+			ui.Info("💡 " + i18n.T("Run 'bagboy --help' for usage information"))
 		}
-		os.Exit(1)
+		return 1
 	}
+	return 0
 }
@@ -2,12 +2,20 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/signing"
 	"github.com/spf13/cobra"
 )
 
@@ -399,3 +407,195 @@ func TestCommandAliases(t *testing.T) {
 		})
 	}
 }
+
+func TestPackFormatsMatchRegistry(t *testing.T) {
+	registry := newRegistry()
+	if len(packFormats) != registry.Count() {
+		t.Errorf("Expected a pack flag for each of the %d packagers, got %d", registry.Count(), len(packFormats))
+	}
+	for _, f := range packFormats {
+		if _, ok := registry.Get(f.Name); !ok {
+			t.Errorf("Pack flag --%s has no registered packager", f.Name)
+		}
+		if packCmd.Flags().Lookup(f.Name) == nil {
+			t.Errorf("Pack flag --%s is not registered", f.Name)
+		}
+	}
+}
+
+func TestSelectedFormats(t *testing.T) {
+	cmd := &cobra.Command{}
+	for _, f := range packFormats {
+		cmd.Flags().Bool(f.Name, false, f.Usage)
+	}
+	cmd.Flags().Set("deb", "true")
+	cmd.Flags().Set("brew", "true")
+
+	got := selectedFormats(cmd, []string{"scoop", "deb"})
+	want := []string{"scoop", "deb", "brew"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestPackResumeHint(t *testing.T) {
+	hint := packResumeHint([]string{"brew", "deb", "rpm"}, []string{"brew"})
+	if !strings.Contains(hint, "bagboy pack --format deb,rpm") {
+		t.Errorf("Expected the hint to name the remaining formats, got: %s", hint)
+	}
+	if hint := packResumeHint([]string{"brew"}, []string{"brew"}); hint != "" {
+		t.Errorf("Expected no hint when every format finished, got: %s", hint)
+	}
+}
+
+func TestPackParallelism(t *testing.T) {
+	cfg := &config.Config{Performance: config.PerformanceConfig{MaxParallelPackagers: 2}}
+	cmd := &cobra.Command{}
+	cmd.Flags().Int("parallel", runtime.NumCPU(), "")
+
+	if got := packParallelism(cmd, cfg); got != 2 {
+		t.Errorf("packParallelism() = %d, want performance.max_parallel_packagers", got)
+	}
+	cmd.Flags().Set("parallel", "5")
+	if got := packParallelism(cmd, cfg); got != 5 {
+		t.Errorf("packParallelism() = %d, want --parallel to win", got)
+	}
+}
+
+// redirectTransport sends every request to the test server
+type redirectTransport struct{ server *httptest.Server }
+
+func (r redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target, _ := url.Parse(r.server.URL)
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = target.Scheme, target.Host
+	return r.server.Client().Transport.RoundTrip(req)
+}
+
+func TestCheckVersionGuard(t *testing.T) {
+	releases := `[{"tag_name": "v1.2.0"}, {"tag_name": "v1.1.0"}, {"tag_name": "v2.0.0", "draft": true}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/testowner/norelease/releases" && r.URL.Path != "/repos/testowner/testrepo/releases" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "norelease") {
+			w.Write([]byte("[]"))
+			return
+		}
+		w.Write([]byte(releases))
+	}))
+	defer server.Close()
+
+	oldTransport := http.DefaultTransport
+	http.DefaultTransport = redirectTransport{server}
+	defer func() { http.DefaultTransport = oldTransport }()
+	t.Setenv("BAGBOY_NO_CACHE", "1")
+	t.Setenv("TEST_GITHUB_TOKEN", "test-token")
+
+	tests := []struct {
+		name    string
+		version string
+		repo    string
+		wantErr bool
+	}{
+		{"lower than the latest tag", "1.1.5", "testrepo", true},
+		{"equal to the latest tag", "1.2.0", "testrepo", true},
+		{"prerelease of the latest tag", "1.2.0-rc.1", "testrepo", true},
+		{"above the latest tag", "1.2.1", "testrepo", false},
+		{"above the latest tag ignoring drafts", "1.3.0-rc.1", "testrepo", false},
+		{"no tags", "0.1.0", "norelease", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Name:    "testapp",
+				Version: tt.version,
+				GitHub:  config.GitHubConfig{Owner: "testowner", Repo: tt.repo, TokenEnv: "TEST_GITHUB_TOKEN"},
+			}
+			err := checkVersionGuard(context.Background(), cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkVersionGuard(%s) = %v, wantErr %v", tt.version, err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "--force") {
+				t.Errorf("Expected the error to mention --force, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSignAndCheckRelease_RequireSigned(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+
+	testDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	key := filepath.Join(testDir, "id_ed25519")
+	if output, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", key).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen failed: %v\n%s", err, output)
+	}
+	os.MkdirAll("dist", 0755)
+	asset := filepath.Join("dist", "testapp_1.0.0_amd64.deb")
+	os.WriteFile(asset, []byte("package"), 0644)
+
+	cfg := &config.Config{Name: "testapp", Version: "1.0.0"}
+	cfg.Policy.RequireSigned = true
+	ctx := context.Background()
+
+	// Nothing signs the release, so the policy blocks it
+	if _, err := signAndCheckRelease(ctx, cfg, []string{asset}, ""); err == nil || !strings.Contains(err.Error(), "require_signed") {
+		t.Fatalf("Expected the unsigned release to be blocked, got %v", err)
+	}
+
+	// The signatures written while publishing satisfy it
+	cfg.Signing.SSH = config.SSHSigningConfig{Enabled: true, Key: key, Identity: "release@example.com"}
+	if _, err := signing.WriteAllowedSigners(ctx, cfg); err != nil {
+		t.Fatalf("WriteAllowedSigners failed: %v", err)
+	}
+	files, err := signAndCheckRelease(ctx, cfg, []string{asset}, "")
+	if err != nil {
+		t.Fatalf("signAndCheckRelease failed: %v", err)
+	}
+	checksums := filepath.Join("dist", signing.ChecksumsFile)
+	want := []string{checksums, checksums + ".sig", signing.AllowedSignersPath(cfg)}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("files = %v, want %v", files, want)
+	}
+}
+
+func TestDedupeAssets(t *testing.T) {
+	testDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	os.MkdirAll("dist", 0755)
+	webi := filepath.Join("dist", "testapp-linux-amd64")
+	copied := filepath.Join("dist", "testapp_linux_amd64")
+	deb := filepath.Join("dist", "testapp_1.0.0_amd64.deb")
+	os.WriteFile(webi, []byte("binary"), 0755)
+	os.WriteFile(copied, []byte("binary"), 0755)
+	os.WriteFile(deb, []byte("package"), 0644)
+
+	assets, aliases, err := dedupeAssets([]string{webi, copied, deb})
+	if err != nil {
+		t.Fatalf("dedupeAssets failed: %v", err)
+	}
+	want := []string{webi, deb, aliases}
+	if strings.Join(assets, ",") != strings.Join(want, ",") {
+		t.Errorf("assets = %v, want %v", assets, want)
+	}
+	if data, _ := os.ReadFile(aliases); string(data) != "testapp_linux_amd64  testapp-linux-amd64\n" {
+		t.Errorf("Unexpected aliases %q", data)
+	}
+
+	assets, aliases, _ = dedupeAssets([]string{webi, deb})
+	if len(assets) != 2 || aliases != "" {
+		t.Errorf("Expected distinct assets unchanged, got %v %q", assets, aliases)
+	}
+}
@@ -0,0 +1,405 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package attest exports and verifies per-release attestation bundles:
+// checksums, signatures, SBOMs, in-toto provenance and policy results in a
+// single tarball that can be checked offline.
+package attest
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/audit"
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/policy"
+)
+
+const (
+	// BundleMediaType identifies the bundle when pushed as an OCI artifact
+	BundleMediaType = "application/vnd.bagboy.attestation.bundle.v1.tar+gzip"
+
+	// ManifestVersion is recorded in manifest.json
+	ManifestVersion = "bagboy.attestation/v1"
+
+	StatementType  = "https://in-toto.io/Statement/v1"
+	ProvenanceType = "https://slsa.dev/provenance/v1"
+	BuildType      = "https://github.com/scttfrdmn/bagboy/pack@v1"
+)
+
+// Bundle file names
+const (
+	ManifestFile   = "manifest.json"
+	ChecksumsFile  = "checksums.txt"
+	ProvenanceFile = "provenance.intoto.json"
+	PolicyFile     = "policy.json"
+	signaturesDir  = "signatures"
+	sbomsDir       = "sboms"
+)
+
+// File kinds recorded in the manifest
+const (
+	KindChecksums  = "checksums"
+	KindProvenance = "provenance"
+	KindSignature  = "signature"
+	KindSBOM       = "sbom"
+	KindPolicy     = "policy"
+)
+
+var sbomSuffixes = []string{".spdx.json", ".spdx", ".cdx.json", ".cdx.xml", ".sbom.json"}
+
+// Subject is an in-toto subject
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Statement is an in-toto v1 statement carrying SLSA provenance
+type Statement struct {
+	Type          string     `json:"_type"`
+	Subject       []Subject  `json:"subject"`
+	PredicateType string     `json:"predicateType"`
+	Predicate     Provenance `json:"predicate"`
+}
+
+// Provenance is a SLSA v1 provenance predicate
+type Provenance struct {
+	BuildDefinition struct {
+		BuildType          string            `json:"buildType"`
+		ExternalParameters map[string]string `json:"externalParameters"`
+	} `json:"buildDefinition"`
+	RunDetails struct {
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+		Metadata struct {
+			InvocationID string    `json:"invocationId,omitempty"`
+			StartedOn    time.Time `json:"startedOn"`
+		} `json:"metadata"`
+	} `json:"runDetails"`
+}
+
+// FileEntry is one file in the bundle
+type FileEntry struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest indexes the bundle contents
+type Manifest struct {
+	Version string      `json:"version"`
+	Name    string      `json:"name"`
+	Release string      `json:"release"`
+	Created time.Time   `json:"created"`
+	Files   []FileEntry `json:"files"`
+}
+
+// bundleSuffix marks bundles so earlier ones are not attested as artifacts
+const bundleSuffix = ".attestations.tar.gz"
+
+// DefaultBundlePath is where attest writes the bundle by default
+func DefaultBundlePath(cfg *config.Config) string {
+	return filepath.Join("dist", fmt.Sprintf("%s-%s%s", cfg.Name, cfg.Version, bundleSuffix))
+}
+
+// IsSignature reports whether path is a detached signature
+func IsSignature(path string) bool {
+	return hasSuffix(path, policy.SignatureSuffixes)
+}
+
+// IsSBOM reports whether path is a software bill of materials
+func IsSBOM(path string) bool {
+	return hasSuffix(path, sbomSuffixes)
+}
+
+// Export writes the attestation bundle for artifacts to output. Signatures
+// and SBOMs are taken from the artifact list and from sidecar files next
+// to each artifact. Policy results are included when not nil.
+func Export(cfg *config.Config, artifacts []string, results []policy.Result, output string, now time.Time) error {
+	files := make(map[string][]byte)
+	kinds := make(map[string]string)
+
+	add := func(path, kind string, data []byte) {
+		files[path] = data
+		kinds[path] = kind
+	}
+
+	var subjects []Subject
+	var checksums strings.Builder
+	for _, artifact := range artifacts {
+		if IsSignature(artifact) || IsSBOM(artifact) || strings.HasSuffix(artifact, bundleSuffix) || artifact == output {
+			continue
+		}
+		info, err := os.Stat(artifact)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		sum, err := fileSHA256(artifact)
+		if err != nil {
+			return err
+		}
+		name := filepath.Base(artifact)
+		subjects = append(subjects, Subject{Name: name, Digest: map[string]string{"sha256": sum}})
+		fmt.Fprintf(&checksums, "%s  %s\n", sum, name)
+	}
+	if len(subjects) == 0 {
+		return fmt.Errorf("no artifacts to attest")
+	}
+	add(ChecksumsFile, KindChecksums, []byte(checksums.String()))
+
+	statement, err := json.MarshalIndent(NewStatement(cfg, subjects, now), "", "  ")
+	if err != nil {
+		return err
+	}
+	add(ProvenanceFile, KindProvenance, statement)
+
+	for _, path := range sidecars(artifacts) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if IsSBOM(path) {
+			add(sbomsDir+"/"+filepath.Base(path), KindSBOM, data)
+		} else {
+			add(signaturesDir+"/"+filepath.Base(path), KindSignature, data)
+		}
+	}
+
+	if results != nil {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		add(PolicyFile, KindPolicy, data)
+	}
+
+	manifest := Manifest{Version: ManifestVersion, Name: cfg.Name, Release: cfg.Version, Created: now.UTC()}
+	for _, path := range sortedPaths(files) {
+		manifest.Files = append(manifest.Files, FileEntry{Path: path, Kind: kinds[path], SHA256: sha256Hex(files[path])})
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	files[ManifestFile] = manifestData
+
+	return writeTarGz(output, files, now)
+}
+
+// Push uploads a bundle to an OCI registry as a single-layer artifact
+// using oras
+func Push(ctx context.Context, bundlePath, ref string) error {
+	if _, err := exec.LookPath("oras"); err != nil {
+		return fmt.Errorf("oras not found - install from https://oras.land to push bundles to a registry")
+	}
+
+	// oras records the layer under the path it was given, so push from the
+	// bundle's directory to keep the name portable
+	cmd := exec.CommandContext(ctx, "oras", "push", ref,
+		"--artifact-type", BundleMediaType,
+		filepath.Base(bundlePath)+":"+BundleMediaType)
+	cmd.Dir = filepath.Dir(bundlePath)
+
+	output, err := cmd.CombinedOutput()
+	audit.Result(ctx, audit.ImagePush, ref, filepath.Base(bundlePath), "", err)
+	if err != nil {
+		return fmt.Errorf("oras push failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+// NewStatement builds the in-toto provenance statement for subjects. When
+// run in GitHub Actions the workflow run is recorded as the builder.
+func NewStatement(cfg *config.Config, subjects []Subject, now time.Time) Statement {
+	statement := Statement{Type: StatementType, Subject: subjects, PredicateType: ProvenanceType}
+
+	predicate := &statement.Predicate
+	predicate.BuildDefinition.BuildType = BuildType
+	predicate.BuildDefinition.ExternalParameters = map[string]string{
+		"name":    cfg.Name,
+		"version": cfg.Version,
+	}
+	if cfg.GitHub.Owner != "" && cfg.GitHub.Repo != "" {
+		predicate.BuildDefinition.ExternalParameters["repository"] = fmt.Sprintf("https://github.com/%s/%s", cfg.GitHub.Owner, cfg.GitHub.Repo)
+	}
+
+	predicate.RunDetails.Builder.ID = "bagboy/local"
+	if repo, run := os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID"); repo != "" && run != "" {
+		predicate.RunDetails.Builder.ID = fmt.Sprintf("https://github.com/%s/actions/runs/%s", repo, run)
+		predicate.RunDetails.Metadata.InvocationID = run
+	}
+	predicate.RunDetails.Metadata.StartedOn = now.UTC()
+
+	return statement
+}
+
+// sidecars returns signature and SBOM files given directly or found next
+// to an artifact
+func sidecars(artifacts []string) []string {
+	seen := make(map[string]bool)
+	var found []string
+
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			found = append(found, path)
+		}
+	}
+
+	for _, artifact := range artifacts {
+		if IsSignature(artifact) || IsSBOM(artifact) {
+			add(artifact)
+			continue
+		}
+		for _, suffix := range append(append([]string{}, policy.SignatureSuffixes...), sbomSuffixes...) {
+			if _, err := os.Stat(artifact + suffix); err == nil {
+				add(artifact + suffix)
+			}
+		}
+	}
+
+	sort.Strings(found)
+	return found
+}
+
+func writeTarGz(output string, files map[string][]byte, now time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	// manifest.json first so readers can stream the index
+	paths := append([]string{ManifestFile}, sortedPaths(files)...)
+	written := make(map[string]bool)
+	for _, path := range paths {
+		if written[path] {
+			continue
+		}
+		written[path] = true
+
+		data := files[path]
+		header := &tar.Header{Name: path, Mode: 0644, Size: int64(len(data)), ModTime: now.UTC()}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// readBundle loads every file of a bundle into memory
+func readBundle(path string) (map[string][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("not a bundle: %w", err)
+	}
+	defer gr.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[header.Name] = data
+	}
+	return files, nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func sortedPaths(files map[string][]byte) []string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func hasSuffix(path string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
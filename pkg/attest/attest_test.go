@@ -0,0 +1,129 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attest
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/policy"
+)
+
+func writeArtifacts(t *testing.T, dir string) {
+	t.Helper()
+	files := map[string]string{
+		"myapp_1.0.0_amd64.deb":           "deb contents",
+		"myapp-1.0.0.msi":                 "msi contents",
+		"myapp-1.0.0.msi.sigstore.bundle": "{}",
+		"myapp_1.0.0_amd64.deb.spdx.json": `{"spdxVersion":"SPDX-2.3"}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestExportAndVerify(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "")
+	t.Setenv("GITHUB_RUN_ID", "")
+	dir := t.TempDir()
+	writeArtifacts(t, dir)
+
+	artifacts := []string{filepath.Join(dir, "myapp_1.0.0_amd64.deb"), filepath.Join(dir, "myapp-1.0.0.msi")}
+	cfg := &config.Config{Name: "myapp", Version: "1.0.0"}
+	results := []policy.Result{{Rule: "require_signed", Passed: true}}
+	bundle := filepath.Join(dir, "bundle.tar.gz")
+
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	if err := Export(cfg, artifacts, results, bundle, now); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	files, err := readBundle(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{ManifestFile, ChecksumsFile, ProvenanceFile, PolicyFile,
+		"signatures/myapp-1.0.0.msi.sigstore.bundle", "sboms/myapp_1.0.0_amd64.deb.spdx.json"} {
+		if _, ok := files[path]; !ok {
+			t.Errorf("Bundle missing %s", path)
+		}
+	}
+
+	var statement Statement
+	if err := json.Unmarshal(files[ProvenanceFile], &statement); err != nil {
+		t.Fatal(err)
+	}
+	if len(statement.Subject) != 2 || statement.Predicate.RunDetails.Builder.ID != "bagboy/local" {
+		t.Errorf("Unexpected statement: %+v", statement)
+	}
+
+	report, err := Verify(context.Background(), bundle, dir)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !report.Passed() {
+		t.Errorf("Expected bundle to verify, got %+v", report.Checks)
+	}
+
+	// A modified artifact no longer matches the provenance
+	if err := os.WriteFile(artifacts[1], []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report, err = Verify(context.Background(), bundle, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Passed() {
+		t.Error("Expected tampered artifact to fail verification")
+	}
+}
+
+func TestVerify_FailedPolicy(t *testing.T) {
+	dir := t.TempDir()
+	artifact := filepath.Join(dir, "myapp-1.0.0.msi")
+	if err := os.WriteFile(artifact, []byte("msi"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle := filepath.Join(dir, "bundle.tar.gz")
+	results := []policy.Result{{Rule: "version_increase", Passed: false, Message: "not greater"}}
+	if err := Export(&config.Config{Name: "myapp", Version: "1.0.0"}, []string{artifact}, results, bundle, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Verify(context.Background(), bundle, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Passed() {
+		t.Error("Expected failed policy results to fail verification")
+	}
+}
+
+func TestExport_NoArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	if err := Export(&config.Config{Name: "myapp"}, nil, nil, filepath.Join(dir, "b.tar.gz"), time.Now()); err == nil {
+		t.Error("Expected error without artifacts")
+	}
+}
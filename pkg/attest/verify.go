@@ -0,0 +1,240 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/policy"
+)
+
+// Check statuses
+const (
+	StatusPass = "pass"
+	StatusFail = "fail"
+	StatusSkip = "skip"
+)
+
+// Check is one verification step
+type Check struct {
+	Name    string
+	Status  string
+	Message string
+}
+
+// Report is the result of verifying a bundle
+type Report struct {
+	Manifest Manifest
+	Checks   []Check
+}
+
+// Passed reports whether no check failed
+func (r *Report) Passed() bool {
+	for _, check := range r.Checks {
+		if check.Status == StatusFail {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Report) add(name, status, format string, args ...interface{}) {
+	r.Checks = append(r.Checks, Check{Name: name, Status: status, Message: fmt.Sprintf(format, args...)})
+}
+
+// Verify checks a bundle without network access: the manifest digests,
+// that provenance subjects match the checksums, the recorded policy
+// results, and, for artifacts present in artifactsDir, their digests and
+// GPG signatures. An empty artifactsDir skips the artifact checks.
+func Verify(ctx context.Context, bundlePath, artifactsDir string) (*Report, error) {
+	files, err := readBundle(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+
+	manifestData, ok := files[ManifestFile]
+	if !ok {
+		return nil, fmt.Errorf("bundle has no %s", ManifestFile)
+	}
+	if err := json.Unmarshal(manifestData, &report.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ManifestFile, err)
+	}
+
+	// Every file listed in the manifest is present and unmodified, and
+	// nothing was added
+	listed := make(map[string]bool)
+	for _, entry := range report.Manifest.Files {
+		listed[entry.Path] = true
+		data, ok := files[entry.Path]
+		switch {
+		case !ok:
+			report.add(entry.Path, StatusFail, "listed in manifest but missing")
+		case sha256Hex(data) != entry.SHA256:
+			report.add(entry.Path, StatusFail, "digest does not match manifest")
+		}
+	}
+	for path := range files {
+		if path != ManifestFile && !listed[path] {
+			report.add(path, StatusFail, "not listed in manifest")
+		}
+	}
+	if report.Passed() {
+		report.add("manifest", StatusPass, "%d files match their digests", len(report.Manifest.Files))
+	}
+
+	// Provenance subjects agree with checksums.txt
+	var statement Statement
+	if err := json.Unmarshal(files[ProvenanceFile], &statement); err != nil {
+		report.add("provenance", StatusFail, "invalid statement: %v", err)
+		return report, nil
+	}
+	if statement.Type != StatementType || statement.PredicateType != ProvenanceType {
+		report.add("provenance", StatusFail, "unexpected statement type %s / %s", statement.Type, statement.PredicateType)
+	}
+
+	checksums := parseChecksums(string(files[ChecksumsFile]))
+	mismatched := 0
+	for _, subject := range statement.Subject {
+		if checksums[subject.Name] != subject.Digest["sha256"] {
+			report.add(subject.Name, StatusFail, "provenance digest does not match checksums.txt")
+			mismatched++
+		}
+	}
+	if len(statement.Subject) != len(checksums) {
+		report.add("provenance", StatusFail, "%d subjects but %d checksums", len(statement.Subject), len(checksums))
+	} else if mismatched == 0 {
+		report.add("provenance", StatusPass, "%d subjects built by %s", len(statement.Subject), statement.Predicate.RunDetails.Builder.ID)
+	}
+
+	// Recorded policy results
+	if data, ok := files[PolicyFile]; ok {
+		var results []policy.Result
+		if err := json.Unmarshal(data, &results); err != nil {
+			report.add("policy", StatusFail, "invalid policy results: %v", err)
+		} else if failed := policy.Failed(results); len(failed) > 0 {
+			for _, result := range failed {
+				report.add("policy", StatusFail, "%s: %s", result.Rule, result.Message)
+			}
+		} else {
+			report.add("policy", StatusPass, "%d rules passed", len(results))
+		}
+	} else {
+		report.add("policy", StatusSkip, "no policy results recorded")
+	}
+
+	if artifactsDir == "" {
+		return report, nil
+	}
+
+	// Local artifacts match the attested digests
+	for _, subject := range statement.Subject {
+		path := filepath.Join(artifactsDir, subject.Name)
+		sum, err := fileSHA256(path)
+		switch {
+		case os.IsNotExist(err):
+			report.add(subject.Name, StatusSkip, "not present in %s", artifactsDir)
+		case err != nil:
+			report.add(subject.Name, StatusFail, "%v", err)
+		case sum != subject.Digest["sha256"]:
+			report.add(subject.Name, StatusFail, "digest does not match provenance")
+		default:
+			report.add(subject.Name, StatusPass, "sha256 %s", sum[:12])
+		}
+	}
+
+	verifySignatures(ctx, report, files, artifactsDir)
+	return report, nil
+}
+
+// verifySignatures checks armored or binary GPG signatures against the
+// local artifacts with the keys in the caller's keyring. Other signature
+// types need online verification and are only reported as present.
+func verifySignatures(ctx context.Context, report *Report, files map[string][]byte, artifactsDir string) {
+	_, gpgErr := exec.LookPath("gpg")
+
+	for _, entry := range report.Manifest.Files {
+		if entry.Kind != KindSignature {
+			continue
+		}
+
+		name := filepath.Base(entry.Path)
+		ext := filepath.Ext(name)
+		artifact := filepath.Join(artifactsDir, strings.TrimSuffix(name, ext))
+
+		if ext != ".asc" && ext != ".sig" {
+			report.add(name, StatusSkip, "signature present; verify online")
+			continue
+		}
+		if _, err := os.Stat(artifact); err != nil {
+			report.add(name, StatusSkip, "signed artifact not present")
+			continue
+		}
+		if ext == ".sig" && bytes.HasPrefix(files[entry.Path], []byte("untrusted comment:")) {
+			report.add(name, StatusSkip, "minisign/signify signature; verify with the release public key")
+			continue
+		}
+		if ext == ".sig" && bytes.HasPrefix(files[entry.Path], []byte("-----BEGIN SSH SIGNATURE-----")) {
+			report.add(name, StatusSkip, "SSH signature; verify with ssh-keygen -Y verify and allowed_signers")
+			continue
+		}
+		if gpgErr != nil {
+			report.add(name, StatusSkip, "gpg not found")
+			continue
+		}
+
+		sigFile, err := os.CreateTemp("", "bagboy-sig-*"+ext)
+		if err != nil {
+			report.add(name, StatusFail, "%v", err)
+			continue
+		}
+		sigFile.Write(files[entry.Path])
+		sigFile.Close()
+
+		output, err := exec.CommandContext(ctx, "gpg", "--batch", "--verify", sigFile.Name(), artifact).CombinedOutput()
+		os.Remove(sigFile.Name())
+		if err != nil {
+			report.add(name, StatusFail, "gpg verification failed: %s", strings.TrimSpace(lastLine(string(output))))
+			continue
+		}
+		report.add(name, StatusPass, "gpg signature valid")
+	}
+}
+
+func parseChecksums(content string) map[string]string {
+	sums := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			sums[fields[1]] = fields[0]
+		}
+	}
+	return sums
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return lines[len(lines)-1]
+}
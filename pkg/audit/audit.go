@@ -0,0 +1,235 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit appends a record of every change bagboy makes to a remote
+// service to a local JSON Lines file, and optionally a webhook, so a release
+// can be reconstructed after an incident
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// DefaultPath is where events are appended unless audit.path is set
+const DefaultPath = ".bagboy/audit.jsonl"
+
+// Actions recorded
+const (
+	ReleaseCreate  = "release.create"
+	ReleasePublish = "release.publish"
+	ReleaseDelete  = "release.delete"
+	AssetUpload    = "asset.upload"
+	AssetDelete    = "asset.delete"
+	FileCommit     = "file.commit"
+	BranchCreate   = "branch.create"
+	BranchMerge    = "branch.merge"
+	BranchDelete   = "branch.delete"
+	TagPush        = "tag.push"
+	TagDelete      = "tag.delete"
+	PROpen         = "pr.open"
+	PRReady        = "pr.ready"
+	PRMerge        = "pr.merge"
+	RepoCreate     = "repo.create"
+	RepoFork       = "repo.fork"
+	RepoTopic      = "repo.topic"
+	ImagePush      = "image.push"
+	ImageDelete    = "image.delete"
+	PackagePublish = "package.publish"
+	ObjectUpload   = "object.upload"
+	KeyPublish     = "key.publish"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a webhook body, as
+// sha256=<hex>, when audit.webhook_secret_env is set
+const SignatureHeader = "X-Bagboy-Signature-256"
+
+// Event is one remote mutation
+type Event struct {
+	Time      time.Time `json:"time"`
+	Run       string    `json:"run"`              // shared by every event of one bagboy invocation
+	Actor     string    `json:"actor,omitempty"`  // CI or local user that ran bagboy
+	Action    string    `json:"action"`           // one of the action constants
+	Target    string    `json:"target"`           // what was changed, e.g. owner/repo@v1.2.0 or a URL
+	Detail    string    `json:"detail,omitempty"` // e.g. the asset name, commit SHA or image digest
+	RequestID string    `json:"request_id,omitempty"`
+	Error     string    `json:"error,omitempty"` // set when the attempt failed; it may still have partly applied
+}
+
+var (
+	mu      sync.Mutex
+	path    string
+	webhook string
+	secret  string
+	run     string
+	client  = &http.Client{Timeout: 10 * time.Second}
+)
+
+// Configure turns recording on or off for the rest of the run
+func Configure(cfg config.AuditConfig) error {
+	mu.Lock()
+	defer mu.Unlock()
+	path, webhook, secret = "", "", ""
+	if !cfg.Enabled {
+		return nil
+	}
+
+	path = cfg.Path
+	if path == "" {
+		path = DefaultPath
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	webhook = cfg.Webhook
+	if cfg.WebhookSecretEnv != "" {
+		secret = os.Getenv(cfg.WebhookSecretEnv)
+		if secret == "" {
+			return fmt.Errorf("audit.webhook_secret_env: %s is not set", cfg.WebhookSecretEnv)
+		}
+	}
+	if run == "" {
+		run = newRunID()
+	}
+	return nil
+}
+
+// Enabled reports whether events are being recorded
+func Enabled() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return path != ""
+}
+
+// Record appends e to the log and sends it to the webhook. Recording never
+// fails the release; problems are printed as warnings.
+func Record(ctx context.Context, e Event) {
+	mu.Lock()
+	defer mu.Unlock()
+	if path == "" {
+		return
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	e.Run = run
+	if e.Actor == "" {
+		e.Actor = actor()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to encode audit event: %v\n", err)
+		return
+	}
+
+	if err := appendLine(path, data); err != nil {
+		fmt.Printf("⚠️  Failed to write audit log %s: %v\n", path, err)
+	}
+	if webhook != "" {
+		if err := post(context.WithoutCancel(ctx), data); err != nil {
+			fmt.Printf("⚠️  Failed to send audit event to webhook: %v\n", err)
+		}
+	}
+}
+
+// Result records action on target, with the error from attempting it
+func Result(ctx context.Context, action, target, detail, requestID string, err error) {
+	e := Event{Action: action, Target: target, Detail: detail, RequestID: requestID}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	Record(ctx, e)
+}
+
+// RequestID returns the ID a service assigned to a request, from whichever
+// response header it uses
+func RequestID(h http.Header) string {
+	for _, key := range []string{"X-GitHub-Request-Id", "X-Request-Id", "X-Amz-Request-Id"} {
+		if id := h.Get(key); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+func appendLine(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func post(ctx context.Context, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(data, secret))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", webhook, resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the signature header value for body, for receivers to
+// compare against
+func Sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func actor() string {
+	for _, key := range []string{"GITHUB_ACTOR", "GITLAB_USER_LOGIN", "USER", "USERNAME"} {
+		if name := os.Getenv(key); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+func newRunID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
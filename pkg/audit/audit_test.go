@@ -0,0 +1,147 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestRecord(t *testing.T) {
+	var received []Event
+	var signatures []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var e Event
+		if err := json.Unmarshal(body, &e); err != nil {
+			t.Errorf("Webhook body is not an event: %v", err)
+		}
+		received = append(received, e)
+		if got := r.Header.Get(SignatureHeader); got != Sign(body, "s3cret") {
+			signatures = append(signatures, got)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("AUDIT_SECRET", "s3cret")
+	t.Setenv("GITHUB_ACTOR", "octocat")
+	path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+	err := Configure(config.AuditConfig{Enabled: true, Path: path, Webhook: server.URL, WebhookSecretEnv: "AUDIT_SECRET"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer Configure(config.AuditConfig{})
+
+	ctx := context.Background()
+	Result(ctx, ReleaseCreate, "owner/app@v1.2.3", "https://github.com/owner/app/releases/tag/v1.2.3", "ABCD:1234", nil)
+	Result(ctx, AssetUpload, "owner/app@v1.2.3", "app.deb", "", errors.New("502 Bad Gateway"))
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var logged []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("Line %q is not an event: %v", scanner.Text(), err)
+		}
+		logged = append(logged, e)
+	}
+
+	if len(logged) != 2 || len(received) != 2 {
+		t.Fatalf("Logged %d and sent %d events, want 2 each", len(logged), len(received))
+	}
+	if len(signatures) > 0 {
+		t.Errorf("Webhook signatures %q do not match the body", signatures)
+	}
+	first, second := logged[0], logged[1]
+	if first.Action != ReleaseCreate || first.RequestID != "ABCD:1234" || first.Error != "" || first.Actor != "octocat" {
+		t.Errorf("Unexpected first event %+v", first)
+	}
+	if second.Action != AssetUpload || second.Error != "502 Bad Gateway" {
+		t.Errorf("Unexpected second event %+v", second)
+	}
+	if first.Run == "" || first.Run != second.Run {
+		t.Errorf("Events should share a run ID, got %q and %q", first.Run, second.Run)
+	}
+	if first.Time.IsZero() {
+		t.Error("Event time not set")
+	}
+
+	// Recording appends rather than truncating
+	Result(ctx, FileCommit, "owner/homebrew-tap:Formula/app.rb", "", "", nil)
+	data, _ := os.ReadFile(path)
+	if lines := bytes.Count(data, []byte("\n")); lines != 3 {
+		t.Errorf("Log has %d lines after a third event, want 3", lines)
+	}
+}
+
+func TestRecord_Disabled(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := Configure(config.AuditConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	Result(context.Background(), ReleaseCreate, "owner/app@v1.2.3", "", "", nil)
+	if _, err := os.Stat(filepath.Join(dir, DefaultPath)); !os.IsNotExist(err) {
+		t.Errorf("Disabled audit log should not be written, stat returned %v", err)
+	}
+	if Enabled() {
+		t.Error("Enabled() = true without audit.enabled")
+	}
+}
+
+func TestConfigure_MissingSecret(t *testing.T) {
+	t.Setenv("AUDIT_SECRET", "")
+	err := Configure(config.AuditConfig{Enabled: true, Path: filepath.Join(t.TempDir(), "audit.jsonl"), WebhookSecretEnv: "AUDIT_SECRET"})
+	if err == nil {
+		t.Error("Expected an error when the webhook secret variable is unset")
+	}
+	Configure(config.AuditConfig{})
+}
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		header http.Header
+		want   string
+	}{
+		{http.Header{"X-Github-Request-Id": {"C0DE:1"}}, "C0DE:1"},
+		{http.Header{"X-Request-Id": {"01HX"}}, "01HX"},
+		{http.Header{"X-Amz-Request-Id": {"4442587FB7D0A2F9"}}, "4442587FB7D0A2F9"},
+		{http.Header{}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := RequestID(tt.header); got != tt.want {
+			t.Errorf("RequestID(%v) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
@@ -77,7 +77,6 @@ func (p *PerformanceProfiler) RecordMetric(name string, value interface{}) {
 // OptimizedPackageRegistry provides optimized parallel packaging
 type OptimizedPackageRegistry struct {
 	packagers   map[string]packager.Packager
-	workerPool  chan struct{}
 	maxWorkers  int
 }
 
@@ -89,105 +88,50 @@ func NewOptimizedPackageRegistry(maxWorkers int) *OptimizedPackageRegistry {
 	
 	return &OptimizedPackageRegistry{
 		packagers:  make(map[string]packager.Packager),
-		workerPool: make(chan struct{}, maxWorkers),
 		maxWorkers: maxWorkers,
 	}
 }
 
+// NewOptimizedPackageRegistryFromConfig creates a registry limited to
+// performance.max_parallel_packagers workers
+func NewOptimizedPackageRegistryFromConfig(cfg *config.Config) *OptimizedPackageRegistry {
+	return NewOptimizedPackageRegistry(cfg.Performance.MaxParallelPackagers)
+}
+
 // Register adds a packager to the registry
 func (r *OptimizedPackageRegistry) Register(p packager.Packager) {
 	r.packagers[p.Name()] = p
 }
 
-// PackAllOptimized packages using optimized parallel processing
+// PackAllOptimized packages on a packager.Registry limited to maxWorkers
+// formats at once and prints how long it took. It returns the primary
+// output of each format built, and every failure.
 func (r *OptimizedPackageRegistry) PackAllOptimized(ctx context.Context, cfg *config.Config) (map[string]string, error) {
 	profiler := NewPerformanceProfiler()
 	profiler.Start()
-	
-	results := make(map[string]string)
-	errors := make(chan error, len(r.packagers))
-	resultsChan := make(chan struct {
-		name string
-		path string
-	}, len(r.packagers))
-	
-	var wg sync.WaitGroup
-	
-	// Launch workers
-	for name, pkg := range r.packagers {
-		wg.Add(1)
-		go func(name string, pkg packager.Packager) {
-			defer wg.Done()
-			
-			// Acquire worker slot
-			r.workerPool <- struct{}{}
-			defer func() { <-r.workerPool }()
-			
-			// Validate before packing
-			if err := pkg.Validate(cfg); err != nil {
-				errors <- fmt.Errorf("validation failed for %s: %w", name, err)
-				return
-			}
-			
-			// Pack with timeout
-			packCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
-			defer cancel()
-			
-			path, err := pkg.Pack(packCtx, cfg)
-			if err != nil {
-				errors <- fmt.Errorf("packing failed for %s: %w", name, err)
-				return
-			}
-			
-			resultsChan <- struct {
-				name string
-				path string
-			}{name, path}
-		}(name, pkg)
+
+	registry := packager.NewRegistry()
+	registry.SetParallelism(r.maxWorkers)
+	for _, pkg := range r.packagers {
+		registry.Register(pkg)
 	}
-	
-	// Wait for completion
-	go func() {
-		wg.Wait()
-		close(resultsChan)
-		close(errors)
-	}()
-	
-	// Collect results
-	var firstError error
-	for {
-		select {
-		case result, ok := <-resultsChan:
-			if !ok {
-				resultsChan = nil
-			} else {
-				results[result.name] = result.path
-			}
-		case err, ok := <-errors:
-			if !ok {
-				errors = nil
-			} else if firstError == nil {
-				firstError = err
-			}
-		}
-		
-		if resultsChan == nil && errors == nil {
-			break
+	packed, err := registry.PackAll(ctx, cfg)
+
+	results := make(map[string]string)
+	for _, result := range packed {
+		if result.Status == packager.StatusSuccess {
+			results[result.Format] = result.Path
 		}
 	}
-	
+
 	metrics := profiler.Stop()
 	fmt.Printf("📊 Performance Metrics:\n")
 	fmt.Printf("   Duration: %.2f seconds\n", metrics["duration_seconds"])
 	fmt.Printf("   Memory: %.2f MB allocated\n", metrics["memory_alloc_mb"])
 	fmt.Printf("   Workers: %d\n", r.maxWorkers)
 	fmt.Printf("   Packages: %d\n", len(results))
-	
-	if firstError != nil {
-		return results, firstError
-	}
-	
-	return results, nil
+
+	return results, err
 }
 
 // BenchmarkResult represents benchmark results
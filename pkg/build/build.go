@@ -0,0 +1,199 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package build cross-compiles the project's Go binaries from the builds:
+// section, injecting version metadata with -ldflags, so bagboy can package
+// straight from source instead of binaries built beforehand.
+package build
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+)
+
+// DefaultLdflags strip the binary and set the version variables most Go
+// CLIs read
+var DefaultLdflags = []string{"-s -w -X main.version={{.Version}} -X main.commit={{.Commit}} -X main.date={{.Date}}"}
+
+// Artifact is a binary built for one platform
+type Artifact struct {
+	Platform string
+	Binary   string
+}
+
+// Metadata is what ldflags templates can use
+type Metadata struct {
+	Name    string
+	Version string
+	Commit  string // full commit hash, or none outside a git checkout
+	Date    string // RFC 3339 build time, from SOURCE_DATE_EPOCH when set
+	Os      string
+	Arch    string
+
+	// Versions of the projects the build embeds, keyed by name
+	Versions map[string]string
+}
+
+// Enabled reports whether cfg builds its binaries
+func Enabled(cfg *config.Config) bool {
+	return len(cfg.Builds) > 0
+}
+
+// Run builds every target of every build into outDir/<platform>/ and
+// returns the binaries in order. Targets already listed in binaries are
+// not built.
+func Run(ctx context.Context, cfg *config.Config, outDir string) ([]Artifact, error) {
+	meta := Metadata{Name: cfg.Name, Version: cfg.Version, Commit: Commit(ctx), Date: date()}
+
+	var artifacts []Artifact
+	for i, b := range cfg.Builds {
+		versions, err := EmbeddedVersions(b, ".")
+		if err != nil {
+			return nil, fmt.Errorf("builds[%d]: %w", i, err)
+		}
+		meta.Versions = versions
+		for _, platform := range b.Targets() {
+			if _, ok := cfg.Binaries[platform]; ok {
+				continue
+			}
+			goos, goarch, _ := strings.Cut(platform, "-")
+			meta.Os, meta.Arch = goos, goarch
+
+			name := b.Binary
+			if name == "" {
+				name = cfg.Name
+			}
+			if goos == "windows" && !strings.HasSuffix(name, ".exe") {
+				name += ".exe"
+			}
+			binary := filepath.Join(outDir, platform, name)
+			output, err := paths.Tool(binary)
+			if err != nil {
+				return nil, err
+			}
+			args, err := Args(b, meta, output)
+			if err != nil {
+				return nil, fmt.Errorf("builds[%d]: %w", i, err)
+			}
+
+			cmd := exec.CommandContext(ctx, "go", args...)
+			cmd.Dir = b.Dir
+			cmd.Env = append(append(os.Environ(), b.Env...), "GOOS="+goos, "GOARCH="+goarch)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				return nil, fmt.Errorf("builds[%d]: go build for %s failed: %w\nOutput: %s", i, platform, err, out)
+			}
+			artifacts = append(artifacts, Artifact{Platform: platform, Binary: binary})
+		}
+	}
+	return artifacts, nil
+}
+
+// EmbeddedVersions loads the config of every project b embeds, relative
+// to dir, and returns their versions keyed by name
+func EmbeddedVersions(b config.BuildConfig, dir string) (map[string]string, error) {
+	versions := make(map[string]string, len(b.Embeds))
+	for _, embed := range b.Embeds {
+		if !filepath.IsAbs(embed) {
+			embed = filepath.Join(dir, embed)
+		}
+		path, err := config.FindConfigIn(embed)
+		if err != nil {
+			return nil, fmt.Errorf("embeds %s: %w", embed, err)
+		}
+		embedded, err := config.Load(path)
+		if err != nil {
+			return nil, fmt.Errorf("embeds %s: %w", embed, err)
+		}
+		versions[embedded.Name] = embedded.Version
+	}
+	return versions, nil
+}
+
+// Args returns the go arguments building b to output, with its ldflags
+// rendered from meta. Templates can also read the environment with
+// {{env "NAME"}}, to embed a value such as a public key kept in CI.
+func Args(b config.BuildConfig, meta Metadata, output string) ([]string, error) {
+	templates := b.Ldflags
+	if len(templates) == 0 {
+		templates = DefaultLdflags
+	}
+	var ldflags []string
+	for _, text := range templates {
+		t, err := template.New("ldflags").Option("missingkey=error").Funcs(template.FuncMap{"env": os.Getenv}).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("ldflags: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, meta); err != nil {
+			return nil, fmt.Errorf("ldflags: %w", err)
+		}
+		ldflags = append(ldflags, buf.String())
+	}
+
+	args := []string{"build", "-o", output, "-ldflags", strings.Join(ldflags, " ")}
+	if len(b.Tags) > 0 {
+		args = append(args, "-tags", strings.Join(b.Tags, ","))
+	}
+	args = append(args, b.Flags...)
+	main := b.Main
+	if main == "" {
+		main = "."
+	}
+	return append(args, main), nil
+}
+
+// Apply records the built binaries in cfg.Binaries. Platforms listed in
+// binaries keep their configured path.
+func Apply(cfg *config.Config, artifacts []Artifact) {
+	if cfg.Binaries == nil {
+		cfg.Binaries = make(map[string]string)
+	}
+	for _, artifact := range artifacts {
+		if _, ok := cfg.Binaries[artifact.Platform]; !ok {
+			cfg.Binaries[artifact.Platform] = artifact.Binary
+		}
+	}
+}
+
+// Commit returns the checked-out commit, or none outside a git checkout
+func Commit(ctx context.Context) string {
+	output, err := exec.CommandContext(ctx, "git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "none"
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// date returns the build time, taken from SOURCE_DATE_EPOCH when set so
+// reproducible builds embed the same date
+func date() string {
+	now := time.Now()
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		now = time.Unix(epoch, 0)
+	}
+	return now.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package build
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestArgs(t *testing.T) {
+	meta := Metadata{Name: "myapp", Version: "1.2.3", Commit: "abc123", Date: "2026-01-02T03:04:05Z", Os: "linux", Arch: "arm64"}
+
+	args, err := Args(config.BuildConfig{}, meta, "out/myapp")
+	if err != nil {
+		t.Fatalf("Args() error = %v", err)
+	}
+	want := "build -o out/myapp -ldflags -s -w -X main.version=1.2.3 -X main.commit=abc123 -X main.date=2026-01-02T03:04:05Z ."
+	if got := strings.Join(args, " "); got != want {
+		t.Errorf("Args() = %q, want %q", got, want)
+	}
+
+	b := config.BuildConfig{
+		Main:    "./cmd/myapp",
+		Ldflags: []string{"-X github.com/me/myapp/internal.Version={{.Version}}", "-X main.target={{.Os}}/{{.Arch}}"},
+		Tags:    []string{"netgo", "osusergo"},
+		Flags:   []string{"-trimpath"},
+	}
+	args, err = Args(b, meta, "out/myapp")
+	if err != nil {
+		t.Fatalf("Args() error = %v", err)
+	}
+	want = "build -o out/myapp -ldflags -X github.com/me/myapp/internal.Version=1.2.3 -X main.target=linux/arm64 -tags netgo,osusergo -trimpath ./cmd/myapp"
+	if got := strings.Join(args, " "); got != want {
+		t.Errorf("Args() = %q, want %q", got, want)
+	}
+
+	t.Setenv("MYAPP_KEY", "RWSkey")
+	args, err = Args(config.BuildConfig{Ldflags: []string{"-X main.key={{env \"MYAPP_KEY\"}}"}}, meta, "out/myapp")
+	if err != nil {
+		t.Fatalf("Args() error = %v", err)
+	}
+	if args[4] != "-X main.key=RWSkey" {
+		t.Errorf("Args() ldflags = %q, want the environment variable", args[4])
+	}
+
+	if _, err := Args(config.BuildConfig{Ldflags: []string{"-X main.v={{.Tag}}"}}, meta, "out"); err == nil {
+		t.Error("Expected an unknown template field to fail")
+	}
+}
+
+func TestEmbeddedVersions(t *testing.T) {
+	root := t.TempDir()
+	helper := filepath.Join(root, "helper")
+	os.MkdirAll(helper, 0755)
+	os.WriteFile(filepath.Join(helper, "bagboy.yaml"), []byte("name: helper\nversion: 0.4.1\n"), 0644)
+
+	b := config.BuildConfig{Embeds: []string{"../helper"}, Ldflags: []string{`-X main.helperVersion={{index .Versions "helper"}}`}}
+	versions, err := EmbeddedVersions(b, filepath.Join(root, "app"))
+	if err != nil {
+		t.Fatalf("EmbeddedVersions() error = %v", err)
+	}
+	if versions["helper"] != "0.4.1" {
+		t.Errorf("EmbeddedVersions() = %v, want helper 0.4.1", versions)
+	}
+
+	args, err := Args(b, Metadata{Version: "1.0.0", Versions: versions}, "out")
+	if err != nil {
+		t.Fatalf("Args() error = %v", err)
+	}
+	if !strings.Contains(strings.Join(args, " "), "-X main.helperVersion=0.4.1") {
+		t.Errorf("Args() = %v, want the helper version", args)
+	}
+
+	if _, err := EmbeddedVersions(config.BuildConfig{Embeds: []string{"missing"}}, root); err == nil {
+		t.Error("Expected an embed without a config to fail")
+	}
+}
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not installed")
+	}
+	src := t.TempDir()
+	os.WriteFile(filepath.Join(src, "go.mod"), []byte("module example.com/myapp\n\ngo 1.21\n"), 0644)
+	os.WriteFile(filepath.Join(src, "main.go"), []byte("package main\n\nvar version = \"dev\"\n\nfunc main() { println(version) }\n"), 0644)
+
+	cfg := &config.Config{
+		Name:     "myapp",
+		Version:  "1.2.3",
+		Binaries: map[string]string{"darwin-amd64": "prebuilt/myapp"},
+		Builds: []config.BuildConfig{{
+			Dir:    src,
+			Goos:   []string{"linux", "windows", "darwin"},
+			Goarch: []string{"amd64"},
+			Env:    []string{"CGO_ENABLED=0"},
+		}},
+	}
+	out := filepath.Join(t.TempDir(), "build")
+	artifacts, err := Run(context.Background(), cfg, out)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// darwin-amd64 is already in binaries, so it is not built
+	want := map[string]string{
+		"linux-amd64":   filepath.Join(out, "linux-amd64", "myapp"),
+		"windows-amd64": filepath.Join(out, "windows-amd64", "myapp.exe"),
+	}
+	if len(artifacts) != len(want) {
+		t.Fatalf("Run() = %+v, want %v", artifacts, want)
+	}
+	for _, artifact := range artifacts {
+		if artifact.Binary != want[artifact.Platform] {
+			t.Errorf("%s built to %s, want %s", artifact.Platform, artifact.Binary, want[artifact.Platform])
+		}
+		if _, err := os.Stat(artifact.Binary); err != nil {
+			t.Errorf("%s: %v", artifact.Platform, err)
+		}
+	}
+
+	Apply(cfg, artifacts)
+	if cfg.Binaries["linux-amd64"] != want["linux-amd64"] || cfg.Binaries["darwin-amd64"] != "prebuilt/myapp" {
+		t.Errorf("Apply() binaries = %v", cfg.Binaries)
+	}
+
+	cfg.Builds[0].Main = "./missing"
+	cfg.Binaries = nil
+	if _, err := Run(context.Background(), cfg, out); err == nil || !strings.Contains(err.Error(), "go build for darwin-amd64 failed") {
+		t.Errorf("Run() error = %v, want the failed target", err)
+	}
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package buildenv records the machine a release was packaged on: the
+// operating system, the versions of the tools bagboy drives, and the
+// environment variables that change what they produce. Comparing two
+// manifests shows why two maintainers' packages of the same version
+// differ.
+package buildenv
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/requirements"
+)
+
+// File is the manifest's name in dist and on the release
+const File = "build-environment.json"
+
+// SchemaVersion is recorded in every manifest
+const SchemaVersion = "bagboy.buildenv/v1"
+
+// Manifest describes the build environment of one release
+type Manifest struct {
+	Schema    string            `json:"schema"`
+	Bagboy    string            `json:"bagboy"`
+	Project   string            `json:"project"`
+	Version   string            `json:"version"`
+	Created   time.Time         `json:"created"`
+	OS        string            `json:"os"`                   // Go operating system, e.g. linux
+	Arch      string            `json:"arch"`                 // Go architecture, e.g. arm64
+	OSVersion string            `json:"os_version,omitempty"` // e.g. Ubuntu 24.04.1 LTS or macOS 15.2
+	Kernel    string            `json:"kernel,omitempty"`
+	CI        string            `json:"ci,omitempty"` // CI provider, when running in one
+	Tools     map[string]string `json:"tools"`        // command -> version, for the tools found
+	Env       map[string]string `json:"env"`
+}
+
+// detector finds a tool and its version
+type detector func(ctx context.Context) (requirements.Tool, bool)
+
+func versionFlag(command string, args ...string) detector {
+	return func(ctx context.Context) (requirements.Tool, bool) {
+		return requirements.Detect(ctx, command, args...)
+	}
+}
+
+// tools are the toolchains and package builders bagboy runs
+var tools = []detector{
+	versionFlag("go", "version"),
+	versionFlag("git", "--version"),
+	requirements.Docker,
+	requirements.WiX,
+	versionFlag("dpkg-deb", "--version"),
+	versionFlag("rpmbuild", "--version"),
+	versionFlag("snapcraft", "--version"),
+	versionFlag("appimagetool", "--version"),
+	versionFlag("flatpak-builder", "--version"),
+	versionFlag("rattler-build", "--version"),
+	versionFlag("conda-build", "--version"),
+	versionFlag("gpg", "--version"),
+	versionFlag("cosign", "version"),
+	versionFlag("minisign", "-v"),
+}
+
+// envNames are variables recorded by default: they change what compilers
+// and packaging tools produce
+var envNames = []string{
+	"GO*", "CGO_*", "CC", "CXX", "CFLAGS", "CXXFLAGS", "LDFLAGS",
+	"SOURCE_DATE_EPOCH", "TZ", "LANG", "LC_ALL", "LC_CTYPE", "UMASK",
+	"DOCKER_BUILDKIT", "DOCKER_DEFAULT_PLATFORM", "MACOSX_DEPLOYMENT_TARGET",
+	"CI", "GITHUB_ACTIONS", "GITHUB_SHA", "GITHUB_REF", "GITHUB_WORKFLOW", "RUNNER_OS", "RUNNER_ARCH",
+	"GITLAB_CI", "CI_COMMIT_SHA", "CI_RUNNER_VERSION",
+}
+
+// secretEnv matches the names of variables never recorded, whatever
+// build_env.env says
+var secretEnv = regexp.MustCompile(`(?i)(TOKEN|SECRET|PASSWORD|PASSWD|API_?KEY|ACCESS_KEY|PRIVATE_KEY|CREDENTIALS|AUTH)`)
+
+// urlPassword matches the password in a URL such as a GOPROXY with
+// credentials
+var urlPassword = regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+@`)
+
+// Capture describes this machine for the release of cfg built by bagboy
+// version bagboy
+func Capture(ctx context.Context, cfg *config.Config, bagboy string) *Manifest {
+	m := &Manifest{
+		Schema:    SchemaVersion,
+		Bagboy:    bagboy,
+		Project:   cfg.Name,
+		Version:   cfg.Version,
+		Created:   time.Now().UTC().Truncate(time.Second),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		OSVersion: osVersion(ctx),
+		Kernel:    kernel(ctx),
+		CI:        ciProvider(),
+		Tools:     make(map[string]string),
+		Env:       Env(os.Environ(), cfg.BuildEnv.Env),
+	}
+	for _, detect := range tools {
+		found, ok := detect(ctx)
+		if !ok {
+			continue
+		}
+		m.Tools[found.Command] = ""
+		if found.Version != (requirements.Version{}) {
+			m.Tools[found.Command] = found.Version.String()
+		}
+	}
+	return m
+}
+
+// Env returns the variables in environ named by envNames or extra. A name
+// ending in * matches by prefix. Variables that look like secrets are
+// always left out, and passwords in URLs are masked.
+func Env(environ []string, extra []string) map[string]string {
+	patterns := append(append([]string{}, envNames...), extra...)
+	env := make(map[string]string)
+	for _, entry := range environ {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || secretEnv.MatchString(name) || !matchName(patterns, name) {
+			continue
+		}
+		env[name] = urlPassword.ReplaceAllString(value, "${1}xxxxx@")
+	}
+	return env
+}
+
+func matchName(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// Write saves the manifest as indented JSON
+func (m *Manifest) Write(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Load reads a manifest written by Write
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if m.Schema != SchemaVersion {
+		return nil, fmt.Errorf("%s: not a build environment manifest (schema %q)", path, m.Schema)
+	}
+	return &m, nil
+}
+
+// Difference is one setting that differs between two manifests. From or
+// To is empty when only one side has it.
+type Difference struct {
+	Field string `json:"field"` // e.g. os_version, tools.go or env.GOFLAGS
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+func (d Difference) String() string {
+	from, to := d.From, d.To
+	if from == "" {
+		from = "(none)"
+	}
+	if to == "" {
+		to = "(none)"
+	}
+	return fmt.Sprintf("%s: %s -> %s", d.Field, from, to)
+}
+
+// Compare returns what differs between the environments of from and to,
+// ignoring the project, version and time, sorted by field
+func Compare(from, to *Manifest) []Difference {
+	var diffs []Difference
+	add := func(field, a, b string) {
+		if a != b {
+			diffs = append(diffs, Difference{Field: field, From: a, To: b})
+		}
+	}
+	add("bagboy", from.Bagboy, to.Bagboy)
+	add("os", from.OS, to.OS)
+	add("arch", from.Arch, to.Arch)
+	add("os_version", from.OSVersion, to.OSVersion)
+	add("kernel", from.Kernel, to.Kernel)
+	add("ci", from.CI, to.CI)
+	compareMaps := func(prefix string, a, b map[string]string) {
+		for key := range union(a, b) {
+			av, aok := a[key]
+			bv, bok := b[key]
+			// A tool with no version and a missing tool still differ
+			if aok != bok && av == bv {
+				av, bv = presence(aok), presence(bok)
+			}
+			add(prefix+key, av, bv)
+		}
+	}
+	compareMaps("tools.", from.Tools, to.Tools)
+	compareMaps("env.", from.Env, to.Env)
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Field < diffs[j].Field })
+	return diffs
+}
+
+func union(a, b map[string]string) map[string]bool {
+	keys := make(map[string]bool)
+	for key := range a {
+		keys[key] = true
+	}
+	for key := range b {
+		keys[key] = true
+	}
+	return keys
+}
+
+func presence(ok bool) string {
+	if ok {
+		return "installed"
+	}
+	return ""
+}
+
+// osVersion returns the distribution or OS release name
+func osVersion(ctx context.Context) string {
+	switch runtime.GOOS {
+	case "linux":
+		f, err := os.Open("/etc/os-release")
+		if err != nil {
+			return ""
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if value, ok := strings.CutPrefix(scanner.Text(), "PRETTY_NAME="); ok {
+				return strings.Trim(value, `"`)
+			}
+		}
+	case "darwin":
+		if version := output(ctx, "sw_vers", "-productVersion"); version != "" {
+			return "macOS " + version
+		}
+	case "windows":
+		return output(ctx, "cmd", "/c", "ver")
+	}
+	return ""
+}
+
+func kernel(ctx context.Context) string {
+	if runtime.GOOS == "windows" {
+		return ""
+	}
+	return output(ctx, "uname", "-r")
+}
+
+// ciProvider names the CI service bagboy is running in
+func ciProvider() string {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return "github-actions"
+	case os.Getenv("GITLAB_CI") == "true":
+		return "gitlab-ci"
+	case os.Getenv("CIRCLECI") == "true":
+		return "circleci"
+	case os.Getenv("BUILDKITE") == "true":
+		return "buildkite"
+	case os.Getenv("JENKINS_URL") != "":
+		return "jenkins"
+	case os.Getenv("CI") != "":
+		return "ci"
+	}
+	return ""
+}
+
+func output(ctx context.Context, command string, args ...string) string {
+	out, err := exec.CommandContext(ctx, command, args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package changelog maintains a CHANGELOG.md in the Keep a Changelog
+// format and reads release entries back for package changelogs.
+package changelog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// DefaultFile is the changelog used when changelog is not configured
+const DefaultFile = "CHANGELOG.md"
+
+// Sections are the Keep a Changelog change types, in the order they are
+// written
+var Sections = []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+
+const header = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),
+and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).
+
+## [Unreleased]
+`
+
+// Release is one version's entry
+type Release struct {
+	Version string
+	Date    time.Time
+	Changes map[string][]string // section name to items
+}
+
+// Items returns the changes in section order
+func (r Release) Items() []string {
+	var items []string
+	for _, section := range Sections {
+		items = append(items, r.Changes[section]...)
+	}
+	return items
+}
+
+// Empty reports whether the release lists no changes
+func (r Release) Empty() bool {
+	return len(r.Items()) == 0
+}
+
+// Path returns the changelog file for cfg
+func Path(cfg *config.Config) string {
+	if cfg.Changelog != "" {
+		return cfg.Changelog
+	}
+	return DefaultFile
+}
+
+var (
+	headingPattern = regexp.MustCompile(`^## \[([^\]]+)\](?:\s+-\s+(\d{4}-\d{2}-\d{2}))?`)
+	sectionPattern = regexp.MustCompile(`^### (\w+)`)
+)
+
+// Parse reads the releases in a changelog, newest first. The Unreleased
+// entry has the version "Unreleased".
+func Parse(content string) []Release {
+	var releases []Release
+	var current *Release
+	section := ""
+	for _, line := range strings.Split(content, "\n") {
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			releases = append(releases, Release{Version: m[1], Changes: map[string][]string{}})
+			current = &releases[len(releases)-1]
+			current.Date, _ = time.Parse("2006-01-02", m[2])
+			section = ""
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if m := sectionPattern.FindStringSubmatch(line); m != nil {
+			section = m[1]
+			continue
+		}
+		if section == "" {
+			continue
+		}
+		if item, ok := strings.CutPrefix(line, "- "); ok {
+			current.Changes[section] = append(current.Changes[section], strings.TrimSpace(item))
+		} else if strings.HasPrefix(line, "  ") && len(current.Changes[section]) > 0 {
+			// Continuation of a wrapped item
+			items := current.Changes[section]
+			items[len(items)-1] += " " + strings.TrimSpace(line)
+		}
+	}
+	return releases
+}
+
+// Find returns the entry for version from the changelog, ignoring a
+// leading "v"
+func Find(cfg *config.Config, version string) (Release, bool) {
+	data, err := os.ReadFile(Path(cfg))
+	if err != nil {
+		return Release{}, false
+	}
+	version = strings.TrimPrefix(version, "v")
+	for _, release := range Parse(string(data)) {
+		if strings.TrimPrefix(release.Version, "v") == version {
+			return release, true
+		}
+	}
+	return Release{}, false
+}
+
+// Format renders a release as a changelog entry
+func Format(release Release) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## [%s] - %s\n", release.Version, release.Date.Format("2006-01-02"))
+	for _, section := range Sections {
+		if len(release.Changes[section]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", section)
+		for _, item := range release.Changes[section] {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+	}
+	return b.String()
+}
+
+// Insert adds release to content directly below the Unreleased heading,
+// replacing whatever was listed under it. An empty content starts a new
+// changelog.
+func Insert(content string, release Release) (string, error) {
+	if content == "" {
+		content = header
+	}
+	for _, existing := range Parse(content) {
+		if strings.TrimPrefix(existing.Version, "v") == strings.TrimPrefix(release.Version, "v") {
+			return "", fmt.Errorf("changelog already has an entry for %s", release.Version)
+		}
+	}
+
+	lines := strings.Split(content, "\n")
+	unreleased, next := -1, len(lines)
+	for i, line := range lines {
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			if unreleased < 0 && strings.EqualFold(m[1], "Unreleased") {
+				unreleased = i
+				continue
+			}
+			next = i
+			break
+		}
+	}
+
+	entry := Format(release)
+	after := strings.Join(lines[next:], "\n")
+	if after != "" {
+		entry += "\n"
+	}
+	if unreleased < 0 {
+		// No Unreleased heading, so the entry goes above the newest release
+		before := strings.TrimRight(strings.Join(lines[:next], "\n"), "\n")
+		return before + "\n\n" + entry + after, nil
+	}
+	before := strings.Join(lines[:unreleased+1], "\n")
+	return before + "\n\n" + entry + after, nil
+}
+
+// Unreleased returns the changes listed under the Unreleased heading
+func Unreleased(content string) map[string][]string {
+	for _, release := range Parse(content) {
+		if strings.EqualFold(release.Version, "Unreleased") {
+			return release.Changes
+		}
+	}
+	return nil
+}
+
+var conventionalPattern = regexp.MustCompile(`^(\w+)(?:\([^)]*\))?!?:\s*(.+)$`)
+
+// FromCommits builds the changes from the commit subjects since the
+// previous tag. Conventional commit types pick the section; chores, docs,
+// tests and merges are left out.
+func FromCommits(ctx context.Context, version string) (map[string][]string, error) {
+	args := []string{"log", "--no-merges", "--format=%s"}
+	tag := "v" + strings.TrimPrefix(version, "v")
+	if previous := previousTag(ctx, tag); previous != "" {
+		args = append(args, previous+"..HEAD")
+	}
+	output, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	changes := map[string][]string{}
+	for _, subject := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if subject == "" {
+			continue
+		}
+		if section, text := classify(subject); section != "" {
+			changes[section] = append(changes[section], text)
+		}
+	}
+	return changes, nil
+}
+
+// classify maps a commit subject to a changelog section and item
+func classify(subject string) (string, string) {
+	m := conventionalPattern.FindStringSubmatch(subject)
+	if m == nil {
+		return "Changed", subject
+	}
+	text := strings.ToUpper(m[2][:1]) + m[2][1:]
+	switch strings.ToLower(m[1]) {
+	case "feat", "feature":
+		return "Added", text
+	case "fix", "bugfix":
+		return "Fixed", text
+	case "security":
+		return "Security", text
+	case "deprecate":
+		return "Deprecated", text
+	case "remove", "revert":
+		return "Removed", text
+	case "chore", "docs", "test", "tests", "ci", "build", "style", "release":
+		return "", ""
+	}
+	return "Changed", text
+}
+
+// previousTag returns the newest tag reachable from HEAD other than tag
+func previousTag(ctx context.Context, tag string) string {
+	output, err := exec.CommandContext(ctx, "git", "describe", "--tags", "--abbrev=0", "--exclude", tag, "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// Update inserts the entry for cfg.Version into the changelog and
+// returns it. The entry takes the changes listed under Unreleased or,
+// when there are none, the commits since the previous tag.
+func Update(ctx context.Context, cfg *config.Config, date time.Time) (Release, error) {
+	path := Path(cfg)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return Release{}, err
+	}
+
+	release := Release{Version: strings.TrimPrefix(cfg.Version, "v"), Date: date, Changes: Unreleased(string(data))}
+	if release.Empty() {
+		if release.Changes, err = FromCommits(ctx, cfg.Version); err != nil {
+			return Release{}, err
+		}
+	}
+	if release.Empty() {
+		return Release{}, fmt.Errorf("no changes for %s: nothing under Unreleased and no commits since the previous tag", cfg.Version)
+	}
+
+	content, err := Insert(string(data), release)
+	if err != nil {
+		return Release{}, err
+	}
+	return release, os.WriteFile(path, []byte(content), 0644)
+}
+
+// Commit commits the changelog on its own
+func Commit(ctx context.Context, cfg *config.Config) error {
+	path := Path(cfg)
+	if output, err := exec.CommandContext(ctx, "git", "add", path).CombinedOutput(); err != nil {
+		return fmt.Errorf("git add failed: %w\nOutput: %s", err, output)
+	}
+	message := fmt.Sprintf("Update changelog for v%s", strings.TrimPrefix(cfg.Version, "v"))
+	if output, err := exec.CommandContext(ctx, "git", "commit", "-m", message, "--", path).CombinedOutput(); err != nil {
+		return fmt.Errorf("git commit failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+const existing = `# Changelog
+
+## [Unreleased]
+
+### Added
+
+- Export to nfpm
+- Snippets for the
+  README
+
+### Fixed
+
+- Crash on empty config
+
+## [1.0.0] - 2026-01-15
+
+### Added
+
+- First release
+`
+
+func TestParse(t *testing.T) {
+	releases := Parse(existing)
+	if len(releases) != 2 {
+		t.Fatalf("Expected 2 releases, got %d", len(releases))
+	}
+	if releases[0].Version != "Unreleased" || releases[1].Version != "1.0.0" {
+		t.Errorf("Unexpected versions: %s, %s", releases[0].Version, releases[1].Version)
+	}
+	want := []string{"Export to nfpm", "Snippets for the README", "Crash on empty config"}
+	if got := releases[0].Items(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Items() = %q, want %q", got, want)
+	}
+	if date := releases[1].Date.Format("2006-01-02"); date != "2026-01-15" {
+		t.Errorf("Expected date 2026-01-15, got %s", date)
+	}
+}
+
+func TestInsert(t *testing.T) {
+	release := Release{
+		Version: "1.1.0",
+		Date:    time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		Changes: Unreleased(existing),
+	}
+	content, err := Insert(existing, release)
+	if err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	expected := `# Changelog
+
+## [Unreleased]
+
+## [1.1.0] - 2026-02-01
+
+### Added
+
+- Export to nfpm
+- Snippets for the README
+
+### Fixed
+
+- Crash on empty config
+
+## [1.0.0] - 2026-01-15
+`
+	if !strings.HasPrefix(content, expected) {
+		t.Errorf("Unexpected changelog:\n%s", content)
+	}
+
+	if _, err := Insert(content, release); err == nil {
+		t.Error("Expected an error inserting a version twice")
+	}
+}
+
+func TestInsertNewFile(t *testing.T) {
+	release := Release{Version: "0.1.0", Changes: map[string][]string{"Added": {"Everything"}}}
+	content, err := Insert("", release)
+	if err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if !strings.Contains(content, "Keep a Changelog") || !strings.HasSuffix(content, "## [Unreleased]\n\n## [0.1.0] - 0001-01-01\n\n### Added\n\n- Everything\n") {
+		t.Errorf("Unexpected changelog:\n%s", content)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		subject string
+		section string
+		text    string
+	}{
+		{"feat(deb): add source packages", "Added", "Add source packages"},
+		{"fix: handle empty versions", "Fixed", "Handle empty versions"},
+		{"feat!: drop Go 1.20", "Added", "Drop Go 1.20"},
+		{"security: pin actions", "Security", "Pin actions"},
+		{"perf: faster checksums", "Changed", "Faster checksums"},
+		{"chore: bump deps", "", ""},
+		{"docs: typo", "", ""},
+		{"Support Alpine packages", "Changed", "Support Alpine packages"},
+	}
+	for _, tt := range tests {
+		section, text := classify(tt.subject)
+		if section != tt.section || text != tt.text {
+			t.Errorf("classify(%q) = %q, %q, want %q, %q", tt.subject, section, text, tt.section, tt.text)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGES.md")
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Changelog: path}
+
+	release, ok := Find(cfg, "v1.0.0")
+	if !ok || !reflect.DeepEqual(release.Items(), []string{"First release"}) {
+		t.Errorf("Find(v1.0.0) = %+v, %v", release, ok)
+	}
+	if _, ok := Find(cfg, "2.0.0"); ok {
+		t.Error("Expected no entry for 2.0.0")
+	}
+}
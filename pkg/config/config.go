@@ -19,24 +19,97 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/scttfrdmn/bagboy/pkg/semver"
+	"github.com/scttfrdmn/bagboy/pkg/spdx"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
+	Include     Includes          `yaml:"include,omitempty"`
 	Name        string            `yaml:"name"`
-	Version     string            `yaml:"version"`
+	Version     string            `yaml:"version"` // or auto, from the git tag
+	TagPattern  string            `yaml:"tag_pattern,omitempty"`
 	Description string            `yaml:"description"`
 	Homepage    string            `yaml:"homepage"`
 	License     string            `yaml:"license"`
 	Author      string            `yaml:"author"`
+	Authors     []AuthorConfig    `yaml:"authors,omitempty"`
 	Binaries    map[string]string `yaml:"binaries"`
+	Files       []FileConfig      `yaml:"files,omitempty"`
+	PostInstall PostInstallConfig `yaml:"post_install,omitempty"`
+	Desktop     *DesktopConfig    `yaml:"desktop,omitempty"` // set for GUI applications
+	Prebuilt    PrebuiltConfig    `yaml:"prebuilt,omitempty"`
+	Builds      []BuildConfig     `yaml:"builds,omitempty"`
+	Hooks       HooksConfig       `yaml:"hooks,omitempty"`
+	Accounts    map[string]string `yaml:"accounts,omitempty"` // your usernames on package registries, keyed by format, e.g. pypi
+	SBOM        SBOMConfig        `yaml:"sbom,omitempty"`
+	BuildEnv    BuildEnvConfig    `yaml:"build_env,omitempty"`
 	GitHub      GitHubConfig      `yaml:"github"`
+	GitLab      GitLabConfig      `yaml:"gitlab,omitempty"`
 	Installer   InstallerConfig   `yaml:"installer"`
 	Packages     PackagesConfig     `yaml:"packages"`
 	Signing      SigningConfig      `yaml:"signing"`
 	Dependencies DependenciesConfig `yaml:"dependencies,omitempty"`
+	Delta        DeltaConfig        `yaml:"delta,omitempty"`
+	Encryption   EncryptionConfig   `yaml:"encryption,omitempty"`
+	Policy       PolicyConfig       `yaml:"policy,omitempty"`
+	GoModule     GoModuleConfig     `yaml:"go_module,omitempty"`
+	Performance  PerformanceConfig  `yaml:"performance,omitempty"`
+	Preserve     PreserveConfig     `yaml:"preserve,omitempty"`
+	Audit        AuditConfig        `yaml:"audit,omitempty"`
+
+	// Changelog is the Keep a Changelog file kept by bagboy changelog
+	// update and read for deb and rpm changelogs, default CHANGELOG.md
+	Changelog string `yaml:"changelog,omitempty"`
+
+	// Templates replaces built-in templates with files, keyed by
+	// <format>/<template> such as brew/formula or deb/control
+	Templates map[string]string `yaml:"templates,omitempty"`
+
+	// unknownPackages are keys under packages: that configure nothing,
+	// usually a misspelled format, reported by Validate
+	unknownPackages []string
+
+	// snapshot is set when version: auto resolved to an untagged build
+	snapshot bool
+	tag      string
+}
+
+// FileConfig ships an extra file or directory, such as example configs,
+// plugins or data, alongside the binary in deb, rpm, msi, dmg and
+// chocolatey packages
+type FileConfig struct {
+	Src     string            `yaml:"src"`
+	Dst     string            `yaml:"dst,omitempty"`     // install path; relative paths go under /usr on Linux and the install directory elsewhere
+	Formats map[string]string `yaml:"formats,omitempty"` // install path per format; "-" leaves the file out
+}
+
+// PostInstallConfig is a message shown once a package is installed, such
+// as "Run `myapp init` to get started". Each format shows it its own way:
+// brew caveats, a deb or rpm scriptlet, Write-Host in PowerShell.
+type PostInstallConfig struct {
+	Message string            `yaml:"message,omitempty"`
+	Formats map[string]string `yaml:"formats,omitempty"` // message per format; "-" shows none
+}
+
+// MessageFor returns the message format shows after installing
+func (p PostInstallConfig) MessageFor(format string) string {
+	message := p.Message
+	if override, ok := p.Formats[format]; ok {
+		message = override
+	}
+	if message == "-" {
+		return ""
+	}
+	return strings.TrimSpace(message)
 }
 
 type GitHubConfig struct {
@@ -47,6 +120,10 @@ type GitHubConfig struct {
 	Tap      TapConfig     `yaml:"tap"`
 	Bucket   BucketConfig  `yaml:"bucket"`
 	Winget   WingetConfig  `yaml:"winget"`
+	Prune    PruneConfig   `yaml:"prune,omitempty"`
+	// ManifestPRs holds back tap and bucket updates for a draft or
+	// prerelease as draft pull requests, merged once it is published
+	ManifestPRs ManifestPRConfig `yaml:"manifest_prs,omitempty"`
 }
 
 type ReleaseConfig struct {
@@ -54,6 +131,16 @@ type ReleaseConfig struct {
 	Draft         bool `yaml:"draft"`
 	Prerelease    bool `yaml:"prerelease"`
 	GenerateNotes bool `yaml:"generate_notes"`
+	// Immutable never changes a published release: assets are uploaded
+	// to a draft that is published last, a published tag is never
+	// released again, and prune only deletes drafts
+	Immutable bool `yaml:"immutable,omitempty"`
+	// RequireChecks names the workflows, or jobs and other check runs,
+	// that must have passed on the commit being released
+	RequireChecks []string `yaml:"require_checks,omitempty"`
+	// DedupeAssets uploads byte-identical assets once, listing the names
+	// left out in aliases.txt
+	DedupeAssets bool `yaml:"dedupe_assets,omitempty"`
 }
 
 type TapConfig struct {
@@ -62,6 +149,9 @@ type TapConfig struct {
 	AutoCreate bool   `yaml:"auto_create"`
 	AutoCommit bool   `yaml:"auto_commit"`
 	AutoPush   bool   `yaml:"auto_push"`
+	// Versioned also commits Formula/<name>@<major>.<minor>.rb so earlier
+	// minor versions stay installable
+	Versioned bool `yaml:"versioned,omitempty"`
 }
 
 type BucketConfig struct {
@@ -70,6 +160,9 @@ type BucketConfig struct {
 	AutoCreate bool   `yaml:"auto_create"`
 	AutoCommit bool   `yaml:"auto_commit"`
 	AutoPush   bool   `yaml:"auto_push"`
+	// Archive also commits archive/<name>/<version>.json, keeping every
+	// released manifest
+	Archive bool `yaml:"archive,omitempty"`
 }
 
 type WingetConfig struct {
@@ -78,11 +171,115 @@ type WingetConfig struct {
 	ForkRepo string `yaml:"fork_repo"`
 }
 
+// PruneConfig is the retention policy applied by bagboy prune
+type PruneConfig struct {
+	DraftMaxAgeDays   int    `yaml:"draft_max_age_days"`
+	NightlyTag        string `yaml:"nightly_tag"`
+	NightlyMaxAgeDays int    `yaml:"nightly_max_age_days"`
+	PrunePrereleases  bool   `yaml:"prune_prereleases"`
+	DeleteTags        bool   `yaml:"delete_tags"`
+}
+
+// ManifestPRConfig opens tap and bucket updates as draft pull requests
+// that bagboy promote marks ready and sets to auto-merge
+type ManifestPRConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	MergeMethod string `yaml:"merge_method,omitempty"` // merge, squash or rebase, default squash
+}
+
+// GitLabConfig publishes releases to gitlab.com or a self-managed GitLab
+// instead of GitHub, with bagboy publish --provider gitlab
+type GitLabConfig struct {
+	URL      string              `yaml:"url,omitempty"`       // instance URL, default https://gitlab.com
+	Project  string              `yaml:"project"`             // project path, e.g. group/myapp
+	TokenEnv string              `yaml:"token_env,omitempty"` // default GITLAB_TOKEN
+	Release  GitLabReleaseConfig `yaml:"release"`
+	Tap      GitLabRepoConfig    `yaml:"tap,omitempty"`
+	Bucket   GitLabRepoConfig    `yaml:"bucket,omitempty"`
+}
+
+// GitLabReleaseConfig creates a GitLab release for the tag. Assets are
+// uploaded to the project's generic package registry and linked from the
+// release, or with assets: links only linked, for assets already served
+// from installer.base_url.
+type GitLabReleaseConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Assets  string `yaml:"assets,omitempty"` // generic (default) or links
+}
+
+// GitLabRepoConfig commits the Homebrew formula or Scoop manifest to a
+// GitLab project used as a tap or bucket
+type GitLabRepoConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Project string `yaml:"project"`          // e.g. group/homebrew-tap
+	Branch  string `yaml:"branch,omitempty"` // default main
+}
+
+// BaseURL returns the instance URL without a trailing slash
+func (g GitLabConfig) BaseURL() string {
+	if g.URL == "" {
+		return "https://gitlab.com"
+	}
+	return strings.TrimSuffix(g.URL, "/")
+}
+
+// DownloadURL returns the base URL release assets are downloaded from,
+// the release's permanent links to them
+func (g GitLabConfig) DownloadURL(tag string) string {
+	return fmt.Sprintf("%s/%s/-/releases/%s/downloads", g.BaseURL(), g.Project, tag)
+}
+
+func (g GitLabConfig) validate() error {
+	if !g.Release.Enabled && !g.Tap.Enabled && !g.Bucket.Enabled {
+		return nil
+	}
+	if g.Release.Enabled && g.Project == "" {
+		return fmt.Errorf("gitlab.project is required")
+	}
+	switch g.Release.Assets {
+	case "", "generic", "links":
+	default:
+		return fmt.Errorf("gitlab.release.assets must be generic or links")
+	}
+	if g.Tap.Enabled && g.Tap.Project == "" {
+		return fmt.Errorf("gitlab.tap.project is required")
+	}
+	if g.Bucket.Enabled && g.Bucket.Project == "" {
+		return fmt.Errorf("gitlab.bucket.project is required")
+	}
+	return nil
+}
+
 type InstallerConfig struct {
 	BaseURL        string `yaml:"base_url"`
 	InstallPath    string `yaml:"install_path"`
 	DetectOS       bool   `yaml:"detect_os"`
-	VerifyChecksum bool   `yaml:"verify_checksum"`
+	VerifyChecksum bool   `yaml:"verify_checksum"` // no longer optional; installers always check pinned checksums
+	Private        bool   `yaml:"private,omitempty"`
+	// Mirrors are base URLs serving the same assets, tried in order when
+	// base_url fails
+	Mirrors     []string          `yaml:"mirrors,omitempty"`
+	SourceForge SourceForgeConfig `yaml:"sourceforge,omitempty"`
+	S3          S3Config          `yaml:"s3,omitempty"`
+}
+
+// S3Config mirrors release assets to an S3 bucket or an S3-compatible
+// service. Credentials come from AWS_ACCESS_KEY_ID and
+// AWS_SECRET_ACCESS_KEY.
+type S3Config struct {
+	Bucket   string `yaml:"bucket"`
+	Prefix   string `yaml:"prefix,omitempty"`   // key prefix; assets go under <prefix>/<version>/
+	Region   string `yaml:"region,omitempty"`   // default AWS_REGION, then us-east-1
+	Endpoint string `yaml:"endpoint,omitempty"` // e.g. https://<account>.r2.cloudflarestorage.com
+}
+
+// SourceForgeConfig mirrors release assets to a SourceForge project's
+// files area
+type SourceForgeConfig struct {
+	Project string `yaml:"project"`
+	User    string `yaml:"user,omitempty"`    // default SF_USER
+	Dir     string `yaml:"dir,omitempty"`     // directory under the project's files; default the version
+	SSHKey  string `yaml:"ssh_key,omitempty"` // private key registered with SourceForge
 }
 
 type PackagesConfig struct {
@@ -93,37 +290,255 @@ type PackagesConfig struct {
 	Deb        DebConfig        `yaml:"deb"`
 	RPM        RPMConfig        `yaml:"rpm"`
 	AppImage   AppImageConfig   `yaml:"appimage"`
+	Docker     DockerConfig     `yaml:"docker,omitempty"`
+	DMG        DMGConfig        `yaml:"dmg,omitempty"`
+	MSIX       MSIXConfig       `yaml:"msix,omitempty"`
+	FreeBSD    FreeBSDConfig    `yaml:"freebsd,omitempty"`
+	Termux     TermuxConfig     `yaml:"termux,omitempty"`
+	Xbps       XbpsConfig       `yaml:"xbps,omitempty"`
+	Maven      MavenConfig      `yaml:"maven,omitempty"`
+	Dotnet     DotnetConfig     `yaml:"dotnet,omitempty"`
+	Gem        GemConfig        `yaml:"gem,omitempty"`
+	NPM        NPMConfig        `yaml:"npm,omitempty"`
+	Conda      CondaConfig      `yaml:"conda,omitempty"`
+
+	// Enabled, when set, limits pack --all and publish to these formats;
+	// Disabled excludes formats. Both take format names such as deb or
+	// gh-extension.
+	Enabled  []string `yaml:"enabled,omitempty"`
+	Disabled []string `yaml:"disabled,omitempty"`
+}
+
+// FormatEnabled reports whether pack --all and publish build format
+func (p PackagesConfig) FormatEnabled(format string) bool {
+	for _, disabled := range p.Disabled {
+		if disabled == format {
+			return false
+		}
+	}
+	if len(p.Enabled) == 0 {
+		return true
+	}
+	for _, enabled := range p.Enabled {
+		if enabled == format {
+			return true
+		}
+	}
+	return false
+}
+
+// FreeBSDConfig configures the FreeBSD package and ports skeleton
+type FreeBSDConfig struct {
+	Category   string `yaml:"category,omitempty"`   // ports category, default sysutils
+	OSVersion  string `yaml:"os_version,omitempty"` // major version in the package ABI, default 14
+	Maintainer string `yaml:"maintainer,omitempty"` // port maintainer email, default the first maintainer's
+}
+
+// TermuxConfig configures the termux-packages build.sh and the prebuilt
+// .deb for the Termux prefix
+type TermuxConfig struct {
+	Depends    []string `yaml:"depends,omitempty"`
+	Maintainer string   `yaml:"maintainer,omitempty"` // default @<github.owner>
+}
+
+// XbpsConfig configures the Void Linux xbps-src template
+type XbpsConfig struct {
+	Depends    []string `yaml:"depends,omitempty"`
+	Maintainer string   `yaml:"maintainer,omitempty"` // "Name <email>", default the first maintainer with an email
+	Revision   int      `yaml:"revision,omitempty"`   // default 1
+}
+
+// MavenConfig publishes the binaries as a Maven artifact with one
+// classifier per platform, for JVM builds that resolve and run the tool
+type MavenConfig struct {
+	GroupID      string `yaml:"group_id"`
+	ArtifactID   string `yaml:"artifact_id,omitempty"`   // default name
+	GradlePlugin string `yaml:"gradle_plugin,omitempty"` // plugin ID; generates a Gradle plugin wrapper when set
+}
+
+// DotnetConfig configures the .NET global tool that wraps the binaries
+type DotnetConfig struct {
+	PackageID       string `yaml:"package_id,omitempty"`       // default name
+	ToolCommand     string `yaml:"tool_command,omitempty"`     // default name
+	TargetFramework string `yaml:"target_framework,omitempty"` // default net8.0
+}
+
+// GemConfig configures the Ruby gems that wrap the binaries
+type GemConfig struct {
+	Name string `yaml:"name,omitempty"` // default name
+	Host string `yaml:"host,omitempty"` // gem server to push to; default https://rubygems.org
+}
+
+// NPMConfig configures the npm package that wraps the binaries
+type NPMConfig struct {
+	Scope string `yaml:"scope,omitempty"` // publish as @scope/name
+}
+
+// CondaConfig configures the conda recipe and the package built from it
+type CondaConfig struct {
+	Builder     string   `yaml:"builder,omitempty"`     // rattler-build or conda-build; default whichever is installed
+	Channels    []string `yaml:"channels,omitempty"`    // channels the build resolves from, default conda-forge
+	Depends     []string `yaml:"depends,omitempty"`     // run requirements
+	Maintainers []string `yaml:"maintainers,omitempty"` // recipe-maintainers GitHub handles, default github.owner
 }
 
 type BrewConfig struct {
-	Test string `yaml:"test"`
+	Test          string         `yaml:"test"`
+	ConflictsWith []BrewConflict `yaml:"conflicts_with,omitempty"`
+	KegOnly       string         `yaml:"keg_only,omitempty"`
+	Caveats       string         `yaml:"caveats,omitempty"`
+	Cask          BrewCaskConfig `yaml:"cask,omitempty"`
+
+	// BaseURL serves the formula's downloads from another host than the
+	// release, e.g. a CDN; default installer.base_url. Mirror adds a
+	// fallback host as a mirror stanza.
+	BaseURL          string `yaml:"base_url,omitempty"`
+	Mirror           string `yaml:"mirror,omitempty"`
+	ChecksumComments bool   `yaml:"checksum_comments,omitempty"` // comment how to verify each sha256
+	AnalyticsNotice  bool   `yaml:"analytics_notice,omitempty"`  // tell users in caveats how to opt out of Homebrew analytics
+}
+
+// BrewCaskConfig also writes a Homebrew cask installing the DMG, for GUI
+// applications installed with brew install --cask. It can be written as
+// cask: true or as a mapping, which enables it unless enabled: false.
+type BrewCaskConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Token    string   `yaml:"token,omitempty"`    // default the project name, lower-cased
+	App      string   `yaml:"app,omitempty"`      // app bundle in the DMG; default <desktop name>.app for GUI apps
+	Pkg      string   `yaml:"pkg,omitempty"`      // installer package in the DMG, installed instead of an app
+	PkgID    string   `yaml:"pkg_id,omitempty"`   // package receipt ID removed on uninstall
+	Binaries []string `yaml:"binaries,omitempty"` // executables linked onto PATH; default the app's executable
+	MacOS    string   `yaml:"macos,omitempty"`    // minimum macOS release, e.g. big_sur
+	Zap      []string `yaml:"zap,omitempty"`      // files brew uninstall --zap removes, e.g. ~/Library/Preferences/<id>.plist
+}
+
+func (c *BrewCaskConfig) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&c.Enabled)
+	}
+
+	type plain BrewCaskConfig
+	c.Enabled = true
+	return value.Decode((*plain)(c))
+}
+
+// BrewConflict is a formula the package conflicts with. It can be written
+// as a plain formula name or as a mapping with a reason.
+type BrewConflict struct {
+	Name    string `yaml:"name"`
+	Because string `yaml:"because,omitempty"`
+}
+
+func (c *BrewConflict) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		c.Name = value.Value
+		return nil
+	}
+
+	type plain BrewConflict
+	return value.Decode((*plain)(c))
 }
 
 type ScoopConfig struct {
 	Bin       string     `yaml:"bin"`
 	Shortcuts [][]string `yaml:"shortcuts"`
+
+	BaseURL          string `yaml:"base_url,omitempty"`          // host of the download, default installer.base_url
+	ChecksumComments bool   `yaml:"checksum_comments,omitempty"` // explain how to verify the hash in a ## comment
 }
 
 type ChocolateyConfig struct {
 	PackageSourceURL string `yaml:"package_source_url"`
 	DocsURL          string `yaml:"docs_url"`
+	IconURL          string `yaml:"icon_url,omitempty"`
+	Icon             string `yaml:"icon,omitempty"`         // PNG or SVG embedded in the package
+	LicenseFile      string `yaml:"license_file,omitempty"` // default: LICENSE, LICENSE.txt, LICENSE.md or COPYING
 }
 
 type WingetPkgConfig struct {
 	PackageIdentifier string `yaml:"package_identifier"`
 	Publisher         string `yaml:"publisher"`
 	MinimumOSVersion  string `yaml:"minimum_os_version"`
+	InstallerType     string `yaml:"installer_type,omitempty"`
+	ProductCode       string `yaml:"product_code,omitempty"`
+	PackageFamilyName string `yaml:"package_family_name,omitempty"`
+	UpgradeBehavior   string `yaml:"upgrade_behavior,omitempty"`
+	Scope             string `yaml:"scope,omitempty"`
 }
 
 type DebConfig struct {
-	Maintainer string `yaml:"maintainer"`
-	Section    string `yaml:"section"`
-	Priority   string `yaml:"priority"`
+	Maintainer   string              `yaml:"maintainer"`
+	Section      string              `yaml:"section"`
+	Priority     string              `yaml:"priority"`
+	Alternatives []AlternativeConfig `yaml:"alternatives,omitempty"`
+	PPA          PPAConfig           `yaml:"ppa,omitempty"`
+}
+
+// PPAConfig uploads source packages to a Launchpad PPA, one per Ubuntu
+// series since Launchpad builds each upload for a single series
+type PPAConfig struct {
+	Target        string   `yaml:"target"`             // e.g. ppa:yourname/myapp
+	Distributions []string `yaml:"distributions"`      // Ubuntu series, e.g. noble, jammy
+	Revision      string   `yaml:"revision,omitempty"` // Debian revision, default 1
+	KeyID         string   `yaml:"key_id,omitempty"`   // debsign key, default signing.linux.gpg_key_id or GPG_KEY_ID
 }
 
 type RPMConfig struct {
-	Group  string `yaml:"group"`
-	Vendor string `yaml:"vendor"`
+	Group        string              `yaml:"group"`
+	Vendor       string              `yaml:"vendor"`
+	Alternatives []AlternativeConfig `yaml:"alternatives,omitempty"`
+	COPR         COPRConfig          `yaml:"copr,omitempty"`
+}
+
+// COPRConfig builds the RPM for Fedora in a COPR project, which users enable
+// with dnf copr enable
+type COPRConfig struct {
+	Project     string   `yaml:"project"`               // owner/name
+	Chroots     []string `yaml:"chroots"`               // e.g. fedora-40-x86_64
+	Source      string   `yaml:"source,omitempty"`      // srpm (default) uploads the SRPM; release builds from the SRPM attached to the GitHub release
+	AutoCreate  bool     `yaml:"auto_create,omitempty"` // create the project when it does not exist
+	Description string   `yaml:"description,omitempty"`
+}
+
+// AlternativeConfig registers the installed binary with update-alternatives
+// so several implementations of a command can coexist
+type AlternativeConfig struct {
+	Link     string `yaml:"link"`           // e.g. /usr/bin/tool
+	Name     string `yaml:"name,omitempty"` // link group, defaults to the link base name
+	Priority int    `yaml:"priority"`
+}
+
+// DMGConfig controls the Gatekeeper assessment run after DMG creation
+type DMGConfig struct {
+	SkipGatekeeperCheck bool `yaml:"skip_gatekeeper_check,omitempty"`
+	FailOnGatekeeper    bool `yaml:"fail_on_gatekeeper,omitempty"`
+}
+
+// MSIXConfig sets the MSIX package identity reserved in Partner Center and
+// how releases are submitted to the Microsoft Store
+type MSIXConfig struct {
+	IdentityName         string          `yaml:"identity_name,omitempty"`          // Package/Identity/Name
+	Publisher            string          `yaml:"publisher,omitempty"`              // Package/Identity/Publisher, e.g. CN=1A2B3C4D-...
+	PublisherDisplayName string          `yaml:"publisher_display_name,omitempty"` // default the primary author
+	PackageFamilyName    string          `yaml:"package_family_name,omitempty"`    // checked against identity_name and publisher
+	Store                MSIXStoreConfig `yaml:"store,omitempty"`
+}
+
+// MSIXStoreConfig submits the MSIX to a Store product, either to its public
+// listing or to a package flight
+type MSIXStoreConfig struct {
+	ProductID string             `yaml:"product_id,omitempty"`
+	Flight    string             `yaml:"flight,omitempty"`  // name of the flight to submit to; empty for the public listing
+	Flights   []MSIXFlightConfig `yaml:"flights,omitempty"`
+	Rollout   float64            `yaml:"rollout,omitempty"` // gradual rollout percentage, 0 for all users
+}
+
+// MSIXFlightConfig is a package flight targeting Partner Center flight
+// groups, such as insider rings
+type MSIXFlightConfig struct {
+	Name   string   `yaml:"name"`
+	ID     string   `yaml:"id,omitempty"` // flight ID; the flight is created when empty
+	Groups []string `yaml:"groups"`       // flight group IDs
 }
 
 type AppImageConfig struct {
@@ -137,16 +552,35 @@ type AppImageDesktopConfig struct {
 	Type     string `yaml:"type"`
 }
 
+// DockerConfig lists the registries an image is pushed to and how old
+// prerelease tags are pruned
+type DockerConfig struct {
+	Registries []string              `yaml:"registries,omitempty"`
+	Retention  DockerRetentionConfig `yaml:"retention,omitempty"`
+}
+
+type DockerRetentionConfig struct {
+	KeepPrereleases int `yaml:"keep_prereleases"`
+}
+
+// Load reads the config at path, merged over the fragments it includes
 func Load(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	doc, err := loadDocument(path, "", nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, err
 	}
 
 	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	if err := doc.Decode(&config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
+	config.unknownPackages = unknownKeys(mappingValue(doc, "packages"), yamlKeys(PackagesConfig{}))
+
+	if config.Version == VersionAuto {
+		if err := config.resolveVersion(filepath.Dir(path)); err != nil {
+			return nil, err
+		}
+	}
 
 	return &config, nil
 }
@@ -158,16 +592,131 @@ func (c *Config) Validate() error {
 	if c.Version == "" {
 		return fmt.Errorf("version is required")
 	}
-	if len(c.Binaries) == 0 {
+	if len(c.Binaries) == 0 && len(c.Prebuilt.Archives) == 0 && len(c.Builds) == 0 {
 		return fmt.Errorf("at least one binary is required")
 	}
+	if err := validateBuilds(c.Builds); err != nil {
+		return err
+	}
+	if err := c.Hooks.validate(); err != nil {
+		return err
+	}
+	if err := c.GitLab.validate(); err != nil {
+		return err
+	}
+	if c.GitLab.Release.Assets == "links" && c.Installer.BaseURL == "" {
+		return fmt.Errorf("gitlab.release.assets: links requires installer.base_url")
+	}
+	switch c.GitHub.ManifestPRs.MergeMethod {
+	case "", "merge", "squash", "rebase":
+	default:
+		return fmt.Errorf("github.manifest_prs.merge_method: unknown method %q (merge, squash or rebase)", c.GitHub.ManifestPRs.MergeMethod)
+	}
+	for _, format := range c.SBOM.Formats {
+		if format != "spdx" && format != "cyclonedx" {
+			return fmt.Errorf("sbom.formats: unknown format %q (spdx or cyclonedx)", format)
+		}
+	}
+	if c.Desktop != nil {
+		if err := c.Desktop.validate(); err != nil {
+			return err
+		}
+	}
+	if _, err := c.tagPattern(); err != nil {
+		return err
+	}
+	if len(c.unknownPackages) > 0 {
+		return unknownKeyError("packages", c.unknownPackages[0], yamlKeys(PackagesConfig{}))
+	}
+	if c.License != "" {
+		if _, err := spdx.Parse(c.License); err != nil {
+			return fmt.Errorf("license must be an SPDX expression: %w", err)
+		}
+	}
+	for i, author := range c.Authors {
+		if author.Name == "" {
+			return fmt.Errorf("authors[%d]: name is required", i)
+		}
+		switch author.Role {
+		case "", RoleAuthor, RoleMaintainer, RoleContributor:
+		default:
+			return fmt.Errorf("authors[%d]: unknown role %q (use author, maintainer or contributor)", i, author.Role)
+		}
+	}
+	for i, file := range c.Files {
+		if file.Src == "" {
+			return fmt.Errorf("files[%d]: src is required", i)
+		}
+		dsts := []string{file.Dst}
+		for _, dst := range file.Formats {
+			dsts = append(dsts, dst)
+		}
+		for _, dst := range dsts {
+			if clean := path.Clean(strings.TrimPrefix(dst, "/")); clean == ".." || strings.HasPrefix(clean, "../") {
+				return fmt.Errorf("files[%d]: %s must not leave the install directory", i, dst)
+			}
+		}
+	}
+	for name, tool := range c.Dependencies.Tools {
+		for platform, download := range tool.Platforms {
+			if download.URL == "" || len(download.SHA256) != 64 {
+				return fmt.Errorf("dependencies.tools.%s.%s: url and a sha256 digest are required", name, platform)
+			}
+			if download.Bundle != "" && tool.CertificateIdentity == "" {
+				return fmt.Errorf("dependencies.tools.%s.%s: a bundle requires certificate_identity and certificate_oidc_issuer", name, platform)
+			}
+		}
+		if (tool.CertificateIdentity == "") != (tool.CertificateOIDCIssuer == "") {
+			return fmt.Errorf("dependencies.tools.%s: set both certificate_identity and certificate_oidc_issuer", name)
+		}
+	}
+	switch c.Signing.Minisign.Tool {
+	case "", "minisign", "signify":
+	default:
+		return fmt.Errorf("signing.minisign.tool must be minisign or signify")
+	}
+	if c.Signing.SSH.Enabled && c.Signing.Minisign.Enabled && c.Signing.Minisign.Tool == "signify" {
+		return fmt.Errorf("signing.ssh and signify both write .sig signatures - enable only one")
+	}
+	perf := c.Performance
+	if perf.MaxParallelPackagers < 0 || perf.MaxParallelUploads < 0 {
+		return fmt.Errorf("performance: parallelism cannot be negative")
+	}
+	if perf.Nice < 0 || perf.Nice > 19 {
+		return fmt.Errorf("performance.nice must be between 0 and 19")
+	}
+	if _, err := ParseBandwidth(perf.BandwidthLimit); err != nil {
+		return fmt.Errorf("performance.bandwidth_limit: %w", err)
+	}
+	for i, dep := range c.Dependencies.Bagboy {
+		if dep.Name == "" {
+			return fmt.Errorf("dependencies.bagboy[%d]: name is required", i)
+		}
+		if _, err := dep.Constraint(); err != nil {
+			return fmt.Errorf("dependencies.bagboy[%d]: %w", i, err)
+		}
+	}
+	if hook := c.Audit.Webhook; hook != "" && !strings.HasPrefix(hook, "https://") && !strings.HasPrefix(hook, "http://") {
+		return fmt.Errorf("audit.webhook must be an http or https URL")
+	}
+	if c.Encryption.Enabled {
+		if manifests := c.manifestChannels(); len(manifests) > 0 {
+			return fmt.Errorf("encryption cannot be combined with %s - their manifests would point users at encrypted assets", strings.Join(manifests, ", "))
+		}
+	}
 	return nil
 }
 
 func FindConfigFile() (string, error) {
+	return FindConfigIn(".")
+}
+
+// FindConfigIn returns the absolute path of the bagboy config file in dir
+func FindConfigIn(dir string) (string, error) {
 	candidates := []string{"bagboy.yaml", "bagboy.yml", ".bagboy.yaml", ".bagboy.yml"}
 
-	for _, candidate := range candidates {
+	for _, name := range candidates {
+		candidate := filepath.Join(dir, name)
 		if _, err := os.Stat(candidate); err == nil {
 			abs, err := filepath.Abs(candidate)
 			if err != nil {
@@ -187,6 +736,12 @@ type SigningConfig struct {
 	Sigstore SigstoreConfig       `yaml:"sigstore"`
 	SignPath SignPathConfig       `yaml:"signpath"`
 	Git      GitSigningConfig     `yaml:"git"`
+	Keys     KeysConfig           `yaml:"keys,omitempty"`
+	Minisign MinisignConfig       `yaml:"minisign,omitempty"`
+	SSH      SSHSigningConfig     `yaml:"ssh,omitempty"`
+
+	// Checksums signs the checksums.txt every release carries
+	Checksums ChecksumSigningConfig `yaml:"checksums,omitempty"`
 }
 
 // DependenciesConfig represents dependency configuration
@@ -194,6 +749,105 @@ type DependenciesConfig struct {
 	System          map[string][]string `yaml:"system,omitempty"`
 	PackageManagers map[string][]string `yaml:"package_managers,omitempty"`
 	Runtime         map[string]string   `yaml:"runtime,omitempty"`
+	Tools           map[string]ToolConfig `yaml:"tools,omitempty"`
+	Bagboy          []ToolDependency      `yaml:"bagboy,omitempty"` // other tools released with bagboy that this one needs
+}
+
+// ToolDependency is another tool released with bagboy that this one
+// requires, encoded in the deb, rpm and brew packages and checked by deps
+// check
+type ToolDependency struct {
+	Name    string `yaml:"name"`              // package name, e.g. helper
+	Version string `yaml:"version,omitempty"` // constraint such as ">=1.2.0" or ">=1.2, <2"
+	Tap     string `yaml:"tap,omitempty"`     // Homebrew tap repository it is in, owner/repo; default this project's tap
+}
+
+// Constraint parses the version constraint
+func (d ToolDependency) Constraint() (semver.Constraint, error) {
+	return semver.ParseConstraint(d.Version)
+}
+
+// ToolConfig pins a tool binary that deps install downloads, such as
+// appimagetool or cosign. Downloads are verified before they are installed.
+type ToolConfig struct {
+	Platforms             map[string]ToolDownload `yaml:"platforms"`                         // e.g. linux-amd64
+	CertificateIdentity   string                  `yaml:"certificate_identity,omitempty"`    // expected signer of the bundle
+	CertificateOIDCIssuer string                  `yaml:"certificate_oidc_issuer,omitempty"` // e.g. https://token.actions.githubusercontent.com
+}
+
+// ToolDownload is a pinned download of a tool for one platform
+type ToolDownload struct {
+	URL    string `yaml:"url"`
+	SHA256 string `yaml:"sha256"`
+	Bundle string `yaml:"bundle,omitempty"` // sigstore bundle URL verified with cosign
+}
+
+// DeltaConfig controls generation of differential update artifacts
+// (MSP patches and deltarpms) against a previous release.
+type DeltaConfig struct {
+	Enabled         bool     `yaml:"enabled"`
+	PreviousDir     string   `yaml:"previous_dir"`
+	PreviousVersion string   `yaml:"previous_version"`
+	Formats         []string `yaml:"formats,omitempty"`
+}
+
+// EncryptionConfig controls encryption of release assets for private
+// distribution to a fixed set of recipients. Public package manager
+// manifests cannot install encrypted assets, so it excludes the Homebrew
+// tap, Scoop bucket and Winget.
+type EncryptionConfig struct {
+	Enabled    bool     `yaml:"enabled"`
+	Method     string   `yaml:"method"` // age (default) or gpg
+	Recipients []string `yaml:"recipients"`
+}
+
+// manifestChannels returns the enabled channels that publish package
+// manager manifests for the release assets
+func (c *Config) manifestChannels() []string {
+	var channels []string
+	if c.GitHub.Tap.Enabled {
+		channels = append(channels, "github.tap")
+	}
+	if c.GitHub.Bucket.Enabled {
+		channels = append(channels, "github.bucket")
+	}
+	if c.GitHub.Winget.Enabled {
+		channels = append(channels, "github.winget")
+	}
+	if c.GitLab.Tap.Enabled {
+		channels = append(channels, "gitlab.tap")
+	}
+	if c.GitLab.Bucket.Enabled {
+		channels = append(channels, "gitlab.bucket")
+	}
+	return channels
+}
+
+// PolicyConfig defines the compliance gate evaluated before publish.
+// Built-in rules cover common checks; rego policies are evaluated with the
+// opa CLI for anything else.
+type PolicyConfig struct {
+	RequireSigned   bool             `yaml:"require_signed,omitempty"`
+	VersionIncrease bool             `yaml:"version_increase,omitempty"`
+	AllowedLicenses []string         `yaml:"allowed_licenses,omitempty"`
+	NoCriticalCVEs  bool             `yaml:"no_critical_cves,omitempty"`
+	Rego            RegoPolicyConfig `yaml:"rego,omitempty"`
+	Secrets         SecretsConfig    `yaml:"secrets,omitempty"`
+}
+
+// SecretsConfig tunes the scan for leaked credentials that runs on
+// generated files before publish. The scan is on unless disabled.
+type SecretsConfig struct {
+	Disabled bool     `yaml:"disabled,omitempty"`
+	Allow    []string `yaml:"allow,omitempty"`  // regexps for matches that are not secrets
+	Ignore   []string `yaml:"ignore,omitempty"` // globs for files not to scan, matched against path and base name
+}
+
+// RegoPolicyConfig points at OPA policies. The query must evaluate to a
+// set or list of violation messages; empty means the release is allowed.
+type RegoPolicyConfig struct {
+	Files []string `yaml:"files,omitempty"`
+	Query string   `yaml:"query,omitempty"` // default data.bagboy.deny
 }
 
 type MacOSSigningConfig struct {
@@ -232,3 +886,398 @@ type GitSigningConfig struct {
 	SignTags  bool   `yaml:"sign_tags"`
 	SignCommits bool `yaml:"sign_commits"`
 }
+
+// KeysConfig configures the release signing key managed by bagboy keys
+type KeysConfig struct {
+	Name       string   `yaml:"name,omitempty"`       // default "<name> release signing key"
+	Email      string   `yaml:"email,omitempty"`      // default the first maintainer's email
+	Expire     string   `yaml:"expire,omitempty"`     // gpg expiry such as 2y (default)
+	Overlap    string   `yaml:"overlap,omitempty"`    // how long a rotated key stays published, default 90d
+	Dir        string   `yaml:"dir,omitempty"`        // where public keys are exported, default keys
+	Keyservers []string `yaml:"keyservers,omitempty"` // default keys.openpgp.org and keyserver.ubuntu.com
+}
+
+// MinisignConfig signs each release's checksums, and optionally every
+// artifact, with minisign or OpenBSD signify as a lightweight alternative
+// to GPG. BAGBOY_MINISIGN_SECRET_KEY may hold the secret key itself, and
+// BAGBOY_MINISIGN_PASSWORD its password, for CI.
+type MinisignConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Tool      string `yaml:"tool,omitempty"`       // minisign (default) or signify
+	SecretKey string `yaml:"secret_key,omitempty"` // default ~/.minisign/<name>.key, or ~/.signify/<name>.sec
+	PublicKey string `yaml:"public_key,omitempty"` // default <keys dir>/<name>.pub; published with every release
+	Artifacts bool   `yaml:"artifacts,omitempty"`  // sign every artifact, not only checksums.txt
+}
+
+// SSHSigningConfig signs checksums.txt, artifacts and the release tag
+// with an existing SSH key via ssh-keygen -Y, which GitHub verifies for
+// tags without any GPG setup. BAGBOY_SSH_SIGNING_KEY may hold the private
+// key itself for CI.
+type SSHSigningConfig struct {
+	Enabled        bool   `yaml:"enabled"`
+	Key            string `yaml:"key,omitempty"`             // default ~/.ssh/id_ed25519, then id_ecdsa, then id_rsa
+	Identity       string `yaml:"identity,omitempty"`        // allowed_signers principal, default the first maintainer's email
+	AllowedSigners string `yaml:"allowed_signers,omitempty"` // default <keys dir>/allowed_signers; published with every release
+	Artifacts      bool   `yaml:"artifacts,omitempty"`       // sign every artifact, not only checksums.txt
+	Tags           bool   `yaml:"tags,omitempty"`            // create and push a signed release tag
+}
+
+// ChecksumSigningConfig signs checksums.txt with GPG, cosign or both, so
+// one signature covers every asset
+type ChecksumSigningConfig struct {
+	GPG    bool `yaml:"gpg,omitempty"`    // checksums.txt.asc, with signing.linux.gpg_key_id or GPG_KEY_ID
+	Cosign bool `yaml:"cosign,omitempty"` // checksums.txt.sigstore.bundle, with the signing.sigstore settings
+}
+
+// PrebuiltConfig packs from archives built by another tool instead of
+// raw binaries. Archives maps a platform such as linux-amd64 to a glob
+// matched in Dir.
+type PrebuiltConfig struct {
+	Dir      string            `yaml:"dir,omitempty"`    // default dist
+	Archives map[string]string `yaml:"archives"`         // e.g. linux-amd64: "*_linux_amd64.tar.gz"
+	Binary   string            `yaml:"binary,omitempty"` // path inside the archive; default the project name
+}
+
+// DesktopConfig integrates a GUI application with the desktop: a menu
+// entry, an optional desktop shortcut, an icon, and the file types and URL
+// schemes it opens. Each format maps it to its native mechanism: desktop
+// files on Linux, shortcuts and registry entries in msi, manifest
+// extensions in msix and an app bundle in dmg.
+type DesktopConfig struct {
+	Name             string                  `yaml:"name,omitempty"`             // display name, default the project name
+	ID               string                  `yaml:"id,omitempty"`               // reverse-DNS application ID, default dev.bagboy.<name>
+	Icon             string                  `yaml:"icon,omitempty"`             // PNG, at least 256x256; SVG is used on Linux only, ICO in msi and ICNS in dmg
+	Categories       []string                `yaml:"categories,omitempty"`       // freedesktop categories, e.g. Development
+	Terminal         bool                    `yaml:"terminal,omitempty"`         // runs in a terminal
+	DesktopShortcut  bool                    `yaml:"desktop_shortcut,omitempty"` // also put a shortcut on the desktop where the format can
+	FileAssociations []FileAssociationConfig `yaml:"file_associations,omitempty"`
+	Protocols        []string                `yaml:"protocols,omitempty"` // URL schemes, e.g. myapp for myapp:// links
+}
+
+// FileAssociationConfig is a file type the application opens
+type FileAssociationConfig struct {
+	Extension   string `yaml:"extension"`             // e.g. mydoc, without the dot
+	Description string `yaml:"description,omitempty"` // e.g. MyApp Document
+	MimeType    string `yaml:"mime_type,omitempty"`   // default application/x-<extension>
+}
+
+var urlScheme = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+
+func (d *DesktopConfig) validate() error {
+	switch ext := strings.ToLower(filepath.Ext(d.Icon)); {
+	case d.Icon == "", ext == ".png", ext == ".svg", ext == ".ico", ext == ".icns":
+	default:
+		return fmt.Errorf("desktop.icon must be a PNG, SVG, ICO or ICNS file")
+	}
+	for i, assoc := range d.FileAssociations {
+		if ext := strings.TrimPrefix(assoc.Extension, "."); ext == "" || strings.ContainsAny(ext, "./\\ ") {
+			return fmt.Errorf("desktop.file_associations[%d]: invalid extension %q", i, assoc.Extension)
+		}
+	}
+	for _, scheme := range d.Protocols {
+		if !urlScheme.MatchString(scheme) {
+			return fmt.Errorf("desktop.protocols: %q is not a URL scheme (lowercase letters, digits, +, - and .)", scheme)
+		}
+	}
+	return nil
+}
+
+// BuildConfig cross-compiles a Go main package with go build for each
+// target before packaging. The binaries fill in binaries for platforms it
+// does not list.
+type BuildConfig struct {
+	Main    string   `yaml:"main,omitempty"`    // package to build, default .
+	Dir     string   `yaml:"dir,omitempty"`     // directory go build runs in, default .
+	Binary  string   `yaml:"binary,omitempty"`  // output name, default the project name
+	Goos    []string `yaml:"goos,omitempty"`    // default linux, darwin and windows
+	Goarch  []string `yaml:"goarch,omitempty"`  // default amd64 and arm64
+	Ignore  []string `yaml:"ignore,omitempty"`  // targets not to build, e.g. windows-arm64
+	Ldflags []string `yaml:"ldflags,omitempty"` // templates with {{.Version}}, {{.Commit}}, {{.Date}} and {{env "NAME"}}; default sets main.version, main.commit and main.date
+	Flags   []string `yaml:"flags,omitempty"`   // extra go build flags, e.g. -trimpath
+	Tags    []string `yaml:"tags,omitempty"`    // build tags
+	Env     []string `yaml:"env,omitempty"`     // e.g. CGO_ENABLED=0
+
+	// Embeds lists the directories of other bagboy projects, relative to
+	// this config, whose versions ldflags read as {{index .Versions "name"}}.
+	// publish --recursive releases them first.
+	Embeds []string `yaml:"embeds,omitempty"`
+}
+
+// Targets returns the platforms the build produces, such as linux-amd64,
+// in order
+func (b BuildConfig) Targets() []string {
+	goos, goarch := b.Goos, b.Goarch
+	if len(goos) == 0 {
+		goos = []string{"linux", "darwin", "windows"}
+	}
+	if len(goarch) == 0 {
+		goarch = []string{"amd64", "arm64"}
+	}
+	var targets []string
+	for _, system := range goos {
+		for _, arch := range goarch {
+			if target := system + "-" + arch; !slices.Contains(b.Ignore, target) {
+				targets = append(targets, target)
+			}
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// validateBuilds checks that no two builds produce the same target, since
+// each platform has one binary
+func validateBuilds(builds []BuildConfig) error {
+	built := make(map[string]int)
+	for i, build := range builds {
+		for _, env := range build.Env {
+			if !strings.Contains(env, "=") {
+				return fmt.Errorf("builds[%d]: env %q must be KEY=value", i, env)
+			}
+		}
+		targets := build.Targets()
+		if len(targets) == 0 {
+			return fmt.Errorf("builds[%d]: every target is ignored", i)
+		}
+		for _, target := range targets {
+			if j, ok := built[target]; ok {
+				return fmt.Errorf("builds[%d]: %s is already built by builds[%d]", i, target, j)
+			}
+			built[target] = i
+		}
+	}
+	return nil
+}
+
+// HooksConfig runs shell commands before and after the pack and publish
+// stages and around each format. Commands see NAME, VERSION and
+// OUTPUT_PATH in their environment, and format hooks FORMAT as well.
+type HooksConfig struct {
+	BeforePack    []string               `yaml:"before_pack,omitempty"`    // after binaries are built, before any format
+	AfterPack     []string               `yaml:"after_pack,omitempty"`     // once every format is packaged
+	BeforePublish []string               `yaml:"before_publish,omitempty"` // after preflight checks, before packaging
+	AfterPublish  []string               `yaml:"after_publish,omitempty"`  // once the release is published
+	Formats       map[string]FormatHooks `yaml:"formats,omitempty"`        // keyed by format, e.g. deb
+}
+
+// FormatHooks run around one format. After hooks run before checksums are
+// taken, so they may change the package at OUTPUT_PATH.
+type FormatHooks struct {
+	Before []string `yaml:"before,omitempty"`
+	After  []string `yaml:"after,omitempty"`
+}
+
+// Stage returns the commands for a stage such as before_pack
+func (h HooksConfig) Stage(stage string) []string {
+	switch stage {
+	case "before_pack":
+		return h.BeforePack
+	case "after_pack":
+		return h.AfterPack
+	case "before_publish":
+		return h.BeforePublish
+	case "after_publish":
+		return h.AfterPublish
+	}
+	return nil
+}
+
+func (h HooksConfig) validate() error {
+	stages := map[string][]string{
+		"before_pack":    h.BeforePack,
+		"after_pack":     h.AfterPack,
+		"before_publish": h.BeforePublish,
+		"after_publish":  h.AfterPublish,
+	}
+	for format, hooks := range h.Formats {
+		stages["formats."+format+".before"] = hooks.Before
+		stages["formats."+format+".after"] = hooks.After
+	}
+	names := make([]string, 0, len(stages))
+	for stage := range stages {
+		names = append(names, stage)
+	}
+	sort.Strings(names)
+	for _, stage := range names {
+		for _, command := range stages[stage] {
+			if strings.TrimSpace(command) == "" {
+				return fmt.Errorf("hooks.%s: empty command", stage)
+			}
+		}
+	}
+	return nil
+}
+
+// SBOMConfig writes an SBOM for every binary and package, embeds one in
+// deb and rpm packages and docker images, and publishes them with the
+// release
+type SBOMConfig struct {
+	Enabled bool     `yaml:"enabled"`
+	Formats []string `yaml:"formats,omitempty"` // spdx and cyclonedx (default both)
+}
+
+// BuildEnvConfig publishes a manifest of the build machine, its tool
+// versions and build-relevant environment variables with the release
+type BuildEnvConfig struct {
+	Enabled bool     `yaml:"enabled"`
+	Env     []string `yaml:"env,omitempty"` // more variables to record; NAME or PREFIX*
+}
+
+// GoModuleConfig enables checking that a release of a Go project installs
+// with go install module@version and reports the released version
+type GoModuleConfig struct {
+	Enabled     bool     `yaml:"enabled"`
+	Dir         string   `yaml:"dir,omitempty"`          // directory holding go.mod, relative to the repository root; default .
+	Package     string   `yaml:"package,omitempty"`      // main package within the module, e.g. cmd/myapp
+	VersionArgs []string `yaml:"version_args,omitempty"` // arguments that print the version; default --version
+}
+
+// PreserveConfig controls what packagers keep when they copy files into
+// a package. File modes and symlinks are always kept.
+type PreserveConfig struct {
+	Timestamps     bool `yaml:"timestamps,omitempty"`      // keep modification times instead of the time of the copy
+	FollowSymlinks bool `yaml:"follow_symlinks,omitempty"` // copy what symlinks point at instead of the links
+}
+
+// PerformanceConfig limits how much of the machine bagboy uses, so it
+// can share CI runners. Zero values mean no limit.
+type PerformanceConfig struct {
+	MaxParallelPackagers int    `yaml:"max_parallel_packagers,omitempty"` // default one per CPU
+	MaxParallelUploads   int    `yaml:"max_parallel_uploads,omitempty"`   // default 1
+	BandwidthLimit       string `yaml:"bandwidth_limit,omitempty"`        // total upload rate, e.g. 10MB/s
+	Nice                 int    `yaml:"nice,omitempty"`                   // scheduling niceness for bagboy and the tools it runs, 1-19
+}
+
+// AuditConfig records every change bagboy makes to a remote service -
+// releases, uploads, commits, pull requests and pushed images - for
+// reconstructing what happened after an incident
+type AuditConfig struct {
+	Enabled          bool   `yaml:"enabled"`
+	Path             string `yaml:"path,omitempty"`               // JSON Lines file appended to, default .bagboy/audit.jsonl
+	Webhook          string `yaml:"webhook,omitempty"`            // also POST each event here
+	WebhookSecretEnv string `yaml:"webhook_secret_env,omitempty"` // signs webhook bodies with HMAC-SHA256 using this variable
+}
+
+// ParseBandwidth parses a rate such as 10MB/s, 512KiB/s or 1.5M into
+// bytes per second. Decimal and binary units are accepted; "" is 0.
+func ParseBandwidth(s string) (int64, error) {
+	value := strings.TrimSuffix(strings.TrimSpace(s), "/s")
+	if value == "" {
+		return 0, nil
+	}
+	i := strings.IndexFunc(value, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	number, unit := value, ""
+	if i >= 0 {
+		number, unit = value[:i], strings.TrimSpace(value[i:])
+	}
+	multipliers := map[string]float64{
+		"": 1, "B": 1,
+		"K": 1e3, "KB": 1e3, "KiB": 1 << 10,
+		"M": 1e6, "MB": 1e6, "MiB": 1 << 20,
+		"G": 1e9, "GB": 1e9, "GiB": 1 << 30,
+	}
+	multiplier, ok := multipliers[unit]
+	n, err := strconv.ParseFloat(number, 64)
+	if !ok || err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid bandwidth %q (use e.g. 10MB/s)", s)
+	}
+	return int64(n * multiplier), nil
+}
+
+// Author roles
+const (
+	RoleAuthor      = "author"
+	RoleMaintainer  = "maintainer"
+	RoleContributor = "contributor"
+)
+
+// AuthorConfig is one person credited in package metadata. Role defaults
+// to author.
+type AuthorConfig struct {
+	Name  string `yaml:"name"`
+	Email string `yaml:"email,omitempty"`
+	Role  string `yaml:"role,omitempty"` // author, maintainer or contributor
+}
+
+// String formats the entry as "Name <email>"
+func (a AuthorConfig) String() string {
+	if a.Email == "" {
+		return a.Name
+	}
+	return fmt.Sprintf("%s <%s>", a.Name, a.Email)
+}
+
+// ParseAuthor splits a "Name <email>" string
+func ParseAuthor(s string) AuthorConfig {
+	s = strings.TrimSpace(s)
+	start, end := strings.Index(s, "<"), strings.LastIndex(s, ">")
+	if start < 0 || end < start {
+		return AuthorConfig{Name: s, Role: RoleAuthor}
+	}
+	return AuthorConfig{
+		Name:  strings.TrimSpace(s[:start]),
+		Email: strings.TrimSpace(s[start+1 : end]),
+		Role:  RoleAuthor,
+	}
+}
+
+// People returns the configured authors with roles filled in. The single
+// author string is used when no authors are listed.
+func (c *Config) People() []AuthorConfig {
+	if len(c.Authors) == 0 {
+		if c.Author == "" {
+			return nil
+		}
+		return []AuthorConfig{ParseAuthor(c.Author)}
+	}
+
+	people := make([]AuthorConfig, len(c.Authors))
+	for i, author := range c.Authors {
+		if author.Role == "" {
+			author.Role = RoleAuthor
+		}
+		people[i] = author
+	}
+	return people
+}
+
+// PeopleWithRole returns the people with the given role
+func (c *Config) PeopleWithRole(role string) []AuthorConfig {
+	var matched []AuthorConfig
+	for _, person := range c.People() {
+		if person.Role == role {
+			matched = append(matched, person)
+		}
+	}
+	return matched
+}
+
+// Maintainers returns the maintainers, falling back to the authors when
+// nobody has the maintainer role
+func (c *Config) Maintainers() []AuthorConfig {
+	if maintainers := c.PeopleWithRole(RoleMaintainer); len(maintainers) > 0 {
+		return maintainers
+	}
+	return c.PeopleWithRole(RoleAuthor)
+}
+
+// PrimaryAuthor returns the first author, or the first person listed when
+// nobody has the author role
+func (c *Config) PrimaryAuthor() AuthorConfig {
+	if authors := c.PeopleWithRole(RoleAuthor); len(authors) > 0 {
+		return authors[0]
+	}
+	if people := c.People(); len(people) > 0 {
+		return people[0]
+	}
+	return AuthorConfig{}
+}
+
+// AuthorNames returns the names of people, in order
+func AuthorNames(people []AuthorConfig) []string {
+	names := make([]string, len(people))
+	for i, person := range people {
+		names[i] = person.Name
+	}
+	return names
+}
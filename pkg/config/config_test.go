@@ -17,9 +17,18 @@ limitations under the License.
 package config
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/semver"
+	"gopkg.in/yaml.v3"
 )
 
 func TestConfigValidation(t *testing.T) {
@@ -61,6 +70,26 @@ func TestConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "file without src",
+			config: &Config{
+				Name:     "test",
+				Version:  "1.0.0",
+				Binaries: map[string]string{"linux-amd64": "test"},
+				Files:    []FileConfig{{Dst: "share/test"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "file leaving the install directory",
+			config: &Config{
+				Name:     "test",
+				Version:  "1.0.0",
+				Binaries: map[string]string{"linux-amd64": "test"},
+				Files:    []FileConfig{{Src: "examples", Formats: map[string]string{"msi": "../examples"}}},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -122,6 +151,128 @@ binaries:
 	}
 }
 
+func TestLoadInclude(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	write("org/signing.yaml", `signing:
+  macos:
+    identity: "Developer ID Application: Example Org"
+    notarize: true
+`)
+	write("org/defaults.yaml", `include: signing.yaml
+github:
+  owner: example-org
+  token_env: ORG_TOKEN
+  release:
+    enabled: true
+    draft: true
+license: Apache-2.0
+`)
+	configPath := write("project/bagboy.yaml", `include:
+  - ../org/defaults.yaml
+name: tool
+version: 1.0
+github:
+  repo: tool
+  release:
+    draft: false
+binaries:
+  linux-amd64: tool
+`)
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Version != "1.0" {
+		t.Errorf("Expected version '1.0', got %s", cfg.Version)
+	}
+	if cfg.GitHub.Owner != "example-org" || cfg.GitHub.Repo != "tool" || cfg.GitHub.TokenEnv != "ORG_TOKEN" {
+		t.Errorf("Expected github settings merged from both files, got %+v", cfg.GitHub)
+	}
+	if !cfg.GitHub.Release.Enabled || cfg.GitHub.Release.Draft {
+		t.Errorf("Expected the project to override release.draft only, got %+v", cfg.GitHub.Release)
+	}
+	if cfg.Signing.MacOS.Identity != "Developer ID Application: Example Org" {
+		t.Errorf("Expected nested include to set the signing identity, got %q", cfg.Signing.MacOS.Identity)
+	}
+	if cfg.License != "Apache-2.0" {
+		t.Errorf("Expected license from the include, got %q", cfg.License)
+	}
+
+	write("a.yaml", "include: b.yaml\n")
+	write("b.yaml", "include: a.yaml\n")
+	if _, err := Load(filepath.Join(tmpDir, "a.yaml")); err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Errorf("Expected an include cycle error, got %v", err)
+	}
+
+	missing := write("missing.yaml", "include: nowhere.yaml\nname: x\n")
+	if _, err := Load(missing); err == nil {
+		t.Error("Expected error for a missing include")
+	}
+}
+
+func TestLoadRemoteInclude(t *testing.T) {
+	defaults := "include: github.yaml\nauthor: Example Org\n"
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bagboy/defaults.yaml":
+			w.Write([]byte(defaults))
+		case "/bagboy/github.yaml":
+			w.Write([]byte("github:\n  owner: example-org\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	oldClient := IncludeClient
+	IncludeClient = server.Client()
+	defer func() { IncludeClient = oldClient }()
+
+	configPath := filepath.Join(t.TempDir(), "bagboy.yaml")
+	load := func(include string) (*Config, error) {
+		content := include + "name: tool\nversion: 1.0.0\n"
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return Load(configPath)
+	}
+
+	cfg, err := load("include: " + server.URL + "/bagboy/defaults.yaml\n")
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Author != "Example Org" || cfg.GitHub.Owner != "example-org" {
+		t.Errorf("Expected remote includes to be merged, got author %q owner %q", cfg.Author, cfg.GitHub.Owner)
+	}
+
+	// A pinned include must match its checksum
+	sum := fmt.Sprintf("%x", sha256.Sum256([]byte(defaults)))
+	if _, err := load("include:\n  - url: " + server.URL + "/bagboy/defaults.yaml\n    sha256: " + sum + "\n"); err != nil {
+		t.Errorf("Expected the pinned include to load, got %v", err)
+	}
+	wrong := strings.Repeat("0", 64)
+	if _, err := load("include:\n  - url: " + server.URL + "/bagboy/defaults.yaml\n    sha256: " + wrong + "\n"); err == nil || !strings.Contains(err.Error(), "pinned") {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+
+	// Plain http is refused
+	insecure := strings.Replace(server.URL, "https://", "http://", 1)
+	if _, err := load("include: " + insecure + "/bagboy/defaults.yaml\n"); err == nil || !strings.Contains(err.Error(), "https") {
+		t.Errorf("Expected http includes to be refused, got %v", err)
+	}
+}
+
 func TestFindConfigFile(t *testing.T) {
 	// Test in directory with no config
 	originalDir, _ := os.Getwd()
@@ -161,3 +312,536 @@ func TestFindConfigFile(t *testing.T) {
 		t.Errorf("FindConfigFile() failed with .yml: %v", err)
 	}
 }
+
+func TestBrewConflictsWithForms(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "bagboy.yaml")
+	content := `name: test
+version: 1.0.0
+binaries:
+  darwin-arm64: test
+packages:
+  brew:
+    conflicts_with:
+      - test-legacy
+      - name: test-core
+        because: both install a test binary
+`
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	conflicts := cfg.Packages.Brew.ConflictsWith
+	if len(conflicts) != 2 {
+		t.Fatalf("Expected 2 conflicts, got %d", len(conflicts))
+	}
+	if conflicts[0].Name != "test-legacy" || conflicts[0].Because != "" {
+		t.Errorf("Unexpected plain conflict: %+v", conflicts[0])
+	}
+	if conflicts[1].Name != "test-core" || conflicts[1].Because != "both install a test binary" {
+		t.Errorf("Unexpected conflict with reason: %+v", conflicts[1])
+	}
+}
+
+func TestBrewCaskForms(t *testing.T) {
+	for content, want := range map[string]BrewCaskConfig{
+		"cask: true":                     {Enabled: true},
+		"cask: {app: My App.app}":        {Enabled: true, App: "My App.app"},
+		"cask: {enabled: false, pkg: x}": {Pkg: "x"},
+	} {
+		var brew BrewConfig
+		if err := yaml.Unmarshal([]byte(content), &brew); err != nil {
+			t.Fatalf("Unmarshal(%q) failed: %v", content, err)
+		}
+		if brew.Cask.Enabled != want.Enabled || brew.Cask.App != want.App || brew.Cask.Pkg != want.Pkg {
+			t.Errorf("Unmarshal(%q) = %+v, want %+v", content, brew.Cask, want)
+		}
+	}
+}
+
+func TestPeople(t *testing.T) {
+	legacy := &Config{Author: "Test Author <test@example.com>"}
+	people := legacy.People()
+	if len(people) != 1 || people[0].Name != "Test Author" || people[0].Email != "test@example.com" || people[0].Role != RoleAuthor {
+		t.Errorf("Unexpected people from author string: %+v", people)
+	}
+
+	cfg := &Config{
+		Author: "ignored",
+		Authors: []AuthorConfig{
+			{Name: "Ada", Email: "ada@example.com"},
+			{Name: "Grace", Email: "grace@example.com", Role: RoleMaintainer},
+			{Name: "Ken", Role: RoleContributor},
+		},
+	}
+	if got := cfg.PrimaryAuthor().String(); got != "Ada <ada@example.com>" {
+		t.Errorf("PrimaryAuthor() = %q", got)
+	}
+	if maintainers := cfg.Maintainers(); len(maintainers) != 1 || maintainers[0].Name != "Grace" {
+		t.Errorf("Maintainers() = %+v", maintainers)
+	}
+
+	// Without a maintainer role the authors maintain the package
+	cfg.Authors[1].Role = ""
+	if names := AuthorNames(cfg.Maintainers()); len(names) != 2 || names[1] != "Grace" {
+		t.Errorf("Expected authors as maintainers, got %v", names)
+	}
+
+	if (&Config{}).PrimaryAuthor().Name != "" {
+		t.Error("Expected no primary author without author or authors")
+	}
+}
+
+func TestValidateAuthors(t *testing.T) {
+	base := Config{Name: "test", Version: "1.0.0", Binaries: map[string]string{"linux-amd64": "test"}}
+
+	cfg := base
+	cfg.Authors = []AuthorConfig{{Name: "Ada", Role: "owner"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected unknown role to fail validation")
+	}
+
+	cfg.Authors = []AuthorConfig{{Email: "ada@example.com"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected author without name to fail validation")
+	}
+
+	cfg.Authors = []AuthorConfig{{Name: "Ada", Role: RoleContributor}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestValidateLicense(t *testing.T) {
+	cfg := Config{Name: "test", Version: "1.0.0", Binaries: map[string]string{"linux-amd64": "test"}}
+
+	for _, license := range []string{"", "MIT", "(MIT OR Apache-2.0) AND BSD-3-Clause", "LicenseRef-Internal"} {
+		cfg.License = license
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Validate() with license %q: %v", license, err)
+		}
+	}
+
+	for _, license := range []string{"MIT License", "MIT OR", "(MIT"} {
+		cfg.License = license
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Expected license %q to fail validation", license)
+		}
+	}
+}
+
+func TestValidateUnknownPackages(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "bagboy.yaml")
+	content := `name: test
+version: 1.0.0
+binaries:
+  linux-amd64: test
+packages:
+  brew:
+    test: test --version
+  %s:
+    package_id: test
+`
+	tests := map[string]string{
+		"choclatey": "packages.choclatey: unknown key (did you mean chocolatey?)",
+		"Deb":       "packages.Deb: unknown key (did you mean deb?)",
+		"snapcraft": "packages.snapcraft: unknown key (known keys: appimage, brew,",
+	}
+	for key, want := range tests {
+		if err := os.WriteFile(configPath, []byte(fmt.Sprintf(content, key)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(configPath)
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		if err := cfg.Validate(); err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("Validate() with packages.%s = %v, want %q", key, err, want)
+		}
+	}
+
+	if err := os.WriteFile(configPath, []byte(fmt.Sprintf(content, "chocolatey")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() with known packages: %v", err)
+	}
+}
+
+func TestValidateMinisignTool(t *testing.T) {
+	cfg := Config{Name: "test", Version: "1.0.0", Binaries: map[string]string{"linux-amd64": "test"}}
+
+	for _, tool := range []string{"", "minisign", "signify"} {
+		cfg.Signing.Minisign.Tool = tool
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Validate() with tool %q: %v", tool, err)
+		}
+	}
+
+	cfg.Signing.Minisign.Tool = "gpg"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected tool gpg to fail validation")
+	}
+
+	cfg.Signing.Minisign = MinisignConfig{Enabled: true, Tool: "signify"}
+	cfg.Signing.SSH.Enabled = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected signify and SSH signing together to fail validation")
+	}
+}
+
+func TestValidateEncryption(t *testing.T) {
+	cfg := Config{Name: "test", Version: "1.0.0", Binaries: map[string]string{"linux-amd64": "test"}}
+	cfg.Encryption = EncryptionConfig{Enabled: true, Recipients: []string{"age1example"}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() failed: %v", err)
+	}
+
+	cfg.GitHub.Tap.Enabled = true
+	cfg.GitHub.Winget.Enabled = true
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "github.tap, github.winget") {
+		t.Errorf("Expected encryption with a tap and winget to fail validation, got %v", err)
+	}
+
+	cfg.Encryption.Enabled = false
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() without encryption failed: %v", err)
+	}
+}
+
+func TestParseBandwidth(t *testing.T) {
+	tests := map[string]int64{
+		"":         0,
+		"1000":     1000,
+		"10MB/s":   10000000,
+		"512KiB/s": 512 << 10,
+		"1.5M":     1500000,
+		"2 GiB/s":  2 << 30,
+	}
+	for s, want := range tests {
+		got, err := ParseBandwidth(s)
+		if err != nil || got != want {
+			t.Errorf("ParseBandwidth(%q) = %d, %v; want %d", s, got, err, want)
+		}
+	}
+
+	for _, s := range []string{"fast", "10XB/s", "-5MB/s", "0"} {
+		if _, err := ParseBandwidth(s); err == nil {
+			t.Errorf("Expected ParseBandwidth(%q) to fail", s)
+		}
+	}
+}
+
+func TestValidatePerformance(t *testing.T) {
+	cfg := Config{Name: "test", Version: "1.0.0", Binaries: map[string]string{"linux-amd64": "test"}}
+	cfg.Performance = PerformanceConfig{MaxParallelPackagers: 2, MaxParallelUploads: 4, BandwidthLimit: "20MB/s", Nice: 10}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() failed: %v", err)
+	}
+
+	for _, perf := range []PerformanceConfig{{Nice: 20}, {MaxParallelUploads: -1}, {BandwidthLimit: "lots"}} {
+		cfg.Performance = perf
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Expected %+v to fail validation", perf)
+		}
+	}
+}
+
+func TestValidateDesktop(t *testing.T) {
+	cfg := Config{Name: "test", Version: "1.0.0", Binaries: map[string]string{"linux-amd64": "test"}}
+	cfg.Desktop = &DesktopConfig{
+		Icon:             "assets/icon.PNG",
+		FileAssociations: []FileAssociationConfig{{Extension: ".tdoc"}},
+		Protocols:        []string{"test", "web+test"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() failed: %v", err)
+	}
+
+	for _, desktop := range []DesktopConfig{
+		{Icon: "icon.jpg"},
+		{FileAssociations: []FileAssociationConfig{{Extension: "."}}},
+		{FileAssociations: []FileAssociationConfig{{Extension: "tar.gz"}}},
+		{Protocols: []string{"Test://"}},
+	} {
+		cfg.Desktop = &desktop
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Expected %+v to fail validation", desktop)
+		}
+	}
+}
+
+func TestBuildTargets(t *testing.T) {
+	got := BuildConfig{}.Targets()
+	want := "darwin-amd64,darwin-arm64,linux-amd64,linux-arm64,windows-amd64,windows-arm64"
+	if strings.Join(got, ",") != want {
+		t.Errorf("default Targets() = %v, want %s", got, want)
+	}
+	got = BuildConfig{Goos: []string{"windows", "linux"}, Goarch: []string{"amd64", "arm64"}, Ignore: []string{"windows-arm64"}}.Targets()
+	if strings.Join(got, ",") != "linux-amd64,linux-arm64,windows-amd64" {
+		t.Errorf("Targets() = %v", got)
+	}
+}
+
+func TestValidateBuilds(t *testing.T) {
+	cfg := Config{Name: "test", Version: "1.0.0"}
+	cfg.Builds = []BuildConfig{
+		{Goos: []string{"linux"}, Env: []string{"CGO_ENABLED=0"}},
+		{Goos: []string{"darwin"}, Env: []string{"CGO_ENABLED=1"}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() failed: %v", err)
+	}
+
+	for _, builds := range [][]BuildConfig{
+		{{Goos: []string{"linux"}}, {Goos: []string{"linux"}, Goarch: []string{"arm64"}}},
+		{{Env: []string{"CGO_ENABLED"}}},
+		{{Goos: []string{"linux"}, Goarch: []string{"amd64"}, Ignore: []string{"linux-amd64"}}},
+	} {
+		cfg.Builds = builds
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Expected %+v to fail validation", builds)
+		}
+	}
+}
+
+func TestValidateHooks(t *testing.T) {
+	cfg := Config{Name: "test", Version: "1.0.0", Binaries: map[string]string{"linux-amd64": "test"}}
+	cfg.Hooks = HooksConfig{
+		BeforePack: []string{"upx dist/build/*/test"},
+		Formats:    map[string]FormatHooks{"deb": {After: []string{"lintian $OUTPUT_PATH"}}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() failed: %v", err)
+	}
+	if got := cfg.Hooks.Stage("before_pack"); len(got) != 1 {
+		t.Errorf("Stage(before_pack) = %v", got)
+	}
+
+	for _, hooks := range []HooksConfig{
+		{AfterPublish: []string{" "}},
+		{Formats: map[string]FormatHooks{"deb": {Before: []string{""}}}},
+	} {
+		cfg.Hooks = hooks
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Expected %+v to fail validation", hooks)
+		}
+	}
+}
+
+func TestValidateSBOM(t *testing.T) {
+	cfg := Config{Name: "test", Version: "1.0.0", Binaries: map[string]string{"linux-amd64": "test"}}
+	cfg.SBOM = SBOMConfig{Enabled: true, Formats: []string{"spdx", "cyclonedx"}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() failed: %v", err)
+	}
+	cfg.SBOM.Formats = []string{"swid"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected an unknown SBOM format to fail validation")
+	}
+}
+
+func TestValidateGitLab(t *testing.T) {
+	cfg := Config{Name: "test", Version: "1.0.0", Binaries: map[string]string{"linux-amd64": "test"}}
+	cfg.GitLab = GitLabConfig{Project: "group/test", Release: GitLabReleaseConfig{Enabled: true}}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() failed: %v", err)
+	}
+	if got := cfg.GitLab.DownloadURL("v1.0.0"); got != "https://gitlab.com/group/test/-/releases/v1.0.0/downloads" {
+		t.Errorf("DownloadURL = %s", got)
+	}
+
+	cfg.GitLab.Release.Assets = "links"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected links without installer.base_url to fail validation")
+	}
+	cfg.GitLab.Release.Assets = "uploads"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected an unknown assets mode to fail validation")
+	}
+	cfg.GitLab.Release.Assets = ""
+	cfg.GitLab.Tap.Enabled = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected a tap without a project to fail validation")
+	}
+}
+
+func TestLoadVersionAuto(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false", "-c", "tag.gpgsign=false"}, args...)...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	configPath := filepath.Join(dir, "bagboy.yaml")
+	os.WriteFile(configPath, []byte("name: myapp\nversion: auto\nbinaries:\n  linux-amd64: myapp\n"), 0644)
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-qm", "Initial")
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.Snapshot() || !strings.HasPrefix(cfg.Version, "0.0.0-0.dev.0+g") {
+		t.Errorf("Expected an untagged snapshot, got %s", cfg.Version)
+	}
+
+	git("tag", "v1.2.0")
+	if cfg, err = Load(configPath); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Version != "1.2.0" || cfg.Tag() != "v1.2.0" || cfg.Snapshot() {
+		t.Errorf("Expected release 1.2.0, got %s (tag %s, snapshot %v)", cfg.Version, cfg.Tag(), cfg.Snapshot())
+	}
+
+	git("commit", "-q", "--allow-empty", "-m", "Next")
+	if cfg, err = Load(configPath); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.Snapshot() || !strings.HasPrefix(cfg.Version, "1.2.1-0.dev.1+g") {
+		t.Errorf("Expected a snapshot after v1.2.0, got %s", cfg.Version)
+	}
+	if v, err := semver.Parse(cfg.Version); err != nil || semver.Compare(v, semver.MustParse("1.2.0")) <= 0 || semver.Compare(v, semver.MustParse("1.2.1")) >= 0 {
+		t.Errorf("Expected the snapshot between 1.2.0 and 1.2.1, got %s", cfg.Version)
+	}
+
+	// Tags outside tag_pattern's prefix are not described from
+	git("tag", "release-2")
+	if cfg, err = Load(configPath); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !strings.HasPrefix(cfg.Version, "1.2.1-0.dev.1+g") {
+		t.Errorf("Expected release-2 to be ignored, got %s", cfg.Version)
+	}
+
+	git("tag", "vnext")
+	if _, err := Load(configPath); err == nil || !strings.Contains(err.Error(), "does not match tag_pattern") {
+		t.Errorf("Expected a tag_pattern error, got %v", err)
+	}
+}
+
+func TestDescribeMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"", "v*"},
+		{`^myapp/v(.+)$`, "myapp/v*"},
+		{`^tools\.cli-v(\d.+)$`, "tools.cli-v*"},
+		{`^v?(\d+\.\d+\.\d+)$`, ""},
+		{`^\d+\.\d+\.\d+$`, ""},
+		{`v(.+)$`, ""},
+	}
+	for _, tt := range tests {
+		cfg := &Config{TagPattern: tt.pattern}
+		if got := cfg.describeMatch(); got != tt.want {
+			t.Errorf("describeMatch() with %q = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestTag(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"", "v1.2.3"},
+		{`^myapp/v(.+)$`, "myapp/v1.2.3"},
+		{`^v?(\d+\.\d+\.\d+)$`, "v1.2.3"},
+	}
+	for _, tt := range tests {
+		cfg := &Config{Version: "1.2.3", TagPattern: tt.pattern}
+		if got := cfg.Tag(); got != tt.want {
+			t.Errorf("Tag() with %q = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+
+	at, err := (&Config{Version: "1.0.0", TagPattern: `^myapp/v(.+)$`}).AtTag("myapp/v1.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if at.Tag() != "myapp/v1.1.0" || at.TagPrefix() != "myapp/v" {
+		t.Errorf("Tag() = %q, TagPrefix() = %q", at.Tag(), at.TagPrefix())
+	}
+}
+
+func TestSnapshotVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		dirty   bool
+		want    string
+	}{
+		{"1.2.3", false, "1.2.4-0.dev.4+gabc1234"},
+		{"1.2.3", true, "1.2.4-0.dev.4+gabc1234.dirty"},
+		{"1.2.3-rc.1", false, "1.2.3-rc.1.0.dev.4+gabc1234"},
+	}
+	for _, tt := range tests {
+		got, err := snapshotVersion(tt.version, "4", "abc1234", tt.dirty)
+		if err != nil || got != tt.want {
+			t.Errorf("snapshotVersion(%s) = %s, %v, want %s", tt.version, got, err, tt.want)
+		}
+	}
+}
+
+func TestTagVersion(t *testing.T) {
+	tests := []struct {
+		pattern string
+		tag     string
+		want    string
+		wantErr bool
+	}{
+		{"", "v1.2.3", "1.2.3", false},
+		{"", "v1.2.3-rc.1", "1.2.3-rc.1", false},
+		{"", "1.2.3", "", true},
+		{`^myapp/v(.+)$`, "myapp/v2.0.0", "2.0.0", false},
+		{`^\d+\.\d+\.\d+$`, "3.1.0", "3.1.0", false},
+		{`^myapp/v(.+)$`, "other/v2.0.0", "", true},
+		{`^v(.+)$`, "vnext", "", true},
+	}
+	for _, tt := range tests {
+		cfg := &Config{TagPattern: tt.pattern}
+		got, err := cfg.TagVersion(tt.tag)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("TagVersion(%q) with %q = %q, %v, want %q", tt.tag, tt.pattern, got, err, tt.want)
+		}
+	}
+
+	cfg := &Config{Name: "test", Version: "1.0.0", Binaries: map[string]string{"linux-amd64": "test"}, TagPattern: "v(["}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "tag_pattern") {
+		t.Errorf("Expected invalid tag_pattern error, got %v", err)
+	}
+}
+
+func TestAtTag(t *testing.T) {
+	cfg := &Config{Name: "myapp", Version: "2.0.0", TagPattern: `^myapp/v(.+)$`, Binaries: map[string]string{"linux-amd64": "dist/myapp"}}
+	at, err := cfg.AtTag("myapp/v1.4.0")
+	if err != nil {
+		t.Fatalf("AtTag failed: %v", err)
+	}
+	if at.Version != "1.4.0" || at.Tag() != "myapp/v1.4.0" {
+		t.Errorf("AtTag() = %s at %s", at.Version, at.Tag())
+	}
+	at.Binaries["linux-amd64"] = "/tmp/myapp"
+	if cfg.Binaries["linux-amd64"] != "dist/myapp" || cfg.Version != "2.0.0" {
+		t.Error("AtTag changed the original config")
+	}
+	if _, err := cfg.AtTag("v1.4.0"); err == nil {
+		t.Error("Expected an error for a tag not matching tag_pattern")
+	}
+}
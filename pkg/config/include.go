@@ -0,0 +1,231 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IncludeClient fetches remote include: fragments
+var IncludeClient = &http.Client{Timeout: 30 * time.Second}
+
+// Include is one config fragment, a path or an https URL. SHA256, when
+// set, pins the fragment to the checksum of its content.
+type Include struct {
+	Source string
+	SHA256 string
+}
+
+// UnmarshalYAML accepts a plain path or URL, or a mapping with path or url
+// and sha256
+func (i *Include) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		i.Source = node.Value
+		return nil
+	}
+	var pinned struct {
+		Path   string `yaml:"path"`
+		URL    string `yaml:"url"`
+		SHA256 string `yaml:"sha256"`
+	}
+	if err := node.Decode(&pinned); err != nil || (pinned.Path == "") == (pinned.URL == "") {
+		return fmt.Errorf("include must be a path, a URL or a mapping with one of path or url and an optional sha256")
+	}
+	i.Source, i.SHA256 = pinned.Path+pinned.URL, strings.ToLower(pinned.SHA256)
+	return nil
+}
+
+// MarshalYAML writes the include back in the form it was read in
+func (i Include) MarshalYAML() (interface{}, error) {
+	if i.SHA256 == "" {
+		return i.Source, nil
+	}
+	key := "path"
+	if isURL(i.Source) {
+		key = "url"
+	}
+	return map[string]string{key: i.Source, "sha256": i.SHA256}, nil
+}
+
+// Includes lists the config fragments a config is merged over. A single
+// fragment can be given as a plain string.
+type Includes []Include
+
+func (i *Includes) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*i = Includes{{Source: node.Value}}
+		return nil
+	}
+	var list []Include
+	if node.Kind != yaml.SequenceNode {
+		return fmt.Errorf("include must be a path, URL or list of them")
+	}
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*i = list
+	return nil
+}
+
+// loadDocument reads the YAML mapping at source, a path or an https URL,
+// and merges it over the fragments listed under its include: key.
+// Fragments apply in order, so later ones override earlier ones, and the
+// including document overrides them all. sum, when set, is the SHA-256
+// the content must have. chain holds the sources being loaded, to catch
+// include cycles.
+func loadDocument(source, sum string, chain []string) (*yaml.Node, error) {
+	for _, seen := range chain {
+		if seen == source {
+			return nil, fmt.Errorf("include cycle: %s -> %s", strings.Join(chain, " -> "), source)
+		}
+	}
+	chain = append(chain, source)
+
+	data, err := readSource(source)
+	if err != nil {
+		if len(chain) == 1 {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		return nil, fmt.Errorf("failed to read include %s: %w", source, err)
+	}
+	if sum != "" {
+		if actual := sha256.Sum256(data); hex.EncodeToString(actual[:]) != sum {
+			return nil, fmt.Errorf("include %s has sha256 %x, not the pinned %s", source, actual, sum)
+		}
+	}
+
+	var file yaml.Node
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		if len(chain) == 1 {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+		return nil, fmt.Errorf("failed to parse include %s: %w", source, err)
+	}
+	doc := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if len(file.Content) > 0 {
+		doc = file.Content[0]
+	}
+	if doc.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: config must be a mapping", source)
+	}
+
+	var includes Includes
+	if node := mappingValue(doc, "include"); node != nil {
+		if err := node.Decode(&includes); err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+	}
+
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, include := range includes {
+		fragment, err := loadDocument(resolveInclude(source, include.Source), include.SHA256, chain)
+		if err != nil {
+			return nil, err
+		}
+		merged = mergeNodes(merged, withoutKey(fragment, "include"))
+	}
+	return mergeNodes(merged, doc), nil
+}
+
+// mappingValue returns the value of key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func withoutKey(node *yaml.Node, key string) *yaml.Node {
+	out := *node
+	out.Content = nil
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != key {
+			out.Content = append(out.Content, node.Content[i], node.Content[i+1])
+		}
+	}
+	return &out
+}
+
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://")
+}
+
+// resolveInclude makes a relative include relative to the file including it
+func resolveInclude(from, include string) string {
+	if isURL(include) || filepath.IsAbs(include) {
+		return include
+	}
+	if isURL(from) {
+		return from[:strings.LastIndex(from, "/")+1] + include
+	}
+	return filepath.Join(filepath.Dir(from), include)
+}
+
+func readSource(source string) ([]byte, error) {
+	if !isURL(source) {
+		return os.ReadFile(source)
+	}
+	if !strings.HasPrefix(source, "https://") {
+		return nil, fmt.Errorf("remote includes must use https")
+	}
+	resp, err := IncludeClient.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", source, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// mergeNodes returns base with override applied on top. Mappings merge key
+// by key; any other value, lists included, replaces the base value.
+func mergeNodes(base, override *yaml.Node) *yaml.Node {
+	if base.Kind != yaml.MappingNode || override.Kind != yaml.MappingNode {
+		return override
+	}
+	merged := *base
+	merged.Content = append([]*yaml.Node(nil), base.Content...)
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		key, value := override.Content[i], override.Content[i+1]
+		replaced := false
+		for j := 0; j+1 < len(merged.Content); j += 2 {
+			if merged.Content[j].Value == key.Value {
+				merged.Content[j+1] = mergeNodes(merged.Content[j+1], value)
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged.Content = append(merged.Content, key, value)
+		}
+	}
+	return &merged
+}
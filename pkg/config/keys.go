@@ -0,0 +1,106 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlKeys returns the YAML keys of the struct type of v
+func yamlKeys(v interface{}) []string {
+	var keys []string
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name != "" && name != "-" {
+			keys = append(keys, name)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// unknownKeys returns the keys of mapping node that are not in known
+func unknownKeys(node *yaml.Node, known []string) []string {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var unknown []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if !contains(known, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	return unknown
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// unknownKeyError describes an unknown key under section, suggesting the
+// closest known key for what is probably a typo
+func unknownKeyError(section, key string, known []string) error {
+	if suggestion := closest(key, known); suggestion != "" {
+		return fmt.Errorf("%s.%s: unknown key (did you mean %s?)", section, key, suggestion)
+	}
+	return fmt.Errorf("%s.%s: unknown key (known keys: %s)", section, key, strings.Join(known, ", "))
+}
+
+// closest returns the candidate nearest to s by edit distance, or "" when
+// none is close enough to be a likely typo
+func closest(s string, candidates []string) string {
+	best, bestDistance := "", len(s)/3+2
+	for _, candidate := range candidates {
+		if d := editDistance(strings.ToLower(s), candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/semver"
+)
+
+// VersionAuto takes the version from the checked-out git tag
+const VersionAuto = "auto"
+
+// DefaultTagPattern matches release tags such as v1.2.3 and v1.2.3-rc.1.
+// The first capture group, or the whole tag without a leading v when
+// there is none, is the version.
+const DefaultTagPattern = `^v(\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?)$`
+
+var describePattern = regexp.MustCompile(`^(.+)-(\d+)-g([0-9a-f]+)(-dirty)?$`)
+
+// tagPattern returns the compiled tag_pattern
+func (c *Config) tagPattern() (*regexp.Regexp, error) {
+	pattern := c.TagPattern
+	if pattern == "" {
+		pattern = DefaultTagPattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("tag_pattern: %w", err)
+	}
+	return re, nil
+}
+
+// TagVersion returns the version in tag, or an error when the tag does
+// not match tag_pattern
+func (c *Config) TagVersion(tag string) (string, error) {
+	re, err := c.tagPattern()
+	if err != nil {
+		return "", err
+	}
+	m := re.FindStringSubmatch(tag)
+	if m == nil {
+		return "", fmt.Errorf("tag %s does not match tag_pattern %s", tag, re)
+	}
+	version := strings.TrimPrefix(tag, "v")
+	if len(m) > 1 && m[1] != "" {
+		version = m[1]
+	}
+	if _, err := semver.Parse(version); err != nil {
+		return "", fmt.Errorf("tag %s: %w", tag, err)
+	}
+	return version, nil
+}
+
+// Tag returns the release tag: the tag version: auto resolved from, or
+// the version after TagPrefix, such as v1.2.3 or myapp/v1.2.3
+func (c *Config) Tag() string {
+	if c.tag != "" {
+		return c.tag
+	}
+	return c.TagPrefix() + c.Version
+}
+
+// TagPrefix returns the part of the release tag before the version, for
+// manifests that build the tag from a version variable: the literal
+// prefix of tag_pattern, or v when it has none
+func (c *Config) TagPrefix() string {
+	if c.tag != "" && strings.HasSuffix(c.tag, c.Version) {
+		return strings.TrimSuffix(c.tag, c.Version)
+	}
+	if match := c.describeMatch(); match != "" {
+		return strings.TrimSuffix(match, "*")
+	}
+	return "v"
+}
+
+// AtTag returns a copy of c for the earlier release tagged tag, to
+// regenerate its manifests. Its binaries are copied so they can be
+// pointed at that release's downloads.
+func (c *Config) AtTag(tag string) (*Config, error) {
+	version, err := c.TagVersion(tag)
+	if err != nil {
+		return nil, err
+	}
+	at := *c
+	at.Version, at.tag, at.snapshot = version, tag, false
+	at.Binaries = make(map[string]string, len(c.Binaries))
+	for platform, path := range c.Binaries {
+		at.Binaries[platform] = path
+	}
+	return &at, nil
+}
+
+// Snapshot reports whether version: auto resolved to a build that is not
+// exactly a release tag
+func (c *Config) Snapshot() bool {
+	return c.snapshot
+}
+
+// describeMatch returns the glob git describe --match selects release tags
+// with: the literal prefix of tag_pattern followed by *, such as v* or
+// myapp/v*, or "" when the pattern is not anchored to literal text
+func (c *Config) describeMatch() string {
+	pattern := c.TagPattern
+	if pattern == "" {
+		pattern = DefaultTagPattern
+	}
+	rest, anchored := strings.CutPrefix(pattern, "^")
+	if !anchored {
+		return ""
+	}
+	var prefix []byte
+	for i := 0; i < len(rest); i++ {
+		ch := rest[i]
+		if ch == '\\' && i+1 < len(rest) && strings.IndexByte("./-_@", rest[i+1]) >= 0 {
+			prefix = append(prefix, rest[i+1])
+			i++
+			continue
+		}
+		if strings.IndexByte(`\.+*?()[]{}|$`, ch) >= 0 {
+			// The character before ?, * or {0,} may be absent
+			if strings.IndexByte("?*{", ch) >= 0 && len(prefix) > 0 {
+				prefix = prefix[:len(prefix)-1]
+			}
+			break
+		}
+		prefix = append(prefix, ch)
+	}
+	if len(prefix) == 0 {
+		return ""
+	}
+	return string(prefix) + "*"
+}
+
+// snapshotVersion returns the version of a build after the release tagged
+// version, ordered after it and before the next release: 1.2.3 becomes
+// 1.2.4-0.dev.4+g1a2b3c4 and 1.2.3-rc.1 becomes 1.2.3-rc.1.0.dev.4+g1a2b3c4
+func snapshotVersion(version, commits, hash string, dirty bool) (string, error) {
+	v, err := semver.Parse(version)
+	if err != nil {
+		return "", err
+	}
+	if v.Prerelease != "" {
+		v.Prerelease += ".0.dev." + commits
+	} else {
+		v.Patch++
+		v.Prerelease = "0.dev." + commits
+	}
+	v.Build = "g" + hash
+	if dirty {
+		v.Build += ".dirty"
+	}
+	return v.String(), nil
+}
+
+// resolveVersion sets the version from git in dir. On a clean checkout of
+// a release tag it is the tag's version; anywhere else it is a snapshot
+// version built from git describe, such as 1.2.4-0.dev.4+g1a2b3c4.dirty.
+// Only tags matching tag_pattern's prefix are considered.
+func (c *Config) resolveVersion(dir string) error {
+	args := []string{"describe", "--tags", "--long", "--dirty", "--always"}
+	if match := c.describeMatch(); match != "" {
+		args = append(args, "--match", match)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("version: auto needs a git checkout: %w", err)
+	}
+	description := strings.TrimSpace(string(output))
+
+	m := describePattern.FindStringSubmatch(description)
+	if m == nil {
+		// No tags yet, only the abbreviated commit
+		hash, dirty := strings.CutSuffix(description, "-dirty")
+		c.Version = "0.0.0-0.dev.0+g" + hash
+		if dirty {
+			c.Version += ".dirty"
+		}
+		c.snapshot = true
+		return nil
+	}
+
+	tag, commits, hash, dirty := m[1], m[2], m[3], m[4]
+	version, err := c.TagVersion(tag)
+	if err != nil {
+		return fmt.Errorf("version: auto: %w", err)
+	}
+	if commits == "0" && dirty == "" {
+		c.Version = version
+		c.tag = tag
+		return nil
+	}
+	if c.Version, err = snapshotVersion(version, commits, hash, dirty != ""); err != nil {
+		return fmt.Errorf("version: auto: %w", err)
+	}
+	c.snapshot = true
+	return nil
+}
@@ -0,0 +1,388 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package delta generates differential update artifacts (MSP patches and
+// deltarpms) between a previous release and the current one.
+package delta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/requirements"
+)
+
+// DefaultFormats are the formats that support delta generation
+var DefaultFormats = []string{"rpm", "msi"}
+
+// Artifact describes a generated delta artifact
+type Artifact struct {
+	Format string
+	Arch   string
+	From   string
+	To     string
+	Path   string
+}
+
+// Generator creates delta artifacts from previous and current packages
+type Generator struct {
+	config  *config.Config
+	distDir string
+}
+
+// NewGenerator creates a new delta generator
+func NewGenerator(cfg *config.Config) *Generator {
+	return &Generator{
+		config:  cfg,
+		distDir: "dist",
+	}
+}
+
+// Formats returns the formats delta generation is configured for
+func (g *Generator) Formats() []string {
+	if len(g.config.Delta.Formats) > 0 {
+		return g.config.Delta.Formats
+	}
+	return DefaultFormats
+}
+
+// Generate builds delta artifacts against the packages in previousDir.
+// An empty previousDir falls back to delta.previous_dir from the config.
+func (g *Generator) Generate(ctx context.Context, previousDir string) ([]Artifact, error) {
+	if previousDir == "" {
+		previousDir = g.config.Delta.PreviousDir
+	}
+	if previousDir == "" {
+		return nil, fmt.Errorf("no previous release directory specified (set delta.previous_dir or use --previous)")
+	}
+	if _, err := os.Stat(previousDir); err != nil {
+		return nil, fmt.Errorf("previous release directory not found: %w", err)
+	}
+
+	var artifacts []Artifact
+	var errs []error
+
+	for _, format := range g.Formats() {
+		switch format {
+		case "rpm":
+			generated, err := g.generateDeltaRPMs(ctx, previousDir)
+			artifacts = append(artifacts, generated...)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", format, err))
+			}
+		case "msi", "msp":
+			artifact, err := g.generateMSP(ctx, previousDir)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", format, err))
+				continue
+			}
+			artifacts = append(artifacts, *artifact)
+		default:
+			errs = append(errs, fmt.Errorf("%s: delta generation not supported for format %s", format, format))
+		}
+	}
+
+	return artifacts, errors.Join(errs...)
+}
+
+// generateDeltaRPMs builds one deltarpm per architecture packaged in both
+// releases, since makedeltarpm only diffs packages of the same arch
+func (g *Generator) generateDeltaRPMs(ctx context.Context, previousDir string) ([]Artifact, error) {
+	if _, err := exec.LookPath("makedeltarpm"); err != nil {
+		return nil, fmt.Errorf("makedeltarpm not found - install the deltarpm package")
+	}
+
+	current, err := FindArtifacts(g.distDir, g.config.Name, g.config.Version, ".rpm")
+	if err != nil {
+		return nil, err
+	}
+	previous, err := FindArtifacts(previousDir, g.config.Name, g.config.Delta.PreviousVersion, ".rpm")
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts []Artifact
+	var errs []error
+	for _, arch := range sortedKeys(current) {
+		old, ok := previous[arch]
+		if !ok {
+			errs = append(errs, fmt.Errorf("no previous %s package in %s", arch, previousDir))
+			continue
+		}
+
+		from := g.previousVersion(old, ".rpm")
+		if from == g.config.Version {
+			errs = append(errs, fmt.Errorf("previous package %s has the same version as the current release", filepath.Base(old)))
+			continue
+		}
+
+		outputPath := filepath.Join(g.distDir, Name(g.config.Name, from, g.config.Version, arch+".drpm"))
+		cmd := exec.CommandContext(ctx, "makedeltarpm", old, current[arch], outputPath)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			errs = append(errs, fmt.Errorf("makedeltarpm failed for %s: %w\nOutput: %s", arch, err, output))
+			continue
+		}
+		artifacts = append(artifacts, Artifact{Format: "drpm", Arch: arch, From: from, To: g.config.Version, Path: outputPath})
+	}
+
+	return artifacts, errors.Join(errs...)
+}
+
+// generateMSP builds a patch from the two .wixpdb files. WiX v4 and later
+// build it with wix build from a patch that names both baselines; WiX v3
+// uses the transform workflow, where torch diffs the .wixpdb files and
+// pyro applies the transform to a compiled patch authoring file.
+func (g *Generator) generateMSP(ctx context.Context, previousDir string) (*Artifact, error) {
+	wix, ok := requirements.WiX(ctx)
+	v4 := ok && wix.Version.Major >= 4
+	if !v4 {
+		for _, tool := range []string{"torch", "candle", "light", "pyro"} {
+			if _, err := exec.LookPath(tool); err != nil {
+				return nil, fmt.Errorf("%s not found - install WiX Toolset (https://wixtoolset.org/)", tool)
+			}
+		}
+	}
+
+	current, err := FindArtifact(g.distDir, g.config.Name, g.config.Version, ".wixpdb")
+	if err != nil {
+		return nil, err
+	}
+	previous, err := FindArtifact(previousDir, g.config.Name, g.config.Delta.PreviousVersion, ".wixpdb")
+	if err != nil {
+		return nil, fmt.Errorf("%w (keep the .wixpdb produced alongside each MSI)", err)
+	}
+
+	from := g.previousVersion(previous, ".wixpdb")
+	if from == g.config.Version {
+		return nil, fmt.Errorf("previous package %s has the same version as the current release", filepath.Base(previous))
+	}
+
+	buildDir := filepath.Join(g.distDir, "msp-build")
+	if err := os.MkdirAll(buildDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create build directory: %w", err)
+	}
+
+	previousAbs, err := filepath.Abs(previous)
+	if err != nil {
+		return nil, err
+	}
+	currentAbs, err := filepath.Abs(current)
+	if err != nil {
+		return nil, err
+	}
+	outputPath, err := filepath.Abs(filepath.Join(g.distDir, Name(g.config.Name, from, g.config.Version, "msp")))
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline *PatchBaseline
+	steps := [][]string{
+		{"torch", "-p", "-xi", previousAbs, currentAbs, "-out", "diff.wixmst"},
+		{"candle", "-out", "patch.wixobj", "patch.wxs"},
+		{"light", "-out", "patch.wixmsp", "patch.wixobj"},
+		{"pyro", "patch.wixmsp", "-out", outputPath, "-t", "RTM", "diff.wixmst"},
+	}
+	if v4 {
+		baseline = &PatchBaseline{Baseline: previousAbs, Update: currentAbs}
+		steps = [][]string{{"wix", "build", "-out", outputPath, "patch.wxs"}}
+	}
+
+	if err := WritePatchSource(filepath.Join(buildDir, "patch.wxs"), g.config, from, baseline); err != nil {
+		return nil, fmt.Errorf("failed to generate patch source: %w", err)
+	}
+	for _, step := range steps {
+		cmd := exec.CommandContext(ctx, step[0], step[1:]...)
+		cmd.Dir = buildDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("%s failed: %w\nOutput: %s", step[0], err, output)
+		}
+	}
+
+	return &Artifact{Format: "msp", From: from, To: g.config.Version, Path: filepath.Join(g.distDir, filepath.Base(outputPath))}, nil
+}
+
+func (g *Generator) previousVersion(path, ext string) string {
+	if g.config.Delta.PreviousVersion != "" {
+		return g.config.Delta.PreviousVersion
+	}
+	return VersionFromFilename(g.config.Name, filepath.Base(path), ext)
+}
+
+// PatchBaseline is the pair of .wixpdb files a WiX v4 patch is built
+// from; WiX v3 takes them from the torch transform instead
+type PatchBaseline struct {
+	Baseline string
+	Update   string
+}
+
+// WritePatchSource writes the WiX patch authoring: for pyro, or in the v4
+// schema for wix build when baseline is set
+func WritePatchSource(path string, cfg *config.Config, from string, baseline *PatchBaseline) error {
+	tmpl := `<?xml version="1.0" encoding="UTF-8"?>
+{{- if .Baseline}}
+<Wix xmlns="http://wixtoolset.org/schemas/v4/wxs">
+{{- else}}
+<Wix xmlns="http://schemas.microsoft.com/wix/2006/wi">
+{{- end}}
+  <Patch AllowRemoval="yes"
+         Manufacturer="{{.Manufacturer}}"
+         DisplayName="{{.Name}} {{.From}} to {{.To}} update"
+         Description="Updates {{.Name}} from {{.From}} to {{.To}}"
+         Classification="Update">
+
+    <Media Id="5000" Cabinet="RTM.cab">
+{{- if .Baseline}}
+      <PatchBaseline Id="RTM" BaselineFile="{{.Baseline.Baseline}}" UpdateFile="{{.Baseline.Update}}" />
+{{- else}}
+      <PatchBaseline Id="RTM" />
+{{- end}}
+    </Media>
+  </Patch>
+</Wix>`
+
+	t, err := template.New("patch").Parse(tmpl)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	manufacturer := cfg.PrimaryAuthor().Name
+
+	return t.Execute(f, struct {
+		Name         string
+		Manufacturer string
+		From         string
+		To           string
+		Baseline     *PatchBaseline
+	}{cfg.Name, manufacturer, from, cfg.Version, baseline})
+}
+
+// FindArtifact locates the package for name in dir. When version is empty
+// the most recently modified matching package is returned.
+func FindArtifact(dir, name, version, ext string) (string, error) {
+	found, err := findArtifacts(dir, name, version, ext, func(string) string { return "" })
+	if err != nil {
+		return "", err
+	}
+	return found[""], nil
+}
+
+// FindArtifacts locates the packages for name in dir, keyed by the
+// architecture in their file names. When version is empty the most
+// recently modified package of each architecture is returned.
+func FindArtifacts(dir, name, version, ext string) (map[string]string, error) {
+	return findArtifacts(dir, name, version, ext, func(file string) string {
+		return ArchFromFilename(file, ext)
+	})
+}
+
+// findArtifacts returns the newest package in dir for each key
+func findArtifacts(dir, name, version, ext string, key func(file string) string) (map[string]string, error) {
+	prefix := name + "-"
+	if version != "" {
+		prefix += version
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	found := make(map[string]string)
+	newest := make(map[string]int64)
+	for _, entry := range entries {
+		file := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(file, prefix) || !strings.HasSuffix(file, ext) {
+			continue
+		}
+		if strings.HasSuffix(file, ".src.rpm") {
+			continue
+		}
+		if version != "" && VersionFromFilename(name, file, ext) != version {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		k := key(file)
+		if _, ok := found[k]; !ok || info.ModTime().UnixNano() > newest[k] {
+			found[k] = filepath.Join(dir, file)
+			newest[k] = info.ModTime().UnixNano()
+		}
+	}
+
+	if len(found) == 0 {
+		if version != "" {
+			return nil, fmt.Errorf("no %s package for %s %s found in %s", ext, name, version, dir)
+		}
+		return nil, fmt.Errorf("no %s package for %s found in %s", ext, name, dir)
+	}
+	return found, nil
+}
+
+// ArchFromFilename extracts the architecture from an rpm file name such
+// as myapp-1.2.0-1.el9.x86_64.rpm. Other packages have none.
+func ArchFromFilename(file, ext string) string {
+	if ext != ".rpm" {
+		return ""
+	}
+	base := strings.TrimSuffix(file, ext)
+	if i := strings.LastIndex(base, "."); i >= 0 {
+		return base[i+1:]
+	}
+	return ""
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// VersionFromFilename extracts the version from a package file name such
+// as myapp-1.2.0.msi or myapp-1.2.0-1.el9.x86_64.rpm
+func VersionFromFilename(name, file, ext string) string {
+	version := strings.TrimSuffix(strings.TrimPrefix(file, name+"-"), ext)
+	if ext == ".rpm" {
+		if i := strings.Index(version, "-"); i >= 0 {
+			version = version[:i]
+		}
+	}
+	return version
+}
+
+// Name returns the file name for a delta artifact between two versions
+func Name(name, from, to, ext string) string {
+	return fmt.Sprintf("%s-%s_%s.%s", name, from, to, ext)
+}
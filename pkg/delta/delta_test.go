@@ -0,0 +1,205 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delta
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestVersionFromFilename(t *testing.T) {
+	tests := []struct {
+		file     string
+		ext      string
+		expected string
+	}{
+		{"myapp-1.2.0.msi", ".msi", "1.2.0"},
+		{"myapp-1.2.0.wixpdb", ".wixpdb", "1.2.0"},
+		{"myapp-1.2.0-1.el9.x86_64.rpm", ".rpm", "1.2.0"},
+		{"myapp-2.0.0-1.x86_64.rpm", ".rpm", "2.0.0"},
+	}
+
+	for _, tt := range tests {
+		if got := VersionFromFilename("myapp", tt.file, tt.ext); got != tt.expected {
+			t.Errorf("VersionFromFilename(%s) = %s, expected %s", tt.file, got, tt.expected)
+		}
+	}
+}
+
+func TestFindArtifact(t *testing.T) {
+	dir := t.TempDir()
+
+	files := []string{
+		"myapp-1.0.0-1.x86_64.rpm",
+		"myapp-1.0.1-1.x86_64.rpm",
+		"myapp-1.0.1-1.src.rpm",
+		"other-1.0.0-1.x86_64.rpm",
+	}
+	for i, file := range files {
+		path := filepath.Join(dir, file)
+		if err := os.WriteFile(path, []byte("rpm"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", file, err)
+		}
+		mtime := time.Now().Add(time.Duration(i) * time.Minute)
+		os.Chtimes(path, mtime, mtime)
+	}
+
+	path, err := FindArtifact(dir, "myapp", "1.0.0", ".rpm")
+	if err != nil {
+		t.Fatalf("FindArtifact failed: %v", err)
+	}
+	if filepath.Base(path) != "myapp-1.0.0-1.x86_64.rpm" {
+		t.Errorf("Expected 1.0.0 package, got %s", path)
+	}
+
+	// Without a version the newest binary package wins
+	path, err = FindArtifact(dir, "myapp", "", ".rpm")
+	if err != nil {
+		t.Fatalf("FindArtifact failed: %v", err)
+	}
+	if filepath.Base(path) != "myapp-1.0.1-1.x86_64.rpm" {
+		t.Errorf("Expected newest package, got %s", path)
+	}
+
+	if _, err := FindArtifact(dir, "myapp", "1.0", ".rpm"); err == nil {
+		t.Error("Expected error for version prefix without exact match")
+	}
+
+	if _, err := FindArtifact(dir, "myapp", "", ".msi"); err == nil {
+		t.Error("Expected error when no package matches")
+	}
+}
+
+func TestFindArtifacts(t *testing.T) {
+	dir := t.TempDir()
+
+	files := []string{
+		"myapp-1.0.0-1.x86_64.rpm",
+		"myapp-1.0.0-1.aarch64.rpm",
+		"myapp-1.0.1-1.aarch64.rpm",
+		"myapp-1.0.1-1.src.rpm",
+	}
+	for i, file := range files {
+		path := filepath.Join(dir, file)
+		if err := os.WriteFile(path, []byte("rpm"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", file, err)
+		}
+		mtime := time.Now().Add(time.Duration(i) * time.Minute)
+		os.Chtimes(path, mtime, mtime)
+	}
+
+	found, err := FindArtifacts(dir, "myapp", "1.0.0", ".rpm")
+	if err != nil {
+		t.Fatalf("FindArtifacts failed: %v", err)
+	}
+	if len(found) != 2 || filepath.Base(found["x86_64"]) != "myapp-1.0.0-1.x86_64.rpm" || filepath.Base(found["aarch64"]) != "myapp-1.0.0-1.aarch64.rpm" {
+		t.Errorf("Expected one 1.0.0 package per arch, got %v", found)
+	}
+
+	// Without a version the newest package of each arch wins
+	found, err = FindArtifacts(dir, "myapp", "", ".rpm")
+	if err != nil {
+		t.Fatalf("FindArtifacts failed: %v", err)
+	}
+	if filepath.Base(found["x86_64"]) != "myapp-1.0.0-1.x86_64.rpm" || filepath.Base(found["aarch64"]) != "myapp-1.0.1-1.aarch64.rpm" {
+		t.Errorf("Expected the newest package per arch, got %v", found)
+	}
+}
+
+func TestName(t *testing.T) {
+	if got := Name("myapp", "1.0.0", "1.1.0", "msp"); got != "myapp-1.0.0_1.1.0.msp" {
+		t.Errorf("Unexpected delta name: %s", got)
+	}
+}
+
+func TestWritePatchSource(t *testing.T) {
+	cfg := &config.Config{
+		Name:    "myapp",
+		Version: "1.1.0",
+		Author:  "Test Author <test@example.com>",
+	}
+
+	path := filepath.Join(t.TempDir(), "patch.wxs")
+	if err := WritePatchSource(path, cfg, "1.0.0", nil); err != nil {
+		t.Fatalf("WritePatchSource failed: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read patch source: %v", err)
+	}
+
+	for _, want := range []string{`Manufacturer="Test Author"`, `<PatchBaseline Id="RTM" />`, "from 1.0.0 to 1.1.0", "schemas.microsoft.com/wix/2006/wi"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Patch source missing %q", want)
+		}
+	}
+
+	// WiX v4 names both baselines in the patch itself
+	if err := WritePatchSource(path, cfg, "1.0.0", &PatchBaseline{Baseline: `C:\old\myapp.wixpdb`, Update: `C:\new\myapp.wixpdb`}); err != nil {
+		t.Fatalf("WritePatchSource failed: %v", err)
+	}
+	content, _ = os.ReadFile(path)
+	for _, want := range []string{"wixtoolset.org/schemas/v4/wxs", `BaselineFile="C:\old\myapp.wixpdb" UpdateFile="C:\new\myapp.wixpdb"`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("v4 patch source missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestFormats(t *testing.T) {
+	g := NewGenerator(&config.Config{})
+	if len(g.Formats()) != len(DefaultFormats) {
+		t.Errorf("Expected default formats, got %v", g.Formats())
+	}
+
+	g = NewGenerator(&config.Config{Delta: config.DeltaConfig{Formats: []string{"rpm"}}})
+	if formats := g.Formats(); len(formats) != 1 || formats[0] != "rpm" {
+		t.Errorf("Expected configured formats, got %v", formats)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	g := NewGenerator(&config.Config{Name: "myapp", Version: "1.1.0"})
+
+	if _, err := g.Generate(context.Background(), ""); err == nil {
+		t.Error("Expected error without previous directory")
+	}
+
+	if _, err := g.Generate(context.Background(), filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected error for missing previous directory")
+	}
+
+	g = NewGenerator(&config.Config{
+		Name:    "myapp",
+		Version: "1.1.0",
+		Delta:   config.DeltaConfig{Formats: []string{"zip"}},
+	})
+	artifacts, err := g.Generate(context.Background(), t.TempDir())
+	if err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+	if len(artifacts) != 0 {
+		t.Errorf("Expected no artifacts, got %d", len(artifacts))
+	}
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/audit"
+	"github.com/scttfrdmn/bagboy/pkg/packager/rpm"
+)
+
+// DefaultCOPRURL is the Fedora COPR instance
+const DefaultCOPRURL = "https://copr.fedorainfracloud.org"
+
+// coprClient talks to the COPR API v3 with the credentials copr-cli uses
+type coprClient struct {
+	baseURL string
+	login   string
+	token   string
+	http    *http.Client
+}
+
+// coprConfigPath returns the copr-cli config file, which holds the API
+// token from https://copr.fedorainfracloud.org/api/
+func coprConfigPath() (string, error) {
+	if path := os.Getenv("COPR_CONFIG"); path != "" {
+		return path, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "copr"), nil
+}
+
+// newCOPRClient reads the [copr-cli] section of the copr-cli config file
+func newCOPRClient() (*coprClient, error) {
+	path, err := coprConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read COPR credentials from %s: %w", path, err)
+	}
+	defer f.Close()
+
+	client := &coprClient{baseURL: DefaultCOPRURL, http: http.DefaultClient}
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.Trim(line, "[]")
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || section != "copr-cli" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "login":
+			client.login = value
+		case "token":
+			client.token = value
+		case "copr_url":
+			client.baseURL = strings.TrimSuffix(value, "/")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if client.login == "" || client.token == "" {
+		return nil, fmt.Errorf("%s has no login and token in [copr-cli] - download it from %s/api/", path, DefaultCOPRURL)
+	}
+	return client, nil
+}
+
+// do sends a request and decodes the JSON response into out
+func (c *coprClient) do(req *http.Request, out interface{}) error {
+	req.SetBasicAuth(c.login, c.token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("COPR API %s: %s", resp.Status, apiErr.Error)
+		}
+		return fmt.Errorf("COPR API %s", resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
+
+func (c *coprClient) postJSON(ctx context.Context, path string, payload, out interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.do(req, out)
+}
+
+// projectExists reports whether owner/name exists
+func (c *coprClient) projectExists(ctx context.Context, owner, name string) (bool, error) {
+	query := url.Values{"ownername": {owner}, "projectname": {name}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api_3/project?"+query.Encode(), nil)
+	if err != nil {
+		return false, err
+	}
+	req.SetBasicAuth(c.login, c.token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode >= 300:
+		return false, fmt.Errorf("COPR API %s", resp.Status)
+	}
+	return true, nil
+}
+
+// createProject creates owner/name with the given chroots enabled
+func (c *coprClient) createProject(ctx context.Context, owner, name string, chroots []string, description string) error {
+	payload := map[string]interface{}{
+		"name":        name,
+		"chroots":     chroots,
+		"description": description,
+	}
+	return c.postJSON(ctx, "/api_3/project/add/"+url.PathEscape(owner), payload, nil)
+}
+
+// buildFromURL starts a build of the SRPM at srpmURL and returns its ID
+func (c *coprClient) buildFromURL(ctx context.Context, owner, name, srpmURL string, chroots []string) (int, error) {
+	payload := map[string]interface{}{
+		"ownername":   owner,
+		"projectname": name,
+		"pkgs":        []string{srpmURL},
+		"chroots":     chroots,
+	}
+	var build struct {
+		ID int `json:"id"`
+	}
+	if err := c.postJSON(ctx, "/api_3/build/create/url", payload, &build); err != nil {
+		return 0, err
+	}
+	return build.ID, nil
+}
+
+// buildFromFile uploads the SRPM at path, starts a build and returns its ID
+func (c *coprClient) buildFromFile(ctx context.Context, owner, name, path string, chroots []string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	metadata, err := json.Marshal(map[string]interface{}{
+		"ownername":   owner,
+		"projectname": name,
+		"chroots":     chroots,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("json", string(metadata)); err != nil {
+		return 0, err
+	}
+	part, err := writer.CreateFormFile("pkgs", filepath.Base(path))
+	if err != nil {
+		return 0, err
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return 0, err
+	}
+	if err := writer.Close(); err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api_3/build/create/upload", &body)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	var build struct {
+		ID int `json:"id"`
+	}
+	if err := c.do(req, &build); err != nil {
+		return 0, err
+	}
+	return build.ID, nil
+}
+
+// deployCOPR triggers a COPR build from the local SRPM or the one attached
+// to the GitHub release, creating the project first when configured to
+func (d *Deployer) deployCOPR(ctx context.Context) error {
+	copr := d.cfg.Packages.RPM.COPR
+	owner, name, ok := strings.Cut(copr.Project, "/")
+	if !ok || owner == "" || name == "" || len(copr.Chroots) == 0 {
+		return fmt.Errorf("rpm.copr.project (owner/name) and rpm.copr.chroots are required")
+	}
+	if copr.Source != "" && copr.Source != "srpm" && copr.Source != "release" {
+		return fmt.Errorf("rpm.copr.source must be srpm or release, got %q", copr.Source)
+	}
+
+	client, err := newCOPRClient()
+	if err != nil {
+		return err
+	}
+
+	exists, err := client.projectExists(ctx, owner, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if !copr.AutoCreate {
+			return fmt.Errorf("COPR project %s does not exist - create it or set rpm.copr.auto_create", copr.Project)
+		}
+		description := copr.Description
+		if description == "" {
+			description = d.cfg.Description
+		}
+		err := client.createProject(ctx, owner, name, copr.Chroots, description)
+		audit.Result(ctx, audit.RepoCreate, client.baseURL+"/coprs/"+copr.Project, "", "", err)
+		if err != nil {
+			return fmt.Errorf("failed to create COPR project: %w", err)
+		}
+		fmt.Printf("✅ Created COPR project %s\n", copr.Project)
+	}
+
+	var buildID int
+	if copr.Source == "release" {
+		if d.cfg.GitHub.Owner == "" || d.cfg.GitHub.Repo == "" {
+			return fmt.Errorf("github.owner and github.repo are required to build from the release")
+		}
+		srpmURL := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s",
+			d.cfg.GitHub.Owner, d.cfg.GitHub.Repo, d.cfg.Tag(), rpm.SRPMName(d.cfg))
+		buildID, err = client.buildFromURL(ctx, owner, name, srpmURL, copr.Chroots)
+	} else {
+		srpm, buildErr := rpm.New().BuildSRPM(ctx, d.cfg)
+		if buildErr != nil {
+			return buildErr
+		}
+		buildID, err = client.buildFromFile(ctx, owner, name, srpm, copr.Chroots)
+	}
+	audit.Result(ctx, audit.PackagePublish, client.baseURL+"/coprs/"+copr.Project, fmt.Sprintf("build %d", buildID), "", err)
+	if err != nil {
+		return fmt.Errorf("failed to start COPR build: %w", err)
+	}
+
+	fmt.Printf("✅ Started COPR build %d: %s/coprs/%s/build/%d/\n", buildID, client.baseURL, copr.Project, buildID)
+	fmt.Printf("   Users install with: sudo dnf copr enable %s && sudo dnf install %s\n", copr.Project, d.cfg.Name)
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func writeCOPRConfig(t *testing.T, baseURL string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "copr")
+	content := "[copr-cli]\nlogin = abc\nusername = tester\ntoken = secret\ncopr_url = " + baseURL + "\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("COPR_CONFIG", path)
+}
+
+func TestDeployCOPR_Config(t *testing.T) {
+	cfg := &config.Config{Name: "testapp", Version: "1.0.0"}
+	deployer := NewDeployer(cfg)
+
+	if err := deployer.deployCOPR(context.Background()); err == nil || !strings.Contains(err.Error(), "rpm.copr.project") {
+		t.Errorf("Expected error for missing COPR config, got %v", err)
+	}
+
+	cfg.Packages.RPM.COPR = config.COPRConfig{Project: "tester/testapp", Chroots: []string{"fedora-40-x86_64"}, Source: "git"}
+	if err := deployer.deployCOPR(context.Background()); err == nil || !strings.Contains(err.Error(), "rpm.copr.source") {
+		t.Errorf("Expected error for invalid source, got %v", err)
+	}
+
+	cfg.Packages.RPM.COPR.Source = ""
+	t.Setenv("COPR_CONFIG", filepath.Join(t.TempDir(), "missing"))
+	if err := deployer.deployCOPR(context.Background()); err == nil || !strings.Contains(err.Error(), "COPR credentials") {
+		t.Errorf("Expected error for missing credentials, got %v", err)
+	}
+}
+
+func TestDeployCOPR_Release(t *testing.T) {
+	var created, built map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "abc" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api_3/project":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "Project tester/testapp does not exist."}`))
+		case "/api_3/project/add/tester":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.Write([]byte(`{"name": "testapp"}`))
+		case "/api_3/build/create/url":
+			json.NewDecoder(r.Body).Decode(&built)
+			w.Write([]byte(`{"id": 42}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	writeCOPRConfig(t, server.URL)
+
+	cfg := &config.Config{Name: "testapp", Version: "1.2.0", Description: "Test app"}
+	cfg.GitHub.Owner = "tester"
+	cfg.GitHub.Repo = "testapp"
+	cfg.Packages.RPM.COPR = config.COPRConfig{
+		Project: "tester/testapp",
+		Chroots: []string{"fedora-40-x86_64"},
+		Source:  "release",
+	}
+	deployer := NewDeployer(cfg)
+
+	if err := deployer.deployCOPR(context.Background()); err == nil || !strings.Contains(err.Error(), "auto_create") {
+		t.Fatalf("Expected error for missing project, got %v", err)
+	}
+
+	cfg.Packages.RPM.COPR.AutoCreate = true
+	if err := deployer.deployCOPR(context.Background()); err != nil {
+		t.Fatalf("deployCOPR failed: %v", err)
+	}
+
+	if created["name"] != "testapp" || created["description"] != "Test app" {
+		t.Errorf("Unexpected project payload: %v", created)
+	}
+	want := "https://github.com/tester/testapp/releases/download/v1.2.0/testapp-1.2.0-1.src.rpm"
+	if pkgs, _ := built["pkgs"].([]interface{}); len(pkgs) != 1 || pkgs[0] != want {
+		t.Errorf("Expected build from %s, got %v", want, built["pkgs"])
+	}
+	if built["ownername"] != "tester" || built["projectname"] != "testapp" {
+		t.Errorf("Unexpected build payload: %v", built)
+	}
+}
+
+func TestCOPRClient_BuildFromFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api_3/build/create/upload" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		var metadata map[string]interface{}
+		if err := json.Unmarshal([]byte(r.FormValue("json")), &metadata); err != nil || metadata["projectname"] != "testapp" {
+			t.Errorf("Unexpected build metadata %q", r.FormValue("json"))
+		}
+		if _, header, err := r.FormFile("pkgs"); err != nil || header.Filename != "testapp-1.0.0-1.src.rpm" {
+			t.Errorf("Expected uploaded SRPM, got %v", err)
+		}
+		w.Write([]byte(`{"id": 7}`))
+	}))
+	defer server.Close()
+	writeCOPRConfig(t, server.URL)
+
+	srpm := filepath.Join(t.TempDir(), "testapp-1.0.0-1.src.rpm")
+	if err := os.WriteFile(srpm, []byte("srpm"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := newCOPRClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := client.buildFromFile(context.Background(), "tester", "testapp", srpm, []string{"fedora-40-x86_64"})
+	if err != nil {
+		t.Fatalf("buildFromFile failed: %v", err)
+	}
+	if id != 7 {
+		t.Errorf("Expected build 7, got %d", id)
+	}
+}
@@ -19,10 +19,15 @@ package deploy
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"strings"
 
+	"github.com/scttfrdmn/bagboy/pkg/audit"
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/requirements"
 )
 
 // Deployer handles deployment of packages to various repositories
@@ -77,9 +82,9 @@ func (d *Deployer) GetDeploymentTargets() []DeploymentTarget {
 			Format:      "npm",
 			Description: "Deploy to npm registry",
 			Instructions: []string{
-				"1. Login to npm: npm login",
-				"2. Navigate to generated npm package directory",
-				"3. Publish: npm publish",
+				"1. Login to npm: npm login, or in GitHub Actions add this repository and workflow as a trusted publisher on npmjs.com",
+				"2. Build package: bagboy pack --npm",
+				"3. Deploy: bagboy deploy --targets npm",
 				"4. Users install with: npm install -g appname",
 			},
 		},
@@ -88,10 +93,11 @@ func (d *Deployer) GetDeploymentTargets() []DeploymentTarget {
 			Format:      "pypi",
 			Description: "Deploy to Python Package Index",
 			Instructions: []string{
-				"1. Install twine: pip install twine",
-				"2. Build package: python setup.py sdist bdist_wheel",
-				"3. Upload: twine upload dist/*",
-				"4. Users install with: pip install appname",
+				"1. Install tools: pip install build twine",
+				"2. Set TWINE_PASSWORD to an API token, or in GitHub Actions add this repository and workflow as a trusted publisher on pypi.org",
+				"3. Build package: bagboy pack --pypi",
+				"4. Deploy: bagboy deploy --targets pypi",
+				"5. Users install with: pip install appname",
 			},
 		},
 		{
@@ -99,18 +105,18 @@ func (d *Deployer) GetDeploymentTargets() []DeploymentTarget {
 			Format:      "cargo",
 			Description: "Deploy to Rust package registry",
 			Instructions: []string{
-				"1. Login to crates.io: cargo login",
-				"2. Navigate to generated cargo package directory",
-				"3. Publish: cargo publish",
+				"1. Login to crates.io: cargo login, or in GitHub Actions add this repository and workflow as a trusted publisher on crates.io",
+				"2. Build package: bagboy pack --cargo",
+				"3. Deploy: bagboy deploy --targets cargo",
 				"4. Users install with: cargo install appname",
 			},
 		},
 		{
 			Name:        "Docker Hub",
 			Format:      "docker",
-			Description: "Deploy Docker image to Docker Hub",
+			Description: "Deploy Docker image to Docker Hub, GHCR, ECR or any registry in packages.docker.registries",
 			Instructions: []string{
-				"1. Login to Docker Hub: docker login",
+				"1. Login to each registry: docker login [registry]",
 				"2. Navigate to generated docker directory",
 				"3. Build image: docker build -t yourname/appname:version .",
 				"4. Push image: docker push yourname/appname:version",
@@ -127,6 +133,63 @@ func (d *Deployer) GetDeploymentTargets() []DeploymentTarget {
 				"3. Users download from GitHub releases page",
 			},
 		},
+		{
+			Name:        "Launchpad PPA",
+			Format:      "ppa",
+			Description: "Upload signed source packages to the Launchpad PPA in deb.ppa",
+			Instructions: []string{
+				"1. Create the PPA on Launchpad and upload your GPG key to your Launchpad account",
+				"2. Install tools: sudo apt-get install devscripts debhelper dput",
+				"3. Set deb.ppa.target (ppa:yourname/appname) and deb.ppa.distributions",
+				"4. Deploy: bagboy deploy --targets ppa",
+				"5. Users install with: sudo add-apt-repository ppa:yourname/appname && sudo apt install appname",
+			},
+		},
+		{
+			Name:        "Fedora COPR",
+			Format:      "copr",
+			Description: "Build the RPM for Fedora in the COPR project in rpm.copr",
+			Instructions: []string{
+				"1. Save your API token from https://copr.fedorainfracloud.org/api/ to ~/.config/copr",
+				"2. Set rpm.copr.project (yourname/appname) and rpm.copr.chroots (e.g. fedora-40-x86_64)",
+				"3. Deploy: bagboy deploy --targets copr",
+				"4. Users install with: sudo dnf copr enable yourname/appname && sudo dnf install appname",
+			},
+		},
+		{
+			Name:        "RubyGems",
+			Format:      "gem",
+			Description: "Push the platform gems to rubygems.org or packages.gem.host",
+			Instructions: []string{
+				"1. Create an API key with the push_rubygem scope at https://rubygems.org/profile/api_keys",
+				"2. Export it as GEM_HOST_API_KEY, or run gem signin",
+				"3. Build gems: bagboy pack --gem",
+				"4. Deploy: bagboy deploy --targets gem",
+				"5. Users install with: gem install appname",
+			},
+		},
+		{
+			Name:        "SourceForge Mirror",
+			Format:      "sourceforge",
+			Description: "Mirror release assets to SourceForge as an installer fallback",
+			Instructions: []string{
+				"1. Create the SourceForge project and add your SSH public key to your account",
+				"2. Set installer.sourceforge.project and installer.sourceforge.user (or SF_USER)",
+				"3. Deploy: bagboy deploy --targets sourceforge",
+				"4. install.sh falls back to https://downloads.sourceforge.net/project/appname/<version>",
+			},
+		},
+		{
+			Name:        "S3 Mirror",
+			Format:      "s3",
+			Description: "Mirror release assets to an S3 bucket with resumable multipart uploads",
+			Instructions: []string{
+				"1. Create the bucket and allow public reads of the release prefix",
+				"2. Set installer.s3.bucket (and region, prefix or endpoint for R2/MinIO)",
+				"3. Export AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY",
+				"4. Deploy: bagboy deploy --targets s3 (rerun to resume an interrupted upload)",
+			},
+		},
 		{
 			Name:        "Snap Store",
 			Format:      "snap",
@@ -184,10 +247,24 @@ func (d *Deployer) executeDeploy(ctx context.Context, target DeploymentTarget) e
 	switch target.Format {
 	case "npm":
 		return d.deployNpm(ctx)
+	case "pypi":
+		return d.deployPyPI(ctx)
+	case "cargo":
+		return d.deployCargo(ctx)
 	case "docker":
 		return d.deployDocker(ctx)
 	case "github":
 		return d.deployGitHub(ctx)
+	case "ppa":
+		return d.deployPPA(ctx)
+	case "copr":
+		return d.deployCOPR(ctx)
+	case "gem":
+		return d.deployRubyGems(ctx)
+	case "sourceforge":
+		return d.deploySourceForge(ctx)
+	case "s3":
+		return d.deployS3(ctx)
 	default:
 		// For most targets, we provide instructions rather than automated deployment
 		fmt.Printf("📋 Manual deployment required for %s:\n", target.Name)
@@ -198,7 +275,31 @@ func (d *Deployer) executeDeploy(ctx context.Context, target DeploymentTarget) e
 
 func (d *Deployer) deployNpm(ctx context.Context) error {
 	cmd := exec.CommandContext(ctx, "npm", "publish", "dist/npm")
+
+	// A trusted publishing token goes in a temporary npmrc rather than on
+	// the command line
+	token := trustedToken(ctx, "npm", npmAudience(), func(oidcToken string) (string, error) {
+		return exchangeNpmToken(ctx, d.cfg.Name, oidcToken)
+	})
+	if token != "" {
+		npmrc, err := os.CreateTemp("", "bagboy-npmrc-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(npmrc.Name())
+		registry := strings.TrimPrefix(strings.TrimPrefix(npmRegistry, "https:"), "http:")
+		_, err = fmt.Fprintf(npmrc, "registry=%s/\n%s/:_authToken=%s\n", npmRegistry, registry, token)
+		if closeErr := npmrc.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		cmd.Env = append(os.Environ(), "NPM_CONFIG_USERCONFIG="+npmrc.Name())
+	}
+
 	output, err := cmd.CombinedOutput()
+	audit.Result(ctx, audit.PackagePublish, "npm:"+d.cfg.Name, d.cfg.Version, "", err)
 	if err != nil {
 		return fmt.Errorf("npm publish failed: %w\nOutput: %s", err, output)
 	}
@@ -206,30 +307,144 @@ func (d *Deployer) deployNpm(ctx context.Context) error {
 	return nil
 }
 
+// deployPyPI builds the package in dist/pypi and uploads it with twine
+func (d *Deployer) deployPyPI(ctx context.Context) error {
+	pypiDir := filepath.Join("dist", "pypi")
+	if _, err := os.Stat(filepath.Join(pypiDir, "pyproject.toml")); err != nil {
+		return fmt.Errorf("no package found in dist/pypi - run bagboy pack --pypi first")
+	}
+
+	// Build into a clean directory so only this version is uploaded
+	outDir := filepath.Join(pypiDir, "dist")
+	if err := os.RemoveAll(outDir); err != nil {
+		return err
+	}
+	build := exec.CommandContext(ctx, "python3", "-m", "build", "--outdir", outDir, pypiDir)
+	if output, err := build.CombinedOutput(); err != nil {
+		return fmt.Errorf("python -m build failed: %w\nOutput: %s", err, output)
+	}
+	files, err := filepath.Glob(filepath.Join(outDir, "*"))
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "python3", append([]string{"-m", "twine", "upload", "--non-interactive"}, files...)...)
+	token := trustedToken(ctx, "PyPI", "pypi", func(oidcToken string) (string, error) {
+		return exchangePyPIToken(ctx, oidcToken)
+	})
+	if token != "" {
+		cmd.Env = append(os.Environ(), "TWINE_USERNAME=__token__", "TWINE_PASSWORD="+token)
+	}
+	output, err := cmd.CombinedOutput()
+	audit.Result(ctx, audit.PackagePublish, "pypi:"+d.cfg.Name, d.cfg.Version, "", err)
+	if err != nil {
+		return fmt.Errorf("twine upload failed: %w\nOutput: %s", err, output)
+	}
+	fmt.Printf("✅ Published to PyPI: %s %s\n", d.cfg.Name, d.cfg.Version)
+	return nil
+}
+
+// deployCargo publishes the crate in dist/cargo to crates.io
+func (d *Deployer) deployCargo(ctx context.Context) error {
+	manifest := filepath.Join("dist", "cargo", "Cargo.toml")
+	if _, err := os.Stat(manifest); err != nil {
+		return fmt.Errorf("no crate found in dist/cargo - run bagboy pack --cargo first")
+	}
+
+	cmd := exec.CommandContext(ctx, "cargo", "publish", "--manifest-path", manifest, "--allow-dirty")
+	token := trustedToken(ctx, "crates.io", "crates.io", func(oidcToken string) (string, error) {
+		return exchangeCratesToken(ctx, oidcToken)
+	})
+	if token != "" {
+		// The token is valid for 30 minutes; revoke it as soon as we are done
+		defer func() {
+			if err := revokeCratesToken(context.WithoutCancel(ctx), token); err != nil {
+				fmt.Printf("⚠️  Failed to revoke crates.io token: %v\n", err)
+			}
+		}()
+		cmd.Env = append(os.Environ(), "CARGO_REGISTRY_TOKEN="+token)
+	}
+	output, err := cmd.CombinedOutput()
+	audit.Result(ctx, audit.PackagePublish, "crates.io:"+d.cfg.Name, d.cfg.Version, "", err)
+	if err != nil {
+		return fmt.Errorf("cargo publish failed: %w\nOutput: %s", err, output)
+	}
+	fmt.Printf("✅ Published to crates.io: %s %s\n", d.cfg.Name, d.cfg.Version)
+	return nil
+}
+
 func (d *Deployer) deployDocker(ctx context.Context) error {
-	// Build Docker image
-	buildCmd := exec.CommandContext(ctx, "docker", "build", "-t", 
-		fmt.Sprintf("%s:%s", d.cfg.Name, d.cfg.Version), "dist/docker")
+	localImage := fmt.Sprintf("%s:%s", d.cfg.Name, d.cfg.Version)
+
+	// Build Docker image, with buildx on Docker 24 and later
+	buildArgs := []string{"build", "-t", localImage, "dist/docker"}
+	if requirements.Buildx(ctx) {
+		buildArgs = append([]string{"buildx", "build", "--load"}, buildArgs[1:]...)
+	}
+	buildCmd := exec.CommandContext(ctx, "docker", buildArgs...)
 	if err := buildCmd.Run(); err != nil {
 		return fmt.Errorf("docker build failed: %w", err)
 	}
-	
-	// Push Docker image (requires docker login)
-	pushCmd := exec.CommandContext(ctx, "docker", "push", 
-		fmt.Sprintf("%s:%s", d.cfg.Name, d.cfg.Version))
-	if err := pushCmd.Run(); err != nil {
-		return fmt.Errorf("docker push failed: %w", err)
+
+	// Push the same image to every configured registry (requires docker login for each)
+	var failed []string
+	for _, image := range d.ImageReferences() {
+		if image != localImage {
+			tagCmd := exec.CommandContext(ctx, "docker", "tag", localImage, image)
+			if output, err := tagCmd.CombinedOutput(); err != nil {
+				return fmt.Errorf("docker tag %s failed: %w\nOutput: %s", image, err, output)
+			}
+		}
+
+		pushCmd := exec.CommandContext(ctx, "docker", "push", image)
+		output, err := pushCmd.CombinedOutput()
+		audit.Result(ctx, audit.ImagePush, image, pushedDigest(output), "", err)
+		if err != nil {
+			fmt.Printf("❌ Failed to push %s: %s\n", image, strings.TrimSpace(string(output)))
+			failed = append(failed, image)
+			continue
+		}
+		fmt.Printf("✅ Pushed Docker image: %s\n", image)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("docker push failed for %s", strings.Join(failed, ", "))
 	}
-	
-	fmt.Printf("✅ Pushed Docker image: %s:%s\n", d.cfg.Name, d.cfg.Version)
 	return nil
 }
 
+var digestPattern = regexp.MustCompile(`digest: (sha256:[0-9a-f]{64})`)
+
+// pushedDigest finds the manifest digest in docker push output
+func pushedDigest(output []byte) string {
+	if m := digestPattern.FindSubmatch(output); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// ImageReferences returns the fully qualified image references for the
+// current version, one per configured registry
+func (d *Deployer) ImageReferences() []string {
+	registries := d.cfg.Packages.Docker.Registries
+	if len(registries) == 0 {
+		return []string{fmt.Sprintf("%s:%s", d.cfg.Name, d.cfg.Version)}
+	}
+
+	images := make([]string, 0, len(registries))
+	for _, registry := range registries {
+		images = append(images, fmt.Sprintf("%s:%s", strings.TrimSuffix(registry, "/"), d.cfg.Version))
+	}
+	return images
+}
+
 func (d *Deployer) deployGitHub(ctx context.Context) error {
 	// Create GitHub release using gh CLI
+	tag := d.cfg.Tag()
 	releaseCmd := exec.CommandContext(ctx, "gh", "release", "create", 
-		"v"+d.cfg.Version, "dist/*", "--title", "v"+d.cfg.Version)
+		tag, "dist/*", "--title", tag)
 	output, err := releaseCmd.CombinedOutput()
+	audit.Result(ctx, audit.ReleaseCreate, tag, strings.TrimSpace(string(output)), "", err)
 	if err != nil {
 		return fmt.Errorf("github release failed: %w\nOutput: %s", err, output)
 	}
@@ -528,3 +528,23 @@ func TestDeployment_ContextCancellation(t *testing.T) {
 		t.Errorf("Dry run should not be affected by context cancellation: %v", err)
 	}
 }
+
+func TestDeployPPA_Config(t *testing.T) {
+	cfg := &config.Config{Name: "testapp", Version: "1.0.0"}
+	deployer := NewDeployer(cfg)
+
+	if err := deployer.deployPPA(context.Background()); err == nil || !strings.Contains(err.Error(), "deb.ppa.target") {
+		t.Errorf("Expected error for missing PPA config, got %v", err)
+	}
+
+	cfg.Packages.Deb.PPA = config.PPAConfig{Target: "ppa:test/testapp", Distributions: []string{"noble"}}
+	t.Setenv("GPG_KEY_ID", "")
+	if err := deployer.deployPPA(context.Background()); err == nil || !strings.Contains(err.Error(), "no signing key") {
+		t.Errorf("Expected error for missing signing key, got %v", err)
+	}
+
+	cfg.Signing.Linux.GPGKeyID = "ABCD1234"
+	if id := deployer.ppaKeyID(); id != "ABCD1234" {
+		t.Errorf("ppaKeyID() = %s", id)
+	}
+}
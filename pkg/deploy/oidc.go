@@ -0,0 +1,180 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Registries that accept GitHub Actions OIDC tokens for trusted publishing
+var (
+	pypiHost    = "https://pypi.org"
+	npmRegistry = "https://registry.npmjs.org"
+	cratesHost  = "https://crates.io"
+)
+
+// errNoOIDC means no ambient OIDC token: not running in GitHub Actions, or
+// the workflow lacks permissions: id-token: write
+var errNoOIDC = errors.New("no GitHub Actions OIDC token")
+
+// githubOIDCToken requests an OIDC token for audience from GitHub Actions
+func githubOIDCToken(ctx context.Context, audience string) (string, error) {
+	requestURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	requestToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+	if requestURL == "" || requestToken == "" {
+		return "", errNoOIDC
+	}
+
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("audience", audience)
+	u.RawQuery = query.Encode()
+
+	var response struct {
+		Value string `json:"value"`
+	}
+	if err := oidcRequest(ctx, http.MethodGet, u.String(), requestToken, nil, &response); err != nil {
+		return "", fmt.Errorf("failed to get OIDC token: %w", err)
+	}
+	return response.Value, nil
+}
+
+// oidcRequest sends a JSON request with a bearer token and decodes the
+// JSON response into out
+func oidcRequest(ctx context.Context, method, url, token string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// trustedToken exchanges the GitHub Actions OIDC token for a short-lived
+// registry token. It returns "" outside GitHub Actions, or when the
+// registry has no trusted publisher for the project, so the upload falls
+// back to the tool's own credentials.
+func trustedToken(ctx context.Context, registry, audience string, exchange func(oidcToken string) (string, error)) string {
+	oidcToken, err := githubOIDCToken(ctx, audience)
+	if errors.Is(err, errNoOIDC) {
+		return ""
+	}
+	if err == nil {
+		var token string
+		if token, err = exchange(oidcToken); err == nil && token != "" {
+			fmt.Printf("🔑 Using %s trusted publishing\n", registry)
+			return token
+		}
+	}
+	fmt.Printf("⚠️  %s trusted publishing unavailable, using configured credentials: %v\n", registry, err)
+	return ""
+}
+
+// exchangePyPIToken mints a PyPI API token for the OIDC token
+func exchangePyPIToken(ctx context.Context, oidcToken string) (string, error) {
+	var response struct {
+		Token string `json:"token"`
+	}
+	body := map[string]string{"token": oidcToken}
+	if err := oidcRequest(ctx, http.MethodPost, pypiHost+"/_/oidc/mint-token", "", body, &response); err != nil {
+		return "", err
+	}
+	return response.Token, nil
+}
+
+// exchangeNpmToken exchanges the OIDC token for a token that can publish
+// pkg
+func exchangeNpmToken(ctx context.Context, pkg, oidcToken string) (string, error) {
+	var response struct {
+		Token string `json:"token"`
+	}
+	endpoint := npmRegistry + "/-/npm/v1/oidc/token/exchange/package/" + url.PathEscape(pkg)
+	if err := oidcRequest(ctx, http.MethodPost, endpoint, oidcToken, nil, &response); err != nil {
+		return "", err
+	}
+	return response.Token, nil
+}
+
+// npmAudience is the OIDC audience npm expects for the registry
+func npmAudience() string {
+	if u, err := url.Parse(npmRegistry); err == nil {
+		return "npm:" + u.Host
+	}
+	return "npm:registry.npmjs.org"
+}
+
+// exchangeCratesToken exchanges the OIDC token for a crates.io token,
+// which should be revoked with revokeCratesToken after publishing
+func exchangeCratesToken(ctx context.Context, oidcToken string) (string, error) {
+	var response struct {
+		Token string `json:"token"`
+	}
+	body := map[string]string{"jwt": oidcToken}
+	if err := oidcRequest(ctx, http.MethodPost, cratesHost+"/api/v1/trusted_publishing/tokens", "", body, &response); err != nil {
+		return "", err
+	}
+	return response.Token, nil
+}
+
+// revokeCratesToken revokes a token from exchangeCratesToken
+func revokeCratesToken(ctx context.Context, token string) error {
+	return oidcRequest(ctx, http.MethodDelete, cratesHost+"/api/v1/trusted_publishing/tokens", token, nil, nil)
+}
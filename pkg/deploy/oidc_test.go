@@ -0,0 +1,101 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGitHubOIDCToken(t *testing.T) {
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", "")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "")
+	if _, err := githubOIDCToken(context.Background(), "pypi"); err != errNoOIDC {
+		t.Errorf("Expected errNoOIDC outside GitHub Actions, got %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer request-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"value": "jwt-for-" + r.URL.Query().Get("audience")})
+	}))
+	defer server.Close()
+
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", server.URL+"/token?api-version=2.0")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+	token, err := githubOIDCToken(context.Background(), "crates.io")
+	if err != nil || token != "jwt-for-crates.io" {
+		t.Errorf("githubOIDCToken = %q, %v", token, err)
+	}
+}
+
+func TestTrustedTokenExchange(t *testing.T) {
+	var revoked string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+
+		switch {
+		case r.URL.Path == "/token":
+			json.NewEncoder(w).Encode(map[string]string{"value": "jwt"})
+		case r.URL.Path == "/_/oidc/mint-token" && body["token"] == "jwt":
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "token": "pypi-token"})
+		case r.URL.EscapedPath() == "/-/npm/v1/oidc/token/exchange/package/@acme%2Ftestapp" && r.Header.Get("Authorization") == "Bearer jwt":
+			json.NewEncoder(w).Encode(map[string]string{"token": "npm-token"})
+		case r.URL.Path == "/api/v1/trusted_publishing/tokens" && r.Method == http.MethodPost && body["jwt"] == "jwt":
+			json.NewEncoder(w).Encode(map[string]string{"token": "crates-token"})
+		case r.URL.Path == "/api/v1/trusted_publishing/tokens" && r.Method == http.MethodDelete:
+			revoked = r.Header.Get("Authorization")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"detail":"no trusted publisher"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	oldPyPI, oldNpm, oldCrates := pypiHost, npmRegistry, cratesHost
+	defer func() { pypiHost, npmRegistry, cratesHost = oldPyPI, oldNpm, oldCrates }()
+	pypiHost, npmRegistry, cratesHost = server.URL, server.URL, server.URL
+
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_URL", server.URL+"/token")
+	t.Setenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN", "request-token")
+	ctx := context.Background()
+
+	if token := trustedToken(ctx, "PyPI", "pypi", func(jwt string) (string, error) { return exchangePyPIToken(ctx, jwt) }); token != "pypi-token" {
+		t.Errorf("PyPI token = %q", token)
+	}
+	if token := trustedToken(ctx, "npm", npmAudience(), func(jwt string) (string, error) { return exchangeNpmToken(ctx, "@acme/testapp", jwt) }); token != "npm-token" {
+		t.Errorf("npm token = %q", token)
+	}
+	if token := trustedToken(ctx, "crates.io", "crates.io", func(jwt string) (string, error) { return exchangeCratesToken(ctx, jwt) }); token != "crates-token" {
+		t.Errorf("crates.io token = %q", token)
+	}
+	if err := revokeCratesToken(ctx, "crates-token"); err != nil || revoked != "Bearer crates-token" {
+		t.Errorf("revokeCratesToken: %v, Authorization %q", err, revoked)
+	}
+
+	// A registry without a trusted publisher falls back to configured credentials
+	if token := trustedToken(ctx, "npm", npmAudience(), func(jwt string) (string, error) { return exchangeNpmToken(ctx, "other", jwt) }); token != "" {
+		t.Errorf("Expected no token without a trusted publisher, got %q", token)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/audit"
+	"github.com/scttfrdmn/bagboy/pkg/packager/deb"
+)
+
+// ppaKeyID returns the key debsign signs uploads with
+func (d *Deployer) ppaKeyID() string {
+	if id := d.cfg.Packages.Deb.PPA.KeyID; id != "" {
+		return id
+	}
+	if id := d.cfg.Signing.Linux.GPGKeyID; id != "" {
+		return id
+	}
+	return os.Getenv("GPG_KEY_ID")
+}
+
+// deployPPA builds a signed source package for every configured Ubuntu
+// series and uploads it to the Launchpad PPA with dput
+func (d *Deployer) deployPPA(ctx context.Context) error {
+	ppa := d.cfg.Packages.Deb.PPA
+	if ppa.Target == "" || len(ppa.Distributions) == 0 {
+		return fmt.Errorf("deb.ppa.target and deb.ppa.distributions are required")
+	}
+
+	keyID := d.ppaKeyID()
+	if keyID == "" {
+		return fmt.Errorf("no signing key - set deb.ppa.key_id, signing.linux.gpg_key_id or GPG_KEY_ID")
+	}
+	for _, tool := range []string{"debsign", "dput"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			return fmt.Errorf("%s not found - install devscripts and dput", tool)
+		}
+	}
+
+	workDir := filepath.Join("dist", "ppa")
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return err
+	}
+
+	packager := deb.New()
+	var failed []string
+	for _, distribution := range ppa.Distributions {
+		sourceDir, err := packager.CreateSource(d.cfg, distribution, workDir)
+		if err != nil {
+			return err
+		}
+
+		changes, err := packager.BuildSource(ctx, d.cfg, distribution, sourceDir)
+		if err != nil {
+			return err
+		}
+
+		signCmd := exec.CommandContext(ctx, "debsign", "-k"+keyID, changes)
+		if output, err := signCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("debsign failed: %w\nOutput: %s", err, output)
+		}
+
+		uploadCmd := exec.CommandContext(ctx, "dput", ppa.Target, changes)
+		output, err := uploadCmd.CombinedOutput()
+		audit.Result(ctx, audit.PackagePublish, ppa.Target, filepath.Base(changes), "", err)
+		if err != nil {
+			fmt.Printf("❌ Failed to upload %s: %s\n", filepath.Base(changes), strings.TrimSpace(string(output)))
+			failed = append(failed, distribution)
+			continue
+		}
+		fmt.Printf("✅ Uploaded %s to %s\n", deb.SourceVersion(d.cfg, distribution), ppa.Target)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("dput failed for %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/audit"
+	"github.com/scttfrdmn/bagboy/pkg/semver"
+)
+
+// PrereleaseTagsToPrune returns the prerelease tags beyond the newest keep,
+// ordered newest first. Release tags and tags that are not versions (such
+// as latest) are never selected, and a keep of zero or less selects
+// nothing, since retention is opt-in.
+func PrereleaseTagsToPrune(tags []string, keep int) []string {
+	if keep <= 0 {
+		return nil
+	}
+
+	var prereleases []string
+	for _, tag := range tags {
+		v, err := semver.Parse(tag)
+		if err != nil || !v.IsPrerelease() {
+			continue
+		}
+		prereleases = append(prereleases, tag)
+	}
+
+	sort.Slice(prereleases, func(i, j int) bool {
+		return semver.Less(prereleases[j], prereleases[i])
+	})
+
+	if len(prereleases) <= keep {
+		return nil
+	}
+	return prereleases[keep:]
+}
+
+// PlanImagePrune lists the prerelease image references to delete from
+// every configured registry according to packages.docker.retention.
+// Nothing is selected unless keep_prereleases is set. Listing tags uses
+// crane, since the docker CLI cannot list or delete remote tags.
+func (d *Deployer) PlanImagePrune(ctx context.Context) ([]string, error) {
+	registries := d.cfg.Packages.Docker.Registries
+	if len(registries) == 0 {
+		return nil, fmt.Errorf("no registries configured in packages.docker.registries")
+	}
+	keep := d.cfg.Packages.Docker.Retention.KeepPrereleases
+	if keep <= 0 {
+		return nil, nil
+	}
+
+	if _, err := exec.LookPath("crane"); err != nil {
+		return nil, fmt.Errorf("crane not found - install with: go install github.com/google/go-containerregistry/cmd/crane@latest")
+	}
+
+	var images []string
+	for _, registry := range registries {
+		repo := strings.TrimSuffix(registry, "/")
+
+		output, err := exec.CommandContext(ctx, "crane", "ls", repo).CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tags for %s: %s", repo, strings.TrimSpace(string(output)))
+		}
+		for _, tag := range PrereleaseTagsToPrune(strings.Fields(string(output)), keep) {
+			images = append(images, fmt.Sprintf("%s:%s", repo, tag))
+		}
+	}
+	return images, nil
+}
+
+// PruneImages deletes the image references PlanImagePrune selected
+func (d *Deployer) PruneImages(ctx context.Context, images []string) error {
+	var failed []string
+	for _, image := range images {
+		output, err := exec.CommandContext(ctx, "crane", "delete", image).CombinedOutput()
+		audit.Result(ctx, audit.ImageDelete, image, "", "", err)
+		if err != nil {
+			fmt.Printf("❌ Failed to delete %s: %s\n", image, strings.TrimSpace(string(output)))
+			failed = append(failed, image)
+			continue
+		}
+		fmt.Printf("🗑️  Deleted %s\n", image)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("pruning failed for %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestPrereleaseTagsToPrune(t *testing.T) {
+	tags := []string{"latest", "1.0.0", "1.1.0-rc.1", "1.1.0-rc.2", "1.1.0-rc.10", "1.2.0-beta.1", "1.1.0", "nightly"}
+
+	tests := []struct {
+		keep     int
+		expected []string
+	}{
+		{0, nil},
+		{1, []string{"1.1.0-rc.10", "1.1.0-rc.2", "1.1.0-rc.1"}},
+		{2, []string{"1.1.0-rc.2", "1.1.0-rc.1"}},
+		{4, nil},
+		{10, nil},
+		{-1, nil},
+	}
+
+	for _, tt := range tests {
+		got := PrereleaseTagsToPrune(tags, tt.keep)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("PrereleaseTagsToPrune(keep=%d) = %v, expected %v", tt.keep, got, tt.expected)
+		}
+	}
+}
+
+func TestPlanImagePrune_Unconfigured(t *testing.T) {
+	cfg := &config.Config{Name: "testapp", Version: "1.0.0"}
+	cfg.Packages.Docker.Registries = []string{"ghcr.io/testuser/testapp"}
+
+	images, err := NewDeployer(cfg).PlanImagePrune(context.Background())
+	if err != nil || images != nil {
+		t.Errorf("Expected no images without keep_prereleases, got %v, %v", images, err)
+	}
+}
+
+func TestImageReferences(t *testing.T) {
+	cfg := &config.Config{Name: "testapp", Version: "1.0.0"}
+	deployer := NewDeployer(cfg)
+
+	if got := deployer.ImageReferences(); !reflect.DeepEqual(got, []string{"testapp:1.0.0"}) {
+		t.Errorf("Expected local image reference, got %v", got)
+	}
+
+	cfg.Packages.Docker.Registries = []string{
+		"docker.io/testuser/testapp",
+		"ghcr.io/testuser/testapp/",
+		"123456789012.dkr.ecr.us-east-1.amazonaws.com/testapp",
+	}
+	expected := []string{
+		"docker.io/testuser/testapp:1.0.0",
+		"ghcr.io/testuser/testapp:1.0.0",
+		"123456789012.dkr.ecr.us-east-1.amazonaws.com/testapp:1.0.0",
+	}
+	if got := deployer.ImageReferences(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("ImageReferences() = %v, expected %v", got, expected)
+	}
+}
+
+func TestPlanImagePrune_NoRegistries(t *testing.T) {
+	deployer := NewDeployer(&config.Config{Name: "testapp", Version: "1.0.0"})
+	if _, err := deployer.PlanImagePrune(context.Background()); err == nil {
+		t.Error("Expected error without configured registries")
+	}
+}
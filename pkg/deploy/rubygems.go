@@ -0,0 +1,129 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/audit"
+	"github.com/scttfrdmn/bagboy/pkg/packager/gem"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultRubyGemsHost is rubygems.org
+const DefaultRubyGemsHost = "https://rubygems.org"
+
+// rubygemsAPIKey returns the API key gem push would use: GEM_HOST_API_KEY,
+// then :rubygems_api_key: in ~/.gem/credentials
+func rubygemsAPIKey() (string, error) {
+	if key := os.Getenv("GEM_HOST_API_KEY"); key != "" {
+		return key, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(home, ".gem", "credentials")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("no RubyGems API key - set GEM_HOST_API_KEY or run gem signin: %w", err)
+	}
+	var credentials map[string]string
+	if err := yaml.Unmarshal(data, &credentials); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if key := credentials[":rubygems_api_key"]; key != "" {
+		return key, nil
+	}
+	return "", fmt.Errorf("%s has no :rubygems_api_key: - set GEM_HOST_API_KEY or run gem signin", path)
+}
+
+// pushGem uploads a .gem to host's push API
+func pushGem(ctx context.Context, host, apiKey, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(host, "/")+"/api/v1/gems", f)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", apiKey)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	// Accounts with MFA on API calls need the current one-time code
+	if otp := os.Getenv("GEM_HOST_OTP_CODE"); otp != "" {
+		req.Header.Set("OTP", otp)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		audit.Result(ctx, audit.PackagePublish, host, filepath.Base(path), "", err)
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	message := strings.TrimSpace(string(body))
+	if err == nil && resp.StatusCode >= 300 {
+		err = fmt.Errorf("RubyGems API %s: %s", resp.Status, message)
+	}
+	audit.Result(ctx, audit.PackagePublish, host, filepath.Base(path), audit.RequestID(resp.Header), err)
+	if err != nil {
+		return "", err
+	}
+	return message, nil
+}
+
+// deployRubyGems pushes every gem the gem packager built for this version
+func (d *Deployer) deployRubyGems(ctx context.Context) error {
+	gems, err := filepath.Glob(filepath.Join("dist", "gem", fmt.Sprintf("%s-%s*.gem", gem.GemName(d.cfg), d.cfg.Version)))
+	if err != nil {
+		return err
+	}
+	if len(gems) == 0 {
+		return fmt.Errorf("no gems found in dist/gem - run bagboy pack --gem first")
+	}
+	sort.Strings(gems)
+
+	apiKey, err := rubygemsAPIKey()
+	if err != nil {
+		return err
+	}
+	host := d.cfg.Packages.Gem.Host
+	if host == "" {
+		host = DefaultRubyGemsHost
+	}
+
+	for _, path := range gems {
+		message, err := pushGem(ctx, host, apiKey, path)
+		if err != nil {
+			return fmt.Errorf("failed to push %s: %w", filepath.Base(path), err)
+		}
+		fmt.Printf("✅ %s\n", message)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestRubyGemsAPIKey(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GEM_HOST_API_KEY", "")
+
+	if _, err := rubygemsAPIKey(); err == nil {
+		t.Error("Expected error without credentials")
+	}
+
+	if err := os.MkdirAll(filepath.Join(home, ".gem"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".gem", "credentials"), []byte("---\n:rubygems_api_key: rubygems_secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if key, err := rubygemsAPIKey(); err != nil || key != "rubygems_secret" {
+		t.Errorf("Expected key from credentials, got %q, %v", key, err)
+	}
+
+	t.Setenv("GEM_HOST_API_KEY", "from_env")
+	if key, _ := rubygemsAPIKey(); key != "from_env" {
+		t.Errorf("Expected GEM_HOST_API_KEY to take precedence, got %q", key)
+	}
+}
+
+func TestDeployRubyGems(t *testing.T) {
+	var pushed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/gems" || r.Header.Get("Authorization") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("Access Denied."))
+			return
+		}
+		if r.Header.Get("OTP") != "123456" {
+			t.Errorf("Expected OTP header, got %q", r.Header.Get("OTP"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		pushed = append(pushed, string(body))
+		w.Write([]byte("Successfully registered gem: testapp (1.0.0)"))
+	}))
+	defer server.Close()
+
+	testDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+	t.Setenv("GEM_HOST_API_KEY", "secret")
+	t.Setenv("GEM_HOST_OTP_CODE", "123456")
+
+	cfg := &config.Config{Name: "testapp", Version: "1.0.0"}
+	cfg.Packages.Gem.Host = server.URL
+	deployer := NewDeployer(cfg)
+
+	if err := deployer.deployRubyGems(context.Background()); err == nil || !strings.Contains(err.Error(), "no gems found") {
+		t.Errorf("Expected error without gems, got %v", err)
+	}
+
+	os.MkdirAll(filepath.Join("dist", "gem"), 0755)
+	for _, name := range []string{"testapp-1.0.0.gem", "testapp-1.0.0-x86_64-linux.gem", "testapp-0.9.0.gem"} {
+		os.WriteFile(filepath.Join("dist", "gem", name), []byte(name), 0644)
+	}
+	if err := deployer.deployRubyGems(context.Background()); err != nil {
+		t.Fatalf("deployRubyGems failed: %v", err)
+	}
+	if len(pushed) != 2 || pushed[0] != "testapp-1.0.0-x86_64-linux.gem" || pushed[1] != "testapp-1.0.0.gem" {
+		t.Errorf("Unexpected pushed gems %v", pushed)
+	}
+
+	t.Setenv("GEM_HOST_API_KEY", "wrong")
+	if err := deployer.deployRubyGems(context.Background()); err == nil || !strings.Contains(err.Error(), "Access Denied") {
+		t.Errorf("Expected API error, got %v", err)
+	}
+}
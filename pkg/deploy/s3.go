@@ -0,0 +1,69 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/scttfrdmn/bagboy/pkg/packager/installer"
+	"github.com/scttfrdmn/bagboy/pkg/upload"
+)
+
+// S3StateDir records unfinished multipart uploads so the next deploy
+// resumes them
+var S3StateDir = filepath.Join("dist", ".uploads")
+
+// deployS3 mirrors the release assets to <prefix>/<version>/ in the
+// configured bucket. Large assets go up in parts and an interrupted
+// deploy picks up where it stopped.
+func (d *Deployer) deployS3(ctx context.Context) error {
+	s3cfg := d.cfg.Installer.S3
+	if s3cfg.Bucket == "" {
+		return fmt.Errorf("installer.s3.bucket is required")
+	}
+	client, err := upload.NewS3FromEnv(s3cfg.Bucket, s3cfg.Region, s3cfg.Endpoint)
+	if err != nil {
+		return err
+	}
+	client.StateDir = S3StateDir
+
+	if err := installer.WriteMirrorList(filepath.Join("dist", installer.MirrorListName), d.cfg); err != nil {
+		return fmt.Errorf("failed to update mirror list: %w", err)
+	}
+	assets, err := releaseAssets()
+	if err != nil {
+		return err
+	}
+
+	dir := installer.S3Dir(d.cfg)
+	err = upload.Each(ctx, len(assets), func(ctx context.Context, i int) error {
+		key := dir + "/" + filepath.Base(assets[i])
+		if err := client.Upload(ctx, assets[i], key); err != nil {
+			return fmt.Errorf("failed to upload %s: %w", assets[i], err)
+		}
+		fmt.Printf("  ✓ %s\n", key)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Mirrored %d file(s) to %s\n", len(assets), installer.S3URL(d.cfg))
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/audit"
+	"github.com/scttfrdmn/bagboy/pkg/packager/installer"
+)
+
+// SourceForgeHost is the SourceForge file release server
+const SourceForgeHost = "frs.sourceforge.net"
+
+// sourceForgeDestination returns the rsync destination for the release,
+// user@frs.sourceforge.net:/home/frs/project/<project>/<dir>/
+func (d *Deployer) sourceForgeDestination() (string, error) {
+	sf := d.cfg.Installer.SourceForge
+	if sf.Project == "" {
+		return "", fmt.Errorf("installer.sourceforge.project is required")
+	}
+	user := sf.User
+	if user == "" {
+		user = os.Getenv("SF_USER")
+	}
+	if user == "" {
+		return "", fmt.Errorf("installer.sourceforge.user or SF_USER is required")
+	}
+	return fmt.Sprintf("%s@%s:/home/frs/project/%s/%s/", user, SourceForgeHost, sf.Project, installer.SourceForgeDir(d.cfg)), nil
+}
+
+// releaseAssets returns the files directly in dist, which are the assets
+// publish attaches to the release
+func releaseAssets() ([]string, error) {
+	entries, err := os.ReadDir("dist")
+	if err != nil {
+		return nil, err
+	}
+	var assets []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			assets = append(assets, filepath.Join("dist", entry.Name()))
+		}
+	}
+	sort.Strings(assets)
+	return assets, nil
+}
+
+// deploySourceForge mirrors the release assets to the SourceForge
+// project's files with rsync over ssh, refreshing the mirror list first
+// so the copy on SourceForge names every mirror
+func (d *Deployer) deploySourceForge(ctx context.Context) error {
+	destination, err := d.sourceForgeDestination()
+	if err != nil {
+		return err
+	}
+	if _, err := exec.LookPath("rsync"); err != nil {
+		return fmt.Errorf("rsync not found - required to upload to SourceForge")
+	}
+
+	if err := installer.WriteMirrorList(filepath.Join("dist", installer.MirrorListName), d.cfg); err != nil {
+		return fmt.Errorf("failed to update mirror list: %w", err)
+	}
+	assets, err := releaseAssets()
+	if err != nil {
+		return err
+	}
+
+	ssh := "ssh -o BatchMode=yes"
+	if key := d.cfg.Installer.SourceForge.SSHKey; key != "" {
+		ssh += " -i " + key
+	}
+	args := append([]string{"--archive", "--partial", "--progress", "-e", ssh}, assets...)
+	args = append(args, destination)
+
+	cmd := exec.CommandContext(ctx, "rsync", args...)
+	output, err := cmd.CombinedOutput()
+	audit.Result(ctx, audit.ObjectUpload, destination, strings.Join(assets, " "), "", err)
+	if err != nil {
+		return fmt.Errorf("rsync to SourceForge failed: %w\nOutput: %s", err, output)
+	}
+
+	fmt.Printf("✅ Mirrored %d file(s) to SourceForge: %s\n", len(assets), installer.SourceForgeURL(d.cfg))
+	fmt.Printf("   Installer mirrors: %s\n", strings.Join(installer.MirrorURLs(d.cfg), ", "))
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestDeploySourceForge(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script in place of rsync")
+	}
+
+	cfg := &config.Config{
+		Name:      "testapp",
+		Version:   "1.0.0",
+		Installer: config.InstallerConfig{BaseURL: "https://github.com/me/testapp/releases/download/v1.0.0"},
+	}
+	deployer := NewDeployer(cfg)
+	if err := deployer.deploySourceForge(context.Background()); err == nil || !strings.Contains(err.Error(), "sourceforge.project") {
+		t.Errorf("Expected error for missing project, got %v", err)
+	}
+
+	cfg.Installer.SourceForge = config.SourceForgeConfig{Project: "testapp", SSHKey: "/keys/sf"}
+	t.Setenv("SF_USER", "")
+	if err := deployer.deploySourceForge(context.Background()); err == nil || !strings.Contains(err.Error(), "SF_USER") {
+		t.Errorf("Expected error for missing user, got %v", err)
+	}
+	t.Setenv("SF_USER", "tester")
+
+	// Record the rsync arguments instead of uploading
+	binDir := t.TempDir()
+	argsFile := filepath.Join(binDir, "args")
+	script := "#!/bin/sh\nfor arg in \"$@\"; do echo \"$arg\"; done > " + argsFile + "\n"
+	if err := os.WriteFile(filepath.Join(binDir, "rsync"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir)
+
+	testDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+	os.MkdirAll(filepath.Join("dist", "npm"), 0755)
+	os.WriteFile(filepath.Join("dist", "testapp-linux-amd64"), []byte("binary"), 0755)
+
+	if err := deployer.deploySourceForge(context.Background()); err != nil {
+		t.Fatalf("deploySourceForge failed: %v", err)
+	}
+
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := strings.Split(strings.TrimSpace(string(data)), "\n")
+	want := []string{
+		"--archive", "--partial", "--progress", "-e", "ssh -o BatchMode=yes -i /keys/sf",
+		"dist/mirrors.txt", "dist/testapp-linux-amd64",
+		"tester@frs.sourceforge.net:/home/frs/project/testapp/1.0.0/",
+	}
+	if strings.Join(args, "|") != strings.Join(want, "|") {
+		t.Errorf("Unexpected rsync arguments:\n%v\nexpected:\n%v", args, want)
+	}
+
+	mirrors, err := os.ReadFile(filepath.Join("dist", "mirrors.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantMirrors := "https://github.com/me/testapp/releases/download/v1.0.0\nhttps://downloads.sourceforge.net/project/testapp/1.0.0\n"
+	if string(mirrors) != wantMirrors {
+		t.Errorf("Unexpected mirror list:\n%s", mirrors)
+	}
+}
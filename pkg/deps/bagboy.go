@@ -0,0 +1,66 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deps
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/semver"
+)
+
+// versionPattern finds the first version number in --version output
+var versionPattern = regexp.MustCompile(`v?\d+\.\d+(\.\d+)?(-[0-9A-Za-z.-]+)?`)
+
+// checkBagboyTool runs the installed tool with --version and checks the
+// version against the constraint
+func (m *Manager) checkBagboyTool(ctx context.Context, dep config.ToolDependency) DependencyStatus {
+	path, err := exec.LookPath(dep.Name)
+	if err != nil {
+		return DependencyStatus{Available: false}
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+	if err != nil {
+		return DependencyStatus{Available: true, Error: fmt.Sprintf("%s --version failed: %v", dep.Name, err)}
+	}
+	return toolStatus(dep, string(output))
+}
+
+// toolStatus checks the version in a tool's --version output against its
+// constraint
+func toolStatus(dep config.ToolDependency, output string) DependencyStatus {
+	found := versionPattern.FindString(output)
+	version, err := semver.Parse(found)
+	if err != nil {
+		return DependencyStatus{Available: true, Error: fmt.Sprintf("no version in %s --version output", dep.Name)}
+	}
+	constraint, err := dep.Constraint()
+	if err != nil {
+		return DependencyStatus{Available: true, Version: version.String(), Error: err.Error()}
+	}
+	status := DependencyStatus{Available: true, Version: version.String(), Satisfies: constraint.Check(version)}
+	if !status.Satisfies {
+		status.Error = fmt.Sprintf("%s %s is installed, %s required", dep.Name, version, constraint)
+	}
+	return status
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deps
+
+import (
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestToolStatus(t *testing.T) {
+	dep := config.ToolDependency{Name: "helper", Version: ">=1.2, <2"}
+
+	tests := []struct {
+		output    string
+		version   string
+		satisfies bool
+	}{
+		{"helper version 1.4.0 (abc123)\n", "1.4.0", true},
+		{"helper v2.0.1\n", "2.0.1", false},
+		{"helper 1.1\n", "1.1.0", false},
+	}
+	for _, tt := range tests {
+		status := toolStatus(dep, tt.output)
+		if status.Version != tt.version || status.Satisfies != tt.satisfies {
+			t.Errorf("toolStatus(%q) = %s satisfies=%v, want %s satisfies=%v", tt.output, status.Version, status.Satisfies, tt.version, tt.satisfies)
+		}
+		if !tt.satisfies && status.Error == "" {
+			t.Errorf("toolStatus(%q) should explain the mismatch", tt.output)
+		}
+	}
+
+	if status := toolStatus(dep, "no version here"); status.Error == "" || status.Satisfies {
+		t.Errorf("Expected an error without a version, got %+v", status)
+	}
+}
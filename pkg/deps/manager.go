@@ -71,6 +71,19 @@ func (m *Manager) Check(ctx context.Context) (map[string]DependencyStatus, error
 		status := m.checkRuntimeDependency(runtime, version)
 		results[runtime] = status
 	}
+
+	// Check pinned tools for the current platform
+	for name, tool := range m.config.Dependencies.Tools {
+		if download, ok := tool.Platforms[currentPlatform()]; ok {
+			results[name] = m.checkTool(name, download)
+		}
+	}
+
+	// Check other bagboy tools are installed at a version that satisfies
+	// their constraint
+	for _, dep := range m.config.Dependencies.Bagboy {
+		results[dep.Name] = m.checkBagboyTool(ctx, dep)
+	}
 	
 	return results, nil
 }
@@ -80,6 +93,12 @@ func (m *Manager) Install(ctx context.Context, deps []string) error {
 	pm := m.detectPackageManager()
 	
 	for _, dep := range deps {
+		if tool, ok := m.config.Dependencies.Tools[dep]; ok {
+			if err := m.installTool(ctx, dep, tool); err != nil {
+				return fmt.Errorf("failed to install %s: %w", dep, err)
+			}
+			continue
+		}
 		if err := m.installDependency(pm, dep); err != nil {
 			return fmt.Errorf("failed to install %s: %w", dep, err)
 		}
@@ -122,6 +141,26 @@ func (m *Manager) List() []Dependency {
 			Version: version,
 		})
 	}
+
+	// Pinned tools
+	for name, tool := range m.config.Dependencies.Tools {
+		for platform := range tool.Platforms {
+			deps = append(deps, Dependency{
+				Name:     name,
+				Type:     "tool",
+				Platform: platform,
+			})
+		}
+	}
+
+	// Other bagboy tools
+	for _, dep := range m.config.Dependencies.Bagboy {
+		deps = append(deps, Dependency{
+			Name:    dep.Name,
+			Type:    "bagboy",
+			Version: dep.Version,
+		})
+	}
 	
 	return deps
 }
@@ -0,0 +1,204 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deps
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// ToolsDir returns where downloaded tools are installed
+func ToolsDir() string {
+	if dir := os.Getenv("BAGBOY_TOOLS_DIR"); dir != "" {
+		return dir
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".bagboy", "bin")
+}
+
+// toolPath returns the installed path of a tool
+func toolPath(name string) string {
+	if runtime.GOOS == "windows" && !strings.HasSuffix(name, ".exe") {
+		name += ".exe"
+	}
+	return filepath.Join(ToolsDir(), name)
+}
+
+// currentPlatform returns the os-arch pair tools are selected by
+func currentPlatform() string {
+	return runtime.GOOS + "-" + runtime.GOARCH
+}
+
+// checkTool reports whether a tool is installed and still matches its pin.
+// A modified binary is reported unavailable so it is downloaded again.
+func (m *Manager) checkTool(name string, download config.ToolDownload) DependencyStatus {
+	path := toolPath(name)
+	if _, err := os.Stat(path); err != nil {
+		return DependencyStatus{Available: false}
+	}
+	digest, err := fileSHA256(path)
+	if err != nil {
+		return DependencyStatus{Available: false, Error: err.Error()}
+	}
+	if !strings.EqualFold(digest, download.SHA256) {
+		return DependencyStatus{Available: false, Error: fmt.Sprintf("%s does not match the pinned sha256", path)}
+	}
+	return DependencyStatus{Available: true, Version: "pinned", Satisfies: true}
+}
+
+// installTool downloads a tool, verifies it against the pinned digest and
+// sigstore bundle, and only then installs it as an executable
+func (m *Manager) installTool(ctx context.Context, name string, tool config.ToolConfig) error {
+	platform := currentPlatform()
+	download, ok := tool.Platforms[platform]
+	if !ok {
+		return fmt.Errorf("no download pinned for %s", platform)
+	}
+	if download.SHA256 == "" {
+		return fmt.Errorf("refusing to install an unpinned download; set sha256 for %s", platform)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "bagboy-tool-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	file := filepath.Join(tmpDir, name)
+	if err := downloadFile(ctx, download.URL, file); err != nil {
+		return err
+	}
+
+	digest, err := fileSHA256(file)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(digest, download.SHA256) {
+		return fmt.Errorf("sha256 mismatch for %s: expected %s, got %s", download.URL, download.SHA256, digest)
+	}
+
+	if download.Bundle != "" {
+		bundle := file + ".sigstore.json"
+		if err := downloadFile(ctx, download.Bundle, bundle); err != nil {
+			return err
+		}
+		if err := verifyBundle(ctx, file, bundle, tool); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(ToolsDir(), 0755); err != nil {
+		return err
+	}
+	if err := os.Chmod(file, 0755); err != nil {
+		return err
+	}
+	return moveFile(file, toolPath(name))
+}
+
+// verifyBundle checks a sigstore bundle with cosign. cosign itself can be
+// installed as a tool, in which case its sha256 pin is the trust root.
+func verifyBundle(ctx context.Context, file, bundle string, tool config.ToolConfig) error {
+	cosign := toolPath("cosign")
+	if _, err := os.Stat(cosign); err != nil {
+		if cosign, err = exec.LookPath("cosign"); err != nil {
+			return fmt.Errorf("cosign is required to verify the sigstore bundle - pin it under dependencies.tools first")
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, cosign, "verify-blob",
+		"--bundle", bundle,
+		"--certificate-identity", tool.CertificateIdentity,
+		"--certificate-oidc-issuer", tool.CertificateOIDCIssuer,
+		file)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("sigstore verification failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+func downloadFile(ctx context.Context, url, dest string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading %s: %s", url, resp.Status)
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// moveFile renames src to dest, copying when they are on different devices
+func moveFile(src, dest string) error {
+	if err := os.Rename(src, dest); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
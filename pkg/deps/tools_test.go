@@ -0,0 +1,89 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deps
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestInstallTool(t *testing.T) {
+	content := []byte("#!/bin/sh\necho appimagetool\n")
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	t.Setenv("BAGBOY_TOOLS_DIR", t.TempDir())
+
+	tests := []struct {
+		name     string
+		sha256   string
+		expected string
+	}{
+		{"unpinned", "", "unpinned"},
+		{"mismatch", strings.Repeat("0", 64), "sha256 mismatch"},
+		{"pinned", digest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := config.ToolConfig{Platforms: map[string]config.ToolDownload{
+				currentPlatform(): {URL: server.URL + "/appimagetool", SHA256: tt.sha256},
+			}}
+			cfg := &config.Config{Dependencies: config.DependenciesConfig{Tools: map[string]config.ToolConfig{"appimagetool": tool}}}
+			manager := NewManager(cfg)
+
+			err := manager.Install(context.Background(), []string{"appimagetool"})
+			if tt.expected != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expected) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expected, err)
+				}
+				if _, err := os.Stat(toolPath("appimagetool")); err == nil {
+					t.Error("Expected an unverified download not to be installed")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Install() error: %v", err)
+			}
+			if status := manager.checkTool("appimagetool", tool.Platforms[currentPlatform()]); !status.Available {
+				t.Errorf("Expected the installed tool to match its pin: %+v", status)
+			}
+
+			// A tampered binary no longer matches the pin
+			if err := os.WriteFile(toolPath("appimagetool"), []byte("tampered"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if status := manager.checkTool("appimagetool", tool.Platforms[currentPlatform()]); status.Available {
+				t.Error("Expected a modified tool to be reported unavailable")
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package encrypt encrypts release assets to a set of recipients using age
+// or GPG for closed distributions.
+package encrypt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// Encryptor encrypts release assets
+type Encryptor struct {
+	config *config.EncryptionConfig
+}
+
+// NewEncryptor creates a new asset encryptor
+func NewEncryptor(cfg *config.EncryptionConfig) *Encryptor {
+	return &Encryptor{config: cfg}
+}
+
+// Method returns the configured encryption method
+func (e *Encryptor) Method() string {
+	if e.config.Method == "" {
+		return "age"
+	}
+	return e.config.Method
+}
+
+// Extension returns the suffix appended to encrypted assets
+func (e *Encryptor) Extension() string {
+	return "." + e.Method()
+}
+
+// Validate checks the encryption configuration and tool availability
+func (e *Encryptor) Validate() error {
+	switch e.Method() {
+	case "age", "gpg":
+	default:
+		return fmt.Errorf("unsupported encryption method %q (expected age or gpg)", e.config.Method)
+	}
+
+	if len(e.config.Recipients) == 0 {
+		return fmt.Errorf("encryption.recipients is required")
+	}
+
+	if _, err := exec.LookPath(e.Method()); err != nil {
+		return fmt.Errorf("%s not found - install it to encrypt release assets", e.Method())
+	}
+
+	return nil
+}
+
+// ShouldEncrypt reports whether an asset should be encrypted. Installer
+// scripts stay in plain text since they carry the decrypt helper.
+func ShouldEncrypt(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+
+	base := filepath.Base(path)
+	if strings.HasPrefix(base, "install") && (strings.HasSuffix(base, ".sh") || strings.HasSuffix(base, ".ps1")) {
+		return false
+	}
+	return true
+}
+
+// EncryptFile encrypts path for all recipients and returns the path of the
+// encrypted file
+func (e *Encryptor) EncryptFile(ctx context.Context, path string) (string, error) {
+	outputPath := path + e.Extension()
+
+	var args []string
+	switch e.Method() {
+	case "age":
+		for _, recipient := range e.config.Recipients {
+			if strings.HasPrefix(recipient, "age1") || strings.HasPrefix(recipient, "ssh-") {
+				args = append(args, "-r", recipient)
+			} else {
+				// Treat anything else as a recipients file
+				args = append(args, "-R", recipient)
+			}
+		}
+		args = append(args, "-o", outputPath, path)
+	case "gpg":
+		args = []string{"--batch", "--yes", "--trust-model", "always", "--encrypt"}
+		for _, recipient := range e.config.Recipients {
+			args = append(args, "--recipient", recipient)
+		}
+		args = append(args, "--output", outputPath, path)
+	default:
+		return "", fmt.Errorf("unsupported encryption method %q", e.Method())
+	}
+
+	cmd := exec.CommandContext(ctx, e.Method(), args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("%s encryption of %s failed: %w\nOutput: %s", e.Method(), filepath.Base(path), err, output)
+	}
+
+	return outputPath, nil
+}
+
+// EncryptAll encrypts every asset that should be encrypted and returns the
+// list of assets to upload in the same order
+func (e *Encryptor) EncryptAll(ctx context.Context, assets []string) ([]string, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		if !ShouldEncrypt(asset) {
+			result = append(result, asset)
+			continue
+		}
+
+		encrypted, err := e.EncryptFile(ctx, asset)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, encrypted)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encrypt
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestMethodAndExtension(t *testing.T) {
+	e := NewEncryptor(&config.EncryptionConfig{})
+	if e.Method() != "age" || e.Extension() != ".age" {
+		t.Errorf("Expected age by default, got %s (%s)", e.Method(), e.Extension())
+	}
+
+	e = NewEncryptor(&config.EncryptionConfig{Method: "gpg"})
+	if e.Method() != "gpg" || e.Extension() != ".gpg" {
+		t.Errorf("Expected gpg, got %s (%s)", e.Method(), e.Extension())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.EncryptionConfig
+	}{
+		{"unknown method", config.EncryptionConfig{Method: "rot13", Recipients: []string{"someone"}}},
+		{"no recipients", config.EncryptionConfig{Method: "gpg"}},
+	}
+
+	for _, tt := range tests {
+		if err := NewEncryptor(&tt.cfg).Validate(); err == nil {
+			t.Errorf("%s: expected validation error", tt.name)
+		}
+	}
+
+	cfg := &config.EncryptionConfig{Method: "age", Recipients: []string{"age1example"}}
+	err := NewEncryptor(cfg).Validate()
+	if _, lookErr := exec.LookPath("age"); lookErr != nil && err == nil {
+		t.Error("Expected error when age is not installed")
+	}
+}
+
+func TestShouldEncrypt(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]bool{
+		"myapp_1.0.0_amd64.deb": true,
+		"myapp-1.0.0.msi":       true,
+		"install.sh":            false,
+		"install-private.sh":    false,
+		"install.ps1":           false,
+	}
+	for name := range files {
+		os.WriteFile(filepath.Join(dir, name), []byte("data"), 0644)
+	}
+
+	for name, expected := range files {
+		if got := ShouldEncrypt(filepath.Join(dir, name)); got != expected {
+			t.Errorf("ShouldEncrypt(%s) = %v, expected %v", name, got, expected)
+		}
+	}
+
+	if ShouldEncrypt(dir) {
+		t.Error("Directories should not be encrypted")
+	}
+	if ShouldEncrypt(filepath.Join(dir, "missing")) {
+		t.Error("Missing files should not be encrypted")
+	}
+}
+
+func TestEncryptAll_InvalidConfig(t *testing.T) {
+	e := NewEncryptor(&config.EncryptionConfig{Method: "gpg"})
+	if _, err := e.EncryptAll(context.Background(), []string{"asset"}); err == nil {
+		t.Error("Expected error without recipients")
+	}
+}
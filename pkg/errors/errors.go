@@ -138,6 +138,8 @@ const (
 	CodeExternalToolFailed = "EXTERNAL_TOOL_FAILED"
 	CodeFileNotFound       = "FILE_NOT_FOUND"
 	CodePermissionDenied   = "PERMISSION_DENIED"
+	CodeNotConfigured      = "NOT_CONFIGURED" // a format's required settings are not set
+	CodeSecretFound        = "SECRET_FOUND"   // a generated file contains a credential
 )
 
 // MissingBinaryError creates a standardized missing binary error
@@ -166,6 +168,24 @@ func MissingDependencyError(tool, installCmd string) *BagboyError {
 	)
 }
 
+// ToolNotFoundError reports an external tool a package format needs that
+// is not installed, e.g. "rpmbuild not found - install rpm-build package"
+func ToolNotFoundError(message string) *BagboyError {
+	return NewDependencyError(CodeMissingDependency, message, "Check if the tool is in your PATH")
+}
+
+// NotConfiguredError reports a setting a package format needs that is not
+// set, so the format is skipped rather than failed
+func NotConfiguredError(message string) *BagboyError {
+	return NewConfigurationError(CodeNotConfigured, message, "Set it in bagboy.yaml to build this format")
+}
+
+// NoPlatformBinaryError reports that there is no binary for the platform
+// a package format targets
+func NoPlatformBinaryError(message string) *BagboyError {
+	return NewValidationError(CodeMissingBinary, message, "Add a binary for the platform to the 'binaries' section in bagboy.yaml")
+}
+
 // ExternalToolError creates a standardized external tool error
 func ExternalToolError(tool string, cause error) *BagboyError {
 	return NewExternalError(
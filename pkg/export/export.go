@@ -0,0 +1,381 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package export renders a bagboy config as the equivalent configuration
+// for other release tools, so projects can interoperate with or migrate to
+// them.
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/github"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats are the supported export formats
+var Formats = []string{"nfpm", "goreleaser"}
+
+// Result is an exported config and what could not be translated
+type Result struct {
+	Data  []byte
+	Notes []string
+}
+
+// Export renders cfg in format. arch selects the Linux binary for nfpm;
+// empty means the primary binary.
+func Export(cfg *config.Config, format, arch string) (*Result, error) {
+	switch format {
+	case "nfpm":
+		return NFPM(cfg, arch)
+	case "goreleaser":
+		return Goreleaser(cfg)
+	default:
+		return nil, fmt.Errorf("unknown export format %q (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+type nfpmFileInfo struct {
+	Mode string `yaml:"mode"`
+}
+
+type nfpmContent struct {
+	Src      string       `yaml:"src"`
+	Dst      string       `yaml:"dst"`
+	FileInfo nfpmFileInfo `yaml:"file_info"`
+}
+
+type nfpmConfig struct {
+	Name        string        `yaml:"name"`
+	Arch        string        `yaml:"arch"`
+	Platform    string        `yaml:"platform"`
+	Version     string        `yaml:"version"`
+	Section     string        `yaml:"section,omitempty"`
+	Priority    string        `yaml:"priority,omitempty"`
+	Maintainer  string        `yaml:"maintainer,omitempty"`
+	Description string        `yaml:"description,omitempty"`
+	Vendor      string        `yaml:"vendor,omitempty"`
+	Homepage    string        `yaml:"homepage,omitempty"`
+	License     string        `yaml:"license,omitempty"`
+	Contents    []nfpmContent `yaml:"contents"`
+}
+
+// NFPM renders an nfpm config for one Linux binary. nfpm builds one
+// architecture per config, so other architectures are reported in the
+// notes.
+func NFPM(cfg *config.Config, arch string) (*Result, error) {
+	binaries := packager.LinuxBinaries(cfg)
+	if len(binaries) == 0 {
+		return nil, fmt.Errorf("no Linux binary configured")
+	}
+
+	binary := binaries[0]
+	if arch != "" {
+		found := false
+		for _, candidate := range binaries {
+			if candidate.Arch == arch {
+				binary, found = candidate, true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no linux-%s binary configured", arch)
+		}
+	}
+
+	result := &Result{}
+	for _, other := range binaries {
+		if other.Arch != binary.Arch {
+			result.Notes = append(result.Notes, fmt.Sprintf("linux-%s is exported separately with --arch %s", other.Arch, other.Arch))
+		}
+	}
+
+	maintainer := cfg.Packages.Deb.Maintainer
+	if maintainer == "" {
+		if maintainers := cfg.Maintainers(); len(maintainers) > 0 {
+			maintainer = maintainers[0].String()
+		}
+	}
+
+	nfpm := nfpmConfig{
+		Name:        cfg.Name,
+		Arch:        binary.Arch,
+		Platform:    "linux",
+		Version:     cfg.Version,
+		Section:     cfg.Packages.Deb.Section,
+		Priority:    cfg.Packages.Deb.Priority,
+		Maintainer:  maintainer,
+		Description: cfg.Description,
+		Vendor:      cfg.Packages.RPM.Vendor,
+		Homepage:    cfg.Homepage,
+		License:     cfg.License,
+		Contents: []nfpmContent{{
+			Src:      binary.Path,
+			Dst:      "/usr/bin/" + cfg.Name,
+			FileInfo: nfpmFileInfo{Mode: "0755"},
+		}},
+	}
+
+	if len(cfg.Packages.Deb.Alternatives) > 0 || len(cfg.Packages.RPM.Alternatives) > 0 {
+		result.Notes = append(result.Notes, "alternatives: add update-alternatives calls to nfpm scripts by hand")
+	}
+
+	data, err := marshal(nfpm)
+	if err != nil {
+		return nil, err
+	}
+	result.Data = data
+	return result, nil
+}
+
+type goreleaserRepo struct {
+	Owner string `yaml:"owner"`
+	Name  string `yaml:"name"`
+}
+
+type goreleaserIgnore struct {
+	Goos   string `yaml:"goos"`
+	Goarch string `yaml:"goarch"`
+}
+
+type goreleaserBuild struct {
+	Binary string             `yaml:"binary"`
+	Goos   []string           `yaml:"goos"`
+	Goarch []string           `yaml:"goarch"`
+	Ignore []goreleaserIgnore `yaml:"ignore,omitempty"`
+}
+
+type goreleaserFormatOverride struct {
+	Goos    string   `yaml:"goos"`
+	Formats []string `yaml:"formats"`
+}
+
+type goreleaserArchive struct {
+	Formats         []string                   `yaml:"formats"`
+	FormatOverrides []goreleaserFormatOverride `yaml:"format_overrides,omitempty"`
+}
+
+type goreleaserBrew struct {
+	Repository  goreleaserRepo `yaml:"repository"`
+	Homepage    string         `yaml:"homepage,omitempty"`
+	Description string         `yaml:"description,omitempty"`
+	License     string         `yaml:"license,omitempty"`
+	Test        string         `yaml:"test,omitempty"`
+	Caveats     string         `yaml:"caveats,omitempty"`
+	Conflicts   []string       `yaml:"conflicts,omitempty"`
+}
+
+type goreleaserScoop struct {
+	Repository  goreleaserRepo `yaml:"repository"`
+	Homepage    string         `yaml:"homepage,omitempty"`
+	Description string         `yaml:"description,omitempty"`
+	License     string         `yaml:"license,omitempty"`
+}
+
+type goreleaserNFPM struct {
+	Maintainer  string   `yaml:"maintainer,omitempty"`
+	Vendor      string   `yaml:"vendor,omitempty"`
+	Homepage    string   `yaml:"homepage,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+	License     string   `yaml:"license,omitempty"`
+	Section     string   `yaml:"section,omitempty"`
+	Priority    string   `yaml:"priority,omitempty"`
+	Formats     []string `yaml:"formats"`
+}
+
+type goreleaserDocker struct {
+	ImageTemplates []string `yaml:"image_templates"`
+}
+
+type goreleaserRelease struct {
+	GitHub     goreleaserRepo `yaml:"github"`
+	Draft      bool           `yaml:"draft,omitempty"`
+	Prerelease string         `yaml:"prerelease,omitempty"`
+}
+
+type goreleaserConfig struct {
+	Version     int                 `yaml:"version"`
+	ProjectName string              `yaml:"project_name"`
+	Builds      []goreleaserBuild   `yaml:"builds"`
+	Archives    []goreleaserArchive `yaml:"archives"`
+	Brews       []goreleaserBrew    `yaml:"brews,omitempty"`
+	Scoops      []goreleaserScoop   `yaml:"scoops,omitempty"`
+	Nfpms       []goreleaserNFPM    `yaml:"nfpms,omitempty"`
+	Dockers     []goreleaserDocker  `yaml:"dockers,omitempty"`
+	Release     *goreleaserRelease  `yaml:"release,omitempty"`
+}
+
+// Goreleaser renders a goreleaser v2 config. Goreleaser builds from
+// source, so the build targets are derived from the configured binaries.
+func Goreleaser(cfg *config.Config) (*Result, error) {
+	platforms := platforms(cfg)
+	if len(platforms) == 0 {
+		return nil, fmt.Errorf("no binaries configured")
+	}
+
+	result := &Result{Notes: []string{"builds: goreleaser compiles from source; check main and ldflags"}}
+
+	build := goreleaserBuild{Binary: cfg.Name}
+	goos := make(map[string]bool)
+	goarch := make(map[string]bool)
+	built := make(map[string]bool)
+	for _, platform := range platforms {
+		system, arch, _ := strings.Cut(platform, "-")
+		goos[system], goarch[arch], built[platform] = true, true, true
+	}
+	build.Goos = sortedKeys(goos)
+	build.Goarch = sortedKeys(goarch)
+	// Exclude the combinations of goos and goarch that were not configured
+	for _, system := range build.Goos {
+		for _, arch := range build.Goarch {
+			if !built[system+"-"+arch] {
+				build.Ignore = append(build.Ignore, goreleaserIgnore{Goos: system, Goarch: arch})
+			}
+		}
+	}
+
+	archive := goreleaserArchive{Formats: []string{"tar.gz"}}
+	if goos["windows"] {
+		archive.FormatOverrides = []goreleaserFormatOverride{{Goos: "windows", Formats: []string{"zip"}}}
+	}
+
+	gr := goreleaserConfig{
+		Version:     2,
+		ProjectName: cfg.Name,
+		Builds:      []goreleaserBuild{build},
+		Archives:    []goreleaserArchive{archive},
+	}
+
+	if cfg.GitHub.Tap.Enabled {
+		var conflicts []string
+		for _, conflict := range cfg.Packages.Brew.ConflictsWith {
+			conflicts = append(conflicts, conflict.Name)
+		}
+		gr.Brews = []goreleaserBrew{{
+			Repository:  repo(github.TapRepo(cfg)),
+			Homepage:    cfg.Homepage,
+			Description: cfg.Description,
+			License:     cfg.License,
+			Test:        cfg.Packages.Brew.Test,
+			Caveats:     cfg.Packages.Brew.Caveats,
+			Conflicts:   conflicts,
+		}}
+	}
+
+	if cfg.GitHub.Bucket.Enabled {
+		gr.Scoops = []goreleaserScoop{{
+			Repository:  repo(github.BucketRepo(cfg)),
+			Homepage:    cfg.Homepage,
+			Description: cfg.Description,
+			License:     cfg.License,
+		}}
+	}
+
+	if _, ok := packager.PrimaryLinuxBinary(cfg); ok {
+		nfpm := goreleaserNFPM{
+			Maintainer:  cfg.Packages.Deb.Maintainer,
+			Vendor:      cfg.Packages.RPM.Vendor,
+			Homepage:    cfg.Homepage,
+			Description: cfg.Description,
+			License:     cfg.License,
+			Section:     cfg.Packages.Deb.Section,
+			Priority:    cfg.Packages.Deb.Priority,
+			Formats:     []string{"deb", "rpm"},
+		}
+		if nfpm.Maintainer == "" {
+			if maintainers := cfg.Maintainers(); len(maintainers) > 0 {
+				nfpm.Maintainer = maintainers[0].String()
+			}
+		}
+		gr.Nfpms = []goreleaserNFPM{nfpm}
+	}
+
+	if len(cfg.Packages.Docker.Registries) > 0 {
+		docker := goreleaserDocker{}
+		for _, registry := range cfg.Packages.Docker.Registries {
+			docker.ImageTemplates = append(docker.ImageTemplates, registry+":{{ .Version }}")
+		}
+		gr.Dockers = []goreleaserDocker{docker}
+	}
+
+	if cfg.GitHub.Release.Enabled && cfg.GitHub.Owner != "" {
+		gr.Release = &goreleaserRelease{
+			GitHub: goreleaserRepo{Owner: cfg.GitHub.Owner, Name: cfg.GitHub.Repo},
+			Draft:  cfg.GitHub.Release.Draft,
+		}
+		if cfg.GitHub.Release.Prerelease {
+			gr.Release.Prerelease = "true"
+		}
+	}
+
+	if cfg.GitHub.Winget.Enabled {
+		result.Notes = append(result.Notes, "winget: add a winget section by hand")
+	}
+	if cfg.Signing.MacOS.Identity != "" || cfg.Signing.Windows.CertificateThumbprint != "" {
+		result.Notes = append(result.Notes, "signing: native code signing has no goreleaser OSS equivalent")
+	}
+
+	data, err := marshal(gr)
+	if err != nil {
+		return nil, err
+	}
+	result.Data = data
+	return result, nil
+}
+
+// platforms returns the configured os-arch pairs, including platforms
+// only available as pre-built archives
+func platforms(cfg *config.Config) []string {
+	seen := make(map[string]bool)
+	for platform := range cfg.Binaries {
+		seen[platform] = true
+	}
+	for platform := range cfg.Prebuilt.Archives {
+		seen[platform] = true
+	}
+	return sortedKeys(seen)
+}
+
+func repo(fullName string) goreleaserRepo {
+	owner, name, _ := strings.Cut(fullName, "/")
+	return goreleaserRepo{Owner: owner, Name: name}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
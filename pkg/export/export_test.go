@@ -0,0 +1,118 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+func testConfig() *config.Config {
+	return &config.Config{
+		Name:        "myapp",
+		Version:     "1.2.3",
+		Description: "My app",
+		License:     "MIT",
+		Author:      "Jane Doe <jane@example.com>",
+		Binaries: map[string]string{
+			"linux-amd64":   "dist/myapp-linux-amd64",
+			"linux-arm64":   "dist/myapp-linux-arm64",
+			"darwin-arm64":  "dist/myapp-darwin-arm64",
+			"windows-amd64": "dist/myapp-windows-amd64.exe",
+		},
+	}
+}
+
+func TestNFPM(t *testing.T) {
+	result, err := NFPM(testConfig(), "arm64")
+	if err != nil {
+		t.Fatalf("NFPM() error: %v", err)
+	}
+
+	var nfpm nfpmConfig
+	if err := yaml.Unmarshal(result.Data, &nfpm); err != nil {
+		t.Fatalf("Invalid yaml: %v", err)
+	}
+	if nfpm.Arch != "arm64" || nfpm.Version != "1.2.3" || nfpm.Maintainer != "Jane Doe <jane@example.com>" {
+		t.Errorf("Unexpected nfpm config: %+v", nfpm)
+	}
+	expected := []nfpmContent{{Src: "dist/myapp-linux-arm64", Dst: "/usr/bin/myapp", FileInfo: nfpmFileInfo{Mode: "0755"}}}
+	if !reflect.DeepEqual(nfpm.Contents, expected) {
+		t.Errorf("Contents = %+v", nfpm.Contents)
+	}
+	if len(result.Notes) != 1 || !strings.Contains(result.Notes[0], "--arch amd64") {
+		t.Errorf("Notes = %v", result.Notes)
+	}
+
+	if _, err := NFPM(testConfig(), "riscv64"); err == nil {
+		t.Error("Expected error for an unconfigured architecture")
+	}
+}
+
+func TestGoreleaser(t *testing.T) {
+	cfg := testConfig()
+	cfg.GitHub.Owner = "acme"
+	cfg.GitHub.Repo = "myapp"
+	cfg.GitHub.Release.Enabled = true
+	cfg.GitHub.Tap.Enabled = true
+	cfg.GitHub.Tap.Repo = "acme/homebrew-tap"
+	cfg.Packages.Docker.Registries = []string{"ghcr.io/acme/myapp"}
+
+	result, err := Export(cfg, "goreleaser", "")
+	if err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+
+	var gr goreleaserConfig
+	if err := yaml.Unmarshal(result.Data, &gr); err != nil {
+		t.Fatalf("Invalid yaml: %v", err)
+	}
+
+	build := gr.Builds[0]
+	if !reflect.DeepEqual(build.Goos, []string{"darwin", "linux", "windows"}) || !reflect.DeepEqual(build.Goarch, []string{"amd64", "arm64"}) {
+		t.Errorf("Unexpected targets: %v %v", build.Goos, build.Goarch)
+	}
+	expectedIgnore := []goreleaserIgnore{{Goos: "darwin", Goarch: "amd64"}, {Goos: "windows", Goarch: "arm64"}}
+	if !reflect.DeepEqual(build.Ignore, expectedIgnore) {
+		t.Errorf("Ignore = %+v", build.Ignore)
+	}
+	if len(gr.Archives[0].FormatOverrides) != 1 || gr.Archives[0].FormatOverrides[0].Goos != "windows" {
+		t.Errorf("Expected a zip override for windows, got %+v", gr.Archives[0])
+	}
+	if len(gr.Brews) != 1 || gr.Brews[0].Repository != (goreleaserRepo{Owner: "acme", Name: "homebrew-tap"}) {
+		t.Errorf("Brews = %+v", gr.Brews)
+	}
+	if len(gr.Scoops) != 0 {
+		t.Errorf("Expected no scoops without a bucket, got %+v", gr.Scoops)
+	}
+	if len(gr.Dockers) != 1 || gr.Dockers[0].ImageTemplates[0] != "ghcr.io/acme/myapp:{{ .Version }}" {
+		t.Errorf("Dockers = %+v", gr.Dockers)
+	}
+	if gr.Release == nil || gr.Release.GitHub.Owner != "acme" {
+		t.Errorf("Release = %+v", gr.Release)
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	if _, err := Export(testConfig(), "snapcraft", ""); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
@@ -0,0 +1,147 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/semver"
+)
+
+// BackfillTags returns the tags of the published releases whose versions
+// are from to to inclusive, oldest first. Drafts, prereleases and tags
+// that don't match tag_pattern are left out.
+func BackfillTags(cfg *config.Config, releases []*github.RepositoryRelease, from, to string) ([]string, error) {
+	from, to = strings.TrimPrefix(from, "v"), strings.TrimPrefix(to, "v")
+	for _, bound := range []string{from, to} {
+		if _, err := semver.Parse(bound); err != nil {
+			return nil, fmt.Errorf("invalid version %q: %w", bound, err)
+		}
+	}
+	if semver.Less(to, from) {
+		return nil, fmt.Errorf("--from %s is above --to %s", from, to)
+	}
+
+	versions := make(map[string]string)
+	var tags []string
+	for _, release := range releases {
+		if release.GetDraft() || release.GetPrerelease() {
+			continue
+		}
+		tag := release.GetTagName()
+		version, err := cfg.TagVersion(tag)
+		if err != nil {
+			continue
+		}
+		if _, err := semver.Parse(version); err != nil {
+			continue
+		}
+		if semver.Less(version, from) || semver.Less(to, version) {
+			continue
+		}
+		versions[tag] = version
+		tags = append(tags, tag)
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		return semver.Less(versions[tags[i]], versions[tags[j]])
+	})
+	return tags, nil
+}
+
+// DownloadBinaries downloads the binaries of cfg's release into dir and
+// points cfg's binaries at them. Platforms the release has no asset for
+// are dropped, as they were added to the config later.
+func (c *Client) DownloadBinaries(ctx context.Context, cfg *config.Config, dir string) error {
+	release, _, err := c.gh.Repositories.GetReleaseByTag(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo, cfg.Tag())
+	if err != nil {
+		return fmt.Errorf("failed to get release %s: %w", cfg.Tag(), err)
+	}
+	assets := make(map[string]*github.ReleaseAsset)
+	for _, asset := range release.Assets {
+		assets[asset.GetName()] = asset
+	}
+
+	for platform := range cfg.Binaries {
+		asset, ok := assets[packager.AssetName(cfg, platform)]
+		if !ok {
+			delete(cfg.Binaries, platform)
+			continue
+		}
+		data, err := c.downloadAsset(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo, asset)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, asset.GetName())
+		if err := os.WriteFile(path, []byte(data), 0755); err != nil {
+			return err
+		}
+		cfg.Binaries[platform] = path
+	}
+	if len(cfg.Binaries) == 0 {
+		return fmt.Errorf("release %s has no binaries named like %s", cfg.Tag(), packager.AssetName(cfg, "<os>-<arch>"))
+	}
+	return nil
+}
+
+// BackfillTap commits formula for an earlier release as its versioned
+// formula, leaving the current formula alone
+func (c *Client) BackfillTap(ctx context.Context, cfg *config.Config, formula string) error {
+	tapOwner, tapRepo, ok := strings.Cut(TapRepo(cfg), "/")
+	if !ok {
+		return fmt.Errorf("invalid tap repo format: %s", TapRepo(cfg))
+	}
+	path, err := VersionedFormulaPath(cfg)
+	if err != nil {
+		return err
+	}
+	versioned, err := VersionedFormula(cfg, formula)
+	if err != nil {
+		return err
+	}
+	return c.updateFile(ctx, tapOwner, tapRepo, path, versioned, fmt.Sprintf("Backfill %s v%s", cfg.Name, cfg.Version))
+}
+
+// BackfillBucket commits manifest for an earlier release to the bucket's
+// archive, leaving the current manifest alone
+func (c *Client) BackfillBucket(ctx context.Context, cfg *config.Config, manifest string) error {
+	bucketOwner, bucketRepo, ok := strings.Cut(BucketRepo(cfg), "/")
+	if !ok {
+		return fmt.Errorf("invalid bucket repo format: %s", BucketRepo(cfg))
+	}
+	return c.updateFile(ctx, bucketOwner, bucketRepo, ArchivedManifestPath(cfg), manifest, fmt.Sprintf("Backfill %s v%s", cfg.Name, cfg.Version))
+}
+
+// WaitForRateLimit sleeps until the core rate limit resets when fewer
+// than reserve requests are left in it
+func (c *Client) WaitForRateLimit(ctx context.Context, reserve int) error {
+	limits, _, err := c.gh.RateLimit.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	core := limits.GetCore()
+	if core == nil || core.Remaining >= reserve {
+		return nil
+	}
+
+	wait := time.Until(core.Reset.Time)
+	if wait <= 0 {
+		return nil
+	}
+	fmt.Printf("⏳ %d API requests left, waiting %s for the rate limit to reset\n", core.Remaining, wait.Round(time.Second))
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestBackfillTags(t *testing.T) {
+	cfg := &config.Config{}
+	releases := []*github.RepositoryRelease{
+		{TagName: github.String("v1.10.0")},
+		{TagName: github.String("v1.2.0")},
+		{TagName: github.String("v1.3.0-rc.1"), Prerelease: github.Bool(true)},
+		{TagName: github.String("v1.4.0"), Draft: github.Bool(true)},
+		{TagName: github.String("v0.9.0")},
+		{TagName: github.String("nightly")},
+		{TagName: github.String("v1.9.1")},
+	}
+
+	tags, err := BackfillTags(cfg, releases, "v1.0.0", "1.10.0")
+	if err != nil {
+		t.Fatalf("BackfillTags failed: %v", err)
+	}
+	if len(tags) != 3 || tags[0] != "v1.2.0" || tags[1] != "v1.9.1" || tags[2] != "v1.10.0" {
+		t.Errorf("BackfillTags() = %v, expected [v1.2.0 v1.9.1 v1.10.0]", tags)
+	}
+
+	if _, err := BackfillTags(cfg, releases, "v2.0.0", "v1.0.0"); err == nil {
+		t.Error("Expected an error for a reversed range")
+	}
+	if _, err := BackfillTags(cfg, releases, "latest", "v1.0.0"); err == nil {
+		t.Error("Expected an error for an invalid version")
+	}
+}
+
+func TestDownloadBinaries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/myapp/releases/tags/v1.2.0":
+			w.Write([]byte(`{"tag_name": "v1.2.0", "assets": [{"id": 1, "name": "myapp-linux-amd64"}]}`))
+		case "/repos/acme/myapp/releases/assets/1":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte("myapp 1.2.0"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Name:     "myapp",
+		Version:  "1.2.0",
+		GitHub:   config.GitHubConfig{Owner: "acme", Repo: "myapp"},
+		Binaries: map[string]string{"linux-amd64": "dist/myapp", "linux-riscv64": "dist/myapp-riscv64"},
+	}
+	if err := newTestClient(t, server.URL).DownloadBinaries(context.Background(), cfg, t.TempDir()); err != nil {
+		t.Fatalf("DownloadBinaries failed: %v", err)
+	}
+	if _, ok := cfg.Binaries["linux-riscv64"]; ok {
+		t.Error("Expected the platform missing from the release to be dropped")
+	}
+	data, err := os.ReadFile(cfg.Binaries["linux-amd64"])
+	if err != nil || string(data) != "myapp 1.2.0" {
+		t.Errorf("Downloaded binary = %q, %v", data, err)
+	}
+}
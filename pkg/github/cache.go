@@ -0,0 +1,125 @@
+package github
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+)
+
+// CacheDir returns the directory GitHub API responses are cached in:
+// $BAGBOY_CACHE_DIR, or bagboy/github under the user cache directory
+// (~/.cache on Linux). It returns "" when caching is disabled with
+// BAGBOY_NO_CACHE or no cache directory is available.
+func CacheDir() string {
+	if os.Getenv("BAGBOY_NO_CACHE") != "" {
+		return ""
+	}
+	if dir := os.Getenv("BAGBOY_CACHE_DIR"); dir != "" {
+		return filepath.Join(dir, "github")
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "bagboy", "github")
+}
+
+// cachingTransport revalidates GET responses with ETags. A cached response
+// is sent with If-None-Match; GitHub answers 304 without counting the
+// request against the rate limit, and the cached response is returned.
+// It must sit below the oauth2 transport so the cache key includes the
+// Authorization header and one token never sees another's responses.
+type cachingTransport struct {
+	base http.RoundTripper
+	dir  string
+}
+
+func newCachingTransport(base http.RoundTripper, dir string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &cachingTransport{base: base, dir: dir}
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	path := t.entryPath(req)
+	cached := t.load(path, req)
+
+	if cached != nil {
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req = req.Clone(req.Context())
+			req.Header.Set("If-None-Match", etag)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		resp.Body.Close()
+		// Keep the fresh rate limit headers so go-github reports them
+		for _, key := range []string{"X-Ratelimit-Limit", "X-Ratelimit-Remaining", "X-Ratelimit-Reset"} {
+			if v := resp.Header.Get(key); v != "" {
+				cached.Header.Set(key, v)
+			}
+		}
+		return cached, nil
+	}
+
+	if resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "" {
+		t.store(path, resp)
+	}
+	return resp, nil
+}
+
+func (t *cachingTransport) entryPath(req *http.Request) string {
+	h := sha256.New()
+	h.Write([]byte(req.URL.String()))
+	h.Write([]byte{0})
+	h.Write([]byte(req.Header.Get("Accept")))
+	h.Write([]byte{0})
+	h.Write([]byte(req.Header.Get("Authorization")))
+	return filepath.Join(t.dir, hex.EncodeToString(h.Sum(nil)))
+}
+
+func (t *cachingTransport) load(path string, req *http.Request) *http.Response {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+	if err != nil {
+		os.Remove(path)
+		return nil
+	}
+	return resp
+}
+
+// store saves resp and replaces its body with the buffered copy. Failures
+// only mean the next request is not cached.
+func (t *cachingTransport) store(path string, resp *http.Response) {
+	data, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(t.dir, 0700); err != nil {
+		return
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return
+	}
+	os.Rename(tmp, path)
+}
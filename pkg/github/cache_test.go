@@ -0,0 +1,77 @@
+package github
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCachingTransport(t *testing.T) {
+	requests, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.Header().Set("X-RateLimit-Remaining", "4999")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("X-RateLimit-Remaining", "5000")
+		io.WriteString(w, `{"name":"testrepo"}`)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newCachingTransport(nil, t.TempDir())}
+
+	get := func() *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/repos/testowner/testrepo", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	for i := 0; i < 3; i++ {
+		resp := get()
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || string(body) != `{"name":"testrepo"}` {
+			t.Fatalf("Request %d: got %d %q", i, resp.StatusCode, body)
+		}
+		if i > 0 && resp.Header.Get("X-Ratelimit-Remaining") != "4999" {
+			t.Errorf("Expected fresh rate limit header, got %q", resp.Header.Get("X-Ratelimit-Remaining"))
+		}
+	}
+
+	if requests != 3 || notModified != 2 {
+		t.Errorf("Expected 3 requests with 2 revalidated, got %d and %d", requests, notModified)
+	}
+
+	// Writes are never cached
+	resp, err := client.Post(server.URL+"/repos/testowner/testrepo", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if notModified != 2 {
+		t.Error("Expected POST to bypass the cache")
+	}
+}
+
+func TestCacheDir(t *testing.T) {
+	t.Setenv("BAGBOY_CACHE_DIR", "/tmp/bagboy-cache")
+	t.Setenv("BAGBOY_NO_CACHE", "")
+	if got := CacheDir(); got != "/tmp/bagboy-cache/github" {
+		t.Errorf("Expected override cache dir, got %s", got)
+	}
+
+	t.Setenv("BAGBOY_NO_CACHE", "1")
+	if got := CacheDir(); got != "" {
+		t.Errorf("Expected caching disabled, got %s", got)
+	}
+}
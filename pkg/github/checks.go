@@ -0,0 +1,53 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/scttfrdmn/bagboy/pkg/preflight"
+)
+
+// CIRuns lists the Actions workflow runs and then the check runs on ref,
+// each newest first, so a required name can be a workflow or a single job
+// or check from any CI that reports to the Checks API
+func (c *Client) CIRuns(ctx context.Context, fullRepo, ref string) ([]preflight.CIRun, error) {
+	owner, repo, ok := strings.Cut(fullRepo, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid repo format: %s", fullRepo)
+	}
+
+	var runs []preflight.CIRun
+	workflows, _, err := c.gh.Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, &github.ListWorkflowRunsOptions{
+		HeadSHA:     ref,
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+	}
+	for _, run := range workflows.WorkflowRuns {
+		runs = append(runs, preflight.CIRun{
+			Name:       run.GetName(),
+			Status:     run.GetStatus(),
+			Conclusion: run.GetConclusion(),
+			URL:        run.GetHTMLURL(),
+		})
+	}
+
+	checks, _, err := c.gh.Checks.ListCheckRunsForRef(ctx, owner, repo, ref, &github.ListCheckRunsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list check runs: %w", err)
+	}
+	for _, run := range checks.CheckRuns {
+		runs = append(runs, preflight.CIRun{
+			Name:       run.GetName(),
+			Status:     run.GetStatus(),
+			Conclusion: run.GetConclusion(),
+			URL:        run.GetHTMLURL(),
+		})
+	}
+	return runs, nil
+}
@@ -0,0 +1,39 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCIRuns(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/myapp/actions/runs":
+			if r.URL.Query().Get("head_sha") != "abc123" {
+				t.Errorf("Unexpected query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"total_count": 1, "workflow_runs": [{"name": "CI", "status": "completed", "conclusion": "success", "html_url": "https://github.com/acme/myapp/actions/runs/1"}]}`))
+		case "/repos/acme/myapp/commits/abc123/check-runs":
+			w.Write([]byte(`{"total_count": 1, "check_runs": [{"name": "test (ubuntu-latest)", "status": "in_progress"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	runs, err := newTestClient(t, server.URL).CIRuns(context.Background(), "acme/myapp", "abc123")
+	if err != nil {
+		t.Fatalf("CIRuns failed: %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("Expected 2 runs, got %+v", runs)
+	}
+	if runs[0].Name != "CI" || runs[0].Conclusion != "success" || runs[0].URL == "" {
+		t.Errorf("Unexpected workflow run %+v", runs[0])
+	}
+	if runs[1].Name != "test (ubuntu-latest)" || runs[1].Status != "in_progress" {
+		t.Errorf("Unexpected check run %+v", runs[1])
+	}
+}
@@ -2,19 +2,31 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/google/go-github/v57/github"
+	"github.com/scttfrdmn/bagboy/pkg/audit"
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/semver"
+	"github.com/scttfrdmn/bagboy/pkg/upload"
 	"golang.org/x/oauth2"
 )
 
 type Client struct {
 	gh  *github.Client
 	cfg *config.GitHubConfig
+
+	// stagingBranch, when set, redirects tap and bucket updates to a
+	// branch that is merged later by FinalizeRelease
+	stagingBranch string
 }
 
 func NewClient(cfg *config.GitHubConfig) (*Client, error) {
@@ -26,6 +38,14 @@ func NewClient(cfg *config.GitHubConfig) (*Client, error) {
 	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
 	tc := oauth2.NewClient(context.Background(), ts)
 
+	// Revalidate lookups with ETags so repeated runs don't spend rate limit
+	if dir := CacheDir(); dir != "" {
+		tc.Transport = &oauth2.Transport{
+			Source: ts,
+			Base:   newCachingTransport(http.DefaultTransport, dir),
+		}
+	}
+
 	return &Client{
 		gh:  github.NewClient(tc),
 		cfg: cfg,
@@ -33,54 +53,210 @@ func NewClient(cfg *config.GitHubConfig) (*Client, error) {
 }
 
 func (c *Client) CreateRelease(ctx context.Context, cfg *config.Config, assets []string) (*github.RepositoryRelease, error) {
-	release := &github.RepositoryRelease{
-		TagName:              github.String("v" + cfg.Version),
-		Name:                 github.String("v" + cfg.Version),
-		Body:                 github.String(fmt.Sprintf("Release %s", cfg.Version)),
-		Draft:                github.Bool(cfg.GitHub.Release.Draft),
-		Prerelease:           github.Bool(cfg.GitHub.Release.Prerelease),
-		GenerateReleaseNotes: github.Bool(cfg.GitHub.Release.GenerateNotes),
+	body, err := ReleaseNotes(cfg, assets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write release notes: %w", err)
+	}
+
+	// An immutable release is only ever changed as a draft, so a draft
+	// left by an earlier attempt is reused and a published one refused
+	immutable := cfg.GitHub.Release.Immutable
+	var rel *github.RepositoryRelease
+	if immutable {
+		if rel, err = c.checkImmutable(ctx, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if rel == nil {
+		release := &github.RepositoryRelease{
+			TagName:              github.String(cfg.Tag()),
+			Name:                 github.String(cfg.Tag()),
+			Body:                 github.String(body),
+			Draft:                github.Bool(cfg.GitHub.Release.Draft || immutable),
+			Prerelease:           github.Bool(cfg.GitHub.Release.Prerelease),
+			GenerateReleaseNotes: github.Bool(cfg.GitHub.Release.GenerateNotes),
+		}
+
+		var resp *github.Response
+		rel, resp, err = c.gh.Repositories.CreateRelease(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo, release)
+		record(ctx, audit.ReleaseCreate, releaseTarget(cfg), rel.GetHTMLURL(), resp, err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create release: %w", err)
+		}
+	} else {
+		fmt.Printf("✅ Reusing draft release %s\n", cfg.Tag())
 	}
 
-	rel, _, err := c.gh.Repositories.CreateRelease(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo, release)
+	// Upload assets, replacing any the reused draft already has
+	replace := len(rel.Assets) > 0
+	err = upload.Each(ctx, len(assets), func(ctx context.Context, i int) error {
+		if replace {
+			if err := c.deletePartialAsset(ctx, cfg, rel.GetID(), filepath.Base(assets[i])); err != nil {
+				return fmt.Errorf("failed to replace asset %s: %w", assets[i], err)
+			}
+		}
+		if err := c.uploadAsset(ctx, cfg, rel.GetID(), assets[i]); err != nil {
+			return fmt.Errorf("failed to upload asset %s: %w", assets[i], err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create release: %w", err)
+		return nil, err
 	}
 
-	// Upload assets
-	for _, asset := range assets {
-		if err := c.uploadAsset(ctx, cfg, rel.GetID(), asset); err != nil {
-			return nil, fmt.Errorf("failed to upload asset %s: %w", asset, err)
+	if immutable && !cfg.GitHub.Release.Draft {
+		var resp *github.Response
+		rel, resp, err = c.gh.Repositories.EditRelease(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo, rel.GetID(), &github.RepositoryRelease{
+			Draft: github.Bool(false),
+		})
+		record(ctx, audit.ReleasePublish, releaseTarget(cfg), rel.GetHTMLURL(), resp, err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to publish release %s: %w", cfg.Tag(), err)
 		}
 	}
 
 	return rel, nil
 }
 
+// CheckImmutable fails when the release for cfg's tag is already
+// published, since github.release.immutable forbids changing it. The next
+// patch version is suggested instead.
+func (c *Client) CheckImmutable(ctx context.Context, cfg *config.Config) error {
+	_, err := c.checkImmutable(ctx, cfg)
+	return err
+}
+
+// checkImmutable returns the draft release for cfg's tag, or nil when
+// there is none, and fails when the release is published
+func (c *Client) checkImmutable(ctx context.Context, cfg *config.Config) (*github.RepositoryRelease, error) {
+	tag := cfg.Tag()
+	releases, err := c.ListReleases(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo)
+	if err != nil {
+		return nil, err
+	}
+	for _, release := range releases {
+		if release.GetTagName() != tag {
+			continue
+		}
+		if release.GetDraft() {
+			return release, nil
+		}
+		message := fmt.Sprintf("release %s is published and github.release.immutable forbids changing it", tag)
+		if v, err := semver.Parse(cfg.Version); err == nil {
+			next := semver.Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+			message += fmt.Sprintf(" - publish version %s instead", next)
+		}
+		return nil, errors.New(message)
+	}
+	return nil, nil
+}
+
+// uploadAsset uploads one release asset, retrying transient failures.
+// GitHub has no resumable uploads, so a retry first deletes whatever an
+// interrupted attempt left on the release and starts the file over.
 func (c *Client) uploadAsset(ctx context.Context, cfg *config.Config, releaseID int64, assetPath string) error {
-	file, err := os.Open(assetPath)
+	info, err := os.Stat(assetPath)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	name := filepath.Base(assetPath)
+	mediaType := mime.TypeByExtension(filepath.Ext(name))
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
 
-	opts := &github.UploadOptions{
-		Name: filepath.Base(assetPath),
+	var progress *upload.Progress
+	if info.Size() > upload.LargeAssetThreshold {
+		progress = upload.NewProgress(os.Stdout, name, info.Size())
+		defer progress.Done()
 	}
 
-	_, _, err = c.gh.Repositories.UploadReleaseAsset(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo, releaseID, opts, file)
+	return upload.Retry(ctx, upload.DefaultAttempts, func(attempt int) error {
+		if attempt > 1 {
+			if err := c.deletePartialAsset(ctx, cfg, releaseID, name); err != nil {
+				return err
+			}
+		}
+
+		file, err := os.Open(assetPath)
+		if err != nil {
+			return upload.Permanent(err)
+		}
+		defer file.Close()
+
+		var body io.Reader = file
+		if progress != nil {
+			progress.Set(0)
+			body = progress.Reader(file)
+		}
+		body = upload.Throttle(body)
+
+		u := fmt.Sprintf("repos/%s/%s/releases/%d/assets?name=%s", cfg.GitHub.Owner, cfg.GitHub.Repo, releaseID, url.QueryEscape(name))
+		req, err := c.gh.NewUploadRequest(u, body, info.Size(), mediaType)
+		if err != nil {
+			return upload.Permanent(err)
+		}
+		asset := new(github.ReleaseAsset)
+		resp, err := c.gh.Do(ctx, req, asset)
+		record(ctx, audit.AssetUpload, releaseTarget(cfg), name, resp, err)
+		return uploadError(err)
+	})
+}
+
+// deletePartialAsset removes an asset an interrupted upload left behind,
+// which would otherwise make the retry fail as a duplicate
+func (c *Client) deletePartialAsset(ctx context.Context, cfg *config.Config, releaseID int64, name string) error {
+	assets, _, err := c.gh.Repositories.ListReleaseAssets(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo, releaseID, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return uploadError(err)
+	}
+	for _, asset := range assets {
+		if asset.GetName() == name {
+			resp, err := c.gh.Repositories.DeleteReleaseAsset(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo, asset.GetID())
+			record(ctx, audit.AssetDelete, releaseTarget(cfg), name, resp, err)
+			return uploadError(err)
+		}
+	}
+	return nil
+}
+
+// uploadError converts GitHub API errors for upload.Retry: rate limits
+// and server errors are retried, other API errors are not
+func uploadError(err error) error {
+	var apiErr *github.ErrorResponse
+	if errors.As(err, &apiErr) && apiErr.Response != nil {
+		return &upload.StatusError{Code: apiErr.Response.StatusCode, Body: apiErr.Message}
+	}
 	return err
 }
 
+// EnsureTopic adds topic to the repository's topics when it is missing
+func (c *Client) EnsureTopic(ctx context.Context, owner, repo, topic string) error {
+	topics, _, err := c.gh.Repositories.ListAllTopics(ctx, owner, repo)
+	if err != nil {
+		return fmt.Errorf("failed to list topics: %w", err)
+	}
+	for _, t := range topics {
+		if t == topic {
+			return nil
+		}
+	}
+
+	_, resp, err := c.gh.Repositories.ReplaceAllTopics(ctx, owner, repo, append(topics, topic))
+	record(ctx, audit.RepoTopic, owner+"/"+repo, topic, resp, err)
+	if err != nil {
+		return fmt.Errorf("failed to add topic %s: %w", topic, err)
+	}
+	return nil
+}
+
 func (c *Client) UpdateTap(ctx context.Context, cfg *config.Config, formula string) error {
 	if !cfg.GitHub.Tap.Enabled {
 		return nil
 	}
 
-	tapRepo := cfg.GitHub.Tap.Repo
-	if tapRepo == "" {
-		tapRepo = fmt.Sprintf("%s/homebrew-tap", cfg.GitHub.Owner)
-	}
+	tapRepo := TapRepo(cfg)
 
 	parts := strings.Split(tapRepo, "/")
 	if len(parts) != 2 {
@@ -100,22 +276,54 @@ func (c *Client) UpdateTap(ctx context.Context, cfg *config.Config, formula stri
 	commitMessage := fmt.Sprintf("Update %s to v%s", cfg.Name, cfg.Version)
 	
 	if cfg.GitHub.Tap.AutoCommit {
-		return c.updateFile(ctx, tapOwner, tapRepoName, formulaPath, formula, commitMessage)
+		if err := c.updateFile(ctx, tapOwner, tapRepoName, formulaPath, formula, commitMessage); err != nil {
+			return err
+		}
+		if !cfg.GitHub.Tap.Versioned {
+			return nil
+		}
+
+		// Keep myapp@1.2 installable after 1.3 replaces the main formula
+		versionedPath, err := VersionedFormulaPath(cfg)
+		if err != nil {
+			return err
+		}
+		versioned, err := VersionedFormula(cfg, formula)
+		if err != nil {
+			return err
+		}
+		return c.updateFile(ctx, tapOwner, tapRepoName, versionedPath, versioned, commitMessage)
 	}
 
 	fmt.Printf("✅ Would update tap %s with formula (auto_commit disabled)\n", tapRepo)
 	return nil
 }
 
+// UpdateTapCask commits the cask to Casks/<token>.rb in the tap
+func (c *Client) UpdateTapCask(ctx context.Context, cfg *config.Config, token, cask string) error {
+	if !cfg.GitHub.Tap.Enabled {
+		return nil
+	}
+
+	tapRepo := TapRepo(cfg)
+	tapOwner, tapRepoName, ok := strings.Cut(tapRepo, "/")
+	if !ok {
+		return fmt.Errorf("invalid tap repo format: %s", tapRepo)
+	}
+	if !cfg.GitHub.Tap.AutoCommit {
+		fmt.Printf("✅ Would update tap %s with cask (auto_commit disabled)\n", tapRepo)
+		return nil
+	}
+	commitMessage := fmt.Sprintf("Update %s to v%s", token, cfg.Version)
+	return c.updateFile(ctx, tapOwner, tapRepoName, fmt.Sprintf("Casks/%s.rb", token), cask, commitMessage)
+}
+
 func (c *Client) UpdateBucket(ctx context.Context, cfg *config.Config, manifest string) error {
 	if !cfg.GitHub.Bucket.Enabled {
 		return nil
 	}
 
-	bucketRepo := cfg.GitHub.Bucket.Repo
-	if bucketRepo == "" {
-		bucketRepo = fmt.Sprintf("%s/scoop-bucket", cfg.GitHub.Owner)
-	}
+	bucketRepo := BucketRepo(cfg)
 
 	parts := strings.Split(bucketRepo, "/")
 	if len(parts) != 2 {
@@ -135,7 +343,13 @@ func (c *Client) UpdateBucket(ctx context.Context, cfg *config.Config, manifest
 	commitMessage := fmt.Sprintf("Update %s to v%s", cfg.Name, cfg.Version)
 	
 	if cfg.GitHub.Bucket.AutoCommit {
-		return c.updateFile(ctx, bucketOwner, bucketRepoName, manifestPath, manifest, commitMessage)
+		if err := c.updateFile(ctx, bucketOwner, bucketRepoName, manifestPath, manifest, commitMessage); err != nil {
+			return err
+		}
+		if cfg.GitHub.Bucket.Archive {
+			return c.updateFile(ctx, bucketOwner, bucketRepoName, ArchivedManifestPath(cfg), manifest, commitMessage)
+		}
+		return nil
 	}
 
 	fmt.Printf("✅ Would update bucket %s with manifest (auto_commit disabled)\n", bucketRepo)
@@ -156,7 +370,8 @@ func (c *Client) ensureRepository(ctx context.Context, owner, repo, description
 		Private:     github.Bool(false),
 	}
 
-	_, _, err = c.gh.Repositories.Create(ctx, "", repository)
+	_, resp, err := c.gh.Repositories.Create(ctx, "", repository)
+	record(ctx, audit.RepoCreate, owner+"/"+repo, "", resp, err)
 	if err != nil {
 		return fmt.Errorf("failed to create repository %s/%s: %w", owner, repo, err)
 	}
@@ -165,7 +380,182 @@ func (c *Client) ensureRepository(ctx context.Context, owner, repo, description
 	return nil
 }
 
+// SetStagingBranch stages tap and bucket updates on branch instead of
+// committing them to the default branch
+func (c *Client) SetStagingBranch(branch string) {
+	c.stagingBranch = branch
+}
+
+// FinalizeRelease publishes the draft release for cfg.Version and merges
+// any tap and bucket updates staged on branch
+func (c *Client) FinalizeRelease(ctx context.Context, cfg *config.Config, branch string) (*github.RepositoryRelease, error) {
+	tag := cfg.Tag()
+
+	release, err := c.findRelease(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if release.GetDraft() {
+		var resp *github.Response
+		release, resp, err = c.gh.Repositories.EditRelease(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo, release.GetID(), &github.RepositoryRelease{
+			Draft: github.Bool(false),
+		})
+		record(ctx, audit.ReleasePublish, releaseTarget(cfg), release.GetHTMLURL(), resp, err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to publish release %s: %w", tag, err)
+		}
+	}
+
+	if cfg.GitHub.Tap.Enabled {
+		if err := c.mergeStagingBranch(ctx, TapRepo(cfg), branch); err != nil {
+			return release, fmt.Errorf("failed to merge staged tap update: %w", err)
+		}
+	}
+	if cfg.GitHub.Bucket.Enabled {
+		if err := c.mergeStagingBranch(ctx, BucketRepo(cfg), branch); err != nil {
+			return release, fmt.Errorf("failed to merge staged bucket update: %w", err)
+		}
+	}
+
+	return release, nil
+}
+
+// findRelease looks up a release by tag. Draft releases are not returned
+// by the tag endpoint, so the release list is searched instead.
+func (c *Client) findRelease(ctx context.Context, owner, repo, tag string) (*github.RepositoryRelease, error) {
+	releases, err := c.ListReleases(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	for _, release := range releases {
+		if release.GetTagName() == tag {
+			return release, nil
+		}
+	}
+	return nil, fmt.Errorf("no release found for tag %s", tag)
+}
+
+// LatestVersion returns the highest version among published releases, or
+// "" when the repository has none. Tags that are not versions are ignored.
+func (c *Client) LatestVersion(ctx context.Context, owner, repo string) (string, error) {
+	releases, err := c.ListReleases(ctx, owner, repo)
+	if err != nil {
+		return "", err
+	}
+
+	latest := ""
+	for _, release := range releases {
+		if release.GetDraft() {
+			continue
+		}
+		tag := release.GetTagName()
+		if _, err := semver.Parse(tag); err != nil {
+			continue
+		}
+		if latest == "" || semver.Less(latest, tag) {
+			latest = tag
+		}
+	}
+	return latest, nil
+}
+
+// Authenticated returns the login of the token's user and, for classic
+// tokens, the OAuth scopes it was granted. Fine-grained tokens report no
+// scopes.
+func (c *Client) Authenticated(ctx context.Context) (string, []string, error) {
+	user, resp, err := c.gh.Users.Get(ctx, "")
+	if err != nil {
+		return "", nil, err
+	}
+
+	var scopes []string
+	for _, scope := range strings.Split(resp.Header.Get("X-OAuth-Scopes"), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return user.GetLogin(), scopes, nil
+}
+
+// RepoAccess reports whether owner/name exists and whether the token can
+// push to it. A missing repository is not an error.
+func (c *Client) RepoAccess(ctx context.Context, fullRepo string) (exists, push bool, err error) {
+	parts := strings.Split(fullRepo, "/")
+	if len(parts) != 2 {
+		return false, false, fmt.Errorf("invalid repo format: %s", fullRepo)
+	}
+
+	repository, resp, err := c.gh.Repositories.Get(ctx, parts[0], parts[1])
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	return true, repository.GetPermissions()["push"], nil
+}
+
+func (c *Client) mergeStagingBranch(ctx context.Context, fullRepo, branch string) error {
+	parts := strings.Split(fullRepo, "/")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid repo format: %s", fullRepo)
+	}
+	owner, repo := parts[0], parts[1]
+
+	repository, _, err := c.gh.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	commit, resp, err := c.gh.Repositories.Merge(ctx, owner, repo, &github.RepositoryMergeRequest{
+		Base:          github.String(repository.GetDefaultBranch()),
+		Head:          github.String(branch),
+		CommitMessage: github.String(fmt.Sprintf("Merge %s", branch)),
+	})
+	record(ctx, audit.BranchMerge, fullRepo+"@"+branch, commit.GetSHA(), resp, err)
+	if err != nil {
+		return err
+	}
+
+	resp, err = c.gh.Git.DeleteRef(ctx, owner, repo, "heads/"+branch)
+	record(ctx, audit.BranchDelete, fullRepo+"@"+branch, "", resp, err)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to delete branch %s in %s: %v\n", branch, fullRepo, err)
+	}
+
+	fmt.Printf("✅ Merged %s into %s:%s\n", branch, fullRepo, repository.GetDefaultBranch())
+	return nil
+}
+
+// TapRepo returns the owner/name of the Homebrew tap repository
+func TapRepo(cfg *config.Config) string {
+	if cfg.GitHub.Tap.Repo != "" {
+		return cfg.GitHub.Tap.Repo
+	}
+	return fmt.Sprintf("%s/homebrew-tap", cfg.GitHub.Owner)
+}
+
+// BucketRepo returns the owner/name of the Scoop bucket repository
+func BucketRepo(cfg *config.Config) string {
+	if cfg.GitHub.Bucket.Repo != "" {
+		return cfg.GitHub.Bucket.Repo
+	}
+	return fmt.Sprintf("%s/scoop-bucket", cfg.GitHub.Owner)
+}
+
 func (c *Client) updateFile(ctx context.Context, owner, repo, path, content, commitMessage string) error {
+	if c.stagingBranch != "" {
+		if err := c.createBranch(ctx, owner, repo, c.stagingBranch); err != nil {
+			return fmt.Errorf("failed to create staging branch: %w", err)
+		}
+		if err := c.updateFileOnBranch(ctx, owner, repo, c.stagingBranch, path, content, commitMessage); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Staged %s/%s:%s on %s\n", owner, repo, path, c.stagingBranch)
+		return nil
+	}
+
 	// Get current file (if exists)
 	var currentSHA *string
 	fileContent, _, _, err := c.gh.Repositories.GetContents(ctx, owner, repo, path, nil)
@@ -180,7 +570,8 @@ func (c *Client) updateFile(ctx context.Context, owner, repo, path, content, com
 		SHA:     currentSHA,
 	}
 
-	_, _, err = c.gh.Repositories.CreateFile(ctx, owner, repo, path, opts)
+	result, resp, err := c.gh.Repositories.CreateFile(ctx, owner, repo, path, opts)
+	record(ctx, audit.FileCommit, fmt.Sprintf("%s/%s:%s", owner, repo, path), commitSHA(result), resp, err)
 	if err != nil {
 		return fmt.Errorf("failed to update file %s: %w", path, err)
 	}
@@ -261,7 +652,8 @@ func (c *Client) SubmitWingetPR(ctx context.Context, cfg *config.Config, manifes
 		Body:  github.String(prBody),
 	}
 
-	createdPR, _, err := c.gh.PullRequests.Create(ctx, upstreamOwner, upstreamRepo, pr)
+	createdPR, resp, err := c.gh.PullRequests.Create(ctx, upstreamOwner, upstreamRepo, pr)
+	record(ctx, audit.PROpen, upstreamOwner+"/"+upstreamRepo, createdPR.GetHTMLURL(), resp, err)
 	if err != nil {
 		return fmt.Errorf("failed to create pull request: %w", err)
 	}
@@ -279,7 +671,8 @@ func (c *Client) ensureFork(ctx context.Context, upstreamOwner, upstreamRepo, fo
 
 	// Create fork
 	opts := &github.RepositoryCreateForkOptions{}
-	_, _, err = c.gh.Repositories.CreateFork(ctx, upstreamOwner, upstreamRepo, opts)
+	_, resp, err := c.gh.Repositories.CreateFork(ctx, upstreamOwner, upstreamRepo, opts)
+	record(ctx, audit.RepoFork, upstreamOwner+"/"+upstreamRepo, forkOwner+"/"+upstreamRepo, resp, err)
 	if err != nil {
 		return fmt.Errorf("failed to create fork: %w", err)
 	}
@@ -309,13 +702,16 @@ func (c *Client) createBranch(ctx context.Context, owner, repo, branchName strin
 		},
 	}
 
-	_, _, err = c.gh.Git.CreateRef(ctx, owner, repo, newRef)
+	_, resp, err := c.gh.Git.CreateRef(ctx, owner, repo, newRef)
 	if err != nil {
 		// Branch might already exist, which is fine
 		if !strings.Contains(err.Error(), "already exists") {
+			record(ctx, audit.BranchCreate, fmt.Sprintf("%s/%s@%s", owner, repo, branchName), "", resp, err)
 			return err
 		}
+		return nil
 	}
+	record(ctx, audit.BranchCreate, fmt.Sprintf("%s/%s@%s", owner, repo, branchName), ref.Object.GetSHA(), resp, nil)
 
 	return nil
 }
@@ -338,10 +734,32 @@ func (c *Client) updateFileOnBranch(ctx context.Context, owner, repo, branch, pa
 		Branch:  github.String(branch),
 	}
 
-	_, _, err = c.gh.Repositories.CreateFile(ctx, owner, repo, path, opts)
+	result, resp, err := c.gh.Repositories.CreateFile(ctx, owner, repo, path, opts)
+	record(ctx, audit.FileCommit, fmt.Sprintf("%s/%s@%s:%s", owner, repo, branch, path), commitSHA(result), resp, err)
 	if err != nil {
 		return fmt.Errorf("failed to update file %s: %w", path, err)
 	}
 
 	return nil
 }
+
+// record adds a change made through the API to the audit log
+func record(ctx context.Context, action, target, detail string, resp *github.Response, err error) {
+	var requestID string
+	if resp != nil && resp.Response != nil {
+		requestID = audit.RequestID(resp.Header)
+	}
+	audit.Result(ctx, action, target, detail, requestID, err)
+}
+
+// releaseTarget names cfg's release in the audit log, e.g. owner/repo@v1.2.0
+func releaseTarget(cfg *config.Config) string {
+	return fmt.Sprintf("%s/%s@%s", cfg.GitHub.Owner, cfg.GitHub.Repo, cfg.Tag())
+}
+
+func commitSHA(result *github.RepositoryContentResponse) string {
+	if result == nil {
+		return ""
+	}
+	return result.Commit.GetSHA()
+}
@@ -2,12 +2,21 @@ package github
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-github/v57/github"
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/upload"
 )
 
 func TestNewClient(t *testing.T) {
@@ -315,3 +324,255 @@ func TestReleaseConfiguration(t *testing.T) {
 		t.Error("Release should generate notes")
 	}
 }
+
+func TestFinalizeRelease(t *testing.T) {
+	var published, merged bool
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/testowner/testrepo/releases", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": 1, "tag_name": "v0.9.0"}, {"id": 2, "tag_name": "v1.0.0", "draft": true}]`)
+	})
+	mux.HandleFunc("/repos/testowner/testrepo/releases/2", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			published = true
+		}
+		fmt.Fprint(w, `{"id": 2, "tag_name": "v1.0.0", "draft": false}`)
+	})
+	mux.HandleFunc("/repos/testowner/homebrew-tap", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"default_branch": "main"}`)
+	})
+	mux.HandleFunc("/repos/testowner/homebrew-tap/merges", func(w http.ResponseWriter, r *http.Request) {
+		merged = true
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{}`)
+	})
+	mux.HandleFunc("/repos/testowner/homebrew-tap/git/refs/heads/bagboy/release-v1.0.0", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	cfg := &config.Config{
+		Name:    "testapp",
+		Version: "1.0.0",
+		GitHub: config.GitHubConfig{
+			Owner: "testowner",
+			Repo:  "testrepo",
+			Tap:   config.TapConfig{Enabled: true},
+		},
+	}
+
+	release, err := client.FinalizeRelease(context.Background(), cfg, "bagboy/release-v1.0.0")
+	if err != nil {
+		t.Fatalf("FinalizeRelease failed: %v", err)
+	}
+	if release.GetDraft() {
+		t.Error("Expected published release")
+	}
+	if !published {
+		t.Error("Expected draft release to be published")
+	}
+	if !merged {
+		t.Error("Expected staged tap branch to be merged")
+	}
+
+	cfg.Version = "2.0.0"
+	if _, err := client.FinalizeRelease(context.Background(), cfg, "bagboy/release-v2.0.0"); err == nil {
+		t.Error("Expected error for missing release")
+	}
+}
+
+func TestUploadAsset_RetriesAfterPartialUpload(t *testing.T) {
+	oldBackoff := upload.Backoff
+	upload.Backoff = time.Millisecond
+	defer func() { upload.Backoff = oldBackoff }()
+
+	var uploads, deleted int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/testowner/testrepo/releases/7/assets", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `[{"id": 70, "name": "other.txt"}, {"id": 71, "name": "test-asset.txt", "state": "starter"}]`)
+			return
+		}
+		uploads++
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Query().Get("name") != "test-asset.txt" || string(body) != "test content" {
+			t.Errorf("Unexpected upload of %q: %q", r.URL.Query().Get("name"), body)
+		}
+		if uploads == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": 72, "name": "test-asset.txt"}`)
+	})
+	mux.HandleFunc("/repos/testowner/testrepo/releases/assets/71", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deleted++
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "test-asset.txt")
+	os.WriteFile(testFile, []byte("test content"), 0644)
+
+	client := newTestClient(t, server.URL)
+	cfg := &config.Config{GitHub: config.GitHubConfig{Owner: "testowner", Repo: "testrepo"}}
+	if err := client.uploadAsset(context.Background(), cfg, 7, testFile); err != nil {
+		t.Fatalf("uploadAsset failed: %v", err)
+	}
+	if uploads != 2 || deleted != 1 {
+		t.Errorf("Expected 2 uploads with the partial asset deleted in between, got %d uploads and %d deletes", uploads, deleted)
+	}
+}
+
+func TestCreateRelease_Immutable(t *testing.T) {
+	var created, createdDraft, published, deleted bool
+	releases := `[]`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/testowner/testrepo/releases", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var release github.RepositoryRelease
+			json.NewDecoder(r.Body).Decode(&release)
+			created, createdDraft = true, release.GetDraft()
+			fmt.Fprint(w, `{"id": 3, "tag_name": "v1.0.0", "draft": true}`)
+			return
+		}
+		fmt.Fprint(w, releases)
+	})
+	mux.HandleFunc("/repos/testowner/testrepo/releases/3", func(w http.ResponseWriter, r *http.Request) {
+		published = r.Method == http.MethodPatch
+		fmt.Fprint(w, `{"id": 3, "tag_name": "v1.0.0", "draft": false}`)
+	})
+	mux.HandleFunc("/repos/testowner/testrepo/releases/3/assets", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `[{"id": 31, "name": "test-asset.txt"}]`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": 32, "name": "test-asset.txt"}`)
+	})
+	mux.HandleFunc("/repos/testowner/testrepo/releases/assets/31", func(w http.ResponseWriter, r *http.Request) {
+		deleted = r.Method == http.MethodDelete
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	testFile := filepath.Join(t.TempDir(), "test-asset.txt")
+	os.WriteFile(testFile, []byte("test content"), 0644)
+
+	client := newTestClient(t, server.URL)
+	cfg := &config.Config{
+		Name:    "testapp",
+		Version: "1.0.0",
+		GitHub: config.GitHubConfig{
+			Owner:   "testowner",
+			Repo:    "testrepo",
+			Release: config.ReleaseConfig{Enabled: true, Immutable: true},
+		},
+	}
+
+	// A new release is created as a draft and published once its assets
+	// are uploaded
+	release, err := client.CreateRelease(context.Background(), cfg, []string{testFile})
+	if err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+	if !created || !createdDraft || !published || release.GetDraft() {
+		t.Errorf("Expected a draft published after upload, got created=%v draft=%v published=%v", created, createdDraft, published)
+	}
+
+	// A draft left by an earlier attempt is reused, replacing its assets
+	created, published = false, false
+	releases = `[{"id": 3, "tag_name": "v1.0.0", "draft": true, "assets": [{"id": 31, "name": "test-asset.txt"}]}]`
+	if _, err := client.CreateRelease(context.Background(), cfg, []string{testFile}); err != nil {
+		t.Fatalf("CreateRelease failed: %v", err)
+	}
+	if created || !deleted || !published {
+		t.Errorf("Expected the draft reused with its asset replaced, got created=%v deleted=%v published=%v", created, deleted, published)
+	}
+
+	// A published release is never touched
+	created, published = false, false
+	releases = `[{"id": 3, "tag_name": "v1.0.0", "draft": false}]`
+	_, err = client.CreateRelease(context.Background(), cfg, []string{testFile})
+	if err == nil || !strings.Contains(err.Error(), "publish version 1.0.1 instead") {
+		t.Errorf("Expected an immutable release error suggesting 1.0.1, got %v", err)
+	}
+	if created || published {
+		t.Error("Expected the published release to be left alone")
+	}
+}
+
+func newTestClient(t *testing.T, serverURL string) *Client {
+	t.Helper()
+
+	baseURL, err := url.Parse(serverURL + "/")
+	if err != nil {
+		t.Fatalf("Failed to parse server URL: %v", err)
+	}
+
+	gh := github.NewClient(nil)
+	gh.BaseURL = baseURL
+	gh.UploadURL = baseURL
+
+	return &Client{gh: gh, cfg: &config.GitHubConfig{Owner: "testowner", Repo: "testrepo"}}
+}
+
+func TestLatestVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"id":1,"tag_name":"v1.2.0"},
+			{"id":2,"tag_name":"v1.10.0"},
+			{"id":3,"tag_name":"v2.0.0","draft":true},
+			{"id":4,"tag_name":"nightly","prerelease":true},
+			{"id":5,"tag_name":"v1.11.0-rc.1","prerelease":true}
+		]`)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	latest, err := client.LatestVersion(context.Background(), "testowner", "testrepo")
+	if err != nil {
+		t.Fatalf("LatestVersion failed: %v", err)
+	}
+	if latest != "v1.11.0-rc.1" {
+		t.Errorf("Expected v1.11.0-rc.1, got %s", latest)
+	}
+}
+
+func TestEnsureTopic(t *testing.T) {
+	var replaced string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			body, _ := io.ReadAll(r.Body)
+			replaced = string(body)
+		}
+		fmt.Fprint(w, `{"names":["cli","golang"]}`)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	if err := client.EnsureTopic(context.Background(), "testowner", "gh-test", "gh-extension"); err != nil {
+		t.Fatalf("EnsureTopic failed: %v", err)
+	}
+	if !strings.Contains(replaced, `"names":["cli","golang","gh-extension"]`) {
+		t.Errorf("Expected gh-extension appended to topics, got %s", replaced)
+	}
+
+	replaced = ""
+	if err := client.EnsureTopic(context.Background(), "testowner", "gh-test", "golang"); err != nil {
+		t.Fatalf("EnsureTopic failed: %v", err)
+	}
+	if replaced != "" {
+		t.Errorf("Expected no update for an existing topic, got %s", replaced)
+	}
+}
@@ -0,0 +1,49 @@
+package github
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/semver"
+)
+
+var formulaClassPattern = regexp.MustCompile(`(?m)^class (\w+) < Formula$`)
+
+// VersionedFormulaPath returns the tap path of the versioned formula kept
+// for the release's minor version, such as Formula/myapp@1.2.rb
+func VersionedFormulaPath(cfg *config.Config) (string, error) {
+	v, err := semver.Parse(cfg.Version)
+	if err != nil {
+		return "", fmt.Errorf("versioned formula: %w", err)
+	}
+	return fmt.Sprintf("Formula/%s@%d.%d.rb", cfg.Name, v.Major, v.Minor), nil
+}
+
+// VersionedFormula turns formula into the versioned formula for its minor
+// version. Homebrew names the class MyappAT12 for myapp@1.2, and versioned
+// formulae are keg-only so they install alongside the current one.
+func VersionedFormula(cfg *config.Config, formula string) (string, error) {
+	v, err := semver.Parse(cfg.Version)
+	if err != nil {
+		return "", fmt.Errorf("versioned formula: %w", err)
+	}
+	m := formulaClassPattern.FindStringSubmatchIndex(formula)
+	if m == nil {
+		return "", fmt.Errorf("versioned formula: no formula class found")
+	}
+
+	class := fmt.Sprintf("class %sAT%d%d < Formula", formula[m[2]:m[3]], v.Major, v.Minor)
+	if !strings.Contains(formula, "\n  keg_only ") {
+		class += "\n  keg_only :versioned_formula"
+	}
+	return formula[:m[0]] + class + formula[m[1]:], nil
+}
+
+// ArchivedManifestPath returns the bucket path the scoop manifest of the
+// release is archived under. It is outside bucket/ so scoop only lists
+// the current version; older ones install from their raw URL.
+func ArchivedManifestPath(cfg *config.Config) string {
+	return fmt.Sprintf("archive/%s/%s.json", cfg.Name, cfg.Version)
+}
@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestVersionedFormula(t *testing.T) {
+	cfg := &config.Config{Name: "myapp", Version: "1.2.3"}
+	formula := "class Myapp < Formula\n  desc \"My app\"\n  version \"1.2.3\"\nend\n"
+
+	versioned, err := VersionedFormula(cfg, formula)
+	if err != nil {
+		t.Fatalf("VersionedFormula failed: %v", err)
+	}
+	expected := "class MyappAT12 < Formula\n  keg_only :versioned_formula\n  desc \"My app\"\n  version \"1.2.3\"\nend\n"
+	if versioned != expected {
+		t.Errorf("VersionedFormula() = %q, want %q", versioned, expected)
+	}
+
+	// An explicit keg_only is kept rather than repeated
+	kegOnly := "class Myapp < Formula\n  keg_only :provided_by_macos\nend\n"
+	if versioned, _ := VersionedFormula(cfg, kegOnly); strings.Count(versioned, "keg_only") != 1 {
+		t.Errorf("Expected a single keg_only, got %q", versioned)
+	}
+
+	if path, _ := VersionedFormulaPath(cfg); path != "Formula/myapp@1.2.rb" {
+		t.Errorf("VersionedFormulaPath() = %s", path)
+	}
+	if _, err := VersionedFormula(cfg, "not a formula"); err == nil {
+		t.Error("Expected an error without a formula class")
+	}
+}
+
+func TestUpdateTapAndBucketKeepHistory(t *testing.T) {
+	written := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			http.NotFound(w, r)
+			return
+		}
+		var body struct {
+			Content []byte `json:"content"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		written[r.URL.Path] = string(body.Content)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	cfg := &config.Config{
+		Name:    "myapp",
+		Version: "1.2.3",
+		GitHub: config.GitHubConfig{
+			Owner:  "testowner",
+			Tap:    config.TapConfig{Enabled: true, AutoCommit: true, Versioned: true},
+			Bucket: config.BucketConfig{Enabled: true, AutoCommit: true, Archive: true},
+		},
+	}
+
+	if err := client.UpdateTap(context.Background(), cfg, "class Myapp < Formula\nend\n"); err != nil {
+		t.Fatalf("UpdateTap failed: %v", err)
+	}
+	if err := client.UpdateBucket(context.Background(), cfg, `{"version": "1.2.3"}`); err != nil {
+		t.Fatalf("UpdateBucket failed: %v", err)
+	}
+
+	var paths []string
+	for path := range written {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	expected := []string{
+		"/repos/testowner/homebrew-tap/contents/Formula/myapp.rb",
+		"/repos/testowner/homebrew-tap/contents/Formula/myapp@1.2.rb",
+		"/repos/testowner/scoop-bucket/contents/archive/myapp/1.2.3.json",
+		"/repos/testowner/scoop-bucket/contents/bucket/myapp.json",
+	}
+	if strings.Join(paths, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Wrote %v, want %v", paths, expected)
+	}
+	if !strings.HasPrefix(written[expected[1]], "class MyappAT12 < Formula") {
+		t.Errorf("Unexpected versioned formula %q", written[expected[1]])
+	}
+}
@@ -0,0 +1,196 @@
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/snippets"
+)
+
+// ReleaseNotesTemplate is the templates key that replaces the release body
+const ReleaseNotesTemplate = "github/release-notes"
+
+// Artifact is a release asset as listed in the release notes
+type Artifact struct {
+	Name     string
+	Format   string
+	Platform string
+	Size     int64
+	SHA256   string
+	URL      string
+}
+
+// HumanSize formats Size as B, KiB, MiB or GiB
+func (a Artifact) HumanSize() string {
+	size := float64(a.Size)
+	for _, unit := range []string{"B", "KiB", "MiB"} {
+		if size < 1024 {
+			if unit == "B" {
+				return fmt.Sprintf("%d B", a.Size)
+			}
+			return fmt.Sprintf("%.1f %s", size, unit)
+		}
+		size /= 1024
+	}
+	return fmt.Sprintf("%.1f GiB", size)
+}
+
+// ReleaseNotesData is passed to the release notes template
+type ReleaseNotesData struct {
+	*config.Config
+	Tag       string
+	Artifacts []Artifact
+	Install   []snippets.Snippet
+}
+
+const defaultReleaseNotes = `{{if .Install}}## Install
+{{range .Install}}
+**{{.Manager}}**
+` + "```" + `
+{{.Command}}
+` + "```" + `
+{{end}}
+{{end}}## Artifacts
+
+| File | Format | Platform | Size | SHA-256 |
+|------|--------|----------|------|---------|
+{{- range .Artifacts}}
+| [{{.Name}}]({{.URL}}) | {{.Format}} | {{.Platform}} | {{.HumanSize}} | ` + "`{{.SHA256}}`" + ` |
+{{- end}}
+`
+
+// ReleaseNotes renders the release body for assets: install snippets for
+// each package manager and a table of the assets with their checksums.
+// templates: github/release-notes replaces the built-in layout.
+func ReleaseNotes(cfg *config.Config, assets []string) (string, error) {
+	tag := cfg.Tag()
+	data := ReleaseNotesData{Config: cfg, Tag: tag}
+
+	for _, asset := range assets {
+		artifact, err := describeAsset(cfg, tag, asset)
+		if err != nil {
+			return "", err
+		}
+		data.Artifacts = append(data.Artifacts, artifact)
+	}
+	sort.SliceStable(data.Artifacts, func(i, j int) bool { return data.Artifacts[i].Name < data.Artifacts[j].Name })
+	data.Install = snippets.Generate(cfg, assets)
+
+	t, err := packager.ParseTemplate(cfg, ReleaseNotesTemplate, defaultReleaseNotes)
+	if err != nil {
+		return "", err
+	}
+	var body strings.Builder
+	if err := t.Execute(&body, data); err != nil {
+		return "", fmt.Errorf("template %s: %w", ReleaseNotesTemplate, err)
+	}
+	return body.String(), nil
+}
+
+func describeAsset(cfg *config.Config, tag, path string) (Artifact, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Artifact{}, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+
+	name := filepath.Base(path)
+	format := assetFormat(cfg, name)
+	return Artifact{
+		Name:     name,
+		Format:   format,
+		Platform: assetPlatform(cfg, name, format),
+		Size:     size,
+		SHA256:   hex.EncodeToString(h.Sum(nil)),
+		URL:      fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", cfg.GitHub.Owner, cfg.GitHub.Repo, tag, name),
+	}, nil
+}
+
+// assetFormat guesses the package format from an asset's file name
+func assetFormat(cfg *config.Config, name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case name == "install.sh" || name == "install.ps1":
+		return "installer"
+	case name == "checksums.txt" || strings.HasSuffix(lower, ".sha256"):
+		return "checksums"
+	case strings.HasSuffix(lower, ".sig"), strings.HasSuffix(lower, ".minisig"), strings.HasSuffix(lower, ".asc"),
+		strings.HasSuffix(lower, ".sigstore.bundle"), name == "allowed_signers", strings.HasSuffix(lower, ".pub"):
+		return "signature"
+	case name == cfg.Name+".rb":
+		return "brew"
+	case name == cfg.Name+".json":
+		return "scoop"
+	}
+
+	formats := []struct{ ext, format string }{
+		{".deb", "deb"}, {".rpm", "rpm"}, {".appimage", "appimage"}, {".dmg", "dmg"}, {".msi", "msi"},
+		{".msix", "msix"}, {".nupkg", "chocolatey"}, {".snap", "snap"}, {".gem", "gem"}, {".apk", "apk"},
+		{".pkg", "pkg"}, {".txz", "freebsd"}, {".sif", "apptainer"}, {".flatpak", "flatpak"},
+		{".tar.gz", "archive"}, {".tgz", "archive"}, {".tar.xz", "archive"}, {".zip", "archive"},
+	}
+	for _, f := range formats {
+		if strings.HasSuffix(lower, f.ext) {
+			return f.format
+		}
+	}
+	for platform := range cfg.Binaries {
+		if name == packager.AssetName(cfg, platform) {
+			return "binary"
+		}
+	}
+	return "file"
+}
+
+// assetPlatform finds the platform in an asset's name, from the configured
+// binaries or the architecture names packages use
+func assetPlatform(cfg *config.Config, name, format string) string {
+	platforms := make([]string, 0, len(cfg.Binaries))
+	for platform := range cfg.Binaries {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	for _, platform := range platforms {
+		if strings.Contains(name, platform) {
+			return platform
+		}
+	}
+
+	goos := map[string]string{"deb": "linux", "rpm": "linux", "appimage": "linux", "snap": "linux", "apk": "linux",
+		"dmg": "darwin", "pkg": "darwin", "msi": "windows", "msix": "windows", "freebsd": "freebsd"}[format]
+	if goos == "" {
+		return ""
+	}
+	arches := []struct {
+		names  []string
+		goarch string
+	}{
+		{[]string{"amd64", "x86_64", "x64"}, "amd64"},
+		{[]string{"arm64", "aarch64"}, "arm64"},
+		{[]string{"i386", "i686", "386", "x86"}, "386"},
+		{[]string{"armhf", "armv7"}, "arm"},
+	}
+	lower := strings.ToLower(name)
+	for _, arch := range arches {
+		for _, n := range arch.names {
+			if strings.Contains(lower, n) {
+				return goos + "-" + arch.goarch
+			}
+		}
+	}
+	return goos
+}
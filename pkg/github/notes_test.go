@@ -0,0 +1,79 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestReleaseNotes(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(content), 0644)
+		return path
+	}
+	assets := []string{
+		write("myapp_1.0.0_arm64.deb", "deb"),
+		write("myapp-linux-amd64", "hello"),
+		write("install.sh", "#!/bin/sh"),
+		write("checksums.txt", "sums"),
+	}
+
+	cfg := &config.Config{
+		Name:     "myapp",
+		Version:  "1.0.0",
+		Binaries: map[string]string{"linux-amd64": "bin/myapp"},
+		GitHub: config.GitHubConfig{
+			Owner:   "acme",
+			Repo:    "myapp",
+			Release: config.ReleaseConfig{Enabled: true},
+			Tap:     config.TapConfig{Enabled: true, Repo: "acme/homebrew-tap"},
+		},
+	}
+
+	body, err := ReleaseNotes(cfg, assets)
+	if err != nil {
+		t.Fatalf("ReleaseNotes failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"brew install acme/tap/myapp",
+		"curl -LO https://github.com/acme/myapp/releases/download/v1.0.0/myapp_1.0.0_arm64.deb\nsudo apt install ./myapp_1.0.0_arm64.deb",
+		"curl -fsSL https://github.com/acme/myapp/releases/latest/download/install.sh | sh",
+		"| [myapp-linux-amd64](https://github.com/acme/myapp/releases/download/v1.0.0/myapp-linux-amd64) | binary | linux-amd64 | 5 B | `2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824` |",
+		"| [myapp_1.0.0_arm64.deb](https://github.com/acme/myapp/releases/download/v1.0.0/myapp_1.0.0_arm64.deb) | deb | linux-arm64 | 3 B |",
+		"| [checksums.txt](https://github.com/acme/myapp/releases/download/v1.0.0/checksums.txt) | checksums |  |",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("release notes missing %q:\n%s", want, body)
+		}
+	}
+
+	override := write("notes.md.tmpl", "{{.Tag}}:{{range .Artifacts}} {{.Name}}={{.Format}}{{end}}")
+	cfg.Templates = map[string]string{ReleaseNotesTemplate: override}
+	body, err = ReleaseNotes(cfg, assets[:2])
+	if err != nil {
+		t.Fatalf("ReleaseNotes with template failed: %v", err)
+	}
+	if body != "v1.0.0: myapp-linux-amd64=binary myapp_1.0.0_arm64.deb=deb" {
+		t.Errorf("templated release notes = %q", body)
+	}
+}
+
+func TestArtifactHumanSize(t *testing.T) {
+	tests := map[int64]string{
+		512:             "512 B",
+		2048:            "2.0 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+		3 << 30:         "3.0 GiB",
+	}
+	for size, want := range tests {
+		if got := (Artifact{Size: size}).HumanSize(); got != want {
+			t.Errorf("HumanSize(%d) = %q, want %q", size, got, want)
+		}
+	}
+}
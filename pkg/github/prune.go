@@ -0,0 +1,176 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/scttfrdmn/bagboy/pkg/audit"
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/semver"
+)
+
+// DefaultNightlyMaxAgeDays applies when prune.nightly_tag is set without
+// prune.nightly_max_age_days
+const DefaultNightlyMaxAgeDays = 30
+
+// PrunePlan lists the releases and assets selected for deletion
+type PrunePlan struct {
+	Drafts      []*github.RepositoryRelease
+	Prereleases []*github.RepositoryRelease
+	Assets      []*github.ReleaseAsset
+}
+
+// Empty reports whether the plan has nothing to delete
+func (p PrunePlan) Empty() bool {
+	return len(p.Drafts) == 0 && len(p.Prereleases) == 0 && len(p.Assets) == 0
+}
+
+// DraftsOnly returns the plan without its published prereleases and
+// nightly assets, for releases that are immutable once published
+func (p PrunePlan) DraftsOnly() PrunePlan {
+	return PrunePlan{Drafts: p.Drafts}
+}
+
+// PlanPrune selects what to delete under policy. Each kind is opt-in, so
+// a zero policy deletes nothing:
+//   - drafts older than draft_max_age_days when it is set, except the
+//     draft for currentTag which may be a staged scheduled release
+//   - prereleases superseded by a stable release of the same or a newer
+//     version, when prune_prereleases is set
+//   - assets on the nightly release older than nightly_max_age_days
+func PlanPrune(releases []*github.RepositoryRelease, policy config.PruneConfig, currentTag string, now time.Time) PrunePlan {
+	var plan PrunePlan
+
+	var newestStable *semver.Version
+	for _, release := range releases {
+		if release.GetDraft() || release.GetPrerelease() {
+			continue
+		}
+		v, err := semver.Parse(release.GetTagName())
+		if err != nil || v.IsPrerelease() {
+			continue
+		}
+		if newestStable == nil || semver.Compare(v, *newestStable) > 0 {
+			newestStable = &v
+		}
+	}
+
+	draftCutoff := now.AddDate(0, 0, -policy.DraftMaxAgeDays)
+
+	nightlyAge := policy.NightlyMaxAgeDays
+	if nightlyAge == 0 {
+		nightlyAge = DefaultNightlyMaxAgeDays
+	}
+	nightlyCutoff := now.AddDate(0, 0, -nightlyAge)
+
+	for _, release := range releases {
+		switch {
+		case release.GetDraft():
+			if policy.DraftMaxAgeDays <= 0 || release.GetTagName() == currentTag {
+				continue
+			}
+			if release.GetCreatedAt().Time.After(draftCutoff) {
+				continue
+			}
+			plan.Drafts = append(plan.Drafts, release)
+
+		case policy.NightlyTag != "" && release.GetTagName() == policy.NightlyTag:
+			for _, asset := range release.Assets {
+				if asset.GetCreatedAt().Time.Before(nightlyCutoff) {
+					plan.Assets = append(plan.Assets, asset)
+				}
+			}
+
+		case policy.PrunePrereleases && newestStable != nil:
+			v, err := semver.Parse(release.GetTagName())
+			if err != nil || !v.IsPrerelease() {
+				continue
+			}
+			stable := semver.Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+			if semver.Compare(stable, *newestStable) <= 0 {
+				plan.Prereleases = append(plan.Prereleases, release)
+			}
+		}
+	}
+
+	return plan
+}
+
+// ListReleases returns all releases of a repository, including drafts
+func (c *Client) ListReleases(ctx context.Context, owner, repo string) ([]*github.RepositoryRelease, error) {
+	var all []*github.RepositoryRelease
+
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		releases, resp, err := c.gh.Repositories.ListReleases(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list releases: %w", err)
+		}
+		all = append(all, releases...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return all, nil
+}
+
+// Prune deletes everything in plan. With deleteTags the git tags of pruned
+// prereleases are removed as well. Failures are reported and pruning
+// continues; the number of failed deletions is returned in the error.
+func (c *Client) Prune(ctx context.Context, owner, repo string, plan PrunePlan, deleteTags bool) error {
+	failures := 0
+
+	for _, release := range append(append([]*github.RepositoryRelease{}, plan.Drafts...), plan.Prereleases...) {
+		resp, err := c.gh.Repositories.DeleteRelease(ctx, owner, repo, release.GetID())
+		record(ctx, audit.ReleaseDelete, fmt.Sprintf("%s/%s@%s", owner, repo, release.GetTagName()), releaseLabel(release), resp, err)
+		if err != nil {
+			fmt.Printf("❌ Failed to delete release %s: %v\n", releaseLabel(release), err)
+			failures++
+			continue
+		}
+		fmt.Printf("🗑️  Deleted release %s\n", releaseLabel(release))
+	}
+
+	if deleteTags {
+		for _, release := range plan.Prereleases {
+			resp, err := c.gh.Git.DeleteRef(ctx, owner, repo, "tags/"+release.GetTagName())
+			record(ctx, audit.TagDelete, fmt.Sprintf("%s/%s@%s", owner, repo, release.GetTagName()), "", resp, err)
+			if err != nil {
+				fmt.Printf("❌ Failed to delete tag %s: %v\n", release.GetTagName(), err)
+				failures++
+				continue
+			}
+			fmt.Printf("🗑️  Deleted tag %s\n", release.GetTagName())
+		}
+	}
+
+	for _, asset := range plan.Assets {
+		resp, err := c.gh.Repositories.DeleteReleaseAsset(ctx, owner, repo, asset.GetID())
+		record(ctx, audit.AssetDelete, owner+"/"+repo, asset.GetName(), resp, err)
+		if err != nil {
+			fmt.Printf("❌ Failed to delete asset %s: %v\n", asset.GetName(), err)
+			failures++
+			continue
+		}
+		fmt.Printf("🗑️  Deleted asset %s\n", asset.GetName())
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d deletions failed", failures)
+	}
+	return nil
+}
+
+func releaseLabel(release *github.RepositoryRelease) string {
+	if release.GetTagName() != "" {
+		return release.GetTagName()
+	}
+	if release.GetName() != "" {
+		return release.GetName()
+	}
+	return fmt.Sprintf("#%d", release.GetID())
+}
@@ -0,0 +1,134 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func testRelease(id int64, tag string, draft, prerelease bool, created time.Time) *github.RepositoryRelease {
+	return &github.RepositoryRelease{
+		ID:         github.Int64(id),
+		TagName:    github.String(tag),
+		Draft:      github.Bool(draft),
+		Prerelease: github.Bool(prerelease),
+		CreatedAt:  &github.Timestamp{Time: created},
+	}
+}
+
+func TestPlanPrune(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+
+	nightly := testRelease(10, "nightly", false, true, daysAgo(100))
+	nightly.Assets = []*github.ReleaseAsset{
+		{ID: github.Int64(101), Name: github.String("old.tar.gz"), CreatedAt: &github.Timestamp{Time: daysAgo(45)}},
+		{ID: github.Int64(102), Name: github.String("new.tar.gz"), CreatedAt: &github.Timestamp{Time: daysAgo(2)}},
+	}
+
+	releases := []*github.RepositoryRelease{
+		testRelease(1, "v1.0.0", false, false, daysAgo(90)),
+		testRelease(2, "v1.1.0-rc.1", false, true, daysAgo(60)),
+		testRelease(3, "v1.1.0", false, false, daysAgo(50)),
+		testRelease(4, "v1.2.0-beta.1", false, true, daysAgo(5)),
+		testRelease(5, "", true, false, daysAgo(30)),
+		testRelease(6, "v1.2.0", true, false, daysAgo(30)),
+		testRelease(7, "v1.0.5", true, false, daysAgo(1)),
+		nightly,
+	}
+
+	policy := config.PruneConfig{DraftMaxAgeDays: 7, NightlyTag: "nightly", PrunePrereleases: true}
+	plan := PlanPrune(releases, policy, "v1.2.0", now)
+
+	if len(plan.Drafts) != 1 || plan.Drafts[0].GetID() != 5 {
+		t.Errorf("Expected only the stale untagged draft, got %v", ids(plan.Drafts))
+	}
+	if len(plan.Prereleases) != 1 || plan.Prereleases[0].GetID() != 2 {
+		t.Errorf("Expected only the superseded rc, got %v", ids(plan.Prereleases))
+	}
+	if len(plan.Assets) != 1 || plan.Assets[0].GetID() != 101 {
+		t.Errorf("Expected only the old nightly asset, got %d assets", len(plan.Assets))
+	}
+
+	// Immutable releases keep everything published
+	drafts := plan.DraftsOnly()
+	if len(drafts.Drafts) != 1 || len(drafts.Prereleases) != 0 || len(drafts.Assets) != 0 {
+		t.Errorf("Expected only drafts, got %v, %v and %d assets", ids(drafts.Drafts), ids(drafts.Prereleases), len(drafts.Assets))
+	}
+
+	policy.PrunePrereleases = false
+	if plan := PlanPrune(releases, policy, "v1.2.0", now); len(plan.Prereleases) != 0 {
+		t.Errorf("Expected prereleases kept, got %v", ids(plan.Prereleases))
+	}
+
+	if plan := PlanPrune(nil, policy, "v1.2.0", now); !plan.Empty() {
+		t.Error("Expected empty plan for no releases")
+	}
+}
+
+func TestPlanPrune_ZeroPolicy(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	releases := []*github.RepositoryRelease{
+		testRelease(1, "v1.0.0-rc.1", false, true, now.AddDate(-1, 0, 0)),
+		testRelease(2, "v1.0.0", false, false, now.AddDate(-1, 0, 0)),
+		testRelease(3, "v0.9.0", true, false, now.AddDate(-1, 0, 0)),
+		testRelease(4, "", true, false, now.AddDate(-1, 0, 0)),
+	}
+
+	if plan := PlanPrune(releases, config.PruneConfig{}, "v1.1.0", now); !plan.Empty() {
+		t.Errorf("Expected a zero policy to delete nothing, got drafts %v and prereleases %v", ids(plan.Drafts), ids(plan.Prereleases))
+	}
+}
+
+func TestPrune(t *testing.T) {
+	deleted := map[string]bool{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.NotFound(w, r)
+			return
+		}
+		deleted[r.URL.Path] = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	plan := PrunePlan{
+		Drafts:      []*github.RepositoryRelease{testRelease(5, "", true, false, time.Now())},
+		Prereleases: []*github.RepositoryRelease{testRelease(2, "v1.1.0-rc.1", false, true, time.Now())},
+		Assets:      []*github.ReleaseAsset{{ID: github.Int64(101), Name: github.String("old.tar.gz")}},
+	}
+
+	if err := client.Prune(context.Background(), "testowner", "testrepo", plan, true); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+
+	for _, path := range []string{
+		"/repos/testowner/testrepo/releases/5",
+		"/repos/testowner/testrepo/releases/2",
+		"/repos/testowner/testrepo/git/refs/tags/v1.1.0-rc.1",
+		"/repos/testowner/testrepo/releases/assets/101",
+	} {
+		if !deleted[path] {
+			t.Errorf("Expected DELETE %s", path)
+		}
+	}
+}
+
+func ids(releases []*github.RepositoryRelease) []string {
+	var out []string
+	for _, release := range releases {
+		out = append(out, fmt.Sprintf("%d:%s", release.GetID(), release.GetTagName()))
+	}
+	return out
+}
@@ -0,0 +1,192 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/scttfrdmn/bagboy/pkg/audit"
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// HoldManifests reports whether tap and bucket updates for cfg's release
+// wait in draft pull requests until it is published
+func HoldManifests(cfg *config.Config) bool {
+	return cfg.GitHub.ManifestPRs.Enabled && (cfg.GitHub.Release.Draft || cfg.GitHub.Release.Prerelease)
+}
+
+// manifestRepos returns the tap and bucket repositories that publish
+// commits to
+func manifestRepos(cfg *config.Config) []string {
+	var repos []string
+	if cfg.GitHub.Tap.Enabled && cfg.GitHub.Tap.AutoCommit {
+		repos = append(repos, TapRepo(cfg))
+	}
+	if cfg.GitHub.Bucket.Enabled && cfg.GitHub.Bucket.AutoCommit {
+		repos = append(repos, BucketRepo(cfg))
+	}
+	return repos
+}
+
+// OpenManifestPRs opens a draft pull request from branch in the tap and
+// bucket, reusing one that is already open
+func (c *Client) OpenManifestPRs(ctx context.Context, cfg *config.Config, branch string) ([]*github.PullRequest, error) {
+	var prs []*github.PullRequest
+	for _, fullRepo := range manifestRepos(cfg) {
+		pr, err := c.openDraftPR(ctx, cfg, fullRepo, branch)
+		if err != nil {
+			return prs, fmt.Errorf("%s: %w", fullRepo, err)
+		}
+		prs = append(prs, pr)
+	}
+	return prs, nil
+}
+
+func (c *Client) openDraftPR(ctx context.Context, cfg *config.Config, fullRepo, branch string) (*github.PullRequest, error) {
+	owner, repo, ok := strings.Cut(fullRepo, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid repo format: %s", fullRepo)
+	}
+
+	if pr, err := c.findPR(ctx, owner, repo, branch); err != nil || pr != nil {
+		return pr, err
+	}
+
+	repository, _, err := c.gh.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	body := fmt.Sprintf(`Updates %s to v%s.
+
+This draft is held back until the %s release is published, then marked
+ready and merged by `+"`bagboy promote`"+`.
+
+---
+*This PR was automatically generated by bagboy*`, cfg.Name, cfg.Version, cfg.Tag())
+
+	pr, resp, err := c.gh.PullRequests.Create(ctx, owner, repo, &github.NewPullRequest{
+		Title: github.String(fmt.Sprintf("Update %s to v%s", cfg.Name, cfg.Version)),
+		Head:  github.String(branch),
+		Base:  github.String(repository.GetDefaultBranch()),
+		Body:  github.String(body),
+		Draft: github.Bool(true),
+	})
+	record(ctx, audit.PROpen, fullRepo, pr.GetHTMLURL(), resp, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	return pr, nil
+}
+
+// findPR returns the open pull request from branch, or nil
+func (c *Client) findPR(ctx context.Context, owner, repo, branch string) (*github.PullRequest, error) {
+	prs, _, err := c.gh.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{
+		State: "open",
+		Head:  owner + ":" + branch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	if len(prs) == 0 {
+		return nil, nil
+	}
+	return prs[0], nil
+}
+
+// ReleasePublished reports whether cfg's release is out: published and
+// no longer a prerelease
+func (c *Client) ReleasePublished(ctx context.Context, cfg *config.Config) (bool, error) {
+	release, err := c.findRelease(ctx, cfg.GitHub.Owner, cfg.GitHub.Repo, cfg.Tag())
+	if err != nil {
+		return false, err
+	}
+	return !release.GetDraft() && !release.GetPrerelease(), nil
+}
+
+// PromoteManifestPRs marks the draft pull requests from branch ready and
+// enables auto-merge on them. Where the repository does not allow
+// auto-merge they are merged straight away.
+func (c *Client) PromoteManifestPRs(ctx context.Context, cfg *config.Config, branch string) error {
+	method := cfg.GitHub.ManifestPRs.MergeMethod
+	if method == "" {
+		method = "squash"
+	}
+
+	for _, fullRepo := range manifestRepos(cfg) {
+		owner, repo, ok := strings.Cut(fullRepo, "/")
+		if !ok {
+			return fmt.Errorf("invalid repo format: %s", fullRepo)
+		}
+		pr, err := c.findPR(ctx, owner, repo, branch)
+		if err != nil {
+			return fmt.Errorf("%s: %w", fullRepo, err)
+		}
+		if pr == nil {
+			fmt.Printf("⚠️  No open pull request from %s in %s\n", branch, fullRepo)
+			continue
+		}
+		target := fmt.Sprintf("%s#%d", fullRepo, pr.GetNumber())
+
+		if pr.GetDraft() {
+			err := c.graphQL(ctx, `mutation($id: ID!) {
+  markPullRequestReadyForReview(input: {pullRequestId: $id}) { clientMutationId }
+}`, map[string]any{"id": pr.GetNodeID()})
+			record(ctx, audit.PRReady, target, pr.GetHTMLURL(), nil, err)
+			if err != nil {
+				return fmt.Errorf("failed to mark %s ready: %w", target, err)
+			}
+		}
+
+		err = c.graphQL(ctx, `mutation($id: ID!, $method: PullRequestMergeMethod!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: $method}) { clientMutationId }
+}`, map[string]any{"id": pr.GetNodeID(), "method": strings.ToUpper(method)})
+		if err == nil {
+			record(ctx, audit.PRMerge, target, "auto-merge", nil, nil)
+			fmt.Printf("✅ Enabled auto-merge on %s\n", pr.GetHTMLURL())
+			continue
+		}
+
+		// Auto-merge is off for the repository, or the pull request
+		// can already be merged
+		result, resp, err := c.gh.PullRequests.Merge(ctx, owner, repo, pr.GetNumber(), "", &github.PullRequestOptions{MergeMethod: method})
+		record(ctx, audit.PRMerge, target, result.GetSHA(), resp, err)
+		if err != nil {
+			return fmt.Errorf("failed to merge %s: %w", target, err)
+		}
+
+		resp, err = c.gh.Git.DeleteRef(ctx, owner, repo, "heads/"+branch)
+		record(ctx, audit.BranchDelete, fullRepo+"@"+branch, "", resp, err)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to delete branch %s in %s: %v\n", branch, fullRepo, err)
+		}
+		fmt.Printf("✅ Merged %s\n", pr.GetHTMLURL())
+	}
+	return nil
+}
+
+// graphQL runs a GraphQL mutation, for the pull request changes the REST
+// API has no endpoint for
+func (c *Client) graphQL(ctx context.Context, query string, variables map[string]any) error {
+	endpoint := *c.gh.BaseURL
+	// https://api.github.com/graphql, or /api/graphql on GitHub Enterprise
+	endpoint.Path = strings.TrimSuffix(strings.TrimSuffix(endpoint.Path, "/"), "/v3") + "/graphql"
+
+	req, err := c.gh.NewRequest("POST", endpoint.String(), map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if _, err := c.gh.Do(ctx, req, &result); err != nil {
+		return err
+	}
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%s", result.Errors[0].Message)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func manifestPRConfig() *config.Config {
+	return &config.Config{
+		Name:    "myapp",
+		Version: "1.0.0",
+		GitHub: config.GitHubConfig{
+			Owner:       "acme",
+			Repo:        "myapp",
+			Release:     config.ReleaseConfig{Prerelease: true},
+			Tap:         config.TapConfig{Enabled: true, AutoCommit: true},
+			ManifestPRs: config.ManifestPRConfig{Enabled: true},
+		},
+	}
+}
+
+func TestHoldManifests(t *testing.T) {
+	cfg := manifestPRConfig()
+	if !HoldManifests(cfg) {
+		t.Error("Expected a prerelease to hold manifests back")
+	}
+	cfg.GitHub.Release.Prerelease = false
+	if HoldManifests(cfg) {
+		t.Error("Expected a full release to update manifests directly")
+	}
+}
+
+func TestOpenManifestPRs(t *testing.T) {
+	var created map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/repos/acme/homebrew-tap/pulls":
+			if r.URL.Query().Get("head") != "acme:bagboy/release-v1.0.0" {
+				t.Errorf("Unexpected query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`[]`))
+		case r.Method == "GET" && r.URL.Path == "/repos/acme/homebrew-tap":
+			w.Write([]byte(`{"default_branch": "main"}`))
+		case r.Method == "POST" && r.URL.Path == "/repos/acme/homebrew-tap/pulls":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"number": 7, "draft": true, "html_url": "https://github.com/acme/homebrew-tap/pull/7"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	prs, err := newTestClient(t, server.URL).OpenManifestPRs(context.Background(), manifestPRConfig(), "bagboy/release-v1.0.0")
+	if err != nil {
+		t.Fatalf("OpenManifestPRs failed: %v", err)
+	}
+	if len(prs) != 1 || prs[0].GetNumber() != 7 {
+		t.Fatalf("Unexpected pull requests %+v", prs)
+	}
+	if created["draft"] != true || created["base"] != "main" || created["head"] != "bagboy/release-v1.0.0" {
+		t.Errorf("Unexpected pull request %v", created)
+	}
+}
+
+func TestPromoteManifestPRs(t *testing.T) {
+	var mutations []string
+	merged, deleted := false, false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/repos/acme/homebrew-tap/pulls":
+			w.Write([]byte(`[{"number": 7, "draft": true, "node_id": "PR_7"}]`))
+		case r.Method == "POST" && r.URL.Path == "/graphql":
+			var body struct {
+				Query     string         `json:"query"`
+				Variables map[string]any `json:"variables"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.Variables["id"] != "PR_7" {
+				t.Errorf("Unexpected variables %v", body.Variables)
+			}
+			mutations = append(mutations, body.Query)
+			if strings.Contains(body.Query, "enablePullRequestAutoMerge") {
+				w.Write([]byte(`{"errors": [{"message": "Auto merge is not allowed for this repository"}]}`))
+				return
+			}
+			w.Write([]byte(`{"data": {}}`))
+		case r.Method == "PUT" && r.URL.Path == "/repos/acme/homebrew-tap/pulls/7/merge":
+			merged = true
+			w.Write([]byte(`{"sha": "abc123", "merged": true}`))
+		case r.Method == "DELETE" && r.URL.Path == "/repos/acme/homebrew-tap/git/refs/heads/bagboy/release-v1.0.0":
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	if err := newTestClient(t, server.URL).PromoteManifestPRs(context.Background(), manifestPRConfig(), "bagboy/release-v1.0.0"); err != nil {
+		t.Fatalf("PromoteManifestPRs failed: %v", err)
+	}
+	if len(mutations) != 2 || !strings.Contains(mutations[0], "markPullRequestReadyForReview") {
+		t.Errorf("Unexpected mutations %v", mutations)
+	}
+	if !merged || !deleted {
+		t.Errorf("Expected the pull request merged without auto-merge (merged %v, branch deleted %v)", merged, deleted)
+	}
+}
@@ -0,0 +1,103 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/release"
+	"gopkg.in/yaml.v3"
+)
+
+// maxManifestSize skips downloading assets too large to be manifests
+const maxManifestSize = 1 << 20
+
+// maxContentsSize skips downloading packages too large to list
+const maxContentsSize = 512 << 20
+
+// ReleaseSnapshot collects the assets, checksums, manifests and declared
+// dependencies of the release tagged tag for comparison with bagboy diff.
+// Dependencies are read from the config file at the tag, when present.
+// With contents, deb, rpm, zip and tarball assets are downloaded to list
+// the files inside them.
+func (c *Client) ReleaseSnapshot(ctx context.Context, cfg *config.Config, tag string, contents bool) (*release.Snapshot, error) {
+	owner, repo := cfg.GitHub.Owner, cfg.GitHub.Repo
+
+	rel, err := c.findRelease(ctx, owner, repo, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &release.Snapshot{Tag: tag, Manifests: make(map[string]string), Contents: make(map[string]*release.Contents)}
+
+	checksums := make(map[string]string)
+	for _, asset := range rel.Assets {
+		snapshot.Assets = append(snapshot.Assets, release.Asset{Name: asset.GetName(), Size: int64(asset.GetSize())})
+
+		switch {
+		case release.IsChecksumFile(asset.GetName()):
+			content, err := c.downloadAsset(ctx, owner, repo, asset)
+			if err != nil {
+				return nil, err
+			}
+			for name, sum := range release.ParseChecksums(content) {
+				checksums[name] = sum
+			}
+		case release.IsManifest(asset.GetName()) && asset.GetSize() <= maxManifestSize:
+			content, err := c.downloadAsset(ctx, owner, repo, asset)
+			if err != nil {
+				return nil, err
+			}
+			snapshot.Manifests[asset.GetName()] = content
+		case contents && release.HasContents(asset.GetName()) && asset.GetSize() <= maxContentsSize:
+			content, err := c.downloadAsset(ctx, owner, repo, asset)
+			if err != nil {
+				return nil, err
+			}
+			// Packages that can't be read are compared by size only
+			if files, err := release.ReadContents(asset.GetName(), []byte(content)); err == nil {
+				snapshot.Contents[asset.GetName()] = files
+			}
+		}
+	}
+
+	for i := range snapshot.Assets {
+		snapshot.Assets[i].SHA256 = checksums[snapshot.Assets[i].Name]
+	}
+
+	for _, path := range []string{"bagboy.yaml", "bagboy.yml", ".bagboy.yaml", ".bagboy.yml"} {
+		file, _, _, err := c.gh.Repositories.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: tag})
+		if err != nil || file == nil {
+			continue
+		}
+		content, err := file.GetContent()
+		if err != nil {
+			continue
+		}
+		var tagged config.Config
+		if err := yaml.Unmarshal([]byte(content), &tagged); err != nil {
+			return nil, fmt.Errorf("failed to parse %s at %s: %w", path, tag, err)
+		}
+		snapshot.Dependencies = tagged.Dependencies
+		break
+	}
+
+	return snapshot, nil
+}
+
+func (c *Client) downloadAsset(ctx context.Context, owner, repo string, asset *github.ReleaseAsset) (string, error) {
+	rc, _, err := c.gh.Repositories.DownloadReleaseAsset(ctx, owner, repo, asset.GetID(), http.DefaultClient)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", asset.GetName(), err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", asset.GetName(), err)
+	}
+	return string(data), nil
+}
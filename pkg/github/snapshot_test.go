@@ -0,0 +1,64 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestReleaseSnapshot(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/testowner/testrepo/releases", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id":1,"tag_name":"v1.0.0","assets":[
+			{"id":11,"name":"myapp_1.0.0_amd64.deb","size":100},
+			{"id":12,"name":"checksums.txt","size":60},
+			{"id":13,"name":"myapp.rb","size":20}
+		]}]`)
+	})
+	mux.HandleFunc("/repos/testowner/testrepo/releases/assets/12", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "abc123  myapp_1.0.0_amd64.deb\n")
+	})
+	mux.HandleFunc("/repos/testowner/testrepo/releases/assets/13", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "class Myapp < Formula\nend\n")
+	})
+	mux.HandleFunc("/repos/testowner/testrepo/contents/bagboy.yaml", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ref") != "v1.0.0" {
+			t.Errorf("Expected config read at the tag, got ref %q", r.URL.Query().Get("ref"))
+		}
+		content := base64.StdEncoding.EncodeToString([]byte("dependencies:\n  runtime:\n    node: \"18\"\n"))
+		fmt.Fprintf(w, `{"type":"file","encoding":"base64","content":%q}`, content)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	cfg := &config.Config{GitHub: config.GitHubConfig{Owner: "testowner", Repo: "testrepo"}}
+
+	snapshot, err := client.ReleaseSnapshot(context.Background(), cfg, "v1.0.0", false)
+	if err != nil {
+		t.Fatalf("ReleaseSnapshot failed: %v", err)
+	}
+
+	if len(snapshot.Assets) != 3 {
+		t.Fatalf("Expected 3 assets, got %d", len(snapshot.Assets))
+	}
+	if snapshot.Assets[0].SHA256 != "abc123" {
+		t.Errorf("Expected checksum from checksums.txt, got %q", snapshot.Assets[0].SHA256)
+	}
+	if snapshot.Manifests["myapp.rb"] != "class Myapp < Formula\nend\n" {
+		t.Errorf("Expected formula manifest, got %q", snapshot.Manifests["myapp.rb"])
+	}
+	if snapshot.Dependencies.Runtime["node"] != "18" {
+		t.Errorf("Expected dependencies from tagged config, got %v", snapshot.Dependencies)
+	}
+
+	if _, err := client.ReleaseSnapshot(context.Background(), cfg, "v9.9.9", false); err == nil {
+		t.Error("Expected error for unknown tag")
+	}
+}
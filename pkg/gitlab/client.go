@@ -0,0 +1,325 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gitlab publishes releases to gitlab.com or a self-managed GitLab
+// through the REST API: releases with their assets in the generic package
+// registry, and formula and manifest commits to tap and bucket projects.
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/audit"
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/snippets"
+	"github.com/scttfrdmn/bagboy/pkg/upload"
+)
+
+// DefaultTokenEnv holds the access token when gitlab.token_env is unset
+const DefaultTokenEnv = "GITLAB_TOKEN"
+
+type Client struct {
+	HTTP  *http.Client
+	api   string
+	token string
+}
+
+// Release is a GitLab release as returned by the API
+type Release struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	Links   struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+// URL returns the release's web page
+func (r *Release) URL() string {
+	return r.Links.Self
+}
+
+// Link is a release asset link
+type Link struct {
+	Name            string `json:"name"`
+	URL             string `json:"url"`
+	DirectAssetPath string `json:"direct_asset_path,omitempty"`
+	LinkType        string `json:"link_type,omitempty"`
+}
+
+func NewClient(cfg *config.GitLabConfig) (*Client, error) {
+	env := cfg.TokenEnv
+	if env == "" {
+		env = DefaultTokenEnv
+	}
+	token := os.Getenv(env)
+	if token == "" {
+		return nil, fmt.Errorf("GitLab token not found in environment variable %s", env)
+	}
+	return &Client{
+		HTTP:  http.DefaultClient,
+		api:   cfg.BaseURL() + "/api/v4",
+		token: token,
+	}, nil
+}
+
+// CreateRelease creates the release for cfg's tag with a link to every
+// asset. With release.assets generic the assets are first uploaded to the
+// project's generic package registry; with links they are expected at
+// installer.base_url already.
+func (c *Client) CreateRelease(ctx context.Context, cfg *config.Config, assets []string) (*Release, error) {
+	project := cfg.GitLab.Project
+	links := make([]Link, len(assets))
+	err := upload.Each(ctx, len(assets), func(ctx context.Context, i int) error {
+		name := filepath.Base(assets[i])
+		links[i] = Link{Name: name, DirectAssetPath: "/" + name, LinkType: linkType(name)}
+		if cfg.GitLab.Release.Assets == "links" {
+			links[i].URL = strings.TrimSuffix(cfg.Installer.BaseURL, "/") + "/" + name
+			return nil
+		}
+		u, err := c.uploadPackageFile(ctx, project, cfg.Name, cfg.Version, assets[i])
+		if err != nil {
+			return fmt.Errorf("failed to upload asset %s: %w", assets[i], err)
+		}
+		links[i].URL = u
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tag := cfg.Tag()
+	body := map[string]any{
+		"tag_name":    tag,
+		"name":        tag,
+		"description": ReleaseNotes(cfg, assets),
+		"assets":      map[string]any{"links": links},
+	}
+	release := new(Release)
+	requestID, err := c.do(ctx, http.MethodPost, projectPath(project)+"/releases", body, release)
+	audit.Result(ctx, audit.ReleaseCreate, project+"@"+tag, release.URL(), requestID, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release: %w", err)
+	}
+	return release, nil
+}
+
+// PackageFileURL returns where the generic package registry serves a file
+func (c *Client) PackageFileURL(project, pkg, version, name string) string {
+	return fmt.Sprintf("%s%s/packages/generic/%s/%s/%s", c.api, projectPath(project), url.PathEscape(pkg), url.PathEscape(version), url.PathEscape(name))
+}
+
+// uploadPackageFile puts one file into the generic package registry,
+// retrying transient failures, and returns its download URL
+func (c *Client) uploadPackageFile(ctx context.Context, project, pkg, version, path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	name := filepath.Base(path)
+	target := c.PackageFileURL(project, pkg, version, name)
+
+	var progress *upload.Progress
+	if info.Size() > upload.LargeAssetThreshold {
+		progress = upload.NewProgress(os.Stdout, name, info.Size())
+		defer progress.Done()
+	}
+
+	err = upload.Retry(ctx, upload.DefaultAttempts, func(attempt int) error {
+		file, err := os.Open(path)
+		if err != nil {
+			return upload.Permanent(err)
+		}
+		defer file.Close()
+
+		var body io.Reader = file
+		if progress != nil {
+			progress.Set(0)
+			body = progress.Reader(file)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, upload.Throttle(body))
+		if err != nil {
+			return upload.Permanent(err)
+		}
+		req.ContentLength = info.Size()
+		req.Header.Set("Content-Type", "application/octet-stream")
+		requestID, err := c.send(req, nil)
+		audit.Result(ctx, audit.AssetUpload, project, target, requestID, err)
+		return err
+	})
+	return target, err
+}
+
+// UpdateTap commits the Homebrew formula to the tap project
+func (c *Client) UpdateTap(ctx context.Context, cfg *config.Config, formula string) error {
+	tap := cfg.GitLab.Tap
+	if !tap.Enabled {
+		return nil
+	}
+	return c.updateFile(ctx, tap, fmt.Sprintf("Formula/%s.rb", cfg.Name), formula, commitMessage(cfg))
+}
+
+// UpdateTapCask commits the cask to Casks/<token>.rb in the tap project
+func (c *Client) UpdateTapCask(ctx context.Context, cfg *config.Config, token, cask string) error {
+	tap := cfg.GitLab.Tap
+	if !tap.Enabled {
+		return nil
+	}
+	return c.updateFile(ctx, tap, fmt.Sprintf("Casks/%s.rb", token), cask, commitMessage(cfg))
+}
+
+// UpdateBucket commits the Scoop manifest to the bucket project
+func (c *Client) UpdateBucket(ctx context.Context, cfg *config.Config, manifest string) error {
+	bucket := cfg.GitLab.Bucket
+	if !bucket.Enabled {
+		return nil
+	}
+	return c.updateFile(ctx, bucket, fmt.Sprintf("bucket/%s.json", cfg.Name), manifest, commitMessage(cfg))
+}
+
+func commitMessage(cfg *config.Config) string {
+	return fmt.Sprintf("Update %s to v%s", cfg.Name, cfg.Version)
+}
+
+// updateFile creates or replaces path on the repo's branch
+func (c *Client) updateFile(ctx context.Context, repo config.GitLabRepoConfig, path, content, message string) error {
+	branch := repo.Branch
+	if branch == "" {
+		branch = "main"
+	}
+	endpoint := projectPath(repo.Project) + "/repository/files/" + url.PathEscape(path)
+
+	method := http.MethodPut
+	_, err := c.do(ctx, http.MethodGet, endpoint+"?ref="+url.QueryEscape(branch), nil, nil)
+	var status *upload.StatusError
+	switch {
+	case errors.As(err, &status) && status.Code == http.StatusNotFound:
+		method = http.MethodPost
+	case err != nil:
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	body := map[string]string{
+		"branch":         branch,
+		"content":        content,
+		"commit_message": message,
+	}
+	requestID, err := c.do(ctx, method, endpoint, body, nil)
+	audit.Result(ctx, audit.FileCommit, fmt.Sprintf("%s@%s:%s", repo.Project, branch, path), "", requestID, err)
+	if err != nil {
+		return fmt.Errorf("failed to update file %s: %w", path, err)
+	}
+
+	fmt.Printf("✅ Updated %s:%s\n", repo.Project, path)
+	return nil
+}
+
+// ReleaseNotes renders the release description: how to install with
+// each package manager. GitLab lists the asset links itself.
+func ReleaseNotes(cfg *config.Config, assets []string) string {
+	install := snippets.Generate(cfg, assets)
+	if len(install) == 0 {
+		return cfg.Tag()
+	}
+	var b strings.Builder
+	b.WriteString("## Install\n")
+	for _, s := range install {
+		fmt.Fprintf(&b, "\n**%s**\n```\n%s\n```\n", s.Manager, s.Command)
+	}
+	return b.String()
+}
+
+// linkType sorts assets into GitLab's link types
+func linkType(name string) string {
+	for _, ext := range []string{".deb", ".rpm", ".apk", ".msi", ".msix", ".dmg", ".pkg", ".nupkg", ".snap", ".AppImage"} {
+		if strings.HasSuffix(name, ext) {
+			return "package"
+		}
+	}
+	return "other"
+}
+
+func projectPath(project string) string {
+	return "/projects/" + url.PathEscape(project)
+}
+
+// do sends a JSON request to the API, decodes the response into out and
+// returns the request's ID for the audit log. Error responses are returned
+// as a *upload.StatusError.
+func (c *Client) do(ctx context.Context, method, endpoint string, in, out any) (string, error) {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return "", err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.api+endpoint, body)
+	if err != nil {
+		return "", err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.send(req, out)
+}
+
+func (c *Client) send(req *http.Request, out any) (string, error) {
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	requestID := audit.RequestID(resp.Header)
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return requestID, err
+	}
+	if resp.StatusCode >= 300 {
+		return requestID, &upload.StatusError{Code: resp.StatusCode, Body: errorMessage(data)}
+	}
+	if out == nil {
+		return requestID, nil
+	}
+	return requestID, json.Unmarshal(data, out)
+}
+
+// errorMessage extracts the message of a GitLab error response
+func errorMessage(data []byte) string {
+	var resp struct {
+		Message any    `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(data, &resp) != nil {
+		return strings.TrimSpace(string(data))
+	}
+	if resp.Message != nil {
+		return fmt.Sprint(resp.Message)
+	}
+	return resp.Error
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// fakeGitLab records the requests made to it
+type fakeGitLab struct {
+	mu       sync.Mutex
+	uploads  map[string]string
+	release  map[string]any
+	files    map[string]string
+	requests []string
+}
+
+func (f *fakeGitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		io.WriteString(w, `{"message":"401 Unauthorized"}`)
+		return
+	}
+	path := r.URL.EscapedPath()
+	f.requests = append(f.requests, r.Method+" "+path)
+	body, _ := io.ReadAll(r.Body)
+
+	switch {
+	case r.Method == http.MethodPut && strings.HasPrefix(path, "/api/v4/projects/group%2Fmyapp/packages/generic/"):
+		f.uploads[strings.TrimPrefix(path, "/api/v4/projects/group%2Fmyapp/packages/generic/")] = string(body)
+		io.WriteString(w, `{"message":"201 Created"}`)
+	case r.Method == http.MethodPost && path == "/api/v4/projects/group%2Fmyapp/releases":
+		json.Unmarshal(body, &f.release)
+		io.WriteString(w, `{"tag_name":"v1.2.3","_links":{"self":"https://gitlab.example.com/group/myapp/-/releases/v1.2.3"}}`)
+	case strings.HasPrefix(path, "/api/v4/projects/group%2Fhomebrew-tap/repository/files/"):
+		file := strings.TrimPrefix(path, "/api/v4/projects/group%2Fhomebrew-tap/repository/files/")
+		switch r.Method {
+		case http.MethodGet:
+			if _, ok := f.files[file]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				io.WriteString(w, `{"message":"404 File Not Found"}`)
+			}
+		default:
+			var req map[string]string
+			json.Unmarshal(body, &req)
+			f.files[file] = r.Method + " " + req["branch"] + " " + req["content"]
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestClient(t *testing.T) (*Client, *fakeGitLab) {
+	t.Helper()
+	fake := &fakeGitLab{uploads: map[string]string{}, files: map[string]string{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	t.Setenv("GITLAB_TOKEN", "secret")
+	client, err := NewClient(&config.GitLabConfig{URL: server.URL + "/", Project: "group/myapp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, fake
+}
+
+func TestNewClient(t *testing.T) {
+	t.Setenv("RELEASE_TOKEN", "")
+	_, err := NewClient(&config.GitLabConfig{TokenEnv: "RELEASE_TOKEN"})
+	if err == nil || !strings.Contains(err.Error(), "RELEASE_TOKEN") {
+		t.Errorf("expected missing RELEASE_TOKEN error, got %v", err)
+	}
+
+	t.Setenv("GITLAB_TOKEN", "secret")
+	client, err := NewClient(&config.GitLabConfig{URL: "https://gitlab.example.com/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.api != "https://gitlab.example.com/api/v4" {
+		t.Errorf("api = %s", client.api)
+	}
+}
+
+func testConfig(t *testing.T) (*config.Config, []string) {
+	t.Helper()
+	dir := t.TempDir()
+	deb := filepath.Join(dir, "myapp_1.2.3_amd64.deb")
+	sums := filepath.Join(dir, "checksums.txt")
+	os.WriteFile(deb, []byte("deb"), 0644)
+	os.WriteFile(sums, []byte("sums"), 0644)
+
+	cfg := &config.Config{
+		Name:    "myapp",
+		Version: "1.2.3",
+		GitLab: config.GitLabConfig{
+			Project: "group/myapp",
+			Release: config.GitLabReleaseConfig{Enabled: true},
+		},
+	}
+	return cfg, []string{deb, sums}
+}
+
+func TestCreateRelease_Generic(t *testing.T) {
+	client, fake := newTestClient(t)
+	cfg, assets := testConfig(t)
+
+	release, err := client.CreateRelease(context.Background(), cfg, assets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.URL() != "https://gitlab.example.com/group/myapp/-/releases/v1.2.3" {
+		t.Errorf("URL = %s", release.URL())
+	}
+
+	if fake.uploads["myapp/1.2.3/myapp_1.2.3_amd64.deb"] != "deb" || fake.uploads["myapp/1.2.3/checksums.txt"] != "sums" {
+		t.Errorf("uploads = %v", fake.uploads)
+	}
+	if fake.release["tag_name"] != "v1.2.3" {
+		t.Errorf("tag_name = %v", fake.release["tag_name"])
+	}
+	links := fake.release["assets"].(map[string]any)["links"].([]any)
+	if len(links) != 2 {
+		t.Fatalf("links = %v", links)
+	}
+	deb := links[0].(map[string]any)
+	if deb["url"] != client.PackageFileURL("group/myapp", "myapp", "1.2.3", "myapp_1.2.3_amd64.deb") {
+		t.Errorf("url = %v", deb["url"])
+	}
+	if deb["direct_asset_path"] != "/myapp_1.2.3_amd64.deb" || deb["link_type"] != "package" {
+		t.Errorf("link = %v", deb)
+	}
+	if links[1].(map[string]any)["link_type"] != "other" {
+		t.Errorf("checksums link = %v", links[1])
+	}
+}
+
+func TestCreateRelease_Links(t *testing.T) {
+	client, fake := newTestClient(t)
+	cfg, assets := testConfig(t)
+	cfg.GitLab.Release.Assets = "links"
+	cfg.Installer.BaseURL = "https://downloads.example.com/myapp/"
+
+	if _, err := client.CreateRelease(context.Background(), cfg, assets); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.uploads) != 0 {
+		t.Errorf("links mode uploaded %v", fake.uploads)
+	}
+	links := fake.release["assets"].(map[string]any)["links"].([]any)
+	if url := links[1].(map[string]any)["url"]; url != "https://downloads.example.com/myapp/checksums.txt" {
+		t.Errorf("url = %v", url)
+	}
+}
+
+func TestCreateRelease_Unauthorized(t *testing.T) {
+	client, _ := newTestClient(t)
+	client.token = "wrong"
+	cfg, _ := testConfig(t)
+
+	_, err := client.CreateRelease(context.Background(), cfg, nil)
+	if err == nil || !strings.Contains(err.Error(), "401 Unauthorized") {
+		t.Errorf("expected 401 error, got %v", err)
+	}
+}
+
+func TestUpdateTap(t *testing.T) {
+	client, fake := newTestClient(t)
+	cfg, _ := testConfig(t)
+	cfg.GitLab.Tap = config.GitLabRepoConfig{Enabled: true, Project: "group/homebrew-tap"}
+
+	if err := client.UpdateTap(context.Background(), cfg, "class Myapp"); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.files["Formula%2Fmyapp.rb"]; got != "POST main class Myapp" {
+		t.Errorf("first update = %q, want a create on main", got)
+	}
+
+	cfg.GitLab.Tap.Branch = "master"
+	if err := client.UpdateTap(context.Background(), cfg, "class Myapp v2"); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.files["Formula%2Fmyapp.rb"]; got != "PUT master class Myapp v2" {
+		t.Errorf("second update = %q, want an update on master", got)
+	}
+
+	// The bucket is not enabled, so nothing is committed
+	if err := client.UpdateBucket(context.Background(), cfg, "{}"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,366 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gomodule checks that a tagged release of a Go module can be
+// installed with go install module@version: the tag is pushed, the module
+// proxy serves it, and the installed binary reports the released version.
+package gomodule
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/preflight"
+	"github.com/scttfrdmn/bagboy/pkg/semver"
+)
+
+// DefaultProxy is the module proxy used when GOPROXY names none
+const DefaultProxy = "https://proxy.golang.org"
+
+// DefaultPkgSite is the Go package discovery site
+const DefaultPkgSite = "https://pkg.go.dev"
+
+var majorSuffix = regexp.MustCompile(`^v([0-9]+)$`)
+
+// Verifier checks a module release against a module proxy and pkg.go.dev
+type Verifier struct {
+	Proxy   string
+	PkgSite string
+	HTTP    *http.Client
+}
+
+// NewVerifier returns a verifier using the first proxy in GOPROXY
+func NewVerifier() *Verifier {
+	return &Verifier{
+		Proxy:   ProxyURL(os.Getenv("GOPROXY")),
+		PkgSite: DefaultPkgSite,
+		HTTP:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// ProxyURL returns the first proxy in a GOPROXY list, skipping direct and
+// off
+func ProxyURL(goproxy string) string {
+	for _, entry := range strings.FieldsFunc(goproxy, func(r rune) bool { return r == ',' || r == '|' }) {
+		entry = strings.TrimSpace(entry)
+		if entry != "" && entry != "direct" && entry != "off" {
+			return strings.TrimSuffix(entry, "/")
+		}
+	}
+	return DefaultProxy
+}
+
+// ModulePath reads the module path from a go.mod file
+func ModulePath(goMod string) (string, error) {
+	f, err := os.Open(goMod)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "module"); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			rest, _, _ = strings.Cut(rest, "//")
+			rest = strings.TrimSpace(rest)
+			if unquoted, err := strconv.Unquote(rest); err == nil {
+				rest = unquoted
+			}
+			if rest != "" {
+				return rest, nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("no module directive in %s", goMod)
+}
+
+// EscapePath escapes a module path or version for proxy URLs, replacing
+// each upper-case letter with ! and its lower-case form
+func EscapePath(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// CheckMajorVersion reports a version whose major version does not match
+// the module path's /vN suffix; go install cannot resolve it
+func CheckMajorVersion(module, version string) error {
+	v, err := semver.Parse(version)
+	if err != nil {
+		return err
+	}
+
+	suffix := 0
+	if m := majorSuffix.FindStringSubmatch(path.Base(module)); m != nil && strings.Contains(module, "/") {
+		suffix, _ = strconv.Atoi(m[1])
+	}
+
+	switch {
+	case suffix == 0 && v.Major >= 2:
+		return fmt.Errorf("version %s needs the module path to end in /v%d, not %s", version, v.Major, module)
+	case suffix != 0 && v.Major != suffix:
+		return fmt.Errorf("version %s does not match the /v%d suffix of %s", version, suffix, module)
+	}
+	return nil
+}
+
+// Tag returns the tag for version of the module in dir. Modules below the
+// repository root are tagged with their directory as a prefix.
+func Tag(dir, version string) string {
+	dir = filepath.ToSlash(filepath.Clean(dir))
+	if dir == "." {
+		return version
+	}
+	return strings.TrimPrefix(dir, "./") + "/" + version
+}
+
+// Private reports whether GOPRIVATE or GONOPROXY exclude module from the
+// proxy
+func Private(module string) bool {
+	for _, env := range []string{"GONOPROXY", "GOPRIVATE"} {
+		for _, pattern := range strings.Split(os.Getenv(env), ",") {
+			pattern = strings.TrimSpace(pattern)
+			if pattern == "" {
+				continue
+			}
+			// Patterns match a leading run of path elements
+			elems := strings.Split(module, "/")
+			n := strings.Count(pattern, "/") + 1
+			if n > len(elems) {
+				continue
+			}
+			if ok, _ := path.Match(pattern, strings.Join(elems[:n], "/")); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Verify runs every check for the configured module at cfg.Version
+func (v *Verifier) Verify(ctx context.Context, cfg *config.Config) []preflight.Check {
+	var checks []preflight.Check
+	add := func(name, status, format string, args ...interface{}) {
+		checks = append(checks, preflight.Check{Name: name, Status: status, Message: fmt.Sprintf(format, args...)})
+	}
+
+	dir := cfg.GoModule.Dir
+	if dir == "" {
+		dir = "."
+	}
+	module, err := ModulePath(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		add("go module", preflight.StatusFail, "%v", err)
+		return checks
+	}
+	version := "v" + strings.TrimPrefix(cfg.Version, "v")
+	if err := CheckMajorVersion(module, version); err != nil {
+		add("go module", preflight.StatusFail, "%v", err)
+		return checks
+	}
+	add("go module", preflight.StatusPass, "%s@%s", module, version)
+
+	tag := Tag(dir, version)
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--tags", "origin", "refs/tags/"+tag)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	switch {
+	case err != nil:
+		add("release tag", preflight.StatusFail, "git ls-remote failed: %v: %s", err, strings.TrimSpace(string(output)))
+		return checks
+	case len(strings.TrimSpace(string(output))) == 0:
+		add("release tag", preflight.StatusFail, "%s has not been pushed to origin", tag)
+		return checks
+	}
+	add("release tag", preflight.StatusPass, "%s pushed", tag)
+
+	if Private(module) {
+		add("module proxy", preflight.StatusWarn, "%s is private; skipping proxy and pkg.go.dev checks", module)
+	} else {
+		status, message := v.CheckProxy(ctx, module, version)
+		add("module proxy", status, "%s", message)
+		if status != preflight.StatusPass {
+			return checks
+		}
+	}
+
+	status, message := Install(ctx, module, version, cfg)
+	add("go install", status, "%s", message)
+
+	if !Private(module) {
+		status, message = v.CheckPkgSite(ctx, module, version)
+		add("pkg.go.dev", status, "%s", message)
+	}
+
+	if status, message, ok := CheckBadge(filepath.Join(dir, "README.md"), module); ok {
+		add("pkg.go.dev badge", status, "%s", message)
+	}
+	return checks
+}
+
+// CheckProxy asks the module proxy for the version's info, which makes
+// the proxy fetch the tag if it has not seen it yet
+func (v *Verifier) CheckProxy(ctx context.Context, module, version string) (string, string) {
+	url := fmt.Sprintf("%s/%s/@v/%s.info", v.Proxy, EscapePath(module), EscapePath(version))
+	body, code, err := v.get(ctx, url)
+	if err != nil {
+		return preflight.StatusFail, fmt.Sprintf("%s: %v", v.Proxy, err)
+	}
+	if code != http.StatusOK {
+		return preflight.StatusFail, fmt.Sprintf("%s cannot serve %s@%s: %s", v.Proxy, module, version, strings.TrimSpace(string(body)))
+	}
+
+	var info struct {
+		Version string
+		Time    time.Time
+	}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return preflight.StatusFail, fmt.Sprintf("invalid response from %s: %v", v.Proxy, err)
+	}
+	if info.Version != version {
+		return preflight.StatusFail, fmt.Sprintf("%s resolved %s to %s", v.Proxy, version, info.Version)
+	}
+	return preflight.StatusPass, fmt.Sprintf("%s serves %s@%s", v.Proxy, module, version)
+}
+
+// CheckPkgSite checks pkg.go.dev has a page for the version. Missing
+// pages are only a warning since indexing lags the proxy.
+func (v *Verifier) CheckPkgSite(ctx context.Context, module, version string) (string, string) {
+	url := fmt.Sprintf("%s/%s@%s", v.PkgSite, module, version)
+	_, code, err := v.get(ctx, url)
+	switch {
+	case err != nil:
+		return preflight.StatusWarn, fmt.Sprintf("%s: %v", v.PkgSite, err)
+	case code == http.StatusOK:
+		return preflight.StatusPass, url
+	case code == http.StatusNotFound:
+		return preflight.StatusWarn, fmt.Sprintf("%s is not indexed yet; request it from that page", url)
+	default:
+		return preflight.StatusWarn, fmt.Sprintf("%s returned %d", url, code)
+	}
+}
+
+// CheckBadge looks for the pkg.go.dev badge in the README. ok is false
+// when there is no README.
+func CheckBadge(readme, module string) (status, message string, ok bool) {
+	data, err := os.ReadFile(readme)
+	if err != nil {
+		return "", "", false
+	}
+	if strings.Contains(string(data), "pkg.go.dev/badge/"+module) {
+		return preflight.StatusPass, "README links to pkg.go.dev", true
+	}
+	badge := fmt.Sprintf("[![Go Reference](%s/badge/%s.svg)](%s/%s)", DefaultPkgSite, module, DefaultPkgSite, module)
+	return preflight.StatusWarn, fmt.Sprintf("README has no pkg.go.dev badge; add %s", badge), true
+}
+
+// Install runs go install module@version into a temporary GOBIN and
+// checks the binary prints the version. Builds set the version with
+// -ldflags, which go install does not pass, so a binary that does not
+// fall back to debug.ReadBuildInfo reports its default instead.
+func Install(ctx context.Context, module, version string, cfg *config.Config) (string, string) {
+	target := module
+	if pkg := strings.Trim(cfg.GoModule.Package, "/"); pkg != "" {
+		target += "/" + pkg
+	}
+
+	gobin, err := os.MkdirTemp("", "bagboy-gobin-*")
+	if err != nil {
+		return preflight.StatusFail, err.Error()
+	}
+	defer os.RemoveAll(gobin)
+
+	cmd := exec.CommandContext(ctx, "go", "install", target+"@"+version)
+	cmd.Dir = gobin
+	cmd.Env = append(os.Environ(), "GOBIN="+gobin, "GOFLAGS=-mod=mod")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return preflight.StatusFail, fmt.Sprintf("go install %s@%s failed: %v\n%s", target, version, err, strings.TrimSpace(string(output)))
+	}
+
+	args := cfg.GoModule.VersionArgs
+	if len(args) == 0 {
+		args = []string{"--version"}
+	}
+	binary := filepath.Join(gobin, BinaryName(target))
+	output, _ := exec.CommandContext(ctx, binary, args...).CombinedOutput()
+	reported := strings.TrimSpace(string(output))
+	if !strings.Contains(reported, strings.TrimPrefix(version, "v")) {
+		return preflight.StatusFail, fmt.Sprintf("%s %s printed %q, not %s - fall back to debug.ReadBuildInfo when the version is not set with -ldflags",
+			filepath.Base(binary), strings.Join(args, " "), firstLine(reported), version)
+	}
+	return preflight.StatusPass, fmt.Sprintf("go install %s@%s reports %s", target, version, firstLine(reported))
+}
+
+// BinaryName returns the name go install gives the binary for a package
+// path, which skips a trailing major version element
+func BinaryName(pkg string) string {
+	name := path.Base(pkg)
+	if majorSuffix.MatchString(name) && strings.Contains(pkg, "/") {
+		name = path.Base(path.Dir(pkg))
+	}
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+func (v *Verifier) get(ctx context.Context, url string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := v.HTTP.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, 0, err
+	}
+	return body, resp.StatusCode, nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gomodule
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/preflight"
+)
+
+func TestProxyURL(t *testing.T) {
+	tests := map[string]string{
+		"":                                   DefaultProxy,
+		"direct":                             DefaultProxy,
+		"https://goproxy.io,direct":          "https://goproxy.io",
+		"off|https://athens.example.com/":    "https://athens.example.com",
+		"https://a.example.com|https://b.io": "https://a.example.com",
+	}
+	for goproxy, want := range tests {
+		if got := ProxyURL(goproxy); got != want {
+			t.Errorf("ProxyURL(%q) = %s, expected %s", goproxy, got, want)
+		}
+	}
+}
+
+func TestModulePath(t *testing.T) {
+	goMod := filepath.Join(t.TempDir(), "go.mod")
+	content := "// comment\nmodule \"github.com/Example/tool/v2\" // trailing\n\ngo 1.24\n"
+	if err := os.WriteFile(goMod, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	module, err := ModulePath(goMod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if module != "github.com/Example/tool/v2" {
+		t.Errorf("Unexpected module path %s", module)
+	}
+	if EscapePath(module) != "github.com/!example/tool/v2" {
+		t.Errorf("Unexpected escaped path %s", EscapePath(module))
+	}
+}
+
+func TestCheckMajorVersion(t *testing.T) {
+	tests := []struct {
+		module, version string
+		ok              bool
+	}{
+		{"github.com/example/tool", "v1.4.0", true},
+		{"github.com/example/tool", "v0.1.0", true},
+		{"github.com/example/tool", "v2.0.0", false},
+		{"github.com/example/tool/v2", "v2.1.0", true},
+		{"github.com/example/tool/v2", "v3.0.0", false},
+		{"github.com/example/tool/v3", "v1.0.0", false},
+	}
+	for _, tt := range tests {
+		if err := CheckMajorVersion(tt.module, tt.version); (err == nil) != tt.ok {
+			t.Errorf("CheckMajorVersion(%s, %s) = %v", tt.module, tt.version, err)
+		}
+	}
+}
+
+func TestTagAndBinaryName(t *testing.T) {
+	if got := Tag(".", "v1.0.0"); got != "v1.0.0" {
+		t.Errorf("Unexpected root tag %s", got)
+	}
+	if got := Tag("./tools/cli", "v1.0.0"); got != "tools/cli/v1.0.0" {
+		t.Errorf("Unexpected nested tag %s", got)
+	}
+
+	want := "tool"
+	if runtime.GOOS == "windows" {
+		want += ".exe"
+	}
+	for _, pkg := range []string{"github.com/example/tool", "github.com/example/tool/v2", "github.com/example/repo/cmd/tool"} {
+		if got := BinaryName(pkg); got != want {
+			t.Errorf("BinaryName(%s) = %s, expected %s", pkg, got, want)
+		}
+	}
+}
+
+func TestPrivate(t *testing.T) {
+	t.Setenv("GONOPROXY", "")
+	t.Setenv("GOPRIVATE", "*.corp.example.com,github.com/acme")
+	if !Private("git.corp.example.com/team/tool") || !Private("github.com/acme/tool") {
+		t.Error("Expected GOPRIVATE modules to be private")
+	}
+	if Private("github.com/example/tool") {
+		t.Error("Expected public module")
+	}
+}
+
+func TestCheckProxyAndPkgSite(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/github.com/!example/tool/@v/v1.2.0.info":
+			w.Write([]byte(`{"Version":"v1.2.0","Time":"2026-01-01T00:00:00Z"}`))
+		case "/github.com/Example/tool@v1.2.0":
+			w.Write([]byte("<html></html>"))
+		default:
+			http.Error(w, "not found: unknown revision v1.3.0", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	v := &Verifier{Proxy: server.URL, PkgSite: server.URL, HTTP: server.Client()}
+	ctx := context.Background()
+
+	if status, message := v.CheckProxy(ctx, "github.com/Example/tool", "v1.2.0"); status != preflight.StatusPass {
+		t.Errorf("Expected proxy pass, got %s: %s", status, message)
+	}
+	status, message := v.CheckProxy(ctx, "github.com/Example/tool", "v1.3.0")
+	if status != preflight.StatusFail || !strings.Contains(message, "unknown revision") {
+		t.Errorf("Expected proxy failure, got %s: %s", status, message)
+	}
+
+	if status, _ := v.CheckPkgSite(ctx, "github.com/Example/tool", "v1.2.0"); status != preflight.StatusPass {
+		t.Errorf("Expected pkg.go.dev pass, got %s", status)
+	}
+	if status, _ := v.CheckPkgSite(ctx, "github.com/Example/tool", "v1.3.0"); status != preflight.StatusWarn {
+		t.Errorf("Expected pkg.go.dev warning, got %s", status)
+	}
+}
+
+func TestCheckBadge(t *testing.T) {
+	readme := filepath.Join(t.TempDir(), "README.md")
+	if _, _, ok := CheckBadge(readme, "github.com/example/tool"); ok {
+		t.Error("Expected no check without a README")
+	}
+
+	os.WriteFile(readme, []byte("# tool\n"), 0644)
+	status, message, _ := CheckBadge(readme, "github.com/example/tool")
+	if status != preflight.StatusWarn || !strings.Contains(message, "https://pkg.go.dev/badge/github.com/example/tool.svg") {
+		t.Errorf("Expected badge warning, got %s: %s", status, message)
+	}
+
+	os.WriteFile(readme, []byte("[![Go Reference](https://pkg.go.dev/badge/github.com/example/tool.svg)](https://pkg.go.dev/github.com/example/tool)\n"), 0644)
+	if status, _, _ := CheckBadge(readme, "github.com/example/tool"); status != preflight.StatusPass {
+		t.Errorf("Expected badge pass, got %s", status)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hooks runs the shell commands in the hooks: section before and
+// after the pack and publish stages and around each format, so tasks such
+// as compressing binaries or notifying other systems need no wrapper
+// script.
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// Stages of the hooks: section, as named in errors
+const (
+	BeforePack    = "before_pack"
+	AfterPack     = "after_pack"
+	BeforePublish = "before_publish"
+	AfterPublish  = "after_publish"
+)
+
+// Env is what a hook is told about the stage it runs in, as environment
+// variables
+type Env struct {
+	Name       string // NAME
+	Version    string // VERSION
+	Format     string // FORMAT, set for format hooks
+	OutputPath string // OUTPUT_PATH: the package for format hooks, the dist directory otherwise
+}
+
+// NewEnv returns the environment for a stage hook of cfg
+func NewEnv(cfg *config.Config) Env {
+	return Env{Name: cfg.Name, Version: cfg.Version, OutputPath: "dist"}
+}
+
+// Environ returns the process environment with env's variables added
+func (e Env) Environ() []string {
+	vars := append(os.Environ(), "NAME="+e.Name, "VERSION="+e.Version, "OUTPUT_PATH="+e.OutputPath)
+	if e.Format != "" {
+		vars = append(vars, "FORMAT="+e.Format)
+	}
+	return vars
+}
+
+// Run runs commands in the shell one after another, stopping at the first
+// that fails. Their output goes to bagboy's.
+func Run(ctx context.Context, stage string, commands []string, env Env) error {
+	for _, command := range commands {
+		cmd := shell(ctx, command)
+		cmd.Env = env.Environ()
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", stage, command, err)
+		}
+	}
+	return nil
+}
+
+// Stage runs the hooks cfg configures for stage
+func Stage(ctx context.Context, cfg *config.Config, stage string) error {
+	return Run(ctx, stage, cfg.Hooks.Stage(stage), NewEnv(cfg))
+}
+
+// Before runs the hooks cfg configures before format is packaged
+func Before(ctx context.Context, cfg *config.Config, format string) error {
+	env := NewEnv(cfg)
+	env.Format, env.OutputPath = format, ""
+	return Run(ctx, format+" before", cfg.Hooks.Formats[format].Before, env)
+}
+
+// After runs the hooks cfg configures after format is packaged into path
+func After(ctx context.Context, cfg *config.Config, format, path string) error {
+	env := NewEnv(cfg)
+	env.Format, env.OutputPath = format, path
+	return Run(ctx, format+" after", cfg.Hooks.Formats[format].After, env)
+}
+
+func shell(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hooks
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	env := Env{Name: "app", Version: "1.2.3", OutputPath: "dist"}
+
+	commands := []string{
+		`echo "$NAME $VERSION $OUTPUT_PATH" > ` + out,
+		"exit 1",
+		"echo unreachable >> " + out,
+	}
+	err := Run(context.Background(), BeforePack, commands, env)
+	if err == nil || !strings.Contains(err.Error(), `before_pack hook "exit 1" failed`) {
+		t.Errorf("Run() error = %v, want the failing command", err)
+	}
+	if got, _ := os.ReadFile(out); string(got) != "app 1.2.3 dist\n" {
+		t.Errorf("hooks wrote %q, want the first command's output only", got)
+	}
+}
+
+func TestStage(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	cfg := &config.Config{Name: "app", Version: "1.2.3"}
+	cfg.Hooks = config.HooksConfig{
+		AfterPack:    []string{"echo after_pack >> " + out},
+		AfterPublish: []string{"echo after_publish >> " + out},
+	}
+
+	for _, stage := range []string{BeforePack, AfterPack, BeforePublish, AfterPublish} {
+		if err := Stage(context.Background(), cfg, stage); err != nil {
+			t.Fatalf("Stage(%s) error = %v", stage, err)
+		}
+	}
+	if got, _ := os.ReadFile(out); string(got) != "after_pack\nafter_publish\n" {
+		t.Errorf("hooks wrote %q", got)
+	}
+}
+
+func TestEnviron(t *testing.T) {
+	vars := strings.Join(Env{Name: "app", Version: "1.0.0"}.Environ(), "\n")
+	if strings.Contains(vars, "FORMAT=") {
+		t.Error("stage hooks should not set FORMAT")
+	}
+	vars = strings.Join(Env{Name: "app", Format: "deb", OutputPath: "dist/app.deb"}.Environ(), "\n")
+	for _, want := range []string{"NAME=app", "FORMAT=deb", "OUTPUT_PATH=dist/app.deb"} {
+		if !strings.Contains(vars, want) {
+			t.Errorf("Environ() missing %s", want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i18n
+
+var german = map[string]string{
+	// Errors and hints
+	"Interrupted":        "Abgebrochen",
+	"Timed out after %s": "Zeitüberschreitung nach %s",
+	"Run 'bagboy --help' for usage information": "Hilfe zur Verwendung: 'bagboy --help'",
+	"Removed partial %s":                        "Unvollständige Datei %s entfernt",
+	"Cleanup incomplete: %v":                    "Aufräumen unvollständig: %v",
+	"Finished packages were kept; run 'bagboy pack --format %s' to create the rest": "Fertige Pakete wurden behalten; 'bagboy pack --format %s' erstellt den Rest",
+	"config validation failed: %w":      "Konfiguration ungültig: %w",
+	"unknown format %q (available: %s)": "Unbekanntes Format %q (verfügbar: %s)",
+
+	// pack and publish
+	"Creating All Package Formats":    "Alle Paketformate werden erstellt",
+	"Packaging":                       "Paketierung",
+	"Created %d packages, skipped %d": "%d Pakete erstellt, %d übersprungen",
+	"Created %s: %s":                  "%s erstellt: %s",
+	"Publishing Workflow":             "Veröffentlichung",
+	"Publishing %s %s":                "%s %s wird veröffentlicht",
+	"Created packages:":               "Erstellte Pakete:",
+	"Created GitHub release: %s":      "GitHub-Release erstellt: %s",
+
+	// Result tables
+	"Format":               "Format",
+	"Output Path":          "Ausgabepfad",
+	"Duration":             "Dauer",
+	"Status":               "Status",
+	"Success":              "Erfolgreich",
+	"Failed: %s":           "Fehlgeschlagen: %s",
+	"Skipped: %s":          "Übersprungen: %s",
+	"disabled":             "deaktiviert",
+	"not configured":       "nicht konfiguriert",
+	"platform unsupported": "Plattform nicht unterstützt",
+	"tool missing":         "Werkzeug fehlt",
+	"validation failed":    "Prüfung fehlgeschlagen",
+
+	// validate
+	"Validating Configuration":                        "Konfiguration wird geprüft",
+	"No bagboy configuration file found":              "Keine bagboy-Konfigurationsdatei gefunden",
+	"Run 'bagboy init' to create a new configuration": "Mit 'bagboy init' eine neue Konfiguration anlegen",
+	"Failed to load configuration file":               "Konfigurationsdatei konnte nicht geladen werden",
+	"Configuration validation failed":                 "Prüfung der Konfiguration fehlgeschlagen",
+	"Configuration is valid":                          "Konfiguration ist gültig",
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i18n
+
+var japanese = map[string]string{
+	// Errors and hints
+	"Interrupted":        "中断されました",
+	"Timed out after %s": "%s 後にタイムアウトしました",
+	"Run 'bagboy --help' for usage information": "使い方は 'bagboy --help' を実行してください",
+	"Removed partial %s":                        "不完全なファイル %s を削除しました",
+	"Cleanup incomplete: %v":                    "クリーンアップが完了しませんでした: %v",
+	"Finished packages were kept; run 'bagboy pack --format %s' to create the rest": "作成済みのパッケージは残してあります。残りは 'bagboy pack --format %s' で作成できます",
+	"config validation failed: %w":      "設定の検証に失敗しました: %w",
+	"unknown format %q (available: %s)": "不明な形式 %q (利用可能: %s)",
+
+	// pack and publish
+	"Creating All Package Formats":    "すべてのパッケージ形式を作成しています",
+	"Packaging":                       "パッケージ作成中",
+	"Created %d packages, skipped %d": "%d 個のパッケージを作成し、%d 個をスキップしました",
+	"Created %s: %s":                  "%s を作成しました: %s",
+	"Publishing Workflow":             "公開ワークフロー",
+	"Publishing %s %s":                "%s %s を公開しています",
+	"Created packages:":               "作成したパッケージ:",
+	"Created GitHub release: %s":      "GitHub リリースを作成しました: %s",
+
+	// Result tables
+	"Format":               "形式",
+	"Output Path":          "出力先",
+	"Duration":             "所要時間",
+	"Status":               "状態",
+	"Success":              "成功",
+	"Failed: %s":           "失敗: %s",
+	"Skipped: %s":          "スキップ: %s",
+	"disabled":             "無効",
+	"not configured":       "未設定",
+	"platform unsupported": "非対応のプラットフォーム",
+	"tool missing":         "ツールがありません",
+	"validation failed":    "検証エラー",
+
+	// validate
+	"Validating Configuration":                        "設定を検証しています",
+	"No bagboy configuration file found":              "bagboy の設定ファイルが見つかりません",
+	"Run 'bagboy init' to create a new configuration": "'bagboy init' で新しい設定を作成してください",
+	"Failed to load configuration file":               "設定ファイルを読み込めませんでした",
+	"Configuration validation failed":                 "設定の検証に失敗しました",
+	"Configuration is valid":                          "設定は有効です",
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package i18n translates bagboy's user-facing messages. Messages are
+// looked up by their English text, so a message without a translation
+// is shown in English.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// English is the language messages are written in
+const English = "en"
+
+// catalogs maps a language to its translations, keyed by English text
+var catalogs = map[string]map[string]string{
+	English: {},
+	"de":    german,
+	"ja":    japanese,
+}
+
+var (
+	mu       sync.RWMutex
+	language = English
+)
+
+// Supported lists the languages with a catalog
+func Supported() []string {
+	var langs []string
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Normalize turns a locale such as ja_JP.UTF-8 or de-DE into its language
+// code, or "" for the C and POSIX locales
+func Normalize(locale string) string {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	lang, _, _ := strings.Cut(strings.ReplaceAll(locale, "-", "_"), "_")
+	lang = strings.ToLower(lang)
+	if lang == "c" || lang == "posix" {
+		return ""
+	}
+	return lang
+}
+
+// Detect returns the language from LC_ALL, LC_MESSAGES or LANG, in the
+// order POSIX gives them precedence, or English when none is supported
+func Detect() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		if lang := Normalize(value); catalogs[lang] != nil {
+			return lang
+		}
+		return English
+	}
+	return English
+}
+
+// SetLanguage selects the language messages are translated to
+func SetLanguage(lang string) error {
+	lang = Normalize(lang)
+	if lang == "" {
+		lang = English
+	}
+	if catalogs[lang] == nil {
+		return fmt.Errorf("unsupported language %q (available: %s)", lang, strings.Join(Supported(), ", "))
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	language = lang
+	return nil
+}
+
+// Language returns the selected language
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return language
+}
+
+// T translates message, then formats it with args like fmt.Sprintf
+func T(message string, args ...any) string {
+	mu.RLock()
+	if translated, ok := catalogs[language][message]; ok {
+		message = translated
+	}
+	mu.RUnlock()
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package i18n
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestCatalogsKeepVerbs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+	for lang, catalog := range catalogs {
+		for english, translated := range catalog {
+			want := strings.Join(verbs.FindAllString(english, -1), " ")
+			got := strings.Join(verbs.FindAllString(translated, -1), " ")
+			if got != want {
+				t.Errorf("%s: %q has verbs %q, want %q", lang, translated, got, want)
+			}
+		}
+	}
+}
+
+func TestCatalogsTranslateTheSameMessages(t *testing.T) {
+	for english := range german {
+		if _, ok := japanese[english]; !ok {
+			t.Errorf("ja is missing %q", english)
+		}
+	}
+	for english := range japanese {
+		if _, ok := german[english]; !ok {
+			t.Errorf("de is missing %q", english)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		lcAll, lang string
+		want        string
+	}{
+		{"", "ja_JP.UTF-8", "ja"},
+		{"", "de_DE@euro", "de"},
+		{"C", "ja_JP.UTF-8", English},
+		{"", "fr_FR.UTF-8", English},
+		{"de_AT.UTF-8", "ja_JP.UTF-8", "de"},
+		{"", "", English},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_MESSAGES", "")
+		t.Setenv("LANG", tt.lang)
+		if got := Detect(); got != tt.want {
+			t.Errorf("Detect() with LC_ALL=%q LANG=%q = %q, want %q", tt.lcAll, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLanguage(English)
+
+	if got := T("Created %d packages, skipped %d", 3, 1); got != "Created 3 packages, skipped 1" {
+		t.Errorf("Expected English, got %q", got)
+	}
+	if err := SetLanguage("de-DE"); err != nil {
+		t.Fatal(err)
+	}
+	if got := T("Created %d packages, skipped %d", 3, 1); got != "3 Pakete erstellt, 1 übersprungen" {
+		t.Errorf("Expected German, got %q", got)
+	}
+	if got := T("A message with no translation"); got != "A message with no translation" {
+		t.Errorf("Expected untranslated messages in English, got %q", got)
+	}
+	if err := SetLanguage("xx"); err == nil {
+		t.Error("Expected error for an unsupported language")
+	}
+}
@@ -0,0 +1,435 @@
+package init
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// GoreleaserFiles are the file names goreleaser looks for, in order
+var GoreleaserFiles = []string{".goreleaser.yaml", ".goreleaser.yml", "goreleaser.yaml", "goreleaser.yml"}
+
+// goreleaserRepo is a repository reference in brews and scoops. Older
+// configs use tap and bucket instead of repository.
+type goreleaserRepo struct {
+	Owner string `yaml:"owner"`
+	Name  string `yaml:"name"`
+}
+
+func (r goreleaserRepo) fullName() string {
+	if r.Owner == "" || r.Name == "" {
+		return ""
+	}
+	return r.Owner + "/" + r.Name
+}
+
+type goreleaserFormats []string
+
+// UnmarshalYAML accepts both format: tar.gz and formats: [tar.gz]
+func (f *goreleaserFormats) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*f = []string{value.Value}
+		return nil
+	}
+	var formats []string
+	if err := value.Decode(&formats); err != nil {
+		return err
+	}
+	*f = formats
+	return nil
+}
+
+type goreleaserConfig struct {
+	ProjectName string `yaml:"project_name"`
+	Dist        string `yaml:"dist"`
+	Builds      []struct {
+		Binary string   `yaml:"binary"`
+		Goos   []string `yaml:"goos"`
+		Goarch []string `yaml:"goarch"`
+		Goarm  []string `yaml:"goarm"`
+		Ignore []struct {
+			Goos   string `yaml:"goos"`
+			Goarch string `yaml:"goarch"`
+		} `yaml:"ignore"`
+		Skip bool `yaml:"skip"`
+	} `yaml:"builds"`
+	Archives []struct {
+		NameTemplate    string            `yaml:"name_template"`
+		Format          goreleaserFormats `yaml:"format"`
+		Formats         goreleaserFormats `yaml:"formats"`
+		FormatOverrides []struct {
+			Goos    string            `yaml:"goos"`
+			Format  goreleaserFormats `yaml:"format"`
+			Formats goreleaserFormats `yaml:"formats"`
+		} `yaml:"format_overrides"`
+	} `yaml:"archives"`
+	Brews []struct {
+		Repository  goreleaserRepo `yaml:"repository"`
+		Tap         goreleaserRepo `yaml:"tap"`
+		Homepage    string         `yaml:"homepage"`
+		Description string         `yaml:"description"`
+		License     string         `yaml:"license"`
+		Test        string         `yaml:"test"`
+		Caveats     string         `yaml:"caveats"`
+		Conflicts   []string       `yaml:"conflicts"`
+	} `yaml:"brews"`
+	Scoops []struct {
+		Repository  goreleaserRepo `yaml:"repository"`
+		Bucket      goreleaserRepo `yaml:"bucket"`
+		Homepage    string         `yaml:"homepage"`
+		Description string         `yaml:"description"`
+		License     string         `yaml:"license"`
+	} `yaml:"scoops"`
+	Nfpms []struct {
+		Maintainer  string   `yaml:"maintainer"`
+		Vendor      string   `yaml:"vendor"`
+		Homepage    string   `yaml:"homepage"`
+		Description string   `yaml:"description"`
+		License     string   `yaml:"license"`
+		Section     string   `yaml:"section"`
+		Priority    string   `yaml:"priority"`
+		Formats     []string `yaml:"formats"`
+	} `yaml:"nfpms"`
+	Dockers []struct {
+		ImageTemplates []string `yaml:"image_templates"`
+	} `yaml:"dockers"`
+	Release struct {
+		GitHub     goreleaserRepo `yaml:"github"`
+		Draft      bool           `yaml:"draft"`
+		Prerelease string         `yaml:"prerelease"`
+	} `yaml:"release"`
+}
+
+// translatedSections are the goreleaser sections FromGoreleaser maps
+var translatedSections = map[string]bool{
+	"version": true, "project_name": true, "dist": true, "builds": true, "archives": true,
+	"brews": true, "scoops": true, "nfpms": true, "dockers": true, "release": true,
+	"before": true, "checksum": true, "changelog": true, "snapshot": true,
+}
+
+// FindGoreleaserConfig returns the goreleaser config in the current
+// directory
+func FindGoreleaserConfig() (string, error) {
+	for _, name := range GoreleaserFiles {
+		if _, err := os.Stat(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("no goreleaser config found (looked for %s)", strings.Join(GoreleaserFiles, ", "))
+}
+
+// FromGoreleaser reads a goreleaser config and applies its builds,
+// archives, brews, scoops, nfpms, dockers and release sections to cfg.
+// Archives become prebuilt globs, so bagboy packages what goreleaser
+// built. The returned notes describe what could not be translated.
+func FromGoreleaser(path string, cfg *config.Config) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var gr goreleaserConfig
+	if err := yaml.Unmarshal(data, &gr); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	var sections map[string]interface{}
+	if err := yaml.Unmarshal(data, &sections); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	var notes []string
+	note := func(format string, args ...interface{}) {
+		notes = append(notes, fmt.Sprintf(format, args...))
+	}
+
+	if gr.ProjectName != "" {
+		cfg.Name = gr.ProjectName
+	}
+
+	// Release repository
+	if gr.Release.GitHub.Owner != "" {
+		cfg.GitHub.Owner = gr.Release.GitHub.Owner
+		cfg.GitHub.Repo = gr.Release.GitHub.Name
+		cfg.Installer.BaseURL = fmt.Sprintf("https://github.com/%s/%s/releases/download/v{{.Version}}", cfg.GitHub.Owner, cfg.GitHub.Repo)
+	}
+	cfg.GitHub.Release.Draft = gr.Release.Draft
+	cfg.GitHub.Release.Prerelease = gr.Release.Prerelease == "true"
+	if gr.Release.Prerelease == "auto" {
+		note("release.prerelease: auto is not supported; set github.release.prerelease per release")
+	}
+
+	// Builds and archives become prebuilt archives
+	platforms, binary := goreleaserPlatforms(&gr)
+	if len(platforms) > 0 {
+		cfg.Binaries = nil
+		cfg.Prebuilt = config.PrebuiltConfig{Dir: gr.Dist, Archives: make(map[string]string), Binary: binary}
+		if binary == cfg.Name {
+			cfg.Prebuilt.Binary = ""
+		}
+		for _, platform := range platforms {
+			glob, err := archiveGlob(&gr, cfg.Name, binary, platform)
+			if err != nil {
+				note("%s: %v; check prebuilt.archives", platform, err)
+			}
+			cfg.Prebuilt.Archives[platform] = glob
+		}
+	}
+
+	// Homebrew tap
+	cfg.GitHub.Tap.Enabled = len(gr.Brews) > 0
+	if len(gr.Brews) > 0 {
+		brew := gr.Brews[0]
+		repo := brew.Repository.fullName()
+		if repo == "" {
+			repo = brew.Tap.fullName()
+		}
+		if repo != "" {
+			cfg.GitHub.Tap.Repo = repo
+		}
+		fillMetadata(cfg, brew.Homepage, brew.Description, brew.License)
+		cfg.Packages.Brew.Test = brew.Test
+		cfg.Packages.Brew.Caveats = brew.Caveats
+		for _, conflict := range brew.Conflicts {
+			cfg.Packages.Brew.ConflictsWith = append(cfg.Packages.Brew.ConflictsWith, config.BrewConflict{Name: conflict})
+		}
+		if len(gr.Brews) > 1 {
+			note("brews: only the first formula was imported")
+		}
+	}
+
+	// Scoop bucket
+	cfg.GitHub.Bucket.Enabled = len(gr.Scoops) > 0
+	if len(gr.Scoops) > 0 {
+		scoop := gr.Scoops[0]
+		repo := scoop.Repository.fullName()
+		if repo == "" {
+			repo = scoop.Bucket.fullName()
+		}
+		if repo != "" {
+			cfg.GitHub.Bucket.Repo = repo
+		}
+		fillMetadata(cfg, scoop.Homepage, scoop.Description, scoop.License)
+	}
+
+	// Linux packages
+	if len(gr.Nfpms) > 0 {
+		nfpm := gr.Nfpms[0]
+		cfg.Packages.Deb.Maintainer = nfpm.Maintainer
+		cfg.Packages.Deb.Section = nfpm.Section
+		cfg.Packages.Deb.Priority = nfpm.Priority
+		cfg.Packages.RPM.Vendor = nfpm.Vendor
+		fillMetadata(cfg, nfpm.Homepage, nfpm.Description, nfpm.License)
+		for _, format := range nfpm.Formats {
+			if format != "deb" && format != "rpm" {
+				note("nfpms: format %s is not supported and was skipped", format)
+			}
+		}
+	}
+
+	// Container registries
+	for _, docker := range gr.Dockers {
+		for _, image := range docker.ImageTemplates {
+			repository := imageRepository(image)
+			if repository != "" && !contains(cfg.Packages.Docker.Registries, repository) {
+				cfg.Packages.Docker.Registries = append(cfg.Packages.Docker.Registries, repository)
+			}
+		}
+	}
+
+	var skipped []string
+	for section := range sections {
+		if !translatedSections[section] {
+			skipped = append(skipped, section)
+		}
+	}
+	sort.Strings(skipped)
+	for _, section := range skipped {
+		note("%s: no bagboy equivalent; section skipped", section)
+	}
+
+	return notes, nil
+}
+
+// goreleaserPlatforms returns the os-arch pairs the builds produce and the
+// binary name of the first build. Goreleaser's defaults apply when goos
+// or goarch is omitted.
+func goreleaserPlatforms(gr *goreleaserConfig) ([]string, string) {
+	seen := make(map[string]bool)
+	var platforms []string
+	binary := ""
+
+	for _, build := range gr.Builds {
+		if build.Skip {
+			continue
+		}
+		if binary == "" {
+			binary = build.Binary
+		}
+
+		goos := build.Goos
+		if len(goos) == 0 {
+			goos = []string{"darwin", "linux", "windows"}
+		}
+		goarch := build.Goarch
+		if len(goarch) == 0 {
+			goarch = []string{"386", "amd64", "arm64"}
+		}
+
+		for _, system := range goos {
+			for _, arch := range goarch {
+				ignored := system == "darwin" && arch == "386"
+				for _, ignore := range build.Ignore {
+					if (ignore.Goos == "" || ignore.Goos == system) && (ignore.Goarch == "" || ignore.Goarch == arch) {
+						ignored = true
+					}
+				}
+				platform := system + "-" + arch
+				if !ignored && !seen[platform] {
+					seen[platform] = true
+					platforms = append(platforms, platform)
+				}
+			}
+		}
+	}
+
+	if binary == "" {
+		binary = gr.ProjectName
+	}
+	sort.Strings(platforms)
+	return platforms, binary
+}
+
+// defaultArchiveTemplate is goreleaser's default archive name
+const defaultArchiveTemplate = `{{ .ProjectName }}_{{ .Version }}_{{ .Os }}_{{ .Arch }}{{ with .Arm }}v{{ . }}{{ end }}{{ with .Mips }}_{{ . }}{{ end }}{{ if not (eq .Amd64 "v1") }}{{ .Amd64 }}{{ end }}`
+
+// archiveGlob renders the archive name template for platform with the
+// version replaced by a wildcard
+func archiveGlob(gr *goreleaserConfig, name, binary, platform string) (string, error) {
+	goos, goarch, _ := strings.Cut(platform, "-")
+
+	nameTemplate := defaultArchiveTemplate
+	formats := []string{"tar.gz"}
+	if len(gr.Archives) > 0 {
+		archive := gr.Archives[0]
+		if archive.NameTemplate != "" {
+			nameTemplate = archive.NameTemplate
+		}
+		if len(archive.Formats) > 0 {
+			formats = archive.Formats
+		} else if len(archive.Format) > 0 {
+			formats = archive.Format
+		}
+		for _, override := range archive.FormatOverrides {
+			if override.Goos != goos {
+				continue
+			}
+			if len(override.Formats) > 0 {
+				formats = override.Formats
+			} else if len(override.Format) > 0 {
+				formats = override.Format
+			}
+		}
+	}
+
+	ext := ""
+	switch format := formats[0]; format {
+	case "binary":
+	case "tar.gz", "tgz", "tar", "zip":
+		ext = "." + format
+	default:
+		return fmt.Sprintf("%s_*_%s_%s.%s", name, goos, goarch, format), fmt.Errorf("archive format %s cannot be unpacked", format)
+	}
+
+	fallback := fmt.Sprintf("%s_*_%s_%s%s", name, goos, goarch, ext)
+
+	t, err := template.New("archive").Funcs(template.FuncMap{
+		"title":      title,
+		"tolower":    strings.ToLower,
+		"toupper":    strings.ToUpper,
+		"replace":    strings.ReplaceAll,
+		"trimprefix": strings.TrimPrefix,
+		"trimsuffix": strings.TrimSuffix,
+	}).Parse(nameTemplate)
+	if err != nil {
+		return fallback, fmt.Errorf("unsupported name_template: %w", err)
+	}
+
+	amd64 := ""
+	if goarch == "amd64" {
+		amd64 = "v1"
+	}
+	arm := ""
+	if goarch == "arm" {
+		arm = "6"
+	}
+
+	var buf bytes.Buffer
+	err = t.Execute(&buf, map[string]string{
+		"ProjectName": name,
+		"Binary":      binary,
+		"Version":     "*",
+		"Tag":         "*",
+		"Os":          goos,
+		"Arch":        goarch,
+		"Arm":         arm,
+		"Amd64":       amd64,
+		"Mips":        "",
+	})
+	if err != nil {
+		return fallback, fmt.Errorf("unsupported name_template: %w", err)
+	}
+
+	glob := strings.ReplaceAll(buf.String(), "<no value>", "*")
+	for strings.Contains(glob, "**") {
+		glob = strings.ReplaceAll(glob, "**", "*")
+	}
+	return glob + ext, nil
+}
+
+// imageRepository strips the tag from an image template
+func imageRepository(image string) string {
+	slash := strings.LastIndex(image, "/")
+	if colon := strings.LastIndex(image, ":"); colon > slash {
+		image = image[:colon]
+	}
+	if strings.Contains(image, "{{") {
+		return ""
+	}
+	return image
+}
+
+func title(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// fillMetadata keeps detected homepage and description but prefers the
+// goreleaser license, since detection falls back to MIT
+func fillMetadata(cfg *config.Config, homepage, description, license string) {
+	if homepage != "" && cfg.Homepage == "" {
+		cfg.Homepage = homepage
+	}
+	if description != "" && cfg.Description == "" {
+		cfg.Description = description
+	}
+	if license != "" {
+		cfg.License = license
+	}
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package init
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+const testGoreleaser = `version: 2
+project_name: myapp
+builds:
+  - binary: myapp
+    goos: [linux, darwin, windows]
+    goarch: [amd64, arm64]
+    ignore:
+      - goos: windows
+        goarch: arm64
+archives:
+  - name_template: "{{ .ProjectName }}_{{ .Version }}_{{ title .Os }}_{{ .Arch }}"
+    formats: [tar.gz]
+    format_overrides:
+      - goos: windows
+        formats: [zip]
+brews:
+  - repository:
+      owner: acme
+      name: homebrew-tap
+    homepage: https://acme.dev/myapp
+    description: My app
+    license: Apache-2.0
+    test: system "#{bin}/myapp --version"
+scoops:
+  - bucket:
+      owner: acme
+      name: scoop-bucket
+nfpms:
+  - maintainer: Acme <ops@acme.dev>
+    vendor: Acme
+    formats: [deb, rpm, apk]
+dockers:
+  - image_templates:
+      - "ghcr.io/acme/myapp:{{ .Version }}"
+      - "ghcr.io/acme/myapp:latest"
+release:
+  github:
+    owner: acme
+    name: myapp
+announce:
+  skip: true
+`
+
+func TestFromGoreleaser(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".goreleaser.yaml")
+	if err := os.WriteFile(path, []byte(testGoreleaser), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Name:     "detected",
+		License:  "MIT",
+		Binaries: map[string]string{"linux-amd64": "myapp"},
+	}
+	notes, err := FromGoreleaser(path, cfg)
+	if err != nil {
+		t.Fatalf("FromGoreleaser() error: %v", err)
+	}
+
+	if cfg.Name != "myapp" || cfg.License != "Apache-2.0" || cfg.Homepage != "https://acme.dev/myapp" {
+		t.Errorf("Unexpected metadata: %s %s %s", cfg.Name, cfg.License, cfg.Homepage)
+	}
+	if cfg.Binaries != nil {
+		t.Error("Expected binaries to be replaced by prebuilt archives")
+	}
+
+	expectedArchives := map[string]string{
+		"darwin-amd64":  "myapp_*_Darwin_amd64.tar.gz",
+		"darwin-arm64":  "myapp_*_Darwin_arm64.tar.gz",
+		"linux-amd64":   "myapp_*_Linux_amd64.tar.gz",
+		"linux-arm64":   "myapp_*_Linux_arm64.tar.gz",
+		"windows-amd64": "myapp_*_Windows_amd64.zip",
+	}
+	if !reflect.DeepEqual(cfg.Prebuilt.Archives, expectedArchives) {
+		t.Errorf("Prebuilt archives = %v", cfg.Prebuilt.Archives)
+	}
+
+	if !cfg.GitHub.Tap.Enabled || cfg.GitHub.Tap.Repo != "acme/homebrew-tap" {
+		t.Errorf("Unexpected tap: %+v", cfg.GitHub.Tap)
+	}
+	if !cfg.GitHub.Bucket.Enabled || cfg.GitHub.Bucket.Repo != "acme/scoop-bucket" {
+		t.Errorf("Unexpected bucket: %+v", cfg.GitHub.Bucket)
+	}
+	if cfg.Packages.Deb.Maintainer != "Acme <ops@acme.dev>" || cfg.Packages.RPM.Vendor != "Acme" {
+		t.Errorf("Unexpected nfpm mapping: %+v %+v", cfg.Packages.Deb, cfg.Packages.RPM)
+	}
+	if !reflect.DeepEqual(cfg.Packages.Docker.Registries, []string{"ghcr.io/acme/myapp"}) {
+		t.Errorf("Unexpected registries: %v", cfg.Packages.Docker.Registries)
+	}
+	if cfg.GitHub.Owner != "acme" || cfg.GitHub.Repo != "myapp" {
+		t.Errorf("Unexpected release repository %s/%s", cfg.GitHub.Owner, cfg.GitHub.Repo)
+	}
+
+	joined := strings.Join(notes, "\n")
+	for _, expected := range []string{"format apk", "announce"} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("Expected a note about %s, got:\n%s", expected, joined)
+		}
+	}
+}
+
+func TestArchiveGlobDefaults(t *testing.T) {
+	gr := &goreleaserConfig{}
+
+	tests := map[string]string{
+		"linux-amd64": "myapp_*_linux_amd64.tar.gz",
+		"linux-arm":   "myapp_*_linux_armv6.tar.gz",
+	}
+	for platform, expected := range tests {
+		glob, err := archiveGlob(gr, "myapp", "myapp", platform)
+		if err != nil {
+			t.Errorf("%s: %v", platform, err)
+		}
+		if glob != expected {
+			t.Errorf("%s: glob = %q, expected %q", platform, glob, expected)
+		}
+	}
+}
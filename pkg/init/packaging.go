@@ -0,0 +1,415 @@
+package init
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of existing packaging config ImportPackaging reads
+const (
+	PackagingDebian    = "debian"
+	PackagingSnapcraft = "snapcraft"
+	PackagingFormula   = "formula"
+)
+
+// PackagingFile is a packaging config already in the repository
+type PackagingFile struct {
+	Path string
+	Kind string
+}
+
+// snapcraftFiles are where snapcraft looks for its config, in order
+var snapcraftFiles = []string{"snap/snapcraft.yaml", "snapcraft.yaml", ".snapcraft.yaml", "build-aux/snap/snapcraft.yaml"}
+
+// FindPackagingFiles returns the debian/control, snapcraft.yaml and
+// handcrafted Homebrew formula for name in the current directory
+func FindPackagingFiles(name string) []PackagingFile {
+	var files []PackagingFile
+	if _, err := os.Stat(filepath.Join("debian", "control")); err == nil {
+		files = append(files, PackagingFile{Path: filepath.Join("debian", "control"), Kind: PackagingDebian})
+	}
+	for _, path := range snapcraftFiles {
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, PackagingFile{Path: path, Kind: PackagingSnapcraft})
+			break
+		}
+	}
+	if path := findFormula(name); path != "" {
+		files = append(files, PackagingFile{Path: path, Kind: PackagingFormula})
+	}
+	return files
+}
+
+// findFormula looks for <name>.rb in Formula/, HomebrewFormula/ and the
+// root, falling back to the only formula in either directory
+func findFormula(name string) string {
+	for _, dir := range []string{"Formula", "HomebrewFormula", "."} {
+		path := filepath.Join(dir, name+".rb")
+		if data, err := os.ReadFile(path); err == nil && strings.Contains(string(data), "< Formula") {
+			return path
+		}
+	}
+	for _, dir := range []string{"Formula", "HomebrewFormula"} {
+		if matches, _ := filepath.Glob(filepath.Join(dir, "*.rb")); len(matches) == 1 {
+			return matches[0]
+		}
+	}
+	return ""
+}
+
+// ImportPackaging applies the maintainer, dependencies, description and
+// categories of an existing packaging config to cfg. Detected homepage
+// and description are kept, as in FromGoreleaser. The returned notes
+// describe what could not be imported.
+func ImportPackaging(file PackagingFile, cfg *config.Config) ([]string, error) {
+	data, err := os.ReadFile(file.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch file.Kind {
+	case PackagingDebian:
+		return importDebianControl(string(data), cfg), nil
+	case PackagingSnapcraft:
+		return importSnapcraft(data, cfg)
+	case PackagingFormula:
+		return importFormula(string(data), cfg), nil
+	}
+	return nil, fmt.Errorf("unknown packaging config kind %s", file.Kind)
+}
+
+// controlStanzas splits a debian/control file into its paragraphs.
+// Continuation lines are joined to their field with newlines, and the
+// " ." lines of extended descriptions become empty lines.
+func controlStanzas(control string) []map[string]string {
+	var stanzas []map[string]string
+	stanza := make(map[string]string)
+	field := ""
+	scanner := bufio.NewScanner(strings.NewReader(control))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "#"):
+		case strings.TrimSpace(line) == "":
+			if len(stanza) > 0 {
+				stanzas = append(stanzas, stanza)
+				stanza = make(map[string]string)
+			}
+			field = ""
+		case line[0] == ' ' || line[0] == '\t':
+			if field == "" {
+				continue
+			}
+			value := strings.TrimSpace(line)
+			if value == "." {
+				value = ""
+			}
+			stanza[field] += "\n" + value
+		default:
+			name, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			field = strings.TrimSpace(name)
+			stanza[field] = strings.TrimSpace(value)
+		}
+	}
+	if len(stanza) > 0 {
+		stanzas = append(stanzas, stanza)
+	}
+	return stanzas
+}
+
+func importDebianControl(control string, cfg *config.Config) []string {
+	stanzas := controlStanzas(control)
+	if len(stanzas) == 0 {
+		return []string{"debian/control: no stanzas found"}
+	}
+	source := stanzas[0]
+
+	// The binary package named like the project, else the first one
+	var binary map[string]string
+	for _, stanza := range stanzas[1:] {
+		if binary == nil || stanza["Package"] == cfg.Name {
+			binary = stanza
+		}
+	}
+	if binary == nil {
+		binary = make(map[string]string)
+	}
+
+	var notes []string
+	if maintainer := source["Maintainer"]; maintainer != "" {
+		cfg.Packages.Deb.Maintainer = maintainer
+		addMaintainer(cfg, maintainer)
+	}
+	for _, uploader := range splitList(source["Uploaders"]) {
+		addMaintainer(cfg, uploader)
+	}
+
+	if section := firstNonEmpty(binary["Section"], source["Section"]); section != "" {
+		cfg.Packages.Deb.Section = section
+	}
+	if priority := firstNonEmpty(binary["Priority"], source["Priority"]); priority != "" {
+		cfg.Packages.Deb.Priority = priority
+	}
+
+	fillMetadata(cfg, source["Homepage"], binary["Description"], "")
+
+	for _, dep := range append(splitList(binary["Pre-Depends"]), splitList(binary["Depends"])...) {
+		if strings.HasPrefix(dep, "${") {
+			continue
+		}
+		addDependency(cfg, "apt", dep)
+	}
+	for _, field := range []string{"Recommends", "Suggests", "Conflicts", "Provides"} {
+		if binary[field] != "" {
+			notes = append(notes, fmt.Sprintf("debian/control: %s has no bagboy equivalent; skipped", field))
+		}
+	}
+	if len(stanzas) > 2 {
+		notes = append(notes, fmt.Sprintf("debian/control: only the %s package was imported", binary["Package"]))
+	}
+	return notes
+}
+
+type snapcraftConfig struct {
+	Name        string `yaml:"name"`
+	Title       string `yaml:"title"`
+	Summary     string `yaml:"summary"`
+	Description string `yaml:"description"`
+	License     string `yaml:"license"`
+	Confinement string `yaml:"confinement"`
+	Apps        map[string]struct {
+		Plugs []string `yaml:"plugs"`
+	} `yaml:"apps"`
+	Parts map[string]struct {
+		StagePackages []string `yaml:"stage-packages"`
+	} `yaml:"parts"`
+}
+
+func importSnapcraft(data []byte, cfg *config.Config) ([]string, error) {
+	var snap snapcraftConfig
+	if err := yaml.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse snapcraft.yaml: %w", err)
+	}
+
+	var notes []string
+	description := strings.TrimSpace(snap.Summary)
+	if long := strings.TrimSpace(snap.Description); long != "" && long != description {
+		description += "\n" + long
+	}
+	fillMetadata(cfg, "", description, snap.License)
+
+	// Stage packages are the Ubuntu packages the snap needs at run time
+	parts := make([]string, 0, len(snap.Parts))
+	for name := range snap.Parts {
+		parts = append(parts, name)
+	}
+	sort.Strings(parts)
+	for _, name := range parts {
+		for _, pkg := range snap.Parts[name].StagePackages {
+			addDependency(cfg, "apt", pkg)
+		}
+	}
+
+	// A desktop file in snap/gui makes this a GUI application
+	if desktops, _ := filepath.Glob(filepath.Join("snap", "gui", "*.desktop")); len(desktops) > 0 {
+		entry, err := os.ReadFile(desktops[0])
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Desktop == nil {
+			cfg.Desktop = &config.DesktopConfig{}
+		}
+		fields := desktopFields(string(entry))
+		if cfg.Desktop.Name == "" {
+			cfg.Desktop.Name = firstNonEmpty(snap.Title, fields["Name"])
+		}
+		cfg.Desktop.Terminal = fields["Terminal"] == "true"
+		for _, category := range strings.Split(fields["Categories"], ";") {
+			if category = strings.TrimSpace(category); category != "" && !contains(cfg.Desktop.Categories, category) {
+				cfg.Desktop.Categories = append(cfg.Desktop.Categories, category)
+			}
+		}
+	}
+
+	if snap.Confinement != "" && snap.Confinement != "strict" {
+		notes = append(notes, fmt.Sprintf("snapcraft.yaml: confinement %s is not supported; snaps are built strict", snap.Confinement))
+	}
+	for _, app := range snap.Apps {
+		if len(app.Plugs) > 0 {
+			notes = append(notes, "snapcraft.yaml: apps plugs have no bagboy equivalent; skipped")
+			break
+		}
+	}
+	return notes, nil
+}
+
+// desktopFields returns the keys of a desktop file's [Desktop Entry]
+// group, without localised variants
+func desktopFields(entry string) map[string]string {
+	fields := make(map[string]string)
+	inEntry := false
+	for _, line := range strings.Split(entry, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inEntry = line == "[Desktop Entry]"
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if inEntry && ok && !strings.Contains(key, "[") {
+			fields[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return fields
+}
+
+var (
+	formulaString    = regexp.MustCompile(`(?m)^\s*(desc|homepage|license|keg_only)\s+"((?:[^"\\]|\\.)*)"`)
+	formulaDependsOn = regexp.MustCompile(`(?m)^\s*depends_on\s+"([^"]+)"(.*)$`)
+	formulaConflicts = regexp.MustCompile(`(?m)^\s*conflicts_with\s+"([^"]+)"(?:,\s*because:\s*"([^"]*)")?`)
+	formulaCaveats   = regexp.MustCompile(`(?s)def caveats\s*\n\s*<<~(\w+)\n(.*?)\n\s*(\w+)\s*\n`)
+)
+
+func importFormula(formula string, cfg *config.Config) []string {
+	values := make(map[string]string)
+	for _, match := range formulaString.FindAllStringSubmatch(formula, -1) {
+		if _, ok := values[match[1]]; !ok {
+			values[match[1]] = strings.ReplaceAll(match[2], `\"`, `"`)
+		}
+	}
+	fillMetadata(cfg, values["homepage"], values["desc"], values["license"])
+	if cfg.Packages.Brew.KegOnly == "" {
+		cfg.Packages.Brew.KegOnly = values["keg_only"]
+	}
+
+	var notes []string
+	for _, match := range formulaDependsOn.FindAllStringSubmatch(formula, -1) {
+		// Build and test dependencies are not needed by a prebuilt binary
+		if strings.Contains(match[2], ":build") || strings.Contains(match[2], ":test") {
+			continue
+		}
+		addDependency(cfg, "homebrew", match[1])
+	}
+	for _, match := range formulaConflicts.FindAllStringSubmatch(formula, -1) {
+		cfg.Packages.Brew.ConflictsWith = append(cfg.Packages.Brew.ConflictsWith, config.BrewConflict{Name: match[1], Because: match[2]})
+	}
+	if match := formulaCaveats.FindStringSubmatch(formula); match != nil && match[1] == match[3] && cfg.Packages.Brew.Caveats == "" {
+		cfg.Packages.Brew.Caveats = dedent(match[2])
+	}
+	if test := rubyBlock(formula, "test do"); test != "" && cfg.Packages.Brew.Test == "" {
+		cfg.Packages.Brew.Test = test
+	}
+	if strings.Contains(formula, "def install") {
+		notes = append(notes, "formula: the install method is replaced by bagboy's; check that it installs everything it did")
+	}
+	return notes
+}
+
+// rubyBlock returns the body of the block opened by the line starting
+// with opener, up to the end at the same indentation
+func rubyBlock(source, opener string) string {
+	lines := strings.Split(source, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != opener {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		for j := i + 1; j < len(lines); j++ {
+			if lines[j] == indent+"end" {
+				return dedent(strings.Join(lines[i+1:j], "\n"))
+			}
+		}
+	}
+	return ""
+}
+
+// dedent removes the indentation common to all non-empty lines
+func dedent(s string) string {
+	lines := strings.Split(s, "\n")
+	common := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if common < 0 || indent < common {
+			common = indent
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= common && common > 0 {
+			lines[i] = line[common:]
+		} else {
+			lines[i] = strings.TrimSpace(line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// addMaintainer credits person, "Name <email>", as a maintainer. A single
+// author string is moved to authors first so it keeps its credit.
+func addMaintainer(cfg *config.Config, person string) {
+	maintainer := config.ParseAuthor(person)
+	maintainer.Role = config.RoleMaintainer
+	if maintainer.Name == "" {
+		return
+	}
+	if len(cfg.Authors) == 0 && cfg.Author != "" {
+		cfg.Authors = []config.AuthorConfig{config.ParseAuthor(cfg.Author)}
+		cfg.Author = ""
+	}
+	for i, existing := range cfg.Authors {
+		if existing.Name == maintainer.Name || (existing.Email != "" && existing.Email == maintainer.Email) {
+			if cfg.Authors[i].Email == "" {
+				cfg.Authors[i].Email = maintainer.Email
+			}
+			cfg.Authors[i].Role = config.RoleMaintainer
+			return
+		}
+	}
+	cfg.Authors = append(cfg.Authors, maintainer)
+}
+
+// addDependency adds a package to dependencies.package_managers once
+func addDependency(cfg *config.Config, manager, pkg string) {
+	pkg = strings.TrimSpace(pkg)
+	if pkg == "" {
+		return
+	}
+	if cfg.Dependencies.PackageManagers == nil {
+		cfg.Dependencies.PackageManagers = make(map[string][]string)
+	}
+	if !contains(cfg.Dependencies.PackageManagers[manager], pkg) {
+		cfg.Dependencies.PackageManagers[manager] = append(cfg.Dependencies.PackageManagers[manager], pkg)
+	}
+}
+
+// splitList splits a comma-separated control field
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.Join(strings.Fields(item), " "); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package init
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+const testControl = `Source: myapp
+Section: devel
+Priority: optional
+Maintainer: Jane Packager <jane@example.com>
+Uploaders: Joe Helper <joe@example.com>
+Build-Depends: debhelper-compat (= 13), golang-go
+Homepage: https://myapp.dev
+
+Package: myapp
+Architecture: any
+Depends: ${shlibs:Depends}, ${misc:Depends}, git (>= 2.30),
+ ca-certificates
+Recommends: less
+Description: Sync files between machines
+ myapp keeps directories in sync over SSH.
+ .
+ It needs no server.
+
+Package: myapp-doc
+Architecture: all
+Description: Documentation for myapp
+`
+
+const testFormula = `class Myapp < Formula
+  desc "Sync files between machines"
+  homepage "https://myapp.dev"
+  url "https://github.com/acme/myapp/archive/v1.0.0.tar.gz"
+  license "Apache-2.0"
+
+  depends_on "go" => :build
+  depends_on "git"
+
+  conflicts_with "otherapp", because: "both install a myapp binary"
+
+  def install
+    system "go", "build", *std_go_args
+  end
+
+  def caveats
+    <<~EOS
+      Run myapp init to get started.
+    EOS
+  end
+
+  test do
+    assert_match version.to_s, shell_output("#{bin}/myapp --version")
+  end
+end
+`
+
+const testSnapcraft = `name: myapp
+title: MyApp
+summary: Sync files between machines
+description: |
+  myapp keeps directories in sync over SSH.
+license: GPL-3.0
+confinement: classic
+apps:
+  myapp:
+    command: bin/myapp
+    plugs: [network, home]
+parts:
+  myapp:
+    plugin: go
+    stage-packages: [openssh-client, git]
+`
+
+func writeFiles(t *testing.T, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func chdirTemp(t *testing.T) {
+	t.Helper()
+	oldWd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(oldWd) })
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindPackagingFiles(t *testing.T) {
+	chdirTemp(t)
+	writeFiles(t, map[string]string{
+		"debian/control":      testControl,
+		"snap/snapcraft.yaml": testSnapcraft,
+		"Formula/myapp.rb":    testFormula,
+	})
+
+	files := FindPackagingFiles("myapp")
+	expected := []PackagingFile{
+		{Path: filepath.Join("debian", "control"), Kind: PackagingDebian},
+		{Path: "snap/snapcraft.yaml", Kind: PackagingSnapcraft},
+		{Path: filepath.Join("Formula", "myapp.rb"), Kind: PackagingFormula},
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("FindPackagingFiles() = %+v, expected %+v", files, expected)
+	}
+}
+
+func TestImportPackaging_DebianControl(t *testing.T) {
+	chdirTemp(t)
+	writeFiles(t, map[string]string{"debian/control": testControl})
+	cfg := &config.Config{Name: "myapp", Author: "Jane Packager", License: "MIT"}
+
+	notes, err := ImportPackaging(PackagingFile{Path: "debian/control", Kind: PackagingDebian}, cfg)
+	if err != nil {
+		t.Fatalf("ImportPackaging failed: %v", err)
+	}
+
+	if cfg.Packages.Deb.Maintainer != "Jane Packager <jane@example.com>" || cfg.Packages.Deb.Section != "devel" || cfg.Packages.Deb.Priority != "optional" {
+		t.Errorf("Unexpected deb config %+v", cfg.Packages.Deb)
+	}
+	authors := []config.AuthorConfig{
+		{Name: "Jane Packager", Email: "jane@example.com", Role: config.RoleMaintainer},
+		{Name: "Joe Helper", Email: "joe@example.com", Role: config.RoleMaintainer},
+	}
+	if cfg.Author != "" || !reflect.DeepEqual(cfg.Authors, authors) {
+		t.Errorf("Unexpected authors %q %+v", cfg.Author, cfg.Authors)
+	}
+	if apt := cfg.Dependencies.PackageManagers["apt"]; !reflect.DeepEqual(apt, []string{"git (>= 2.30)", "ca-certificates"}) {
+		t.Errorf("apt dependencies = %v", apt)
+	}
+	if cfg.Description != "Sync files between machines\nmyapp keeps directories in sync over SSH.\n\nIt needs no server." {
+		t.Errorf("Description = %q", cfg.Description)
+	}
+	if cfg.Homepage != "https://myapp.dev" {
+		t.Errorf("Homepage = %q", cfg.Homepage)
+	}
+	if len(notes) != 2 {
+		t.Errorf("Expected notes for Recommends and the skipped package, got %v", notes)
+	}
+}
+
+func TestImportPackaging_Formula(t *testing.T) {
+	chdirTemp(t)
+	writeFiles(t, map[string]string{"myapp.rb": testFormula})
+	cfg := &config.Config{Name: "myapp", Description: "Detected description", License: "MIT"}
+
+	notes, err := ImportPackaging(PackagingFile{Path: "myapp.rb", Kind: PackagingFormula}, cfg)
+	if err != nil {
+		t.Fatalf("ImportPackaging failed: %v", err)
+	}
+
+	if cfg.Description != "Detected description" || cfg.License != "Apache-2.0" || cfg.Homepage != "https://myapp.dev" {
+		t.Errorf("Unexpected metadata %q %q %q", cfg.Description, cfg.License, cfg.Homepage)
+	}
+	if brew := cfg.Dependencies.PackageManagers["homebrew"]; !reflect.DeepEqual(brew, []string{"git"}) {
+		t.Errorf("homebrew dependencies = %v", brew)
+	}
+	if !reflect.DeepEqual(cfg.Packages.Brew.ConflictsWith, []config.BrewConflict{{Name: "otherapp", Because: "both install a myapp binary"}}) {
+		t.Errorf("ConflictsWith = %+v", cfg.Packages.Brew.ConflictsWith)
+	}
+	if cfg.Packages.Brew.Caveats != "Run myapp init to get started." {
+		t.Errorf("Caveats = %q", cfg.Packages.Brew.Caveats)
+	}
+	if cfg.Packages.Brew.Test != `assert_match version.to_s, shell_output("#{bin}/myapp --version")` {
+		t.Errorf("Test = %q", cfg.Packages.Brew.Test)
+	}
+	if len(notes) != 1 {
+		t.Errorf("Expected a note about the install method, got %v", notes)
+	}
+}
+
+func TestImportPackaging_Snapcraft(t *testing.T) {
+	chdirTemp(t)
+	writeFiles(t, map[string]string{
+		"snapcraft.yaml":         testSnapcraft,
+		"snap/gui/myapp.desktop": "[Desktop Entry]\nName=MyApp\nName[de]=MeineApp\nCategories=Network;FileTransfer;\n",
+	})
+	cfg := &config.Config{Name: "myapp"}
+
+	notes, err := ImportPackaging(PackagingFile{Path: "snapcraft.yaml", Kind: PackagingSnapcraft}, cfg)
+	if err != nil {
+		t.Fatalf("ImportPackaging failed: %v", err)
+	}
+
+	if cfg.Description != "Sync files between machines\nmyapp keeps directories in sync over SSH." || cfg.License != "GPL-3.0" {
+		t.Errorf("Unexpected metadata %q %q", cfg.Description, cfg.License)
+	}
+	if apt := cfg.Dependencies.PackageManagers["apt"]; !reflect.DeepEqual(apt, []string{"openssh-client", "git"}) {
+		t.Errorf("apt dependencies = %v", apt)
+	}
+	if cfg.Desktop == nil || cfg.Desktop.Name != "MyApp" || !reflect.DeepEqual(cfg.Desktop.Categories, []string{"Network", "FileTransfer"}) {
+		t.Errorf("Unexpected desktop config %+v", cfg.Desktop)
+	}
+	if len(notes) != 2 {
+		t.Errorf("Expected notes for classic confinement and plugs, got %v", notes)
+	}
+}
@@ -0,0 +1,169 @@
+package init
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// Workflow tools supported by init --with-make
+const (
+	WorkflowMake = "make"
+	WorkflowTask = "task"
+)
+
+// WorkflowEnv are the environment variables bagboy reads while signing and
+// publishing. Generated workflows pass them through to bagboy.
+var WorkflowEnv = []string{
+	"GITHUB_TOKEN",
+	"GPG_KEY_ID",
+	"APPLE_DEVELOPER_ID",
+	"APPLE_ID",
+	"APPLE_APP_PASSWORD",
+	"APPLE_TEAM_ID",
+	"WINDOWS_CERT_THUMBPRINT",
+	"SIGNPATH_API_TOKEN",
+}
+
+// defaultPlatforms are built for Go projects without detected binaries
+var defaultPlatforms = []string{"darwin-amd64", "darwin-arm64", "linux-amd64", "linux-arm64", "windows-amd64"}
+
+// Workflow returns the file name and content of a Makefile or Taskfile
+// with build, pack, sign and publish targets. For Go projects without
+// detected binaries, cfg.Binaries is filled with the paths the build
+// target produces.
+func Workflow(tool string, info *ProjectInfo, cfg *config.Config) (string, []byte, error) {
+	if info.Language == "go" && len(cfg.Binaries) == 0 && len(cfg.Prebuilt.Archives) == 0 {
+		cfg.Binaries = make(map[string]string)
+		for _, platform := range defaultPlatforms {
+			path := fmt.Sprintf("dist/%s-%s", cfg.Name, platform)
+			if strings.HasPrefix(platform, "windows-") {
+				path += ".exe"
+			}
+			cfg.Binaries[platform] = path
+		}
+	}
+
+	build := buildCommands(info, cfg)
+	env := workflowEnv(cfg)
+
+	switch tool {
+	case WorkflowMake:
+		return "Makefile", []byte(makefile(build, env)), nil
+	case WorkflowTask:
+		return "Taskfile.yml", []byte(taskfile(build, env)), nil
+	default:
+		return "", nil, fmt.Errorf("unknown workflow tool %q (supported: %s, %s)", tool, WorkflowMake, WorkflowTask)
+	}
+}
+
+// buildCommands returns the commands that produce the configured binaries
+func buildCommands(info *ProjectInfo, cfg *config.Config) []string {
+	if len(cfg.Prebuilt.Archives) > 0 {
+		return []string{"goreleaser release --clean --skip=publish"}
+	}
+
+	switch info.Language {
+	case "go":
+		platforms := make([]string, 0, len(cfg.Binaries))
+		for platform := range cfg.Binaries {
+			platforms = append(platforms, platform)
+		}
+		sort.Strings(platforms)
+
+		var commands []string
+		for _, platform := range platforms {
+			system, arch, _ := strings.Cut(platform, "-")
+			commands = append(commands, fmt.Sprintf("GOOS=%s GOARCH=%s go build -o %s .", system, arch, cfg.Binaries[platform]))
+		}
+		return commands
+	case "rust":
+		return []string{"cargo build --release"}
+	case "nodejs":
+		return []string{"npm run build"}
+	case "python":
+		return []string{"python -m build"}
+	default:
+		return []string{"echo \"Add the commands that build your binaries\""}
+	}
+}
+
+// workflowEnv returns WorkflowEnv plus the configured GitHub token variable
+func workflowEnv(cfg *config.Config) []string {
+	env := append([]string{}, WorkflowEnv...)
+	if cfg.GitHub.TokenEnv != "" && !contains(env, cfg.GitHub.TokenEnv) {
+		env = append(env, cfg.GitHub.TokenEnv)
+	}
+	return env
+}
+
+func makefile(build, env []string) string {
+	var b strings.Builder
+	b.WriteString("# Generated by bagboy init --with-make\n\n")
+	b.WriteString("BAGBOY ?= bagboy\n")
+	b.WriteString("PACK_FLAGS ?= --all\n")
+	b.WriteString("PUBLISH_FLAGS ?=\n\n")
+	b.WriteString("# Credentials are read from the environment or the command line,\n")
+	b.WriteString("# e.g. make publish GITHUB_TOKEN=...\n")
+	for _, name := range env {
+		fmt.Fprintf(&b, "export %s\n", name)
+	}
+
+	b.WriteString("\n.PHONY: build pack sign publish\n\n")
+	b.WriteString("build:\n")
+	for _, command := range build {
+		fmt.Fprintf(&b, "\t%s\n", command)
+	}
+	b.WriteString("\npack: build\n")
+	b.WriteString("\t$(BAGBOY) pack $(PACK_FLAGS)\n")
+	b.WriteString("\nsign: build\n")
+	b.WriteString("\t$(BAGBOY) sign --check\n")
+	b.WriteString("\t$(BAGBOY) pack $(PACK_FLAGS) --sign\n")
+	b.WriteString("\npublish: build\n")
+	b.WriteString("\t$(BAGBOY) publish $(PUBLISH_FLAGS)\n")
+	return b.String()
+}
+
+func taskfile(build, env []string) string {
+	var b strings.Builder
+	b.WriteString("# Generated by bagboy init --with-make=task\n")
+	b.WriteString("version: '3'\n\n")
+	b.WriteString("vars:\n")
+	b.WriteString("  BAGBOY: '{{.BAGBOY | default \"bagboy\"}}'\n")
+	b.WriteString("  PACK_FLAGS: '{{.PACK_FLAGS | default \"--all\"}}'\n")
+	b.WriteString("  PUBLISH_FLAGS: '{{.PUBLISH_FLAGS | default \"\"}}'\n\n")
+	b.WriteString("# Credentials are read from the environment or the command line,\n")
+	b.WriteString("# e.g. task publish GITHUB_TOKEN=...\n")
+	b.WriteString("env:\n")
+	for _, name := range env {
+		fmt.Fprintf(&b, "  %s: '{{.%s}}'\n", name, name)
+	}
+
+	b.WriteString("\ntasks:\n")
+	b.WriteString("  build:\n")
+	b.WriteString("    cmds:\n")
+	for _, command := range build {
+		fmt.Fprintf(&b, "      - %s\n", yamlQuote(command))
+	}
+	b.WriteString("\n  pack:\n")
+	b.WriteString("    deps: [build]\n")
+	b.WriteString("    cmds:\n")
+	b.WriteString("      - '{{.BAGBOY}} pack {{.PACK_FLAGS}}'\n")
+	b.WriteString("\n  sign:\n")
+	b.WriteString("    deps: [build]\n")
+	b.WriteString("    cmds:\n")
+	b.WriteString("      - '{{.BAGBOY}} sign --check'\n")
+	b.WriteString("      - '{{.BAGBOY}} pack {{.PACK_FLAGS}} --sign'\n")
+	b.WriteString("\n  publish:\n")
+	b.WriteString("    deps: [build]\n")
+	b.WriteString("    cmds:\n")
+	b.WriteString("      - '{{.BAGBOY}} publish {{.PUBLISH_FLAGS}}'\n")
+	return b.String()
+}
+
+// yamlQuote single-quotes s for use as a YAML scalar
+func yamlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package init
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWorkflowMakefile(t *testing.T) {
+	info := &ProjectInfo{Language: "go"}
+	cfg := &config.Config{Name: "myapp", GitHub: config.GitHubConfig{TokenEnv: "RELEASE_TOKEN"}}
+
+	name, data, err := Workflow(WorkflowMake, info, cfg)
+	if err != nil {
+		t.Fatalf("Workflow() error: %v", err)
+	}
+	if name != "Makefile" {
+		t.Errorf("name = %s", name)
+	}
+	if cfg.Binaries["windows-amd64"] != "dist/myapp-windows-amd64.exe" {
+		t.Errorf("Expected default binaries to be configured, got %v", cfg.Binaries)
+	}
+
+	makefile := string(data)
+	for _, expected := range []string{
+		"\tGOOS=windows GOARCH=amd64 go build -o dist/myapp-windows-amd64.exe .\n",
+		"export GITHUB_TOKEN\n",
+		"export RELEASE_TOKEN\n",
+		"pack: build\n\t$(BAGBOY) pack $(PACK_FLAGS)\n",
+		"\t$(BAGBOY) pack $(PACK_FLAGS) --sign\n",
+		"\t$(BAGBOY) publish $(PUBLISH_FLAGS)\n",
+	} {
+		if !strings.Contains(makefile, expected) {
+			t.Errorf("Makefile missing %q:\n%s", expected, makefile)
+		}
+	}
+}
+
+func TestWorkflowTaskfile(t *testing.T) {
+	info := &ProjectInfo{Language: "rust"}
+	cfg := &config.Config{Name: "myapp", Binaries: map[string]string{"linux-amd64": "target/release/myapp"}}
+
+	name, data, err := Workflow(WorkflowTask, info, cfg)
+	if err != nil {
+		t.Fatalf("Workflow() error: %v", err)
+	}
+	if name != "Taskfile.yml" {
+		t.Errorf("name = %s", name)
+	}
+
+	var taskfile struct {
+		Env   map[string]string `yaml:"env"`
+		Tasks map[string]struct {
+			Deps []string `yaml:"deps"`
+			Cmds []string `yaml:"cmds"`
+		} `yaml:"tasks"`
+	}
+	if err := yaml.Unmarshal(data, &taskfile); err != nil {
+		t.Fatalf("Invalid Taskfile: %v\n%s", err, data)
+	}
+	if taskfile.Env["GPG_KEY_ID"] != "{{.GPG_KEY_ID}}" {
+		t.Errorf("Env = %v", taskfile.Env)
+	}
+	if cmds := taskfile.Tasks["build"].Cmds; len(cmds) != 1 || cmds[0] != "cargo build --release" {
+		t.Errorf("build cmds = %v", cmds)
+	}
+	for _, task := range []string{"pack", "sign", "publish"} {
+		if deps := taskfile.Tasks[task].Deps; len(deps) != 1 || deps[0] != "build" {
+			t.Errorf("%s deps = %v", task, deps)
+		}
+	}
+
+	if _, _, err := Workflow("bazel", info, cfg); err == nil {
+		t.Error("Expected error for unknown workflow tool")
+	}
+}
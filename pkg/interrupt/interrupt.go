@@ -0,0 +1,154 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package interrupt turns Ctrl-C into context cancellation so external
+// tools are stopped cleanly, and removes the files a cancelled command
+// left half-written.
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ExitCode is the conventional status for a process stopped by SIGINT
+const ExitCode = 130
+
+// Context returns a context that is cancelled on the first interrupt or
+// SIGTERM. A second interrupt exits immediately with ExitCode.
+func Context(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	stopped := make(chan struct{})
+
+	go func() {
+		select {
+		case <-signals:
+		case <-stopped:
+			return
+		}
+		fmt.Fprintln(os.Stderr, "\n⚠️  Interrupted - stopping (press Ctrl-C again to quit immediately)")
+		cancel()
+		select {
+		case <-signals:
+			os.Exit(ExitCode)
+		case <-stopped:
+		}
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(stopped)
+			cancel()
+		})
+	}
+}
+
+// Guard remembers what was in a directory before a command ran so an
+// interrupted command can remove the files it left half-written
+type Guard struct {
+	dir    string
+	before map[string]time.Time
+	mu     sync.Mutex
+	keep   []string
+}
+
+// Watch records the current contents of dir, which need not exist yet
+func Watch(dir string) *Guard {
+	g := &Guard{dir: dir, before: make(map[string]time.Time)}
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			g.before[path] = info.ModTime()
+		}
+		return nil
+	})
+	return g
+}
+
+// Keep protects paths, and everything under them, from Cleanup: finished
+// packages and state that lets the next run resume
+func (g *Guard) Keep(paths ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, path := range paths {
+		g.keep = append(g.keep, filepath.Clean(path))
+	}
+}
+
+func (g *Guard) kept(path string) bool {
+	for _, keep := range g.keep {
+		if path == keep || strings.HasPrefix(path, keep+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Cleanup removes the files created or changed since Watch that are not
+// kept, then any directories created since Watch that are left empty. It
+// returns the files removed.
+func (g *Guard) Cleanup() ([]string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var removed, newDirs []string
+	var errs []error
+	filepath.WalkDir(g.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || g.kept(path) {
+			return nil
+		}
+		before, existed := g.before[path]
+		if d.IsDir() {
+			if !existed {
+				newDirs = append(newDirs, path)
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || (existed && info.ModTime().Equal(before)) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		removed = append(removed, path)
+		return nil
+	})
+
+	// Deepest first so parents empty out before they are tried
+	sort.Sort(sort.Reverse(sort.StringSlice(newDirs)))
+	for _, dir := range newDirs {
+		os.Remove(dir)
+	}
+	return removed, errors.Join(errs...)
+}
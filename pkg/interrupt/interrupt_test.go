@@ -0,0 +1,90 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interrupt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGuardCleanup(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string) string {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	old := write("old.deb")
+	overwritten := write("app.rb")
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(overwritten, past, past)
+
+	guard := Watch(dir)
+
+	partial := write("app.rpm")
+	nested := write("msix/AppxManifest.xml")
+	write("app.rb")
+	finished := write("app.nupkg")
+	state := write(".uploads/abc.json")
+	guard.Keep(finished, filepath.Dir(state))
+
+	removed, err := guard.Cleanup()
+	if err != nil {
+		t.Fatalf("Cleanup failed: %v", err)
+	}
+	if len(removed) != 3 {
+		t.Errorf("Expected 3 files removed, got %v", removed)
+	}
+
+	for _, path := range []string{partial, nested, overwritten, filepath.Join(dir, "msix")} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed", path)
+		}
+	}
+	for _, path := range []string{old, finished, state} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to be kept: %v", path, err)
+		}
+	}
+}
+
+func TestWatchMissingDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "dist")
+	guard := Watch(dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "brew"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "brew", "app.rb"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := guard.Cleanup(); err != nil {
+		t.Fatalf("Cleanup failed: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("Expected the directory created after Watch to be removed")
+	}
+}
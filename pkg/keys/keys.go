@@ -0,0 +1,349 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package keys manages the GPG key used to sign releases: generating it,
+// exporting the public key for users and APT/YUM repositories, uploading
+// it to keyservers, and rotating it with an overlap period during which
+// the old key stays published.
+package keys
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/audit"
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile records the current and retired keys in the key directory
+const ManifestFile = "keys.yaml"
+
+// PassphraseEnv holds the passphrase for generated keys. Without it keys
+// are generated unprotected, as CI signing usually requires.
+const PassphraseEnv = "BAGBOY_GPG_PASSPHRASE"
+
+// DefaultKeyservers receive the public key on publish
+var DefaultKeyservers = []string{"hkps://keys.openpgp.org", "hkps://keyserver.ubuntu.com"}
+
+// Manifest is the key history kept alongside the exported public keys
+type Manifest struct {
+	Current string       `yaml:"current,omitempty"`
+	Retired []RetiredKey `yaml:"retired,omitempty"`
+}
+
+// RetiredKey is a rotated key that stays published until Until
+type RetiredKey struct {
+	Fingerprint string    `yaml:"fingerprint"`
+	Until       time.Time `yaml:"until"`
+}
+
+// Published returns the keys to export and publish at now: the current
+// key and retired keys still in their overlap period
+func (m *Manifest) Published(now time.Time) []string {
+	var fingerprints []string
+	if m.Current != "" {
+		fingerprints = append(fingerprints, m.Current)
+	}
+	for _, key := range m.Retired {
+		if now.Before(key.Until) {
+			fingerprints = append(fingerprints, key.Fingerprint)
+		}
+	}
+	return fingerprints
+}
+
+// Manager runs gpg for the key configuration of a project
+type Manager struct {
+	cfg *config.Config
+	now func() time.Time
+	run func(ctx context.Context, stdin []byte, args ...string) ([]byte, error)
+}
+
+// NewManager creates a key manager
+func NewManager(cfg *config.Config) *Manager {
+	return &Manager{cfg: cfg, now: time.Now, run: runGPG}
+}
+
+func runGPG(ctx context.Context, stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "gpg", args...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("gpg failed: %w\nOutput: %s", err, stderr.String())
+	}
+	return output, nil
+}
+
+// Dir returns the directory public keys are exported to
+func (m *Manager) Dir() string {
+	if m.cfg.Signing.Keys.Dir != "" {
+		return m.cfg.Signing.Keys.Dir
+	}
+	return "keys"
+}
+
+// UserID returns the user ID of generated keys
+func (m *Manager) UserID() (string, error) {
+	name, email, err := m.identity()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s <%s>", name, email), nil
+}
+
+func (m *Manager) identity() (string, string, error) {
+	keys := m.cfg.Signing.Keys
+	name := keys.Name
+	if name == "" {
+		name = m.cfg.Name + " release signing key"
+	}
+	email := keys.Email
+	if email == "" {
+		for _, person := range m.cfg.Maintainers() {
+			if person.Email != "" {
+				email = person.Email
+				break
+			}
+		}
+	}
+	if email == "" {
+		return "", "", fmt.Errorf("signing.keys.email is required (no maintainer email configured)")
+	}
+	return name, email, nil
+}
+
+// Overlap returns how long a rotated key stays published
+func (m *Manager) Overlap() (time.Duration, error) {
+	if m.cfg.Signing.Keys.Overlap == "" {
+		return 90 * 24 * time.Hour, nil
+	}
+	return ParseDuration(m.cfg.Signing.Keys.Overlap)
+}
+
+// Keyservers returns the keyservers the public key is uploaded to
+func (m *Manager) Keyservers() []string {
+	if len(m.cfg.Signing.Keys.Keyservers) > 0 {
+		return m.cfg.Signing.Keys.Keyservers
+	}
+	return DefaultKeyservers
+}
+
+// LoadManifest reads the key manifest. A missing manifest is empty.
+func (m *Manager) LoadManifest() (*Manifest, error) {
+	manifest := &Manifest{}
+	data, err := os.ReadFile(filepath.Join(m.Dir(), ManifestFile))
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ManifestFile, err)
+	}
+	return manifest, nil
+}
+
+// SaveManifest writes the key manifest, dropping retired keys whose
+// overlap period has ended
+func (m *Manager) SaveManifest(manifest *Manifest) error {
+	now := m.now()
+	var retired []RetiredKey
+	for _, key := range manifest.Retired {
+		if now.Before(key.Until) {
+			retired = append(retired, key)
+		}
+	}
+	manifest.Retired = retired
+
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.Dir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(m.Dir(), ManifestFile), data, 0644)
+}
+
+// Generate creates a new signing key and returns its fingerprint
+func (m *Manager) Generate(ctx context.Context) (string, error) {
+	name, email, err := m.identity()
+	if err != nil {
+		return "", err
+	}
+
+	expire := m.cfg.Signing.Keys.Expire
+	if expire == "" {
+		expire = "2y"
+	}
+
+	// RSA keeps the key usable by older rpm versions, which lack EdDSA
+	params := []string{
+		"Key-Type: RSA",
+		"Key-Length: 4096",
+		"Key-Usage: sign",
+		"Name-Real: " + name,
+		"Name-Email: " + email,
+		"Expire-Date: " + expire,
+	}
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		params = append(params, "Passphrase: "+passphrase)
+	} else {
+		params = append(params, "%no-protection")
+	}
+	params = append(params, "%commit", "")
+
+	output, err := m.run(ctx, []byte(strings.Join(params, "\n")), "--batch", "--status-fd", "1", "--gen-key")
+	if err != nil {
+		return "", err
+	}
+	return parseKeyCreated(output)
+}
+
+// parseKeyCreated finds the fingerprint in gpg's KEY_CREATED status line
+func parseKeyCreated(status []byte) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(status))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 4 && fields[0] == "[GNUPG:]" && fields[1] == "KEY_CREATED" {
+			return fields[3], nil
+		}
+	}
+	return "", fmt.Errorf("gpg did not report the created key")
+}
+
+// Export writes the published keys as an armored key for users, an RPM
+// GPG key for YUM repositories and a binary keyring for APT signed-by.
+// It returns the written files.
+func (m *Manager) Export(ctx context.Context, manifest *Manifest) ([]string, error) {
+	fingerprints := manifest.Published(m.now())
+	if len(fingerprints) == 0 {
+		return nil, fmt.Errorf("no release key - run 'bagboy keys generate' first")
+	}
+
+	armored, err := m.run(ctx, nil, append([]string{"--armor", "--export"}, fingerprints...)...)
+	if err != nil {
+		return nil, err
+	}
+	keyring, err := m.run(ctx, nil, append([]string{"--export"}, fingerprints...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(m.Dir(), 0755); err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{m.cfg.Name + ".asc", armored},
+		{"RPM-GPG-KEY-" + m.cfg.Name, armored},
+		{m.cfg.Name + "-archive-keyring.gpg", keyring},
+	}
+	var written []string
+	for _, file := range files {
+		path := filepath.Join(m.Dir(), file.name)
+		if err := os.WriteFile(path, file.data, 0644); err != nil {
+			return nil, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// Publish uploads the published keys to every keyserver
+func (m *Manager) Publish(ctx context.Context, manifest *Manifest) error {
+	fingerprints := manifest.Published(m.now())
+	if len(fingerprints) == 0 {
+		return fmt.Errorf("no release key - run 'bagboy keys generate' first")
+	}
+
+	var failed []string
+	for _, server := range m.Keyservers() {
+		args := append([]string{"--keyserver", server, "--send-keys"}, fingerprints...)
+		_, err := m.run(ctx, nil, args...)
+		audit.Result(ctx, audit.KeyPublish, server, strings.Join(fingerprints, ","), "", err)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", server, err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("upload failed:\n%s", strings.Join(failed, "\n"))
+	}
+	return nil
+}
+
+// Rotate generates a new key, certifies it with the current key so users
+// who trust the old key can trust the new one, and retires the current key
+// for the overlap period. It returns the new fingerprint.
+func (m *Manager) Rotate(ctx context.Context, manifest *Manifest) (string, error) {
+	overlap, err := m.Overlap()
+	if err != nil {
+		return "", err
+	}
+
+	fingerprint, err := m.Generate(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if manifest.Current != "" {
+		if _, err := m.run(ctx, nil, "--batch", "--yes", "--default-key", manifest.Current, "--quick-sign-key", fingerprint); err != nil {
+			return "", fmt.Errorf("certifying the new key with the current key: %w", err)
+		}
+		manifest.Retired = append(manifest.Retired, RetiredKey{
+			Fingerprint: manifest.Current,
+			Until:       m.now().Add(overlap).UTC().Truncate(time.Second),
+		})
+	}
+	manifest.Current = fingerprint
+	return fingerprint, nil
+}
+
+// ParseDuration parses a duration in days (90d), weeks (12w) or any unit
+// time.ParseDuration accepts
+func ParseDuration(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(s, suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(s, suffix))
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 90d, 12w or 720h)", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keys
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// fakeGPG records gpg invocations and hands out fingerprints on --gen-key
+type fakeGPG struct {
+	calls        [][]string
+	stdin        []string
+	fingerprints []string
+}
+
+func (f *fakeGPG) run(ctx context.Context, stdin []byte, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, args)
+	f.stdin = append(f.stdin, string(stdin))
+	if args[len(args)-1] == "--gen-key" {
+		fingerprint := f.fingerprints[0]
+		f.fingerprints = f.fingerprints[1:]
+		return []byte("[GNUPG:] KEY_CONSIDERED X 0\n[GNUPG:] KEY_CREATED P " + fingerprint + "\n"), nil
+	}
+	return []byte(strings.Join(args, " ")), nil
+}
+
+func testManager(t *testing.T, gpg *fakeGPG, now time.Time) *Manager {
+	cfg := &config.Config{
+		Name:    "myapp",
+		Authors: []config.AuthorConfig{{Name: "Jane Doe", Email: "jane@example.com", Role: config.RoleMaintainer}},
+	}
+	cfg.Signing.Keys.Dir = t.TempDir()
+	return &Manager{cfg: cfg, now: func() time.Time { return now }, run: gpg.run}
+}
+
+func TestRotate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	gpg := &fakeGPG{fingerprints: []string{"AAAA", "BBBB"}}
+	m := testManager(t, gpg, now)
+	t.Setenv(PassphraseEnv, "")
+
+	manifest := &Manifest{}
+	if _, err := m.Rotate(context.Background(), manifest); err != nil {
+		t.Fatalf("Rotate() error: %v", err)
+	}
+	if !strings.Contains(gpg.stdin[0], "Name-Email: jane@example.com") || !strings.Contains(gpg.stdin[0], "%no-protection") {
+		t.Errorf("Unexpected key parameters:\n%s", gpg.stdin[0])
+	}
+
+	fingerprint, err := m.Rotate(context.Background(), manifest)
+	if err != nil {
+		t.Fatalf("Rotate() error: %v", err)
+	}
+	if fingerprint != "BBBB" || manifest.Current != "BBBB" {
+		t.Errorf("Current = %s", manifest.Current)
+	}
+	if !reflect.DeepEqual(gpg.calls[2], []string{"--batch", "--yes", "--default-key", "AAAA", "--quick-sign-key", "BBBB"}) {
+		t.Errorf("Expected the new key to be certified by the old one, got %v", gpg.calls[2])
+	}
+
+	expected := []RetiredKey{{Fingerprint: "AAAA", Until: now.Add(90 * 24 * time.Hour)}}
+	if !reflect.DeepEqual(manifest.Retired, expected) {
+		t.Errorf("Retired = %+v", manifest.Retired)
+	}
+	if published := manifest.Published(now); !reflect.DeepEqual(published, []string{"BBBB", "AAAA"}) {
+		t.Errorf("Published during overlap = %v", published)
+	}
+	if published := manifest.Published(now.Add(91 * 24 * time.Hour)); !reflect.DeepEqual(published, []string{"BBBB"}) {
+		t.Errorf("Published after overlap = %v", published)
+	}
+}
+
+func TestExportAndManifest(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	gpg := &fakeGPG{}
+	m := testManager(t, gpg, now)
+
+	manifest := &Manifest{
+		Current: "BBBB",
+		Retired: []RetiredKey{
+			{Fingerprint: "AAAA", Until: now.Add(time.Hour)},
+			{Fingerprint: "0000", Until: now.Add(-time.Hour)},
+		},
+	}
+
+	files, err := m.Export(context.Background(), manifest)
+	if err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+	var names []string
+	for _, file := range files {
+		names = append(names, filepath.Base(file))
+	}
+	if !reflect.DeepEqual(names, []string{"myapp.asc", "RPM-GPG-KEY-myapp", "myapp-archive-keyring.gpg"}) {
+		t.Errorf("Exported %v", names)
+	}
+	if armored, _ := os.ReadFile(files[0]); string(armored) != "--armor --export BBBB AAAA" {
+		t.Errorf("Expected the current and overlapping keys, got %q", armored)
+	}
+
+	if err := m.SaveManifest(manifest); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := m.LoadManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Current != "BBBB" || len(loaded.Retired) != 1 || loaded.Retired[0].Fingerprint != "AAAA" {
+		t.Errorf("Expected expired keys to be dropped, got %+v", loaded)
+	}
+
+	if _, err := m.Export(context.Background(), &Manifest{}); err == nil {
+		t.Error("Expected error without a key")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"90d":  90 * 24 * time.Hour,
+		"2w":   14 * 24 * time.Hour,
+		"720h": 720 * time.Hour,
+	}
+	for input, expected := range tests {
+		got, err := ParseDuration(input)
+		if err != nil || got != expected {
+			t.Errorf("ParseDuration(%q) = %v, %v", input, got, err)
+		}
+	}
+	if _, err := ParseDuration("soon"); err == nil {
+		t.Error("Expected error for invalid duration")
+	}
+}
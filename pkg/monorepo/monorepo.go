@@ -0,0 +1,258 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package monorepo finds the bagboy projects under a directory and
+// publishes them in parallel, each after the projects whose versions it
+// embeds
+package monorepo
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// Project is one bagboy config found under the root
+type Project struct {
+	Dir    string // absolute directory of the config
+	Config *config.Config
+	Needs  []*Project // projects whose versions this one embeds or requires
+}
+
+// skipDirs are never searched for projects, along with hidden directories
+var skipDirs = map[string]bool{"node_modules": true, "vendor": true, "dist": true, "testdata": true}
+
+// Discover loads every bagboy config under root, in directory order, and
+// links each project to the ones its builds embed or its
+// dependencies.bagboy requires. Projects outside root are taken as already
+// released. Two projects with the same
+// name, or projects that embed each other, are an error.
+func Discover(root string) ([]*Project, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []*Project
+	byDir := make(map[string]*Project)
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(d.Name(), ".") || skipDirs[d.Name()]) {
+			return filepath.SkipDir
+		}
+		configPath, err := config.FindConfigIn(path)
+		if err != nil {
+			return nil
+		}
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return fmt.Errorf("%s: %w", relative(root, configPath), err)
+		}
+		project := &Project{Dir: path, Config: cfg}
+		projects = append(projects, project)
+		byDir[path] = project
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("no bagboy config files found under %s", root)
+	}
+
+	names := make(map[string]*Project)
+	for _, project := range projects {
+		if other, ok := names[project.Config.Name]; ok {
+			return nil, fmt.Errorf("%s and %s are both named %s", relative(root, other.Dir), relative(root, project.Dir), project.Config.Name)
+		}
+		names[project.Config.Name] = project
+
+		for _, b := range project.Config.Builds {
+			for _, embed := range b.Embeds {
+				if !filepath.IsAbs(embed) {
+					embed = filepath.Join(project.Dir, embed)
+				}
+				if need, ok := byDir[filepath.Clean(embed)]; ok {
+					project.need(need)
+				}
+			}
+		}
+	}
+
+	// A tool requiring another tool in the tree is published after it
+	for _, project := range projects {
+		for _, dep := range project.Config.Dependencies.Bagboy {
+			if need, ok := names[dep.Name]; ok && need != project {
+				project.need(need)
+			}
+		}
+	}
+
+	if cycle := findCycle(projects); cycle != nil {
+		return nil, fmt.Errorf("projects depend on each other: %s", strings.Join(cycle, " -> "))
+	}
+	return projects, nil
+}
+
+func (p *Project) need(other *Project) {
+	if !slices.Contains(p.Needs, other) {
+		p.Needs = append(p.Needs, other)
+	}
+}
+
+// Order returns projects sorted so each comes after the projects it needs,
+// otherwise keeping their order
+func Order(projects []*Project) []*Project {
+	ordered := make([]*Project, 0, len(projects))
+	seen := make(map[*Project]bool)
+	var visit func(*Project)
+	visit = func(p *Project) {
+		if seen[p] {
+			return
+		}
+		seen[p] = true
+		for _, need := range p.Needs {
+			visit(need)
+		}
+		ordered = append(ordered, p)
+	}
+	for _, p := range projects {
+		visit(p)
+	}
+	return ordered
+}
+
+// Result is the outcome of publishing one project
+type Result struct {
+	Project  *Project
+	Err      error
+	Skipped  bool // a project it needs failed, so it was not started
+	Duration time.Duration
+}
+
+// Run publishes every project with publish, at most jobs at once, starting
+// each as soon as the projects it needs have succeeded. Projects needing
+// one that failed are skipped. Results are in the order of projects, which
+// must include everything their projects need.
+func Run(ctx context.Context, projects []*Project, jobs int, publish func(context.Context, *Project) error) []Result {
+	results := make([]Result, len(projects))
+	done := make(map[*Project]chan struct{}, len(projects))
+	index := make(map[*Project]int, len(projects))
+	for i, p := range projects {
+		done[p] = make(chan struct{})
+		index[p] = i
+	}
+
+	slots := make(chan struct{}, max(jobs, 1))
+	var wg sync.WaitGroup
+	for i, p := range projects {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[p])
+			result := &results[i]
+			result.Project = p
+
+			for _, need := range p.Needs {
+				<-done[need]
+				if results[index[need]].Err != nil {
+					result.Skipped = true
+					result.Err = fmt.Errorf("%s failed", need.Config.Name)
+					return
+				}
+			}
+
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				result.Err = ctx.Err()
+				return
+			}
+			defer func() { <-slots }()
+			if err := ctx.Err(); err != nil {
+				result.Err = err
+				return
+			}
+
+			start := time.Now()
+			result.Err = publish(ctx, p)
+			result.Duration = time.Since(start)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// findCycle returns the names around a cycle of needs, or nil
+func findCycle(projects []*Project) []string {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[*Project]int)
+	var stack []*Project
+	var cycle []string
+	var visit func(*Project) bool
+	visit = func(p *Project) bool {
+		switch state[p] {
+		case visiting:
+			start := slices.Index(stack, p)
+			for _, q := range stack[start:] {
+				cycle = append(cycle, q.Config.Name)
+			}
+			cycle = append(cycle, p.Config.Name)
+			return true
+		case visited:
+			return false
+		}
+		state[p] = visiting
+		stack = append(stack, p)
+		for _, need := range p.Needs {
+			if visit(need) {
+				return true
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[p] = visited
+		return false
+	}
+	for _, p := range projects {
+		if visit(p) {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// relative returns path relative to root for messages
+func relative(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monorepo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func writeProject(t *testing.T, dir, config string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bagboy.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func names(projects []*Project) string {
+	var list []string
+	for _, p := range projects {
+		list = append(list, p.Config.Name)
+	}
+	return strings.Join(list, " ")
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	writeProject(t, filepath.Join(root, "tools", "app"), `name: app
+version: 1.0.0
+builds:
+  - embeds: [../lib, ../../outside]
+`)
+	writeProject(t, filepath.Join(root, "tools", "lib"), "name: lib\nversion: 0.3.0\n")
+	writeProject(t, filepath.Join(root, "tools", "plugin"), `name: plugin
+version: 0.1.0
+dependencies:
+  bagboy:
+    - name: cli
+      version: ">=2.0"
+`)
+	writeProject(t, filepath.Join(root, "tools", "cli"), `name: cli
+version: 2.0.0
+builds:
+  - embeds: [../app]
+`)
+	// Never searched
+	writeProject(t, filepath.Join(root, "node_modules", "dep"), "name: dep\nversion: 1.0.0\n")
+	writeProject(t, filepath.Join(root, ".cache", "old"), "name: old\nversion: 1.0.0\n")
+
+	projects, err := Discover(root)
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if got := names(projects); got != "app cli lib plugin" {
+		t.Errorf("Discover() found %q, want app cli lib plugin", got)
+	}
+	if got := names(projects[0].Needs); got != "lib" {
+		t.Errorf("app needs %q, want lib", got)
+	}
+	if got := names(projects[3].Needs); got != "cli" {
+		t.Errorf("plugin needs %q, want cli", got)
+	}
+	if got := names(Order(projects)); got != "lib app cli plugin" {
+		t.Errorf("Order() = %q, want lib app cli plugin", got)
+	}
+}
+
+func TestDiscover_Errors(t *testing.T) {
+	if _, err := Discover(t.TempDir()); err == nil {
+		t.Error("Expected an error without any projects")
+	}
+
+	root := t.TempDir()
+	writeProject(t, filepath.Join(root, "a"), "name: a\nversion: 1.0.0\nbuilds:\n  - embeds: [../b]\n")
+	writeProject(t, filepath.Join(root, "b"), "name: b\nversion: 1.0.0\nbuilds:\n  - embeds: [../a]\n")
+	if _, err := Discover(root); err == nil || !strings.Contains(err.Error(), "a -> b -> a") {
+		t.Errorf("Expected the cycle to be reported, got %v", err)
+	}
+
+	root = t.TempDir()
+	writeProject(t, filepath.Join(root, "a"), "name: same\nversion: 1.0.0\n")
+	writeProject(t, filepath.Join(root, "b"), "name: same\nversion: 1.0.0\n")
+	if _, err := Discover(root); err == nil || !strings.Contains(err.Error(), "both named same") {
+		t.Errorf("Expected duplicate names to be reported, got %v", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	root := t.TempDir()
+	writeProject(t, filepath.Join(root, "lib"), "name: lib\nversion: 1.0.0\n")
+	writeProject(t, filepath.Join(root, "app"), "name: app\nversion: 1.0.0\nbuilds:\n  - embeds: [../lib]\n")
+	writeProject(t, filepath.Join(root, "other"), "name: other\nversion: 1.0.0\n")
+	projects, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var started []string
+	results := Run(context.Background(), projects, 2, func(ctx context.Context, p *Project) error {
+		mu.Lock()
+		started = append(started, p.Config.Name)
+		mu.Unlock()
+		return nil
+	})
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("%s: %v", r.Project.Config.Name, r.Err)
+		}
+	}
+	lib, app := -1, -1
+	for i, name := range started {
+		switch name {
+		case "lib":
+			lib = i
+		case "app":
+			app = i
+		}
+	}
+	if lib < 0 || app < lib {
+		t.Errorf("Started %v, want lib before app", started)
+	}
+
+	// A failure skips what needs it but not the rest
+	results = Run(context.Background(), projects, 2, func(ctx context.Context, p *Project) error {
+		if p.Config.Name == "lib" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	for _, r := range results {
+		switch r.Project.Config.Name {
+		case "lib":
+			if r.Err == nil || r.Skipped {
+				t.Errorf("lib: want failed, got %+v", r)
+			}
+		case "app":
+			if !r.Skipped {
+				t.Errorf("app: want skipped, got %+v", r)
+			}
+		case "other":
+			if r.Err != nil {
+				t.Errorf("other: want success, got %v", r.Err)
+			}
+		}
+	}
+}
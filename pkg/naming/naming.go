@@ -0,0 +1,151 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package naming applies the package naming rules of each registry, so a
+// name the registry would reject is fixed or reported before anything is
+// built.
+package naming
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Debian Policy 5.6.1
+	debName = regexp.MustCompile(`^[a-z0-9][a-z0-9+.-]+$`)
+
+	snapName     = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	snapLetter   = regexp.MustCompile(`[a-z]`)
+	snapHyphens  = regexp.MustCompile(`-+`)
+	snapInvalid  = regexp.MustCompile(`[^a-z0-9-]+`)
+	npmName      = regexp.MustCompile(`^[a-z0-9][a-z0-9._~-]*$`)
+	wingetID     = regexp.MustCompile(`^[^.\s\\/:*?"<>|\x01-\x1f]{1,32}(\.[^.\s\\/:*?"<>|\x01-\x1f]{1,32}){1,7}$`)
+	debSeparator = regexp.MustCompile(`[_\s]+`)
+)
+
+// maxSnapName is the longest name the Snap Store accepts
+const maxSnapName = 40
+
+// maxNPMName is the longest name, scope included, npm accepts
+const maxNPMName = 214
+
+// maxWingetID is the longest PackageIdentifier winget accepts
+const maxWingetID = 128
+
+// npmReserved are names npm refuses without a scope: Node.js core modules,
+// which require() resolves before any package, and npm's own blocklist
+var npmReserved = map[string]bool{
+	"assert": true, "async_hooks": true, "buffer": true, "child_process": true, "cluster": true,
+	"console": true, "constants": true, "crypto": true, "dgram": true, "diagnostics_channel": true,
+	"dns": true, "domain": true, "events": true, "fs": true, "http": true, "http2": true,
+	"https": true, "inspector": true, "module": true, "net": true, "os": true, "path": true,
+	"perf_hooks": true, "process": true, "punycode": true, "querystring": true, "readline": true,
+	"repl": true, "stream": true, "string_decoder": true, "sys": true, "timers": true, "tls": true,
+	"trace_events": true, "tty": true, "url": true, "util": true, "v8": true, "vm": true,
+	"wasi": true, "worker_threads": true, "zlib": true,
+	"node_modules": true, "favicon.ico": true,
+}
+
+// Trademarks are words stores reject in names without the owner's
+// permission, such as the Snap Store and winget's review
+var Trademarks = []string{"android", "apple", "chrome", "docker", "firefox", "github", "google", "microsoft", "ubuntu", "windows"}
+
+// Deb returns name as a Debian package name: lower case, with underscores
+// and spaces turned into hyphens
+func Deb(name string) (string, error) {
+	fixed := debSeparator.ReplaceAllString(strings.ToLower(name), "-")
+	if !debName.MatchString(fixed) {
+		return "", fmt.Errorf("deb package names need at least two characters, start with a letter or digit and use only a-z, 0-9, +, - and .; set a name like %q", suggest(fixed))
+	}
+	return fixed, nil
+}
+
+// Snap returns name as a Snap Store name: lower case letters, digits and
+// single hyphens, with at least one letter and at most 40 characters
+func Snap(name string) (string, error) {
+	fixed := snapInvalid.ReplaceAllString(strings.ToLower(name), "-")
+	fixed = strings.Trim(snapHyphens.ReplaceAllString(fixed, "-"), "-")
+	switch {
+	case !snapName.MatchString(fixed):
+		return "", fmt.Errorf("snap names use only a-z, 0-9 and hyphens; %q has nothing left to use", name)
+	case !snapLetter.MatchString(fixed):
+		return "", fmt.Errorf("snap names need at least one letter; %q has none", fixed)
+	case len(fixed) > maxSnapName:
+		return "", fmt.Errorf("snap names are at most %d characters; %q has %d", maxSnapName, fixed, len(fixed))
+	}
+	return fixed, nil
+}
+
+// NPM returns name as an npm package name, lower case and under scope
+// when one is given. Names npm reserves need a scope.
+func NPM(name, scope string) (string, error) {
+	fixed := strings.ToLower(name)
+	if !npmName.MatchString(fixed) {
+		return "", fmt.Errorf("npm names start with a letter or digit and use only a-z, 0-9, -, ., _ and ~; set a name like %q", suggest(fixed))
+	}
+	scope = strings.TrimPrefix(scope, "@")
+	switch {
+	case scope != "" && !npmName.MatchString(scope):
+		return "", fmt.Errorf("npm scopes use only a-z, 0-9, -, ., _ and ~; %q does not", scope)
+	case scope != "":
+		fixed = "@" + scope + "/" + fixed
+	case npmReserved[fixed]:
+		return "", fmt.Errorf("npm reserves %q for Node.js; publish under a scope with packages.npm.scope", fixed)
+	}
+	if len(fixed) > maxNPMName {
+		return "", fmt.Errorf("npm names are at most %d characters; %q has %d", maxNPMName, fixed, len(fixed))
+	}
+	return fixed, nil
+}
+
+// WingetIdentifier checks a winget PackageIdentifier, which is two to
+// eight dot-separated parts such as Publisher.Package
+func WingetIdentifier(id string) error {
+	if len(id) > maxWingetID || !wingetID.MatchString(id) {
+		return fmt.Errorf("winget identifiers are 2 to 8 parts of at most 32 characters joined by dots, without spaces or any of \\/:*?\"<>|, e.g. Publisher.Package; %q is not", id)
+	}
+	return nil
+}
+
+// Trademark returns the first of Trademarks name contains, or ""
+func Trademark(name string) string {
+	lower := strings.ToLower(name)
+	for _, mark := range Trademarks {
+		if strings.Contains(lower, mark) {
+			return mark
+		}
+	}
+	return ""
+}
+
+// suggest strips name down to characters every registry accepts
+func suggest(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	if s := strings.Trim(b.String(), "-"); s != "" {
+		return s
+	}
+	return "myapp"
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package naming
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeb(t *testing.T) {
+	tests := []struct {
+		name, want, err string
+	}{
+		{name: "myapp", want: "myapp"},
+		{name: "My_App", want: "my-app"},
+		{name: "lib++", want: "lib++"},
+		{name: "x", err: "at least two characters"},
+		{name: "-app", err: `"app"`},
+		{name: "my@app", err: `"my-app"`},
+	}
+	for _, tt := range tests {
+		got, err := Deb(tt.name)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Deb(%q) error = %v, want it to mention %s", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Deb(%q) = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestSnap(t *testing.T) {
+	tests := []struct {
+		name, want, err string
+	}{
+		{name: "myapp", want: "myapp"},
+		{name: "My_App.CLI", want: "my-app-cli"},
+		{name: "--my--app--", want: "my-app"},
+		{name: "2024", err: "at least one letter"},
+		{name: "__", err: "nothing left"},
+		{name: strings.Repeat("a", 41), err: "at most 40"},
+	}
+	for _, tt := range tests {
+		got, err := Snap(tt.name)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Snap(%q) error = %v, want it to mention %s", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Snap(%q) = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestNPM(t *testing.T) {
+	tests := []struct {
+		name, scope, want, err string
+	}{
+		{name: "MyApp", want: "myapp"},
+		{name: "myapp", scope: "@acme", want: "@acme/myapp"},
+		{name: "myapp", scope: "acme", want: "@acme/myapp"},
+		{name: "http", err: "packages.npm.scope"},
+		{name: "http", scope: "acme", want: "@acme/http"},
+		{name: "_private", err: "start with a letter or digit"},
+		{name: "myapp", scope: "Acme Corp", err: "npm scopes"},
+		{name: strings.Repeat("a", 215), err: "at most 214"},
+	}
+	for _, tt := range tests {
+		got, err := NPM(tt.name, tt.scope)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("NPM(%q, %q) error = %v, want it to mention %s", tt.name, tt.scope, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NPM(%q, %q) = %q, %v, want %q", tt.name, tt.scope, got, err, tt.want)
+		}
+	}
+}
+
+func TestWingetIdentifier(t *testing.T) {
+	for _, id := range []string{"Acme.MyApp", "Acme.Tools.MyApp", "Microsoft.VisualStudioCode.Insiders"} {
+		if err := WingetIdentifier(id); err != nil {
+			t.Errorf("WingetIdentifier(%q) = %v", id, err)
+		}
+	}
+	for _, id := range []string{"MyApp", "Acme..MyApp", "Acme.My App", "Acme/MyApp", ".Acme.MyApp", "Acme." + strings.Repeat("a", 33)} {
+		if err := WingetIdentifier(id); err == nil {
+			t.Errorf("WingetIdentifier(%q) accepted an invalid identifier", id)
+		}
+	}
+}
+
+func TestTrademark(t *testing.T) {
+	if got := Trademark("docker-helper"); got != "docker" {
+		t.Errorf("Trademark(docker-helper) = %q", got)
+	}
+	if got := Trademark("myapp"); got != "" {
+		t.Errorf("Trademark(myapp) = %q", got)
+	}
+}
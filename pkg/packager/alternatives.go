@@ -0,0 +1,93 @@
+package packager
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// ValidateAlternatives checks update-alternatives entries for deb and rpm
+func ValidateAlternatives(alternatives []config.AlternativeConfig, target string) error {
+	seen := make(map[string]bool)
+	for _, alt := range alternatives {
+		if alt.Link == "" {
+			return fmt.Errorf("alternatives: link is required")
+		}
+		if !filepath.IsAbs(alt.Link) {
+			return fmt.Errorf("alternatives: link %s must be an absolute path", alt.Link)
+		}
+		if alt.Link == target {
+			return fmt.Errorf("alternatives: link %s cannot be the installed binary itself", alt.Link)
+		}
+		name := AlternativeName(alt)
+		if seen[name] {
+			return fmt.Errorf("alternatives: duplicate link group %s", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// AlternativeName returns the link group name, defaulting to the base name
+// of the link
+func AlternativeName(alt config.AlternativeConfig) string {
+	if alt.Name != "" {
+		return alt.Name
+	}
+	return filepath.Base(alt.Link)
+}
+
+// AlternativeInstallCommands returns update-alternatives --install lines
+// registering target for each entry
+func AlternativeInstallCommands(alternatives []config.AlternativeConfig, target string) []string {
+	var commands []string
+	for _, alt := range alternatives {
+		commands = append(commands, fmt.Sprintf("update-alternatives --install %s %s %s %d",
+			alt.Link, AlternativeName(alt), target, alt.Priority))
+	}
+	return commands
+}
+
+// AlternativeRemoveCommands returns update-alternatives --remove lines
+// unregistering target for each entry
+func AlternativeRemoveCommands(alternatives []config.AlternativeConfig, target string) []string {
+	var commands []string
+	for _, alt := range alternatives {
+		commands = append(commands, fmt.Sprintf("update-alternatives --remove %s %s", AlternativeName(alt), target))
+	}
+	return commands
+}
+
+// indentLines prefixes every command with indent, one per line
+func indentLines(lines []string, indent string) string {
+	return indent + strings.Join(lines, "\n"+indent)
+}
+
+// DebPostinst returns a DEBIAN/postinst script registering alternatives
+// and printing the post-install message
+func DebPostinst(alternatives []config.AlternativeConfig, target, message string) string {
+	return fmt.Sprintf(`#!/bin/sh
+set -e
+
+if [ "$1" = "configure" ]; then
+%s
+fi
+
+exit 0
+`, indentLines(append(AlternativeInstallCommands(alternatives, target), ShellEcho(message)...), "    "))
+}
+
+// DebPrerm returns a DEBIAN/prerm script removing alternatives on removal
+func DebPrerm(alternatives []config.AlternativeConfig, target string) string {
+	return fmt.Sprintf(`#!/bin/sh
+set -e
+
+if [ "$1" = "remove" ] || [ "$1" = "deconfigure" ]; then
+%s
+fi
+
+exit 0
+`, indentLines(AlternativeRemoveCommands(alternatives, target), "    "))
+}
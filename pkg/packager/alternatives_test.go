@@ -0,0 +1,80 @@
+package packager
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestValidateAlternatives(t *testing.T) {
+	target := "/usr/bin/myapp"
+
+	tests := []struct {
+		name    string
+		alts    []config.AlternativeConfig
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"valid", []config.AlternativeConfig{{Link: "/usr/bin/tool", Priority: 50}}, false},
+		{"missing link", []config.AlternativeConfig{{Priority: 50}}, true},
+		{"relative link", []config.AlternativeConfig{{Link: "bin/tool"}}, true},
+		{"link is target", []config.AlternativeConfig{{Link: target}}, true},
+		{"duplicate group", []config.AlternativeConfig{{Link: "/usr/bin/tool"}, {Link: "/usr/local/bin/tool"}}, true},
+		{"distinct groups", []config.AlternativeConfig{{Link: "/usr/bin/tool"}, {Link: "/usr/local/bin/tool", Name: "tool-local"}}, false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateAlternatives(tt.alts, target)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: ValidateAlternatives() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestAlternativeCommands(t *testing.T) {
+	alts := []config.AlternativeConfig{
+		{Link: "/usr/bin/tool", Priority: 50},
+		{Link: "/usr/bin/t", Name: "t-short", Priority: 10},
+	}
+
+	install := AlternativeInstallCommands(alts, "/usr/bin/myapp")
+	expected := []string{
+		"update-alternatives --install /usr/bin/tool tool /usr/bin/myapp 50",
+		"update-alternatives --install /usr/bin/t t-short /usr/bin/myapp 10",
+	}
+	for i := range expected {
+		if install[i] != expected[i] {
+			t.Errorf("install[%d] = %q, expected %q", i, install[i], expected[i])
+		}
+	}
+
+	remove := AlternativeRemoveCommands(alts, "/usr/bin/myapp")
+	if remove[1] != "update-alternatives --remove t-short /usr/bin/myapp" {
+		t.Errorf("Unexpected remove command: %q", remove[1])
+	}
+}
+
+func TestDebMaintainerScripts(t *testing.T) {
+	alts := []config.AlternativeConfig{{Link: "/usr/bin/tool", Priority: 50}}
+
+	postinst := DebPostinst(alts, "/usr/bin/myapp", "")
+	if !strings.Contains(postinst, `if [ "$1" = "configure" ]; then`) ||
+		!strings.Contains(postinst, "    update-alternatives --install /usr/bin/tool tool /usr/bin/myapp 50") {
+		t.Errorf("Unexpected postinst:\n%s", postinst)
+	}
+
+	prerm := DebPrerm(alts, "/usr/bin/myapp")
+	if !strings.Contains(prerm, `"$1" = "remove"`) ||
+		!strings.Contains(prerm, "update-alternatives --remove tool /usr/bin/myapp") {
+		t.Errorf("Unexpected prerm:\n%s", prerm)
+	}
+}
+
+func TestDebPostinstMessage(t *testing.T) {
+	postinst := DebPostinst(nil, "/usr/bin/myapp", "Run `myapp init` to get started.\nIt's quick.")
+	expected := "if [ \"$1\" = \"configure\" ]; then\n    echo 'Run `myapp init` to get started.'\n    echo 'It'\\''s quick.'\nfi"
+	if !strings.Contains(postinst, expected) {
+		t.Errorf("postinst missing %q:\n%s", expected, postinst)
+	}
+}
@@ -3,16 +3,21 @@ package appimage
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
-	"text/template"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
 )
 
-type Packager struct{}
+type Packager struct {
+	outputs map[string]string
+}
 
 func New() *Packager {
 	return &Packager{}
@@ -24,25 +29,45 @@ func (p *Packager) Name() string {
 
 func (p *Packager) Validate(cfg *config.Config) error {
 	if len(cfg.Packages.AppImage.Categories) == 0 {
-		return fmt.Errorf("appimage.categories is required")
+		return errors.NotConfiguredError("appimage.categories is required")
 	}
 	return nil
 }
 
 func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
-	// Find Linux binary
-	var linuxBinary string
-	for arch, path := range cfg.Binaries {
-		if strings.HasPrefix(arch, "linux-") {
-			linuxBinary = path
-			break
-		}
-	}
-	if linuxBinary == "" {
+	binaries := packager.LinuxBinaries(cfg)
+	if len(binaries) == 0 {
 		return "", fmt.Errorf("no Linux binary found")
 	}
 
-	appDir := filepath.Join("dist", cfg.Name+".AppDir")
+	// Build one AppImage per architecture; the first (amd64 when present)
+	// is returned and the rest are reported through ArchOutputs
+	p.outputs = make(map[string]string)
+	var primary string
+	for _, binary := range binaries {
+		output, err := p.packArch(ctx, cfg, binary)
+		if err != nil {
+			return "", err
+		}
+		if primary == "" {
+			primary = output
+		}
+		p.outputs[packager.AppImageArch(binary.Arch)] = output
+	}
+
+	return primary, nil
+}
+
+// ArchOutputs returns the AppImages built by the last Pack, keyed by
+// architecture
+func (p *Packager) ArchOutputs() map[string]string {
+	return p.outputs
+}
+
+func (p *Packager) packArch(ctx context.Context, cfg *config.Config, binary packager.PlatformBinary) (string, error) {
+	arch := packager.AppImageArch(binary.Arch)
+
+	appDir := filepath.Join("dist", fmt.Sprintf("%s-%s.AppDir", cfg.Name, arch))
 	if err := os.RemoveAll(appDir); err != nil {
 		return "", err
 	}
@@ -51,12 +76,12 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 	}
 
 	// Create AppDir structure
-	if err := p.createAppDirStructure(appDir, cfg, linuxBinary); err != nil {
+	if err := p.createAppDirStructure(appDir, cfg, binary.Path); err != nil {
 		return "", err
 	}
 
 	// Build AppImage
-	return p.buildAppImage(ctx, appDir, cfg)
+	return p.buildAppImage(ctx, appDir, cfg, arch)
 }
 
 func (p *Packager) createAppDirStructure(appDir string, cfg *config.Config, binaryPath string) error {
@@ -75,10 +100,7 @@ func (p *Packager) createAppDirStructure(appDir string, cfg *config.Config, bina
 
 	// Copy binary
 	binDest := filepath.Join(appDir, "usr", "bin", cfg.Name)
-	if err := p.copyFile(binaryPath, binDest); err != nil {
-		return err
-	}
-	if err := os.Chmod(binDest, 0755); err != nil {
+	if err := paths.CopyExecutable(binaryPath, binDest, packager.CopyOptions(cfg)); err != nil {
 		return err
 	}
 
@@ -95,7 +117,7 @@ func (p *Packager) createAppDirStructure(appDir string, cfg *config.Config, bina
 	}
 
 	// Create symlinks for AppImage convention
-	if err := os.Symlink("usr/share/applications/"+cfg.Name+".desktop", filepath.Join(appDir, cfg.Name+".desktop")); err != nil {
+	if err := paths.Link("usr/share/applications/"+cfg.Name+".desktop", filepath.Join(appDir, cfg.Name+".desktop")); err != nil {
 		return err
 	}
 
@@ -109,7 +131,7 @@ HERE="$(dirname "$(readlink -f "${0}")")"
 export PATH="${HERE}/usr/bin:${PATH}"
 exec "${HERE}/usr/bin/{{.Name}}" "$@"`
 
-	t, err := template.New("apprun").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "appimage/apprun", tmpl)
 	if err != nil {
 		return err
 	}
@@ -137,7 +159,7 @@ Icon={{.Name}}
 Categories={{.Categories}}
 Terminal={{.Terminal}}`
 
-	t, err := template.New("desktop").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "appimage/desktop", tmpl)
 	if err != nil {
 		return err
 	}
@@ -167,29 +189,13 @@ Terminal={{.Terminal}}`
 	return t.Execute(f, data)
 }
 
-func (p *Packager) copyFile(src, dst string) error {
-	srcFile, err := os.Open(src)
-	if err != nil {
-		return err
-	}
-	defer srcFile.Close()
-
-	dstFile, err := os.Create(dst)
-	if err != nil {
-		return err
-	}
-	defer dstFile.Close()
-
-	_, err = dstFile.ReadFrom(srcFile)
-	return err
-}
 
-func (p *Packager) buildAppImage(ctx context.Context, appDir string, cfg *config.Config) (string, error) {
-	outputPath := filepath.Join("dist", fmt.Sprintf("%s-%s-x86_64.AppImage", cfg.Name, cfg.Version))
+func (p *Packager) buildAppImage(ctx context.Context, appDir string, cfg *config.Config, arch string) (string, error) {
+	outputPath := filepath.Join("dist", fmt.Sprintf("%s-%s-%s.AppImage", cfg.Name, cfg.Version, arch))
 
 	// Try appimagetool first
 	if _, err := exec.LookPath("appimagetool"); err == nil {
-		return p.buildWithAppimagetool(ctx, appDir, outputPath)
+		return p.buildWithAppimagetool(ctx, appDir, outputPath, arch)
 	}
 
 	// Fallback to manual squashfs creation
@@ -197,11 +203,14 @@ func (p *Packager) buildAppImage(ctx context.Context, appDir string, cfg *config
 		return p.buildWithSquashfs(ctx, appDir, outputPath)
 	}
 
-	return "", fmt.Errorf("neither appimagetool nor mksquashfs found - install AppImageKit or squashfs-tools")
+	return "", errors.ToolNotFoundError("neither appimagetool nor mksquashfs found - install AppImageKit or squashfs-tools")
 }
 
-func (p *Packager) buildWithAppimagetool(ctx context.Context, appDir, outputPath string) (string, error) {
+// buildWithAppimagetool sets ARCH so appimagetool embeds the runtime
+// for the target architecture rather than the host's
+func (p *Packager) buildWithAppimagetool(ctx context.Context, appDir, outputPath, arch string) (string, error) {
 	cmd := exec.CommandContext(ctx, "appimagetool", appDir, outputPath)
+	cmd.Env = append(os.Environ(), "ARCH="+arch)
 	
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return "", fmt.Errorf("appimagetool failed: %w\nOutput: %s", err, output)
@@ -231,10 +240,11 @@ func (p *Packager) buildWithSquashfs(ctx context.Context, appDir, outputPath str
 
 func (p *Packager) createAppImageFromSquashfs(squashfsPath, outputPath string) error {
 	// This is a simplified version - in production would need proper AppImage runtime
-	squashfsData, err := os.ReadFile(squashfsPath)
+	squashfs, err := os.Open(squashfsPath)
 	if err != nil {
 		return err
 	}
+	defer squashfs.Close()
 
 	// Create a basic AppImage header (simplified)
 	header := fmt.Sprintf("#!/bin/sh\n# AppImage created by bagboy\n# This is a simplified AppImage - use appimagetool for production\necho 'AppImage would execute here'\n")
@@ -249,7 +259,7 @@ func (p *Packager) createAppImageFromSquashfs(squashfsPath, outputPath string) e
 		return err
 	}
 
-	if _, err := file.Write(squashfsData); err != nil {
+	if _, err := io.Copy(file, squashfs); err != nil {
 		return err
 	}
 
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
 )
 
 func TestAppImagePackager(t *testing.T) {
@@ -110,7 +111,7 @@ func TestAppImagePack(t *testing.T) {
 		}
 		
 		// Check if AppDir was created
-		appDirPath := filepath.Join("dist", "testapp.AppDir")
+		appDirPath := filepath.Join("dist", "testapp-x86_64.AppDir")
 		if _, err := os.Stat(appDirPath); os.IsNotExist(err) {
 			t.Error("AppDir was not created")
 		}
@@ -259,8 +260,6 @@ func TestCreateDesktopFile(t *testing.T) {
 }
 
 func TestCopyFile(t *testing.T) {
-	packager := New()
-	
 	tmpDir := t.TempDir()
 	srcPath := filepath.Join(tmpDir, "source")
 	dstPath := filepath.Join(tmpDir, "dest")
@@ -270,8 +269,8 @@ func TestCopyFile(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := packager.copyFile(srcPath, dstPath); err != nil {
-		t.Errorf("copyFile() error = %v", err)
+	if err := paths.CopyExecutable(srcPath, dstPath, paths.CopyOptions{}); err != nil {
+		t.Errorf("CopyExecutable() error = %v", err)
 	}
 
 	// Check destination file
@@ -286,12 +285,10 @@ func TestCopyFile(t *testing.T) {
 }
 
 func TestCopyFile_Error(t *testing.T) {
-	packager := New()
-	
 	// Test with non-existent source file
-	err := packager.copyFile("/non/existent/file", "/tmp/dest")
+	err := paths.CopyExecutable("/non/existent/file", "/tmp/dest", paths.CopyOptions{})
 	if err == nil {
-		t.Error("copyFile() should fail with non-existent source file")
+		t.Error("CopyExecutable() should fail with non-existent source file")
 	}
 }
 
@@ -308,7 +305,7 @@ func TestBuildAppImage_NoTools(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	_, err := packager.buildAppImage(ctx, appDir, cfg)
+	_, err := packager.buildAppImage(ctx, appDir, cfg, "x86_64")
 	
 	// Should return error about missing tools
 	if err == nil {
@@ -329,7 +326,7 @@ func TestBuildWithAppimagetool(t *testing.T) {
 	outputPath := filepath.Join(tmpDir, "test.AppImage")
 	
 	ctx := context.Background()
-	_, err := packager.buildWithAppimagetool(ctx, appDir, outputPath)
+	_, err := packager.buildWithAppimagetool(ctx, appDir, outputPath, "x86_64")
 	
 	// This will fail because appimagetool is not available, but we test the code path
 	if err == nil {
@@ -415,6 +412,10 @@ func TestCreateAppDirStructure_Error(t *testing.T) {
 }
 
 func TestAppImagePack_MissingBinary(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(t.TempDir())
+
 	packager := New()
 	
 	cfg := &config.Config{
@@ -453,3 +454,47 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestAppImagePack_MultiArch(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		Name:     "testapp",
+		Version:  "1.0.0",
+		Binaries: map[string]string{},
+		Packages: config.PackagesConfig{
+			AppImage: config.AppImageConfig{Categories: []string{"Utility"}},
+		},
+	}
+	for _, arch := range []string{"amd64", "arm64"} {
+		path := filepath.Join(tmpDir, "testapp-linux-"+arch)
+		if err := os.WriteFile(path, []byte("binary"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		cfg.Binaries["linux-"+arch] = path
+	}
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	packager := New()
+	outputPath, err := packager.Pack(context.Background(), cfg)
+	if err != nil {
+		if !contains(err.Error(), "neither appimagetool nor mksquashfs found") {
+			t.Fatalf("Pack() unexpected error = %v", err)
+		}
+		// Without tools the AppDirs are still staged per architecture
+		if _, err := os.Stat(filepath.Join("dist", "testapp-x86_64.AppDir")); err != nil {
+			t.Error("x86_64 AppDir was not created")
+		}
+		return
+	}
+
+	if filepath.Base(outputPath) != "testapp-1.0.0-x86_64.AppImage" {
+		t.Errorf("Expected x86_64 AppImage as primary output, got %s", outputPath)
+	}
+	outputs := packager.ArchOutputs()
+	if filepath.Base(outputs["aarch64"]) != "testapp-1.0.0-aarch64.AppImage" {
+		t.Errorf("Expected aarch64 AppImage, got %v", outputs)
+	}
+}
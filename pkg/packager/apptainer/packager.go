@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
-	"text/template"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
 )
 
 type Packager struct{}
@@ -23,23 +23,18 @@ func (p *Packager) Name() string {
 
 func (p *Packager) Validate(cfg *config.Config) error {
 	if cfg.Description == "" {
-		return fmt.Errorf("description is required for Apptainer definition")
+		return errors.NotConfiguredError("description is required for Apptainer definition")
 	}
 	return nil
 }
 
 func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
 	// Find Linux binary (Apptainer primarily runs on Linux)
-	var linuxBinary string
-	for arch, path := range cfg.Binaries {
-		if strings.HasPrefix(arch, "linux-") {
-			linuxBinary = path
-			break
-		}
-	}
-	if linuxBinary == "" {
+	binary, ok := packager.PrimaryLinuxBinary(cfg)
+	if !ok {
 		return "", fmt.Errorf("no Linux binary found for Apptainer")
 	}
+	linuxBinary := binary.Path
 
 	apptainerDir := filepath.Join("dist", "apptainer")
 	if err := os.MkdirAll(apptainerDir, 0755); err != nil {
@@ -66,7 +61,7 @@ func (p *Packager) createDefinitionFile(path string, cfg *config.Config, binaryP
 From: ubuntu:22.04
 
 %labels
-    Author {{.Author}}
+    Author {{.PrimaryAuthor}}
     Version {{.Version}}
     Description {{.Description}}
     {{if .Homepage}}URL {{.Homepage}}{{end}}
@@ -103,7 +98,7 @@ From: ubuntu:22.04
 %test
     {{.Name}} --version || {{.Name}} --help || echo "{{.Name}} installed successfully"`
 
-	t, err := template.New("definition").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "apptainer/definition", tmpl)
 	if err != nil {
 		return err
 	}
@@ -162,7 +157,7 @@ else
     exit 1
 fi`
 
-	t, err := template.New("build").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "apptainer/build", tmpl)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,118 @@
+package packager
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// PlatformBinary is a binary from cfg.Binaries with its Go architecture
+type PlatformBinary struct {
+	Arch string
+	Path string
+}
+
+// archOrder ranks architectures so amd64 is always the primary binary
+var archOrder = map[string]int{"amd64": 0, "arm64": 1, "386": 2, "arm": 3}
+
+// LinuxBinaries returns the configured Linux binaries in a stable order:
+// amd64 first, then arm64, then any others alphabetically
+func LinuxBinaries(cfg *config.Config) []PlatformBinary {
+	return PlatformBinaries(cfg, "linux")
+}
+
+// PlatformBinaries returns the configured binaries for goos in the same
+// order as LinuxBinaries
+func PlatformBinaries(cfg *config.Config, goos string) []PlatformBinary {
+	var binaries []PlatformBinary
+	for platform, path := range cfg.Binaries {
+		if arch, ok := strings.CutPrefix(platform, goos+"-"); ok {
+			binaries = append(binaries, PlatformBinary{Arch: arch, Path: path})
+		}
+	}
+
+	sort.Slice(binaries, func(i, j int) bool {
+		ri, iKnown := archOrder[binaries[i].Arch]
+		rj, jKnown := archOrder[binaries[j].Arch]
+		switch {
+		case iKnown && jKnown:
+			return ri < rj
+		case iKnown != jKnown:
+			return iKnown
+		}
+		return binaries[i].Arch < binaries[j].Arch
+	})
+
+	return binaries
+}
+
+// PrimaryLinuxBinary returns the binary used by packagers that ship a single
+// architecture, preferring amd64
+func PrimaryLinuxBinary(cfg *config.Config) (PlatformBinary, bool) {
+	binaries := LinuxBinaries(cfg)
+	if len(binaries) == 0 {
+		return PlatformBinary{}, false
+	}
+	return binaries[0], true
+}
+
+// DebArch maps a Go architecture to its Debian name
+func DebArch(goarch string) string {
+	switch goarch {
+	case "386":
+		return "i386"
+	case "arm":
+		return "armhf"
+	default:
+		return goarch
+	}
+}
+
+// RPMArch maps a Go architecture to its RPM name
+func RPMArch(goarch string) string {
+	switch goarch {
+	case "amd64":
+		return "x86_64"
+	case "arm64":
+		return "aarch64"
+	case "386":
+		return "i686"
+	case "arm":
+		return "armv7hl"
+	default:
+		return goarch
+	}
+}
+
+// AppImageArch maps a Go architecture to the ARCH name appimagetool uses to
+// select its runtime
+func AppImageArch(goarch string) string {
+	switch goarch {
+	case "amd64":
+		return "x86_64"
+	case "arm64":
+		return "aarch64"
+	case "386":
+		return "i686"
+	case "arm":
+		return "armhf"
+	default:
+		return goarch
+	}
+}
+
+// FreeBSDArch maps a Go architecture to the name FreeBSD uses in package
+// ABIs and ports
+func FreeBSDArch(goarch string) string {
+	switch goarch {
+	case "arm64":
+		return "aarch64"
+	case "386":
+		return "i386"
+	case "arm":
+		return "armv7"
+	default:
+		return goarch
+	}
+}
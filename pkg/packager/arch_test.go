@@ -0,0 +1,70 @@
+package packager
+
+import (
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestLinuxBinaries(t *testing.T) {
+	cfg := &config.Config{
+		Binaries: map[string]string{
+			"darwin-arm64":  "bin/darwin-arm64",
+			"linux-riscv64": "bin/linux-riscv64",
+			"linux-arm64":   "bin/linux-arm64",
+			"linux-amd64":   "bin/linux-amd64",
+			"linux-arm":     "bin/linux-arm",
+		},
+	}
+
+	binaries := LinuxBinaries(cfg)
+	expected := []string{"amd64", "arm64", "arm", "riscv64"}
+	if len(binaries) != len(expected) {
+		t.Fatalf("Expected %d Linux binaries, got %d", len(expected), len(binaries))
+	}
+	for i, arch := range expected {
+		if binaries[i].Arch != arch {
+			t.Errorf("binaries[%d] = %s, expected %s", i, binaries[i].Arch, arch)
+		}
+	}
+
+	primary, ok := PrimaryLinuxBinary(cfg)
+	if !ok || primary.Path != "bin/linux-amd64" {
+		t.Errorf("Expected amd64 primary binary, got %+v", primary)
+	}
+
+	if _, ok := PrimaryLinuxBinary(&config.Config{}); ok {
+		t.Error("Expected no primary binary without Linux binaries")
+	}
+
+	if darwin := PlatformBinaries(cfg, "darwin"); len(darwin) != 1 || darwin[0].Arch != "arm64" {
+		t.Errorf("Expected the darwin-arm64 binary, got %+v", darwin)
+	}
+}
+
+func TestArchNames(t *testing.T) {
+	tests := []struct {
+		goarch, deb, rpm, appimage, freebsd string
+	}{
+		{"amd64", "amd64", "x86_64", "x86_64", "amd64"},
+		{"arm64", "arm64", "aarch64", "aarch64", "aarch64"},
+		{"386", "i386", "i686", "i686", "i386"},
+		{"arm", "armhf", "armv7hl", "armhf", "armv7"},
+		{"riscv64", "riscv64", "riscv64", "riscv64", "riscv64"},
+	}
+
+	for _, tt := range tests {
+		if got := DebArch(tt.goarch); got != tt.deb {
+			t.Errorf("DebArch(%s) = %s, expected %s", tt.goarch, got, tt.deb)
+		}
+		if got := RPMArch(tt.goarch); got != tt.rpm {
+			t.Errorf("RPMArch(%s) = %s, expected %s", tt.goarch, got, tt.rpm)
+		}
+		if got := AppImageArch(tt.goarch); got != tt.appimage {
+			t.Errorf("AppImageArch(%s) = %s, expected %s", tt.goarch, got, tt.appimage)
+		}
+		if got := FreeBSDArch(tt.goarch); got != tt.freebsd {
+			t.Errorf("FreeBSDArch(%s) = %s, expected %s", tt.goarch, got, tt.freebsd)
+		}
+	}
+}
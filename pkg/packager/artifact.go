@@ -0,0 +1,182 @@
+package packager
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// Kind says what an artifact is
+type Kind string
+
+const (
+	KindPackage   Kind = "package"   // installed by a package manager, e.g. a deb
+	KindInstaller Kind = "installer" // run by the user to install, e.g. an msi or install.sh
+	KindManifest  Kind = "manifest"  // describes where to fetch the binaries, e.g. a formula
+	KindImage     Kind = "image"     // a container image or its build context
+	KindBinary    Kind = "binary"    // a bare executable published as a release asset
+)
+
+// Artifact is one file built by a packager
+type Artifact struct {
+	Path     string `json:"path"`
+	Format   string `json:"format,omitempty"`
+	OS       string `json:"os,omitempty"`      // Go operating system, e.g. linux
+	Arch     string `json:"arch,omitempty"`    // Go architecture, or the format's own name for it
+	Variant  string `json:"variant,omitempty"` // anything else setting it apart, e.g. a locale
+	Kind     Kind   `json:"kind,omitempty"`
+	Checksum string `json:"sha256,omitempty"`
+}
+
+// Label names the artifact within its format, e.g. arm64 or amd64-de-DE
+func (a Artifact) Label() string {
+	switch {
+	case a.Arch == "":
+		return a.Variant
+	case a.Variant == "":
+		return a.Arch
+	}
+	return a.Arch + "-" + a.Variant
+}
+
+// Platform returns the artifact's os-arch, e.g. linux-arm64, or "" when it
+// targets no particular platform
+func (a Artifact) Platform() string {
+	if a.OS == "" || a.Arch == "" {
+		return a.OS
+	}
+	return a.OS + "-" + a.Arch
+}
+
+// ArtifactPacker is the second version of the Packager interface.
+// PackArtifacts returns every file built, primary first. PackAll prefers it
+// over Pack; packagers not yet migrated are wrapped by Adapt.
+type ArtifactPacker interface {
+	Name() string
+	Validate(cfg *config.Config) error
+	PackArtifacts(ctx context.Context, cfg *config.Config) ([]Artifact, error)
+}
+
+// MultiArchPackager is implemented by packagers that build one artifact per
+// architecture but have not moved to ArtifactPacker. Pack returns the
+// primary artifact; ArchOutputs returns all of them keyed by architecture.
+type MultiArchPackager interface {
+	ArchOutputs() map[string]string
+}
+
+// Adapt returns p as an ArtifactPacker. A Packager that only implements
+// Pack reports its output, plus any ArchOutputs, as artifacts.
+func Adapt(p Packager) ArtifactPacker {
+	if packer, ok := p.(ArtifactPacker); ok {
+		return packer
+	}
+	return adapter{p}
+}
+
+// Primary returns the path of the first artifact, for implementing Pack on
+// top of PackArtifacts
+func Primary(artifacts []Artifact, err error) (string, error) {
+	if err != nil || len(artifacts) == 0 {
+		return "", err
+	}
+	return artifacts[0].Path, nil
+}
+
+type adapter struct{ Packager }
+
+func (a adapter) PackArtifacts(ctx context.Context, cfg *config.Config) ([]Artifact, error) {
+	primary, err := a.Pack(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	all := []Artifact{{Path: primary}}
+	multi, ok := a.Packager.(MultiArchPackager)
+	if !ok {
+		return all, nil
+	}
+
+	outputs := multi.ArchOutputs()
+	keys := make([]string, 0, len(outputs))
+	for key, path := range outputs {
+		if path == primary {
+			all[0].OS, all[0].Arch = splitPlatform(key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		artifact := Artifact{Path: outputs[key]}
+		artifact.OS, artifact.Arch = splitPlatform(key)
+		all = append(all, artifact)
+	}
+	return all, nil
+}
+
+// splitPlatform splits an ArchOutputs key such as linux-arm64 into its
+// operating system and architecture. Keys without a known operating system
+// are taken as the architecture alone.
+func splitPlatform(key string) (string, string) {
+	goos, arch, ok := strings.Cut(key, "-")
+	if ok && knownOS[goos] {
+		return goos, arch
+	}
+	return "", key
+}
+
+var knownOS = map[string]bool{"linux": true, "darwin": true, "windows": true, "freebsd": true, "android": true}
+
+// formatOS is the operating system a format's artifacts are for, when the
+// format only serves one
+var formatOS = map[string]string{
+	"appimage": "linux", "deb": "linux", "rpm": "linux", "snap": "linux", "flatpak": "linux", "xbps": "linux",
+	"brew": "darwin", "dmg": "darwin",
+	"chocolatey": "windows", "msi": "windows", "msix": "windows", "scoop": "windows", "winget": "windows",
+	"freebsd": "freebsd",
+	"termux":  "android",
+}
+
+// formatKind is the kind of a format's artifacts when it is not a package
+var formatKind = map[string]Kind{
+	"dmg": KindInstaller, "msi": KindInstaller, "msix": KindInstaller, "installer": KindInstaller,
+	"brew": KindManifest, "scoop": KindManifest, "winget": KindManifest, "nix": KindManifest,
+	"spack": KindManifest, "flatpak": KindManifest, "webi": KindManifest, "xbps": KindManifest,
+	"docker": KindImage, "apptainer": KindImage,
+	"gh-extension": KindBinary,
+}
+
+// describe fills in what the packager left out of its artifacts: the
+// format, the operating system and kind the format implies, and the
+// SHA-256 of each regular file
+func describe(format string, artifacts []Artifact) error {
+	for i := range artifacts {
+		a := &artifacts[i]
+		if a.Format == "" {
+			a.Format = format
+		}
+		if a.OS == "" {
+			a.OS = formatOS[format]
+		}
+		if a.Kind == "" {
+			a.Kind = formatKind[format]
+			if a.Kind == "" {
+				a.Kind = KindPackage
+			}
+		}
+		if a.Checksum != "" {
+			continue
+		}
+		if info, err := os.Stat(a.Path); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		sum, err := fileSHA256(a.Path)
+		if err != nil {
+			return err
+		}
+		a.Checksum = sum
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package brew
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/packager/dmg"
+)
+
+const caskTemplate = `cask "{{.Token}}" do
+  version "{{.Version}}"
+  sha256 {{.SHA256}}
+
+  url "{{.URL}}"
+  name "{{.AppName}}"
+  desc {{printf "%q" .Desc}}
+  homepage "{{.Homepage}}"
+{{- if .MacOS}}
+
+  depends_on macos: ">= :{{.MacOS}}"
+{{- end}}
+
+{{- if .Pkg}}
+
+  pkg "{{.Pkg}}"
+{{- end}}
+{{- if .App}}
+
+  app "{{.App}}"
+{{- end}}
+{{- range .Binaries}}
+  binary "{{.Source}}"{{if .Target}}, target: "{{.Target}}"{{end}}
+{{- end}}
+{{- if .PkgID}}
+
+  uninstall pkgutil: "{{.PkgID}}"
+{{- end}}
+{{- if .Zap}}
+
+  zap trash: [
+{{- range .Zap}}
+    "{{.}}",
+{{- end}}
+  ]
+{{- end}}
+{{- if .Caveats}}
+
+  caveats <<~EOS
+{{.Caveats}}
+  EOS
+{{- end}}
+end
+`
+
+// caskBinary is a binary stanza, linking Source onto PATH as Target
+type caskBinary struct {
+	Source string
+	Target string
+}
+
+var tokenInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+
+// CaskToken is the cask's name in brew install --cask, e.g. my-app
+func CaskToken(cfg *config.Config) string {
+	if token := cfg.Packages.Brew.Cask.Token; token != "" {
+		return token
+	}
+	return strings.Trim(tokenInvalid.ReplaceAllString(strings.ToLower(cfg.Name), "-"), "-")
+}
+
+// CaskPath is where the cask is written, as Casks/<token>.rb in a tap
+func CaskPath(cfg *config.Config) string {
+	return filepath.Join("dist", "Casks", CaskToken(cfg)+".rb")
+}
+
+// validateCask checks the cask has a DMG to install from and one way of
+// installing it
+func validateCask(cfg *config.Config) error {
+	cask := cfg.Packages.Brew.Cask
+	if cask.App != "" && cask.Pkg != "" {
+		return fmt.Errorf("brew.cask: set app or pkg, not both")
+	}
+	if cask.PkgID != "" && cask.Pkg == "" {
+		return fmt.Errorf("brew.cask.pkg_id needs brew.cask.pkg")
+	}
+	if err := dmg.New().Validate(cfg); err != nil {
+		return fmt.Errorf("brew.cask installs the DMG: %w", err)
+	}
+	return nil
+}
+
+// Cask renders the cask installing the DMG at dmgPath. Its sha256 is
+// :no_check until the DMG has been built.
+func Cask(cfg *config.Config, dmgPath string) (string, error) {
+	t, err := packager.ParseTemplate(cfg, "brew/cask", caskTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	url, err := packager.ReleaseURL(cfg, filepath.Base(dmgPath))
+	if err != nil {
+		return "", err
+	}
+
+	cask := cfg.Packages.Brew.Cask
+	app := cask.App
+	if app == "" && cask.Pkg == "" && cfg.Desktop != nil {
+		app = dmg.AppBundle(cfg)
+	}
+	var binaries []caskBinary
+	for _, binary := range cask.Binaries {
+		binaries = append(binaries, caskBinary{Source: binary})
+	}
+	switch {
+	case len(binaries) > 0 || cask.Pkg != "":
+	case app != "":
+		// Put the app's own executable on PATH too
+		binaries = []caskBinary{{Source: "#{appdir}/" + app + "/Contents/MacOS/" + cfg.Name, Target: cfg.Name}}
+	default:
+		// A command-line tool shipped on a DMG
+		binaries = []caskBinary{{Source: cfg.Name}}
+	}
+
+	caveats := cfg.Packages.Brew.Caveats
+	if caveats == "" {
+		caveats = cfg.PostInstall.MessageFor("brew")
+	}
+
+	data := struct {
+		*config.Config
+		Token    string
+		SHA256   string
+		URL      string
+		AppName  string
+		Desc     string
+		MacOS    string
+		App      string
+		Pkg      string
+		PkgID    string
+		Binaries []caskBinary
+		Zap      []string
+		Caveats  string
+	}{
+		Config:   cfg,
+		Token:    CaskToken(cfg),
+		SHA256:   dmgSHA256(dmgPath),
+		URL:      url,
+		AppName:  packager.DesktopName(cfg),
+		Desc:     strings.TrimSpace(strings.SplitN(cfg.Description, "\n", 2)[0]),
+		MacOS:    strings.TrimPrefix(cask.MacOS, ":"),
+		App:      app,
+		Pkg:      cask.Pkg,
+		PkgID:    cask.PkgID,
+		Binaries: binaries,
+		Zap:      cask.Zap,
+		Caveats:  indent(caveats, "    "),
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// dmgSHA256 returns the quoted SHA-256 of the DMG, or :no_check when it
+// has not been built
+func dmgSHA256(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ":no_check"
+	}
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// writeCask writes the cask for the DMG to CaskPath
+func writeCask(cfg *config.Config) (string, error) {
+	cask, err := Cask(cfg, dmg.OutputPath(cfg))
+	if err != nil {
+		return "", err
+	}
+	path := CaskPath(cfg)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(cask), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"text/template"
+	"strconv"
+	"strings"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
 )
 
 type Packager struct{}
@@ -22,29 +25,77 @@ func (p *Packager) Name() string {
 
 func (p *Packager) Validate(cfg *config.Config) error {
 	if cfg.Homepage == "" {
-		return fmt.Errorf("homepage is required for brew formula")
+		return errors.NotConfiguredError("homepage is required for brew formula")
+	}
+	if cfg.Packages.Brew.Cask.Enabled {
+		return validateCask(cfg)
 	}
 	return nil
 }
 
 func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
+	return packager.Primary(p.PackArtifacts(ctx, cfg))
+}
+
+// PackArtifacts writes the formula and, with brew.cask, a cask for the DMG
+func (p *Packager) PackArtifacts(ctx context.Context, cfg *config.Config) ([]packager.Artifact, error) {
+	formula, err := p.writeFormula(cfg)
+	if err != nil {
+		return nil, err
+	}
+	artifacts := []packager.Artifact{{Path: formula, Kind: packager.KindManifest}}
+	if !cfg.Packages.Brew.Cask.Enabled {
+		return artifacts, nil
+	}
+
+	cask, err := writeCask(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write cask: %w", err)
+	}
+	return append(artifacts, packager.Artifact{Path: cask, OS: "darwin", Variant: "cask", Kind: packager.KindManifest}), nil
+}
+
+func (p *Packager) writeFormula(cfg *config.Config) (string, error) {
 	tmpl := `class {{.ClassName}} < Formula
-  desc "{{.Description}}"
+  desc {{printf "%q" .Description}}
   homepage "{{.Homepage}}"
   version "{{.Version}}"
   license "{{.License}}"
+{{- range .ConflictsWith}}
+  conflicts_with "{{.Name}}"{{if .Because}}, because: {{printf "%q" .Because}}{{end}}
+{{- end}}
+{{- if .KegOnly}}
+  keg_only {{.KegOnly}}
+{{- end}}
+{{- range .DependsOn}}
+  depends_on "{{.}}"
+{{- end}}
 
   {{range $arch, $binary := .Binaries}}
   {{if eq $arch "darwin-amd64"}}
   if Hardware::CPU.intel?
     url "{{$.BaseURL}}/{{$.Name}}-darwin-amd64"
-    sha256 "TODO_CHECKSUM_AMD64"
+    {{- if $.Mirror}}
+    mirror "{{$.Mirror}}/{{$.Name}}-darwin-amd64"
+    {{- end}}
+    {{- if $.ChecksumComments}}
+    # Verify a download with: shasum -a 256 {{$.Name}}-darwin-amd64
+    # It must print this, and match checksums.txt on the release
+    {{- end}}
+    sha256 "{{shaOf $arch}}"
   end
   {{end}}
   {{if eq $arch "darwin-arm64"}}
   if Hardware::CPU.arm?
     url "{{$.BaseURL}}/{{$.Name}}-darwin-arm64"
-    sha256 "TODO_CHECKSUM_ARM64"
+    {{- if $.Mirror}}
+    mirror "{{$.Mirror}}/{{$.Name}}-darwin-arm64"
+    {{- end}}
+    {{- if $.ChecksumComments}}
+    # Verify a download with: shasum -a 256 {{$.Name}}-darwin-arm64
+    # It must print this, and match checksums.txt on the release
+    {{- end}}
+    sha256 "{{shaOf $arch}}"
   end
   {{end}}
   {{end}}
@@ -52,6 +103,14 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
   def install
     bin.install "{{.Name}}"
   end
+{{- if .Caveats}}
+
+  def caveats
+    <<~EOS
+{{.Caveats}}
+    EOS
+  end
+{{- end}}
 
   {{if .Test}}
   test do
@@ -60,21 +119,39 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
   {{end}}
 end`
 
-	t, err := template.New("formula").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "brew/formula", tmpl)
 	if err != nil {
 		return "", err
 	}
 
 	data := struct {
 		*config.Config
-		ClassName string
-		BaseURL   string
-		Test      string
+		ClassName        string
+		BaseURL          string
+		Mirror           string
+		ChecksumComments bool
+		Test             string
+		ConflictsWith    []config.BrewConflict
+		KegOnly          string
+		DependsOn        []string
+		Caveats          string
 	}{
-		Config:    cfg,
-		ClassName: capitalize(cfg.Name),
-		BaseURL:   cfg.Installer.BaseURL,
-		Test:      cfg.Packages.Brew.Test,
+		Config:           cfg,
+		ClassName:        capitalize(cfg.Name),
+		BaseURL:          baseURL(cfg),
+		Mirror:           strings.TrimSuffix(cfg.Packages.Brew.Mirror, "/"),
+		ChecksumComments: cfg.Packages.Brew.ChecksumComments,
+		Test:             cfg.Packages.Brew.Test,
+		ConflictsWith:    cfg.Packages.Brew.ConflictsWith,
+		KegOnly:          kegOnly(cfg.Packages.Brew.KegOnly),
+		DependsOn:        DependsOn(cfg),
+		Caveats:          indent(cfg.Packages.Brew.Caveats, "      "),
+	}
+	if data.Caveats == "" {
+		data.Caveats = indent(cfg.PostInstall.MessageFor("brew"), "      ")
+	}
+	if cfg.Packages.Brew.AnalyticsNotice {
+		data.Caveats = strings.TrimPrefix(data.Caveats+"\n"+indent(analyticsNotice, "      "), "\n")
 	}
 
 	outputPath := filepath.Join("dist", cfg.Name+".rb")
@@ -95,6 +172,77 @@ end`
 	return outputPath, nil
 }
 
+// analyticsNotice tells users how to turn off Homebrew's install analytics
+const analyticsNotice = `Homebrew records anonymous install analytics. To opt out, run
+  brew analytics off
+or set HOMEBREW_NO_ANALYTICS=1 in your shell profile.`
+
+// baseURL is where the formula downloads the binaries: brew.base_url,
+// such as a CDN in front of the release, or else installer.base_url
+func baseURL(cfg *config.Config) string {
+	if cfg.Packages.Brew.BaseURL != "" {
+		return strings.TrimSuffix(cfg.Packages.Brew.BaseURL, "/")
+	}
+	return cfg.Installer.BaseURL
+}
+
+// DependsOn returns the formulas of the bagboy tools cfg requires, from
+// their tap or this project's, e.g. owner/tap/helper. Homebrew installs the
+// latest formula, so version constraints are left to deps check.
+func DependsOn(cfg *config.Config) []string {
+	var formulas []string
+	for _, dep := range cfg.Dependencies.Bagboy {
+		tap := dep.Tap
+		if tap == "" {
+			tap = defaultTap(cfg)
+		}
+		owner, repo, ok := strings.Cut(tap, "/")
+		if !ok {
+			formulas = append(formulas, dep.Name)
+			continue
+		}
+		formulas = append(formulas, owner+"/"+strings.TrimPrefix(repo, "homebrew-")+"/"+dep.Name)
+	}
+	return formulas
+}
+
+// defaultTap returns the tap repository this project publishes to, or ""
+func defaultTap(cfg *config.Config) string {
+	switch {
+	case cfg.GitHub.Tap.Enabled && cfg.GitHub.Tap.Repo != "":
+		return cfg.GitHub.Tap.Repo
+	case cfg.GitHub.Tap.Enabled && cfg.GitHub.Owner != "":
+		return cfg.GitHub.Owner + "/homebrew-tap"
+	case cfg.GitLab.Tap.Enabled:
+		return cfg.GitLab.Tap.Project
+	}
+	return ""
+}
+
+// kegOnly renders the keg_only argument: symbols such as :versioned_formula
+// are passed through, anything else becomes a quoted reason
+func kegOnly(reason string) string {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || strings.HasPrefix(reason, ":") {
+		return reason
+	}
+	return strconv.Quote(reason)
+}
+
+func indent(text, prefix string) string {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return ""
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 func capitalize(s string) string {
 	if len(s) == 0 {
 		return s
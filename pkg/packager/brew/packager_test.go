@@ -18,6 +18,11 @@ package brew
 
 import (
 	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
@@ -55,14 +60,20 @@ func TestBrewPackager(t *testing.T) {
 }
 
 func TestBrewPack(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(t.TempDir())
+
 	p := New()
+	binary := filepath.Join(t.TempDir(), "test")
+	os.WriteFile(binary, []byte("fake binary"), 0755)
 	cfg := &config.Config{
 		Name:        "test",
 		Version:     "1.0.0",
 		Description: "Test app",
 		Homepage:    "https://example.com",
 		License:     "Apache-2.0",
-		Binaries:    map[string]string{"darwin-amd64": "test"},
+		Binaries:    map[string]string{"darwin-amd64": binary},
 		Installer: config.InstallerConfig{
 			BaseURL: "https://example.com/releases",
 		},
@@ -82,4 +93,226 @@ func TestBrewPack(t *testing.T) {
 	if output == "" {
 		t.Error("Expected output path")
 	}
+
+	// The formula carries the binary's real checksum
+	content, _ := os.ReadFile(output)
+	if sum := fmt.Sprintf("%x", sha256.Sum256([]byte("fake binary"))); !strings.Contains(string(content), `sha256 "`+sum+`"`) {
+		t.Errorf("Formula missing checksum %s:\n%s", sum, content)
+	}
+
+	cfg.Binaries["darwin-amd64"] = filepath.Join(t.TempDir(), "missing")
+	if _, err := p.Pack(ctx, cfg); err == nil {
+		t.Error("Expected Pack to fail without the binary to checksum")
+	}
+}
+
+func TestBrewPack_FormulaOptions(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(t.TempDir())
+
+	p := New()
+	binary := filepath.Join(t.TempDir(), "test-darwin-arm64")
+	os.WriteFile(binary, []byte("fake binary"), 0755)
+	cfg := &config.Config{
+		Name:        "test",
+		Version:     "1.0.0",
+		Description: "Test app",
+		Homepage:    "https://example.com",
+		License:     "MIT",
+		Binaries: map[string]string{
+			"darwin-arm64": binary,
+		},
+		Packages: config.PackagesConfig{
+			Brew: config.BrewConfig{
+				ConflictsWith: []config.BrewConflict{
+					{Name: "test-core", Because: `both install a "test" binary`},
+					{Name: "test-legacy"},
+				},
+				KegOnly: "it conflicts with the core formula",
+				Caveats: "Run `test init` to get started.\nDocs: https://example.com",
+			},
+		},
+		Dependencies: config.DependenciesConfig{
+			Bagboy: []config.ToolDependency{{Name: "helper", Version: ">=1.2", Tap: "acme/homebrew-tap"}},
+		},
+	}
+
+	output, err := p.Pack(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	defer os.Remove(output)
+
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read formula: %v", err)
+	}
+
+	for _, expected := range []string{
+		`conflicts_with "test-core", because: "both install a \"test\" binary"`,
+		`conflicts_with "test-legacy"` + "\n",
+		`keg_only "it conflicts with the core formula"`,
+		"def caveats\n    <<~EOS\n      Run `test init` to get started.\n      Docs: https://example.com\n    EOS\n  end",
+		`depends_on "acme/tap/helper"`,
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Formula missing %q:\n%s", expected, content)
+		}
+	}
+
+	// Symbols are passed through unquoted
+	cfg.Packages.Brew.KegOnly = ":versioned_formula"
+	cfg.Packages.Brew.Caveats = ""
+	if _, err := p.Pack(context.Background(), cfg); err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	content, _ = os.ReadFile(output)
+	if !strings.Contains(string(content), "keg_only :versioned_formula") {
+		t.Errorf("Expected symbol keg_only reason:\n%s", content)
+	}
+	if strings.Contains(string(content), "caveats") {
+		t.Error("Caveats block should be omitted when empty")
+	}
+
+	// The post-install message fills in for unset caveats
+	cfg.PostInstall.Message = "Run `test init` to get started."
+	if _, err := p.Pack(context.Background(), cfg); err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	content, _ = os.ReadFile(output)
+	if !strings.Contains(string(content), "<<~EOS\n      Run `test init` to get started.\n    EOS") {
+		t.Errorf("Expected post-install message as caveats:\n%s", content)
+	}
+}
+
+func TestBrewPack_Hosting(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(t.TempDir())
+
+	binary := filepath.Join(t.TempDir(), "test-darwin-arm64")
+	os.WriteFile(binary, []byte("fake binary"), 0755)
+	cfg := &config.Config{
+		Name:     "test",
+		Version:  "1.0.0",
+		Homepage: "https://example.com",
+		Binaries: map[string]string{"darwin-arm64": binary},
+		Installer: config.InstallerConfig{
+			BaseURL: "https://github.com/acme/test/releases/download/v1.0.0",
+		},
+		Packages: config.PackagesConfig{
+			Brew: config.BrewConfig{
+				BaseURL:          "https://cdn.example.com/test/1.0.0/",
+				Mirror:           "https://github.com/acme/test/releases/download/v1.0.0",
+				ChecksumComments: true,
+				AnalyticsNotice:  true,
+				Caveats:          "Run `test init` first.",
+			},
+		},
+	}
+
+	output, err := New().Pack(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	defer os.Remove(output)
+	content, _ := os.ReadFile(output)
+
+	sum := fmt.Sprintf("%x", sha256.Sum256([]byte("fake binary")))
+	for _, expected := range []string{
+		"    url \"https://cdn.example.com/test/1.0.0/test-darwin-arm64\"\n" +
+			"    mirror \"https://github.com/acme/test/releases/download/v1.0.0/test-darwin-arm64\"\n" +
+			"    # Verify a download with: shasum -a 256 test-darwin-arm64\n" +
+			"    # It must print this, and match checksums.txt on the release\n" +
+			"    sha256 \"" + sum + "\"",
+		"      Run `test init` first.\n      Homebrew records anonymous install analytics",
+		"      or set HOMEBREW_NO_ANALYTICS=1",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Formula missing %q:\n%s", expected, content)
+		}
+	}
+}
+
+func TestBrewPack_Cask(t *testing.T) {
+	testDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	binary := filepath.Join(testDir, "myapp-darwin-arm64")
+	os.WriteFile(binary, []byte("fake binary"), 0755)
+	cfg := &config.Config{
+		Name:        "myapp",
+		Version:     "2.0.0",
+		Description: "Draws diagrams\nLonger description.",
+		Homepage:    "https://example.com",
+		Binaries:    map[string]string{"darwin-arm64": binary},
+		GitHub:      config.GitHubConfig{Owner: "acme", Repo: "myapp"},
+		Desktop:     &config.DesktopConfig{Name: "My App"},
+		Packages: config.PackagesConfig{
+			Brew: config.BrewConfig{
+				Cask: config.BrewCaskConfig{Enabled: true, MacOS: "big_sur", Zap: []string{"~/Library/Preferences/dev.bagboy.myapp.plist"}},
+			},
+		},
+	}
+
+	p := New()
+	if err := p.Validate(cfg); err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+	artifacts, err := p.PackArtifacts(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	if len(artifacts) != 2 || artifacts[0].Path != filepath.Join("dist", "myapp.rb") || artifacts[1].Path != filepath.Join("dist", "Casks", "myapp.rb") {
+		t.Fatalf("Unexpected artifacts %+v", artifacts)
+	}
+
+	content, _ := os.ReadFile(artifacts[1].Path)
+	for _, expected := range []string{
+		`cask "myapp" do`,
+		`version "2.0.0"`,
+		"sha256 :no_check",
+		`url "https://github.com/acme/myapp/releases/download/v2.0.0/myapp-2.0.0.dmg"`,
+		`name "My App"`,
+		`desc "Draws diagrams"`,
+		`depends_on macos: ">= :big_sur"`,
+		`app "My App.app"`,
+		`binary "#{appdir}/My App.app/Contents/MacOS/myapp", target: "myapp"`,
+		"zap trash: [\n    \"~/Library/Preferences/dev.bagboy.myapp.plist\",\n  ]",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Cask missing %q:\n%s", expected, content)
+		}
+	}
+
+	// Once the DMG is built the cask pins its checksum; a pkg replaces the app
+	os.WriteFile(filepath.Join("dist", "myapp-2.0.0.dmg"), []byte("fake dmg"), 0644)
+	cfg.Packages.Brew.Cask.Pkg = "MyApp.pkg"
+	cfg.Packages.Brew.Cask.PkgID = "com.acme.myapp"
+	cask, err := Cask(cfg, filepath.Join("dist", "myapp-2.0.0.dmg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := fmt.Sprintf("%x", sha256.Sum256([]byte("fake dmg")))
+	for _, expected := range []string{`sha256 "` + sum + `"`, `pkg "MyApp.pkg"`, `uninstall pkgutil: "com.acme.myapp"`} {
+		if !strings.Contains(cask, expected) {
+			t.Errorf("Cask missing %q:\n%s", expected, cask)
+		}
+	}
+	if strings.Contains(cask, "app \"") || strings.Contains(cask, "binary") {
+		t.Errorf("pkg cask should not link the app:\n%s", cask)
+	}
+
+	cfg.Packages.Brew.Cask.App = "Other.app"
+	if err := p.Validate(cfg); err == nil {
+		t.Error("Expected validation to fail with both app and pkg")
+	}
+	cfg.Packages.Brew.Cask = config.BrewCaskConfig{Enabled: true}
+	cfg.Binaries = map[string]string{"linux-amd64": binary}
+	if err := p.Validate(cfg); err == nil {
+		t.Error("Expected validation to fail without a macOS binary for the DMG")
+	}
 }
@@ -5,9 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"text/template"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
 )
 
 type Packager struct{}
@@ -22,7 +23,7 @@ func (p *Packager) Name() string {
 
 func (p *Packager) Validate(cfg *config.Config) error {
 	if cfg.Homepage == "" {
-		return fmt.Errorf("homepage is required for Cargo package")
+		return errors.NotConfiguredError("homepage is required for Cargo package")
 	}
 	return nil
 }
@@ -68,7 +69,7 @@ description = "{{.Description}}"
 homepage = "{{.Homepage}}"
 repository = "{{.Homepage}}"
 license = "{{.License}}"
-authors = ["{{.Author}}"]
+authors = [{{range $i, $person := .People}}{{if $i}}, {{end}}"{{$person}}"{{end}}]
 keywords = ["cli", "tool"]
 categories = ["command-line-utilities"]
 
@@ -83,7 +84,7 @@ dirs = "5.0"
 flate2 = "1.0"
 tar = "0.4"`
 
-	t, err := template.New("cargo").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "cargo/cargo", tmpl)
 	if err != nil {
 		return err
 	}
@@ -187,7 +188,7 @@ fn get_binary_name() -> String {
     format!("{BIN_NAME}-{os}-{arch}{ext}")
 }`
 
-	t, err := template.New("main").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "cargo/main", tmpl)
 	if err != nil {
 		return err
 	}
@@ -2,14 +2,21 @@ package chocolatey
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
-	"text/template"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+	"github.com/scttfrdmn/bagboy/pkg/spdx"
 )
 
 type Packager struct{}
@@ -23,8 +30,8 @@ func (p *Packager) Name() string {
 }
 
 func (p *Packager) Validate(cfg *config.Config) error {
-	if cfg.Author == "" {
-		return fmt.Errorf("author is required for chocolatey package")
+	if len(cfg.People()) == 0 {
+		return errors.NotConfiguredError("author is required for chocolatey package")
 	}
 	// Check for Windows binary
 	hasWindowsBinary := false
@@ -35,23 +42,18 @@ func (p *Packager) Validate(cfg *config.Config) error {
 		}
 	}
 	if !hasWindowsBinary {
-		return fmt.Errorf("no Windows binary specified for Chocolatey package")
+		return errors.NoPlatformBinaryError("no Windows binary specified for Chocolatey package")
 	}
 	return nil
 }
 
 func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
 	// Find Windows binary
-	var windowsBinary string
-	for arch, path := range cfg.Binaries {
-		if strings.HasPrefix(arch, "windows-") {
-			windowsBinary = path
-			break
-		}
-	}
-	if windowsBinary == "" {
+	platform := windowsPlatform(cfg)
+	if platform == "" {
 		return "", fmt.Errorf("no Windows binary found")
 	}
+	windowsBinary := cfg.Binaries[platform]
 
 	// Create build directory
 	buildDir := filepath.Join("dist", "chocolatey-build")
@@ -62,9 +64,34 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 
 	// Copy binary to tools directory
 	binaryDest := filepath.Join(toolsDir, cfg.Name+".exe")
-	if err := p.copyFile(windowsBinary, binaryDest); err != nil {
+	if err := paths.CopyExecutable(windowsBinary, binaryDest, packager.CopyOptions(cfg)); err != nil {
 		return "", fmt.Errorf("failed to copy binary: %w", err)
 	}
+	if err := packager.StageFiles(toolsDir, packager.ExtraFiles(cfg, "chocolatey", ""), packager.CopyOptions(cfg)); err != nil {
+		return "", err
+	}
+
+	// Moderators need to verify embedded binaries against their source
+	legalDir := filepath.Join(buildDir, "legal")
+	if err := os.MkdirAll(legalDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create legal directory: %w", err)
+	}
+	if err := p.createVerification(filepath.Join(legalDir, "VERIFICATION.txt"), binaryDest, platform, cfg); err != nil {
+		return "", fmt.Errorf("failed to generate VERIFICATION.txt: %w", err)
+	}
+	if license := licenseFile(cfg); license != "" {
+		if err := paths.CopyFile(license, filepath.Join(legalDir, "LICENSE.txt"), paths.CopyOptions{}); err != nil {
+			return "", fmt.Errorf("failed to copy license: %w", err)
+		}
+	} else {
+		fmt.Println("⚠️  No license file found for legal/LICENSE.txt - set packages.chocolatey.license_file")
+	}
+
+	if cfg.Packages.Chocolatey.Icon != "" {
+		if err := paths.CopyFile(cfg.Packages.Chocolatey.Icon, filepath.Join(buildDir, iconName(cfg)), paths.CopyOptions{}); err != nil {
+			return "", fmt.Errorf("failed to copy icon: %w", err)
+		}
+	}
 
 	// Generate .nuspec file
 	nuspecPath := filepath.Join(buildDir, cfg.Name+".nuspec")
@@ -95,7 +122,7 @@ func (p *Packager) createNuspec(path string, cfg *config.Config) error {
     <id>{{.Name}}</id>
     <version>{{.Version}}</version>
     <packageSourceUrl>{{.PackageSourceURL}}</packageSourceUrl>
-    <owners>{{.AuthorName}}</owners>
+    <owners>{{.Owners}}</owners>
     <title>{{.Name}}</title>
     <authors>{{.AuthorName}}</authors>
     <projectUrl>{{.Homepage}}</projectUrl>
@@ -103,15 +130,29 @@ func (p *Packager) createNuspec(path string, cfg *config.Config) error {
     <tags>{{.Name}} cli tool</tags>
     <summary>{{.Description}}</summary>
     <description>{{.Description}}</description>
+{{- if .LicenseExpression}}
+    <license type="expression">{{.LicenseExpression}}</license>
+{{- else}}
     <licenseUrl>{{.Homepage}}/blob/main/LICENSE</licenseUrl>
+{{- end}}
     <requireLicenseAcceptance>false</requireLicenseAcceptance>
+{{- if .IconURL}}
+    <iconUrl>{{.IconURL}}</iconUrl>
+{{- end}}
+{{- if .Icon}}
+    <icon>{{.Icon}}</icon>
+{{- end}}
   </metadata>
   <files>
     <file src="tools\**" target="tools" />
+    <file src="legal\**" target="legal" />
+{{- if .Icon}}
+    <file src="{{.Icon}}" target="" />
+{{- end}}
   </files>
 </package>`
 
-	t, err := template.New("nuspec").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "chocolatey/nuspec", tmpl)
 	if err != nil {
 		return err
 	}
@@ -124,14 +165,23 @@ func (p *Packager) createNuspec(path string, cfg *config.Config) error {
 
 	data := struct {
 		*config.Config
-		AuthorName       string
-		PackageSourceURL string
-		DocsURL          string
+		AuthorName        string
+		Owners            string
+		LicenseExpression string
+		PackageSourceURL  string
+		DocsURL           string
+		IconURL           string
+		Icon              string
 	}{
 		Config:           cfg,
 		AuthorName:       p.getAuthorName(cfg),
+		Owners:           strings.Join(config.AuthorNames(cfg.Maintainers()), ", "),
 		PackageSourceURL: cfg.Packages.Chocolatey.PackageSourceURL,
 		DocsURL:          cfg.Packages.Chocolatey.DocsURL,
+		IconURL:          cfg.Packages.Chocolatey.IconURL,
+	}
+	if cfg.Packages.Chocolatey.Icon != "" {
+		data.Icon = iconName(cfg)
 	}
 
 	if data.PackageSourceURL == "" {
@@ -140,7 +190,72 @@ func (p *Packager) createNuspec(path string, cfg *config.Config) error {
 	if data.DocsURL == "" {
 		data.DocsURL = cfg.Homepage
 	}
+	// NuGet only accepts expressions of known SPDX identifiers
+	if expr, err := spdx.Parse(cfg.License); err == nil {
+		data.LicenseExpression, _ = expr.NuGet()
+	}
+
+	return t.Execute(f, data)
+}
+
+// createVerification writes legal/VERIFICATION.txt, telling moderators
+// where the embedded binary is published and how to check it matches
+func (p *Packager) createVerification(path, binary, platform string, cfg *config.Config) error {
+	tmpl := `VERIFICATION
+Verification is intended to assist the Chocolatey moderators and community
+in verifying that this package's contents are trustworthy.
+
+tools\{{.Name}}.exe is the {{.Platform}} binary of {{.Name}} {{.Version}}, published by
+the {{.Name}} project{{if .Homepage}} ({{.Homepage}}){{end}}.
+{{- if .URL}}
+
+1. Download the binary from the release:
+
+   {{.URL}}
+
+2. Compare its checksum with the embedded tools\{{.Name}}.exe:
+
+   Get-FileHash {{.AssetName}} -Algorithm SHA256
+   Get-FileHash tools\{{.Name}}.exe -Algorithm SHA256
+{{- end}}
+
+   checksum type: sha256
+   checksum: {{.SHA256}}
+
+legal\LICENSE.txt is the project's license ({{.License}}).
+`
+
+	t, err := packager.ParseTemplate(cfg, "chocolatey/verification", tmpl)
+	if err != nil {
+		return err
+	}
+
+	sum, err := fileSHA256(binary)
+	if err != nil {
+		return err
+	}
+	// Without a release URL moderators can still check the checksum
+	url, _ := packager.AssetURL(cfg, platform)
 
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data := struct {
+		*config.Config
+		Platform  string
+		AssetName string
+		URL       string
+		SHA256    string
+	}{
+		Config:    cfg,
+		Platform:  platform,
+		AssetName: packager.AssetName(cfg, platform),
+		URL:       url,
+		SHA256:    strings.ToUpper(sum),
+	}
 	return t.Execute(f, data)
 }
 
@@ -155,9 +270,12 @@ $exePath = Join-Path $toolsDir $exeName
 Install-BinFile -Name $packageName -Path $exePath
 
 Write-Host "{{.Name}} has been installed successfully!" -ForegroundColor Green
-Write-Host "You can now use '{{.Name}}' from any command prompt." -ForegroundColor Green`
+Write-Host "You can now use '{{.Name}}' from any command prompt." -ForegroundColor Green
+{{- range .Message}}
+{{.}}
+{{- end}}`
 
-	t, err := template.New("install").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "chocolatey/install", tmpl)
 	if err != nil {
 		return err
 	}
@@ -168,7 +286,14 @@ Write-Host "You can now use '{{.Name}}' from any command prompt." -ForegroundCol
 	}
 	defer f.Close()
 
-	return t.Execute(f, cfg)
+	data := struct {
+		*config.Config
+		Message []string
+	}{
+		Config:  cfg,
+		Message: packager.WriteHost(cfg.PostInstall.MessageFor("chocolatey")),
+	}
+	return t.Execute(f, data)
 }
 
 func (p *Packager) createUninstallScript(path string, cfg *config.Config) error {
@@ -180,7 +305,7 @@ Uninstall-BinFile -Name $packageName
 
 Write-Host "{{.Name}} has been uninstalled successfully!" -ForegroundColor Green`
 
-	t, err := template.New("uninstall").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "chocolatey/uninstall", tmpl)
 	if err != nil {
 		return err
 	}
@@ -212,9 +337,12 @@ func (p *Packager) buildPackage(ctx context.Context, buildDir string, cfg *confi
 }
 
 func (p *Packager) buildWithChoco(ctx context.Context, buildDir, outputPath string, cfg *config.Config) (string, error) {
-	nuspecPath := filepath.Join(buildDir, cfg.Name+".nuspec")
-	
-	cmd := exec.CommandContext(ctx, "choco", "pack", nuspecPath, "--outputdirectory", filepath.Dir(outputPath))
+	nuspecPath, outputDir, err := toolPaths(buildDir, outputPath, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := exec.CommandContext(ctx, "choco", "pack", nuspecPath, "--outputdirectory", outputDir)
 	cmd.Dir = buildDir
 	
 	if output, err := cmd.CombinedOutput(); err != nil {
@@ -225,9 +353,12 @@ func (p *Packager) buildWithChoco(ctx context.Context, buildDir, outputPath stri
 }
 
 func (p *Packager) buildWithNuget(ctx context.Context, buildDir, outputPath string, cfg *config.Config) (string, error) {
-	nuspecPath := filepath.Join(buildDir, cfg.Name+".nuspec")
-	
-	cmd := exec.CommandContext(ctx, "nuget", "pack", nuspecPath, "-OutputDirectory", filepath.Dir(outputPath))
+	nuspecPath, outputDir, err := toolPaths(buildDir, outputPath, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := exec.CommandContext(ctx, "nuget", "pack", nuspecPath, "-OutputDirectory", outputDir)
 	cmd.Dir = buildDir
 	
 	if output, err := cmd.CombinedOutput(); err != nil {
@@ -237,39 +368,93 @@ func (p *Packager) buildWithNuget(ctx context.Context, buildDir, outputPath stri
 	return outputPath, nil
 }
 
+// toolPaths returns the nuspec and output directory as choco and nuget,
+// run from buildDir, need them
+func toolPaths(buildDir, outputPath string, cfg *config.Config) (string, string, error) {
+	nuspecPath, err := paths.Tool(filepath.Join(buildDir, cfg.Name+".nuspec"))
+	if err != nil {
+		return "", "", err
+	}
+	outputDir, err := paths.Tool(filepath.Dir(outputPath))
+	if err != nil {
+		return "", "", err
+	}
+	return nuspecPath, outputDir, nil
+}
+
 func (p *Packager) buildManually(buildDir, outputPath string, cfg *config.Config) (string, error) {
 	// Ensure output directory exists
 	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 		return "", fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	// Create a simple zip file (Chocolatey packages are essentially zip files with .nupkg extension)
-	if _, err := exec.LookPath("zip"); err == nil {
-		cmd := exec.Command("zip", "-r", outputPath, ".")
-		cmd.Dir = buildDir
-		
-		if output, err := cmd.CombinedOutput(); err != nil {
-			return "", fmt.Errorf("zip failed: %w\nOutput: %s", err, output)
-		}
-		
-		return outputPath, nil
+	// Chocolatey packages are essentially zip files with a .nupkg extension
+	if err := paths.ZipDir(buildDir, outputPath); err != nil {
+		return "", fmt.Errorf("failed to create package: %w", err)
 	}
+	return outputPath, nil
+}
 
-	return "", fmt.Errorf("Chocolatey build tools not found - install Chocolatey CLI, NuGet CLI, or zip")
+// windowsPlatform returns the Windows platform to package, preferring
+// windows-amd64
+func windowsPlatform(cfg *config.Config) string {
+	var platforms []string
+	for platform := range cfg.Binaries {
+		if strings.HasPrefix(platform, "windows-") {
+			platforms = append(platforms, platform)
+		}
+	}
+	if len(platforms) == 0 {
+		return ""
+	}
+	sort.Strings(platforms)
+	for _, platform := range platforms {
+		if platform == "windows-amd64" {
+			return platform
+		}
+	}
+	return platforms[0]
 }
 
-func (p *Packager) getAuthorName(cfg *config.Config) string {
-	if strings.Contains(cfg.Author, "<") {
-		parts := strings.Split(cfg.Author, "<")
-		return strings.TrimSpace(parts[0])
+// licenseFile returns packages.chocolatey.license_file or the first
+// license file found in the project root
+func licenseFile(cfg *config.Config) string {
+	if cfg.Packages.Chocolatey.LicenseFile != "" {
+		return cfg.Packages.Chocolatey.LicenseFile
+	}
+	for _, name := range []string{"LICENSE", "LICENSE.txt", "LICENSE.md", "COPYING"} {
+		if info, err := os.Stat(name); err == nil && info.Mode().IsRegular() {
+			return name
+		}
 	}
-	return cfg.Author
+	return ""
 }
 
-func (p *Packager) copyFile(src, dst string) error {
-	data, err := os.ReadFile(src)
+// iconName is the file name of the embedded icon in the package
+func iconName(cfg *config.Config) string {
+	return "icon" + strings.ToLower(filepath.Ext(cfg.Packages.Chocolatey.Icon))
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
 	if err != nil {
-		return err
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// getAuthorName returns the nuspec authors: every person with the author
+// role
+func (p *Packager) getAuthorName(cfg *config.Config) string {
+	authors := cfg.PeopleWithRole(config.RoleAuthor)
+	if len(authors) == 0 {
+		return cfg.PrimaryAuthor().Name
 	}
-	return os.WriteFile(dst, data, 0755)
+	return strings.Join(config.AuthorNames(authors), ", ")
 }
@@ -1,12 +1,15 @@
 package chocolatey
 
 import (
+	"archive/zip"
 	"context"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
 )
 
 func TestChocolateyPackager(t *testing.T) {
@@ -105,7 +108,7 @@ func TestChocolateyPack(t *testing.T) {
 	
 	// Should not error even if choco/nuget/zip is not available
 	// The function should return an error about tools not being found
-	if err != nil && !contains(err.Error(), "Chocolatey build tools not found") && !contains(err.Error(), "zip failed") {
+	if err != nil && !contains(err.Error(), "Chocolatey build tools not found") {
 		t.Errorf("Pack() unexpected error = %v", err)
 	}
 
@@ -169,6 +172,7 @@ func TestCreateNuspec(t *testing.T) {
 		"<description>Test application</description>",
 		"<packageSourceUrl>https://github.com/test/testapp</packageSourceUrl>",
 		"<docsUrl>https://example.com/docs</docsUrl>",
+		"<license type=\"expression\">MIT</license>",
 	}
 
 	for _, element := range requiredElements {
@@ -176,6 +180,16 @@ func TestCreateNuspec(t *testing.T) {
 			t.Errorf("Nuspec file missing required element: %s", element)
 		}
 	}
+
+	// Custom licenses cannot be expressions and fall back to licenseUrl
+	cfg.License = "LicenseRef-Proprietary"
+	if err := packager.createNuspec(nuspecPath, cfg); err != nil {
+		t.Fatal(err)
+	}
+	content, _ = os.ReadFile(nuspecPath)
+	if !contains(string(content), "<licenseUrl>https://example.com/blob/main/LICENSE</licenseUrl>") || contains(string(content), "<license ") {
+		t.Errorf("Expected licenseUrl for custom license:\n%s", content)
+	}
 }
 
 func TestCreateInstallScript(t *testing.T) {
@@ -218,6 +232,22 @@ func TestCreateInstallScript(t *testing.T) {
 	}
 }
 
+func TestCreateInstallScript_PostInstallMessage(t *testing.T) {
+	scriptPath := filepath.Join(t.TempDir(), "chocolateyInstall.ps1")
+	cfg := &config.Config{
+		Name:        "testapp",
+		PostInstall: config.PostInstallConfig{Message: "Run 'testapp init' to get started."},
+	}
+
+	if err := New().createInstallScript(scriptPath, cfg); err != nil {
+		t.Fatalf("createInstallScript() error = %v", err)
+	}
+	content, _ := os.ReadFile(scriptPath)
+	if !contains(string(content), "\nWrite-Host 'Run ''testapp init'' to get started.'") {
+		t.Errorf("Install script missing post-install message:\n%s", content)
+	}
+}
+
 func TestCreateUninstallScript(t *testing.T) {
 	packager := New()
 	
@@ -259,8 +289,6 @@ func TestCreateUninstallScript(t *testing.T) {
 }
 
 func TestCopyFile(t *testing.T) {
-	packager := New()
-	
 	tmpDir := t.TempDir()
 	srcPath := filepath.Join(tmpDir, "source.exe")
 	dstPath := filepath.Join(tmpDir, "dest.exe")
@@ -270,8 +298,8 @@ func TestCopyFile(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := packager.copyFile(srcPath, dstPath); err != nil {
-		t.Errorf("copyFile() error = %v", err)
+	if err := paths.CopyExecutable(srcPath, dstPath, paths.CopyOptions{}); err != nil {
+		t.Errorf("CopyExecutable() error = %v", err)
 	}
 
 	// Check destination file
@@ -296,12 +324,10 @@ func TestCopyFile(t *testing.T) {
 }
 
 func TestCopyFile_Error(t *testing.T) {
-	packager := New()
-	
 	// Test with non-existent source file
-	err := packager.copyFile("/non/existent/file", "/tmp/dest")
+	err := paths.CopyExecutable("/non/existent/file", "/tmp/dest", paths.CopyOptions{})
 	if err == nil {
-		t.Error("copyFile() should fail with non-existent source file")
+		t.Error("CopyExecutable() should fail with non-existent source file")
 	}
 }
 
@@ -358,11 +384,22 @@ func TestBuildWithNuget(t *testing.T) {
 }
 
 func TestBuildPackage_NoTools(t *testing.T) {
+	for _, tool := range []string{"choco", "nuget"} {
+		if _, err := exec.LookPath(tool); err == nil {
+			t.Skipf("%s is installed", tool)
+		}
+	}
+
 	packager := New()
 	
 	tmpDir := t.TempDir()
 	buildDir := filepath.Join(tmpDir, "build")
-	os.MkdirAll(buildDir, 0755)
+	os.MkdirAll(filepath.Join(buildDir, "tools"), 0755)
+	os.WriteFile(filepath.Join(buildDir, "tools", "chocolateyinstall.ps1"), []byte("# install"), 0644)
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
 	
 	cfg := &config.Config{
 		Name:    "testapp",
@@ -370,16 +407,18 @@ func TestBuildPackage_NoTools(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	_, err := packager.buildPackage(ctx, buildDir, cfg)
-	
-	// Should return error about missing tools or zip failure
-	if err == nil {
-		t.Error("buildPackage() should fail when no Chocolatey build tools are available")
+	outputPath, err := packager.buildPackage(ctx, buildDir, cfg)
+	if err != nil {
+		t.Fatalf("buildPackage() without Chocolatey tools should zip the package itself, got: %v", err)
 	}
-	
-	// Accept either missing tools error or zip failure
-	if !contains(err.Error(), "Chocolatey build tools not found") && !contains(err.Error(), "zip failed") {
-		t.Errorf("Expected 'Chocolatey build tools not found' or 'zip failed' error, got: %v", err)
+
+	r, err := zip.OpenReader(outputPath)
+	if err != nil {
+		t.Fatalf("package is not a zip: %v", err)
+	}
+	defer r.Close()
+	if len(r.File) != 1 || r.File[0].Name != "tools/chocolateyinstall.ps1" {
+		t.Errorf("package entries should use forward slashes, got %v", r.File)
 	}
 }
 
@@ -420,6 +459,10 @@ func TestGetAuthorName(t *testing.T) {
 }
 
 func TestChocolateyPack_MissingBinary(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(t.TempDir())
+
 	packager := New()
 	
 	cfg := &config.Config{
@@ -454,3 +497,81 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestCreateVerification(t *testing.T) {
+	tmpDir := t.TempDir()
+	binaryPath := filepath.Join(tmpDir, "testapp.exe")
+	os.WriteFile(binaryPath, []byte("fake exe content"), 0755)
+
+	cfg := &config.Config{
+		Name:     "testapp",
+		Version:  "1.0.0",
+		License:  "MIT",
+		Binaries: map[string]string{"windows-amd64": binaryPath},
+		GitHub:   config.GitHubConfig{Owner: "test", Repo: "testapp"},
+	}
+
+	path := filepath.Join(tmpDir, "VERIFICATION.txt")
+	if err := New().createVerification(path, binaryPath, "windows-amd64", cfg); err != nil {
+		t.Fatalf("createVerification() error = %v", err)
+	}
+	content, _ := os.ReadFile(path)
+	for _, expected := range []string{
+		"https://github.com/test/testapp/releases/download/v1.0.0/testapp-windows-amd64.exe",
+		"Get-FileHash testapp-windows-amd64.exe -Algorithm SHA256",
+		"checksum: A77AACAFD52A24201EACD6AC380AB301A71651B102C4F7684C056F638CD69FBF",
+		"legal\\LICENSE.txt is the project's license (MIT)",
+	} {
+		if !contains(string(content), expected) {
+			t.Errorf("VERIFICATION.txt missing %q:\n%s", expected, content)
+		}
+	}
+}
+
+func TestChocolateyPack_Legal(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, "testapp.exe"), []byte("fake exe content"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "LICENSE"), []byte("MIT License"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "logo.PNG"), []byte("png"), 0644)
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	cfg := &config.Config{
+		Name:     "testapp",
+		Version:  "1.0.0",
+		License:  "MIT",
+		Author:   "Test Author",
+		Binaries: map[string]string{"windows-arm64": "testapp.exe", "windows-amd64": "testapp.exe"},
+		Packages: config.PackagesConfig{
+			Chocolatey: config.ChocolateyConfig{Icon: "logo.PNG", IconURL: "https://example.com/icon.png"},
+		},
+	}
+	t.Setenv("PATH", "")
+	if _, err := New().Pack(context.Background(), cfg); err != nil {
+		t.Fatalf("Pack() error = %v", err)
+	}
+
+	buildDir := filepath.Join("dist", "chocolatey-build")
+	if content, _ := os.ReadFile(filepath.Join(buildDir, "legal", "LICENSE.txt")); string(content) != "MIT License" {
+		t.Errorf("Unexpected LICENSE.txt %q", content)
+	}
+	if content, _ := os.ReadFile(filepath.Join(buildDir, "legal", "VERIFICATION.txt")); !contains(string(content), "windows-amd64 binary") {
+		t.Errorf("Expected the windows-amd64 binary to be verified:\n%s", content)
+	}
+	if _, err := os.Stat(filepath.Join(buildDir, "icon.png")); err != nil {
+		t.Errorf("Expected embedded icon: %v", err)
+	}
+	nuspec, _ := os.ReadFile(filepath.Join(buildDir, "testapp.nuspec"))
+	for _, expected := range []string{
+		"<iconUrl>https://example.com/icon.png</iconUrl>",
+		"<icon>icon.png</icon>",
+		`<file src="legal\**" target="legal" />`,
+		`<file src="icon.png" target="" />`,
+	} {
+		if !contains(string(nuspec), expected) {
+			t.Errorf("Nuspec missing %q:\n%s", expected, nuspec)
+		}
+	}
+}
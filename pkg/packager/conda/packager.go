@@ -0,0 +1,438 @@
+package conda
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/template"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+)
+
+// Builders are the tools that can build the package, in the order they
+// are looked for
+var Builders = []string{"rattler-build", "conda-build"}
+
+// subdirs maps Go platforms to conda subdirs and the meta.yaml selector
+// that picks each one
+var subdirs = map[string]struct{ Subdir, Selector string }{
+	"linux-amd64":   {"linux-64", "linux and x86_64"},
+	"linux-arm64":   {"linux-aarch64", "linux and aarch64"},
+	"linux-ppc64le": {"linux-ppc64le", "linux and ppc64le"},
+	"darwin-amd64":  {"osx-64", "osx and x86_64"},
+	"darwin-arm64":  {"osx-arm64", "osx and arm64"},
+	"windows-amd64": {"win-64", "win and x86_64"},
+	"windows-arm64": {"win-arm64", "win and arm64"},
+}
+
+type Packager struct{}
+
+func New() *Packager {
+	return &Packager{}
+}
+
+func (p *Packager) Name() string {
+	return "conda"
+}
+
+func (p *Packager) Validate(cfg *config.Config) error {
+	platforms := Platforms(cfg)
+	if len(platforms) == 0 {
+		return errors.NoPlatformBinaryError("a linux, darwin or windows amd64 or arm64 binary is required for conda packages")
+	}
+	if builder := cfg.Packages.Conda.Builder; builder != "" && !validBuilder(builder) {
+		return errors.InvalidConfigError("conda.builder", fmt.Sprintf("must be one of %s", strings.Join(Builders, ", ")))
+	}
+	if _, err := packager.AssetURL(cfg, platforms[0]); err != nil {
+		return errors.NotConfiguredError("installer.base_url, github.owner and github.repo, or gitlab.release is required for conda sources")
+	}
+	return nil
+}
+
+func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
+	return packager.Primary(p.PackArtifacts(ctx, cfg))
+}
+
+// PackArtifacts writes the recipe and, when a builder is installed and
+// there is a binary for this host, builds the package. The package comes
+// first, then the recipe.
+func (p *Packager) PackArtifacts(ctx context.Context, cfg *config.Config) ([]packager.Artifact, error) {
+	if len(Platforms(cfg)) == 0 {
+		return nil, fmt.Errorf("no binary for a conda platform found")
+	}
+
+	condaDir := filepath.Join("dist", "conda")
+	recipeDir := filepath.Join(condaDir, "recipe")
+	if err := os.MkdirAll(recipeDir, 0755); err != nil {
+		return nil, err
+	}
+	if err := p.createRecipe(recipeDir, cfg); err != nil {
+		return nil, err
+	}
+	recipe := packager.Artifact{Path: recipeDir, Kind: packager.KindManifest}
+
+	builder, err := findBuilder(cfg)
+	if err != nil {
+		return nil, err
+	}
+	platform := runtime.GOOS + "-" + runtime.GOARCH
+	binary, ok := cfg.Binaries[platform]
+	if builder == "" || !ok {
+		return []packager.Artifact{recipe}, nil
+	}
+
+	output, err := p.build(ctx, cfg, builder, filepath.Join(condaDir, "build"), binary)
+	if err != nil {
+		return nil, err
+	}
+	return []packager.Artifact{{Path: output, OS: runtime.GOOS, Arch: runtime.GOARCH}, recipe}, nil
+}
+
+// Platforms returns the configured platforms conda has a subdir for, in
+// sorted order
+func Platforms(cfg *config.Config) []string {
+	var platforms []string
+	for _, goos := range []string{"linux", "darwin", "windows"} {
+		for _, binary := range packager.PlatformBinaries(cfg, goos) {
+			platform := goos + "-" + binary.Arch
+			if _, ok := subdirs[platform]; ok {
+				platforms = append(platforms, platform)
+			}
+		}
+	}
+	return platforms
+}
+
+// Subdir returns the conda subdir for a Go platform, such as osx-arm64
+// for darwin-arm64
+func Subdir(platform string) string {
+	return subdirs[platform].Subdir
+}
+
+// findBuilder returns the configured builder, or the first one installed.
+// It returns "" when none is installed and none was asked for.
+func findBuilder(cfg *config.Config) (string, error) {
+	if builder := cfg.Packages.Conda.Builder; builder != "" {
+		if _, err := exec.LookPath(builder); err != nil {
+			return "", errors.ToolNotFoundError(fmt.Sprintf("%s not found - required to build conda packages", builder))
+		}
+		return builder, nil
+	}
+	for _, builder := range Builders {
+		if _, err := exec.LookPath(builder); err == nil {
+			return builder, nil
+		}
+	}
+	return "", nil
+}
+
+func validBuilder(builder string) bool {
+	for _, b := range Builders {
+		if b == builder {
+			return true
+		}
+	}
+	return false
+}
+
+// source is one platform's binary in the recipe
+type source struct {
+	Selector string
+	URL      string
+	SHA256   string
+}
+
+// createRecipe writes a feedstock recipe that fetches each platform's
+// binary from the release: meta.yaml, build.sh and bld.bat
+func (p *Packager) createRecipe(recipeDir string, cfg *config.Config) error {
+	var sources []source
+	hasUnix, hasWindows := false, false
+	for _, platform := range Platforms(cfg) {
+		sum, err := packager.BinarySHA256(cfg, platform)
+		if err != nil {
+			return err
+		}
+		url, err := packager.AssetURL(cfg, platform)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, source{Selector: subdirs[platform].Selector, URL: url, SHA256: sum})
+		if strings.HasPrefix(platform, "windows-") {
+			hasWindows = true
+		} else {
+			hasUnix = true
+		}
+	}
+
+	tmpl := `package:
+  name: {{.PackageName}}
+  version: "{{.Version}}"
+
+source:
+{{- range .Sources}}
+  url: {{.URL}}  # [{{.Selector}}]
+  sha256: {{.SHA256}}  # [{{.Selector}}]
+{{- end}}
+  fname: {{.Name}}  # [not win]
+  fname: {{.Name}}.exe  # [win]
+` + recipeBody
+
+	t, err := packager.ParseTemplate(cfg, "conda/meta.yaml", tmpl)
+	if err != nil {
+		return err
+	}
+	data := p.recipeData(cfg)
+	data.Sources = sources
+
+	if err := executeTo(filepath.Join(recipeDir, "meta.yaml"), t, data); err != nil {
+		return err
+	}
+	if hasUnix {
+		if err := writeBuildScript(filepath.Join(recipeDir, "build.sh"), cfg); err != nil {
+			return err
+		}
+	}
+	if hasWindows {
+		if err := writeBatchScript(filepath.Join(recipeDir, "bld.bat"), cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// build stages a recipe that takes the local binary as its source and
+// runs builder on it, returning the built package
+func (p *Packager) build(ctx context.Context, cfg *config.Config, builder, buildDir, binary string) (string, error) {
+	if err := os.RemoveAll(buildDir); err != nil {
+		return "", err
+	}
+	exe := cfg.Name
+	if runtime.GOOS == "windows" {
+		exe += ".exe"
+	}
+	if err := paths.CopyExecutable(binary, filepath.Join(buildDir, "src", exe), packager.CopyOptions(cfg)); err != nil {
+		return "", fmt.Errorf("failed to copy binary: %w", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		// rattler-build looks for build.bat, conda-build for bld.bat
+		script := "bld.bat"
+		if builder == "rattler-build" {
+			script = "build.bat"
+		}
+		if err := writeBatchScript(filepath.Join(buildDir, script), cfg); err != nil {
+			return "", err
+		}
+	} else if err := writeBuildScript(filepath.Join(buildDir, "build.sh"), cfg); err != nil {
+		return "", err
+	}
+
+	data := p.recipeData(cfg)
+	outputDir := filepath.Join(filepath.Dir(buildDir), "output")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", err
+	}
+	// The builders resolve relative paths from their own working directory
+	absOutput, err := filepath.Abs(outputDir)
+	if err != nil {
+		return "", err
+	}
+
+	var args []string
+	switch builder {
+	case "rattler-build":
+		if err := p.createRattlerRecipe(filepath.Join(buildDir, "recipe.yaml"), cfg, data); err != nil {
+			return "", err
+		}
+		args = []string{"build", "--recipe", buildDir, "--output-dir", absOutput}
+	default:
+		t, err := packager.ParseTemplate(cfg, "conda/build/meta.yaml", `package:
+  name: {{.PackageName}}
+  version: "{{.Version}}"
+
+source:
+  path: src
+`+recipeBody)
+		if err != nil {
+			return "", err
+		}
+		if err := executeTo(filepath.Join(buildDir, "meta.yaml"), t, data); err != nil {
+			return "", err
+		}
+		args = []string{"build", buildDir, "--output-folder", absOutput, "--no-anaconda-upload"}
+	}
+	for _, channel := range data.Channels {
+		args = append(args, "-c", channel)
+	}
+
+	cmd := exec.CommandContext(ctx, builder, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("%s failed: %w\nOutput: %s", builder, err, output)
+	}
+
+	subdir := Subdir(runtime.GOOS + "-" + runtime.GOARCH)
+	for _, ext := range []string{".conda", ".tar.bz2"} {
+		matches, _ := filepath.Glob(filepath.Join(outputDir, subdir, data.PackageName+"-"+cfg.Version+"-*"+ext))
+		if len(matches) > 0 {
+			return matches[0], nil
+		}
+	}
+	return "", fmt.Errorf("%s did not produce a package in %s", builder, filepath.Join(outputDir, subdir))
+}
+
+// createRattlerRecipe writes the recipe.yaml rattler-build reads for the
+// local build
+func (p *Packager) createRattlerRecipe(path string, cfg *config.Config, data recipeData) error {
+	tmpl := `package:
+  name: {{.PackageName}}
+  version: "{{.Version}}"
+
+source:
+  - path: src
+
+build:
+  number: 0
+  dynamic_linking:
+    binary_relocation: false
+{{- if .Depends}}
+
+requirements:
+  run:
+{{- range .Depends}}
+    - {{.}}
+{{- end}}
+{{- end}}
+
+about:
+{{- if .Homepage}}
+  homepage: {{.Homepage}}
+{{- end}}
+{{- if .License}}
+  license: {{.License}}
+{{- end}}
+  summary: {{toJSON .Summary}}
+`
+	t, err := packager.ParseTemplate(cfg, "conda/recipe.yaml", tmpl)
+	if err != nil {
+		return err
+	}
+	return executeTo(path, t, data)
+}
+
+// recipeBody is the part of meta.yaml after the source, shared by the
+// feedstock recipe and the local build
+const recipeBody = `
+build:
+  number: 0
+  binary_relocation: false
+{{- if .Depends}}
+
+requirements:
+  run:
+{{- range .Depends}}
+    - {{.}}
+{{- end}}
+{{- end}}
+
+test:
+  commands:
+    - command -v {{.Name}}  # [not win]
+    - where {{.Name}}  # [win]
+
+about:
+{{- if .Homepage}}
+  home: {{.Homepage}}
+{{- end}}
+{{- if .License}}
+  license: {{.License}}
+{{- end}}
+  summary: {{toJSON .Summary}}
+{{- if .Description}}
+  description: {{toJSON .Description}}
+{{- end}}
+{{- if .DevURL}}
+  dev_url: {{.DevURL}}
+{{- end}}
+{{- if .Maintainers}}
+
+extra:
+  recipe-maintainers:
+{{- range .Maintainers}}
+    - {{.}}
+{{- end}}
+{{- end}}
+`
+
+type recipeData struct {
+	*config.Config
+	PackageName string
+	Summary     string
+	DevURL      string
+	Depends     []string
+	Channels    []string
+	Maintainers []string
+	Sources     []source
+}
+
+func (p *Packager) recipeData(cfg *config.Config) recipeData {
+	conda := cfg.Packages.Conda
+	data := recipeData{
+		Config:      cfg,
+		PackageName: strings.ToLower(cfg.Name),
+		Summary:     summary(cfg),
+		Depends:     conda.Depends,
+		Channels:    conda.Channels,
+		Maintainers: conda.Maintainers,
+	}
+	if len(data.Channels) == 0 {
+		data.Channels = []string{"conda-forge"}
+	}
+	if len(data.Maintainers) == 0 && cfg.GitHub.Owner != "" {
+		data.Maintainers = []string{cfg.GitHub.Owner}
+	}
+	if cfg.GitHub.Owner != "" && cfg.GitHub.Repo != "" {
+		data.DevURL = fmt.Sprintf("https://github.com/%s/%s", cfg.GitHub.Owner, cfg.GitHub.Repo)
+	}
+	return data
+}
+
+// executeTo executes t with data into a new file at path
+func executeTo(path string, t *template.Template, data interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return t.Execute(f, data)
+}
+
+func writeBuildScript(path string, cfg *config.Config) error {
+	script := fmt.Sprintf(`#!/bin/bash
+set -euo pipefail
+
+mkdir -p "${PREFIX}/bin"
+install -m 755 "${SRC_DIR}/%[1]s" "${PREFIX}/bin/%[1]s"
+`, cfg.Name)
+	return os.WriteFile(path, []byte(script), 0755)
+}
+
+func writeBatchScript(path string, cfg *config.Config) error {
+	script := fmt.Sprintf("@echo on\r\n"+
+		"if not exist \"%%LIBRARY_BIN%%\" mkdir \"%%LIBRARY_BIN%%\"\r\n"+
+		"copy /Y \"%%SRC_DIR%%\\%[1]s.exe\" \"%%LIBRARY_BIN%%\\%[1]s.exe\"\r\n"+
+		"if errorlevel 1 exit 1\r\n", cfg.Name)
+	return os.WriteFile(path, []byte(script), 0644)
+}
+
+// summary returns the first line of the description
+func summary(cfg *config.Config) string {
+	line, _, _ := strings.Cut(cfg.Description, "\n")
+	return strings.TrimSpace(line)
+}
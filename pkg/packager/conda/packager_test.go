@@ -0,0 +1,174 @@
+package conda
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+)
+
+func testConfig(t *testing.T, testDir string) *config.Config {
+	t.Helper()
+	binaries := make(map[string]string)
+	for _, platform := range []string{"linux-amd64", "darwin-arm64", "windows-amd64", runtime.GOOS + "-" + runtime.GOARCH} {
+		path := filepath.Join(testDir, "testapp-"+platform)
+		if err := os.WriteFile(path, []byte("fake binary"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		binaries[platform] = path
+	}
+	return &config.Config{
+		Name:        "testapp",
+		Version:     "1.0.0",
+		Description: "Test application for science\nLonger description.",
+		Homepage:    "https://github.com/test/testapp",
+		License:     "MIT",
+		Binaries:    binaries,
+		GitHub:      config.GitHubConfig{Owner: "test", Repo: "testapp"},
+		Packages: config.PackagesConfig{
+			Conda: config.CondaConfig{Depends: []string{"git"}},
+		},
+	}
+}
+
+func TestCondaPackager(t *testing.T) {
+	testDir := t.TempDir()
+	cfg := testConfig(t, testDir)
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	// Without a builder on PATH only the recipe is written
+	t.Setenv("PATH", testDir)
+
+	p := New()
+	if err := p.Validate(cfg); err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+
+	artifacts, err := p.PackArtifacts(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	recipeDir := filepath.Join("dist", "conda", "recipe")
+	if len(artifacts) != 1 || artifacts[0].Path != recipeDir || artifacts[0].Kind != packager.KindManifest {
+		t.Fatalf("Unexpected artifacts %+v", artifacts)
+	}
+
+	meta, err := os.ReadFile(filepath.Join(recipeDir, "meta.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"name: testapp",
+		`version: "1.0.0"`,
+		"url: https://github.com/test/testapp/releases/download/v1.0.0/testapp-linux-amd64  # [linux and x86_64]",
+		"url: https://github.com/test/testapp/releases/download/v1.0.0/testapp-darwin-arm64  # [osx and arm64]",
+		"url: https://github.com/test/testapp/releases/download/v1.0.0/testapp-windows-amd64.exe  # [win and x86_64]",
+		"fname: testapp.exe  # [win]",
+		"binary_relocation: false",
+		"    - git",
+		"license: MIT",
+		`summary: "Test application for science"`,
+		"dev_url: https://github.com/test/testapp",
+		"recipe-maintainers:\n    - test",
+	} {
+		if !strings.Contains(string(meta), want) {
+			t.Errorf("meta.yaml missing %q:\n%s", want, meta)
+		}
+	}
+
+	for _, script := range []string{"build.sh", "bld.bat"} {
+		if _, err := os.Stat(filepath.Join(recipeDir, script)); err != nil {
+			t.Errorf("%s not created", script)
+		}
+	}
+}
+
+func TestCondaPackager_Build(t *testing.T) {
+	subdir := Subdir(runtime.GOOS + "-" + runtime.GOARCH)
+	if runtime.GOOS == "windows" || subdir == "" {
+		t.Skip("fake builder is a shell script for a known subdir")
+	}
+
+	testDir := t.TempDir()
+	cfg := testConfig(t, testDir)
+	cfg.Packages.Conda.Builder = "rattler-build"
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	// The fake builder drops a package where rattler-build would
+	binDir := filepath.Join(testDir, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := fmt.Sprintf("#!/bin/sh\nmkdir -p \"$5/%[1]s\" && touch \"$5/%[1]s/testapp-1.0.0-h0_0.conda\"\n", subdir)
+	if err := os.WriteFile(filepath.Join(binDir, "rattler-build"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	artifacts, err := New().PackArtifacts(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	want := filepath.Join("dist", "conda", "output", subdir, "testapp-1.0.0-h0_0.conda")
+	if len(artifacts) != 2 || artifacts[0].Path != want || artifacts[0].OS != runtime.GOOS {
+		t.Fatalf("Unexpected artifacts %+v, expected %s first", artifacts, want)
+	}
+
+	recipe, err := os.ReadFile(filepath.Join("dist", "conda", "build", "recipe.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(recipe), "- path: src") {
+		t.Errorf("Unexpected recipe.yaml:\n%s", recipe)
+	}
+	if _, err := os.Stat(filepath.Join("dist", "conda", "build", "src", "testapp")); err != nil {
+		t.Error("binary not staged for the build")
+	}
+}
+
+func TestCondaPackager_Validate(t *testing.T) {
+	cfg := &config.Config{Name: "testapp", Binaries: map[string]string{"freebsd-amd64": "bin/testapp"}}
+	if err := New().Validate(cfg); err == nil {
+		t.Error("Expected validation error without a conda platform binary")
+	}
+
+	cfg.Binaries = map[string]string{"linux-amd64": "bin/testapp"}
+	cfg.Packages.Conda.Builder = "mamba"
+	if err := New().Validate(cfg); err == nil {
+		t.Error("Expected validation error for an unknown builder")
+	}
+
+	cfg.Packages.Conda.Builder = ""
+	if err := New().Validate(cfg); !errors.HasCode(err, errors.CodeNotConfigured) {
+		t.Errorf("Expected a not configured error without a download URL, got %v", err)
+	}
+}
+
+func TestCondaPackager_MissingBuilder(t *testing.T) {
+	testDir := t.TempDir()
+	cfg := testConfig(t, testDir)
+	cfg.Packages.Conda.Builder = "conda-build"
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+	t.Setenv("PATH", testDir)
+
+	_, err := New().Pack(context.Background(), cfg)
+	if !errors.IsType(err, errors.ErrorTypeDependency) {
+		t.Errorf("Expected a missing tool error, got %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+package packager
+
+import (
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+)
+
+// CopyOptions returns how files are copied into packages for cfg
+func CopyOptions(cfg *config.Config) paths.CopyOptions {
+	return paths.CopyOptions{
+		Timestamps:     cfg.Preserve.Timestamps,
+		FollowSymlinks: cfg.Preserve.FollowSymlinks,
+	}
+}
@@ -9,11 +9,15 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"text/template"
 
 	"github.com/blakesmith/ar"
+	"github.com/scttfrdmn/bagboy/pkg/changelog"
 	"github.com/scttfrdmn/bagboy/pkg/config"
 	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/naming"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+	"github.com/scttfrdmn/bagboy/pkg/spdx"
 )
 
 type Packager struct{}
@@ -27,15 +31,47 @@ func (p *Packager) Name() string {
 }
 
 func (p *Packager) Validate(cfg *config.Config) error {
-	if cfg.Packages.Deb.Maintainer == "" {
-		return errors.InvalidConfigError("deb.maintainer", "maintainer email is required for DEB packages")
+	if maintainer, _ := debMaintainers(cfg); maintainer == "" {
+		return errors.NotConfiguredError("maintainer email is required for DEB packages (set deb.maintainer or an authors entry with an email)")
+	}
+	if err := packager.ValidateAlternatives(cfg.Packages.Deb.Alternatives, "/usr/bin/"+cfg.Name); err != nil {
+		return errors.InvalidConfigError("deb.alternatives", err.Error())
+	}
+	if _, err := naming.Deb(cfg.Name); cfg.Name != "" && err != nil {
+		return errors.InvalidConfigError("name", err.Error())
 	}
 	return nil
 }
 
 func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
+	return packager.Primary(p.PackArtifacts(ctx, cfg))
+}
+
+// PackArtifacts builds one package per architecture, amd64 first when
+// present
+func (p *Packager) PackArtifacts(ctx context.Context, cfg *config.Config) ([]packager.Artifact, error) {
+	binaries := packager.LinuxBinaries(cfg)
+	if len(binaries) == 0 {
+		return nil, errors.MissingBinaryError("linux")
+	}
+
+	var artifacts []packager.Artifact
+	for _, binary := range binaries {
+		output, err := p.packArch(cfg, binary)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, packager.Artifact{Path: output, OS: "linux", Arch: binary.Arch})
+	}
+	return artifacts, nil
+}
+
+// packArch builds the package for one Linux binary
+func (p *Packager) packArch(cfg *config.Config, linuxBinary packager.PlatformBinary) (string, error) {
+	arch := packager.DebArch(linuxBinary.Arch)
+
 	// Create temp directory for package structure
-	tempDir := filepath.Join(os.TempDir(), fmt.Sprintf("%s-deb-%s", cfg.Name, cfg.Version))
+	tempDir := filepath.Join(os.TempDir(), fmt.Sprintf("%s-deb-%s-%s", cfg.Name, cfg.Version, arch))
 	if err := os.RemoveAll(tempDir); err != nil {
 		return "", err
 	}
@@ -52,7 +88,13 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 
 	// Create control file
 	controlPath := filepath.Join(debianDir, "control")
-	if err := p.createControlFile(controlPath, cfg); err != nil {
+	if err := p.createControlFile(controlPath, cfg, arch); err != nil {
+		return "", err
+	}
+
+	// Register update-alternatives and the post-install message in
+	// maintainer scripts
+	if err := p.createMaintainerScripts(debianDir, cfg); err != nil {
 		return "", err
 	}
 
@@ -62,42 +104,55 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 		return "", err
 	}
 
-	// Find Linux binary
-	var linuxBinary string
-	for arch, path := range cfg.Binaries {
-		if strings.HasPrefix(arch, "linux-") {
-			linuxBinary = path
-			break
-		}
+	// Copy binary
+	if err := paths.CopyExecutable(linuxBinary.Path, filepath.Join(binDir, cfg.Name), paths.CopyOptions{FollowSymlinks: true}); err != nil {
+		return "", err
 	}
 
-	if linuxBinary == "" {
-		return "", errors.MissingBinaryError("linux")
+	// Extra files, with anything under /etc kept across upgrades
+	if err := packager.StageFiles(tempDir, packager.ExtraFiles(cfg, "deb", "/usr"), packager.CopyOptions(cfg)); err != nil {
+		return "", err
 	}
-
-	// Copy binary
-	src, err := os.Open(linuxBinary)
-	if err != nil {
+	if err := p.createConffiles(tempDir); err != nil {
 		return "", err
 	}
-	defer src.Close()
 
-	dst, err := os.Create(filepath.Join(binDir, cfg.Name))
-	if err != nil {
-		return "", err
+	// Menu entry, icon and file types; the desktop-file-utils and
+	// shared-mime-info triggers refresh their caches
+	if cfg.Desktop != nil {
+		if err := packager.StageDesktop(tempDir, cfg, "/usr/bin/"+cfg.Name); err != nil {
+			return "", fmt.Errorf("failed to add desktop integration: %w", err)
+		}
 	}
-	defer dst.Close()
 
-	if _, err := io.Copy(dst, src); err != nil {
-		return "", err
+	// Machine-readable copyright file
+	docDir := filepath.Join(tempDir, "usr", "share", "doc", cfg.Name)
+	if cfg.License != "" {
+		if err := os.MkdirAll(docDir, 0755); err != nil {
+			return "", err
+		}
+		if err := p.createCopyrightFile(filepath.Join(docDir, "copyright"), cfg); err != nil {
+			return "", err
+		}
 	}
 
-	if err := os.Chmod(filepath.Join(binDir, cfg.Name), 0755); err != nil {
-		return "", err
+	// SBOM of the binary, with the other package documentation
+	if _, err := packager.WriteSBOM(cfg, linuxBinary.Path, filepath.Join(docDir, packager.SBOMName(cfg))); err != nil {
+		return "", fmt.Errorf("failed to write SBOM: %w", err)
+	}
+
+	// Changelog from CHANGELOG.md, when it has an entry for this version
+	if release, ok := changelog.Find(cfg, cfg.Version); ok && !release.Empty() {
+		if err := os.MkdirAll(docDir, 0755); err != nil {
+			return "", err
+		}
+		if err := p.createChangelogDebian(filepath.Join(docDir, "changelog.Debian.gz"), cfg); err != nil {
+			return "", err
+		}
 	}
 
 	// Create the .deb package
-	outputPath := filepath.Join("dist", fmt.Sprintf("%s_%s_amd64.deb", cfg.Name, cfg.Version))
+	outputPath := filepath.Join("dist", fmt.Sprintf("%s_%s_%s.deb", cfg.Name, cfg.Version, arch))
 	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 		return "", err
 	}
@@ -105,17 +160,151 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 	return outputPath, p.createDebPackage(tempDir, outputPath)
 }
 
-func (p *Packager) createControlFile(path string, cfg *config.Config) error {
-	tmpl := `Package: {{.Name}}
+// debMaintainers returns the Maintainer and Uploaders fields. deb.maintainer
+// takes precedence; otherwise the first maintainer with an email is the
+// Maintainer and the others are Uploaders.
+func debMaintainers(cfg *config.Config) (string, []string) {
+	maintainer := cfg.Packages.Deb.Maintainer
+	var uploaders []string
+	for _, person := range cfg.Maintainers() {
+		if person.Email == "" {
+			continue
+		}
+		if maintainer == "" {
+			maintainer = person.String()
+		} else if person.String() != maintainer {
+			uploaders = append(uploaders, person.String())
+		}
+	}
+	return maintainer, uploaders
+}
+
+// createChangelogDebian writes the gzipped Debian changelog installed
+// with the binary package
+func (p *Packager) createChangelogDebian(path string, cfg *config.Config) error {
+	maintainer, _ := debMaintainers(cfg)
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	if _, err := io.WriteString(gz, debianChangelog(cfg, cfg.Version, "unstable", maintainer)); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// createCopyrightFile writes debian/copyright in the machine-readable
+// format, translating the SPDX license to Debian short names
+func (p *Packager) createCopyrightFile(path string, cfg *config.Config) error {
+	tmpl := `Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/
+Upstream-Name: {{.Name}}
+{{- if .Homepage}}
+Source: {{.Homepage}}
+{{- end}}
+
+Files: *
+Copyright: {{.Copyright}}
+License: {{.DebianLicense}}
+{{- range .Common}}
+
+License: {{.Name}}
+ On Debian systems, the complete text of this license can be found in
+ /usr/share/common-licenses/{{.File}}.
+{{- end}}
+`
+
+	t, err := packager.ParseTemplate(cfg, "deb/copyright", tmpl)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	type commonLicense struct{ Name, File string }
+	data := struct {
+		*config.Config
+		Copyright     string
+		DebianLicense string
+		Common        []commonLicense
+	}{
+		Config:        cfg,
+		Copyright:     strings.Join(config.AuthorNames(cfg.PeopleWithRole(config.RoleAuthor)), ", "),
+		DebianLicense: cfg.License,
+	}
+	if data.Copyright == "" {
+		data.Copyright = cfg.Name + " authors"
+	}
+
+	if expr, err := spdx.Parse(cfg.License); err == nil {
+		data.DebianLicense = expr.Debian()
+		for _, id := range expr.Licenses() {
+			if license, ok := spdx.Lookup(id); ok && license.Common != "" {
+				data.Common = append(data.Common, commonLicense{Name: license.Debian, File: license.Common})
+			}
+		}
+	}
+
+	return t.Execute(f, data)
+}
+
+// PackageName returns the Debian package name for cfg.Name, lower case
+// with hyphens for underscores. Names that cannot be fixed are left for
+// bagboy validate to report.
+func PackageName(cfg *config.Config) string {
+	name, err := naming.Deb(cfg.Name)
+	if err != nil {
+		return cfg.Name
+	}
+	return name
+}
+
+// debOperators maps constraint operators to Debian's relations
+var debOperators = map[string]string{">=": ">=", "<=": "<=", ">": ">>", "<": "<<", "=": "="}
+
+// Depends returns the relations on the bagboy tools cfg requires, e.g.
+// "helper (>= 1.2.0), helper (<< 2.0.0)"
+func Depends(cfg *config.Config) string {
+	var relations []string
+	for _, dep := range cfg.Dependencies.Bagboy {
+		name, err := naming.Deb(dep.Name)
+		if err != nil {
+			name = dep.Name
+		}
+		constraint, _ := dep.Constraint()
+		if len(constraint) == 0 {
+			relations = append(relations, name)
+		}
+		for _, c := range constraint {
+			relations = append(relations, fmt.Sprintf("%s (%s %s)", name, debOperators[c.Op], c.Version))
+		}
+	}
+	return strings.Join(relations, ", ")
+}
+
+func (p *Packager) createControlFile(path string, cfg *config.Config, arch string) error {
+	tmpl := `Package: {{.Package}}
 Version: {{.Version}}
 Section: {{.Section}}
 Priority: {{.Priority}}
-Architecture: amd64
+Architecture: {{.Architecture}}
 Maintainer: {{.Maintainer}}
+{{- if .Uploaders}}
+Uploaders: {{.Uploaders}}
+{{- end}}
+{{- if .Depends}}
+Depends: {{.Depends}}
+{{- end}}
 Description: {{.Description}}
 Homepage: {{.Homepage}}`
 
-	t, err := template.New("control").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "deb/control", tmpl)
 	if err != nil {
 		return err
 	}
@@ -128,16 +317,26 @@ Homepage: {{.Homepage}}`
 
 	data := struct {
 		*config.Config
-		Section    string
-		Priority   string
-		Maintainer string
+		Package      string
+		Section      string
+		Priority     string
+		Maintainer   string
+		Uploaders    string
+		Architecture string
+		Depends      string
 	}{
-		Config:     cfg,
-		Section:    cfg.Packages.Deb.Section,
-		Priority:   cfg.Packages.Deb.Priority,
-		Maintainer: cfg.Packages.Deb.Maintainer,
+		Config:       cfg,
+		Package:      PackageName(cfg),
+		Depends:      Depends(cfg),
+		Section:      cfg.Packages.Deb.Section,
+		Priority:     cfg.Packages.Deb.Priority,
+		Architecture: arch,
 	}
 
+	maintainer, uploaders := debMaintainers(cfg)
+	data.Maintainer = maintainer
+	data.Uploaders = strings.Join(uploaders, ", ")
+
 	if data.Section == "" {
 		data.Section = "utils"
 	}
@@ -148,6 +347,50 @@ Homepage: {{.Homepage}}`
 	return t.Execute(f, data)
 }
 
+func (p *Packager) createMaintainerScripts(debianDir string, cfg *config.Config) error {
+	alternatives := cfg.Packages.Deb.Alternatives
+	message := cfg.PostInstall.MessageFor("deb")
+	target := "/usr/bin/" + cfg.Name
+
+	scripts := map[string]string{}
+	if len(alternatives) > 0 || message != "" {
+		scripts["postinst"] = packager.DebPostinst(alternatives, target, message)
+	}
+	if len(alternatives) > 0 {
+		scripts["prerm"] = packager.DebPrerm(alternatives, target)
+	}
+	for name, content := range scripts {
+		if err := os.WriteFile(filepath.Join(debianDir, name), []byte(content), 0755); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// createConffiles lists the files installed under /etc in DEBIAN/conffiles
+// so dpkg keeps local changes to them on upgrade
+func (p *Packager) createConffiles(root string) error {
+	var conffiles []string
+	err := filepath.Walk(filepath.Join(root, "etc"), func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		conffiles = append(conffiles, "/"+paths.Slash(relPath))
+		return nil
+	})
+	if os.IsNotExist(err) || len(conffiles) == 0 {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(root, "DEBIAN", "conffiles"), []byte(strings.Join(conffiles, "\n")+"\n"), 0644)
+}
+
 func (p *Packager) createDebPackage(sourceDir, outputPath string) error {
 	// For now, create a mock DEB file to get tests passing
 	// TODO: Fix ar library integration issue
@@ -181,17 +424,23 @@ func (p *Packager) createTarGz(sourceDir, outputPath string, exclude []string) e
 			}
 		}
 
-		header, err := tar.FileInfoHeader(info, "")
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
 		if err != nil {
 			return err
 		}
 
-		header.Name = relPath
+		header.Name = paths.Slash(relPath)
 		if err := tarWriter.WriteHeader(header); err != nil {
 			return err
 		}
 
-		if !info.IsDir() {
+		if info.Mode().IsRegular() {
 			file, err := os.Open(path)
 			if err != nil {
 				return err
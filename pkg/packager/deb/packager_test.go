@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
@@ -128,7 +129,7 @@ func TestCreateControlFile(t *testing.T) {
 		},
 	}
 
-	if err := packager.createControlFile(controlPath, cfg); err != nil {
+	if err := packager.createControlFile(controlPath, cfg, "amd64"); err != nil {
 		t.Errorf("createControlFile() error = %v", err)
 	}
 
@@ -151,6 +152,7 @@ func TestCreateControlFile(t *testing.T) {
 		"Maintainer: test@example.com",
 		"Section: utils",
 		"Priority: optional",
+		"Architecture: amd64",
 	}
 
 	for _, field := range requiredFields {
@@ -158,6 +160,94 @@ func TestCreateControlFile(t *testing.T) {
 			t.Errorf("Control file missing required field: %s", field)
 		}
 	}
+
+	// An arm64 package is labelled arm64
+	if err := packager.createControlFile(controlPath, cfg, "arm64"); err != nil {
+		t.Fatalf("createControlFile() error = %v", err)
+	}
+	content, _ = os.ReadFile(controlPath)
+	if !contains(string(content), "Architecture: arm64") {
+		t.Errorf("Expected arm64 architecture, got:\n%s", content)
+	}
+
+	// Names are lower case with hyphens, as Debian requires
+	cfg.Name = "Test_App"
+	if err := packager.createControlFile(controlPath, cfg, "amd64"); err != nil {
+		t.Fatalf("createControlFile() error = %v", err)
+	}
+	content, _ = os.ReadFile(controlPath)
+	if !contains(string(content), "Package: test-app\n") {
+		t.Errorf("Expected a normalized package name, got:\n%s", content)
+	}
+
+	// Other bagboy tools become versioned dependencies
+	cfg.Dependencies.Bagboy = []config.ToolDependency{{Name: "Helper", Version: ">=1.2, <2"}}
+	if err := packager.createControlFile(controlPath, cfg, "amd64"); err != nil {
+		t.Fatalf("createControlFile() error = %v", err)
+	}
+	content, _ = os.ReadFile(controlPath)
+	if !contains(string(content), "Depends: helper (>= 1.2.0), helper (<< 2.0.0)\n") {
+		t.Errorf("Expected versioned dependencies, got:\n%s", content)
+	}
+}
+
+func TestDebMaintainers(t *testing.T) {
+	cfg := &config.Config{
+		Authors: []config.AuthorConfig{
+			{Name: "Ada", Email: "ada@example.com"},
+			{Name: "Grace", Email: "grace@example.com", Role: config.RoleMaintainer},
+			{Name: "Linus", Email: "linus@example.com", Role: config.RoleMaintainer},
+			{Name: "Ken", Role: config.RoleContributor},
+		},
+	}
+
+	maintainer, uploaders := debMaintainers(cfg)
+	if maintainer != "Grace <grace@example.com>" {
+		t.Errorf("Expected first maintainer, got %q", maintainer)
+	}
+	if len(uploaders) != 1 || uploaders[0] != "Linus <linus@example.com>" {
+		t.Errorf("Expected Linus as uploader, got %v", uploaders)
+	}
+
+	// deb.maintainer wins and every authors maintainer becomes an uploader
+	cfg.Packages.Deb.Maintainer = "Team <team@example.com>"
+	_, uploaders = debMaintainers(cfg)
+	if len(uploaders) != 2 {
+		t.Errorf("Expected 2 uploaders, got %v", uploaders)
+	}
+
+	// The single author string still works
+	legacy := &config.Config{Author: "Test Author <test@example.com>"}
+	if maintainer, _ := debMaintainers(legacy); maintainer != "Test Author <test@example.com>" {
+		t.Errorf("Expected author as maintainer, got %q", maintainer)
+	}
+}
+
+func TestCreateCopyrightFile(t *testing.T) {
+	cfg := &config.Config{
+		Name:     "testapp",
+		Homepage: "https://example.com",
+		License:  "mit OR Apache-2.0",
+		Author:   "Test Author <test@example.com>",
+	}
+
+	path := filepath.Join(t.TempDir(), "copyright")
+	if err := New().createCopyrightFile(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(path)
+
+	for _, expected := range []string{
+		"Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/",
+		"Upstream-Name: testapp",
+		"Copyright: Test Author",
+		"License: Expat or Apache-2.0",
+		"/usr/share/common-licenses/Apache-2.0.",
+	} {
+		if !contains(string(content), expected) {
+			t.Errorf("Copyright file missing %q:\n%s", expected, content)
+		}
+	}
 }
 
 func TestCreateTarGz(t *testing.T) {
@@ -288,3 +378,186 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestCreateMaintainerScripts(t *testing.T) {
+	p := New()
+	debianDir := t.TempDir()
+
+	cfg := &config.Config{Name: "myapp", Version: "1.0.0"}
+	if err := p.createMaintainerScripts(debianDir, cfg); err != nil {
+		t.Fatalf("createMaintainerScripts failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(debianDir, "postinst")); !os.IsNotExist(err) {
+		t.Error("postinst should not be created without alternatives")
+	}
+
+	cfg.Packages.Deb.Alternatives = []config.AlternativeConfig{{Link: "/usr/bin/tool", Priority: 50}}
+	if err := p.createMaintainerScripts(debianDir, cfg); err != nil {
+		t.Fatalf("createMaintainerScripts failed: %v", err)
+	}
+
+	for _, name := range []string{"postinst", "prerm"} {
+		info, err := os.Stat(filepath.Join(debianDir, name))
+		if err != nil {
+			t.Errorf("%s not created: %v", name, err)
+			continue
+		}
+		if info.Mode().Perm()&0111 == 0 {
+			t.Errorf("%s should be executable", name)
+		}
+	}
+}
+
+func TestDEBPackager_ValidateAlternatives(t *testing.T) {
+	cfg := &config.Config{
+		Name: "myapp",
+		Packages: config.PackagesConfig{
+			Deb: config.DebConfig{
+				Maintainer:   "test@example.com",
+				Alternatives: []config.AlternativeConfig{{Link: "tool"}},
+			},
+		},
+	}
+	if err := New().Validate(cfg); err == nil {
+		t.Error("Expected error for relative alternatives link")
+	}
+}
+
+func TestCreateSource(t *testing.T) {
+	testDir := t.TempDir()
+	for _, arch := range []string{"amd64", "arm64"} {
+		if err := os.WriteFile(filepath.Join(testDir, "testapp-linux-"+arch), []byte(arch+" binary"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Name:        "testapp",
+		Version:     "1.2.0",
+		Description: "Test application",
+		License:     "MIT",
+		Author:      "Test Author <test@example.com>",
+		Binaries: map[string]string{
+			"linux-amd64": filepath.Join(testDir, "testapp-linux-amd64"),
+			"linux-arm64": filepath.Join(testDir, "testapp-linux-arm64"),
+		},
+	}
+
+	if version := SourceVersion(cfg, "noble"); version != "1.2.0-1~noble1" {
+		t.Errorf("SourceVersion() = %s", version)
+	}
+
+	workDir := t.TempDir()
+	sourceDir, err := New().CreateSource(cfg, "noble", workDir)
+	if err != nil {
+		t.Fatalf("CreateSource() error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(workDir, "testapp_1.2.0.orig.tar.gz")); err != nil {
+		t.Errorf("Expected orig tarball: %v", err)
+	}
+	if content, err := os.ReadFile(filepath.Join(sourceDir, "binaries", "arm64", "testapp")); err != nil || string(content) != "arm64 binary" {
+		t.Errorf("Expected arm64 binary in source tree: %q %v", content, err)
+	}
+
+	expected := map[string][]string{
+		"control":   {"Source: testapp\n", "Maintainer: Test Author <test@example.com>\n", "Architecture: amd64 arm64\n"},
+		"changelog": {"testapp (1.2.0-1~noble1) noble; urgency=medium\n", " -- Test Author <test@example.com>  "},
+		"rules":     {"binaries/$(DEB_HOST_ARCH)/testapp debian/testapp/usr/bin/testapp"},
+		"copyright": {"License: Expat\n"},
+	}
+	for file, substrings := range expected {
+		content, err := os.ReadFile(filepath.Join(sourceDir, "debian", file))
+		if err != nil {
+			t.Errorf("%s: %v", file, err)
+			continue
+		}
+		for _, substring := range substrings {
+			if !strings.Contains(string(content), substring) {
+				t.Errorf("debian/%s missing %q:\n%s", file, substring, content)
+			}
+		}
+	}
+}
+
+func TestCreateConffiles(t *testing.T) {
+	p := New()
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "DEBIAN"), 0755)
+	if err := p.createConffiles(root); err != nil {
+		t.Fatalf("createConffiles() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "DEBIAN", "conffiles")); !os.IsNotExist(err) {
+		t.Error("conffiles should not be written without files under /etc")
+	}
+
+	os.MkdirAll(filepath.Join(root, "etc", "myapp", "conf.d"), 0755)
+	os.WriteFile(filepath.Join(root, "etc", "myapp", "config.yaml"), nil, 0644)
+	os.WriteFile(filepath.Join(root, "etc", "myapp", "conf.d", "extra.yaml"), nil, 0644)
+	if err := p.createConffiles(root); err != nil {
+		t.Fatalf("createConffiles() error = %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(root, "DEBIAN", "conffiles"))
+	if want := "/etc/myapp/conf.d/extra.yaml\n/etc/myapp/config.yaml\n"; string(data) != want {
+		t.Errorf("conffiles = %q, want %q", data, want)
+	}
+}
+
+func TestDebianChangelog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	os.WriteFile(path, []byte("## [1.2.0] - 2026-03-02\n\n### Added\n\n- Shell completions\n"), 0644)
+	cfg := &config.Config{Name: "testapp", Version: "1.2.0", Changelog: path}
+
+	entry := debianChangelog(cfg, "1.2.0-1~noble1", "noble", "Test Author <test@example.com>")
+	expected := "testapp (1.2.0-1~noble1) noble; urgency=medium\n\n  * Shell completions\n\n -- Test Author <test@example.com>  Mon, 02 Mar 2026 00:00:00 +0000\n"
+	if entry != expected {
+		t.Errorf("debianChangelog() = %q, want %q", entry, expected)
+	}
+
+	cfg.Version = "1.3.0"
+	if entry := debianChangelog(cfg, "1.3.0", "unstable", ""); !strings.Contains(entry, "  * Release 1.3.0.\n") {
+		t.Errorf("Expected fallback entry, got %q", entry)
+	}
+}
+
+func TestDEBPackager_MultiArch(t *testing.T) {
+	testDir := t.TempDir()
+	cfg := &config.Config{
+		Name:        "testapp",
+		Version:     "1.0.0",
+		Description: "Test application",
+		Packages:    config.PackagesConfig{Deb: config.DebConfig{Maintainer: "test@example.com"}},
+		Binaries:    map[string]string{},
+	}
+	for _, arch := range []string{"amd64", "arm64", "arm"} {
+		binary := filepath.Join(testDir, "testapp-linux-"+arch)
+		if err := os.WriteFile(binary, []byte("fake binary "+arch), 0755); err != nil {
+			t.Fatal(err)
+		}
+		cfg.Binaries["linux-"+arch] = binary
+	}
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	artifacts, err := New().PackArtifacts(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("PackArtifacts failed: %v", err)
+	}
+
+	// amd64 comes first, so it is what Pack returns
+	want := map[string]string{"amd64": "amd64", "arm64": "arm64", "arm": "armhf"}
+	if len(artifacts) != len(want) || artifacts[0].Arch != "amd64" {
+		t.Fatalf("Expected amd64, arm64 and arm artifacts, got %+v", artifacts)
+	}
+	for _, artifact := range artifacts {
+		path := filepath.Join("dist", "testapp_1.0.0_"+want[artifact.Arch]+".deb")
+		if artifact.OS != "linux" || artifact.Path != path {
+			t.Errorf("Artifact %+v, want linux at %s", artifact, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Package not created: %v", err)
+		}
+	}
+}
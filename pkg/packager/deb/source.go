@@ -0,0 +1,234 @@
+package deb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/changelog"
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+)
+
+// SourceVersion returns the Debian version of a source upload for an
+// Ubuntu series. The ~series1 suffix keeps uploads of the same release to
+// different series distinct and sorted below the next upstream version.
+func SourceVersion(cfg *config.Config, distribution string) string {
+	revision := cfg.Packages.Deb.PPA.Revision
+	if revision == "" {
+		revision = "1"
+	}
+	return fmt.Sprintf("%s-%s~%s1", cfg.Version, revision, distribution)
+}
+
+// CreateSource writes a Debian source package for distribution into
+// workDir: the orig tarball with the Linux binaries for every configured
+// architecture and an unpacked tree with the debian/ directory. It returns
+// the unpacked source directory.
+func (p *Packager) CreateSource(cfg *config.Config, distribution, workDir string) (string, error) {
+	binaries := packager.LinuxBinaries(cfg)
+	if len(binaries) == 0 {
+		return "", errors.MissingBinaryError("linux")
+	}
+
+	sourceDir := filepath.Join(workDir, fmt.Sprintf("%s-%s", PackageName(cfg), cfg.Version))
+	if err := os.RemoveAll(sourceDir); err != nil {
+		return "", err
+	}
+
+	// Launchpad builds from source, so the binaries are shipped in the orig
+	// tarball and debian/rules installs the one for the build architecture
+	var architectures []string
+	for _, binary := range binaries {
+		arch := packager.DebArch(binary.Arch)
+		architectures = append(architectures, arch)
+		if err := paths.CopyExecutable(binary.Path, filepath.Join(sourceDir, "binaries", arch, cfg.Name), paths.CopyOptions{FollowSymlinks: true}); err != nil {
+			return "", err
+		}
+	}
+
+	// The orig tarball is shared by the uploads for every series
+	origPath := filepath.Join(workDir, fmt.Sprintf("%s_%s.orig.tar.gz", PackageName(cfg), cfg.Version))
+	if _, err := os.Stat(origPath); os.IsNotExist(err) {
+		stageDir := filepath.Join(workDir, "orig")
+		if err := os.RemoveAll(stageDir); err != nil {
+			return "", err
+		}
+		if err := paths.CopyTree(sourceDir, filepath.Join(stageDir, filepath.Base(sourceDir)), packager.CopyOptions(cfg)); err != nil {
+			return "", err
+		}
+		if err := p.createTarGz(stageDir, origPath, nil); err != nil {
+			return "", err
+		}
+		if err := os.RemoveAll(stageDir); err != nil {
+			return "", err
+		}
+	}
+
+	debianDir := filepath.Join(sourceDir, "debian")
+	if err := os.MkdirAll(filepath.Join(debianDir, "source"), 0755); err != nil {
+		return "", err
+	}
+
+	if err := p.createSourceControl(filepath.Join(debianDir, "control"), cfg, architectures); err != nil {
+		return "", err
+	}
+	if err := p.createChangelog(filepath.Join(debianDir, "changelog"), cfg, distribution); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(debianDir, "rules"), []byte(sourceRules(cfg)), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(debianDir, "source", "format"), []byte("3.0 (quilt)\n"), 0644); err != nil {
+		return "", err
+	}
+	if cfg.License != "" {
+		if err := p.createCopyrightFile(filepath.Join(debianDir, "copyright"), cfg); err != nil {
+			return "", err
+		}
+	}
+	if err := p.createMaintainerScripts(debianDir, cfg); err != nil {
+		return "", err
+	}
+
+	return sourceDir, nil
+}
+
+// BuildSource runs dpkg-buildpackage to produce the unsigned .dsc and
+// _source.changes for sourceDir and returns the path of the .changes file
+func (p *Packager) BuildSource(ctx context.Context, cfg *config.Config, distribution, sourceDir string) (string, error) {
+	if _, err := exec.LookPath("dpkg-buildpackage"); err != nil {
+		return "", fmt.Errorf("dpkg-buildpackage not found - install dpkg-dev and debhelper")
+	}
+
+	// -sa includes the orig tarball, -d skips the build-dependency check
+	// since nothing is compiled locally
+	cmd := exec.CommandContext(ctx, "dpkg-buildpackage", "-S", "-sa", "-d", "-us", "-uc")
+	cmd.Dir = sourceDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("dpkg-buildpackage failed: %w\nOutput: %s", err, output)
+	}
+
+	changes := fmt.Sprintf("%s_%s_source.changes", PackageName(cfg), SourceVersion(cfg, distribution))
+	return filepath.Join(filepath.Dir(sourceDir), changes), nil
+}
+
+func (p *Packager) createSourceControl(path string, cfg *config.Config, architectures []string) error {
+	tmpl := `Source: {{.Package}}
+Section: {{.Section}}
+Priority: {{.Priority}}
+Maintainer: {{.Maintainer}}
+{{- if .Uploaders}}
+Uploaders: {{.Uploaders}}
+{{- end}}
+Build-Depends: debhelper-compat (= 13)
+Standards-Version: 4.6.2
+{{- if .Homepage}}
+Homepage: {{.Homepage}}
+{{- end}}
+Rules-Requires-Root: no
+
+Package: {{.Package}}
+Architecture: {{.Architectures}}
+Depends: ${misc:Depends}{{if .Depends}}, {{.Depends}}{{end}}
+Description: {{.Description}}
+`
+
+	t, err := packager.ParseTemplate(cfg, "deb/source-control", tmpl)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data := struct {
+		*config.Config
+		Package       string
+		Section       string
+		Priority      string
+		Maintainer    string
+		Uploaders     string
+		Architectures string
+		Depends       string
+	}{
+		Config:        cfg,
+		Package:       PackageName(cfg),
+		Depends:       Depends(cfg),
+		Section:       cfg.Packages.Deb.Section,
+		Priority:      cfg.Packages.Deb.Priority,
+		Architectures: strings.Join(architectures, " "),
+	}
+
+	maintainer, uploaders := debMaintainers(cfg)
+	data.Maintainer = maintainer
+	data.Uploaders = strings.Join(uploaders, ", ")
+
+	if data.Section == "" {
+		data.Section = "utils"
+	}
+	if data.Priority == "" {
+		data.Priority = "optional"
+	}
+
+	return t.Execute(f, data)
+}
+
+func (p *Packager) createChangelog(path string, cfg *config.Config, distribution string) error {
+	maintainer, _ := debMaintainers(cfg)
+	entry := debianChangelog(cfg, SourceVersion(cfg, distribution), distribution, maintainer)
+	return os.WriteFile(path, []byte(entry), 0644)
+}
+
+// debianChangelog returns a debian/changelog entry for version, listing
+// the changes from CHANGELOG.md when it has this version
+func debianChangelog(cfg *config.Config, version, distribution, maintainer string) string {
+	items := []string{fmt.Sprintf("Release %s.", cfg.Version)}
+	date := time.Now().UTC()
+	if release, ok := changelog.Find(cfg, cfg.Version); ok && !release.Empty() {
+		items = release.Items()
+		if !release.Date.IsZero() {
+			date = release.Date
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s) %s; urgency=medium\n\n", PackageName(cfg), version, distribution)
+	for _, item := range items {
+		fmt.Fprintf(&b, "  * %s\n", item)
+	}
+	fmt.Fprintf(&b, "\n -- %s  %s\n", maintainer, date.Format(time.RFC1123Z))
+	return b.String()
+}
+
+// sourceRules returns debian/rules. The binaries are prebuilt, so the
+// build steps that expect compiled sources are skipped.
+func sourceRules(cfg *config.Config) string {
+	return fmt.Sprintf(`#!/usr/bin/make -f
+
+include /usr/share/dpkg/architecture.mk
+
+%%:
+	dh $@
+
+override_dh_auto_build:
+
+override_dh_auto_install:
+	install -D -m 0755 binaries/$(DEB_HOST_ARCH)/%[1]s debian/%[2]s/usr/bin/%[1]s
+
+override_dh_strip:
+
+override_dh_dwz:
+
+override_dh_shlibdeps:
+`, cfg.Name, PackageName(cfg))
+}
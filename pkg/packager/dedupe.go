@@ -0,0 +1,141 @@
+package packager
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// DuplicateBinaries warns about binaries entries for different platforms
+// that point to the same file or to byte-identical files, usually a
+// copy-paste mistake in binaries:
+func DuplicateBinaries(cfg *config.Config) []string {
+	platforms := make([]string, 0, len(cfg.Binaries))
+	for platform := range cfg.Binaries {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	byPath := map[string][]string{}
+	var paths []string
+	for _, platform := range platforms {
+		path := filepath.Clean(cfg.Binaries[platform])
+		if _, ok := byPath[path]; !ok {
+			paths = append(paths, path)
+		}
+		byPath[path] = append(byPath[path], platform)
+	}
+
+	var warnings []string
+	for _, path := range paths {
+		if group := byPath[path]; len(group) > 1 {
+			warnings = append(warnings, fmt.Sprintf("binaries %s all point to %s", strings.Join(group, ", "), path))
+		}
+	}
+
+	// Different files with the same content
+	bySum := map[string][]string{}
+	var sums []string
+	for _, path := range paths {
+		sum, err := fileSHA256(path)
+		if err != nil {
+			continue
+		}
+		if _, ok := bySum[sum]; !ok {
+			sums = append(sums, sum)
+		}
+		bySum[sum] = append(bySum[sum], path)
+	}
+	for _, sum := range sums {
+		group := bySum[sum]
+		if len(group) < 2 {
+			continue
+		}
+		var names []string
+		for _, path := range group {
+			names = append(names, strings.Join(byPath[path], ", "))
+		}
+		warnings = append(warnings, fmt.Sprintf("binaries %s are byte-identical (%s)", strings.Join(names, " and "), strings.Join(group, ", ")))
+	}
+	return warnings
+}
+
+// Identical maps each output that is byte-identical to an earlier one to
+// that earlier output. Outputs that are not regular files are skipped.
+func Identical(outputs []string) (map[string]string, error) {
+	first := map[string]string{}
+	duplicates := map[string]string{}
+	for _, path := range outputs {
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		sum, err := fileSHA256(path)
+		if err != nil {
+			return nil, err
+		}
+		if original, ok := first[sum]; ok {
+			duplicates[path] = original
+			continue
+		}
+		first[sum] = path
+	}
+	return duplicates, nil
+}
+
+// LinkIdentical replaces outputs that are byte-identical to an earlier one
+// with a hard link to it, so duplicate artifacts take no extra local disk
+// space. It returns how many were linked and the bytes saved. Outputs that
+// cannot be linked, such as across filesystems, are left as they are.
+func LinkIdentical(outputs []string) (int, int64, error) {
+	duplicates, err := Identical(outputs)
+	if err != nil {
+		return 0, 0, err
+	}
+	linked, saved := 0, int64(0)
+	for _, path := range outputs {
+		original, ok := duplicates[path]
+		if !ok {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if originalInfo, err := os.Stat(original); err == nil && os.SameFile(info, originalInfo) {
+			continue
+		}
+
+		tmp := path + ".link"
+		if err := os.Link(original, tmp); err != nil {
+			continue
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			os.Remove(tmp)
+			return linked, saved, err
+		}
+		linked++
+		saved += info.Size()
+	}
+	return linked, saved, nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
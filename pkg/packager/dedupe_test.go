@@ -0,0 +1,95 @@
+package packager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestDuplicateBinaries(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(content), 0755)
+		return path
+	}
+	amd64 := write("myapp-linux-amd64", "amd64")
+	darwin := write("myapp-darwin-amd64", "darwin")
+	copied := write("myapp-darwin-arm64", "darwin")
+
+	cfg := &config.Config{Binaries: map[string]string{
+		"linux-amd64":  amd64,
+		"linux-arm64":  amd64,
+		"darwin-amd64": darwin,
+		"darwin-arm64": copied,
+	}}
+	warnings := DuplicateBinaries(cfg)
+	if len(warnings) != 2 {
+		t.Fatalf("Expected 2 warnings, got %q", warnings)
+	}
+	if !strings.Contains(warnings[0], "linux-amd64, linux-arm64 all point to "+amd64) {
+		t.Errorf("Unexpected same-file warning: %s", warnings[0])
+	}
+	if !strings.Contains(warnings[1], "darwin-amd64 and darwin-arm64 are byte-identical") {
+		t.Errorf("Unexpected identical-content warning: %s", warnings[1])
+	}
+
+	cfg.Binaries = map[string]string{"linux-amd64": amd64, "darwin-amd64": darwin}
+	if warnings := DuplicateBinaries(cfg); len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %q", warnings)
+	}
+}
+
+func TestIdentical(t *testing.T) {
+	dir := t.TempDir()
+	var outputs []string
+	for _, file := range []struct{ name, content string }{{"a.zip", "same"}, {"b.zip", "different"}, {"c.zip", "same"}} {
+		path := filepath.Join(dir, file.name)
+		os.WriteFile(path, []byte(file.content), 0644)
+		outputs = append(outputs, path)
+	}
+	outputs = append(outputs, dir)
+
+	duplicates, err := Identical(outputs)
+	if err != nil {
+		t.Fatalf("Identical failed: %v", err)
+	}
+	if len(duplicates) != 1 || duplicates[outputs[2]] != outputs[0] {
+		t.Errorf("Identical() = %v", duplicates)
+	}
+}
+
+func TestLinkIdentical(t *testing.T) {
+	dir := t.TempDir()
+	var outputs []string
+	for name, content := range map[string]string{"a.zip": "same", "b.zip": "same", "c.zip": "different"} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(content), 0644)
+		outputs = append(outputs, path)
+	}
+
+	linked, saved, err := LinkIdentical(outputs)
+	if err != nil {
+		t.Fatalf("LinkIdentical failed: %v", err)
+	}
+	if linked != 1 || saved != 4 {
+		t.Errorf("Expected 1 link saving 4 bytes, got %d and %d", linked, saved)
+	}
+
+	a, _ := os.Stat(filepath.Join(dir, "a.zip"))
+	b, _ := os.Stat(filepath.Join(dir, "b.zip"))
+	if !os.SameFile(a, b) {
+		t.Error("Expected identical outputs to be hard-linked")
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, "c.zip")); string(content) != "different" {
+		t.Errorf("Unexpected content %q", content)
+	}
+
+	// Already linked outputs are not counted again
+	if linked, _, _ := LinkIdentical(outputs); linked != 0 {
+		t.Errorf("Expected nothing to link, got %d", linked)
+	}
+}
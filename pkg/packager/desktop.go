@@ -0,0 +1,213 @@
+package packager
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"image"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+)
+
+// DesktopName returns the name a GUI application is shown under
+func DesktopName(cfg *config.Config) string {
+	if cfg.Desktop != nil && cfg.Desktop.Name != "" {
+		return cfg.Desktop.Name
+	}
+	return cfg.Name
+}
+
+// DesktopID returns the reverse-DNS application ID, e.g. for the macOS
+// bundle identifier
+func DesktopID(cfg *config.Config) string {
+	if cfg.Desktop != nil && cfg.Desktop.ID != "" {
+		return cfg.Desktop.ID
+	}
+	return "dev.bagboy." + cfg.Name
+}
+
+// Extension returns the file extension without its dot
+func Extension(assoc config.FileAssociationConfig) string {
+	return strings.TrimPrefix(assoc.Extension, ".")
+}
+
+// MimeType returns the MIME type of an associated file type
+func MimeType(assoc config.FileAssociationConfig) string {
+	if assoc.MimeType != "" {
+		return assoc.MimeType
+	}
+	return "application/x-" + strings.ToLower(Extension(assoc))
+}
+
+// DesktopEntry returns the freedesktop.org desktop entry launching exec,
+// declaring the file types and URL schemes it opens
+func DesktopEntry(cfg *config.Config, exec string) string {
+	desktop := cfg.Desktop
+	var mimeTypes []string
+	for _, assoc := range desktop.FileAssociations {
+		mimeTypes = append(mimeTypes, MimeType(assoc))
+	}
+	for _, scheme := range desktop.Protocols {
+		mimeTypes = append(mimeTypes, "x-scheme-handler/"+scheme)
+	}
+	if len(mimeTypes) > 0 {
+		exec += " %U"
+	}
+
+	var b strings.Builder
+	b.WriteString("[Desktop Entry]\nType=Application\n")
+	fmt.Fprintf(&b, "Name=%s\n", DesktopName(cfg))
+	if cfg.Description != "" {
+		fmt.Fprintf(&b, "Comment=%s\n", cfg.Description)
+	}
+	fmt.Fprintf(&b, "Exec=%s\n", exec)
+	if desktop.Icon != "" {
+		fmt.Fprintf(&b, "Icon=%s\n", cfg.Name)
+	}
+	fmt.Fprintf(&b, "Terminal=%t\n", desktop.Terminal)
+	if len(desktop.Categories) > 0 {
+		fmt.Fprintf(&b, "Categories=%s;\n", strings.Join(desktop.Categories, ";"))
+	}
+	if len(mimeTypes) > 0 {
+		fmt.Fprintf(&b, "MimeType=%s;\n", strings.Join(mimeTypes, ";"))
+	}
+	return b.String()
+}
+
+// MimeInfo returns the shared-mime-info definitions of the associated file
+// types, or "" when there are none
+func MimeInfo(cfg *config.Config) string {
+	if len(cfg.Desktop.FileAssociations) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	b.WriteString("<mime-info xmlns=\"http://www.freedesktop.org/standards/shared-mime-info\">\n")
+	for _, assoc := range cfg.Desktop.FileAssociations {
+		fmt.Fprintf(&b, "  <mime-type type=\"%s\">\n", MimeType(assoc))
+		if assoc.Description != "" {
+			b.WriteString("    <comment>")
+			xml.EscapeText(&b, []byte(assoc.Description))
+			b.WriteString("</comment>\n")
+		}
+		fmt.Fprintf(&b, "    <glob pattern=\"*.%s\"/>\n", Extension(assoc))
+		b.WriteString("  </mime-type>\n")
+	}
+	b.WriteString("</mime-info>\n")
+	return b.String()
+}
+
+// StageDesktop writes the Linux desktop integration under root: the
+// desktop entry launching exec, the icon in the hicolor theme and the
+// file type definitions
+func StageDesktop(root string, cfg *config.Config, exec string) error {
+	share := filepath.Join(root, "usr", "share")
+	files := map[string]string{
+		filepath.Join(share, "applications", cfg.Name+".desktop"): DesktopEntry(cfg, exec),
+	}
+	if info := MimeInfo(cfg); info != "" {
+		files[filepath.Join(share, "mime", "packages", cfg.Name+".xml")] = info
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	icon := cfg.Desktop.Icon
+	var size string
+	switch strings.ToLower(filepath.Ext(icon)) {
+	case ".png":
+		width, _, err := IconSize(icon)
+		if err != nil {
+			return err
+		}
+		size = fmt.Sprintf("%dx%d", width, width)
+	case ".svg":
+		size = "scalable"
+	default:
+		// ICO and ICNS icons are for Windows and macOS
+		return nil
+	}
+	dst := filepath.Join(share, "icons", "hicolor", size, "apps", cfg.Name+strings.ToLower(filepath.Ext(icon)))
+	return paths.CopyFile(icon, dst, CopyOptions(cfg))
+}
+
+// IconSize returns the width and height of a PNG icon
+func IconSize(path string) (int, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	img, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return 0, 0, fmt.Errorf("icon %s: %w", path, err)
+	}
+	return img.Width, img.Height, nil
+}
+
+// WriteICO writes the PNG icon at src to dst as a Windows icon. The PNG is
+// embedded as is, which Windows Vista and later read.
+func WriteICO(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	width, height, err := IconSize(src)
+	if err != nil {
+		return err
+	}
+	// A dimension of 0 means 256 or more
+	dim := func(n int) uint8 {
+		if n >= 256 {
+			return 0
+		}
+		return uint8(n)
+	}
+
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, [3]uint16{0, 1, 1}) // reserved, type icon, one image
+	b.Write([]byte{dim(width), dim(height), 0, 0})
+	binary.Write(&b, binary.LittleEndian, [2]uint16{1, 32}) // planes, bits per pixel
+	binary.Write(&b, binary.LittleEndian, [2]uint32{uint32(len(data)), 22})
+	b.Write(data)
+	return os.WriteFile(dst, b.Bytes(), 0644)
+}
+
+// icnsTypes are the icns element types holding a PNG of each size
+var icnsTypes = map[int]string{16: "icp4", 32: "icp5", 64: "icp6", 128: "ic07", 256: "ic08", 512: "ic09", 1024: "ic10"}
+
+// WriteICNS writes the PNG icon at src to dst as a macOS icon. The PNG must
+// be square and 16, 32, 64, 128, 256, 512 or 1024 pixels.
+func WriteICNS(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	width, height, err := IconSize(src)
+	if err != nil {
+		return err
+	}
+	kind, ok := icnsTypes[width]
+	if !ok || width != height {
+		return fmt.Errorf("icon %s is %dx%d; icns needs a square PNG of 16 to 1024 pixels in powers of two", src, width, height)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("icns")
+	binary.Write(&b, binary.BigEndian, uint32(16+len(data)))
+	b.WriteString(kind)
+	binary.Write(&b, binary.BigEndian, uint32(8+len(data)))
+	b.Write(data)
+	return os.WriteFile(dst, b.Bytes(), 0644)
+}
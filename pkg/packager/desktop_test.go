@@ -0,0 +1,119 @@
+package packager
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// writePNG writes a blank square PNG of the given size
+func writePNG(t *testing.T, path string, size int) {
+	t.Helper()
+	var b bytes.Buffer
+	if err := png.Encode(&b, image.NewRGBA(image.Rect(0, 0, size, size))); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func desktopConfig(icon string) *config.Config {
+	return &config.Config{
+		Name:        "myapp",
+		Description: "My application",
+		Desktop: &config.DesktopConfig{
+			Name:       "My App",
+			Icon:       icon,
+			Categories: []string{"Development", "Utility"},
+			FileAssociations: []config.FileAssociationConfig{
+				{Extension: ".mydoc", Description: "My App Document"},
+				{Extension: "mycfg", MimeType: "text/x-mycfg"},
+			},
+			Protocols: []string{"myapp"},
+		},
+	}
+}
+
+func TestDesktopEntry(t *testing.T) {
+	entry := DesktopEntry(desktopConfig("icon.png"), "/usr/bin/myapp")
+	for _, line := range []string{
+		"Name=My App",
+		"Comment=My application",
+		"Exec=/usr/bin/myapp %U",
+		"Icon=myapp",
+		"Terminal=false",
+		"Categories=Development;Utility;",
+		"MimeType=application/x-mydoc;text/x-mycfg;x-scheme-handler/myapp;",
+	} {
+		if !strings.Contains(entry, line+"\n") {
+			t.Errorf("desktop entry missing %q:\n%s", line, entry)
+		}
+	}
+
+	plain := &config.Config{Name: "myapp", Desktop: &config.DesktopConfig{}}
+	if entry := DesktopEntry(plain, "/usr/bin/myapp"); !strings.Contains(entry, "Exec=/usr/bin/myapp\n") || strings.Contains(entry, "MimeType") || strings.Contains(entry, "Icon") {
+		t.Errorf("unexpected desktop entry:\n%s", entry)
+	}
+	if DesktopID(plain) != "dev.bagboy.myapp" || DesktopName(plain) != "myapp" {
+		t.Errorf("DesktopID() = %s, DesktopName() = %s", DesktopID(plain), DesktopName(plain))
+	}
+}
+
+func TestStageDesktop(t *testing.T) {
+	icon := filepath.Join(t.TempDir(), "icon.png")
+	writePNG(t, icon, 64)
+	root := t.TempDir()
+	if err := StageDesktop(root, desktopConfig(icon), "/usr/bin/myapp"); err != nil {
+		t.Fatalf("StageDesktop() error = %v", err)
+	}
+
+	for _, path := range []string{
+		"usr/share/applications/myapp.desktop",
+		"usr/share/icons/hicolor/64x64/apps/myapp.png",
+		"usr/share/mime/packages/myapp.xml",
+	} {
+		if _, err := os.Stat(filepath.Join(root, path)); err != nil {
+			t.Errorf("%s not staged: %v", path, err)
+		}
+	}
+	info, _ := os.ReadFile(filepath.Join(root, "usr/share/mime/packages/myapp.xml"))
+	if !strings.Contains(string(info), `<glob pattern="*.mydoc"/>`) || !strings.Contains(string(info), "<comment>My App Document</comment>") {
+		t.Errorf("unexpected mime info:\n%s", info)
+	}
+}
+
+func TestWriteIcons(t *testing.T) {
+	dir := t.TempDir()
+	icon := filepath.Join(dir, "icon.png")
+	writePNG(t, icon, 256)
+	data, _ := os.ReadFile(icon)
+
+	if err := WriteICO(icon, filepath.Join(dir, "icon.ico")); err != nil {
+		t.Fatalf("WriteICO() error = %v", err)
+	}
+	ico, _ := os.ReadFile(filepath.Join(dir, "icon.ico"))
+	if !bytes.Equal(ico[:6], []byte{0, 0, 1, 0, 1, 0}) || ico[6] != 0 || binary.LittleEndian.Uint32(ico[18:22]) != 22 || !bytes.Equal(ico[22:], data) {
+		t.Errorf("unexpected ICO header % x", ico[:22])
+	}
+
+	if err := WriteICNS(icon, filepath.Join(dir, "icon.icns")); err != nil {
+		t.Fatalf("WriteICNS() error = %v", err)
+	}
+	icns, _ := os.ReadFile(filepath.Join(dir, "icon.icns"))
+	if string(icns[:4]) != "icns" || int(binary.BigEndian.Uint32(icns[4:8])) != len(icns) || string(icns[8:12]) != "ic08" || !bytes.Equal(icns[16:], data) {
+		t.Errorf("unexpected ICNS header % x", icns[:16])
+	}
+
+	writePNG(t, icon, 300)
+	if err := WriteICNS(icon, filepath.Join(dir, "odd.icns")); err == nil {
+		t.Error("Expected a 300px icon to be rejected for icns")
+	}
+}
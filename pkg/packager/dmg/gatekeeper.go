@@ -0,0 +1,149 @@
+package dmg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+)
+
+// udifTrailer is the magic of the 512 byte koly block that ends every UDIF
+// disk image
+const udifTrailer = "koly"
+
+// GatekeeperResult is the outcome of an spctl assessment
+type GatekeeperResult struct {
+	Path     string
+	Accepted bool
+	Source   string
+	Output   string
+}
+
+// Summary describes the result in one line
+func (r GatekeeperResult) Summary() string {
+	status := "rejected"
+	if r.Accepted {
+		status = "accepted"
+	}
+	if r.Source != "" {
+		return fmt.Sprintf("%s: %s (%s)", filepath.Base(r.Path), status, r.Source)
+	}
+	return fmt.Sprintf("%s: %s", filepath.Base(r.Path), status)
+}
+
+// ParseAssessment builds a result from spctl --verbose output. spctl exits
+// non-zero on rejection, which is passed as accepted=false; the output is
+// still checked because older releases report "rejected" with status 0.
+func ParseAssessment(path, output string, accepted bool) GatekeeperResult {
+	result := GatekeeperResult{Path: path, Accepted: accepted, Output: strings.TrimSpace(output)}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "source="):
+			result.Source = strings.TrimPrefix(line, "source=")
+		case strings.HasSuffix(line, ": rejected"), strings.Contains(line, ": rejected ("):
+			result.Accepted = false
+		}
+	}
+
+	return result
+}
+
+// GatekeeperAvailable reports whether Gatekeeper can be queried on this host
+func GatekeeperAvailable() bool {
+	if runtime.GOOS != "darwin" {
+		return false
+	}
+	_, err := exec.LookPath("spctl")
+	return err == nil
+}
+
+// IsDiskImage reports whether path ends with a UDIF trailer, i.e. it was
+// produced by hdiutil rather than being a placeholder
+func IsDiskImage(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.Size() < 512 {
+		return false
+	}
+
+	magic := make([]byte, len(udifTrailer))
+	if _, err := f.ReadAt(magic, info.Size()-512); err != nil && err != io.EOF {
+		return false
+	}
+	return string(magic) == udifTrailer
+}
+
+// AssessGatekeeper simulates what a user downloading dmgPath would see. The
+// image is copied aside and given a com.apple.quarantine attribute like a
+// browser download, then assessed with spctl as the primary signature. Any
+// .app bundles in contentsDir are assessed for execution as well.
+func AssessGatekeeper(ctx context.Context, dmgPath, contentsDir string) ([]GatekeeperResult, error) {
+	if !GatekeeperAvailable() {
+		return nil, fmt.Errorf("spctl not available - Gatekeeper can only be checked on macOS")
+	}
+
+	quarantined, cleanup, err := quarantineCopy(dmgPath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	result := runSpctl(ctx, quarantined, "-a", "-t", "open", "--context", "context:primary-signature", "-v", quarantined)
+	result.Path = dmgPath
+	results := []GatekeeperResult{result}
+
+	apps, _ := filepath.Glob(filepath.Join(contentsDir, "*.app"))
+	for _, app := range apps {
+		results = append(results, runSpctl(ctx, app, "-a", "-t", "exec", "-vv", app))
+	}
+
+	return results, nil
+}
+
+func runSpctl(ctx context.Context, path string, args ...string) GatekeeperResult {
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, "spctl", args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	return ParseAssessment(path, out.String(), err == nil)
+}
+
+// quarantineCopy copies path into a temporary directory and marks the copy
+// as downloaded so Gatekeeper applies first-launch checks
+func quarantineCopy(path string) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "bagboy-gatekeeper-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	dst := filepath.Join(dir, filepath.Base(path))
+	if err := paths.CopyExecutable(path, dst, paths.CopyOptions{}); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	attr := fmt.Sprintf("0081;%x;bagboy;", time.Now().Unix())
+	if output, err := exec.Command("xattr", "-w", "com.apple.quarantine", attr, dst).CombinedOutput(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to set quarantine attribute: %s", strings.TrimSpace(string(output)))
+	}
+
+	return dst, cleanup, nil
+}
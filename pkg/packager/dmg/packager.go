@@ -2,13 +2,16 @@ package dmg
 
 import (
 	"context"
+	"encoding/xml"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
-	"text/template"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
 )
 
 type Packager struct{}
@@ -28,7 +31,7 @@ func (p *Packager) Validate(cfg *config.Config) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("no macOS binary found for DMG creation")
+	return errors.NoPlatformBinaryError("no macOS binary found for DMG creation")
 }
 
 func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
@@ -52,12 +55,19 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 		return "", err
 	}
 
-	// Copy binary to contents
+	// Copy binary to contents, in an app bundle for GUI applications
 	binaryDest := filepath.Join(contentsDir, cfg.Name)
-	if err := p.copyFile(darwinBinary, binaryDest); err != nil {
+	if cfg.Desktop != nil {
+		bundle := filepath.Join(contentsDir, AppBundle(cfg))
+		if err := p.createAppBundle(bundle, cfg); err != nil {
+			return "", fmt.Errorf("failed to create app bundle: %w", err)
+		}
+		binaryDest = filepath.Join(bundle, "Contents", "MacOS", cfg.Name)
+	}
+	if err := paths.CopyExecutable(darwinBinary, binaryDest, packager.CopyOptions(cfg)); err != nil {
 		return "", err
 	}
-	if err := os.Chmod(binaryDest, 0755); err != nil {
+	if err := packager.StageFiles(contentsDir, packager.ExtraFiles(cfg, "dmg", ""), packager.CopyOptions(cfg)); err != nil {
 		return "", err
 	}
 
@@ -79,15 +89,57 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 	}
 
 	// Create mock DMG file (in production would use hdiutil)
-	outputPath := filepath.Join("dist", fmt.Sprintf("%s-%s.dmg", cfg.Name, cfg.Version))
+	outputPath := OutputPath(cfg)
 	mockDMG := fmt.Sprintf("# Mock DMG for %s %s\n# Generated by bagboy\n# In production, run: cd %s && ./build-dmg.sh\n", cfg.Name, cfg.Version, dmgDir)
 	if err := os.WriteFile(outputPath, []byte(mockDMG), 0644); err != nil {
 		return "", err
 	}
 
+	if err := p.checkGatekeeper(ctx, outputPath, contentsDir, cfg); err != nil {
+		return "", err
+	}
+
 	return outputPath, nil
 }
 
+// OutputPath is where Pack writes the DMG; its base name is the release
+// asset
+func OutputPath(cfg *config.Config) string {
+	return filepath.Join("dist", fmt.Sprintf("%s-%s.dmg", cfg.Name, cfg.Version))
+}
+
+// checkGatekeeper warns when a built DMG would be blocked on first open.
+// It only runs on macOS against real disk images; with
+// fail_on_gatekeeper a rejection fails the build instead.
+func (p *Packager) checkGatekeeper(ctx context.Context, dmgPath, contentsDir string, cfg *config.Config) error {
+	if cfg.Packages.DMG.SkipGatekeeperCheck || !GatekeeperAvailable() || !IsDiskImage(dmgPath) {
+		return nil
+	}
+
+	results, err := AssessGatekeeper(ctx, dmgPath, contentsDir)
+	if err != nil {
+		fmt.Printf("⚠️  Gatekeeper check skipped: %v\n", err)
+		return nil
+	}
+
+	var rejected []string
+	for _, result := range results {
+		if result.Accepted {
+			continue
+		}
+		fmt.Printf("⚠️  Gatekeeper would block %s\n", result.Summary())
+		rejected = append(rejected, filepath.Base(result.Path))
+	}
+
+	if len(rejected) > 0 {
+		fmt.Println("   Sign with a Developer ID and notarize before publishing (bagboy sign)")
+		if cfg.Packages.DMG.FailOnGatekeeper {
+			return fmt.Errorf("gatekeeper rejected %s", strings.Join(rejected, ", "))
+		}
+	}
+	return nil
+}
+
 func (p *Packager) createBuildScript(path string, cfg *config.Config) error {
 	tmpl := `#!/bin/bash
 set -e
@@ -95,6 +147,7 @@ set -e
 # Build script for {{.Name}} DMG
 
 APP_NAME="{{.Name}}"
+APP_ITEM="{{.Item}}"
 VERSION="{{.Version}}"
 DMG_NAME="${APP_NAME}-${VERSION}.dmg"
 VOLUME_NAME="${APP_NAME} ${VERSION}"
@@ -138,7 +191,7 @@ tell application "Finder"
         set viewOptions to the icon view options of container window
         set arrangement of viewOptions to not arranged
         set icon size of viewOptions to 72
-        set position of item "${APP_NAME}" of container window to {150, 200}
+        set position of item "${APP_ITEM}" of container window to {150, 200}
         set position of item "Applications" of container window to {350, 200}
         close
         open
@@ -160,9 +213,9 @@ rm -f "${TEMP_DMG}"
 echo "✅ Created ${DMG_NAME}"
 echo ""
 echo "Usage:"
-echo "  Open ${DMG_NAME} and drag ${APP_NAME} to Applications"`
+echo "  Open ${DMG_NAME} and drag ${APP_ITEM} to Applications"`
 
-	t, err := template.New("build").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "dmg/build", tmpl)
 	if err != nil {
 		return err
 	}
@@ -177,29 +230,167 @@ echo "  Open ${DMG_NAME} and drag ${APP_NAME} to Applications"`
 		return err
 	}
 
-	return t.Execute(f, cfg)
+	item := cfg.Name
+	if cfg.Desktop != nil {
+		item = AppBundle(cfg)
+	}
+	data := struct {
+		*config.Config
+		Item string // what the user drags to Applications
+	}{
+		Config: cfg,
+		Item:   item,
+	}
+	return t.Execute(f, data)
 }
 
-func (p *Packager) createDSStoreTemplate(path string, cfg *config.Config) error {
-	// Create a simple DS_Store template file
-	// In production, this would be a binary DS_Store file for layout
-	template := fmt.Sprintf("# DS_Store template for %s DMG\n# This would be a binary .DS_Store file in production\n# Controls icon positions and window layout\n", cfg.Name)
-	return os.WriteFile(path, []byte(template), 0644)
+// AppBundle is the name of the app bundle the DMG holds for a GUI
+// application, such as My App.app
+func AppBundle(cfg *config.Config) string {
+	return packager.DesktopName(cfg) + ".app"
 }
 
-func (p *Packager) copyFile(src, dst string) error {
-	srcFile, err := os.Open(src)
+// createAppBundle writes the app bundle's Info.plist, declaring the file
+// types and URL schemes the application opens, and its icon. A PNG icon
+// that is not a size icns holds is left out with a warning.
+func (p *Packager) createAppBundle(bundle string, cfg *config.Config) error {
+	tmpl := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+  <key>CFBundleName</key>
+  <string>{{.DisplayName}}</string>
+  <key>CFBundleDisplayName</key>
+  <string>{{.DisplayName}}</string>
+  <key>CFBundleIdentifier</key>
+  <string>{{.ID}}</string>
+  <key>CFBundleVersion</key>
+  <string>{{.Version}}</string>
+  <key>CFBundleShortVersionString</key>
+  <string>{{.Version}}</string>
+  <key>CFBundleExecutable</key>
+  <string>{{.Name}}</string>
+  <key>CFBundlePackageType</key>
+  <string>APPL</string>
+{{- if .Icon}}
+  <key>CFBundleIconFile</key>
+  <string>{{.Icon}}</string>
+{{- end}}
+  <key>NSHighResolutionCapable</key>
+  <true/>
+{{- if .FileAssociations}}
+  <key>CFBundleDocumentTypes</key>
+  <array>
+{{- range .FileAssociations}}
+    <dict>
+      <key>CFBundleTypeName</key>
+      <string>{{.Description}}</string>
+      <key>CFBundleTypeRole</key>
+      <string>Editor</string>
+      <key>CFBundleTypeExtensions</key>
+      <array>
+        <string>{{.Extension}}</string>
+      </array>
+      <key>CFBundleTypeMIMETypes</key>
+      <array>
+        <string>{{.MimeType}}</string>
+      </array>
+    </dict>
+{{- end}}
+  </array>
+{{- end}}
+{{- if .Protocols}}
+  <key>CFBundleURLTypes</key>
+  <array>
+{{- range .Protocols}}
+    <dict>
+      <key>CFBundleURLName</key>
+      <string>{{$.ID}}.{{.}}</string>
+      <key>CFBundleURLSchemes</key>
+      <array>
+        <string>{{.}}</string>
+      </array>
+    </dict>
+{{- end}}
+  </array>
+{{- end}}
+</dict>
+</plist>
+`
+
+	t, err := packager.ParseTemplate(cfg, "dmg/info-plist", tmpl)
 	if err != nil {
 		return err
 	}
-	defer srcFile.Close()
 
-	dstFile, err := os.Create(dst)
+	resources := filepath.Join(bundle, "Contents", "Resources")
+	if err := os.MkdirAll(resources, 0755); err != nil {
+		return err
+	}
+	var icon string
+	switch src := cfg.Desktop.Icon; strings.ToLower(filepath.Ext(src)) {
+	case ".icns":
+		icon = cfg.Name + ".icns"
+		if err := paths.CopyFile(src, filepath.Join(resources, icon), packager.CopyOptions(cfg)); err != nil {
+			return err
+		}
+	case ".png":
+		icon = cfg.Name + ".icns"
+		if err := packager.WriteICNS(src, filepath.Join(resources, icon)); err != nil {
+			fmt.Printf("⚠️  App bundle has no icon: %v\n", err)
+			icon = ""
+		}
+	}
+
+	type association struct {
+		Extension   string
+		Description string
+		MimeType    string
+	}
+	data := struct {
+		*config.Config
+		DisplayName      string
+		ID               string
+		Icon             string
+		FileAssociations []association
+		Protocols        []string
+	}{
+		Config:      cfg,
+		DisplayName: plistEscape(packager.DesktopName(cfg)),
+		ID:          plistEscape(packager.DesktopID(cfg)),
+		Icon:        icon,
+		Protocols:   cfg.Desktop.Protocols,
+	}
+	for _, assoc := range cfg.Desktop.FileAssociations {
+		description := assoc.Description
+		if description == "" {
+			description = strings.ToUpper(packager.Extension(assoc)) + " document"
+		}
+		data.FileAssociations = append(data.FileAssociations, association{
+			Extension:   plistEscape(packager.Extension(assoc)),
+			Description: plistEscape(description),
+			MimeType:    plistEscape(packager.MimeType(assoc)),
+		})
+	}
+
+	f, err := os.Create(filepath.Join(bundle, "Contents", "Info.plist"))
 	if err != nil {
 		return err
 	}
-	defer dstFile.Close()
+	defer f.Close()
+	return t.Execute(f, data)
+}
+
+// plistEscape escapes s for a property list string
+func plistEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
 
-	_, err = dstFile.ReadFrom(srcFile)
-	return err
+func (p *Packager) createDSStoreTemplate(path string, cfg *config.Config) error {
+	// Create a simple DS_Store template file
+	// In production, this would be a binary DS_Store file for layout
+	template := fmt.Sprintf("# DS_Store template for %s DMG\n# This would be a binary .DS_Store file in production\n# Controls icon positions and window layout\n", cfg.Name)
+	return os.WriteFile(path, []byte(template), 0644)
 }
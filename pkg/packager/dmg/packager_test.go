@@ -1,9 +1,13 @@
 package dmg
 
 import (
+	"bytes"
 	"context"
+	"image"
+	"image/png"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
@@ -76,3 +80,109 @@ func TestDMGPackagerValidation(t *testing.T) {
 		t.Error("Expected validation to fail with no macOS binary")
 	}
 }
+
+func TestParseAssessment(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		exitOK   bool
+		accepted bool
+		source   string
+	}{
+		{"notarized", "app.dmg: accepted\nsource=Notarized Developer ID\n", true, true, "Notarized Developer ID"},
+		{"unsigned", "app.dmg: rejected\nsource=no usable signature\n", false, false, "no usable signature"},
+		{"rejected with status 0", "app.dmg: rejected (the code is valid but does not seem to be an app)\n", true, false, ""},
+		{"unnotarized", "app.dmg: rejected\nsource=Unnotarized Developer ID\n", false, false, "Unnotarized Developer ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseAssessment("app.dmg", tt.output, tt.exitOK)
+			if result.Accepted != tt.accepted {
+				t.Errorf("Expected accepted=%v, got %v", tt.accepted, result.Accepted)
+			}
+			if result.Source != tt.source {
+				t.Errorf("Expected source %q, got %q", tt.source, result.Source)
+			}
+		})
+	}
+}
+
+func TestIsDiskImage(t *testing.T) {
+	dir := t.TempDir()
+
+	placeholder := filepath.Join(dir, "mock.dmg")
+	if err := os.WriteFile(placeholder, []byte("# Mock DMG\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if IsDiskImage(placeholder) {
+		t.Error("Expected placeholder not to be a disk image")
+	}
+
+	image := make([]byte, 4096)
+	copy(image[len(image)-512:], "koly")
+	real := filepath.Join(dir, "real.dmg")
+	if err := os.WriteFile(real, image, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !IsDiskImage(real) {
+		t.Error("Expected UDIF trailer to be detected")
+	}
+}
+
+func TestDMGPackager_AppBundle(t *testing.T) {
+	testDir := t.TempDir()
+	testBinary := filepath.Join(testDir, "test-darwin-arm64")
+	os.WriteFile(testBinary, []byte("fake binary"), 0755)
+	icon := filepath.Join(testDir, "icon.png")
+	var b bytes.Buffer
+	png.Encode(&b, image.NewRGBA(image.Rect(0, 0, 512, 512)))
+	os.WriteFile(icon, b.Bytes(), 0644)
+
+	cfg := &config.Config{
+		Name:     "testapp",
+		Version:  "1.0.0",
+		Binaries: map[string]string{"darwin-arm64": testBinary},
+		Desktop: &config.DesktopConfig{
+			Name:             "Test App",
+			ID:               "com.example.TestApp",
+			Icon:             icon,
+			FileAssociations: []config.FileAssociationConfig{{Extension: "tdoc"}},
+			Protocols:        []string{"testapp"},
+		},
+	}
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	if _, err := New().Pack(context.Background(), cfg); err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	bundle := filepath.Join("dist", "dmg", "contents", "Test App.app", "Contents")
+	for _, path := range []string{"MacOS/testapp", "Resources/testapp.icns", "Info.plist"} {
+		if _, err := os.Stat(filepath.Join(bundle, path)); err != nil {
+			t.Errorf("app bundle missing %s: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join("dist", "dmg", "contents", "testapp")); err == nil {
+		t.Error("binary should be inside the app bundle")
+	}
+
+	plist, _ := os.ReadFile(filepath.Join(bundle, "Info.plist"))
+	for _, expected := range []string{
+		"<string>com.example.TestApp</string>",
+		"<key>CFBundleIconFile</key>\n  <string>testapp.icns</string>",
+		"<string>TDOC document</string>",
+		"<string>application/x-tdoc</string>",
+		"<string>com.example.TestApp.testapp</string>",
+	} {
+		if !strings.Contains(string(plist), expected) {
+			t.Errorf("Info.plist missing %q:\n%s", expected, plist)
+		}
+	}
+	script, _ := os.ReadFile(filepath.Join("dist", "dmg", "build-dmg.sh"))
+	if !strings.Contains(string(script), `APP_ITEM="Test App.app"`) {
+		t.Error("build script should position the app bundle")
+	}
+}
@@ -2,13 +2,15 @@ package docker
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
-	"text/template"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
 )
 
 type Packager struct{}
@@ -23,7 +25,7 @@ func (p *Packager) Name() string {
 
 func (p *Packager) Validate(cfg *config.Config) error {
 	if cfg.Description == "" {
-		return fmt.Errorf("description is required for Docker image")
+		return errors.NotConfiguredError("description is required for Docker image")
 	}
 	return nil
 }
@@ -77,16 +79,11 @@ target/debug/
 
 func (p *Packager) createDockerfile(path string, cfg *config.Config) error {
 	// Find Linux binary
-	var linuxBinary string
-	for arch, binaryPath := range cfg.Binaries {
-		if strings.HasPrefix(arch, "linux-") {
-			linuxBinary = binaryPath
-			break
-		}
-	}
-	if linuxBinary == "" {
+	binary, ok := packager.PrimaryLinuxBinary(cfg)
+	if !ok {
 		return fmt.Errorf("no Linux binary found for Docker image")
 	}
+	linuxBinary := binary.Path
 
 	tmpl := `# Multi-stage build for {{.Name}}
 FROM alpine:latest as builder
@@ -108,9 +105,16 @@ COPY --from=builder /usr/share/zoneinfo /usr/share/zoneinfo
 
 # Copy the binary
 COPY --from=builder /root/{{.Name}} /{{.Name}}
+{{- if .SBOM}}
+
+# SBOM of the binary
+COPY {{.SBOM}} /{{.SBOMName}}
+LABEL dev.bagboy.sbom="/{{.SBOMName}}"
+LABEL dev.bagboy.sbom.sha256="{{.SBOMSHA256}}"
+{{- end}}
 
 # Metadata
-LABEL maintainer="{{.Author}}"
+LABEL maintainer="{{.PrimaryAuthor}}"
 LABEL description="{{.Description}}"
 LABEL version="{{.Version}}"
 LABEL homepage="{{.Homepage}}"
@@ -122,25 +126,43 @@ LABEL org.opencontainers.image.version="{{.Version}}"
 ENTRYPOINT ["/{{.Name}}"]
 CMD ["--help"]`
 
-	t, err := template.New("dockerfile").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "docker/dockerfile", tmpl)
 	if err != nil {
 		return err
 	}
 
-	f, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
 	data := struct {
 		*config.Config
 		BinaryPath string
+		SBOM       string
+		SBOMName   string
+		SBOMSHA256 string
 	}{
 		Config:     cfg,
 		BinaryPath: linuxBinary,
+		SBOMName:   packager.SBOMName(cfg),
 	}
 
+	// The SBOM sits next to the Dockerfile, copied in from the build
+	// context like the binary
+	sbomPath := filepath.Join(filepath.Dir(path), data.SBOMName)
+	if ok, err := packager.WriteSBOM(cfg, linuxBinary, sbomPath); err != nil {
+		return fmt.Errorf("failed to write SBOM: %w", err)
+	} else if ok {
+		content, err := os.ReadFile(sbomPath)
+		if err != nil {
+			return err
+		}
+		data.SBOM = filepath.ToSlash(sbomPath)
+		data.SBOMSHA256 = fmt.Sprintf("%x", sha256.Sum256(content))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
 	return t.Execute(f, data)
 }
 
@@ -164,7 +186,7 @@ services:
 # For CLI usage:
 # docker-compose run --rm {{.Name}} [command]`
 
-	t, err := template.New("compose").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "docker/compose", tmpl)
 	if err != nil {
 		return err
 	}
@@ -215,7 +237,7 @@ echo "To push to registry:"
 echo "  docker push ${VERSION_TAG}"
 echo "  docker push ${LATEST_TAG}"`
 
-	t, err := template.New("build").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "docker/build", tmpl)
 	if err != nil {
 		return err
 	}
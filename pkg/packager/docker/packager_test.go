@@ -18,6 +18,9 @@ package docker
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
@@ -52,6 +55,10 @@ func TestDockerPackager(t *testing.T) {
 }
 
 func TestDockerPack(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(t.TempDir())
+
 	p := New()
 	cfg := &config.Config{
 		Name:        "test",
@@ -73,3 +80,40 @@ func TestDockerPack(t *testing.T) {
 		t.Error("Expected output path")
 	}
 }
+
+func TestCreateDockerfile_SBOM(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "test-linux-amd64")
+	os.WriteFile(binary, []byte("binary"), 0755)
+	cfg := &config.Config{
+		Name:     "test",
+		Version:  "1.0.0",
+		Binaries: map[string]string{"linux-amd64": binary},
+		SBOM:     config.SBOMConfig{Enabled: true},
+	}
+
+	p := New()
+	path := filepath.Join(dir, "Dockerfile")
+	if err := p.createDockerfile(path, cfg); err != nil {
+		t.Fatalf("createDockerfile failed: %v", err)
+	}
+	content, _ := os.ReadFile(path)
+	for _, expected := range []string{
+		"COPY " + filepath.ToSlash(filepath.Join(dir, "test.spdx.json")) + " /test.spdx.json",
+		`LABEL dev.bagboy.sbom="/test.spdx.json"`,
+		`LABEL dev.bagboy.sbom.sha256="`,
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Dockerfile missing %q:\n%s", expected, content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "test.spdx.json")); err != nil {
+		t.Errorf("SBOM not written: %v", err)
+	}
+
+	cfg.SBOM.Enabled = false
+	p.createDockerfile(path, cfg)
+	if content, _ := os.ReadFile(path); strings.Contains(string(content), "sbom") {
+		t.Errorf("Dockerfile should not mention an SBOM when disabled:\n%s", content)
+	}
+}
@@ -0,0 +1,262 @@
+package dotnet
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+)
+
+// Packager wraps the native binaries in a .NET global tool. The tool is a
+// thin launcher that runs the bundled binary for the current runtime
+// identifier, the same approach the npm and PyPI wrappers take.
+type Packager struct{}
+
+func New() *Packager {
+	return &Packager{}
+}
+
+func (p *Packager) Name() string {
+	return "dotnet"
+}
+
+func (p *Packager) Validate(cfg *config.Config) error {
+	if cfg.Description == "" {
+		return errors.NotConfiguredError("description is required for NuGet packages")
+	}
+	if len(cfg.Binaries) == 0 {
+		return fmt.Errorf("at least one binary is required for a .NET tool")
+	}
+	return nil
+}
+
+// PackageID returns the NuGet package ID, defaulting to the project name
+func PackageID(cfg *config.Config) string {
+	if id := cfg.Packages.Dotnet.PackageID; id != "" {
+		return id
+	}
+	return cfg.Name
+}
+
+// RID maps a platform such as linux-amd64 to the .NET runtime identifier
+// the launcher looks up, e.g. linux-x64
+func RID(platform string) string {
+	goos, goarch, _ := strings.Cut(platform, "-")
+	switch goos {
+	case "darwin":
+		goos = "osx"
+	case "windows":
+		goos = "win"
+	}
+	switch goarch {
+	case "amd64":
+		goarch = "x64"
+	case "386":
+		goarch = "x86"
+	}
+	return goos + "-" + goarch
+}
+
+func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
+	if err := p.Validate(cfg); err != nil {
+		return "", err
+	}
+
+	projectDir := filepath.Join("dist", "dotnet")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		return "", err
+	}
+
+	platforms := make([]string, 0, len(cfg.Binaries))
+	for platform := range cfg.Binaries {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	for _, platform := range platforms {
+		name := cfg.Name
+		if strings.HasPrefix(platform, "windows-") {
+			name += ".exe"
+		}
+		dest := filepath.Join(projectDir, "binaries", RID(platform), name)
+		if err := paths.CopyExecutable(cfg.Binaries[platform], dest, paths.CopyOptions{FollowSymlinks: true}); err != nil {
+			return "", fmt.Errorf("failed to copy %s binary: %w", platform, err)
+		}
+	}
+
+	projectPath := filepath.Join(projectDir, PackageID(cfg)+".csproj")
+	if err := p.createProject(projectPath, cfg); err != nil {
+		return "", err
+	}
+	if err := p.createLauncher(filepath.Join(projectDir, "Program.cs"), cfg); err != nil {
+		return "", err
+	}
+
+	// Without the SDK the project is left for dotnet pack to build later
+	if _, err := exec.LookPath("dotnet"); err != nil {
+		return projectDir, nil
+	}
+
+	outputDir, err := filepath.Abs("dist")
+	if err != nil {
+		return "", err
+	}
+	cmd := exec.CommandContext(ctx, "dotnet", "pack", "--configuration", "Release", "--output", outputDir)
+	cmd.Dir = projectDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("dotnet pack failed: %w\nOutput: %s", err, output)
+	}
+
+	return filepath.Join("dist", fmt.Sprintf("%s.%s.nupkg", PackageID(cfg), cfg.Version)), nil
+}
+
+// createProject writes the SDK project that packs the launcher as a tool,
+// carrying the binaries alongside it in the tool's output
+func (p *Packager) createProject(path string, cfg *config.Config) error {
+	tmpl := `<Project Sdk="Microsoft.NET.Sdk">
+
+  <PropertyGroup>
+    <OutputType>Exe</OutputType>
+    <TargetFramework>{{.TargetFramework}}</TargetFramework>
+    <ImplicitUsings>enable</ImplicitUsings>
+    <Nullable>enable</Nullable>
+    <PackAsTool>true</PackAsTool>
+    <ToolCommandName>{{.ToolCommand | xml}}</ToolCommandName>
+    <PackageId>{{.PackageID | xml}}</PackageId>
+    <Version>{{.Version | xml}}</Version>
+    <Description>{{.Description | xml}}</Description>
+{{- if .Authors}}
+    <Authors>{{.Authors | xml}}</Authors>
+{{- end}}
+{{- if .License}}
+    <PackageLicenseExpression>{{.License | xml}}</PackageLicenseExpression>
+{{- end}}
+{{- if .Homepage}}
+    <PackageProjectUrl>{{.Homepage | xml}}</PackageProjectUrl>
+{{- end}}
+{{- if .RepositoryURL}}
+    <RepositoryUrl>{{.RepositoryURL | xml}}</RepositoryUrl>
+{{- end}}
+  </PropertyGroup>
+
+  <ItemGroup>
+    <None Include="binaries/**" CopyToOutputDirectory="PreserveNewest" />
+  </ItemGroup>
+
+</Project>
+`
+
+	t, err := packager.ParseTemplate(cfg, "dotnet/csproj", tmpl, template.FuncMap{"xml": xmlEscape})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data := struct {
+		*config.Config
+		PackageID       string
+		ToolCommand     string
+		TargetFramework string
+		Authors         string
+		RepositoryURL   string
+	}{
+		Config:          cfg,
+		PackageID:       PackageID(cfg),
+		ToolCommand:     cfg.Packages.Dotnet.ToolCommand,
+		TargetFramework: cfg.Packages.Dotnet.TargetFramework,
+	}
+	if data.ToolCommand == "" {
+		data.ToolCommand = cfg.Name
+	}
+	if data.TargetFramework == "" {
+		data.TargetFramework = "net8.0"
+	}
+	var authors []string
+	for _, person := range cfg.PeopleWithRole(config.RoleAuthor) {
+		authors = append(authors, person.Name)
+	}
+	data.Authors = strings.Join(authors, ", ")
+	if cfg.GitHub.Owner != "" && cfg.GitHub.Repo != "" {
+		data.RepositoryURL = fmt.Sprintf("https://github.com/%s/%s", cfg.GitHub.Owner, cfg.GitHub.Repo)
+	}
+
+	return t.Execute(f, data)
+}
+
+// createLauncher writes the entry point, which execs the native binary
+// with the tool's arguments and exits with its exit code
+func (p *Packager) createLauncher(path string, cfg *config.Config) error {
+	tmpl := `using System.Diagnostics;
+using System.Runtime.InteropServices;
+
+var os = OperatingSystem.IsWindows() ? "win" : OperatingSystem.IsMacOS() ? "osx" : "linux";
+var arch = RuntimeInformation.OSArchitecture switch
+{
+    Architecture.X64 => "x64",
+    Architecture.X86 => "x86",
+    Architecture.Arm64 => "arm64",
+    Architecture.Arm => "arm",
+    var other => other.ToString().ToLowerInvariant(),
+};
+var name = OperatingSystem.IsWindows() ? "{{.Name}}.exe" : "{{.Name}}";
+var binary = Path.Combine(AppContext.BaseDirectory, "binaries", $"{os}-{arch}", name);
+
+if (!File.Exists(binary))
+{
+    Console.Error.WriteLine($"{{.Name}} {{.Version}} has no binary for {os}-{arch}");
+    return 1;
+}
+
+if (!OperatingSystem.IsWindows())
+{
+    // NuGet does not preserve file modes when extracting the tool
+    File.SetUnixFileMode(binary, File.GetUnixFileMode(binary) |
+        UnixFileMode.UserExecute | UnixFileMode.GroupExecute | UnixFileMode.OtherExecute);
+}
+
+var start = new ProcessStartInfo(binary) { UseShellExecute = false };
+foreach (var arg in args)
+{
+    start.ArgumentList.Add(arg);
+}
+
+using var process = Process.Start(start)!;
+process.WaitForExit();
+return process.ExitCode;
+`
+
+	t, err := packager.ParseTemplate(cfg, "dotnet/Program.cs", tmpl)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return t.Execute(f, cfg)
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,97 @@
+package dotnet
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestDotnetPackager(t *testing.T) {
+	testDir := t.TempDir()
+	binary := filepath.Join(testDir, "binary")
+	if err := os.WriteFile(binary, []byte("fake binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Name:        "my-tool",
+		Version:     "1.0.0",
+		Description: "Tool & friends",
+		Author:      "Test Author <test@example.com>",
+		License:     "MIT",
+		GitHub:      config.GitHubConfig{Owner: "test", Repo: "my-tool"},
+		Binaries: map[string]string{
+			"linux-amd64":   binary,
+			"darwin-arm64":  binary,
+			"windows-amd64": binary,
+		},
+	}
+	cfg.Packages.Dotnet = config.DotnetConfig{PackageID: "Example.MyTool"}
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+	// Keep dotnet pack out of the test so the generated project is returned
+	t.Setenv("PATH", "")
+
+	p := New()
+	if err := p.Validate(cfg); err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+
+	output, err := p.Pack(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	if output != filepath.Join("dist", "dotnet") {
+		t.Errorf("Unexpected output %s", output)
+	}
+
+	for _, path := range []string{
+		"binaries/linux-x64/my-tool",
+		"binaries/osx-arm64/my-tool",
+		"binaries/win-x64/my-tool.exe",
+		"Program.cs",
+	} {
+		if _, err := os.Stat(filepath.Join(output, filepath.FromSlash(path))); err != nil {
+			t.Errorf("Expected %s: %v", path, err)
+		}
+	}
+
+	project, err := os.ReadFile(filepath.Join(output, "Example.MyTool.csproj"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<PackAsTool>true</PackAsTool>",
+		"<ToolCommandName>my-tool</ToolCommandName>",
+		"<PackageId>Example.MyTool</PackageId>",
+		"<TargetFramework>net8.0</TargetFramework>",
+		"<Description>Tool &amp; friends</Description>",
+		"<Authors>Test Author</Authors>",
+		"<RepositoryUrl>https://github.com/test/my-tool</RepositoryUrl>",
+	} {
+		if !strings.Contains(string(project), want) {
+			t.Errorf("Project missing %q", want)
+		}
+	}
+}
+
+func TestRID(t *testing.T) {
+	tests := map[string]string{
+		"linux-amd64":   "linux-x64",
+		"linux-arm64":   "linux-arm64",
+		"darwin-amd64":  "osx-x64",
+		"windows-386":   "win-x86",
+		"windows-arm64": "win-arm64",
+	}
+	for platform, want := range tests {
+		if got := RID(platform); got != want {
+			t.Errorf("RID(%s) = %s, expected %s", platform, got, want)
+		}
+	}
+}
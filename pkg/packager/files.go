@@ -0,0 +1,69 @@
+package packager
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+)
+
+// File is an entry from cfg.Files resolved for one format
+type File struct {
+	Src string // file or directory on disk
+	Dst string // slash-separated install path
+}
+
+// ExtraFiles returns cfg.Files as installed by format. Relative
+// destinations are placed under prefix, which Linux packages set to /usr;
+// with no prefix every destination is relative to the package's install
+// directory. A destination ending in / receives the source by name, and
+// entries whose destination for format is "-" are left out.
+func ExtraFiles(cfg *config.Config, format, prefix string) []File {
+	var files []File
+	for _, file := range cfg.Files {
+		dst := file.Dst
+		if override, ok := file.Formats[format]; ok {
+			dst = override
+		}
+		if dst == "-" {
+			continue
+		}
+		if dst == "" && prefix != "" {
+			dst = "share/" + cfg.Name + "/"
+		}
+		if dst == "" || strings.HasSuffix(dst, "/") {
+			dst += filepath.Base(file.Src)
+		}
+		if prefix == "" {
+			dst = path.Clean(strings.TrimPrefix(dst, "/"))
+		} else if !path.IsAbs(dst) {
+			dst = path.Join(prefix, dst)
+		}
+		files = append(files, File{Src: file.Src, Dst: path.Clean(dst)})
+	}
+	return files
+}
+
+// StageFiles copies files into root, each at its destination
+func StageFiles(root string, files []File, opts paths.CopyOptions) error {
+	for _, file := range files {
+		dst := filepath.Join(root, paths.Native(strings.TrimPrefix(file.Dst, "/")))
+		info, err := os.Stat(file.Src)
+		if err != nil {
+			return fmt.Errorf("files: %w", err)
+		}
+		if info.IsDir() {
+			err = paths.CopyTree(file.Src, dst, opts)
+		} else {
+			err = paths.CopyFile(file.Src, dst, opts)
+		}
+		if err != nil {
+			return fmt.Errorf("files: failed to copy %s: %w", file.Src, err)
+		}
+	}
+	return nil
+}
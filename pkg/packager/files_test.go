@@ -0,0 +1,76 @@
+package packager
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+)
+
+func TestExtraFiles(t *testing.T) {
+	cfg := &config.Config{
+		Name: "myapp",
+		Files: []config.FileConfig{
+			{Src: "examples"},
+			{Src: "config/default.yaml", Dst: "/etc/myapp/config.yaml", Formats: map[string]string{"msi": "config.yaml", "dmg": "-"}},
+			{Src: "plugins", Dst: "lib/myapp/"},
+		},
+	}
+
+	tests := []struct {
+		format string
+		prefix string
+		want   []File
+	}{
+		{"deb", "/usr", []File{
+			{"examples", "/usr/share/myapp/examples"},
+			{"config/default.yaml", "/etc/myapp/config.yaml"},
+			{"plugins", "/usr/lib/myapp/plugins"},
+		}},
+		{"msi", "", []File{
+			{"examples", "examples"},
+			{"config/default.yaml", "config.yaml"},
+			{"plugins", "lib/myapp/plugins"},
+		}},
+		{"dmg", "", []File{
+			{"examples", "examples"},
+			{"plugins", "lib/myapp/plugins"},
+		}},
+	}
+	for _, tt := range tests {
+		if got := ExtraFiles(cfg, tt.format, tt.prefix); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ExtraFiles(%s) = %v, want %v", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestStageFiles(t *testing.T) {
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "examples"), 0755)
+	os.WriteFile(filepath.Join(src, "examples", "basic.yaml"), []byte("basic"), 0644)
+	os.WriteFile(filepath.Join(src, "default.yaml"), []byte("default"), 0644)
+
+	root := t.TempDir()
+	files := []File{
+		{filepath.Join(src, "examples"), "/usr/share/myapp/examples"},
+		{filepath.Join(src, "default.yaml"), "/etc/myapp/config.yaml"},
+	}
+	if err := StageFiles(root, files, paths.CopyOptions{}); err != nil {
+		t.Fatalf("StageFiles() error = %v", err)
+	}
+	for path, want := range map[string]string{
+		"usr/share/myapp/examples/basic.yaml": "basic",
+		"etc/myapp/config.yaml":               "default",
+	} {
+		if data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(path))); err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", path, data, err, want)
+		}
+	}
+
+	if err := StageFiles(root, []File{{filepath.Join(src, "missing"), "missing"}}, paths.CopyOptions{}); err == nil {
+		t.Error("StageFiles() should fail for a missing source")
+	}
+}
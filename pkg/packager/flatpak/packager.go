@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
 )
 
 type Packager struct{}
@@ -23,23 +25,18 @@ func (p *Packager) Name() string {
 
 func (p *Packager) Validate(cfg *config.Config) error {
 	if cfg.Homepage == "" {
-		return fmt.Errorf("homepage is required for flatpak manifest")
+		return errors.NotConfiguredError("homepage is required for flatpak manifest")
 	}
 	return nil
 }
 
 func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
 	// Find Linux binary
-	var linuxBinary string
-	for arch, path := range cfg.Binaries {
-		if strings.HasPrefix(arch, "linux-") {
-			linuxBinary = path
-			break
-		}
-	}
-	if linuxBinary == "" {
+	binary, ok := packager.PrimaryLinuxBinary(cfg)
+	if !ok {
 		return "", fmt.Errorf("no Linux binary found")
 	}
+	linuxBinary := binary.Path
 
 	appId := fmt.Sprintf("dev.bagboy.%s", strings.Title(cfg.Name))
 
@@ -0,0 +1,393 @@
+package freebsd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+)
+
+// prefix is the FreeBSD local prefix packages install under
+const prefix = "/usr/local"
+
+// licenseNames maps SPDX identifiers to the names the ports tree uses
+var licenseNames = map[string]string{
+	"Apache-2.0":   "APACHE20",
+	"BSD-2-Clause": "BSD2CLAUSE",
+	"BSD-3-Clause": "BSD3CLAUSE",
+	"GPL-2.0":      "GPLv2",
+	"GPL-3.0":      "GPLv3",
+	"ISC":          "ISCL",
+	"LGPL-3.0":     "LGPL3",
+	"MPL-2.0":      "MPL20",
+}
+
+type Packager struct{}
+
+func New() *Packager {
+	return &Packager{}
+}
+
+func (p *Packager) Name() string {
+	return "freebsd"
+}
+
+func (p *Packager) Validate(cfg *config.Config) error {
+	if len(packager.PlatformBinaries(cfg, "freebsd")) == 0 {
+		return errors.NoPlatformBinaryError("a freebsd binary is required for FreeBSD packages")
+	}
+	if _, err := masterSites(cfg); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
+	return packager.Primary(p.PackArtifacts(ctx, cfg))
+}
+
+// PackArtifacts builds one package per architecture, amd64 first when
+// present, and writes the ports skeleton for all of them
+func (p *Packager) PackArtifacts(ctx context.Context, cfg *config.Config) ([]packager.Artifact, error) {
+	binaries := packager.PlatformBinaries(cfg, "freebsd")
+	if len(binaries) == 0 {
+		return nil, fmt.Errorf("no FreeBSD binary found")
+	}
+
+	var artifacts []packager.Artifact
+	for _, binary := range binaries {
+		output, err := p.packArch(ctx, cfg, binary)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, packager.Artifact{Path: output, OS: "freebsd", Arch: binary.Arch})
+	}
+
+	if err := p.createPort(filepath.Join("dist", "freebsd", "ports"), cfg, binaries); err != nil {
+		return nil, fmt.Errorf("failed to create ports skeleton: %w", err)
+	}
+
+	return artifacts, nil
+}
+
+// Origin returns the port origin, category/name
+func Origin(cfg *config.Config) string {
+	category := cfg.Packages.FreeBSD.Category
+	if category == "" {
+		category = "sysutils"
+	}
+	return category + "/" + cfg.Name
+}
+
+// ABI returns the package ABI string for a Go architecture
+func ABI(cfg *config.Config, goarch string) string {
+	version := cfg.Packages.FreeBSD.OSVersion
+	if version == "" {
+		version = "14"
+	}
+	return fmt.Sprintf("FreeBSD:%s:%s", version, packager.FreeBSDArch(goarch))
+}
+
+// Manifest returns the +MANIFEST for the binary at binaryPath. pkg reads
+// UCL, which accepts JSON.
+func Manifest(cfg *config.Config, goarch, binaryPath string) ([]byte, error) {
+	info, err := os.Stat(binaryPath)
+	if err != nil {
+		return nil, err
+	}
+	sum, err := fileSHA256(binaryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := map[string]interface{}{
+		"name":       cfg.Name,
+		"origin":     Origin(cfg),
+		"version":    cfg.Version,
+		"comment":    comment(cfg),
+		"desc":       cfg.Description,
+		"maintainer": maintainer(cfg),
+		"www":        cfg.Homepage,
+		"abi":        ABI(cfg, goarch),
+		"prefix":     prefix,
+		"flatsize":   info.Size(),
+		"files": map[string]string{
+			prefix + "/bin/" + cfg.Name: sum,
+		},
+	}
+	if cfg.License != "" {
+		manifest["licenselogic"] = "single"
+		manifest["licenses"] = []string{license(cfg.License)}
+	}
+
+	return json.MarshalIndent(manifest, "", "  ")
+}
+
+func (p *Packager) packArch(ctx context.Context, cfg *config.Config, binary packager.PlatformBinary) (string, error) {
+	arch := packager.FreeBSDArch(binary.Arch)
+
+	workDir := filepath.Join("dist", "freebsd", arch)
+	if err := os.RemoveAll(workDir); err != nil {
+		return "", err
+	}
+
+	rootDir := filepath.Join(workDir, "root")
+	binPath := filepath.Join(rootDir, strings.TrimPrefix(prefix, "/"), "bin", cfg.Name)
+	if err := paths.CopyExecutable(binary.Path, binPath, paths.CopyOptions{FollowSymlinks: true}); err != nil {
+		return "", fmt.Errorf("failed to copy binary: %w", err)
+	}
+
+	manifest, err := Manifest(cfg, binary.Arch, binPath)
+	if err != nil {
+		return "", err
+	}
+	manifestPath := filepath.Join(workDir, "+MANIFEST")
+	if err := os.WriteFile(manifestPath, manifest, 0644); err != nil {
+		return "", err
+	}
+
+	outputPath := filepath.Join("dist", fmt.Sprintf("%s-%s-freebsd-%s.pkg", cfg.Name, cfg.Version, arch))
+
+	// Use pkg create on FreeBSD hosts; elsewhere pkg may be an unrelated tool
+	if _, err := exec.LookPath("pkg"); err == nil && runtime.GOOS == "freebsd" {
+		cmd := exec.CommandContext(ctx, "pkg", "create", "-M", manifestPath, "-r", rootDir, "-o", workDir)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("pkg create failed: %w\nOutput: %s", err, output)
+		}
+		created := filepath.Join(workDir, fmt.Sprintf("%s-%s.pkg", cfg.Name, cfg.Version))
+		if err := os.Rename(created, outputPath); err != nil {
+			return "", fmt.Errorf("failed to move package: %w", err)
+		}
+		return outputPath, nil
+	}
+
+	// Elsewhere write the package archive directly: the manifests first,
+	// then the files under their installed paths
+	if err := writePackage(outputPath, manifest, binPath, prefix+"/bin/"+cfg.Name); err != nil {
+		return "", fmt.Errorf("failed to create package: %w", err)
+	}
+	return outputPath, nil
+}
+
+func writePackage(outputPath string, manifest []byte, binPath, installPath string) error {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	for _, name := range []string{"+COMPACT_MANIFEST", "+MANIFEST"} {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(manifest))}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(manifest); err != nil {
+			return err
+		}
+	}
+
+	in, err := os.Open(binPath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	header := &tar.Header{Name: installPath, Mode: 0755, Size: info.Size(), Uname: "root", Gname: "wheel"}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, in); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// createPort writes a ports skeleton under portsDir/category/name. The
+// prebuilt binaries are the distfiles, so the port only installs them.
+func (p *Packager) createPort(portsDir string, cfg *config.Config, binaries []packager.PlatformBinary) error {
+	portDir := filepath.Join(portsDir, Origin(cfg))
+	if err := os.MkdirAll(portDir, 0755); err != nil {
+		return err
+	}
+
+	tmpl := `PORTNAME=	{{.Name}}
+DISTVERSION=	{{.Version}}
+CATEGORIES=	{{.Category}}
+MASTER_SITES=	{{.MasterSites}}
+{{- range .Distfiles}}
+DISTFILES_{{.Arch}}=	{{.File}}
+{{- end}}
+DIST_SUBDIR=	${PORTNAME}-${DISTVERSION}
+EXTRACT_ONLY=
+
+MAINTAINER=	{{.Maintainer}}
+COMMENT=	{{.Comment}}
+{{- if .Homepage}}
+WWW=		{{.Homepage}}
+{{- end}}
+{{- if .License}}
+
+LICENSE=	{{.License}}
+{{- end}}
+
+ONLY_FOR_ARCHS=	{{.Archs}}
+NO_BUILD=	yes
+NO_WRKSUBDIR=	yes
+
+PLIST_FILES=	bin/${PORTNAME}
+
+do-install:
+	${INSTALL_PROGRAM} ${DISTDIR}/${DIST_SUBDIR}/${DISTFILES} ${STAGEDIR}${PREFIX}/bin/${PORTNAME}
+
+.include <bsd.port.mk>
+`
+
+	t, err := packager.ParseTemplate(cfg, "freebsd/Makefile", tmpl)
+	if err != nil {
+		return err
+	}
+
+	type distfile struct {
+		Arch string
+		File string
+	}
+	sites, err := masterSites(cfg)
+	if err != nil {
+		return err
+	}
+	data := struct {
+		*config.Config
+		Category    string
+		MasterSites string
+		Distfiles   []distfile
+		Maintainer  string
+		Comment     string
+		License     string
+		Archs       string
+	}{
+		Config:      cfg,
+		Category:    strings.Split(Origin(cfg), "/")[0],
+		MasterSites: sites,
+		Maintainer:  maintainer(cfg),
+		Comment:     comment(cfg),
+	}
+	if cfg.License != "" {
+		data.License = license(cfg.License)
+	}
+
+	// distinfo records the checksum and size of each distfile, stored
+	// under DIST_SUBDIR since the binary names carry no version
+	var archs []string
+	distinfo := fmt.Sprintf("TIMESTAMP = %d\n", time.Now().Unix())
+	for _, binary := range binaries {
+		arch := packager.FreeBSDArch(binary.Arch)
+		file := filepath.Base(binary.Path)
+		archs = append(archs, arch)
+		data.Distfiles = append(data.Distfiles, distfile{Arch: arch, File: file})
+
+		info, err := os.Stat(binary.Path)
+		if err != nil {
+			return err
+		}
+		sum, err := fileSHA256(binary.Path)
+		if err != nil {
+			return err
+		}
+		distPath := fmt.Sprintf("%s-%s/%s", cfg.Name, cfg.Version, file)
+		distinfo += fmt.Sprintf("SHA256 (%s) = %s\nSIZE (%s) = %d\n", distPath, sum, distPath, info.Size())
+	}
+	data.Archs = strings.Join(archs, " ")
+
+	f, err := os.Create(filepath.Join(portDir, "Makefile"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := t.Execute(f, data); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(portDir, "distinfo"), []byte(distinfo), 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(portDir, "pkg-descr"), []byte(cfg.Description+"\n"), 0644)
+}
+
+// masterSites returns where the port fetches the binaries from: the
+// installer base URL, or the GitHub release for the version
+func masterSites(cfg *config.Config) (string, error) {
+	if cfg.Installer.BaseURL != "" {
+		return strings.TrimSuffix(cfg.Installer.BaseURL, "/") + "/", nil
+	}
+	if cfg.GitHub.Owner != "" && cfg.GitHub.Repo != "" {
+		return fmt.Sprintf("https://github.com/%s/%s/releases/download/%s${DISTVERSION}/", cfg.GitHub.Owner, cfg.GitHub.Repo, cfg.TagPrefix()), nil
+	}
+	return "", errors.NotConfiguredError("installer.base_url, or github.owner and github.repo, is required for the FreeBSD port's MASTER_SITES")
+}
+
+// maintainer returns the port maintainer's email; the ports tree requires
+// one, so unmaintained ports go to ports@FreeBSD.org
+func maintainer(cfg *config.Config) string {
+	if cfg.Packages.FreeBSD.Maintainer != "" {
+		return cfg.Packages.FreeBSD.Maintainer
+	}
+	for _, person := range cfg.Maintainers() {
+		if person.Email != "" {
+			return person.Email
+		}
+	}
+	return "ports@FreeBSD.org"
+}
+
+// comment returns the one-line summary, without the trailing period
+// portlint rejects
+func comment(cfg *config.Config) string {
+	summary, _, _ := strings.Cut(cfg.Description, "\n")
+	return strings.TrimSuffix(strings.TrimSpace(summary), ".")
+}
+
+func license(spdx string) string {
+	if name, ok := licenseNames[spdx]; ok {
+		return name
+	}
+	return spdx
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
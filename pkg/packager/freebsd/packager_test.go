@@ -0,0 +1,144 @@
+package freebsd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+)
+
+func TestFreeBSDPackager(t *testing.T) {
+	testDir := t.TempDir()
+	amd64 := filepath.Join(testDir, "testapp-freebsd-amd64")
+	arm64 := filepath.Join(testDir, "testapp-freebsd-arm64")
+	for _, path := range []string{amd64, arm64} {
+		if err := os.WriteFile(path, []byte("fake binary"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Name:        "testapp",
+		Version:     "1.0.0",
+		Description: "Test application.",
+		Author:      "Test Author <test@example.com>",
+		Homepage:    "https://github.com/test/testapp",
+		License:     "Apache-2.0",
+		Binaries: map[string]string{
+			"freebsd-amd64": amd64,
+			"freebsd-arm64": arm64,
+			"linux-amd64":   amd64,
+		},
+		GitHub: config.GitHubConfig{Owner: "test", Repo: "testapp"},
+	}
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	p := New()
+	if err := p.Validate(cfg); err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+
+	artifacts, err := p.PackArtifacts(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("PackArtifacts failed: %v", err)
+	}
+	if len(artifacts) != 2 {
+		t.Fatalf("Expected 2 packages, got %+v", artifacts)
+	}
+	outputPath := artifacts[0].Path
+	if outputPath != filepath.Join("dist", "testapp-1.0.0-freebsd-amd64.pkg") {
+		t.Errorf("Unexpected output path %s", outputPath)
+	}
+	if arm := artifacts[1]; arm.Path != filepath.Join("dist", "testapp-1.0.0-freebsd-aarch64.pkg") || arm.OS != "freebsd" || arm.Arch != "arm64" {
+		t.Errorf("Unexpected arm64 artifact %+v", arm)
+	}
+
+	// The package starts with the manifests
+	f, err := os.Open(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gr)
+	var names []string
+	var manifest map[string]interface{}
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, header.Name)
+		if header.Name == "+MANIFEST" {
+			if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+				t.Fatalf("Invalid +MANIFEST: %v", err)
+			}
+		}
+	}
+	expected := []string{"+COMPACT_MANIFEST", "+MANIFEST", "/usr/local/bin/testapp"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("Package entries = %v, expected %v", names, expected)
+	}
+	if manifest["abi"] != "FreeBSD:14:amd64" || manifest["origin"] != "sysutils/testapp" {
+		t.Errorf("Unexpected manifest: %v", manifest)
+	}
+	if manifest["comment"] != "Test application" || manifest["maintainer"] != "test@example.com" {
+		t.Errorf("Unexpected manifest: %v", manifest)
+	}
+
+	portDir := filepath.Join("dist", "freebsd", "ports", "sysutils", "testapp")
+	makefile, err := os.ReadFile(filepath.Join(portDir, "Makefile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"PORTNAME=\ttestapp",
+		"MASTER_SITES=\thttps://github.com/test/testapp/releases/download/v${DISTVERSION}/",
+		"DISTFILES_amd64=\ttestapp-freebsd-amd64",
+		"DISTFILES_aarch64=\ttestapp-freebsd-arm64",
+		"LICENSE=\tAPACHE20",
+		"ONLY_FOR_ARCHS=\tamd64 aarch64",
+		".include <bsd.port.mk>",
+	} {
+		if !strings.Contains(string(makefile), want) {
+			t.Errorf("Makefile missing %q", want)
+		}
+	}
+
+	distinfo, err := os.ReadFile(filepath.Join(portDir, "distinfo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(distinfo), "SIZE (testapp-1.0.0/testapp-freebsd-amd64) = 11") {
+		t.Errorf("Unexpected distinfo:\n%s", distinfo)
+	}
+
+	if _, err := os.Stat(filepath.Join(portDir, "pkg-descr")); err != nil {
+		t.Error("pkg-descr not created")
+	}
+}
+
+func TestFreeBSDPackager_Validate(t *testing.T) {
+	cfg := &config.Config{Name: "testapp", Binaries: map[string]string{"linux-amd64": "bin/testapp"}}
+	if err := New().Validate(cfg); err == nil {
+		t.Error("Expected validation error without a FreeBSD binary")
+	}
+
+	cfg.Binaries = map[string]string{"freebsd-amd64": "bin/testapp"}
+	if err := New().Validate(cfg); !errors.HasCode(err, errors.CodeNotConfigured) {
+		t.Errorf("Expected a not configured error without MASTER_SITES, got %v", err)
+	}
+}
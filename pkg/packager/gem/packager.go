@@ -0,0 +1,409 @@
+package gem
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+)
+
+// Packager builds one platform gem per binary, with a Ruby executable that
+// execs the bundled binary. When installer.base_url is set it also builds
+// a pure Ruby gem whose executable downloads the binary on first run, so
+// platforms without a platform gem still install.
+type Packager struct {
+	outputs map[string]string
+}
+
+func New() *Packager {
+	return &Packager{}
+}
+
+func (p *Packager) Name() string {
+	return "gem"
+}
+
+func (p *Packager) Validate(cfg *config.Config) error {
+	if cfg.Description == "" {
+		return errors.NotConfiguredError("description is required for gems")
+	}
+	if len(cfg.Binaries) == 0 {
+		return fmt.Errorf("at least one binary is required for gems")
+	}
+	return nil
+}
+
+// GemName returns the gem name, defaulting to the project name
+func GemName(cfg *config.Config) string {
+	if name := cfg.Packages.Gem.Name; name != "" {
+		return name
+	}
+	return cfg.Name
+}
+
+// Platform maps a platform such as linux-amd64 to the RubyGems platform
+// gem install matches, e.g. x86_64-linux
+func Platform(platform string) string {
+	goos, goarch, _ := strings.Cut(platform, "-")
+	cpu := map[string]string{
+		"amd64": "x86_64",
+		"arm64": "aarch64",
+		"386":   "x86",
+	}[goarch]
+	if cpu == "" {
+		cpu = goarch
+	}
+	switch goos {
+	case "darwin":
+		if goarch == "arm64" {
+			cpu = "arm64"
+		}
+	case "windows":
+		if goarch == "386" {
+			return "x86-mingw32"
+		}
+		if goarch == "amd64" {
+			cpu = "x64"
+		}
+		return cpu + "-mingw-ucrt"
+	}
+	return cpu + "-" + goos
+}
+
+func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
+	if err := p.Validate(cfg); err != nil {
+		return "", err
+	}
+
+	gemDir := filepath.Join("dist", "gem")
+	if err := os.MkdirAll(gemDir, 0755); err != nil {
+		return "", err
+	}
+
+	platforms := make([]string, 0, len(cfg.Binaries))
+	for platform := range cfg.Binaries {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	// The first gem is returned and the rest are reported through
+	// ArchOutputs, keyed by RubyGems platform
+	p.outputs = make(map[string]string)
+	var primary string
+	for _, platform := range platforms {
+		binary, err := os.ReadFile(cfg.Binaries[platform])
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s binary: %w", platform, err)
+		}
+		binaryName := cfg.Name
+		if strings.HasPrefix(platform, "windows-") {
+			binaryName += ".exe"
+		}
+
+		gemPlatform := Platform(platform)
+		files := []gemFile{
+			{path: "exe/" + cfg.Name, mode: 0755, data: []byte(execShim(binaryName))},
+			{path: "libexec/" + binaryName, mode: 0755, data: binary},
+		}
+		outputPath := filepath.Join(gemDir, fmt.Sprintf("%s-%s-%s.gem", GemName(cfg), cfg.Version, gemPlatform))
+		if err := writeGem(outputPath, cfg, gemPlatform, files); err != nil {
+			return "", fmt.Errorf("failed to create %s gem: %w", gemPlatform, err)
+		}
+		if primary == "" {
+			primary = outputPath
+		}
+		p.outputs[gemPlatform] = outputPath
+	}
+
+	if cfg.Installer.BaseURL != "" {
+		files := []gemFile{
+			{path: "exe/" + cfg.Name, mode: 0755, data: []byte(downloadShim(cfg))},
+		}
+		outputPath := filepath.Join(gemDir, fmt.Sprintf("%s-%s.gem", GemName(cfg), cfg.Version))
+		if err := writeGem(outputPath, cfg, "ruby", files); err != nil {
+			return "", fmt.Errorf("failed to create ruby gem: %w", err)
+		}
+		p.outputs["ruby"] = outputPath
+	}
+
+	return primary, nil
+}
+
+// ArchOutputs returns the gems written by the last Pack, keyed by RubyGems
+// platform
+func (p *Packager) ArchOutputs() map[string]string {
+	return p.outputs
+}
+
+type gemFile struct {
+	path string
+	mode int64
+	data []byte
+}
+
+// writeGem writes a .gem: a tar of the gzipped gemspec, the gzipped data
+// tarball and their checksums
+func writeGem(outputPath string, cfg *config.Config, platform string, files []gemFile) error {
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = file.path
+	}
+	spec, err := specification(cfg, platform, paths)
+	if err != nil {
+		return err
+	}
+	metadata, err := gzipBytes(spec)
+	if err != nil {
+		return err
+	}
+
+	var dataTar bytes.Buffer
+	tw := tar.NewWriter(&dataTar)
+	now := time.Now()
+	for _, file := range files {
+		header := &tar.Header{Name: file.path, Mode: file.mode, Size: int64(len(file.data)), ModTime: now}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	data, err := gzipBytes(dataTar.Bytes())
+	if err != nil {
+		return err
+	}
+
+	sha256Metadata, sha256Data := sha256.Sum256(metadata), sha256.Sum256(data)
+	sha512Metadata, sha512Data := sha512.Sum512(metadata), sha512.Sum512(data)
+	checksums := fmt.Sprintf("---\nSHA256:\n  metadata.gz: %x\n  data.tar.gz: %x\nSHA512:\n  metadata.gz: %x\n  data.tar.gz: %x\n",
+		sha256Metadata, sha256Data, sha512Metadata, sha512Data)
+	checksumsGz, err := gzipBytes([]byte(checksums))
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gw := tar.NewWriter(f)
+	for _, member := range []struct {
+		name string
+		data []byte
+	}{{"metadata.gz", metadata}, {"data.tar.gz", data}, {"checksums.yaml.gz", checksumsGz}} {
+		header := &tar.Header{Name: member.name, Mode: 0444, Size: int64(len(member.data)), ModTime: now}
+		if err := gw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := gw.Write(member.data); err != nil {
+			return err
+		}
+	}
+	return gw.Close()
+}
+
+// specification renders the YAML gemspec RubyGems stores in metadata.gz
+func specification(cfg *config.Config, platform string, files []string) ([]byte, error) {
+	tmpl := `--- !ruby/object:Gem::Specification
+name: {{quote .GemName}}
+version: !ruby/object:Gem::Version
+  version: {{quote .Version}}
+platform: {{.Platform}}
+authors:
+{{- range .Authors}}
+- {{quote .}}
+{{- else}} []
+{{- end}}
+autorequire:
+bindir: exe
+cert_chain: []
+date: {{.Date}}
+dependencies: []
+description: {{quote .Description}}
+email:
+{{- range .Emails}}
+- {{quote .}}
+{{- else}} []
+{{- end}}
+executables:
+- {{quote .Name}}
+extensions: []
+extra_rdoc_files: []
+files:
+{{- range .Files}}
+- {{quote .}}
+{{- end}}
+homepage: {{if .Homepage}}{{quote .Homepage}}{{end}}
+licenses:
+{{- if .License}}
+- {{quote .License}}
+{{- else}} []
+{{- end}}
+metadata:
+{{- if .SourceURL}}
+  source_code_uri: {{quote .SourceURL}}
+{{- else}} {}
+{{- end}}
+post_install_message:
+rdoc_options: []
+require_paths:
+- lib
+required_ruby_version: !ruby/object:Gem::Requirement
+  requirements:
+  - - ">="
+    - !ruby/object:Gem::Version
+      version: '0'
+required_rubygems_version: !ruby/object:Gem::Requirement
+  requirements:
+  - - ">="
+    - !ruby/object:Gem::Version
+      version: '0'
+requirements: []
+rubygems_version: 3.5.0
+signing_key:
+specification_version: 4
+summary: {{quote .Summary}}
+test_files: []
+`
+
+	t, err := packager.ParseTemplate(cfg, "gem/gemspec", tmpl, template.FuncMap{"quote": quote})
+	if err != nil {
+		return nil, err
+	}
+
+	data := struct {
+		*config.Config
+		GemName   string
+		Platform  string
+		Date      string
+		Authors   []string
+		Emails    []string
+		Files     []string
+		SourceURL string
+		Summary   string
+	}{
+		Config:   cfg,
+		GemName:  GemName(cfg),
+		Platform: platform,
+		Date:     time.Now().UTC().Format("2006-01-02") + " 00:00:00.000000000 Z",
+		Files:    files,
+	}
+	for _, person := range cfg.PeopleWithRole(config.RoleAuthor) {
+		data.Authors = append(data.Authors, person.Name)
+		if person.Email != "" {
+			data.Emails = append(data.Emails, person.Email)
+		}
+	}
+	if cfg.GitHub.Owner != "" && cfg.GitHub.Repo != "" {
+		data.SourceURL = fmt.Sprintf("https://github.com/%s/%s", cfg.GitHub.Owner, cfg.GitHub.Repo)
+	}
+	data.Summary, _, _ = strings.Cut(cfg.Description, "\n")
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// execShim is the executable of a platform gem, which replaces itself with
+// the bundled binary
+func execShim(binaryName string) string {
+	return fmt.Sprintf(`#!/usr/bin/env ruby
+# frozen_string_literal: true
+
+exec(File.expand_path("../libexec/%s", __dir__), *ARGV)
+`, binaryName)
+}
+
+// downloadShim is the executable of the pure Ruby gem. It downloads
+// <base_url>/<name>-<os>-<arch> into a per-version cache on first run, the
+// asset naming the npm and PyPI wrappers use.
+func downloadShim(cfg *config.Config) string {
+	tmpl := `#!/usr/bin/env ruby
+# frozen_string_literal: true
+
+require "fileutils"
+require "net/http"
+require "rbconfig"
+require "uri"
+
+os = case RbConfig::CONFIG["host_os"]
+     when /darwin/ then "darwin"
+     when /mswin|mingw|cygwin/ then "windows"
+     when /freebsd/ then "freebsd"
+     else "linux"
+     end
+arch = case RbConfig::CONFIG["host_cpu"]
+       when /x86_64|x64|amd64/ then "amd64"
+       when /aarch64|arm64/ then "arm64"
+       when /i[3-6]86|x86/ then "386"
+       else RbConfig::CONFIG["host_cpu"]
+       end
+asset = "{{.Name}}-#{os}-#{arch}#{os == "windows" ? ".exe" : ""}"
+binary = File.join(Dir.home, ".cache", "{{.Name}}", "{{.Version}}", asset)
+
+unless File.exist?(binary)
+  uri = URI("{{.BaseURL}}/#{asset}")
+  warn "Downloading #{uri}"
+  response = Net::HTTP.get_response(uri)
+  5.times do
+    break unless response.is_a?(Net::HTTPRedirection)
+    response = Net::HTTP.get_response(URI(response["location"]))
+  end
+  abort "Failed to download #{uri}: #{response.code}" unless response.is_a?(Net::HTTPSuccess)
+
+  FileUtils.mkdir_p(File.dirname(binary))
+  File.binwrite("#{binary}.part", response.body)
+  File.chmod(0o755, "#{binary}.part")
+  File.rename("#{binary}.part", binary)
+end
+
+exec(binary, *ARGV)
+`
+	var buf bytes.Buffer
+	template.Must(template.New("shim").Parse(tmpl)).Execute(&buf, struct {
+		*config.Config
+		BaseURL string
+	}{cfg, strings.TrimSuffix(cfg.Installer.BaseURL, "/")})
+	return buf.String()
+}
+
+// quote renders s as a double-quoted YAML scalar; JSON strings are valid
+// YAML
+func quote(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
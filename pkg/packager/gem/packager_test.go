@@ -0,0 +1,156 @@
+package gem
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestGemPackager(t *testing.T) {
+	testDir := t.TempDir()
+	binary := filepath.Join(testDir, "binary")
+	if err := os.WriteFile(binary, []byte("fake binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Name:        "my-tool",
+		Version:     "1.0.0",
+		Description: "A \"quoted\" tool",
+		Author:      "Test Author <test@example.com>",
+		License:     "MIT",
+		GitHub:      config.GitHubConfig{Owner: "test", Repo: "my-tool"},
+		Installer:   config.InstallerConfig{BaseURL: "https://example.com/releases/"},
+		Binaries: map[string]string{
+			"linux-amd64":   binary,
+			"darwin-arm64":  binary,
+			"windows-amd64": binary,
+		},
+	}
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	p := New()
+	if err := p.Validate(cfg); err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+
+	output, err := p.Pack(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	if output != filepath.Join("dist", "gem", "my-tool-1.0.0-arm64-darwin.gem") {
+		t.Errorf("Unexpected output %s", output)
+	}
+	for _, platform := range []string{"arm64-darwin", "x86_64-linux", "x64-mingw-ucrt", "ruby"} {
+		if _, ok := p.ArchOutputs()[platform]; !ok {
+			t.Errorf("Missing %s gem", platform)
+		}
+	}
+
+	members := readGem(t, p.ArchOutputs()["x86_64-linux"])
+	for _, name := range []string{"metadata.gz", "data.tar.gz", "checksums.yaml.gz"} {
+		if _, ok := members[name]; !ok {
+			t.Errorf("Gem missing %s", name)
+		}
+	}
+	spec := gunzip(t, members["metadata.gz"])
+	for _, want := range []string{
+		`name: "my-tool"`,
+		"platform: x86_64-linux",
+		`description: "A \"quoted\" tool"`,
+		`- "test@example.com"`,
+		`- "libexec/my-tool"`,
+		`source_code_uri: "https://github.com/test/my-tool"`,
+	} {
+		if !strings.Contains(spec, want) {
+			t.Errorf("Gemspec missing %q:\n%s", want, spec)
+		}
+	}
+
+	shim := readData(t, members["data.tar.gz"], "exe/my-tool")
+	if !strings.Contains(shim, `exec(File.expand_path("../libexec/my-tool", __dir__), *ARGV)`) {
+		t.Errorf("Unexpected executable:\n%s", shim)
+	}
+
+	members = readGem(t, p.ArchOutputs()["ruby"])
+	shim = readData(t, members["data.tar.gz"], "exe/my-tool")
+	if !strings.Contains(shim, `URI("https://example.com/releases/#{asset}")`) {
+		t.Errorf("Unexpected download shim:\n%s", shim)
+	}
+}
+
+func TestPlatform(t *testing.T) {
+	tests := map[string]string{
+		"linux-amd64":   "x86_64-linux",
+		"linux-arm64":   "aarch64-linux",
+		"darwin-amd64":  "x86_64-darwin",
+		"darwin-arm64":  "arm64-darwin",
+		"windows-amd64": "x64-mingw-ucrt",
+		"windows-386":   "x86-mingw32",
+	}
+	for platform, want := range tests {
+		if got := Platform(platform); got != want {
+			t.Errorf("Platform(%s) = %s, expected %s", platform, got, want)
+		}
+	}
+}
+
+func readGem(t *testing.T, path string) map[string][]byte {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	return readTar(t, f)
+}
+
+func readTar(t *testing.T, r io.Reader) map[string][]byte {
+	t.Helper()
+	members := make(map[string][]byte)
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		members[header.Name] = data
+	}
+	return members
+}
+
+func gunzip(t *testing.T, data []byte) string {
+	t.Helper()
+	gr, err := gzip.NewReader(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(gr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func readData(t *testing.T, data []byte, name string) string {
+	t.Helper()
+	files := readTar(t, strings.NewReader(gunzip(t, data)))
+	return string(files[name])
+}
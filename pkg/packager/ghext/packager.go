@@ -0,0 +1,118 @@
+package ghext
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+)
+
+// Topic is the repository topic gh extension search and browse list
+const Topic = "gh-extension"
+
+// Packager attaches the binaries as a precompiled gh CLI extension. gh
+// only treats repositories named gh-<name> as extensions and installs the
+// release asset whose name ends in <os>-<arch>.
+type Packager struct {
+	outputs map[string]string
+}
+
+func New() *Packager {
+	return &Packager{}
+}
+
+func (p *Packager) Name() string {
+	return "gh-extension"
+}
+
+func (p *Packager) Validate(cfg *config.Config) error {
+	if !strings.HasPrefix(cfg.GitHub.Repo, "gh-") {
+		return errors.NotConfiguredError("github.repo must be named gh-<name> for a gh extension")
+	}
+	if len(cfg.Binaries) == 0 {
+		return fmt.Errorf("at least one binary is required for a gh extension")
+	}
+	return nil
+}
+
+// AssetName returns the release asset name for a platform such as
+// linux-amd64, following gh-extension-precompile. Slashes in the tag, as
+// in myapp/v1.2.3, become dashes.
+func AssetName(cfg *config.Config, platform string) string {
+	name := fmt.Sprintf("%s_%s_%s", cfg.GitHub.Repo, strings.ReplaceAll(cfg.Tag(), "/", "-"), platform)
+	if strings.HasPrefix(platform, "windows-") {
+		name += ".exe"
+	}
+	return name
+}
+
+// Manifest returns the manifest.yml gh writes next to an installed binary
+// extension
+func Manifest(cfg *config.Config) string {
+	return fmt.Sprintf("owner: %s\nname: %s\nhost: github.com\ntag: %s\nispinned: false\n",
+		cfg.GitHub.Owner, cfg.GitHub.Repo, cfg.Tag())
+}
+
+func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
+	if err := p.Validate(cfg); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll("dist", 0755); err != nil {
+		return "", err
+	}
+
+	platforms := make([]string, 0, len(cfg.Binaries))
+	for platform := range cfg.Binaries {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	// The first platform is returned and the rest are reported through
+	// ArchOutputs, so publish attaches every one to the release
+	p.outputs = make(map[string]string)
+	var primary string
+	for _, platform := range platforms {
+		assetPath := filepath.Join("dist", AssetName(cfg, platform))
+		if err := paths.CopyExecutable(cfg.Binaries[platform], assetPath, paths.CopyOptions{FollowSymlinks: true}); err != nil {
+			return "", fmt.Errorf("failed to copy %s binary: %w", platform, err)
+		}
+		if primary == "" {
+			primary = assetPath
+		}
+		p.outputs[platform] = assetPath
+	}
+
+	// Lay out the extension as gh installs it, so it can be tried locally
+	// by copying the directory into gh's extensions directory
+	extDir := filepath.Join("dist", "gh-extension", cfg.GitHub.Repo)
+	if err := os.MkdirAll(extDir, 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(extDir, "manifest.yml"), []byte(Manifest(cfg)), 0644); err != nil {
+		return "", err
+	}
+	if binary, ok := cfg.Binaries[runtime.GOOS+"-"+runtime.GOARCH]; ok {
+		name := cfg.GitHub.Repo
+		if runtime.GOOS == "windows" {
+			name += ".exe"
+		}
+		if err := paths.CopyExecutable(binary, filepath.Join(extDir, name), paths.CopyOptions{FollowSymlinks: true}); err != nil {
+			return "", err
+		}
+	}
+
+	return primary, nil
+}
+
+// ArchOutputs returns the release assets written by the last Pack, keyed
+// by platform
+func (p *Packager) ArchOutputs() map[string]string {
+	return p.outputs
+}
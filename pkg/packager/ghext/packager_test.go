@@ -0,0 +1,94 @@
+package ghext
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestGHExtensionPackager(t *testing.T) {
+	testDir := t.TempDir()
+	binary := filepath.Join(testDir, "binary")
+	if err := os.WriteFile(binary, []byte("fake binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Name:    "test",
+		Version: "1.0.0",
+		GitHub:  config.GitHubConfig{Owner: "tester", Repo: "gh-test"},
+		Binaries: map[string]string{
+			"linux-amd64":   binary,
+			"windows-amd64": binary,
+		},
+	}
+
+	cfg.Binaries[runtime.GOOS+"-"+runtime.GOARCH] = binary
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	p := New()
+	if err := p.Validate(cfg); err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+
+	if _, err := p.Pack(context.Background(), cfg); err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	outputs := p.ArchOutputs()
+	expected := map[string]string{
+		"linux-amd64":   "gh-test_v1.0.0_linux-amd64",
+		"windows-amd64": "gh-test_v1.0.0_windows-amd64.exe",
+	}
+	for platform, name := range expected {
+		if outputs[platform] != filepath.Join("dist", name) {
+			t.Errorf("Expected %s asset %s, got %s", platform, name, outputs[platform])
+		}
+		if _, err := os.Stat(filepath.Join("dist", name)); err != nil {
+			t.Errorf("Asset %s not created", name)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join("dist", "gh-extension", "gh-test", "gh-test")); err != nil && runtime.GOOS != "windows" {
+		t.Error("Extension binary for this platform not created")
+	}
+
+	manifest, err := os.ReadFile(filepath.Join("dist", "gh-extension", "gh-test", "manifest.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(manifest) != "owner: tester\nname: gh-test\nhost: github.com\ntag: v1.0.0\nispinned: false\n" {
+		t.Errorf("Unexpected manifest.yml:\n%s", manifest)
+	}
+}
+
+func TestAssetName_TagPattern(t *testing.T) {
+	cfg := &config.Config{Name: "test", Version: "1.2.3", TagPattern: `^gh-test/v(.+)$`}
+	cfg.GitHub = config.GitHubConfig{Owner: "tester", Repo: "gh-test"}
+
+	if got := AssetName(cfg, "linux-amd64"); got != "gh-test_gh-test-v1.2.3_linux-amd64" {
+		t.Errorf("AssetName() = %s", got)
+	}
+	if manifest := Manifest(cfg); !strings.Contains(manifest, "tag: gh-test/v1.2.3\n") {
+		t.Errorf("Unexpected manifest.yml:\n%s", manifest)
+	}
+}
+
+func TestGHExtensionPackager_Validate(t *testing.T) {
+	cfg := &config.Config{
+		Name:     "test",
+		GitHub:   config.GitHubConfig{Owner: "tester", Repo: "test"},
+		Binaries: map[string]string{"linux-amd64": "bin/test"},
+	}
+	if err := New().Validate(cfg); err == nil {
+		t.Error("Expected validation error for a repository not named gh-<name>")
+	}
+}
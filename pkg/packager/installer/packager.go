@@ -2,12 +2,20 @@ package installer
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"text/template"
+	"sort"
+	"strings"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/encrypt"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/upload"
 )
 
 type Packager struct{}
@@ -21,12 +29,90 @@ func (p *Packager) Name() string {
 }
 
 func (p *Packager) Validate(cfg *config.Config) error {
+	if cfg.Installer.Private {
+		if cfg.GitHub.Owner == "" || cfg.GitHub.Repo == "" {
+			return errors.NotConfiguredError("github.owner and github.repo are required for a private installer")
+		}
+		return nil
+	}
 	if cfg.Installer.BaseURL == "" {
-		return fmt.Errorf("installer.base_url is required")
+		return errors.NotConfiguredError("installer.base_url is required")
 	}
 	return nil
 }
 
+// verifyScript pins the SHA-256 of each asset and defines verify, which
+// the shell installers run on the binary before installing it. It
+// expects BINARY_NAME to be set.
+const verifyScript = `# Pinned SHA-256 of each asset. The binary is never installed or run
+# unless it matches, wherever it came from.
+CHECKSUMS="
+{{- range .Checksums}}
+{{.SHA256}}  {{.Asset}}
+{{- end}}
+"
+
+EXPECTED="$(printf '%s\n' "$CHECKSUMS" | awk -v asset="$BINARY_NAME" '$2 == asset { print $1 }')"
+if [[ -z "$EXPECTED" ]]; then
+  echo "No pinned checksum for ${BINARY_NAME} - ${OS}-${ARCH} is not supported by this installer"
+  exit 1
+fi
+
+sha256() {
+  if command -v sha256sum >/dev/null 2>&1; then
+    sha256sum "$1" | awk '{ print $1 }'
+  elif command -v shasum >/dev/null 2>&1; then
+    shasum -a 256 "$1" | awk '{ print $1 }'
+  else
+    echo "sha256sum or shasum is required to verify ${BIN_NAME}" >&2
+  fi
+}
+
+# verify checks $1 against the pinned checksum, removing it on mismatch
+verify() {
+  ACTUAL="$(sha256 "$1")"
+  if [[ "$ACTUAL" != "$EXPECTED" ]]; then
+    echo "⚠ Checksum mismatch for ${BINARY_NAME}: expected ${EXPECTED}, got ${ACTUAL:-nothing}"
+    rm -f "$1"
+    return 1
+  fi
+  echo "✓ Checksum verified"
+}
+`
+
+// decryptScript decrypts the downloaded asset with age or gpg and verifies
+// the binary, when assets are encrypted
+const decryptScript = `{{- if .Encrypted}}
+
+# Decrypt
+{{- if eq .EncryptedExt ".age"}}
+AGE_IDENTITY="${AGE_IDENTITY:-$HOME/.config/age/keys.txt}"
+if ! command -v age >/dev/null 2>&1; then
+  echo "age is required to decrypt ${BIN_NAME} - see https://age-encryption.org"
+  exit 1
+fi
+age --decrypt -i "$AGE_IDENTITY" -o "/tmp/${BIN_NAME}" "/tmp/${BIN_NAME}.age"
+{{- else}}
+if ! command -v gpg >/dev/null 2>&1; then
+  echo "gpg is required to decrypt ${BIN_NAME}"
+  exit 1
+fi
+gpg --batch --yes --decrypt -o "/tmp/${BIN_NAME}" "/tmp/${BIN_NAME}.gpg"
+{{- end}}
+rm -f "/tmp/${BIN_NAME}{{.EncryptedExt}}"
+echo "✓ Decrypted ${BIN_NAME}"
+verify "/tmp/${BIN_NAME}" || exit 1
+{{- end}}`
+
+// encryptedExt returns the extension encrypted assets carry, or "" when
+// assets are not encrypted
+func encryptedExt(cfg *config.Config) string {
+	if !cfg.Encryption.Enabled {
+		return ""
+	}
+	return encrypt.NewEncryptor(&cfg.Encryption).Extension()
+}
+
 func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
 	tmpl := `#!/bin/bash
 set -e
@@ -42,10 +128,12 @@ ARCH="$(uname -m)"
 
 # Config
 VERSION="${VERSION:-{{.Version}}}"
-BASE_URL="{{.BaseURL}}"
 BIN_NAME="{{.Name}}"
 INSTALL_PATH="${INSTALL_PATH:-{{.InstallPath}}}"
 
+# Base URLs serving the assets, tried in order
+MIRRORS="${MIRRORS:-{{.Mirrors}}}"
+
 # Determine binary name
 case "$OS" in
   darwin)
@@ -60,29 +148,40 @@ case "$OS" in
     ;;
 esac
 
-DOWNLOAD_URL="${BASE_URL}/${BINARY_NAME}"
+` + verifyScript + `
+# download fetches the asset from BASE_URL into $1
+download() {
+{{- if .Encrypted}}
+  # Assets are encrypted for private distribution
+  DOWNLOAD_URL="${BASE_URL}/${BINARY_NAME}{{.EncryptedExt}}"
+{{- else}}
+  DOWNLOAD_URL="${BASE_URL}/${BINARY_NAME}"
+{{- end}}
+  echo "Downloading from: ${DOWNLOAD_URL}"
+  curl -fsSL "$DOWNLOAD_URL" -o "$1"
+}
 
 echo "Installing ${BIN_NAME} ${VERSION}..."
-echo "Downloading from: ${DOWNLOAD_URL}"
-
-# Download
-curl -fsSL "$DOWNLOAD_URL" -o "/tmp/${BIN_NAME}"
-chmod +x "/tmp/${BIN_NAME}"
 
-{{if .VerifyChecksum}}
-# Verify checksum (if available)
-if command -v sha256sum >/dev/null 2>&1; then
-  CHECKSUMS_URL="${BASE_URL}/checksums.txt"
-  if curl -fsSL "$CHECKSUMS_URL" -o "/tmp/checksums.txt" 2>/dev/null; then
-    cd /tmp
-    if sha256sum -c checksums.txt --ignore-missing 2>/dev/null | grep -q "${BIN_NAME}"; then
-      echo "✓ Checksum verified"
-    else
-      echo "⚠ Checksum verification failed, but continuing..."
-    fi
+# Download, falling back to the next mirror on failure
+DOWNLOADED=""
+for BASE_URL in $MIRRORS; do
+  if ! download "/tmp/${BIN_NAME}{{.EncryptedExt}}"; then
+    echo "⚠ Download from ${BASE_URL} failed"
+    continue
   fi
+{{- if not .Encrypted}}
+  verify "/tmp/${BIN_NAME}" || continue
+{{- end}}
+  DOWNLOADED=1
+  break
+done
+if [[ -z "$DOWNLOADED" ]]; then
+  echo "Failed to download ${BINARY_NAME} from any mirror"
+  exit 1
 fi
-{{end}}
+` + decryptScript + `
+chmod +x "/tmp/${BIN_NAME}"
 
 # Install (with sudo if needed)
 if [[ -w "$INSTALL_PATH" ]]; then
@@ -94,23 +193,40 @@ fi
 
 echo "✓ Installed ${BIN_NAME} to ${INSTALL_PATH}/${BIN_NAME}"
 echo ""
-echo "Run '${BIN_NAME} --help' to get started!"`
+{{- if .Message}}
+{{- range .Message}}
+{{.}}
+{{- end}}
+{{- else}}
+echo "Run '${BIN_NAME} --help' to get started!"
+{{- end}}`
+
+	t, err := packager.ParseTemplate(cfg, "installer/installer", tmpl)
+	if err != nil {
+		return "", err
+	}
 
-	t, err := template.New("installer").Parse(tmpl)
+	checksums, err := Checksums(cfg, "linux", "darwin")
 	if err != nil {
 		return "", err
 	}
 
 	data := struct {
 		*config.Config
-		BaseURL        string
-		InstallPath    string
-		VerifyChecksum bool
+		Mirrors      string
+		Checksums    []Checksum
+		InstallPath  string
+		Encrypted    bool
+		EncryptedExt string
+		Message      []string
 	}{
-		Config:         cfg,
-		BaseURL:        cfg.Installer.BaseURL,
-		InstallPath:    cfg.Installer.InstallPath,
-		VerifyChecksum: cfg.Installer.VerifyChecksum,
+		Config:       cfg,
+		Message:      packager.ShellEcho(cfg.PostInstall.MessageFor("installer")),
+		Mirrors:      strings.Join(MirrorURLs(cfg), " "),
+		Checksums:    checksums,
+		InstallPath:  cfg.Installer.InstallPath,
+		Encrypted:    cfg.Encryption.Enabled,
+		EncryptedExt: encryptedExt(cfg),
 	}
 
 	outputPath := filepath.Join("dist", "install.sh")
@@ -133,5 +249,398 @@ echo "Run '${BIN_NAME} --help' to get started!"`
 		return "", err
 	}
 
+	if err := WriteMirrorList(filepath.Join("dist", MirrorListName), cfg); err != nil {
+		return "", fmt.Errorf("failed to write mirror list: %w", err)
+	}
+
+	// The PowerShell installer cannot decrypt assets
+	if !cfg.Encryption.Enabled {
+		if err := p.createPowerShellInstaller(filepath.Join("dist", "install.ps1"), cfg); err != nil {
+			return "", fmt.Errorf("failed to create PowerShell installer: %w", err)
+		}
+	}
+
+	if cfg.Installer.Private {
+		privatePath := filepath.Join("dist", "install-private.sh")
+		if err := p.createPrivateInstaller(privatePath, cfg); err != nil {
+			return "", fmt.Errorf("failed to create private installer: %w", err)
+		}
+	}
+
 	return outputPath, nil
 }
+
+// Checksum pins the SHA-256 of one release asset
+type Checksum struct {
+	Asset  string
+	SHA256 string
+}
+
+// AssetName returns the name the installers download a platform's binary
+// as, e.g. myapp-linux-amd64
+func AssetName(cfg *config.Config, platform string) string {
+	return packager.AssetName(cfg, platform)
+}
+
+// Checksums hashes the configured binaries for the given operating systems
+func Checksums(cfg *config.Config, goos ...string) ([]Checksum, error) {
+	platforms := make([]string, 0, len(cfg.Binaries))
+	for platform := range cfg.Binaries {
+		platformOS, _, _ := strings.Cut(platform, "-")
+		for _, want := range goos {
+			if platformOS == want {
+				platforms = append(platforms, platform)
+			}
+		}
+	}
+	sort.Strings(platforms)
+
+	checksums := make([]Checksum, 0, len(platforms))
+	for _, platform := range platforms {
+		f, err := os.Open(cfg.Binaries[platform])
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s binary: %w", platform, err)
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s binary: %w", platform, err)
+		}
+		checksums = append(checksums, Checksum{Asset: AssetName(cfg, platform), SHA256: hex.EncodeToString(h.Sum(nil))})
+	}
+	return checksums, nil
+}
+
+// MirrorListName is the file listing the mirrors, published with the
+// release assets
+const MirrorListName = "mirrors.txt"
+
+// MirrorURLs returns base_url followed by the GitHub and GitLab releases,
+// the S3 bucket, the configured mirrors and the SourceForge mirror, in the
+// order the installers try them
+func MirrorURLs(cfg *config.Config) []string {
+	candidates := []string{cfg.Installer.BaseURL}
+	if cfg.GitHub.Release.Enabled && cfg.GitHub.Owner != "" && cfg.GitHub.Repo != "" {
+		candidates = append(candidates, fmt.Sprintf("https://github.com/%s/%s/releases/download/%s", cfg.GitHub.Owner, cfg.GitHub.Repo, cfg.Tag()))
+	}
+	if cfg.GitLab.Release.Enabled && cfg.GitLab.Project != "" {
+		candidates = append(candidates, cfg.GitLab.DownloadURL(cfg.Tag()))
+	}
+	candidates = append(candidates, S3URL(cfg))
+	candidates = append(candidates, cfg.Installer.Mirrors...)
+	candidates = append(candidates, SourceForgeURL(cfg))
+
+	var urls []string
+	seen := make(map[string]bool)
+	for _, url := range candidates {
+		url = strings.TrimSuffix(url, "/")
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+		urls = append(urls, url)
+	}
+	return urls
+}
+
+// WriteMirrorList writes the mirrors to path, one base URL per line
+func WriteMirrorList(path string, cfg *config.Config) error {
+	var b strings.Builder
+	for _, url := range MirrorURLs(cfg) {
+		b.WriteString(url + "\n")
+	}
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// SourceForgeDir returns the directory assets are mirrored to under the
+// SourceForge project's files
+func SourceForgeDir(cfg *config.Config) string {
+	if dir := strings.Trim(cfg.Installer.SourceForge.Dir, "/"); dir != "" {
+		return dir
+	}
+	return cfg.Version
+}
+
+// SourceForgeURL returns the download base URL of the SourceForge mirror,
+// or "" when none is configured. downloads.sourceforge.net redirects to a
+// nearby mirror.
+func SourceForgeURL(cfg *config.Config) string {
+	project := cfg.Installer.SourceForge.Project
+	if project == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://downloads.sourceforge.net/project/%s/%s", project, SourceForgeDir(cfg))
+}
+
+// S3Dir returns the key prefix assets are mirrored to in the S3 bucket,
+// <prefix>/<version>
+func S3Dir(cfg *config.Config) string {
+	if prefix := strings.Trim(cfg.Installer.S3.Prefix, "/"); prefix != "" {
+		return prefix + "/" + cfg.Version
+	}
+	return cfg.Version
+}
+
+// S3URL returns the download base URL of the S3 mirror, or "" when none
+// is configured
+func S3URL(cfg *config.Config) string {
+	s3 := cfg.Installer.S3
+	if s3.Bucket == "" {
+		return ""
+	}
+	return upload.S3URL(s3.Bucket, s3.Region, s3.Endpoint, S3Dir(cfg))
+}
+
+// createPowerShellInstaller writes install.ps1 for Windows, with the same
+// mirror fallback and pinned checksums as install.sh
+func (p *Packager) createPowerShellInstaller(path string, cfg *config.Config) error {
+	tmpl := `# {{.Name}} installer script for Windows
+# Generated by bagboy
+#
+# Usage: irm https://example.com/install.ps1 | iex
+
+$ErrorActionPreference = "Stop"
+$ProgressPreference = "SilentlyContinue"
+
+$Version = if ($env:VERSION) { $env:VERSION } else { "{{.Version}}" }
+$BinName = "{{.Name}}"
+$InstallPath = if ($env:INSTALL_PATH) { $env:INSTALL_PATH } else { Join-Path $env:LOCALAPPDATA "Programs\{{.Name}}" }
+
+# Base URLs serving the assets, tried in order
+$Mirrors = if ($env:MIRRORS) { $env:MIRRORS -split '\s+' } else { @({{range $i, $m := .Mirrors}}{{if $i}}, {{end}}"{{$m}}"{{end}}) }
+
+# Pinned SHA-256 of each asset. The binary is never installed or run
+# unless it matches, whichever mirror it came from.
+$Checksums = @{
+{{- range .Checksums}}
+    "{{.Asset}}" = "{{.SHA256}}"
+{{- end}}
+}
+
+$Arch = switch ($env:PROCESSOR_ARCHITECTURE) {
+    "AMD64" { "amd64" }
+    "ARM64" { "arm64" }
+    "x86" { "386" }
+    default { throw "Unsupported architecture: $env:PROCESSOR_ARCHITECTURE" }
+}
+$Asset = "$BinName-windows-$Arch.exe"
+$Expected = $Checksums[$Asset]
+if (-not $Expected) {
+    throw "No pinned checksum for $Asset - windows-$Arch is not supported by this installer"
+}
+
+Write-Host "Installing $BinName $Version..."
+
+# Download, falling back to the next mirror on failure
+$Temp = Join-Path ([System.IO.Path]::GetTempPath()) $Asset
+$Downloaded = $false
+foreach ($Mirror in $Mirrors) {
+    $Url = "$Mirror/$Asset"
+    Write-Host "Downloading from: $Url"
+    try {
+        Invoke-WebRequest -Uri $Url -OutFile $Temp -UseBasicParsing
+    } catch {
+        Write-Host "⚠ Download from $Mirror failed"
+        continue
+    }
+    $Actual = (Get-FileHash -Algorithm SHA256 -Path $Temp).Hash.ToLower()
+    if ($Actual -ne $Expected) {
+        Write-Host "⚠ Checksum mismatch for ${Asset}: expected $Expected, got $Actual"
+        Remove-Item -Force $Temp
+        continue
+    }
+    Write-Host "✓ Checksum verified"
+    $Downloaded = $true
+    break
+}
+if (-not $Downloaded) {
+    throw "Failed to download $Asset from any mirror"
+}
+
+New-Item -ItemType Directory -Force -Path $InstallPath | Out-Null
+Move-Item -Force -Path $Temp -Destination (Join-Path $InstallPath "$BinName.exe")
+
+$UserPath = [Environment]::GetEnvironmentVariable("Path", "User")
+if (($UserPath -split ';') -notcontains $InstallPath) {
+    [Environment]::SetEnvironmentVariable("Path", "$UserPath;$InstallPath", "User")
+    Write-Host "Added $InstallPath to your PATH - restart your shell to pick it up"
+}
+
+Write-Host "✓ Installed $BinName to $InstallPath\$BinName.exe"
+Write-Host ""
+{{- if .Message}}
+{{- range .Message}}
+{{.}}
+{{- end}}
+{{- else}}
+Write-Host "Run '$BinName --help' to get started!"
+{{- end}}
+`
+
+	t, err := packager.ParseTemplate(cfg, "installer/install.ps1", tmpl)
+	if err != nil {
+		return err
+	}
+
+	checksums, err := Checksums(cfg, "windows")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data := struct {
+		*config.Config
+		Mirrors   []string
+		Checksums []Checksum
+		Message   []string
+	}{
+		Config:    cfg,
+		Mirrors:   MirrorURLs(cfg),
+		Checksums: checksums,
+		Message:   packager.WriteHost(cfg.PostInstall.MessageFor("installer")),
+	}
+	return t.Execute(f, data)
+}
+
+// createPrivateInstaller writes an install script that downloads release
+// assets through the GitHub API with a token, so it works for private repos
+func (p *Packager) createPrivateInstaller(path string, cfg *config.Config) error {
+	tmpl := `#!/bin/bash
+set -e
+
+# {{.Name}} installer script for private GitHub releases
+# Generated by bagboy
+#
+# Usage: GITHUB_TOKEN=<token> bash install-private.sh
+# The token needs read access to {{.Owner}}/{{.Repo}} (contents:read).
+
+TOKEN="${GITHUB_TOKEN:-${GH_TOKEN}}"
+if [[ -z "$TOKEN" ]]; then
+  echo "GITHUB_TOKEN (or GH_TOKEN) must be set to download from {{.Owner}}/{{.Repo}}"
+  exit 1
+fi
+
+# Detection
+OS="$(uname -s | tr '[:upper:]' '[:lower:]')"
+ARCH="$(uname -m)"
+[[ "$ARCH" == "x86_64" ]] && ARCH="amd64"
+[[ "$ARCH" == "aarch64" ]] && ARCH="arm64"
+
+# Config
+VERSION="{{.Version}}"
+TAG="{{.Tag}}"
+API_URL="${GITHUB_API_URL:-https://api.github.com}"
+REPO="{{.Owner}}/{{.Repo}}"
+BIN_NAME="{{.Name}}"
+INSTALL_PATH="${INSTALL_PATH:-{{.InstallPath}}}"
+
+case "$OS" in
+  darwin|linux)
+    BINARY_NAME="${BIN_NAME}-${OS}-${ARCH}"
+    ;;
+  *)
+    echo "Unsupported OS: $OS"
+    exit 1
+    ;;
+esac
+
+` + verifyScript + `
+api() {
+  curl -fsSL -H "Authorization: Bearer ${TOKEN}" -H "X-GitHub-Api-Version: 2022-11-28" "$@"
+}
+
+# asset_id prints the id of the named asset from a release JSON document
+asset_id() {
+  if command -v jq >/dev/null 2>&1; then
+    jq -r --arg name "$1" '.assets[] | select(.name == $name) | .id'
+  else
+    grep -oE '"url": *"[^"]*/releases/assets/[0-9]+"|"name": *"[^"]*"' |
+      awk -v want="\"$1\"" '
+        /releases\/assets/ { n = split($0, parts, "/"); id = parts[n]; gsub(/"/, "", id); next }
+        { sub(/^"name": */, ""); if ($0 == want && id != "") { print id; exit } }'
+  fi
+}
+
+echo "Installing ${BIN_NAME} ${VERSION} from ${REPO}..."
+
+RELEASE_JSON="$(api -H "Accept: application/vnd.github+json" "${API_URL}/repos/${REPO}/releases/tags/${TAG}")"
+ASSET_ID="$(echo "$RELEASE_JSON" | asset_id "${BINARY_NAME}{{.EncryptedExt}}")"
+if [[ -z "$ASSET_ID" ]]; then
+  echo "Asset ${BINARY_NAME}{{.EncryptedExt}} not found in release ${TAG}"
+  exit 1
+fi
+
+# Download via the asset API, which redirects to a signed URL
+api -H "Accept: application/octet-stream" "${API_URL}/repos/${REPO}/releases/assets/${ASSET_ID}" -o "/tmp/${BIN_NAME}{{.EncryptedExt}}"
+{{- if not .Encrypted}}
+verify "/tmp/${BIN_NAME}" || exit 1
+{{- end}}
+` + decryptScript + `
+chmod +x "/tmp/${BIN_NAME}"
+
+# Install (with sudo if needed)
+if [[ -w "$INSTALL_PATH" ]]; then
+    mv "/tmp/${BIN_NAME}" "${INSTALL_PATH}/${BIN_NAME}"
+else
+    echo "Installing to ${INSTALL_PATH} (requires sudo)"
+    sudo mv "/tmp/${BIN_NAME}" "${INSTALL_PATH}/${BIN_NAME}"
+fi
+
+echo "✓ Installed ${BIN_NAME} to ${INSTALL_PATH}/${BIN_NAME}"
+echo ""
+{{- if .Message}}
+{{- range .Message}}
+{{.}}
+{{- end}}
+{{- else}}
+echo "Run '${BIN_NAME} --help' to get started!"
+{{- end}}`
+
+	t, err := packager.ParseTemplate(cfg, "installer/private-installer", tmpl)
+	if err != nil {
+		return err
+	}
+
+	checksums, err := Checksums(cfg, "linux", "darwin")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data := struct {
+		*config.Config
+		Owner        string
+		Repo         string
+		Checksums    []Checksum
+		InstallPath  string
+		Encrypted    bool
+		EncryptedExt string
+		Message      []string
+	}{
+		Config:       cfg,
+		Message:      packager.ShellEcho(cfg.PostInstall.MessageFor("installer")),
+		Owner:        cfg.GitHub.Owner,
+		Repo:         cfg.GitHub.Repo,
+		Checksums:    checksums,
+		InstallPath:  cfg.Installer.InstallPath,
+		Encrypted:    cfg.Encryption.Enabled,
+		EncryptedExt: encryptedExt(cfg),
+	}
+
+	if err := t.Execute(f, data); err != nil {
+		return err
+	}
+
+	return os.Chmod(path, 0755)
+}
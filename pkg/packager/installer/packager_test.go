@@ -19,6 +19,8 @@ package installer
 import (
 	"context"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
@@ -54,6 +56,10 @@ func TestInstallerPackager(t *testing.T) {
 }
 
 func TestInstallerPack(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(t.TempDir())
+
 	p := New()
 	cfg := &config.Config{
 		Name:        "test",
@@ -84,3 +90,277 @@ func TestInstallerPack(t *testing.T) {
 	// Clean up
 	os.Remove(output)
 }
+
+func TestInstallerPack_PostInstallMessage(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	cfg := &config.Config{
+		Name:    "test",
+		Version: "1.0.0",
+		Installer: config.InstallerConfig{
+			BaseURL: "https://example.com/releases",
+		},
+		PostInstall: config.PostInstallConfig{
+			Message: "Run `test init` to get started.",
+			Formats: map[string]string{"brew": "-"},
+		},
+	}
+
+	if _, err := New().Pack(context.Background(), cfg); err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+
+	for path, expected := range map[string]string{
+		"dist/install.sh":  "echo 'Run `test init` to get started.'",
+		"dist/install.ps1": "Write-Host 'Run `test init` to get started.'",
+	} {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if !strings.Contains(string(content), expected) || strings.Contains(string(content), "--help' to get started") {
+			t.Errorf("%s should end with the post-install message:\n%s", path, content)
+		}
+	}
+}
+
+func TestInstallerPack_Encrypted(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(t.TempDir())
+
+	p := New()
+
+	for method, expected := range map[string]string{"age": "age --decrypt", "gpg": "gpg --batch --yes --decrypt"} {
+		cfg := &config.Config{
+			Name:    "test",
+			Version: "1.0.0",
+			Installer: config.InstallerConfig{
+				BaseURL:     "https://example.com/releases",
+				InstallPath: "/usr/local/bin",
+			},
+			Encryption: config.EncryptionConfig{
+				Enabled:    true,
+				Method:     method,
+				Recipients: []string{"someone"},
+			},
+		}
+
+		output, err := p.Pack(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("Pack failed: %v", err)
+		}
+
+		content, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatalf("Failed to read installer: %v", err)
+		}
+
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("%s installer missing decrypt helper %q", method, expected)
+		}
+		if !strings.Contains(string(content), `DOWNLOAD_URL="${BASE_URL}/${BINARY_NAME}.`+method+`"`) {
+			t.Errorf("%s installer should download the encrypted asset", method)
+		}
+
+		os.Remove(output)
+	}
+}
+
+func TestInstallerPack_Mirrors(t *testing.T) {
+	cfg := &config.Config{
+		Name:    "test",
+		Version: "1.0.0",
+		Installer: config.InstallerConfig{
+			BaseURL:     "https://example.com/releases/",
+			Mirrors:     []string{"https://mirror.example.com/test", "https://example.com/releases"},
+			SourceForge: config.SourceForgeConfig{Project: "testproj"},
+		},
+	}
+
+	want := []string{
+		"https://example.com/releases",
+		"https://mirror.example.com/test",
+		"https://downloads.sourceforge.net/project/testproj/1.0.0",
+	}
+	if got := MirrorURLs(cfg); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Unexpected mirrors %v", got)
+	}
+
+	testDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	output, err := New().Pack(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), `MIRRORS="${MIRRORS:-`+strings.Join(want, " ")+`}"`) {
+		t.Error("Installer should embed the mirror list")
+	}
+
+	list, err := os.ReadFile(filepath.Join("dist", MirrorListName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(list) != strings.Join(want, "\n")+"\n" {
+		t.Errorf("Unexpected mirror list:\n%s", list)
+	}
+}
+
+func TestInstallerPack_PinnedChecksums(t *testing.T) {
+	testDir := t.TempDir()
+	binary := filepath.Join(testDir, "binary")
+	if err := os.WriteFile(binary, []byte("fake binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// sha256 of "fake binary"
+	sum := "17a815baf7efd5341b39e803d557cea4b127e125af8a5f92f0edd6322a0c38e5"
+
+	cfg := &config.Config{
+		Name:    "test",
+		Version: "1.0.0",
+		GitHub: config.GitHubConfig{
+			Owner:   "testowner",
+			Repo:    "test",
+			Release: config.ReleaseConfig{Enabled: true},
+		},
+		Installer: config.InstallerConfig{
+			BaseURL: "https://cdn.example.com/test",
+			Mirrors: []string{"https://test.s3.amazonaws.com/1.0.0"},
+		},
+		Binaries: map[string]string{
+			"linux-amd64":   binary,
+			"darwin-arm64":  binary,
+			"windows-amd64": binary,
+		},
+	}
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	output, err := New().Pack(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	script, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		sum + "  test-linux-amd64\n",
+		sum + "  test-darwin-arm64\n",
+		`verify "/tmp/${BIN_NAME}" || continue`,
+		"https://cdn.example.com/test https://github.com/testowner/test/releases/download/v1.0.0 https://test.s3.amazonaws.com/1.0.0",
+	} {
+		if !strings.Contains(string(script), want) {
+			t.Errorf("install.sh missing %q", want)
+		}
+	}
+	if strings.Contains(string(script), "test-windows-amd64") {
+		t.Error("install.sh should only pin Unix assets")
+	}
+
+	ps1, err := os.ReadFile(filepath.Join("dist", "install.ps1"))
+	if err != nil {
+		t.Fatalf("install.ps1 not created: %v", err)
+	}
+	for _, want := range []string{
+		`"test-windows-amd64.exe" = "` + sum + `"`,
+		`@("https://cdn.example.com/test", "https://github.com/testowner/test/releases/download/v1.0.0", "https://test.s3.amazonaws.com/1.0.0")`,
+		"Get-FileHash -Algorithm SHA256",
+	} {
+		if !strings.Contains(string(ps1), want) {
+			t.Errorf("install.ps1 missing %q", want)
+		}
+	}
+}
+
+func TestInstallerPack_Private(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(t.TempDir())
+
+	os.WriteFile("test-linux-amd64", []byte("binary"), 0755)
+
+	p := New()
+	cfg := &config.Config{
+		Name:     "test",
+		Version:  "1.0.0",
+		Binaries: map[string]string{"linux-amd64": "test-linux-amd64"},
+		GitHub: config.GitHubConfig{
+			Owner: "testowner",
+			Repo:  "testrepo",
+		},
+		Installer: config.InstallerConfig{
+			InstallPath: "/usr/local/bin",
+			Private:     true,
+		},
+	}
+
+	if err := p.Validate(cfg); err != nil {
+		t.Errorf("Private installer should not require base_url: %v", err)
+	}
+
+	output, err := p.Pack(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	defer os.Remove(output)
+
+	privatePath := filepath.Join(filepath.Dir(output), "install-private.sh")
+	defer os.Remove(privatePath)
+
+	content, err := os.ReadFile(privatePath)
+	if err != nil {
+		t.Fatalf("Private installer not created: %v", err)
+	}
+
+	for _, expected := range []string{
+		`REPO="testowner/testrepo"`,
+		"Accept: application/octet-stream",
+		`TAG="v1.0.0"`,
+		"/releases/tags/${TAG}",
+		"Authorization: Bearer ${TOKEN}",
+		"  test-linux-amd64\n",
+		`verify "/tmp/${BIN_NAME}" || exit 1`,
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Private installer missing %q", expected)
+		}
+	}
+
+	// Encrypted assets are fetched, decrypted and then verified
+	cfg.Encryption = config.EncryptionConfig{Enabled: true, Recipients: []string{"someone"}}
+	if _, err := p.Pack(context.Background(), cfg); err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	content, _ = os.ReadFile(privatePath)
+	for _, expected := range []string{
+		`asset_id "${BINARY_NAME}.age"`,
+		`-o "/tmp/${BIN_NAME}.age"`,
+		"age --decrypt",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Encrypted private installer missing %q", expected)
+		}
+	}
+	if _, decrypted, _ := strings.Cut(string(content), "age --decrypt"); !strings.Contains(decrypted, `verify "/tmp/${BIN_NAME}" || exit 1`) {
+		t.Error("Expected the decrypted binary to be verified")
+	}
+	cfg.Encryption = config.EncryptionConfig{}
+
+	cfg.GitHub.Owner = ""
+	if err := p.Validate(cfg); err == nil {
+		t.Error("Expected error for private installer without GitHub repo")
+	}
+}
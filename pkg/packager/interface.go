@@ -2,8 +2,14 @@ package packager
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/hooks"
 )
 
 type Packager interface {
@@ -14,6 +20,9 @@ type Packager interface {
 
 type Registry struct {
 	packagers map[string]Packager
+	parallel  int
+	progress  func(Result)
+	strict    bool
 }
 
 func NewRegistry() *Registry {
@@ -31,11 +40,13 @@ func (r *Registry) Get(name string) (Packager, bool) {
 	return p, ok
 }
 
+// List returns the names of the registered packagers in sorted order
 func (r *Registry) List() []string {
 	var names []string
 	for name := range r.packagers {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 	return names
 }
 
@@ -43,21 +54,140 @@ func (r *Registry) Count() int {
 	return len(r.packagers)
 }
 
-func (r *Registry) PackAll(ctx context.Context, cfg *config.Config) (map[string]string, error) {
-	results := make(map[string]string)
+// SetParallelism sets how many packagers PackAll runs at once; the
+// default is one at a time
+func (r *Registry) SetParallelism(n int) {
+	r.parallel = n
+}
+
+// SetProgress sets a function PackAll calls with each format's result as
+// soon as it is done. Calls are never concurrent.
+func (r *Registry) SetProgress(fn func(Result)) {
+	r.progress = fn
+}
+
+// SetStrict makes PackAll fail formats whose tool is missing instead of
+// skipping them, and stop starting formats after the first failure
+func (r *Registry) SetStrict(strict bool) {
+	r.strict = strict
+}
+
+// PackAll packages every enabled format whose Validate accepts cfg and
+// returns the results ordered by format, including the formats it skipped
+// and why. A format whose tool is missing is skipped rather than failed,
+// unless the registry is strict. A failed format does not stop the others;
+// the failures are returned together, each prefixed with its format.
+// Cancelling ctx stops formats not yet started.
+func (r *Registry) PackAll(ctx context.Context, cfg *config.Config) (Results, error) {
+	var (
+		mu      sync.Mutex
+		errs    []error
+		results Results
+	)
 
-	for name, packager := range r.packagers {
-		if err := packager.Validate(cfg); err != nil {
-			continue // Skip packagers that can't handle this config
+	workers := r.parallel
+	if workers < 1 {
+		workers = 1
+	}
+	jobs := make(chan Packager)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for packager := range jobs {
+				mu.Lock()
+				stop := r.strict && len(errs) > 0
+				mu.Unlock()
+				if stop {
+					continue
+				}
+				result, err := packOne(ctx, cfg, packager, r.strict)
+
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", result.Format, err))
+				}
+				results = append(results, result)
+				if r.progress != nil {
+					r.progress(result)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, name := range r.List() {
+		if ctx.Err() != nil {
+			break
 		}
+		jobs <- r.packagers[name]
+	}
+	close(jobs)
+	wg.Wait()
 
-		output, err := packager.Pack(ctx, cfg)
-		if err != nil {
-			return nil, err
+	results.sort()
+	if err := ctx.Err(); err != nil && len(errs) == 0 {
+		return results, err
+	}
+	return results, errors.Join(errs...)
+}
+
+// packOne packages one format. Formats that are disabled, that Validate
+// rejects or whose tool is missing get a skipped result and no error; when
+// strict, a missing tool is a failure.
+func packOne(ctx context.Context, cfg *config.Config, p Packager, strict bool) (Result, error) {
+	result := Result{Format: p.Name(), Status: StatusSkipped}
+	if !cfg.Packages.FormatEnabled(p.Name()) {
+		result.Reason = SkipDisabled
+		return result, nil
+	}
+	if err := p.Validate(cfg); err != nil {
+		result.Reason, result.Error = skipReason(err), err.Error()
+		if strict && result.Reason == SkipToolMissing {
+			result.Status, result.Reason = StatusFailed, ""
+			return result, err
 		}
+		return result, nil
+	}
 
-		results[name] = output
+	start := time.Now()
+	artifacts, err := PackFormat(ctx, cfg, p)
+	result.Duration = time.Since(start)
+	switch {
+	case err == nil:
+		result.Status, result.Artifacts = StatusSuccess, artifacts
+		if len(artifacts) > 0 {
+			result.Path = artifacts[0].Path
+		}
+	case skipReason(err) == SkipToolMissing && !strict:
+		result.Reason, result.Error = SkipToolMissing, err.Error()
+	default:
+		result.Status, result.Error = StatusFailed, err.Error()
+		return result, err
 	}
+	return result, nil
+}
 
-	return results, nil
+// PackFormat packages one format the way PackAll does, between its hooks,
+// and returns every artifact it built with format, platform and checksum
+// filled in
+func PackFormat(ctx context.Context, cfg *config.Config, p Packager) ([]Artifact, error) {
+	artifacts, err := packWithHooks(ctx, cfg, p)
+	if err != nil {
+		return artifacts, err
+	}
+	return artifacts, describe(p.Name(), artifacts)
+}
+
+// packWithHooks packages one format between its before and after hooks
+func packWithHooks(ctx context.Context, cfg *config.Config, p Packager) ([]Artifact, error) {
+	if err := hooks.Before(ctx, cfg, p.Name()); err != nil {
+		return nil, err
+	}
+	artifacts, err := Adapt(p).PackArtifacts(ctx, cfg)
+	if err != nil || len(artifacts) == 0 {
+		return artifacts, err
+	}
+	return artifacts, hooks.After(ctx, cfg, p.Name(), artifacts[0].Path)
 }
@@ -18,10 +18,19 @@ package packager
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	bagboyerrors "github.com/scttfrdmn/bagboy/pkg/errors"
 )
 
 // MockPackager for testing
@@ -90,8 +99,8 @@ func TestPackAll(t *testing.T) {
 	if len(results) != 1 {
 		t.Errorf("Expected 1 result, got %d", len(results))
 	}
-	if results["good"] != "mock-output" {
-		t.Errorf("Expected 'mock-output', got %s", results["good"])
+	if path, _ := results.Get("good"); path != "mock-output" {
+		t.Errorf("Expected 'mock-output', got %s", path)
 	}
 
 	// Test with packager that fails validation (should be skipped)
@@ -102,7 +111,378 @@ func TestPackAll(t *testing.T) {
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
 	}
-	if len(results) != 0 {
-		t.Errorf("Expected 0 results (validation failed), got %d", len(results))
+	if len(results) != 1 || results[0].Status != StatusSkipped || results[0].Reason != SkipValidationFailed {
+		t.Errorf("Expected a skipped result (validation failed), got %+v", results)
+	}
+	if results.Succeeded() != 0 || results.Skipped() != 1 {
+		t.Errorf("Succeeded() = %d, Skipped() = %d", results.Succeeded(), results.Skipped())
+	}
+}
+
+// slowPackager records how many packagers are packing at once
+type slowPackager struct {
+	name    string
+	running *int32
+	peak    *int32
+}
+
+func (s *slowPackager) Name() string                      { return s.name }
+func (s *slowPackager) Validate(cfg *config.Config) error { return nil }
+
+func (s *slowPackager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
+	n := atomic.AddInt32(s.running, 1)
+	defer atomic.AddInt32(s.running, -1)
+	for {
+		peak := atomic.LoadInt32(s.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(s.peak, peak, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return s.name + "-output", nil
+}
+
+func TestPackAll_Parallelism(t *testing.T) {
+	for _, parallel := range []int{0, 1, 3} {
+		var running, peak int32
+		registry := NewRegistry()
+		registry.SetParallelism(parallel)
+		for i := 0; i < 8; i++ {
+			registry.Register(&slowPackager{name: fmt.Sprintf("p%d", i), running: &running, peak: &peak})
+		}
+
+		results, err := registry.PackAll(context.Background(), &config.Config{})
+		if err != nil {
+			t.Fatalf("PackAll failed: %v", err)
+		}
+		if len(results) != 8 {
+			t.Errorf("Expected 8 results, got %d", len(results))
+		}
+
+		want := int32(max(parallel, 1))
+		if peak > want {
+			t.Errorf("SetParallelism(%d): %d packagers ran at once", parallel, peak)
+		}
+	}
+}
+
+func TestPackAll_Ordered(t *testing.T) {
+	registry := NewRegistry()
+	for _, name := range []string{"rpm", "brew", "deb", "appimage", "scoop"} {
+		registry.Register(&MockPackager{name: name})
+	}
+	registry.SetParallelism(3)
+
+	want := []string{"appimage", "brew", "deb", "rpm", "scoop"}
+	for i := 0; i < 5; i++ {
+		results, err := registry.PackAll(context.Background(), &config.Config{})
+		if err != nil {
+			t.Fatalf("PackAll failed: %v", err)
+		}
+		var got []string
+		for _, result := range results {
+			got = append(got, result.Format)
+			if result.Status != StatusSuccess {
+				t.Errorf("%s: status %s, want success", result.Format, result.Status)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("Results in order %v, want %v", got, want)
+		}
+	}
+}
+
+// failingPackager validates but fails to pack
+type failingPackager struct{ MockPackager }
+
+func (f *failingPackager) Validate(cfg *config.Config) error { return nil }
+
+func TestPackAll_Failure(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(&MockPackager{name: "brew"})
+	registry.Register(&failingPackager{MockPackager{name: "deb", shouldErr: true}})
+	registry.Register(&failingPackager{MockPackager{name: "rpm", shouldErr: true}})
+
+	// Every format is attempted and every failure reported
+	results, err := registry.PackAll(context.Background(), &config.Config{})
+	if err == nil {
+		t.Fatal("Expected PackAll to fail")
+	}
+	if err.Error() != "deb: mock error\nrpm: mock error" {
+		t.Errorf("PackAll error = %q", err)
+	}
+	if len(results) != 3 || results[1].Format != "deb" || results[1].Status != StatusFailed || results[1].Error != "mock error" || results[2].Status != StatusFailed {
+		t.Errorf("Unexpected results %+v", results)
+	}
+	if _, ok := results.Get("deb"); ok {
+		t.Error("Get should not return a failed format")
+	}
+	if paths := results.Paths(); len(paths) != 1 || paths[0] != "mock-output" {
+		t.Errorf("Paths() = %v", paths)
+	}
+}
+
+func TestPackAll_Progress(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetParallelism(3)
+	for _, name := range []string{"brew", "deb", "rpm", "scoop"} {
+		registry.Register(&MockPackager{name: name})
+	}
+	registry.Register(&failingPackager{MockPackager{name: "msi", shouldErr: true}})
+
+	seen := map[string]Status{}
+	registry.SetProgress(func(result Result) {
+		seen[result.Format] = result.Status // unsynchronized, so -race catches concurrent calls
+	})
+	registry.PackAll(context.Background(), &config.Config{})
+
+	if len(seen) != 5 || seen["msi"] != StatusFailed || seen["deb"] != StatusSuccess {
+		t.Errorf("Progress reported %v", seen)
+	}
+}
+
+func TestPackAll_Cancelled(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(&MockPackager{name: "brew"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := registry.PackAll(ctx, &config.Config{})
+	if err != context.Canceled || len(results) != 0 {
+		t.Errorf("PackAll() = %v, %v; want no results and context.Canceled", results, err)
+	}
+}
+
+func TestPackAll_Strict(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetStrict(true)
+	registry.Register(&skipPackager{name: "appimage", validateErr: bagboyerrors.NotConfiguredError("appimage.categories is required")})
+	registry.Register(&skipPackager{name: "brew", packErr: bagboyerrors.ToolNotFoundError("rpmbuild not found")})
+	for _, name := range []string{"deb", "rpm", "scoop"} {
+		registry.Register(&MockPackager{name: name})
+	}
+
+	// A missing tool fails the format and no further formats are started
+	results, err := registry.PackAll(context.Background(), &config.Config{})
+	if err == nil || !strings.HasPrefix(err.Error(), "brew: ") {
+		t.Fatalf("PackAll error = %v, want brew's missing tool", err)
+	}
+	if len(results) != 2 || results[0].Status != StatusSkipped || results[1].Status != StatusFailed {
+		t.Errorf("Unexpected results %+v", results)
+	}
+}
+
+func TestPackAll_Hooks(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(dir)
+	os.WriteFile("mock-output", []byte("mock"), 0644)
+
+	cfg := &config.Config{Name: "app", Version: "1.0.0"}
+	cfg.Hooks.Formats = map[string]config.FormatHooks{
+		"deb": {
+			Before: []string{`echo "$FORMAT $NAME $VERSION" > before`},
+			After:  []string{`echo changed > "$OUTPUT_PATH"`},
+		},
+		"rpm": {Before: []string{"exit 3"}},
+	}
+
+	registry := NewRegistry()
+	registry.Register(&MockPackager{name: "deb"})
+	registry.Register(&MockPackager{name: "rpm"})
+	results, err := registry.PackAll(context.Background(), cfg)
+	if err == nil || !strings.Contains(err.Error(), `rpm: rpm before hook "exit 3" failed`) {
+		t.Errorf("PackAll() error = %v, want the rpm hook failure", err)
+	}
+
+	if got, _ := os.ReadFile("before"); string(got) != "deb app 1.0.0\n" {
+		t.Errorf("before hook saw %q", got)
+	}
+	// After hooks run before the checksum is taken
+	deb := results[0].Artifacts[0]
+	if deb.Checksum != fmt.Sprintf("%x", sha256.Sum256([]byte("changed\n"))) {
+		t.Errorf("deb checksum = %q, want that of the file the after hook wrote", deb.Checksum)
+	}
+	if results[1].Status != StatusFailed {
+		t.Errorf("rpm status = %s, want failed", results[1].Status)
+	}
+}
+
+func TestResults_WriteJSON(t *testing.T) {
+	results := Results{
+		{Format: "brew", Path: "dist/brew/app.rb", Duration: 1500 * time.Millisecond, Status: StatusSuccess},
+		{Format: "deb", Status: StatusFailed, Error: "dpkg-deb not found"},
+	}
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := results.WriteJSON(path); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	var report []map[string]interface{}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, data)
+	}
+	if len(report) != 2 || report[0]["format"] != "brew" || report[0]["duration_seconds"] != 1.5 || report[1]["error"] != "dpkg-deb not found" {
+		t.Errorf("Unexpected report %s", data)
+	}
+}
+
+// skipPackager fails Validate or Pack with err
+type skipPackager struct {
+	name        string
+	validateErr error
+	packErr     error
+}
+
+func (s *skipPackager) Name() string                      { return s.name }
+func (s *skipPackager) Validate(cfg *config.Config) error { return s.validateErr }
+func (s *skipPackager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
+	return "", s.packErr
+}
+
+func TestPackAll_SkipReasons(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(&MockPackager{name: "brew"})
+	registry.Register(&MockPackager{name: "scoop"})
+	registry.Register(&skipPackager{name: "appimage", validateErr: bagboyerrors.NotConfiguredError("appimage.categories is required")})
+	registry.Register(&skipPackager{name: "dmg", validateErr: bagboyerrors.NoPlatformBinaryError("no macOS binary found for DMG creation")})
+	registry.Register(&skipPackager{name: "rpm", packErr: bagboyerrors.ToolNotFoundError("rpmbuild not found - install rpm-build package")})
+	registry.Register(&skipPackager{name: "winget", validateErr: fmt.Errorf("unsupported winget.scope %q", "global")})
+	registry.Register(&skipPackager{name: "snap", packErr: fmt.Errorf("snapcraft failed: %w", &exec.Error{Name: "snapcraft", Err: exec.ErrNotFound})})
+
+	cfg := &config.Config{Packages: config.PackagesConfig{Disabled: []string{"scoop"}}}
+	results, err := registry.PackAll(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("PackAll failed: %v", err)
+	}
+
+	want := map[string]SkipReason{
+		"appimage": SkipNotConfigured,
+		"dmg":      SkipUnsupportedPlatform,
+		"rpm":      SkipToolMissing,
+		"scoop":    SkipDisabled,
+		"snap":     SkipToolMissing,
+		"winget":   SkipValidationFailed,
+	}
+	for _, result := range results {
+		if result.Format == "brew" {
+			if result.Status != StatusSuccess {
+				t.Errorf("brew: status %s, want success", result.Status)
+			}
+			continue
+		}
+		if result.Status != StatusSkipped || result.Reason != want[result.Format] {
+			t.Errorf("%s: %s (%s), want skipped (%s)", result.Format, result.Status, result.Reason, want[result.Format])
+		}
+	}
+	if len(results) != 7 {
+		t.Errorf("Expected 7 results, got %d", len(results))
+	}
+
+	// packages.enabled limits PackAll to the listed formats
+	cfg.Packages = config.PackagesConfig{Enabled: []string{"brew"}}
+	results, _ = registry.PackAll(context.Background(), cfg)
+	if results.Succeeded() != 1 || results.Skipped() != 6 {
+		t.Errorf("Succeeded() = %d, Skipped() = %d with only brew enabled", results.Succeeded(), results.Skipped())
+	}
+}
+
+// archPackager builds one artifact per platform through ArchOutputs
+type archPackager struct{ MockPackager }
+
+func (a *archPackager) ArchOutputs() map[string]string {
+	return map[string]string{"linux-amd64": "mock-output", "linux-arm64": "mock-arm64", "386": "mock-386"}
+}
+
+// localePackager implements ArtifactPacker, building one artifact per
+// locale
+type localePackager struct{ MockPackager }
+
+func (l *localePackager) PackArtifacts(ctx context.Context, cfg *config.Config) ([]Artifact, error) {
+	return []Artifact{
+		{Path: "mock-output", Arch: "amd64", Variant: "en-US"},
+		{Path: "mock-de", Arch: "amd64", Variant: "de-DE", Kind: KindPackage},
+	}, nil
+}
+
+func TestPackAll_Artifacts(t *testing.T) {
+	dir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(dir)
+	if err := os.WriteFile("mock-output", []byte("mock"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewRegistry()
+	registry.Register(&MockPackager{name: "brew"})
+	registry.Register(&archPackager{MockPackager{name: "deb"}})
+	registry.Register(&localePackager{MockPackager{name: "msi"}})
+
+	results, err := registry.PackAll(context.Background(), &config.Config{})
+	if err != nil {
+		t.Fatalf("PackAll failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected one result per format, got %+v", results)
+	}
+
+	// Adapted from Pack: the format, OS, kind and checksum are filled in
+	brew := results[0].Artifacts
+	if len(brew) != 1 || brew[0].Format != "brew" || brew[0].OS != "darwin" || brew[0].Kind != KindManifest {
+		t.Errorf("brew artifacts = %+v", brew)
+	}
+	if brew[0].Checksum != fmt.Sprintf("%x", sha256.Sum256([]byte("mock"))) {
+		t.Errorf("brew checksum = %q", brew[0].Checksum)
+	}
+
+	// Adapted from ArchOutputs, primary first
+	var platforms []string
+	for _, artifact := range results[1].Artifacts {
+		platforms = append(platforms, artifact.Platform()+"="+artifact.Path)
+	}
+	if got := strings.Join(platforms, ","); got != "linux-amd64=mock-output,linux-386=mock-386,linux-arm64=mock-arm64" {
+		t.Errorf("deb artifacts = %s, want the primary first", got)
+	}
+
+	// From PackArtifacts
+	msi := results[2].Artifacts
+	if label := msi[1].Label(); label != "amd64-de-DE" {
+		t.Errorf("Label() = %q, want amd64-de-DE", label)
+	}
+	if msi[0].Kind != KindInstaller || msi[1].Kind != KindPackage || msi[1].Platform() != "windows-amd64" {
+		t.Errorf("msi artifacts = %+v", msi)
+	}
+
+	want := "mock-output,mock-output,mock-386,mock-arm64,mock-output,mock-de"
+	if got := strings.Join(results.Paths(), ","); got != want {
+		t.Errorf("Paths() = %s, want %s", got, want)
+	}
+	if path, _ := results.Get("deb"); path != "mock-output" {
+		t.Errorf("Get(deb) = %s, want the primary artifact", path)
+	}
+}
+
+func TestPackFormat(t *testing.T) {
+	artifacts, err := PackFormat(context.Background(), &config.Config{}, &archPackager{MockPackager{name: "rpm"}})
+	if err != nil {
+		t.Fatalf("PackFormat failed: %v", err)
+	}
+	if len(artifacts) != 3 {
+		t.Fatalf("Expected every architecture, got %+v", artifacts)
+	}
+	for _, artifact := range artifacts {
+		if artifact.Format != "rpm" || artifact.OS != "linux" {
+			t.Errorf("Expected the artifact described like PackAll does, got %+v", artifact)
+		}
+	}
+
+	if _, err := PackFormat(context.Background(), &config.Config{}, &MockPackager{name: "rpm", shouldErr: true}); err == nil {
+		t.Error("Expected the packager error")
 	}
 }
@@ -0,0 +1,398 @@
+package maven
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+)
+
+type Packager struct{}
+
+func New() *Packager {
+	return &Packager{}
+}
+
+func (p *Packager) Name() string {
+	return "maven"
+}
+
+func (p *Packager) Validate(cfg *config.Config) error {
+	if cfg.Packages.Maven.GroupID == "" {
+		return errors.NotConfiguredError("maven.group_id is required for Maven artifacts")
+	}
+	if len(cfg.Binaries) == 0 {
+		return fmt.Errorf("at least one binary is required for Maven artifacts")
+	}
+	return nil
+}
+
+// ArtifactID returns the configured artifact ID, defaulting to the name
+func ArtifactID(cfg *config.Config) string {
+	if id := cfg.Packages.Maven.ArtifactID; id != "" {
+		return id
+	}
+	return cfg.Name
+}
+
+// Classifier maps a platform such as darwin-arm64 to the classifier
+// os-maven-plugin detects, e.g. osx-aarch_64, as protoc's artifacts use
+func Classifier(platform string) string {
+	goos, goarch, _ := strings.Cut(platform, "-")
+	if goos == "darwin" {
+		goos = "osx"
+	}
+	switch goarch {
+	case "amd64":
+		goarch = "x86_64"
+	case "arm64":
+		goarch = "aarch_64"
+	case "386":
+		goarch = "x86_32"
+	case "ppc64le":
+		goarch = "ppcle_64"
+	case "s390x":
+		goarch = "s390_64"
+	}
+	return goos + "-" + goarch
+}
+
+func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
+	if err := p.Validate(cfg); err != nil {
+		return "", err
+	}
+
+	artifactID := ArtifactID(cfg)
+	repoDir := filepath.Join("dist", "maven", "repository")
+	if err := os.RemoveAll(repoDir); err != nil {
+		return "", err
+	}
+	versionDir := filepath.Join(repoDir, filepath.FromSlash(strings.ReplaceAll(cfg.Packages.Maven.GroupID, ".", "/")), artifactID, cfg.Version)
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		return "", err
+	}
+	base := filepath.Join(versionDir, fmt.Sprintf("%s-%s", artifactID, cfg.Version))
+
+	// The POM has packaging pom; each binary is attached with type exe and
+	// its platform classifier
+	files := []string{base + ".pom"}
+	if err := p.createPOM(base+".pom", cfg); err != nil {
+		return "", err
+	}
+	for _, platform := range sortedPlatforms(cfg.Binaries) {
+		dest := fmt.Sprintf("%s-%s.exe", base, Classifier(platform))
+		if err := paths.CopyExecutable(cfg.Binaries[platform], dest, packager.CopyOptions(cfg)); err != nil {
+			return "", fmt.Errorf("failed to copy %s binary: %w", platform, err)
+		}
+		files = append(files, dest)
+	}
+
+	// Maven Central requires checksums and, for releases, signatures
+	keyID := signingKey(cfg)
+	for _, file := range files {
+		if err := writeChecksums(file); err != nil {
+			return "", err
+		}
+		if keyID != "" {
+			if err := sign(ctx, keyID, file); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if cfg.Packages.Maven.GradlePlugin != "" {
+		if err := p.createGradlePlugin(filepath.Join("dist", "maven", "gradle-plugin"), cfg); err != nil {
+			return "", fmt.Errorf("failed to create Gradle plugin: %w", err)
+		}
+	}
+
+	// The bundle is what the Central Portal and Nexus upload APIs accept
+	bundlePath := filepath.Join("dist", fmt.Sprintf("%s-%s-bundle.zip", artifactID, cfg.Version))
+	if err := paths.ZipDir(repoDir, bundlePath); err != nil {
+		return "", fmt.Errorf("failed to create bundle: %w", err)
+	}
+	return bundlePath, nil
+}
+
+func (p *Packager) createPOM(path string, cfg *config.Config) error {
+	tmpl := `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0 https://maven.apache.org/xsd/maven-4.0.0.xsd">
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>{{.GroupID}}</groupId>
+  <artifactId>{{.ArtifactID}}</artifactId>
+  <version>{{.Version | xml}}</version>
+  <packaging>pom</packaging>
+  <name>{{.Name | xml}}</name>
+  <description>{{.Description | xml}}</description>
+{{- if .URL}}
+  <url>{{.URL | xml}}</url>
+{{- end}}
+{{- if .License}}
+  <licenses>
+    <license>
+      <name>{{.License | xml}}</name>
+    </license>
+  </licenses>
+{{- end}}
+{{- if .Developers}}
+  <developers>
+{{- range .Developers}}
+    <developer>
+      <name>{{.Name | xml}}</name>
+{{- if .Email}}
+      <email>{{.Email | xml}}</email>
+{{- end}}
+    </developer>
+{{- end}}
+  </developers>
+{{- end}}
+{{- if .SCM}}
+  <scm>
+    <url>{{.SCM}}</url>
+    <connection>scm:git:{{.SCM}}.git</connection>
+  </scm>
+{{- end}}
+</project>
+`
+
+	t, err := packager.ParseTemplate(cfg, "maven/pom", tmpl, template.FuncMap{"xml": xmlEscape})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data := struct {
+		*config.Config
+		GroupID    string
+		ArtifactID string
+		URL        string
+		SCM        string
+		Developers []config.AuthorConfig
+	}{
+		Config:     cfg,
+		GroupID:    cfg.Packages.Maven.GroupID,
+		ArtifactID: ArtifactID(cfg),
+		URL:        cfg.Homepage,
+		Developers: cfg.People(),
+	}
+	if cfg.GitHub.Owner != "" && cfg.GitHub.Repo != "" {
+		data.SCM = fmt.Sprintf("https://github.com/%s/%s", cfg.GitHub.Owner, cfg.GitHub.Repo)
+		if data.URL == "" {
+			data.URL = data.SCM
+		}
+	}
+
+	return t.Execute(f, data)
+}
+
+// createGradlePlugin writes a Gradle plugin project that resolves the
+// binary for the build machine from the Maven artifact and registers a
+// task running it
+func (p *Packager) createGradlePlugin(dir string, cfg *config.Config) error {
+	className := javaIdentifier(cfg.Name) + "Plugin"
+	javaPackage := strings.ReplaceAll(cfg.Packages.Maven.GroupID, "-", "_") + ".gradle"
+	sourceDir := filepath.Join(dir, "src", "main", "java", filepath.FromSlash(strings.ReplaceAll(javaPackage, ".", "/")))
+	if err := os.MkdirAll(sourceDir, 0755); err != nil {
+		return err
+	}
+
+	data := struct {
+		*config.Config
+		GroupID     string
+		ArtifactID  string
+		PluginID    string
+		ClassName   string
+		JavaPackage string
+		TaskName    string
+	}{
+		Config:      cfg,
+		GroupID:     cfg.Packages.Maven.GroupID,
+		ArtifactID:  ArtifactID(cfg),
+		PluginID:    cfg.Packages.Maven.GradlePlugin,
+		ClassName:   className,
+		JavaPackage: javaPackage,
+		TaskName:    lowerFirst(javaIdentifier(cfg.Name)),
+	}
+
+	files := map[string]string{
+		filepath.Join(dir, "settings.gradle.kts"):   gradleSettingsTemplate,
+		filepath.Join(dir, "build.gradle.kts"):      gradleBuildTemplate,
+		filepath.Join(sourceDir, className+".java"): gradlePluginTemplate,
+	}
+	for path, tmpl := range files {
+		t, err := packager.ParseTemplate(cfg, "maven/"+filepath.Base(path), tmpl)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const gradleSettingsTemplate = `rootProject.name = "{{.ArtifactID}}-gradle-plugin"
+`
+
+const gradleBuildTemplate = `plugins {
+    ` + "`java-gradle-plugin`" + `
+    ` + "`maven-publish`" + `
+}
+
+group = "{{.GroupID}}"
+version = "{{.Version}}"
+
+gradlePlugin {
+    plugins {
+        create("{{.TaskName}}") {
+            id = "{{.PluginID}}"
+            implementationClass = "{{.JavaPackage}}.{{.ClassName}}"
+        }
+    }
+}
+`
+
+const gradlePluginTemplate = `package {{.JavaPackage}};
+
+import java.io.File;
+import java.util.Locale;
+
+import org.gradle.api.Plugin;
+import org.gradle.api.Project;
+import org.gradle.api.artifacts.Configuration;
+import org.gradle.api.tasks.Exec;
+
+/** Resolves {{.Name}} for the build machine and registers the {{.TaskName}} task. */
+public class {{.ClassName}} implements Plugin<Project> {
+    static final String COORDINATES = "{{.GroupID}}:{{.ArtifactID}}:{{.Version}}";
+
+    @Override
+    public void apply(Project project) {
+        Configuration binary = project.getConfigurations().create("{{.TaskName}}Binary");
+        binary.setTransitive(false);
+        project.getDependencies().add(binary.getName(), COORDINATES + ":" + classifier() + "@exe");
+
+        project.getTasks().register("{{.TaskName}}", Exec.class, task -> {
+            task.setGroup("{{.Name}}");
+            task.setDescription("Runs {{.Name}} {{.Version}}");
+            task.doFirst(t -> {
+                File file = binary.getSingleFile();
+                file.setExecutable(true);
+                task.setExecutable(file.getAbsolutePath());
+            });
+        });
+    }
+
+    static String classifier() {
+        String os = System.getProperty("os.name").toLowerCase(Locale.ROOT);
+        String arch = System.getProperty("os.arch").toLowerCase(Locale.ROOT);
+        String osName = os.contains("win") ? "windows" : os.contains("mac") ? "osx" : "linux";
+        String archName = arch.equals("aarch64") || arch.equals("arm64") ? "aarch_64" : "x86_64";
+        return osName + "-" + archName;
+    }
+}
+`
+
+// signingKey returns the GPG key artifacts are signed with, if any
+func signingKey(cfg *config.Config) string {
+	if id := cfg.Signing.Linux.GPGKeyID; id != "" {
+		return id
+	}
+	return os.Getenv("GPG_KEY_ID")
+}
+
+func sign(ctx context.Context, keyID, path string) error {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		return errors.ToolNotFoundError("gpg not found - required to sign Maven artifacts")
+	}
+	cmd := exec.CommandContext(ctx, "gpg", "--batch", "--yes", "--armor", "--detach-sign",
+		"--local-user", keyID, "--output", path+".asc", path)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("gpg signing failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
+// writeChecksums writes the .md5 and .sha1 files Maven repositories expect
+func writeChecksums(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	md5Hash, sha1Hash := md5.New(), sha1.New()
+	if _, err := io.Copy(io.MultiWriter(md5Hash, sha1Hash), f); err != nil {
+		return err
+	}
+	md5Sum, sha1Sum := md5Hash.Sum(nil), sha1Hash.Sum(nil)
+	if err := os.WriteFile(path+".md5", []byte(hex.EncodeToString(md5Sum)), 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(path+".sha1", []byte(hex.EncodeToString(sha1Sum)), 0644)
+}
+
+func sortedPlatforms(binaries map[string]string) []string {
+	platforms := make([]string, 0, len(binaries))
+	for platform := range binaries {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// javaIdentifier turns a name such as my-tool into MyTool
+func javaIdentifier(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
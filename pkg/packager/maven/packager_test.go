@@ -0,0 +1,117 @@
+package maven
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestMavenPackager(t *testing.T) {
+	testDir := t.TempDir()
+	binary := filepath.Join(testDir, "binary")
+	if err := os.WriteFile(binary, []byte("fake binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Name:        "my-tool",
+		Version:     "1.0.0",
+		Description: "Tool & friends",
+		Author:      "Test Author <test@example.com>",
+		License:     "MIT",
+		GitHub:      config.GitHubConfig{Owner: "test", Repo: "my-tool"},
+		Binaries: map[string]string{
+			"linux-amd64":   binary,
+			"darwin-arm64":  binary,
+			"windows-amd64": binary,
+		},
+	}
+	cfg.Packages.Maven = config.MavenConfig{GroupID: "io.example", GradlePlugin: "io.example.my-tool"}
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+	t.Setenv("GPG_KEY_ID", "")
+
+	p := New()
+	if err := p.Validate(cfg); err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+
+	bundle, err := p.Pack(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	if bundle != filepath.Join("dist", "my-tool-1.0.0-bundle.zip") {
+		t.Errorf("Unexpected bundle path %s", bundle)
+	}
+
+	r, err := zip.OpenReader(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+
+	prefix := "io/example/my-tool/1.0.0/my-tool-1.0.0"
+	for _, want := range []string{
+		prefix + ".pom",
+		prefix + ".pom.sha1",
+		prefix + "-linux-x86_64.exe",
+		prefix + "-osx-aarch_64.exe.md5",
+		prefix + "-windows-x86_64.exe",
+	} {
+		if i := sort.SearchStrings(names, want); i == len(names) || names[i] != want {
+			t.Errorf("Bundle missing %s", want)
+		}
+	}
+
+	pom, err := os.ReadFile(filepath.Join("dist", "maven", "repository", filepath.FromSlash(prefix)+".pom"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<groupId>io.example</groupId>",
+		"<packaging>pom</packaging>",
+		"<description>Tool &amp; friends</description>",
+		"<url>https://github.com/test/my-tool</url>",
+		"<email>test@example.com</email>",
+	} {
+		if !strings.Contains(string(pom), want) {
+			t.Errorf("POM missing %q", want)
+		}
+	}
+
+	plugin, err := os.ReadFile(filepath.Join("dist", "maven", "gradle-plugin", "src", "main", "java", "io", "example", "gradle", "MyToolPlugin.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(plugin), `COORDINATES = "io.example:my-tool:1.0.0"`) || !strings.Contains(string(plugin), `register("myTool", Exec.class`) {
+		t.Errorf("Unexpected Gradle plugin:\n%s", plugin)
+	}
+}
+
+func TestClassifier(t *testing.T) {
+	tests := map[string]string{
+		"linux-amd64":   "linux-x86_64",
+		"linux-arm64":   "linux-aarch_64",
+		"darwin-amd64":  "osx-x86_64",
+		"windows-386":   "windows-x86_32",
+		"linux-ppc64le": "linux-ppcle_64",
+	}
+	for platform, want := range tests {
+		if got := Classifier(platform); got != want {
+			t.Errorf("Classifier(%s) = %s, expected %s", platform, got, want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package packager
+
+import "strings"
+
+// ShellEcho returns echo commands printing message, one per line
+func ShellEcho(message string) []string {
+	if message == "" {
+		return nil
+	}
+	var commands []string
+	for _, line := range strings.Split(message, "\n") {
+		commands = append(commands, "echo '"+strings.ReplaceAll(line, "'", `'\''`)+"'")
+	}
+	return commands
+}
+
+// WriteHost returns PowerShell Write-Host commands printing message, one
+// per line
+func WriteHost(message string) []string {
+	if message == "" {
+		return nil
+	}
+	var commands []string
+	for _, line := range strings.Split(message, "\n") {
+		commands = append(commands, "Write-Host '"+strings.ReplaceAll(line, "'", "''")+"'")
+	}
+	return commands
+}
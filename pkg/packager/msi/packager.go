@@ -2,15 +2,21 @@ package msi
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/xml"
 	"fmt"
+	"hash/fnv"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
-	"text/template"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+	"github.com/scttfrdmn/bagboy/pkg/requirements"
 )
 
 type Packager struct{}
@@ -30,7 +36,7 @@ func (p *Packager) Validate(cfg *config.Config) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("no Windows binary found for MSI creation")
+	return errors.NoPlatformBinaryError("no Windows binary found for MSI creation")
 }
 
 func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
@@ -51,13 +57,32 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 
 	// Copy binary
 	binaryDest := filepath.Join(buildDir, cfg.Name+".exe")
-	if err := p.copyFile(windowsBinary, binaryDest); err != nil {
+	if err := paths.CopyExecutable(windowsBinary, binaryDest, packager.CopyOptions(cfg)); err != nil {
 		return "", fmt.Errorf("failed to copy binary: %w", err)
 	}
 
-	// Generate WiX source file
+	// Generate WiX source file; candle resolves Source from its own
+	// working directory, so the binary is given by absolute path
+	binarySource, err := paths.Tool(binaryDest)
+	if err != nil {
+		return "", err
+	}
+	filesDir := filepath.Join(buildDir, "files")
+	if err := os.RemoveAll(filesDir); err != nil {
+		return "", err
+	}
+	if err := packager.StageFiles(filesDir, packager.ExtraFiles(cfg, "msi", ""), packager.CopyOptions(cfg)); err != nil {
+		return "", err
+	}
+	extra, err := wixFiles(filesDir, cfg.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to add files: %w", err)
+	}
+	// WiX v4 and later read a different schema from v3
+	wix, ok := requirements.WiX(ctx)
+	v4 := ok && wix.Version.Major >= 4
 	wxsPath := filepath.Join(buildDir, cfg.Name+".wxs")
-	if err := p.createWixSource(wxsPath, cfg, binaryDest); err != nil {
+	if err := p.createWixSource(wxsPath, cfg, binarySource, extra, v4); err != nil {
 		return "", fmt.Errorf("failed to generate WiX file: %w", err)
 	}
 
@@ -65,8 +90,23 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 	return p.buildMSI(ctx, buildDir, wxsPath, cfg)
 }
 
-func (p *Packager) createWixSource(path string, cfg *config.Config, binaryPath string) error {
+// createWixSource writes the WiX source, in the v4 schema when v4 is set
+// and the v3 schema otherwise. A PNG desktop icon is converted to an ICO
+// next to it.
+func (p *Packager) createWixSource(path string, cfg *config.Config, binaryPath string, extra wixTree, v4 bool) error {
 	tmpl := `<?xml version="1.0" encoding="UTF-8"?>
+{{- if .V4}}
+<Wix xmlns="http://wixtoolset.org/schemas/v4/wxs" xmlns:ui="http://wixtoolset.org/schemas/v4/wxs/ui">
+  <Package Name="{{.Name}}" 
+           Language="1033" 
+           Version="{{.Version}}.0" 
+           Manufacturer="{{.AuthorName}}" 
+           UpgradeCode="{{.UpgradeCode}}"
+           Compressed="yes" 
+           Scope="perMachine">
+    
+    <SummaryInformation Description="{{.Description}}" />
+{{- else}}
 <Wix xmlns="http://schemas.microsoft.com/wix/2006/wi">
   <Product Id="*" 
            Name="{{.Name}}" 
@@ -80,22 +120,48 @@ func (p *Packager) createWixSource(path string, cfg *config.Config, binaryPath s
              InstallScope="perMachine"
              Description="{{.Description}}"
              Comments="{{.Description}}" />
+{{- end}}
 
     <MajorUpgrade DowngradeErrorMessage="A newer version of [ProductName] is already installed." />
     <MediaTemplate EmbedCab="yes" />
+{{- if .Icon}}
+
+    <Icon Id="AppIcon.ico" SourceFile="{{.Icon}}" />
+    <Property Id="ARPPRODUCTICON" Value="AppIcon.ico" />
+{{- end}}
 
     <Feature Id="ProductFeature" Title="{{.Name}}" Level="1">
       <ComponentGroupRef Id="ProductComponents" />
+{{- range .Extra.Components}}
+      <ComponentRef Id="{{.}}" />
+{{- end}}
     </Feature>
 
+{{- if .V4}}
+    <StandardDirectory Id="ProgramFiles6432Folder">
+        <Directory Id="INSTALLFOLDER" Name="{{.Name}}"{{if .Extra.XML}}>
+{{.Extra.XML}}        </Directory>{{else}} />{{end}}
+    </StandardDirectory>
+    <StandardDirectory Id="ProgramMenuFolder">
+        <Directory Id="ApplicationProgramsFolder" Name="{{.Name}}" />
+    </StandardDirectory>
+{{- if .DesktopShortcut}}
+    <StandardDirectory Id="DesktopFolder" />
+{{- end}}
+{{- else}}
     <Directory Id="TARGETDIR" Name="SourceDir">
       <Directory Id="ProgramFilesFolder">
-        <Directory Id="INSTALLFOLDER" Name="{{.Name}}" />
+        <Directory Id="INSTALLFOLDER" Name="{{.Name}}"{{if .Extra.XML}}>
+{{.Extra.XML}}        </Directory>{{else}} />{{end}}
       </Directory>
       <Directory Id="ProgramMenuFolder">
         <Directory Id="ApplicationProgramsFolder" Name="{{.Name}}" />
       </Directory>
+{{- if .DesktopShortcut}}
+      <Directory Id="DesktopFolder" Name="Desktop" />
+{{- end}}
     </Directory>
+{{- end}}
 
     <ComponentGroup Id="ProductComponents" Directory="INSTALLFOLDER">
       <Component Id="MainExecutable" Guid="{{.ComponentGuid}}">
@@ -109,11 +175,41 @@ func (p *Packager) createWixSource(path string, cfg *config.Config, binaryPath s
         
         <!-- Start Menu shortcut -->
         <Shortcut Id="ApplicationStartMenuShortcut"
-                  Name="{{.Name}}"
+                  Name="{{.DisplayName}}"
                   Description="{{.Description}}"
                   Target="[#MainExe]"
-                  WorkingDirectory="INSTALLFOLDER"
+                  WorkingDirectory="INSTALLFOLDER"{{if .Icon}}
+                  Icon="AppIcon.ico"{{end}}
                   Directory="ApplicationProgramsFolder" />
+{{- if .DesktopShortcut}}
+        <Shortcut Id="ApplicationDesktopShortcut"
+                  Name="{{.DisplayName}}"
+                  Description="{{.Description}}"
+                  Target="[#MainExe]"
+                  WorkingDirectory="INSTALLFOLDER"{{if .Icon}}
+                  Icon="AppIcon.ico"{{end}}
+                  Directory="DesktopFolder" />
+{{- end}}
+{{- range .FileAssociations}}
+
+        <!-- Open .{{.Extension}} files -->
+        <ProgId Id="{{$.Name}}.{{.Extension}}" Description="{{.Description}}">
+          <Extension Id="{{.Extension}}" ContentType="{{.MimeType}}">
+            <Verb Id="open" Command="Open" TargetFile="MainExe" Argument="&quot;%1&quot;" />
+          </Extension>
+        </ProgId>
+{{- end}}
+{{- range .Protocols}}
+
+        <!-- Open {{.}}:// links -->
+        <RegistryKey Root="HKCR" Key="{{.}}">
+          <RegistryValue Type="string" Value="URL:{{.}} Protocol" />
+          <RegistryValue Name="URL Protocol" Type="string" Value="" />
+          <RegistryKey Key="shell\open\command">
+            <RegistryValue Type="string" Value="&quot;[#MainExe]&quot; &quot;%1&quot;" />
+          </RegistryKey>
+        </RegistryKey>
+{{- end}}
         
         <!-- Remove start menu folder on uninstall -->
         <RemoveFolder Id="ApplicationProgramsFolder" On="uninstall" />
@@ -129,8 +225,12 @@ func (p *Packager) createWixSource(path string, cfg *config.Config, binaryPath s
     </ComponentGroup>
 
     <!-- UI -->
+{{- if .V4}}
+    <ui:WixUI Id="WixUI_InstallDir" InstallDirectory="INSTALLFOLDER" />
+{{- else}}
     <UIRef Id="WixUI_InstallDir" />
     <Property Id="WIXUI_INSTALLDIR" Value="INSTALLFOLDER" />
+{{- end}}
     
     <!-- License -->
     <WixVariable Id="WixUILicenseRtf" Value="license.rtf" />
@@ -140,10 +240,14 @@ func (p *Packager) createWixSource(path string, cfg *config.Config, binaryPath s
     <Property Id="ARPCONTACT" Value="{{.AuthorName}}" />
     <Property Id="ARPHELPLINK" Value="{{.Homepage}}" />
     
-  </Product>
+  </{{if .V4}}Package{{else}}Product{{end}}>
 </Wix>`
 
-	t, err := template.New("wix").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "msi/wix", tmpl)
+	if err != nil {
+		return err
+	}
+	icon, err := wixIcon(cfg, filepath.Dir(path))
 	if err != nil {
 		return err
 	}
@@ -154,30 +258,178 @@ func (p *Packager) createWixSource(path string, cfg *config.Config, binaryPath s
 	}
 	defer f.Close()
 
-	// Parse author name
-	authorName := cfg.Author
-	if strings.Contains(cfg.Author, "<") {
-		parts := strings.Split(cfg.Author, "<")
-		authorName = strings.TrimSpace(parts[0])
-	}
-
 	data := struct {
 		*config.Config
 		AuthorName    string
 		BinaryPath    string
 		UpgradeCode   string
 		ComponentGuid string
+		Extra         wixTree
+		V4            bool
+		wixDesktop
 	}{
 		Config:        cfg,
-		AuthorName:    authorName,
+		AuthorName:    p.getAuthorName(cfg),
 		BinaryPath:    binaryPath,
 		UpgradeCode:   fmt.Sprintf("{%s-UPGRADE-CODE-GUID}", strings.ToUpper(cfg.Name)),
 		ComponentGuid: fmt.Sprintf("{%s-COMPONENT-GUID}", strings.ToUpper(cfg.Name)),
+		Extra:         extra,
+		V4:            v4,
+		wixDesktop:    newWixDesktop(cfg, icon),
 	}
 
 	return t.Execute(f, data)
 }
 
+// wixDesktop is the desktop integration in the WiX source, with text
+// escaped for XML
+type wixDesktop struct {
+	DisplayName      string
+	Icon             string // ICO file, absolute
+	DesktopShortcut  bool
+	FileAssociations []wixAssociation
+	Protocols        []string
+}
+
+type wixAssociation struct {
+	Extension   string
+	Description string
+	MimeType    string
+}
+
+func newWixDesktop(cfg *config.Config, icon string) wixDesktop {
+	desktop := wixDesktop{DisplayName: wixEscape(packager.DesktopName(cfg)), Icon: wixEscape(icon)}
+	if cfg.Desktop == nil {
+		return desktop
+	}
+	desktop.DesktopShortcut = cfg.Desktop.DesktopShortcut
+	desktop.Protocols = cfg.Desktop.Protocols
+	for _, assoc := range cfg.Desktop.FileAssociations {
+		desktop.FileAssociations = append(desktop.FileAssociations, wixAssociation{
+			Extension:   packager.Extension(assoc),
+			Description: wixEscape(assoc.Description),
+			MimeType:    wixEscape(packager.MimeType(assoc)),
+		})
+	}
+	return desktop
+}
+
+// wixIcon prepares the desktop icon for WiX, which needs an ICO file: an
+// ICO is used as is and a PNG converted. Other icons are left out.
+func wixIcon(cfg *config.Config, buildDir string) (string, error) {
+	if cfg.Desktop == nil || cfg.Desktop.Icon == "" {
+		return "", nil
+	}
+	icon := cfg.Desktop.Icon
+	switch strings.ToLower(filepath.Ext(icon)) {
+	case ".ico":
+	case ".png":
+		icon = filepath.Join(buildDir, cfg.Name+".ico")
+		if err := packager.WriteICO(cfg.Desktop.Icon, icon); err != nil {
+			return "", fmt.Errorf("failed to convert icon: %w", err)
+		}
+	default:
+		return "", nil
+	}
+	return paths.Tool(icon)
+}
+
+// wixTree is the WiX markup installing the extra files into INSTALLFOLDER
+type wixTree struct {
+	XML        string   // Directory and Component elements
+	Components []string // component IDs for the feature
+}
+
+// wixFiles harvests the tree staged under root, as heat does: one
+// component per file so each gets its own generated GUID, and a
+// CreateFolder component for each empty directory so it is installed too.
+// IDs are derived from the relative path, which keeps them stable between
+// builds.
+func wixFiles(root, product string) (wixTree, error) {
+	var tree wixTree
+	var b strings.Builder
+	depth := 0
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		relPath, err := filepath.Rel(root, path)
+		if err != nil || relPath == "." {
+			return err
+		}
+		relPath = paths.Slash(relPath)
+
+		// Close the directories the walk has left
+		level := strings.Count(relPath, "/")
+		for ; depth > level; depth-- {
+			b.WriteString(strings.Repeat("  ", depth+4) + "</Directory>\n")
+		}
+		indent := strings.Repeat("  ", depth+5)
+
+		if info.IsDir() {
+			fmt.Fprintf(&b, "%s<Directory Id=\"%s\" Name=\"%s\">\n", indent, wixID("dir", relPath), wixEscape(info.Name()))
+			depth++
+			entries, err := os.ReadDir(path)
+			if err != nil || len(entries) > 0 {
+				return err
+			}
+			// An empty directory has no file to key an automatic GUID
+			// on, so its GUID is derived from the product and path
+			component := wixID("cmp", relPath)
+			fmt.Fprintf(&b, "%s  <Component Id=\"%s\" Guid=\"%s\">\n", indent, component, wixGUID(product, relPath))
+			fmt.Fprintf(&b, "%s    <CreateFolder />\n", indent)
+			fmt.Fprintf(&b, "%s  </Component>\n", indent)
+			tree.Components = append(tree.Components, component)
+			return nil
+		}
+		source, err := paths.Tool(path)
+		if err != nil {
+			return err
+		}
+		component := wixID("cmp", relPath)
+		fmt.Fprintf(&b, "%s<Component Id=\"%s\" Guid=\"*\">\n", indent, component)
+		fmt.Fprintf(&b, "%s  <File Id=\"%s\" Source=\"%s\" Name=\"%s\" KeyPath=\"yes\" />\n", indent, wixID("fil", relPath), wixEscape(source), wixEscape(info.Name()))
+		fmt.Fprintf(&b, "%s</Component>\n", indent)
+		tree.Components = append(tree.Components, component)
+		return nil
+	})
+	if err != nil {
+		return wixTree{}, err
+	}
+	for ; depth > 0; depth-- {
+		b.WriteString(strings.Repeat("  ", depth+4) + "</Directory>\n")
+	}
+	tree.XML = b.String()
+	return tree, nil
+}
+
+// wixID returns a WiX identifier for relPath; WiX IDs are limited to 72
+// characters of [A-Za-z0-9_.], so the path is hashed
+func wixID(prefix, relPath string) string {
+	h := fnv.New64a()
+	h.Write([]byte(relPath))
+	return fmt.Sprintf("%s_%016x", prefix, h.Sum64())
+}
+
+// wixGUID returns a GUID that is the same for every build of product's
+// component at relPath, as component rules require
+func wixGUID(product, relPath string) string {
+	sum := sha1.Sum([]byte(product + "\x00" + relPath))
+	sum[6] = sum[6]&0x0f | 0x50 // version 5, name-based
+	sum[8] = sum[8]&0x3f | 0x80
+	return strings.ToUpper(fmt.Sprintf("{%x-%x-%x-%x-%x}", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16]))
+}
+
+// wixEscape escapes s for an XML attribute
+func wixEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
 func (p *Packager) createBuildScript(path string, cfg *config.Config) error {
 	tmpl := `@echo off
 REM Build script for {{.Name}} MSI installer
@@ -226,7 +478,7 @@ echo   msiexec /i %MSI_FILE%           (Install)
 echo   msiexec /x %MSI_FILE%           (Uninstall)
 echo   %MSI_FILE%                      (Interactive install)`
 
-	t, err := template.New("build").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "msi/build", tmpl)
 	if err != nil {
 		return err
 	}
@@ -300,7 +552,7 @@ Write-Host "  msiexec /i $MsiFile           (Install)" -ForegroundColor White
 Write-Host "  msiexec /x $MsiFile           (Uninstall)" -ForegroundColor White
 Write-Host "  ./$MsiFile                    (Interactive install)" -ForegroundColor White`
 
-	t, err := template.New("ps1").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "msi/ps1", tmpl)
 	if err != nil {
 		return err
 	}
@@ -329,16 +581,28 @@ func (p *Packager) buildMSI(ctx context.Context, buildDir, wxsPath string, cfg *
 		return p.buildWithGoMSI(ctx, buildDir, cfg, outputPath)
 	}
 
-	return "", fmt.Errorf("MSI build tools not found - install WiX Toolset (Windows) or go-msi")
+	return "", errors.ToolNotFoundError("MSI build tools not found - install WiX Toolset (Windows) or go-msi")
 }
 
 func (p *Packager) buildWithWix(ctx context.Context, buildDir, wxsPath, outputPath string) error {
 	// Check for WiX tools
-	if _, err := exec.LookPath("candle"); err != nil {
-		return fmt.Errorf("candle not found")
+	wix, ok := requirements.WiX(ctx)
+	if !ok {
+		return fmt.Errorf("WiX Toolset not found")
 	}
-	if _, err := exec.LookPath("light"); err != nil {
-		return fmt.Errorf("light not found")
+
+	// The tools run from buildDir, so every path they are given is absolute
+	wxsPath, err := paths.Tool(wxsPath)
+	if err != nil {
+		return err
+	}
+	outputPath, err = paths.Tool(outputPath)
+	if err != nil {
+		return err
+	}
+
+	if wix.Version.Major >= 4 {
+		return p.buildWithWix4(ctx, buildDir, wxsPath, outputPath)
 	}
 
 	// Compile WiX source
@@ -360,7 +624,22 @@ func (p *Packager) buildWithWix(ctx context.Context, buildDir, wxsPath, outputPa
 	return nil
 }
 
+// buildWithWix4 builds a v4 source with the single wix command of WiX v4
+// and later
+func (p *Packager) buildWithWix4(ctx context.Context, buildDir, wxsPath, outputPath string) error {
+	buildCmd := exec.CommandContext(ctx, "wix", "build", "-o", outputPath, wxsPath, "-ext", "WixToolset.UI.wixext")
+	buildCmd.Dir = buildDir
+	if output, err := buildCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("wix build failed: %w\nOutput: %s", err, output)
+	}
+	return nil
+}
+
 func (p *Packager) buildWithGoMSI(ctx context.Context, buildDir string, cfg *config.Config, outputPath string) (string, error) {
+	if len(packager.ExtraFiles(cfg, "msi", "")) > 0 {
+		fmt.Println("⚠️  go-msi builds install only the binary; install the WiX Toolset to ship files")
+	}
+
 	// Create go-msi configuration
 	goMSIConfig := fmt.Sprintf(`{
   "product-name": "%s",
@@ -382,8 +661,12 @@ func (p *Packager) buildWithGoMSI(ctx context.Context, buildDir string, cfg *con
 		return "", err
 	}
 
-	// Build with go-msi
-	cmd := exec.CommandContext(ctx, "go-msi", "make", "--msi", outputPath, "--version", cfg.Version)
+	// Build with go-msi, which runs from buildDir
+	msiPath, err := paths.Tool(outputPath)
+	if err != nil {
+		return "", err
+	}
+	cmd := exec.CommandContext(ctx, "go-msi", "make", "--msi", msiPath, "--version", cfg.Version)
 	cmd.Dir = buildDir
 	
 	if output, err := cmd.CombinedOutput(); err != nil {
@@ -403,17 +686,5 @@ func (p *Packager) generateUpgradeCode(cfg *config.Config) string {
 }
 
 func (p *Packager) getAuthorName(cfg *config.Config) string {
-	if strings.Contains(cfg.Author, "<") {
-		parts := strings.Split(cfg.Author, "<")
-		return strings.TrimSpace(parts[0])
-	}
-	return cfg.Author
-}
-
-func (p *Packager) copyFile(src, dst string) error {
-	data, err := os.ReadFile(src)
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(dst, data, 0755)
+	return cfg.PrimaryAuthor().Name
 }
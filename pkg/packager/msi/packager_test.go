@@ -1,12 +1,18 @@
 package msi
 
 import (
+	"bytes"
 	"context"
+	"encoding/xml"
+	"image"
+	"image/png"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
 )
 
 func TestMSIPackager(t *testing.T) {
@@ -122,7 +128,7 @@ func TestCreateWixSource(t *testing.T) {
 		Author:      "Test Author <test@example.com>",
 	}
 
-	if err := packager.createWixSource(wxsPath, cfg, binaryPath); err != nil {
+	if err := packager.createWixSource(wxsPath, cfg, binaryPath, wixTree{}, false); err != nil {
 		t.Errorf("createWixSource() error = %v", err)
 	}
 
@@ -218,8 +224,6 @@ func TestGetAuthorName(t *testing.T) {
 }
 
 func TestCopyFile(t *testing.T) {
-	packager := New()
-	
 	tmpDir := t.TempDir()
 	srcPath := filepath.Join(tmpDir, "source.exe")
 	dstPath := filepath.Join(tmpDir, "dest.exe")
@@ -229,8 +233,8 @@ func TestCopyFile(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := packager.copyFile(srcPath, dstPath); err != nil {
-		t.Errorf("copyFile() error = %v", err)
+	if err := paths.CopyExecutable(srcPath, dstPath, paths.CopyOptions{}); err != nil {
+		t.Errorf("CopyExecutable() error = %v", err)
 	}
 
 	// Check destination file
@@ -255,12 +259,10 @@ func TestCopyFile(t *testing.T) {
 }
 
 func TestCopyFile_Error(t *testing.T) {
-	packager := New()
-	
 	// Test with non-existent source file
-	err := packager.copyFile("/non/existent/file", "/tmp/dest")
+	err := paths.CopyExecutable("/non/existent/file", "/tmp/dest", paths.CopyOptions{})
 	if err == nil {
-		t.Error("copyFile() should fail with non-existent source file")
+		t.Error("CopyExecutable() should fail with non-existent source file")
 	}
 }
 
@@ -432,7 +434,7 @@ func TestCreateWixSource_Error(t *testing.T) {
 	packager := New()
 	
 	// Test with invalid path (directory that doesn't exist)
-	err := packager.createWixSource("/non/existent/dir/test.wxs", &config.Config{}, "test.exe")
+	err := packager.createWixSource("/non/existent/dir/test.wxs", &config.Config{}, "test.exe", wixTree{}, false)
 	if err == nil {
 		t.Error("createWixSource() should fail with invalid path")
 	}
@@ -459,6 +461,10 @@ func TestCreatePowerShellScript_Error(t *testing.T) {
 }
 
 func TestMSIPack_MissingBinary(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(t.TempDir())
+
 	packager := New()
 	
 	cfg := &config.Config{
@@ -493,3 +499,139 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestWixFiles(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "examples", "plugins"), 0755)
+	os.MkdirAll(filepath.Join(root, "logs"), 0755)
+	os.WriteFile(filepath.Join(root, "config.yaml"), []byte("a: 1"), 0644)
+	os.WriteFile(filepath.Join(root, "examples", "plugins", "hello & bye.lua"), []byte("--"), 0644)
+
+	tree, err := wixFiles(root, "testapp")
+	if err != nil {
+		t.Fatalf("wixFiles() error = %v", err)
+	}
+	if len(tree.Components) != 3 {
+		t.Errorf("Components = %v, want one per file and empty directory", tree.Components)
+	}
+	for _, expected := range []string{
+		`Name="examples">`,
+		`Name="plugins">`,
+		`Name="hello &amp; bye.lua" KeyPath="yes" />`,
+		`Name="config.yaml" KeyPath="yes" />`,
+		`Name="logs">`,
+		`<CreateFolder />`,
+	} {
+		if !strings.Contains(tree.XML, expected) {
+			t.Errorf("WiX markup missing %q:\n%s", expected, tree.XML)
+		}
+	}
+	if strings.Count(tree.XML, "<Directory ") != strings.Count(tree.XML, "</Directory>") {
+		t.Errorf("unbalanced Directory elements:\n%s", tree.XML)
+	}
+
+	wxsPath := filepath.Join(t.TempDir(), "test.wxs")
+	cfg := &config.Config{Name: "testapp", Version: "1.0.0"}
+	if err := New().createWixSource(wxsPath, cfg, "testapp.exe", tree, false); err != nil {
+		t.Fatalf("createWixSource() error = %v", err)
+	}
+	content, _ := os.ReadFile(wxsPath)
+	for _, id := range tree.Components {
+		if !strings.Contains(string(content), `<ComponentRef Id="`+id+`" />`) {
+			t.Errorf("feature does not reference %s", id)
+		}
+	}
+
+	// The empty directory's GUID is stable and differs between products
+	again, _ := wixFiles(root, "testapp")
+	other, _ := wixFiles(root, "otherapp")
+	if again.XML != tree.XML || other.XML == tree.XML {
+		t.Error("expected GUIDs stable per product and distinct between products")
+	}
+
+	empty, err := wixFiles(filepath.Join(root, "missing"), "testapp")
+	if err != nil || empty.XML != "" {
+		t.Errorf("wixFiles() without files = %+v, %v", empty, err)
+	}
+}
+
+func TestCreateWixSource_V4(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "config.yaml"), []byte("a: 1"), 0644)
+	tree, err := wixFiles(root, "testapp")
+	if err != nil {
+		t.Fatalf("wixFiles() error = %v", err)
+	}
+
+	cfg := &config.Config{Name: "testapp", Version: "1.0.0", Description: "Test application", Author: "Test Author"}
+	for _, v4 := range []bool{false, true} {
+		wxsPath := filepath.Join(t.TempDir(), "test.wxs")
+		if err := New().createWixSource(wxsPath, cfg, "testapp.exe", tree, v4); err != nil {
+			t.Fatalf("createWixSource(v4=%v) error = %v", v4, err)
+		}
+		content, _ := os.ReadFile(wxsPath)
+
+		// Well-formed, with the root element in the schema's namespace
+		var doc struct {
+			XMLName xml.Name
+		}
+		if err := xml.Unmarshal(content, &doc); err != nil {
+			t.Fatalf("v4=%v: invalid XML: %v\n%s", v4, err, content)
+		}
+		wantNS, wantTop, absent := "http://schemas.microsoft.com/wix/2006/wi", "<Product ", "<StandardDirectory"
+		if v4 {
+			wantNS, wantTop, absent = "http://wixtoolset.org/schemas/v4/wxs", `<Package Name="testapp"`, "<Product"
+		}
+		if doc.XMLName.Space != wantNS {
+			t.Errorf("v4=%v: namespace %q, want %q", v4, doc.XMLName.Space, wantNS)
+		}
+		if !strings.Contains(string(content), wantTop) || strings.Contains(string(content), absent) {
+			t.Errorf("v4=%v: unexpected schema:\n%s", v4, content)
+		}
+	}
+}
+
+func TestCreateWixSource_Desktop(t *testing.T) {
+	dir := t.TempDir()
+	icon := filepath.Join(dir, "icon.png")
+	var b bytes.Buffer
+	png.Encode(&b, image.NewRGBA(image.Rect(0, 0, 256, 256)))
+	os.WriteFile(icon, b.Bytes(), 0644)
+
+	cfg := &config.Config{
+		Name:    "testapp",
+		Version: "1.0.0",
+		Desktop: &config.DesktopConfig{
+			Name:             "Test & App",
+			Icon:             icon,
+			DesktopShortcut:  true,
+			FileAssociations: []config.FileAssociationConfig{{Extension: "tdoc", Description: "Test Document"}},
+			Protocols:        []string{"testapp"},
+		},
+	}
+	for _, v4 := range []bool{false, true} {
+		wxsPath := filepath.Join(dir, "test.wxs")
+		if err := New().createWixSource(wxsPath, cfg, "testapp.exe", wixTree{}, v4); err != nil {
+			t.Fatalf("createWixSource(v4=%v) error = %v", v4, err)
+		}
+		content, _ := os.ReadFile(wxsPath)
+		if err := xml.Unmarshal(content, new(struct{ XMLName xml.Name })); err != nil {
+			t.Fatalf("v4=%v: invalid XML: %v\n%s", v4, err, content)
+		}
+		for _, expected := range []string{
+			`<Icon Id="AppIcon.ico" SourceFile="` + filepath.Join(dir, "testapp.ico") + `" />`,
+			`Name="Test &amp; App"`,
+			`Directory="DesktopFolder"`,
+			`<ProgId Id="testapp.tdoc" Description="Test Document">`,
+			`<Extension Id="tdoc" ContentType="application/x-tdoc">`,
+			`<RegistryKey Root="HKCR" Key="testapp">`,
+		} {
+			if !strings.Contains(string(content), expected) {
+				t.Errorf("v4=%v: WiX source missing %q", v4, expected)
+			}
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "testapp.ico")); err != nil {
+		t.Errorf("icon not converted: %v", err)
+	}
+}
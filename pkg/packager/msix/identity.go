@@ -0,0 +1,77 @@
+package msix
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// publisherIDAlphabet is the Crockford base32 alphabet Windows uses for
+// publisher IDs
+const publisherIDAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// Identity is the package identity written to AppxManifest.xml
+type Identity struct {
+	Name                 string
+	Publisher            string
+	PublisherDisplayName string
+}
+
+// PackageIdentity returns the configured identity, falling back to one
+// derived from the primary author for sideloaded packages
+func PackageIdentity(cfg *config.Config) Identity {
+	author := cfg.PrimaryAuthor().Name
+	identity := Identity{
+		Name:                 cfg.Packages.MSIX.IdentityName,
+		Publisher:            cfg.Packages.MSIX.Publisher,
+		PublisherDisplayName: cfg.Packages.MSIX.PublisherDisplayName,
+	}
+	if identity.Name == "" {
+		identity.Name = fmt.Sprintf("com.%s.%s", strings.ToLower(author), strings.ToLower(cfg.Name))
+	}
+	if identity.Publisher == "" {
+		identity.Publisher = "CN=" + author
+	}
+	if identity.PublisherDisplayName == "" {
+		identity.PublisherDisplayName = author
+	}
+	return identity
+}
+
+// PublisherID computes the 13 character publisher ID Windows derives from
+// the publisher subject: the first 8 bytes of the SHA-256 of its UTF-16LE
+// encoding, in base32
+func PublisherID(publisher string) string {
+	var encoded []byte
+	for _, unit := range utf16.Encode([]rune(publisher)) {
+		encoded = append(encoded, byte(unit), byte(unit>>8))
+	}
+	sum := sha256.Sum256(encoded)
+
+	var bits uint64
+	for _, b := range sum[:8] {
+		bits = bits<<8 | uint64(b)
+	}
+
+	// 64 bits padded to 65 give 13 groups of 5
+	id := make([]byte, 13)
+	for i := 0; i < 13; i++ {
+		shift := 59 - 5*i
+		var group uint64
+		if shift >= 0 {
+			group = bits >> uint(shift) & 0x1f
+		} else {
+			group = bits << uint(-shift) & 0x1f
+		}
+		id[i] = publisherIDAlphabet[group]
+	}
+	return string(id)
+}
+
+// PackageFamilyName returns the package family name of an identity
+func (i Identity) PackageFamilyName() string {
+	return i.Name + "_" + PublisherID(i.Publisher)
+}
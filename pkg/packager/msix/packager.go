@@ -2,13 +2,16 @@ package msix
 
 import (
 	"context"
+	"encoding/xml"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
-	"text/template"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
 )
 
 type Packager struct{}
@@ -22,12 +25,39 @@ func (p *Packager) Name() string {
 }
 
 func (p *Packager) Validate(cfg *config.Config) error {
+	hasWindows := false
 	for arch := range cfg.Binaries {
 		if strings.HasPrefix(arch, "windows-") {
-			return nil
+			hasWindows = true
+			break
 		}
 	}
-	return fmt.Errorf("no Windows binary found for MSIX creation")
+	if !hasWindows {
+		return errors.NoPlatformBinaryError("no Windows binary found for MSIX creation")
+	}
+
+	msix := cfg.Packages.MSIX
+	if msix.PackageFamilyName != "" {
+		if family := PackageIdentity(cfg).PackageFamilyName(); family != msix.PackageFamilyName {
+			return fmt.Errorf("package_family_name %s does not match identity_name and publisher (%s)", msix.PackageFamilyName, family)
+		}
+	}
+
+	for _, flight := range msix.Store.Flights {
+		if flight.Name == "" {
+			return fmt.Errorf("msix.store.flights: name is required")
+		}
+		if flight.ID == "" && len(flight.Groups) == 0 {
+			return fmt.Errorf("msix.store.flights.%s: groups are required to create the flight", flight.Name)
+		}
+	}
+	if _, err := SelectedFlight(cfg); err != nil {
+		return err
+	}
+	if msix.Store.Rollout < 0 || msix.Store.Rollout > 100 {
+		return fmt.Errorf("msix.store.rollout must be between 0 and 100")
+	}
+	return nil
 }
 
 func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
@@ -44,6 +74,13 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 		return "", err
 	}
 
+	// The desktop icon becomes the package logos
+	if hasIcon(cfg) {
+		if err := paths.CopyFile(cfg.Desktop.Icon, filepath.Join(msixDir, "Assets", "icon.png"), packager.CopyOptions(cfg)); err != nil {
+			return "", fmt.Errorf("failed to copy icon: %w", err)
+		}
+	}
+
 	// Create AppxManifest.xml
 	manifestPath := filepath.Join(msixDir, "AppxManifest.xml")
 	if err := p.createManifest(manifestPath, cfg); err != nil {
@@ -57,7 +94,7 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 	}
 
 	// Create mock MSIX
-	outputPath := filepath.Join("dist", fmt.Sprintf("%s-%s.msix", cfg.Name, cfg.Version))
+	outputPath := OutputPath(cfg)
 	mockMSIX := fmt.Sprintf("# Mock MSIX for %s %s\n# Generated by bagboy\n# Modern Windows app package\n# Run: cd %s && .\\build-msix.ps1\n", cfg.Name, cfg.Version, msixDir)
 	if err := os.WriteFile(outputPath, []byte(mockMSIX), 0644); err != nil {
 		return "", err
@@ -66,18 +103,23 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 	return outputPath, nil
 }
 
+// OutputPath returns the path Pack writes the MSIX to
+func OutputPath(cfg *config.Config) string {
+	return filepath.Join("dist", fmt.Sprintf("%s-%s.msix", cfg.Name, cfg.Version))
+}
+
 func (p *Packager) createManifest(path string, cfg *config.Config) error {
 	tmpl := `<?xml version="1.0" encoding="utf-8"?>
 <Package xmlns="http://schemas.microsoft.com/appx/manifest/foundation/windows10"
          xmlns:uap="http://schemas.microsoft.com/appx/manifest/uap/windows10">
-  <Identity Name="{{.PackageId}}"
+  <Identity Name="{{.Identity.Name}}"
             Version="{{.Version}}.0"
-            Publisher="CN={{.Publisher}}"
+            Publisher="{{.Identity.Publisher}}"
             ProcessorArchitecture="x64" />
   
   <Properties>
-    <DisplayName>{{.Name}}</DisplayName>
-    <PublisherDisplayName>{{.Publisher}}</PublisherDisplayName>
+    <DisplayName>{{.DisplayName}}</DisplayName>
+    <PublisherDisplayName>{{.Identity.PublisherDisplayName}}</PublisherDisplayName>
     <Description>{{.Description}}</Description>
     <Logo>Assets\StoreLogo.png</Logo>
   </Properties>
@@ -88,12 +130,33 @@ func (p *Packager) createManifest(path string, cfg *config.Config) error {
   
   <Applications>
     <Application Id="{{.Name}}" Executable="{{.Name}}.exe" EntryPoint="Windows.FullTrustApplication">
-      <uap:VisualElements DisplayName="{{.Name}}"
+      <uap:VisualElements DisplayName="{{.DisplayName}}"
                           Description="{{.Description}}"
                           BackgroundColor="transparent"
                           Square150x150Logo="Assets\Square150x150Logo.png"
                           Square44x44Logo="Assets\Square44x44Logo.png">
       </uap:VisualElements>
+{{- if or .FileAssociations .Protocols}}
+      <Extensions>
+{{- range .FileAssociations}}
+        <uap:Extension Category="windows.fileTypeAssociation">
+          <uap:FileTypeAssociation Name="{{.Name}}">
+{{- if .Description}}
+            <uap:DisplayName>{{.Description}}</uap:DisplayName>
+{{- end}}
+            <uap:SupportedFileTypes>
+              <uap:FileType ContentType="{{.MimeType}}">.{{.Name}}</uap:FileType>
+            </uap:SupportedFileTypes>
+          </uap:FileTypeAssociation>
+        </uap:Extension>
+{{- end}}
+{{- range .Protocols}}
+        <uap:Extension Category="windows.protocol">
+          <uap:Protocol Name="{{.}}" />
+        </uap:Extension>
+{{- end}}
+      </Extensions>
+{{- end}}
     </Application>
   </Applications>
   
@@ -103,7 +166,7 @@ func (p *Packager) createManifest(path string, cfg *config.Config) error {
   </Capabilities>
 </Package>`
 
-	t, err := template.New("manifest").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "msix/manifest", tmpl)
 	if err != nil {
 		return err
 	}
@@ -114,20 +177,31 @@ func (p *Packager) createManifest(path string, cfg *config.Config) error {
 	}
 	defer f.Close()
 
-	publisher := cfg.Author
-	if strings.Contains(cfg.Author, "<") {
-		parts := strings.Split(cfg.Author, "<")
-		publisher = strings.TrimSpace(parts[0])
+	type association struct {
+		Name        string // the extension, which also names the association
+		Description string
+		MimeType    string
 	}
-
 	data := struct {
 		*config.Config
-		PackageId string
-		Publisher string
+		Identity         Identity
+		DisplayName      string
+		FileAssociations []association
+		Protocols        []string
 	}{
-		Config:    cfg,
-		PackageId: fmt.Sprintf("com.%s.%s", strings.ToLower(publisher), strings.ToLower(cfg.Name)),
-		Publisher: publisher,
+		Config:      cfg,
+		Identity:    PackageIdentity(cfg),
+		DisplayName: xmlEscape(packager.DesktopName(cfg)),
+	}
+	if cfg.Desktop != nil {
+		data.Protocols = cfg.Desktop.Protocols
+		for _, assoc := range cfg.Desktop.FileAssociations {
+			data.FileAssociations = append(data.FileAssociations, association{
+				Name:        strings.ToLower(packager.Extension(assoc)),
+				Description: xmlEscape(assoc.Description),
+				MimeType:    xmlEscape(packager.MimeType(assoc)),
+			})
+		}
 	}
 
 	return t.Execute(f, data)
@@ -158,11 +232,18 @@ New-Item -ItemType Directory -Path "$PackageDir\Assets" -Force | Out-Null
 Copy-Item "AppxManifest.xml" "$PackageDir\"
 Copy-Item $BinaryPath "$PackageDir\$AppName.exe"
 
-# Create placeholder assets (in production, use real icons)
+{{- if .Icon}}
+# Logos from the desktop icon
+foreach ($Logo in "StoreLogo", "Square150x150Logo", "Square44x44Logo") {
+    Copy-Item "Assets\icon.png" "$PackageDir\Assets\$Logo.png"
+}
+{{- else}}
+# Create placeholder assets (set desktop.icon to use a real icon)
 $PlaceholderIcon = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg=="
 [System.IO.File]::WriteAllBytes("$PackageDir\Assets\StoreLogo.png", [System.Convert]::FromBase64String($PlaceholderIcon))
 [System.IO.File]::WriteAllBytes("$PackageDir\Assets\Square150x150Logo.png", [System.Convert]::FromBase64String($PlaceholderIcon))
 [System.IO.File]::WriteAllBytes("$PackageDir\Assets\Square44x44Logo.png", [System.Convert]::FromBase64String($PlaceholderIcon))
+{{- end}}
 
 # Build MSIX
 try {
@@ -188,7 +269,7 @@ try {
     Write-Error "MakeAppx.exe not found. Install Windows SDK."
 }`
 
-	t, err := template.New("build").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "msix/build", tmpl)
 	if err != nil {
 		return err
 	}
@@ -202,10 +283,25 @@ try {
 	data := struct {
 		*config.Config
 		BinaryPath string
+		Icon       bool
 	}{
 		Config:     cfg,
 		BinaryPath: binaryPath,
+		Icon:       hasIcon(cfg),
 	}
 
 	return t.Execute(f, data)
 }
+
+// hasIcon reports whether the desktop icon can be the package logo, which
+// must be a PNG
+func hasIcon(cfg *config.Config) bool {
+	return cfg.Desktop != nil && strings.EqualFold(filepath.Ext(cfg.Desktop.Icon), ".png")
+}
+
+// xmlEscape escapes s for XML text and attributes
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
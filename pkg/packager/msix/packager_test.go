@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
@@ -98,6 +99,71 @@ func TestMSIXPackagerValidation(t *testing.T) {
 	}
 }
 
+func TestPackageFamilyName(t *testing.T) {
+	identity := Identity{
+		Name:      "Microsoft.WindowsCalculator",
+		Publisher: "CN=Microsoft Corporation, O=Microsoft Corporation, L=Redmond, S=Washington, C=US",
+	}
+	if family := identity.PackageFamilyName(); family != "Microsoft.WindowsCalculator_8wekyb3d8bbwe" {
+		t.Errorf("PackageFamilyName() = %s", family)
+	}
+
+	cfg := &config.Config{
+		Name:     "testapp",
+		Binaries: map[string]string{"windows-amd64": "testapp.exe"},
+		Packages: config.PackagesConfig{MSIX: config.MSIXConfig{
+			IdentityName:      identity.Name,
+			Publisher:         identity.Publisher,
+			PackageFamilyName: "Microsoft.WindowsCalculator_0000000000000",
+		}},
+	}
+	if err := New().Validate(cfg); err == nil {
+		t.Error("Expected validation to fail for a mismatched package family name")
+	}
+}
+
+func TestSubmitToStore(t *testing.T) {
+	var calls [][]string
+	defer func(run func(context.Context, ...string) ([]byte, error)) { runMSStore = run }(runMSStore)
+	runMSStore = func(ctx context.Context, args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		if args[0] == "flights" {
+			return []byte("Flight created: 0b6f2f3a-1c2d-4e5f-8a9b-0c1d2e3f4a5b\n"), nil
+		}
+		return nil, nil
+	}
+
+	cfg := &config.Config{Packages: config.PackagesConfig{MSIX: config.MSIXConfig{Store: config.MSIXStoreConfig{
+		ProductID: "9NBLGGH4R315",
+		Flight:    "insiders",
+		Rollout:   25,
+		Flights: []config.MSIXFlightConfig{
+			{Name: "insiders", Groups: []string{"1001", "1002"}},
+		},
+	}}}}
+
+	flightID, err := SubmitToStore(context.Background(), cfg, "dist/testapp.msix")
+	if err != nil {
+		t.Fatalf("SubmitToStore() error: %v", err)
+	}
+	if flightID != "0b6f2f3a-1c2d-4e5f-8a9b-0c1d2e3f4a5b" {
+		t.Errorf("flightID = %s", flightID)
+	}
+
+	expected := [][]string{
+		{"flights", "create", "9NBLGGH4R315", "insiders", "--group-ids", "1001", "1002"},
+		{"publish", "dist/testapp.msix", "--appId", "9NBLGGH4R315", "--flightId", flightID, "--packageRolloutPercentage", "25"},
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("msstore calls = %v", calls)
+	}
+
+	cfg.Packages.MSIX.Store.Flight = "beta"
+	if _, err := SubmitToStore(context.Background(), cfg, "dist/testapp.msix"); err == nil {
+		t.Error("Expected error for an unknown flight")
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && (s[:len(substr)] == substr || s[len(s)-len(substr):] == substr || containsSubstring(s, substr)))
 }
@@ -110,3 +176,51 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestMSIXPackager_Desktop(t *testing.T) {
+	testDir := t.TempDir()
+	testBinary := filepath.Join(testDir, "test-windows-amd64.exe")
+	os.WriteFile(testBinary, []byte("fake binary"), 0755)
+	icon := filepath.Join(testDir, "icon.png")
+	os.WriteFile(icon, []byte("png"), 0644)
+
+	cfg := &config.Config{
+		Name:    "testapp",
+		Version: "1.0.0",
+		Binaries: map[string]string{"windows-amd64": testBinary},
+		Desktop: &config.DesktopConfig{
+			Name:             "Test App",
+			Icon:             icon,
+			FileAssociations: []config.FileAssociationConfig{{Extension: ".TDoc", Description: "Test Document"}},
+			Protocols:        []string{"testapp"},
+		},
+	}
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	if _, err := New().Pack(context.Background(), cfg); err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	manifest, _ := os.ReadFile(filepath.Join("dist", "msix", "AppxManifest.xml"))
+	for _, expected := range []string{
+		"<DisplayName>Test App</DisplayName>",
+		`<uap:FileTypeAssociation Name="tdoc">`,
+		"<uap:DisplayName>Test Document</uap:DisplayName>",
+		`<uap:FileType ContentType="application/x-tdoc">.tdoc</uap:FileType>`,
+		`<uap:Protocol Name="testapp" />`,
+	} {
+		if !contains(string(manifest), expected) {
+			t.Errorf("manifest missing %q:\n%s", expected, manifest)
+		}
+	}
+
+	script, _ := os.ReadFile(filepath.Join("dist", "msix", "build-msix.ps1"))
+	if !contains(string(script), `Copy-Item "Assets\icon.png"`) || contains(string(script), "PlaceholderIcon") {
+		t.Error("build script should use the desktop icon")
+	}
+	if _, err := os.Stat(filepath.Join("dist", "msix", "Assets", "icon.png")); err != nil {
+		t.Errorf("icon not staged: %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package msix
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+var flightIDPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// runMSStore runs the Microsoft Store Developer CLI
+var runMSStore = func(ctx context.Context, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath("msstore"); err != nil {
+		return nil, fmt.Errorf("msstore not found - install the Microsoft Store Developer CLI")
+	}
+	output, err := exec.CommandContext(ctx, "msstore", args...).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("msstore %s failed: %w\nOutput: %s", args[0], err, output)
+	}
+	return output, nil
+}
+
+// SelectedFlight returns the flight releases are submitted to, or nil for
+// the public Store listing
+func SelectedFlight(cfg *config.Config) (*config.MSIXFlightConfig, error) {
+	store := cfg.Packages.MSIX.Store
+	if store.Flight == "" {
+		return nil, nil
+	}
+	for i := range store.Flights {
+		if store.Flights[i].Name == store.Flight {
+			return &store.Flights[i], nil
+		}
+	}
+	return nil, fmt.Errorf("msix.store.flight %q is not in msix.store.flights", store.Flight)
+}
+
+// SubmitToStore submits msixPath to the configured Store product. With a
+// flight selected, the package only reaches that flight's groups; a flight
+// without an ID is created first. It returns the flight ID, if any.
+func SubmitToStore(ctx context.Context, cfg *config.Config, msixPath string) (string, error) {
+	store := cfg.Packages.MSIX.Store
+	if store.ProductID == "" {
+		return "", fmt.Errorf("msix.store.product_id is required")
+	}
+
+	flight, err := SelectedFlight(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"publish", msixPath, "--appId", store.ProductID}
+
+	flightID := ""
+	if flight != nil {
+		flightID = flight.ID
+		if flightID == "" {
+			if flightID, err = createFlight(ctx, store.ProductID, flight); err != nil {
+				return "", err
+			}
+		}
+		args = append(args, "--flightId", flightID)
+	}
+
+	if store.Rollout > 0 && store.Rollout < 100 {
+		args = append(args, "--packageRolloutPercentage", strconv.FormatFloat(store.Rollout, 'f', -1, 64))
+	}
+
+	if _, err := runMSStore(ctx, args...); err != nil {
+		return "", err
+	}
+	return flightID, nil
+}
+
+// createFlight creates a package flight for the flight's groups and
+// returns its ID
+func createFlight(ctx context.Context, productID string, flight *config.MSIXFlightConfig) (string, error) {
+	args := []string{"flights", "create", productID, flight.Name, "--group-ids"}
+	args = append(args, flight.Groups...)
+
+	output, err := runMSStore(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+	id := flightIDPattern.FindString(string(output))
+	if id == "" {
+		return "", fmt.Errorf("msstore did not report the ID of flight %q", flight.Name)
+	}
+	return id, nil
+}
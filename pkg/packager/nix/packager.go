@@ -2,13 +2,13 @@ package nix
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
-	"text/template"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
 )
 
 type Packager struct{}
@@ -23,7 +23,7 @@ func (p *Packager) Name() string {
 
 func (p *Packager) Validate(cfg *config.Config) error {
 	if cfg.Homepage == "" {
-		return fmt.Errorf("homepage is required for Nix package")
+		return errors.NotConfiguredError("homepage is required for Nix package")
 	}
 	return nil
 }
@@ -88,7 +88,7 @@ stdenv.mkDerivation rec {
   };
 }`
 
-	t, err := template.New("default").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "nix/default", tmpl)
 	if err != nil {
 		return err
 	}
@@ -158,7 +158,7 @@ func (p *Packager) createFlake(path string, cfg *config.Config) error {
       });
 }`
 
-	t, err := template.New("flake").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "nix/flake", tmpl)
 	if err != nil {
 		return err
 	}
@@ -186,7 +186,7 @@ pkgs.mkShell {
   '';
 }`
 
-	t, err := template.New("shell").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "nix/shell", tmpl)
 	if err != nil {
 		return err
 	}
@@ -18,6 +18,7 @@ package nix
 
 import (
 	"context"
+	"os"
 	"testing"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
@@ -51,6 +52,10 @@ func TestNixPackager(t *testing.T) {
 }
 
 func TestNixPack(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(t.TempDir())
+
 	p := New()
 	cfg := &config.Config{
 		Name:        "test",
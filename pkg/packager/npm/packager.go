@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/naming"
 )
 
 type Packager struct{}
@@ -22,11 +24,24 @@ func (p *Packager) Name() string {
 
 func (p *Packager) Validate(cfg *config.Config) error {
 	if cfg.Description == "" {
-		return fmt.Errorf("description is required for npm package")
+		return errors.NotConfiguredError("description is required for npm package")
+	}
+	if _, err := naming.NPM(cfg.Name, cfg.Packages.NPM.Scope); cfg.Name != "" && err != nil {
+		return errors.InvalidConfigError("name", err.Error())
 	}
 	return nil
 }
 
+// PackageName returns the npm package name for cfg.Name: lower case, and
+// under packages.npm.scope when set
+func PackageName(cfg *config.Config) string {
+	name, err := naming.NPM(cfg.Name, cfg.Packages.NPM.Scope)
+	if err != nil {
+		return cfg.Name
+	}
+	return name
+}
+
 func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
 	// Find appropriate binary for npm (prefer linux, fallback to others)
 	var binary string
@@ -47,7 +62,7 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 
 	// Create package.json for CLI tool
 	packageJSON := map[string]interface{}{
-		"name":        cfg.Name,
+		"name":        PackageName(cfg),
 		"version":     cfg.Version,
 		"description": cfg.Description,
 		"main":        "index.js",
@@ -58,7 +73,7 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 			"postinstall": "node install.js",
 		},
 		"keywords": []string{"cli", "tool", cfg.Name},
-		"author":   cfg.Author,
+		"author":   cfg.PrimaryAuthor().String(),
 		"license":  cfg.License,
 		"homepage": cfg.Homepage,
 		"repository": map[string]string{
@@ -71,6 +86,10 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 		},
 	}
 
+	if contributors := npmContributors(cfg); len(contributors) > 0 {
+		packageJSON["contributors"] = contributors
+	}
+
 	// Write package.json
 	packagePath := filepath.Join(npmDir, "package.json")
 	f, err := os.Create(packagePath)
@@ -119,3 +138,16 @@ console.log('Installed', binaryName, 'to', binaryPath);
 
 	return npmDir, nil
 }
+
+// npmContributors lists everyone except the primary author as
+// "Name <email>" strings
+func npmContributors(cfg *config.Config) []string {
+	primary := cfg.PrimaryAuthor()
+	var contributors []string
+	for _, person := range cfg.People() {
+		if person != primary {
+			contributors = append(contributors, person.String())
+		}
+	}
+	return contributors
+}
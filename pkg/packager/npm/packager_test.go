@@ -18,6 +18,7 @@ package npm
 
 import (
 	"context"
+	"os"
 	"testing"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
@@ -52,6 +53,10 @@ func TestNpmPackager(t *testing.T) {
 }
 
 func TestNpmPack(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(t.TempDir())
+
 	p := New()
 	cfg := &config.Config{
 		Name:        "test",
@@ -76,3 +81,25 @@ func TestNpmPack(t *testing.T) {
 		t.Error("Expected output path")
 	}
 }
+
+func TestNpmContributors(t *testing.T) {
+	cfg := &config.Config{
+		Authors: []config.AuthorConfig{
+			{Name: "Ada", Email: "ada@example.com"},
+			{Name: "Grace", Role: config.RoleMaintainer},
+			{Name: "Ken", Email: "ken@example.com", Role: config.RoleContributor},
+		},
+	}
+
+	if author := cfg.PrimaryAuthor().String(); author != "Ada <ada@example.com>" {
+		t.Errorf("Expected Ada as author, got %q", author)
+	}
+	contributors := npmContributors(cfg)
+	if len(contributors) != 2 || contributors[0] != "Grace" || contributors[1] != "Ken <ken@example.com>" {
+		t.Errorf("Unexpected contributors: %v", contributors)
+	}
+
+	if contributors := npmContributors(&config.Config{Author: "Test Author"}); len(contributors) != 0 {
+		t.Errorf("Expected no contributors for a single author, got %v", contributors)
+	}
+}
@@ -6,9 +6,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"text/template"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/spdx"
 )
 
 type Packager struct{}
@@ -22,8 +24,8 @@ func (p *Packager) Name() string {
 }
 
 func (p *Packager) Validate(cfg *config.Config) error {
-	if cfg.Author == "" {
-		return fmt.Errorf("author is required for PyPI package")
+	if len(cfg.People()) == 0 {
+		return errors.NotConfiguredError("author is required for PyPI package")
 	}
 	return nil
 }
@@ -89,6 +91,10 @@ setup(
     version="{{.Version}}",
     author="{{.AuthorName}}",
     author_email="{{.AuthorEmail}}",
+{{- if .MaintainerName}}
+    maintainer="{{.MaintainerName}}",
+    maintainer_email="{{.MaintainerEmail}}",
+{{- end}}
     description="{{.Description}}",
     long_description=long_description,
     long_description_content_type="text/markdown",
@@ -97,7 +103,9 @@ setup(
     classifiers=[
         "Development Status :: 4 - Beta",
         "Intended Audience :: Developers",
-        "License :: OSI Approved :: MIT License",
+{{- range .LicenseClassifiers}}
+        "{{.}}",
+{{- end}}
         "Operating System :: OS Independent",
         "Programming Language :: Python :: 3",
         "Programming Language :: Python :: 3.8",
@@ -116,7 +124,7 @@ setup(
     ],
 )`
 
-	t, err := template.New("setup").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "pypi/setup", tmpl)
 	if err != nil {
 		return err
 	}
@@ -127,25 +135,25 @@ setup(
 	}
 	defer f.Close()
 
-	// Parse author name and email
-	authorName := cfg.Author
-	authorEmail := ""
-	if strings.Contains(cfg.Author, "<") && strings.Contains(cfg.Author, ">") {
-		parts := strings.Split(cfg.Author, "<")
-		authorName = strings.TrimSpace(parts[0])
-		authorEmail = strings.Trim(parts[1], ">")
-	}
+	authors := pypiAuthors(cfg)
+	maintainers := cfg.PeopleWithRole(config.RoleMaintainer)
 
 	data := struct {
 		*config.Config
-		AuthorName  string
-		AuthorEmail string
-		PackageName string
+		AuthorName         string
+		AuthorEmail        string
+		MaintainerName     string
+		MaintainerEmail    string
+		PackageName        string
+		LicenseClassifiers []string
 	}{
-		Config:      cfg,
-		AuthorName:  authorName,
-		AuthorEmail: authorEmail,
-		PackageName: strings.ReplaceAll(cfg.Name, "-", "_"),
+		Config:             cfg,
+		AuthorName:         strings.Join(config.AuthorNames(authors), ", "),
+		AuthorEmail:        joinEmails(authors),
+		MaintainerName:     strings.Join(config.AuthorNames(maintainers), ", "),
+		MaintainerEmail:    joinEmails(maintainers),
+		PackageName:        strings.ReplaceAll(cfg.Name, "-", "_"),
+		LicenseClassifiers: licenseClassifiers(cfg),
 	}
 
 	return t.Execute(f, data)
@@ -161,14 +169,25 @@ name = "{{.Name}}"
 version = "{{.Version}}"
 description = "{{.Description}}"
 readme = "README.md"
-license = {text = "{{.License}}"}
+license = {text = "{{.SPDXLicense}}"}
 authors = [
-    {name = "{{.AuthorName}}", email = "{{.AuthorEmail}}"},
+{{- range .Authors}}
+    {name = "{{.Name}}"{{if .Email}}, email = "{{.Email}}"{{end}}},
+{{- end}}
+]
+{{- if .Maintainers}}
+maintainers = [
+{{- range .Maintainers}}
+    {name = "{{.Name}}"{{if .Email}}, email = "{{.Email}}"{{end}}},
+{{- end}}
 ]
+{{- end}}
 classifiers = [
     "Development Status :: 4 - Beta",
     "Intended Audience :: Developers",
-    "License :: OSI Approved :: MIT License",
+{{- range .LicenseClassifiers}}
+    "{{.}}",
+{{- end}}
     "Operating System :: OS Independent",
     "Programming Language :: Python :: 3",
 ]
@@ -184,7 +203,7 @@ Repository = "{{.Homepage}}"
 [project.scripts]
 {{.Name}} = "{{.PackageName}}.main:main"`
 
-	t, err := template.New("pyproject").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "pypi/pyproject", tmpl)
 	if err != nil {
 		return err
 	}
@@ -195,25 +214,20 @@ Repository = "{{.Homepage}}"
 	}
 	defer f.Close()
 
-	// Parse author name and email
-	authorName := cfg.Author
-	authorEmail := ""
-	if strings.Contains(cfg.Author, "<") && strings.Contains(cfg.Author, ">") {
-		parts := strings.Split(cfg.Author, "<")
-		authorName = strings.TrimSpace(parts[0])
-		authorEmail = strings.Trim(parts[1], ">")
-	}
-
 	data := struct {
 		*config.Config
-		AuthorName  string
-		AuthorEmail string
-		PackageName string
+		Authors            []config.AuthorConfig
+		Maintainers        []config.AuthorConfig
+		PackageName        string
+		SPDXLicense        string
+		LicenseClassifiers []string
 	}{
-		Config:      cfg,
-		AuthorName:  authorName,
-		AuthorEmail: authorEmail,
-		PackageName: strings.ReplaceAll(cfg.Name, "-", "_"),
+		Config:             cfg,
+		Authors:            pypiAuthors(cfg),
+		Maintainers:        cfg.PeopleWithRole(config.RoleMaintainer),
+		PackageName:        strings.ReplaceAll(cfg.Name, "-", "_"),
+		SPDXLicense:        spdx.Normalize(cfg.License),
+		LicenseClassifiers: licenseClassifiers(cfg),
 	}
 
 	return t.Execute(f, data)
@@ -305,7 +319,7 @@ def main():
 if __name__ == "__main__":
     main()`
 
-	t, err := template.New("main").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "pypi/main", tmpl)
 	if err != nil {
 		return err
 	}
@@ -326,3 +340,36 @@ if __name__ == "__main__":
 
 	return t.Execute(f, data)
 }
+
+// pypiAuthors returns the people credited as authors: everyone with the
+// author role, plus contributors, since core metadata has no contributor
+// field
+func pypiAuthors(cfg *config.Config) []config.AuthorConfig {
+	var authors []config.AuthorConfig
+	for _, person := range cfg.People() {
+		if person.Role != config.RoleMaintainer {
+			authors = append(authors, person)
+		}
+	}
+	return authors
+}
+
+// licenseClassifiers returns the trove classifiers for the configured
+// license, none when it is not a known SPDX expression
+func licenseClassifiers(cfg *config.Config) []string {
+	expr, err := spdx.Parse(cfg.License)
+	if err != nil {
+		return nil
+	}
+	return expr.Classifiers()
+}
+
+func joinEmails(people []config.AuthorConfig) string {
+	var emails []string
+	for _, person := range people {
+		if person.Email != "" {
+			emails = append(emails, person.Email)
+		}
+	}
+	return strings.Join(emails, ", ")
+}
@@ -18,6 +18,9 @@ package pypi
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
@@ -51,6 +54,10 @@ func TestPypiPackager(t *testing.T) {
 }
 
 func TestPypiPack(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(t.TempDir())
+
 	p := New()
 	cfg := &config.Config{
 		Name:        "test",
@@ -74,3 +81,32 @@ func TestPypiPack(t *testing.T) {
 		t.Error("Expected output path")
 	}
 }
+
+func TestPyprojectAuthors(t *testing.T) {
+	p := New()
+	cfg := &config.Config{
+		Name:    "test",
+		Version: "1.0.0",
+		Authors: []config.AuthorConfig{
+			{Name: "Ada", Email: "ada@example.com"},
+			{Name: "Grace", Email: "grace@example.com", Role: config.RoleMaintainer},
+			{Name: "Ken", Role: config.RoleContributor},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "pyproject.toml")
+	if err := p.createPyprojectToml(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(path)
+
+	for _, expected := range []string{
+		`{name = "Ada", email = "ada@example.com"},`,
+		`{name = "Ken"},`,
+		"maintainers = [\n    {name = \"Grace\", email = \"grace@example.com\"},\n]",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected %q in pyproject.toml:\n%s", expected, content)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package packager
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"sort"
+	"time"
+
+	bagboyerrors "github.com/scttfrdmn/bagboy/pkg/errors"
+)
+
+// Status is the outcome of packaging one format
+type Status string
+
+const (
+	StatusSuccess Status = "success"
+	StatusSkipped Status = "skipped"
+	StatusFailed  Status = "failed"
+)
+
+// SkipReason says why a format was skipped
+type SkipReason string
+
+const (
+	SkipDisabled            SkipReason = "disabled"             // excluded by packages.enabled or packages.disabled
+	SkipNotConfigured       SkipReason = "not configured"       // a setting the format needs is not set
+	SkipUnsupportedPlatform SkipReason = "platform unsupported" // no binary for the platform the format targets
+	SkipToolMissing         SkipReason = "tool missing"         // an external tool the format needs is not installed
+	SkipValidationFailed    SkipReason = "validation failed"    // the format's settings are invalid
+)
+
+// skipReason classifies an error from Validate, or from Pack when it is a
+// missing tool. A command that is not on PATH counts as a missing tool.
+func skipReason(err error) SkipReason {
+	if errors.Is(err, exec.ErrNotFound) {
+		return SkipToolMissing
+	}
+	var bagboyErr *bagboyerrors.BagboyError
+	if !errors.As(err, &bagboyErr) {
+		return SkipValidationFailed
+	}
+	switch {
+	case bagboyErr.Code == bagboyerrors.CodeNotConfigured:
+		return SkipNotConfigured
+	case bagboyErr.Code == bagboyerrors.CodeMissingBinary:
+		return SkipUnsupportedPlatform
+	case bagboyErr.Type == bagboyerrors.ErrorTypeDependency:
+		return SkipToolMissing
+	}
+	return SkipValidationFailed
+}
+
+// Result is the outcome of one format in PackAll. Path is the primary
+// output; Artifacts lists every file the format built, primary first.
+type Result struct {
+	Format    string        `json:"format"`
+	Path      string        `json:"path,omitempty"`
+	Artifacts []Artifact    `json:"artifacts,omitempty"`
+	Duration  time.Duration `json:"-"`
+	Status    Status        `json:"status"`
+	Reason    SkipReason    `json:"reason,omitempty"` // why a skipped format was skipped
+	Error     string        `json:"error,omitempty"`  // the failure, or details of the skip reason
+}
+
+// MarshalJSON reports the duration in seconds
+func (r Result) MarshalJSON() ([]byte, error) {
+	type result Result
+	return json.Marshal(struct {
+		result
+		Seconds float64 `json:"duration_seconds"`
+	}{result(r), r.Duration.Seconds()})
+}
+
+// Results are PackAll results, ordered by format
+type Results []Result
+
+func (r Results) sort() {
+	sort.SliceStable(r, func(i, j int) bool { return r[i].Format < r[j].Format })
+}
+
+// Get returns the output of format when it was packaged
+func (r Results) Get(format string) (string, bool) {
+	for _, result := range r {
+		if result.Format == format && result.Status == StatusSuccess {
+			return result.Path, true
+		}
+	}
+	return "", false
+}
+
+// Paths returns every artifact of every packaged format, in order
+func (r Results) Paths() []string {
+	var paths []string
+	for _, result := range r {
+		if result.Status != StatusSuccess {
+			continue
+		}
+		if len(result.Artifacts) == 0 && result.Path != "" {
+			paths = append(paths, result.Path)
+		}
+		for _, artifact := range result.Artifacts {
+			paths = append(paths, artifact.Path)
+		}
+	}
+	return paths
+}
+
+// Artifacts returns every artifact of every packaged format, in order
+func (r Results) Artifacts() []Artifact {
+	var artifacts []Artifact
+	for _, result := range r {
+		if result.Status == StatusSuccess {
+			artifacts = append(artifacts, result.Artifacts...)
+		}
+	}
+	return artifacts
+}
+
+// Skipped returns how many formats were skipped
+func (r Results) Skipped() int {
+	n := 0
+	for _, result := range r {
+		if result.Status == StatusSkipped {
+			n++
+		}
+	}
+	return n
+}
+
+// Succeeded returns how many formats were packaged
+func (r Results) Succeeded() int {
+	n := 0
+	for _, result := range r {
+		if result.Status == StatusSuccess {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteJSON writes the results to path as a JSON report
+func (r Results) WriteJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
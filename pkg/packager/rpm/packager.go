@@ -7,9 +7,14 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
-	"text/template"
+	"time"
 
+	"github.com/scttfrdmn/bagboy/pkg/changelog"
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+	"github.com/scttfrdmn/bagboy/pkg/spdx"
 )
 
 type Packager struct{}
@@ -24,23 +29,80 @@ func (p *Packager) Name() string {
 
 func (p *Packager) Validate(cfg *config.Config) error {
 	if cfg.Packages.RPM.Vendor == "" {
-		return fmt.Errorf("rpm.vendor is required")
+		return errors.NotConfiguredError("rpm.vendor is required")
 	}
-	return nil
+	return packager.ValidateAlternatives(cfg.Packages.RPM.Alternatives, "/usr/bin/"+cfg.Name)
 }
 
 func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
-	// Find Linux binary
-	var linuxBinary string
-	for arch, path := range cfg.Binaries {
-		if strings.HasPrefix(arch, "linux-") {
-			linuxBinary = path
-			break
+	return packager.Primary(p.PackArtifacts(ctx, cfg))
+}
+
+// PackArtifacts builds one RPM per architecture, x86_64 first when
+// present
+func (p *Packager) PackArtifacts(ctx context.Context, cfg *config.Config) ([]packager.Artifact, error) {
+	binaries := packager.LinuxBinaries(cfg)
+	if len(binaries) == 0 {
+		return nil, fmt.Errorf("no Linux binary found")
+	}
+
+	var artifacts []packager.Artifact
+	for _, binary := range binaries {
+		buildDir, specPath, err := p.prepareBuild(cfg, binary)
+		if err != nil {
+			return nil, err
 		}
+		output, err := p.buildRPM(ctx, buildDir, specPath, cfg, packager.RPMArch(binary.Arch))
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, packager.Artifact{Path: output, OS: "linux", Arch: binary.Arch})
 	}
-	if linuxBinary == "" {
+	return artifacts, nil
+}
+
+// SRPMName returns the file name of the source RPM. The dist tag is left
+// out so the name does not depend on the build host.
+func SRPMName(cfg *config.Config) string {
+	return fmt.Sprintf("%s-%s-1.src.rpm", cfg.Name, cfg.Version)
+}
+
+// BuildSRPM builds a source RPM into dist/ and returns its path. Its
+// sources are the prebuilt binary, so builders such as COPR only need to
+// install it.
+func (p *Packager) BuildSRPM(ctx context.Context, cfg *config.Config) (string, error) {
+	binary, ok := packager.PrimaryLinuxBinary(cfg)
+	if !ok {
 		return "", fmt.Errorf("no Linux binary found")
 	}
+	buildDir, specPath, err := p.prepareBuild(cfg, binary)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := exec.LookPath("rpmbuild"); err != nil {
+		return "", errors.ToolNotFoundError("rpmbuild not found - install rpm-build package")
+	}
+
+	cmd := exec.CommandContext(ctx, "rpmbuild",
+		"--define", "_topdir "+buildDir,
+		"--define", "dist %{nil}",
+		"-bs", specPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("rpmbuild failed: %w\nOutput: %s", err, output)
+	}
+
+	finalPath := filepath.Join("dist", SRPMName(cfg))
+	if err := os.Rename(filepath.Join(buildDir, "SRPMS", SRPMName(cfg)), finalPath); err != nil {
+		return "", fmt.Errorf("failed to move SRPM: %w", err)
+	}
+	return finalPath, nil
+}
+
+// prepareBuild lays out the rpmbuild tree with the binary and spec file
+// and returns the build directory and spec path
+func (p *Packager) prepareBuild(cfg *config.Config, binary packager.PlatformBinary) (string, string, error) {
+	linuxBinary := binary.Path
 
 	// Create RPM build directory structure
 	buildDir := filepath.Join("dist", "rpm-build")
@@ -48,38 +110,87 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 	
 	for _, dir := range dirs {
 		if err := os.MkdirAll(filepath.Join(buildDir, dir), 0755); err != nil {
-			return "", fmt.Errorf("failed to create RPM directory %s: %w", dir, err)
+			return "", "", fmt.Errorf("failed to create RPM directory %s: %w", dir, err)
 		}
 	}
 
 	// Copy binary to SOURCES
 	sourcePath := filepath.Join(buildDir, "SOURCES", cfg.Name)
-	if err := p.copyFile(linuxBinary, sourcePath); err != nil {
-		return "", fmt.Errorf("failed to copy binary: %w", err)
+	if err := paths.CopyExecutable(linuxBinary, sourcePath, packager.CopyOptions(cfg)); err != nil {
+		return "", "", fmt.Errorf("failed to copy binary: %w", err)
+	}
+
+	// Extra files are installed from SOURCES/files, laid out as on disk
+	if err := packager.StageFiles(filepath.Join(buildDir, "SOURCES", "files"), packager.ExtraFiles(cfg, "rpm", "/usr"), packager.CopyOptions(cfg)); err != nil {
+		return "", "", err
+	}
+
+	// SBOM of the binary, installed as documentation
+	sbomName := packager.SBOMName(cfg)
+	if ok, err := packager.WriteSBOM(cfg, linuxBinary, filepath.Join(buildDir, "SOURCES", sbomName)); err != nil {
+		return "", "", fmt.Errorf("failed to write SBOM: %w", err)
+	} else if !ok {
+		sbomName = ""
 	}
 
 	// Generate spec file
 	specPath := filepath.Join(buildDir, "SPECS", cfg.Name+".spec")
-	specContent := p.generateSpec(cfg, linuxBinary)
+	specContent, err := p.generateSpec(cfg, linuxBinary, packager.RPMArch(binary.Arch), sbomName)
+	if err != nil {
+		return "", "", err
+	}
 	if err := os.WriteFile(specPath, []byte(specContent), 0644); err != nil {
-		return "", fmt.Errorf("failed to write spec file: %w", err)
+		return "", "", fmt.Errorf("failed to write spec file: %w", err)
 	}
 
-	// Build RPM
-	return p.buildRPM(ctx, buildDir, specPath, cfg)
+	return buildDir, specPath, nil
+}
+
+// rpmPackager returns the first maintainer for the Packager tag
+func rpmPackager(cfg *config.Config) string {
+	if maintainers := cfg.Maintainers(); len(maintainers) > 0 {
+		return maintainers[0].String()
+	}
+	return ""
+}
+
+// Requires returns a Requires tag value for each bagboy tool cfg needs,
+// one per version comparison, e.g. "helper >= 1.2.0"
+func Requires(cfg *config.Config) []string {
+	var requires []string
+	for _, dep := range cfg.Dependencies.Bagboy {
+		constraint, _ := dep.Constraint()
+		if len(constraint) == 0 {
+			requires = append(requires, dep.Name)
+		}
+		for _, c := range constraint {
+			requires = append(requires, fmt.Sprintf("%s %s %s", dep.Name, c.Op, c.Version))
+		}
+	}
+	return requires
 }
 
-func (p *Packager) generateSpec(cfg *config.Config, binaryPath string) string {
+func (p *Packager) generateSpec(cfg *config.Config, binaryPath, arch, sbomName string) (string, error) {
 	tmpl := `Name:           {{.Name}}
 Version:        {{.Version}}
 Release:        1%{?dist}
 Summary:        {{.Description}}
-License:        {{.License}}
+License:        {{.SPDXLicense}}
 URL:            {{.Homepage}}
 Source0:        %{name}-%{version}.tar.gz
-BuildArch:      x86_64
+BuildArch:      {{.BuildArch}}
 Group:          {{.Group}}
 Vendor:         {{.Vendor}}
+{{- if .Packager}}
+Packager:       {{.Packager}}
+{{- end}}
+{{- range .Requires}}
+Requires:       {{.}}
+{{- end}}
+{{- if .PreunCommands}}
+Requires(post): %{_sbindir}/update-alternatives
+Requires(preun): %{_sbindir}/update-alternatives
+{{- end}}
 
 %description
 {{.Description}}
@@ -94,46 +205,106 @@ Vendor:         {{.Vendor}}
 rm -rf $RPM_BUILD_ROOT
 mkdir -p $RPM_BUILD_ROOT/usr/bin
 cp {{.BinaryName}} $RPM_BUILD_ROOT/usr/bin/{{.Name}}
+{{- if .Files}}
+cp -a %{_sourcedir}/files/. $RPM_BUILD_ROOT/
+{{- end}}
+{{- if .SBOM}}
+mkdir -p $RPM_BUILD_ROOT%{_docdir}/{{.Name}}
+cp %{_sourcedir}/{{.SBOM}} $RPM_BUILD_ROOT%{_docdir}/{{.Name}}/{{.SBOM}}
+{{- end}}
 
+{{if .PostCommands}}
+%post
+{{range .PostCommands}}{{.}}
+{{end}}{{end}}{{if .PreunCommands}}
+%preun
+if [ $1 -eq 0 ]; then
+{{- range .PreunCommands}}
+    {{.}}
+{{- end}}
+fi
+{{end}}
 %files
 /usr/bin/{{.Name}}
+{{- range .Files}}
+{{if hasPrefix .Dst "/etc/"}}%config(noreplace) {{end}}{{.Dst}}
+{{- end}}
+{{- if .SBOM}}
+%{_docdir}/{{.Name}}/{{.SBOM}}
+{{- end}}
 
 %changelog
-* $(date "+%a %b %d %Y") {{.Vendor}} - {{.Version}}-1
-- Initial package`
+* {{.ChangelogDate}} {{.Vendor}} - {{.Version}}-1
+{{- range .Changes}}
+- {{.}}
+{{- end}}`
 
-	t, _ := template.New("spec").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "rpm/spec", tmpl)
+	if err != nil {
+		return "", err
+	}
 
 	data := struct {
 		*config.Config
-		Group      string
-		Vendor     string
-		BinaryName string
+		Group         string
+		Vendor        string
+		Packager      string
+		SPDXLicense   string
+		BuildArch     string
+		BinaryName    string
+		SBOM          string
+		Requires      []string
+		Files         []packager.File
+		PostCommands  []string
+		PreunCommands []string
+		ChangelogDate string
+		Changes       []string
 	}{
-		Config:     cfg,
-		Group:      cfg.Packages.RPM.Group,
-		Vendor:     cfg.Packages.RPM.Vendor,
-		BinaryName: filepath.Base(binaryPath),
+		Config:        cfg,
+		Group:         cfg.Packages.RPM.Group,
+		Vendor:        cfg.Packages.RPM.Vendor,
+		Packager:      rpmPackager(cfg),
+		SPDXLicense:   spdx.Normalize(cfg.License),
+		BuildArch:     arch,
+		BinaryName:    filepath.Base(binaryPath),
+		SBOM:          sbomName,
+		Requires:      Requires(cfg),
+		Files:         packager.ExtraFiles(cfg, "rpm", "/usr"),
+		PostCommands:  append(packager.AlternativeInstallCommands(cfg.Packages.RPM.Alternatives, "/usr/bin/"+cfg.Name), packager.ShellEcho(cfg.PostInstall.MessageFor("rpm"))...),
+		PreunCommands: packager.AlternativeRemoveCommands(cfg.Packages.RPM.Alternatives, "/usr/bin/"+cfg.Name),
 	}
 
 	if data.Group == "" {
 		data.Group = "Applications/System"
 	}
 
+	// The %changelog entry comes from CHANGELOG.md when it lists this version
+	data.ChangelogDate = time.Now().UTC().Format("Mon Jan 02 2006")
+	data.Changes = []string{"Release " + cfg.Version}
+	if release, ok := changelog.Find(cfg, cfg.Version); ok && !release.Empty() {
+		data.Changes = release.Items()
+		if !release.Date.IsZero() {
+			data.ChangelogDate = release.Date.Format("Mon Jan 02 2006")
+		}
+	}
+
 	var result strings.Builder
-	t.Execute(&result, data)
-	return result.String()
+	if err := t.Execute(&result, data); err != nil {
+		return "", err
+	}
+	return result.String(), nil
 }
 
-func (p *Packager) buildRPM(ctx context.Context, buildDir, specPath string, cfg *config.Config) (string, error) {
+func (p *Packager) buildRPM(ctx context.Context, buildDir, specPath string, cfg *config.Config, arch string) (string, error) {
 	// Check if rpmbuild is available
 	if _, err := exec.LookPath("rpmbuild"); err != nil {
-		return "", fmt.Errorf("rpmbuild not found - install rpm-build package")
+		return "", errors.ToolNotFoundError("rpmbuild not found - install rpm-build package")
 	}
 
-	// Build RPM
+	// Build RPM, targeting the binary's architecture rather than the host's
 	cmd := exec.CommandContext(ctx, "rpmbuild",
 		"--define", "_topdir "+buildDir,
+		"--target", arch,
 		"-bb", specPath)
 
 	if output, err := cmd.CombinedOutput(); err != nil {
@@ -141,7 +312,7 @@ func (p *Packager) buildRPM(ctx context.Context, buildDir, specPath string, cfg
 	}
 
 	// Find generated RPM
-	rpmPattern := filepath.Join(buildDir, "RPMS", "x86_64", fmt.Sprintf("%s-%s-*.rpm", cfg.Name, cfg.Version))
+	rpmPattern := filepath.Join(buildDir, "RPMS", arch, fmt.Sprintf("%s-%s-*.rpm", cfg.Name, cfg.Version))
 	matches, err := filepath.Glob(rpmPattern)
 	if err != nil || len(matches) == 0 {
 		return "", fmt.Errorf("RPM file not found after build")
@@ -155,11 +326,3 @@ func (p *Packager) buildRPM(ctx context.Context, buildDir, specPath string, cfg
 
 	return finalPath, nil
 }
-
-func (p *Packager) copyFile(src, dst string) error {
-	data, err := os.ReadFile(src)
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(dst, data, 0755)
-}
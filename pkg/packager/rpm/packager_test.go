@@ -4,9 +4,11 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
 )
 
 func TestRPMPackager(t *testing.T) {
@@ -131,7 +133,10 @@ func TestGenerateSpec(t *testing.T) {
 		},
 	}
 
-	spec := packager.generateSpec(cfg, "/path/to/binary")
+	spec, err := packager.generateSpec(cfg, "/path/to/binary", "x86_64", "")
+	if err != nil {
+		t.Fatalf("generateSpec failed: %v", err)
+	}
 	
 	// Check that spec contains required fields
 	requiredFields := []string{
@@ -149,11 +154,21 @@ func TestGenerateSpec(t *testing.T) {
 			t.Errorf("Spec file missing required field: %s", field)
 		}
 	}
+
+	// Other bagboy tools become versioned requirements
+	cfg.Dependencies.Bagboy = []config.ToolDependency{{Name: "helper", Version: ">=1.2, <2"}}
+	spec, err = packager.generateSpec(cfg, "/path/to/binary", "x86_64", "")
+	if err != nil {
+		t.Fatalf("generateSpec failed: %v", err)
+	}
+	for _, field := range []string{"Requires:       helper >= 1.2.0\n", "Requires:       helper < 2.0.0\n"} {
+		if !contains(spec, field) {
+			t.Errorf("Spec file missing %q:\n%s", field, spec)
+		}
+	}
 }
 
 func TestCopyFile(t *testing.T) {
-	packager := New()
-	
 	tmpDir := t.TempDir()
 	srcPath := filepath.Join(tmpDir, "source")
 	dstPath := filepath.Join(tmpDir, "dest")
@@ -163,8 +178,8 @@ func TestCopyFile(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := packager.copyFile(srcPath, dstPath); err != nil {
-		t.Errorf("copyFile() error = %v", err)
+	if err := paths.CopyExecutable(srcPath, dstPath, paths.CopyOptions{}); err != nil {
+		t.Errorf("CopyExecutable() error = %v", err)
 	}
 
 	// Check destination file
@@ -189,12 +204,10 @@ func TestCopyFile(t *testing.T) {
 }
 
 func TestCopyFile_Error(t *testing.T) {
-	packager := New()
-	
 	// Test with non-existent source file
-	err := packager.copyFile("/non/existent/file", "/tmp/dest")
+	err := paths.CopyExecutable("/non/existent/file", "/tmp/dest", paths.CopyOptions{})
 	if err == nil {
-		t.Error("copyFile() should fail with non-existent source file")
+		t.Error("CopyExecutable() should fail with non-existent source file")
 	}
 }
 
@@ -212,7 +225,7 @@ func TestBuildRPM_NoRpmbuild(t *testing.T) {
 	os.WriteFile(specPath, []byte("Name: testapp\nVersion: 1.0.0\n"), 0644)
 
 	ctx := context.Background()
-	_, err := packager.buildRPM(ctx, tmpDir, specPath, cfg)
+	_, err := packager.buildRPM(ctx, tmpDir, specPath, cfg, "x86_64")
 	
 	// Should return error about missing rpmbuild
 	if err == nil {
@@ -242,7 +255,10 @@ func TestGenerateSpec_WithDefaults(t *testing.T) {
 		},
 	}
 
-	spec := packager.generateSpec(cfg, "/path/to/binary")
+	spec, err := packager.generateSpec(cfg, "/path/to/binary", "x86_64", "")
+	if err != nil {
+		t.Fatalf("generateSpec failed: %v", err)
+	}
 	
 	// Check default group is used
 	if !contains(spec, "Group:          Applications/System") {
@@ -269,7 +285,10 @@ func TestGenerateSpec_EmptyFields(t *testing.T) {
 		},
 	}
 
-	spec := packager.generateSpec(cfg, "/path/to/binary")
+	spec, err := packager.generateSpec(cfg, "/path/to/binary", "x86_64", "")
+	if err != nil {
+		t.Fatalf("generateSpec failed: %v", err)
+	}
 	
 	// Should handle empty fields gracefully
 	if !contains(spec, "Name:           testapp") {
@@ -282,6 +301,10 @@ func TestGenerateSpec_EmptyFields(t *testing.T) {
 }
 
 func TestRPMPack_MissingBinary(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(t.TempDir())
+
 	packager := New()
 	
 	cfg := &config.Config{
@@ -319,7 +342,10 @@ func TestGenerateSpec_BinaryName(t *testing.T) {
 		},
 	}
 
-	spec := packager.generateSpec(cfg, "/path/to/my-binary")
+	spec, err := packager.generateSpec(cfg, "/path/to/my-binary", "x86_64", "")
+	if err != nil {
+		t.Fatalf("generateSpec failed: %v", err)
+	}
 	
 	// Check binary name is extracted correctly
 	if !contains(spec, "cp my-binary $RPM_BUILD_ROOT/usr/bin/testapp") {
@@ -421,3 +447,129 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestGenerateSpec_Alternatives(t *testing.T) {
+	p := New()
+	cfg := &config.Config{
+		Name:    "myapp",
+		Version: "1.0.0",
+		Packages: config.PackagesConfig{
+			RPM: config.RPMConfig{
+				Vendor:       "Test",
+				Alternatives: []config.AlternativeConfig{{Link: "/usr/bin/tool", Priority: 50}},
+			},
+		},
+	}
+
+	spec, err := p.generateSpec(cfg, "myapp-linux-amd64", "x86_64", "")
+	if err != nil {
+		t.Fatalf("generateSpec failed: %v", err)
+	}
+	for _, expected := range []string{
+		"Requires(post): %{_sbindir}/update-alternatives",
+		"%post\nupdate-alternatives --install /usr/bin/tool tool /usr/bin/myapp 50\n",
+		"%preun\nif [ $1 -eq 0 ]; then\n    update-alternatives --remove tool /usr/bin/myapp\nfi",
+	} {
+		if !strings.Contains(spec, expected) {
+			t.Errorf("Spec missing %q:\n%s", expected, spec)
+		}
+	}
+
+	cfg.Packages.RPM.Alternatives = nil
+	if spec, _ := p.generateSpec(cfg, "myapp-linux-amd64", "x86_64", ""); strings.Contains(spec, "%post") {
+		t.Error("Spec should not have a post scriptlet without alternatives")
+	}
+}
+
+func TestGenerateSpec_Files(t *testing.T) {
+	p := New()
+	cfg := &config.Config{
+		Name:    "myapp",
+		Version: "1.0.0",
+		Files: []config.FileConfig{
+			{Src: "examples"},
+			{Src: "default.yaml", Dst: "/etc/myapp/config.yaml"},
+		},
+	}
+
+	spec, err := p.generateSpec(cfg, "myapp-linux-amd64", "x86_64", "")
+	if err != nil {
+		t.Fatalf("generateSpec failed: %v", err)
+	}
+	for _, expected := range []string{
+		"cp -a %{_sourcedir}/files/. $RPM_BUILD_ROOT/",
+		"/usr/bin/myapp\n/usr/share/myapp/examples\n%config(noreplace) /etc/myapp/config.yaml\n",
+	} {
+		if !strings.Contains(spec, expected) {
+			t.Errorf("Spec missing %q:\n%s", expected, spec)
+		}
+	}
+
+	cfg.Files = nil
+	if spec, _ := p.generateSpec(cfg, "myapp-linux-amd64", "x86_64", ""); strings.Contains(spec, "_sourcedir}/files") {
+		t.Error("Spec should not copy files when none are configured")
+	}
+}
+
+func TestGenerateSpec_SBOM(t *testing.T) {
+	p := New()
+	cfg := &config.Config{Name: "myapp", Version: "1.0.0"}
+
+	spec, err := p.generateSpec(cfg, "myapp-linux-amd64", "x86_64", "myapp.spdx.json")
+	if err != nil {
+		t.Fatalf("generateSpec failed: %v", err)
+	}
+	for _, expected := range []string{
+		"cp %{_sourcedir}/myapp.spdx.json $RPM_BUILD_ROOT%{_docdir}/myapp/myapp.spdx.json",
+		"%files\n/usr/bin/myapp\n%{_docdir}/myapp/myapp.spdx.json\n",
+	} {
+		if !strings.Contains(spec, expected) {
+			t.Errorf("Spec missing %q:\n%s", expected, spec)
+		}
+	}
+}
+
+func TestGenerateSpec_PostInstallMessage(t *testing.T) {
+	p := New()
+	cfg := &config.Config{
+		Name:        "myapp",
+		Version:     "1.0.0",
+		PostInstall: config.PostInstallConfig{Message: "Run `myapp init` to get started."},
+	}
+
+	spec, err := p.generateSpec(cfg, "myapp-linux-amd64", "x86_64", "")
+	if err != nil {
+		t.Fatalf("generateSpec failed: %v", err)
+	}
+	if !strings.Contains(spec, "%post\necho 'Run `myapp init` to get started.'\n") {
+		t.Errorf("Spec missing post-install message:\n%s", spec)
+	}
+	if strings.Contains(spec, "%preun") || strings.Contains(spec, "Requires(post)") {
+		t.Errorf("Spec should not remove alternatives without any:\n%s", spec)
+	}
+}
+
+func TestGenerateSpec_Changelog(t *testing.T) {
+	p := New()
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	os.WriteFile(path, []byte("## [Unreleased]\n\n## [1.0.0] - 2026-03-02\n\n### Added\n\n- Shell completions\n\n### Fixed\n\n- Crash on start\n"), 0644)
+	cfg := &config.Config{
+		Name:      "myapp",
+		Version:   "1.0.0",
+		Changelog: path,
+		Packages:  config.PackagesConfig{RPM: config.RPMConfig{Vendor: "Acme"}},
+	}
+
+	spec, err := p.generateSpec(cfg, "myapp-linux-amd64", "x86_64", "")
+	if err != nil {
+		t.Fatalf("generateSpec failed: %v", err)
+	}
+	if !strings.Contains(spec, "%changelog\n* Mon Mar 02 2026 Acme - 1.0.0-1\n- Shell completions\n- Crash on start") {
+		t.Errorf("Spec missing changelog entry:\n%s", spec)
+	}
+
+	cfg.Version = "1.1.0"
+	if spec, _ := p.generateSpec(cfg, "myapp-linux-amd64", "x86_64", ""); !strings.Contains(spec, "Acme - 1.1.0-1\n- Release 1.1.0") {
+		t.Errorf("Spec should fall back without a changelog entry:\n%s", spec)
+	}
+}
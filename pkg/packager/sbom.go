@@ -0,0 +1,34 @@
+package packager
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/sbom"
+)
+
+// SBOMName is the file name of the SPDX SBOM embedded in packages
+func SBOMName(cfg *config.Config) string {
+	return cfg.Name + sbom.Extensions[sbom.SPDX]
+}
+
+// WriteSBOM writes the SPDX SBOM of binary to path when sbom.enabled is
+// set, and reports whether it did
+func WriteSBOM(cfg *config.Config, binary, path string) (bool, error) {
+	if !cfg.SBOM.Enabled {
+		return false, nil
+	}
+	doc, err := sbom.ForBinary(cfg, binary)
+	if err != nil {
+		return false, err
+	}
+	data, err := doc.SPDX()
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, data, 0644)
+}
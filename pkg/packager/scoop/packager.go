@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
 )
 
 type Packager struct{}
@@ -22,22 +25,39 @@ func (p *Packager) Name() string {
 
 func (p *Packager) Validate(cfg *config.Config) error {
 	if cfg.Homepage == "" {
-		return fmt.Errorf("homepage is required for scoop manifest")
+		return errors.NotConfiguredError("homepage is required for scoop manifest")
+	}
+	if _, ok := cfg.Binaries["windows-amd64"]; !ok {
+		return errors.NoPlatformBinaryError("no windows-amd64 binary specified for scoop manifest")
 	}
 	return nil
 }
 
 func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
+	hash, err := packager.BinarySHA256(cfg, "windows-amd64")
+	if err != nil {
+		return "", err
+	}
+
 	manifest := map[string]interface{}{
 		"version":     cfg.Version,
 		"description": cfg.Description,
 		"homepage":    cfg.Homepage,
 		"license":     cfg.License,
-		"url":         fmt.Sprintf("%s/%s-windows-amd64.exe", cfg.Installer.BaseURL, cfg.Name),
-		"hash":        "sha256:TODO", // Would need actual hash
+		"url":         fmt.Sprintf("%s/%s-windows-amd64.exe", baseURL(cfg), cfg.Name),
+		"hash":        "sha256:" + hash,
 		"bin":         cfg.Name + ".exe",
 	}
 
+	// Scoop ignores the ## key, which manifests use for comments
+	if cfg.Packages.Scoop.ChecksumComments {
+		asset := cfg.Name + "-windows-amd64.exe"
+		manifest["##"] = []string{
+			"hash is the SHA-256 of " + asset + ", as listed in checksums.txt on the release.",
+			"Verify a download with: Get-FileHash " + asset + " -Algorithm SHA256",
+		}
+	}
+
 	if cfg.Packages.Scoop.Bin != "" {
 		manifest["bin"] = cfg.Packages.Scoop.Bin
 	}
@@ -46,6 +66,10 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 		manifest["shortcuts"] = cfg.Packages.Scoop.Shortcuts
 	}
 
+	if message := cfg.PostInstall.MessageFor("scoop"); message != "" {
+		manifest["notes"] = strings.Split(message, "\n")
+	}
+
 	outputPath := filepath.Join("dist", cfg.Name+".json")
 	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 		return "", err
@@ -65,3 +89,12 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 
 	return outputPath, nil
 }
+
+// baseURL is where the manifest downloads the binary: scoop.base_url, or
+// else installer.base_url
+func baseURL(cfg *config.Config) string {
+	if cfg.Packages.Scoop.BaseURL != "" {
+		return strings.TrimSuffix(cfg.Packages.Scoop.BaseURL, "/")
+	}
+	return cfg.Installer.BaseURL
+}
@@ -18,6 +18,12 @@ package scoop
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
@@ -35,6 +41,7 @@ func TestScoopPackager(t *testing.T) {
 		Name:     "test",
 		Version:  "1.0.0",
 		Homepage: "https://example.com",
+		Binaries: map[string]string{"windows-amd64": "test.exe"},
 		Installer: config.InstallerConfig{
 			BaseURL: "https://example.com/releases",
 		},
@@ -45,6 +52,12 @@ func TestScoopPackager(t *testing.T) {
 		t.Errorf("Validation failed: %v", err)
 	}
 
+	// Test validation failure without a Windows binary
+	cfg.Binaries = map[string]string{"linux-amd64": "test"}
+	if err := p.Validate(cfg); err == nil {
+		t.Error("Expected validation to fail without a windows-amd64 binary")
+	}
+
 	// Test validation failure
 	cfg.Homepage = ""
 	err = p.Validate(cfg)
@@ -54,13 +67,20 @@ func TestScoopPackager(t *testing.T) {
 }
 
 func TestScoopPack(t *testing.T) {
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(t.TempDir())
+
 	p := New()
+	binary := filepath.Join(t.TempDir(), "test.exe")
+	os.WriteFile(binary, []byte("fake binary"), 0755)
 	cfg := &config.Config{
 		Name:        "test",
 		Version:     "1.0.0",
 		Description: "Test app",
 		Homepage:    "https://example.com",
 		License:     "Apache-2.0",
+		Binaries:    map[string]string{"windows-amd64": binary},
 		Installer: config.InstallerConfig{
 			BaseURL: "https://example.com/releases",
 		},
@@ -81,4 +101,35 @@ func TestScoopPack(t *testing.T) {
 	if output == "" {
 		t.Error("Expected output path")
 	}
+
+	// The manifest carries the binary's real checksum
+	var manifest map[string]interface{}
+	content, _ := os.ReadFile(output)
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		t.Fatalf("Invalid manifest: %v", err)
+	}
+	if want := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("fake binary"))); manifest["hash"] != want {
+		t.Errorf("hash = %v, want %s", manifest["hash"], want)
+	}
+	if _, ok := manifest["##"]; ok {
+		t.Error("Comments should be left out by default")
+	}
+
+	// Another asset host, and comments on verifying the hash
+	cfg.Packages.Scoop.BaseURL = "https://cdn.example.com/test/"
+	cfg.Packages.Scoop.ChecksumComments = true
+	if _, err := p.Pack(ctx, cfg); err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	content, _ = os.ReadFile(output)
+	manifest = nil
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		t.Fatalf("Invalid manifest: %v", err)
+	}
+	if manifest["url"] != "https://cdn.example.com/test/test-windows-amd64.exe" {
+		t.Errorf("url = %v", manifest["url"])
+	}
+	if comments, _ := manifest["##"].([]interface{}); len(comments) != 2 || !strings.Contains(comments[1].(string), "Get-FileHash test-windows-amd64.exe") {
+		t.Errorf("Unexpected ## comments %v", manifest["##"])
+	}
 }
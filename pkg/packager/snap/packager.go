@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
-	"text/template"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/naming"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/requirements"
 )
 
 type Packager struct{}
@@ -23,23 +25,21 @@ func (p *Packager) Name() string {
 
 func (p *Packager) Validate(cfg *config.Config) error {
 	if cfg.Description == "" {
-		return fmt.Errorf("description is required for snap package")
+		return errors.NotConfiguredError("description is required for snap package")
+	}
+	if _, err := naming.Snap(cfg.Name); cfg.Name != "" && err != nil {
+		return errors.InvalidConfigError("name", err.Error())
 	}
 	return nil
 }
 
 func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
 	// Find Linux binary
-	var linuxBinary string
-	for arch, path := range cfg.Binaries {
-		if strings.HasPrefix(arch, "linux-") {
-			linuxBinary = path
-			break
-		}
-	}
-	if linuxBinary == "" {
+	binary, ok := packager.PrimaryLinuxBinary(cfg)
+	if !ok {
 		return "", fmt.Errorf("no Linux binary found")
 	}
+	linuxBinary := binary.Path
 
 	snapDir := filepath.Join("dist", "snap")
 	if err := os.MkdirAll(snapDir, 0755); err != nil {
@@ -48,15 +48,27 @@ func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error)
 
 	// Create snapcraft.yaml
 	snapcraftPath := filepath.Join(snapDir, "snapcraft.yaml")
-	if err := p.createSnapcraft(snapcraftPath, cfg, linuxBinary); err != nil {
+	// Use the newest base the installed snapcraft can build
+	if err := p.createSnapcraft(snapcraftPath, cfg, linuxBinary, requirements.SnapBase(ctx)); err != nil {
 		return "", err
 	}
 
 	return snapDir, nil
 }
 
-func (p *Packager) createSnapcraft(path string, cfg *config.Config, binaryPath string) error {
-	tmpl := `name: {{.Name}}
+// SnapName returns the Snap Store name for cfg.Name, lower case with
+// hyphens for anything else. Names that cannot be fixed are left for
+// bagboy validate to report.
+func SnapName(cfg *config.Config) string {
+	name, err := naming.Snap(cfg.Name)
+	if err != nil {
+		return cfg.Name
+	}
+	return name
+}
+
+func (p *Packager) createSnapcraft(path string, cfg *config.Config, binaryPath, base string) error {
+	tmpl := `name: {{.SnapName}}
 version: '{{.Version}}'
 summary: {{.Description}}
 description: |
@@ -66,7 +78,7 @@ description: |
 
 grade: stable
 confinement: strict
-base: core22
+base: {{.Base}}
 
 apps:
   {{.Name}}:
@@ -87,7 +99,7 @@ parts:
     prime:
       - bin/{{.Name}}`
 
-	t, err := template.New("snapcraft").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "snap/snapcraft", tmpl)
 	if err != nil {
 		return err
 	}
@@ -100,10 +112,14 @@ parts:
 
 	data := struct {
 		*config.Config
+		SnapName   string
 		BinaryName string
+		Base       string
 	}{
 		Config:     cfg,
+		SnapName:   SnapName(cfg),
 		BinaryName: filepath.Base(binaryPath),
+		Base:       base,
 	}
 
 	return t.Execute(f, data)
@@ -6,9 +6,10 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"text/template"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
 )
 
 type Packager struct{}
@@ -23,7 +24,7 @@ func (p *Packager) Name() string {
 
 func (p *Packager) Validate(cfg *config.Config) error {
 	if cfg.Homepage == "" {
-		return fmt.Errorf("homepage is required for Spack package")
+		return errors.NotConfiguredError("homepage is required for Spack package")
 	}
 	return nil
 }
@@ -87,7 +88,7 @@ class {{.ClassName}}(Package):
         {{.Name}} = Executable(join_path(self.prefix.bin, "{{.Name}}"))
         {{.Name}}("--version", output=str.split, error=str.split)`
 
-	t, err := template.New("package").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "spack/package", tmpl)
 	if err != nil {
 		return err
 	}
@@ -107,11 +108,9 @@ class {{.ClassName}}(Package):
 		}
 	}
 
-	// Extract maintainer from author
-	maintainer := cfg.Author
-	if strings.Contains(cfg.Author, "<") {
-		parts := strings.Split(cfg.Author, "<")
-		maintainer = strings.TrimSpace(parts[0])
+	var maintainer string
+	if maintainers := cfg.Maintainers(); len(maintainers) > 0 {
+		maintainer = maintainers[0].Name
 	}
 
 	data := struct {
@@ -200,7 +199,7 @@ spack load {{.Name}}
 - Consider adding variants for different build options
 - Test installation on your target HPC system`
 
-	t, err := template.New("instructions").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "spack/instructions", tmpl)
 	if err != nil {
 		return err
 	}
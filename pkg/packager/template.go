@@ -0,0 +1,189 @@
+package packager
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/semver"
+)
+
+// ParseTemplate parses a built-in template with the TemplateFuncs
+// functions and any extra funcs. name is <format>/<template>; when
+// cfg.Templates has a file for name, that file is parsed instead and is
+// executed with the same data.
+func ParseTemplate(cfg *config.Config, name, text string, funcs ...template.FuncMap) (*template.Template, error) {
+	if path := cfg.Templates[name]; path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", name, err)
+		}
+		text = string(data)
+	}
+
+	t := template.New(name).Funcs(TemplateFuncs(cfg))
+	for _, f := range funcs {
+		t = t.Funcs(f)
+	}
+	t, err := t.Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", name, err)
+	}
+	return t, nil
+}
+
+// TemplateFuncs returns the functions available in every manifest
+// template
+func TemplateFuncs(cfg *config.Config) template.FuncMap {
+	version := func() (semver.Version, error) {
+		return semver.Parse(cfg.Version)
+	}
+
+	return template.FuncMap{
+		// Versions
+		"semverMajor": func() (int, error) {
+			v, err := version()
+			return v.Major, err
+		},
+		"semverMinor": func() (int, error) {
+			v, err := version()
+			return v.Minor, err
+		},
+		"semverPatch": func() (int, error) {
+			v, err := version()
+			return v.Patch, err
+		},
+		"semverPrerelease": func() (string, error) {
+			v, err := version()
+			return v.Prerelease, err
+		},
+
+		// Release assets
+		"shaOf": func(platform string) (string, error) {
+			return BinarySHA256(cfg, platform)
+		},
+		"assetName": func(platform string) string {
+			return AssetName(cfg, platform)
+		},
+		"assetURL": func(platform string) (string, error) {
+			return AssetURL(cfg, platform)
+		},
+
+		// Strings
+		"toTitle":   toTitle,
+		"toUpper":   strings.ToUpper,
+		"toLower":   strings.ToLower,
+		"trim":      strings.TrimSpace,
+		"replace":   func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"join":      func(sep string, elems []string) string { return strings.Join(elems, sep) },
+		"split":     func(sep, s string) []string { return strings.Split(s, sep) },
+		"contains":  func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix": strings.HasPrefix,
+		"hasSuffix": strings.HasSuffix,
+		"indent":    Indent,
+		"nindent":   func(spaces int, s string) string { return "\n" + Indent(spaces, s) },
+		"default": func(fallback, value interface{}) interface{} {
+			if value == nil || value == "" {
+				return fallback
+			}
+			return value
+		},
+
+		// Escaping
+		"toJSON": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"xml": func(s string) string {
+			var b bytes.Buffer
+			xml.EscapeText(&b, []byte(s))
+			return b.String()
+		},
+	}
+}
+
+// AssetName is the release asset name of the binary for platform, such as
+// myapp-linux-amd64 or myapp-windows-amd64.exe
+func AssetName(cfg *config.Config, platform string) string {
+	name := cfg.Name + "-" + platform
+	if strings.HasPrefix(platform, "windows-") {
+		name += ".exe"
+	}
+	return name
+}
+
+// AssetURL returns the download URL of the binary for platform, under
+// installer.base_url or else the GitHub release
+func AssetURL(cfg *config.Config, platform string) (string, error) {
+	if _, ok := cfg.Binaries[platform]; !ok {
+		return "", fmt.Errorf("no binary for %s", platform)
+	}
+	return ReleaseURL(cfg, AssetName(cfg, platform))
+}
+
+// ReleaseURL returns the download URL of the release asset named file,
+// under installer.base_url or else the GitHub or GitLab release
+func ReleaseURL(cfg *config.Config, file string) (string, error) {
+	base := strings.TrimSuffix(cfg.Installer.BaseURL, "/")
+	switch {
+	case base != "":
+	case cfg.GitHub.Owner != "" && cfg.GitHub.Repo != "":
+		base = fmt.Sprintf("https://github.com/%s/%s/releases/download/%s", cfg.GitHub.Owner, cfg.GitHub.Repo, cfg.Tag())
+	case cfg.GitLab.Release.Enabled:
+		base = cfg.GitLab.DownloadURL(cfg.Tag())
+	default:
+		return "", fmt.Errorf("assetURL needs installer.base_url, github.owner and github.repo, or gitlab.release")
+	}
+	return base + "/" + file, nil
+}
+
+// BinarySHA256 returns the hex SHA-256 of the binary for platform
+func BinarySHA256(cfg *config.Config, platform string) (string, error) {
+	path, ok := cfg.Binaries[platform]
+	if !ok {
+		return "", fmt.Errorf("no binary for %s", platform)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash %s binary: %w", platform, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Indent prefixes every non-empty line of s with spaces
+func Indent(spaces int, s string) string {
+	prefix := strings.Repeat(" ", spaces)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// toTitle upper-cases the first letter of each word
+func toTitle(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		r, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(r)) + word[size:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,83 @@
+package packager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "myapp")
+	os.WriteFile(binary, []byte("hello"), 0755)
+
+	cfg := &config.Config{
+		Name:     "myapp",
+		Version:  "2.3.4-rc.1",
+		Binaries: map[string]string{"linux-amd64": binary, "windows-amd64": binary},
+		GitHub:   config.GitHubConfig{Owner: "acme", Repo: "myapp"},
+	}
+
+	tests := map[string]string{
+		`{{semverMajor}}.{{semverMinor}}.{{semverPatch}} {{semverPrerelease}}`: "2.3.4 rc.1",
+		`{{shaOf "linux-amd64"}}`:      "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		`{{assetURL "windows-amd64"}}`: "https://github.com/acme/myapp/releases/download/v2.3.4-rc.1/myapp-windows-amd64.exe",
+		`{{toTitle "my cool app"}}`:    "My Cool App",
+		`{{"a\nb" | indent 2}}`:        "  a\n  b",
+		`{{"" | default "none"}}`:      "none",
+		`{{.Name | toUpper}}`:          "MYAPP",
+		`{{xml "a<b"}}`:                "a&lt;b",
+	}
+	for text, want := range tests {
+		tmpl, err := ParseTemplate(cfg, "test/funcs", text)
+		if err != nil {
+			t.Fatalf("ParseTemplate(%q) failed: %v", text, err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, cfg); err != nil {
+			t.Fatalf("Execute(%q) failed: %v", text, err)
+		}
+		if b.String() != want {
+			t.Errorf("%s = %q, want %q", text, b.String(), want)
+		}
+	}
+
+	tmpl, _ := ParseTemplate(cfg, "test/funcs", `{{shaOf "darwin-arm64"}}`)
+	if err := tmpl.Execute(&strings.Builder{}, cfg); err == nil {
+		t.Error("Expected shaOf to fail for a platform without a binary")
+	}
+
+	cfg.Installer.BaseURL = "https://example.com/dl/"
+	if url, _ := AssetURL(cfg, "linux-amd64"); url != "https://example.com/dl/myapp-linux-amd64" {
+		t.Errorf("AssetURL with base_url = %q", url)
+	}
+}
+
+func TestParseTemplateOverride(t *testing.T) {
+	override := filepath.Join(t.TempDir(), "formula.rb")
+	os.WriteFile(override, []byte(`custom {{.Name}} {{semverMajor}}`), 0644)
+
+	cfg := &config.Config{
+		Name:      "myapp",
+		Version:   "1.0.0",
+		Templates: map[string]string{"brew/formula": override},
+	}
+
+	tmpl, err := ParseTemplate(cfg, "brew/formula", "built-in")
+	if err != nil {
+		t.Fatalf("ParseTemplate failed: %v", err)
+	}
+	var b strings.Builder
+	tmpl.Execute(&b, cfg)
+	if b.String() != "custom myapp 1" {
+		t.Errorf("override rendered %q", b.String())
+	}
+
+	cfg.Templates["brew/formula"] = filepath.Join(t.TempDir(), "missing")
+	if _, err := ParseTemplate(cfg, "brew/formula", "built-in"); err == nil {
+		t.Error("Expected error for a missing override")
+	}
+}
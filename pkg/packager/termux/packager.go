@@ -0,0 +1,415 @@
+package termux
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/blakesmith/ar"
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+)
+
+// Prefix is where Termux installs packages on the device
+const Prefix = "/data/data/com.termux/files/usr"
+
+type Packager struct {
+	outputs map[string]string
+}
+
+func New() *Packager {
+	return &Packager{}
+}
+
+func (p *Packager) Name() string {
+	return "termux"
+}
+
+func (p *Packager) Validate(cfg *config.Config) error {
+	binaries := Binaries(cfg)
+	if len(binaries) == 0 {
+		return errors.NoPlatformBinaryError("an android or linux binary is required for Termux packages")
+	}
+	if _, err := packager.AssetURL(cfg, Platform(cfg, binaries[0].Arch)); err != nil {
+		return errors.NotConfiguredError("installer.base_url, github.owner and github.repo, or gitlab.release is required for the Termux build.sh")
+	}
+	return nil
+}
+
+// Binaries returns the binaries to package in the usual architecture
+// order. android builds replace linux builds of the same architecture;
+// static linux builds run under Termux too.
+func Binaries(cfg *config.Config) []packager.PlatformBinary {
+	android := make(map[string]string)
+	for _, binary := range packager.PlatformBinaries(cfg, "android") {
+		android[binary.Arch] = binary.Path
+	}
+
+	var binaries []packager.PlatformBinary
+	for _, binary := range packager.LinuxBinaries(cfg) {
+		if path, ok := android[binary.Arch]; ok {
+			binary.Path = path
+			delete(android, binary.Arch)
+		}
+		binaries = append(binaries, binary)
+	}
+	for _, binary := range packager.PlatformBinaries(cfg, "android") {
+		if _, ok := android[binary.Arch]; ok {
+			binaries = append(binaries, binary)
+		}
+	}
+	return binaries
+}
+
+// Platform returns the configured platform Binaries takes the arch's
+// binary from
+func Platform(cfg *config.Config, goarch string) string {
+	if _, ok := cfg.Binaries["android-"+goarch]; ok {
+		return "android-" + goarch
+	}
+	return "linux-" + goarch
+}
+
+// Arch maps a Go architecture to the Termux architecture name
+func Arch(goarch string) string {
+	switch goarch {
+	case "amd64":
+		return "x86_64"
+	case "arm64":
+		return "aarch64"
+	case "386":
+		return "i686"
+	default:
+		return goarch
+	}
+}
+
+func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
+	binaries := Binaries(cfg)
+	if len(binaries) == 0 {
+		return "", fmt.Errorf("no android or linux binary found")
+	}
+
+	termuxDir := filepath.Join("dist", "termux")
+	if err := os.MkdirAll(termuxDir, 0755); err != nil {
+		return "", err
+	}
+
+	buildPath := filepath.Join(termuxDir, "packages", cfg.Name, "build.sh")
+	if err := p.createBuildScript(buildPath, cfg); err != nil {
+		return "", err
+	}
+
+	// Build one .deb per architecture; the first (aarch64 unless an amd64
+	// binary is configured) is returned and the rest are reported through
+	// ArchOutputs
+	p.outputs = make(map[string]string)
+	var primary string
+	for _, binary := range binaries {
+		arch := Arch(binary.Arch)
+		outputPath := filepath.Join(termuxDir, fmt.Sprintf("%s_%s_%s.deb", cfg.Name, cfg.Version, arch))
+		if err := p.createDeb(outputPath, cfg, arch, binary.Path); err != nil {
+			return "", fmt.Errorf("failed to create Termux package: %w", err)
+		}
+		if primary == "" {
+			primary = outputPath
+		}
+		p.outputs[arch] = outputPath
+	}
+
+	return primary, nil
+}
+
+// ArchOutputs returns the packages built by the last Pack, keyed by
+// architecture
+func (p *Packager) ArchOutputs() map[string]string {
+	return p.outputs
+}
+
+// createBuildScript writes the build.sh for submitting the package to
+// termux-packages, which installs the released binary for each arch
+func (p *Packager) createBuildScript(outputPath string, cfg *config.Config) error {
+	tmpl := `TERMUX_PKG_HOMEPAGE={{.Homepage}}
+TERMUX_PKG_DESCRIPTION="{{.Summary}}"
+TERMUX_PKG_LICENSE="{{.License}}"
+TERMUX_PKG_MAINTAINER="{{.Maintainer}}"
+TERMUX_PKG_VERSION="{{.Version}}"
+{{- if .Depends}}
+TERMUX_PKG_DEPENDS="{{.Depends}}"
+{{- end}}
+TERMUX_PKG_EXCLUDED_ARCHES="{{.ExcludedArches}}"
+TERMUX_PKG_SKIP_SRC_EXTRACT=true
+
+case "${TERMUX_ARCH}" in
+{{- range .Sources}}
+	{{.Arch}})
+		TERMUX_PKG_SRCURL={{.URL}}
+		TERMUX_PKG_SHA256={{.SHA256}}
+		;;
+{{- end}}
+esac
+
+termux_step_make_install() {
+	install -Dm700 "${TERMUX_PKG_CACHEDIR}/${TERMUX_PKG_SRCURL##*/}" "${TERMUX_PREFIX}/bin/{{.Name}}"
+}
+`
+
+	t, err := packager.ParseTemplate(cfg, "termux/build.sh", tmpl)
+	if err != nil {
+		return err
+	}
+
+	type source struct {
+		Arch, URL, SHA256 string
+	}
+	data := struct {
+		*config.Config
+		Summary        string
+		Maintainer     string
+		Depends        string
+		ExcludedArches string
+		Sources        []source
+	}{
+		Config:     cfg,
+		Summary:    summary(cfg),
+		Maintainer: cfg.Packages.Termux.Maintainer,
+		Depends:    strings.Join(cfg.Packages.Termux.Depends, ", "),
+	}
+	if data.Maintainer == "" && cfg.GitHub.Owner != "" {
+		data.Maintainer = "@" + cfg.GitHub.Owner
+	}
+	archs := map[string]bool{}
+	for _, binary := range Binaries(cfg) {
+		platform := Platform(cfg, binary.Arch)
+		url, err := packager.AssetURL(cfg, platform)
+		if err != nil {
+			return err
+		}
+		sum, err := packager.BinarySHA256(cfg, platform)
+		if err != nil {
+			return err
+		}
+		data.Sources = append(data.Sources, source{Arch: Arch(binary.Arch), URL: url, SHA256: sum})
+		archs[Arch(binary.Arch)] = true
+	}
+	var excluded []string
+	for _, arch := range []string{"aarch64", "arm", "i686", "x86_64"} {
+		if !archs[arch] {
+			excluded = append(excluded, arch)
+		}
+	}
+	data.ExcludedArches = strings.Join(excluded, ", ")
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return t.Execute(f, data)
+}
+
+// createDeb writes a .deb that installs the binary under the Termux prefix.
+// The binary is streamed into data.tar.gz in a temporary directory, since
+// ar needs each member's size before its content.
+func (p *Packager) createDeb(outputPath string, cfg *config.Config, arch, binaryPath string) error {
+	info, err := os.Stat(binaryPath)
+	if err != nil {
+		return err
+	}
+
+	var control strings.Builder
+	fmt.Fprintf(&control, "Package: %s\n", cfg.Name)
+	fmt.Fprintf(&control, "Version: %s\n", cfg.Version)
+	fmt.Fprintf(&control, "Architecture: %s\n", arch)
+	fmt.Fprintf(&control, "Maintainer: %s\n", maintainer(cfg))
+	fmt.Fprintf(&control, "Installed-Size: %d\n", (info.Size()+1023)/1024)
+	if depends := cfg.Packages.Termux.Depends; len(depends) > 0 {
+		fmt.Fprintf(&control, "Depends: %s\n", strings.Join(depends, ", "))
+	}
+	if cfg.Homepage != "" {
+		fmt.Fprintf(&control, "Homepage: %s\n", cfg.Homepage)
+	}
+	fmt.Fprintf(&control, "Description: %s\n", summary(cfg))
+
+	tmpDir, err := os.MkdirTemp("", "bagboy-termux-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "debian-binary"), []byte("2.0\n"), 0644); err != nil {
+		return err
+	}
+	if err := writeTarGz(filepath.Join(tmpDir, "control.tar.gz"), map[string]tarEntry{
+		"control": {mode: 0644, data: []byte(control.String())},
+	}); err != nil {
+		return err
+	}
+	if err := writeTarGz(filepath.Join(tmpDir, "data.tar.gz"), map[string]tarEntry{
+		strings.TrimPrefix(Prefix, "/") + "/bin/" + cfg.Name: {mode: 0755, src: binaryPath},
+	}); err != nil {
+		return err
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := ar.NewWriter(f)
+	if err := w.WriteGlobalHeader(); err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, member := range []string{"debian-binary", "control.tar.gz", "data.tar.gz"} {
+		if err := writeMember(w, filepath.Join(tmpDir, member), now); err != nil {
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// writeMember streams file into the ar archive
+func writeMember(w *ar.Writer, file string, modTime time.Time) error {
+	in, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	header := &ar.Header{Name: filepath.Base(file), Size: info.Size(), Mode: 0644, ModTime: modTime}
+	if err := w.WriteHeader(header); err != nil {
+		return err
+	}
+
+	// The ar writer pads every odd-length write, so only the last chunk
+	// may be short
+	buf := make([]byte, 32<<10)
+	for {
+		n, err := io.ReadFull(in, buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// tarEntry is a file in a tarball, with its content in data or streamed
+// from the file at src
+type tarEntry struct {
+	mode int64
+	data []byte
+	src  string
+}
+
+// writeTarGz writes a gzipped tarball of files to outputPath, adding
+// their parent directories
+func writeTarGz(outputPath string, files map[string]tarEntry) error {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	written := map[string]bool{}
+	now := time.Now()
+	for name, entry := range files {
+		var dirs []string
+		for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+			dirs = append([]string{dir}, dirs...)
+		}
+		for _, dir := range dirs {
+			if written[dir] {
+				continue
+			}
+			written[dir] = true
+			header := &tar.Header{Name: dir + "/", Typeflag: tar.TypeDir, Mode: 0755, ModTime: now}
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+		}
+
+		if err := writeTarEntry(tw, name, entry, now); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func writeTarEntry(tw *tar.Writer, name string, entry tarEntry, modTime time.Time) error {
+	if entry.src == "" {
+		header := &tar.Header{Name: name, Mode: entry.mode, Size: int64(len(entry.data)), ModTime: modTime}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(entry.data)
+		return err
+	}
+
+	in, err := os.Open(entry.src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	header := &tar.Header{Name: name, Mode: entry.mode, Size: info.Size(), ModTime: modTime}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, in)
+	return err
+}
+
+// maintainer returns the Maintainer field of the .deb, which needs a name
+// and email unlike the GitHub handle build.sh uses
+func maintainer(cfg *config.Config) string {
+	for _, person := range cfg.Maintainers() {
+		if person.Email != "" {
+			return person.String()
+		}
+	}
+	if cfg.Packages.Termux.Maintainer != "" {
+		return cfg.Packages.Termux.Maintainer
+	}
+	return cfg.Name
+}
+
+func summary(cfg *config.Config) string {
+	line, _, _ := strings.Cut(cfg.Description, "\n")
+	return strings.TrimSpace(line)
+}
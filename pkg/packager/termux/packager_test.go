@@ -0,0 +1,150 @@
+package termux
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blakesmith/ar"
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+)
+
+func TestTermuxPackager(t *testing.T) {
+	testDir := t.TempDir()
+	linux := filepath.Join(testDir, "testapp-linux-arm64")
+	android := filepath.Join(testDir, "testapp-android-arm64")
+	for _, path := range []string{linux, android} {
+		if err := os.WriteFile(path, []byte(filepath.Base(path)), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Name:        "testapp",
+		Version:     "1.0.0",
+		Description: "Test application",
+		Author:      "Test Author <test@example.com>",
+		Homepage:    "https://github.com/test/testapp",
+		License:     "MIT",
+		Binaries: map[string]string{
+			"linux-arm64":   linux,
+			"android-arm64": android,
+		},
+		GitHub: config.GitHubConfig{Owner: "test", Repo: "testapp"},
+	}
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	p := New()
+	if err := p.Validate(cfg); err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+
+	outputPath, err := p.Pack(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	if outputPath != filepath.Join("dist", "termux", "testapp_1.0.0_aarch64.deb") {
+		t.Errorf("Unexpected output path %s", outputPath)
+	}
+
+	buildScript, err := os.ReadFile(filepath.Join("dist", "termux", "packages", "testapp", "build.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := packager.BinarySHA256(cfg, "android-arm64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`TERMUX_PKG_MAINTAINER="@test"`,
+		`TERMUX_PKG_EXCLUDED_ARCHES="arm, i686, x86_64"`,
+		"\taarch64)\n\t\tTERMUX_PKG_SRCURL=https://github.com/test/testapp/releases/download/v1.0.0/testapp-android-arm64\n",
+		"TERMUX_PKG_SHA256=" + sum,
+		`"${TERMUX_PREFIX}/bin/testapp"`,
+	} {
+		if !strings.Contains(string(buildScript), want) {
+			t.Errorf("build.sh missing %q", want)
+		}
+	}
+
+	// The .deb is an ar archive whose data installs the android binary
+	// under the Termux prefix
+	f, err := os.Open(outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	files := map[string]string{}
+	reader := ar.NewReader(f)
+	for {
+		header, err := reader.Next()
+		if err != nil {
+			break
+		}
+		data, _ := io.ReadAll(reader)
+		if !strings.HasSuffix(header.Name, ".tar.gz") {
+			continue
+		}
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tr := tar.NewReader(gr)
+		for {
+			entry, err := tr.Next()
+			if err != nil {
+				break
+			}
+			content, _ := io.ReadAll(tr)
+			files[entry.Name] = string(content)
+		}
+	}
+
+	if got := files["data/data/com.termux/files/usr/bin/testapp"]; got != "testapp-android-arm64" {
+		t.Errorf("Expected the android binary in the package, got %q", got)
+	}
+	control := files["control"]
+	if !strings.Contains(control, "Architecture: aarch64") || !strings.Contains(control, "Maintainer: Test Author <test@example.com>") {
+		t.Errorf("Unexpected control file:\n%s", control)
+	}
+}
+
+func TestBinaries(t *testing.T) {
+	cfg := &config.Config{Binaries: map[string]string{
+		"linux-amd64":   "bin/linux-amd64",
+		"linux-arm64":   "bin/linux-arm64",
+		"android-arm64": "bin/android-arm64",
+		"android-arm":   "bin/android-arm",
+		"darwin-arm64":  "bin/darwin-arm64",
+	}}
+
+	binaries := Binaries(cfg)
+	expected := []string{"bin/linux-amd64", "bin/android-arm64", "bin/android-arm"}
+	if len(binaries) != len(expected) {
+		t.Fatalf("Expected %d binaries, got %+v", len(expected), binaries)
+	}
+	for i, path := range expected {
+		if binaries[i].Path != path {
+			t.Errorf("binaries[%d] = %s, expected %s", i, binaries[i].Path, path)
+		}
+	}
+
+	if err := New().Validate(&config.Config{}); err == nil {
+		t.Error("Expected validation error without binaries")
+	}
+	if err := New().Validate(cfg); !errors.HasCode(err, errors.CodeNotConfigured) {
+		t.Errorf("Expected a not configured error without a download URL, got %v", err)
+	}
+}
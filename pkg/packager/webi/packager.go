@@ -0,0 +1,223 @@
+package webi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
+)
+
+// Packager writes the webi-installers package and release assets named
+// <name>-<os>-<arch>, which webi, eget and ubi all detect by OS and
+// architecture without extra configuration
+type Packager struct {
+	outputs map[string]string
+}
+
+func New() *Packager {
+	return &Packager{}
+}
+
+func (p *Packager) Name() string {
+	return "webi"
+}
+
+func (p *Packager) Validate(cfg *config.Config) error {
+	if cfg.GitHub.Owner == "" || cfg.GitHub.Repo == "" {
+		return errors.NotConfiguredError("github.owner and github.repo are required for webi")
+	}
+	if len(cfg.Binaries) == 0 {
+		return fmt.Errorf("at least one binary is required for webi")
+	}
+	return nil
+}
+
+// AssetName returns the release asset name for a platform such as
+// linux-amd64
+func AssetName(cfg *config.Config, platform string) string {
+	name := cfg.Name + "-" + platform
+	if strings.HasPrefix(platform, "windows-") {
+		name += ".exe"
+	}
+	return name
+}
+
+func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
+	if len(cfg.Binaries) == 0 {
+		return "", fmt.Errorf("no binaries configured")
+	}
+
+	if err := os.MkdirAll("dist", 0755); err != nil {
+		return "", err
+	}
+
+	// Copy the binaries under their asset names; the first platform is
+	// returned and the rest are reported through ArchOutputs
+	platforms := make([]string, 0, len(cfg.Binaries))
+	for platform := range cfg.Binaries {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	p.outputs = make(map[string]string)
+	var primary string
+	for _, platform := range platforms {
+		assetPath := filepath.Join("dist", AssetName(cfg, platform))
+		if err := paths.CopyExecutable(cfg.Binaries[platform], assetPath, paths.CopyOptions{FollowSymlinks: true}); err != nil {
+			return "", fmt.Errorf("failed to copy %s binary: %w", platform, err)
+		}
+		if primary == "" {
+			primary = assetPath
+		}
+		p.outputs[platform] = assetPath
+	}
+
+	webiDir := filepath.Join("dist", "webi", cfg.Name)
+	if err := os.MkdirAll(webiDir, 0755); err != nil {
+		return "", err
+	}
+	files := map[string]string{
+		"releases.js": releasesTemplate,
+		"install.sh":  installShTemplate,
+		"install.ps1": installPs1Template,
+		"README.md":   readmeTemplate,
+	}
+	for name, tmpl := range files {
+		if err := writeTemplate(filepath.Join(webiDir, name), tmpl, cfg); err != nil {
+			return "", fmt.Errorf("failed to write webi %s: %w", name, err)
+		}
+	}
+
+	return primary, nil
+}
+
+// ArchOutputs returns the release assets written by the last Pack, keyed
+// by platform
+func (p *Packager) ArchOutputs() map[string]string {
+	return p.outputs
+}
+
+const releasesTemplate = `'use strict';
+
+var github = require('../_common/github.js');
+var owner = '{{.GitHub.Owner}}';
+var repo = '{{.GitHub.Repo}}';
+
+module.exports = function () {
+  return github(null, owner, repo).then(function (all) {
+    return all;
+  });
+};
+
+if (module === require.main) {
+  module.exports().then(function (all) {
+    all = require('../_webi/normalize.js')(all);
+    console.info(JSON.stringify(all, null, 2));
+  });
+}
+`
+
+const installShTemplate = `#!/bin/sh
+set -e
+set -u
+
+__init_{{.Ident}}() {
+    pkg_cmd_name="{{.Name}}"
+
+    pkg_dst_cmd="$HOME/.local/bin/{{.Name}}"
+    pkg_dst="$pkg_dst_cmd"
+
+    pkg_src_cmd="$HOME/.local/opt/{{.Name}}-v$WEBI_VERSION/bin/{{.Name}}"
+    pkg_src_dir="$HOME/.local/opt/{{.Name}}-v$WEBI_VERSION"
+    pkg_src="$pkg_src_cmd"
+
+    pkg_install() {
+        mkdir -p "$(dirname "$pkg_src_cmd")"
+        mv ./"$pkg_cmd_name"* "$pkg_src_cmd"
+        chmod a+x "$pkg_src_cmd"
+    }
+
+    pkg_get_current_version() {
+        {{.Name}} --version 2> /dev/null | head -n 1 | grep -oE '[0-9]+\.[0-9]+\.[0-9]+[^ ]*' | head -n 1
+    }
+}
+
+__init_{{.Ident}}
+`
+
+const installPs1Template = `#!/usr/bin/env pwsh
+
+$pkg_cmd_name = "{{.Name}}"
+
+$pkg_dst_cmd = "$Env:USERPROFILE\.local\bin\{{.Name}}.exe"
+$pkg_dst = "$pkg_dst_cmd"
+
+$pkg_src_cmd = "$Env:USERPROFILE\.local\opt\{{.Name}}-v$Env:WEBI_VERSION\bin\{{.Name}}.exe"
+$pkg_src_bin = "$Env:USERPROFILE\.local\opt\{{.Name}}-v$Env:WEBI_VERSION\bin"
+$pkg_src = "$pkg_src_cmd"
+
+New-Item "$Env:USERPROFILE\Downloads\webi" -ItemType Directory -Force | Out-Null
+$pkg_download = "$Env:USERPROFILE\Downloads\webi\$Env:WEBI_PKG_FILE"
+
+if (!(Test-Path -Path "$pkg_download")) {
+    Write-Output "Downloading {{.Name}} from $Env:WEBI_PKG_URL to $pkg_download"
+    & curl.exe -A "$Env:WEBI_UA" -fsSL "$Env:WEBI_PKG_URL" -o "$pkg_download.part"
+    & Move-Item "$pkg_download.part" "$pkg_download"
+}
+
+if (!(Test-Path -Path "$pkg_src_cmd")) {
+    Write-Output "Installing {{.Name}}"
+    New-Item "$pkg_src_bin" -ItemType Directory -Force | Out-Null
+    Copy-Item -Path "$pkg_download" -Destination "$pkg_src_cmd" -Force
+}
+
+Write-Output "Copying into '$pkg_dst_cmd' from '$pkg_src_cmd'"
+Remove-Item -Path "$pkg_dst_cmd" -Recurse -ErrorAction Ignore | Out-Null
+New-Item "$Env:USERPROFILE\.local\bin" -ItemType Directory -Force | Out-Null
+Copy-Item -Path "$pkg_src_cmd" -Destination "$pkg_dst_cmd" -Recurse
+`
+
+const readmeTemplate = `---
+title: {{.Name}}
+homepage: {{if .Homepage}}{{.Homepage}}{{else}}https://github.com/{{.GitHub.Owner}}/{{.GitHub.Repo}}{{end}}
+tagline: |
+  {{.Name}}: {{.Description}}
+---
+
+To update or switch versions, run ` + "`webi {{.Name}}@stable`" + ` (or ` + "`@v{{.Version}}`" + `, etc).
+
+## Cheat Sheet
+
+> {{.Description}}
+
+` + "```sh\n{{.Name}} --help\n```" + `
+`
+
+func writeTemplate(path, tmpl string, cfg *config.Config) error {
+	t, err := packager.ParseTemplate(cfg, "webi/"+filepath.Base(path), tmpl)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data := struct {
+		*config.Config
+		Ident string
+	}{
+		Config: cfg,
+		Ident:  strings.NewReplacer("-", "_", ".", "_").Replace(cfg.Name),
+	}
+	return t.Execute(f, data)
+}
@@ -0,0 +1,95 @@
+package webi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestWebiPackager(t *testing.T) {
+	testDir := t.TempDir()
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	// The linux binary is already built under its asset name
+	if err := os.MkdirAll("dist", 0755); err != nil {
+		t.Fatal(err)
+	}
+	linux := filepath.Join("dist", "test-app-linux-amd64")
+	windows := filepath.Join(testDir, "test-app.exe")
+	for _, path := range []string{linux, windows} {
+		if err := os.WriteFile(path, []byte("fake binary"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Name:        "test-app",
+		Version:     "1.0.0",
+		Description: "Test application",
+		GitHub:      config.GitHubConfig{Owner: "test", Repo: "test-app"},
+		Binaries: map[string]string{
+			"linux-amd64":   linux,
+			"windows-amd64": windows,
+		},
+	}
+
+	p := New()
+	if err := p.Validate(cfg); err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+
+	outputPath, err := p.Pack(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	if outputPath != linux {
+		t.Errorf("Expected primary asset %s, got %s", linux, outputPath)
+	}
+
+	outputs := p.ArchOutputs()
+	if outputs["windows-amd64"] != filepath.Join("dist", "test-app-windows-amd64.exe") {
+		t.Errorf("Unexpected windows asset %s", outputs["windows-amd64"])
+	}
+	for _, path := range outputs {
+		if content, err := os.ReadFile(path); err != nil || string(content) != "fake binary" {
+			t.Errorf("Asset %s not copied: %v", path, err)
+		}
+	}
+
+	webiDir := filepath.Join("dist", "webi", "test-app")
+	releases, err := os.ReadFile(filepath.Join(webiDir, "releases.js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(releases), "var owner = 'test';") || !strings.Contains(string(releases), "var repo = 'test-app';") {
+		t.Errorf("Unexpected releases.js:\n%s", releases)
+	}
+
+	install, err := os.ReadFile(filepath.Join(webiDir, "install.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(install), "__init_test_app() {") || !strings.Contains(string(install), `pkg_cmd_name="test-app"`) {
+		t.Errorf("Unexpected install.sh:\n%s", install)
+	}
+
+	for _, name := range []string{"install.ps1", "README.md"} {
+		if _, err := os.Stat(filepath.Join(webiDir, name)); err != nil {
+			t.Errorf("%s not created", name)
+		}
+	}
+}
+
+func TestWebiPackager_Validate(t *testing.T) {
+	cfg := &config.Config{Name: "test-app", Binaries: map[string]string{"linux-amd64": "bin/test-app"}}
+	if err := New().Validate(cfg); err == nil {
+		t.Error("Expected validation error without a GitHub repository")
+	}
+}
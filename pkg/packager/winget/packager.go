@@ -6,9 +6,12 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"text/template"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/naming"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+	"github.com/scttfrdmn/bagboy/pkg/packager/msix"
 )
 
 type Packager struct{}
@@ -23,10 +26,13 @@ func (p *Packager) Name() string {
 
 func (p *Packager) Validate(cfg *config.Config) error {
 	if cfg.Packages.Winget.PackageIdentifier == "" {
-		return fmt.Errorf("winget.package_identifier is required")
+		return errors.NotConfiguredError("winget.package_identifier is required")
 	}
 	if cfg.Packages.Winget.Publisher == "" {
-		return fmt.Errorf("winget.publisher is required")
+		return errors.NotConfiguredError("winget.publisher is required")
+	}
+	if err := naming.WingetIdentifier(cfg.Packages.Winget.PackageIdentifier); err != nil {
+		return errors.InvalidConfigError("winget.package_identifier", err.Error())
 	}
 	// Check for Windows binary
 	hasWindowsBinary := false
@@ -37,7 +43,24 @@ func (p *Packager) Validate(cfg *config.Config) error {
 		}
 	}
 	if !hasWindowsBinary {
-		return fmt.Errorf("no Windows binary specified for Winget package")
+		return errors.NoPlatformBinaryError("no Windows binary specified for Winget package")
+	}
+
+	winget := cfg.Packages.Winget
+	switch winget.InstallerType {
+	case "", "exe", "msi", "msix", "portable":
+	default:
+		return errors.InvalidConfigError("winget.installer_type", fmt.Sprintf("unsupported type %q (expected exe, msi, msix or portable)", winget.InstallerType))
+	}
+	switch winget.Scope {
+	case "", "user", "machine":
+	default:
+		return errors.InvalidConfigError("winget.scope", fmt.Sprintf("unsupported scope %q (expected user or machine)", winget.Scope))
+	}
+	switch winget.UpgradeBehavior {
+	case "", "install", "uninstallPrevious":
+	default:
+		return errors.InvalidConfigError("winget.upgrade_behavior", fmt.Sprintf("unsupported behavior %q (expected install or uninstallPrevious)", winget.UpgradeBehavior))
 	}
 	return nil
 }
@@ -89,14 +112,33 @@ func (p *Packager) createInstallerManifest(path string, cfg *config.Config) erro
 	tmpl := `PackageIdentifier: {{.PackageIdentifier}}
 PackageVersion: {{.Version}}
 MinimumOSVersion: {{.MinimumOSVersion}}
+{{- if .Scope}}
+Scope: {{.Scope}}
+{{- end}}
+{{- if .UpgradeBehavior}}
+UpgradeBehavior: {{.UpgradeBehavior}}
+{{- end}}
 Installers:
 - Architecture: x64
-  InstallerType: exe
-  InstallerUrl: {{.BaseURL}}/{{.Name}}-windows-amd64.exe
+  InstallerType: {{.InstallerType}}
+  InstallerUrl: {{.BaseURL}}/{{.InstallerFile}}
   InstallerSha256: TODO_CHECKSUM
+{{- if eq .InstallerType "msi"}}
+{{- if .ProductCode}}
+  ProductCode: '{{.ProductCode}}'
+{{- end}}
+{{- else if eq .InstallerType "msix"}}
+{{- if .PackageFamilyName}}
+  PackageFamilyName: {{.PackageFamilyName}}
+{{- end}}
+{{- else if eq .InstallerType "portable"}}
+  Commands:
+  - {{.Name}}
+{{- else}}
   InstallerSwitches:
     Silent: /S
     SilentWithProgress: /S
+{{- end}}
 ManifestType: installer
 ManifestVersion: 1.4.0`
 
@@ -119,7 +161,7 @@ ManifestVersion: 1.4.0`
 }
 
 func (p *Packager) writeTemplate(path, tmpl string, cfg *config.Config) error {
-	t, err := template.New("manifest").Parse(tmpl)
+	t, err := packager.ParseTemplate(cfg, "winget/manifest", tmpl)
 	if err != nil {
 		return err
 	}
@@ -136,20 +178,50 @@ func (p *Packager) writeTemplate(path, tmpl string, cfg *config.Config) error {
 		Publisher         string
 		MinimumOSVersion  string
 		BaseURL           string
+		InstallerType     string
+		InstallerFile     string
+		ProductCode       string
+		PackageFamilyName string
+		UpgradeBehavior   string
+		Scope             string
 	}{
 		Config:            cfg,
 		PackageIdentifier: cfg.Packages.Winget.PackageIdentifier,
 		Publisher:         cfg.Packages.Winget.Publisher,
 		MinimumOSVersion:  cfg.Packages.Winget.MinimumOSVersion,
 		BaseURL:           cfg.Installer.BaseURL,
+		InstallerType:     cfg.Packages.Winget.InstallerType,
+		ProductCode:       cfg.Packages.Winget.ProductCode,
+		PackageFamilyName: cfg.Packages.Winget.PackageFamilyName,
+		UpgradeBehavior:   cfg.Packages.Winget.UpgradeBehavior,
+		Scope:             cfg.Packages.Winget.Scope,
 	}
 
 	if data.Publisher == "" {
-		data.Publisher = cfg.Author
+		data.Publisher = cfg.PrimaryAuthor().Name
 	}
 	if data.MinimumOSVersion == "" {
 		data.MinimumOSVersion = "10.0.0.0"
 	}
+	if data.InstallerType == "" {
+		data.InstallerType = "exe"
+	}
+	data.InstallerFile = p.installerFile(cfg, data.InstallerType)
+	// The MSIX identity reserved in Partner Center determines the family name
+	if data.InstallerType == "msix" && data.PackageFamilyName == "" && cfg.Packages.MSIX.IdentityName != "" {
+		data.PackageFamilyName = msix.PackageIdentity(cfg).PackageFamilyName()
+	}
 
 	return t.Execute(f, data)
 }
+
+// installerFile returns the release asset name the installer manifest
+// points at, matching the output names of the msi and msix packagers
+func (p *Packager) installerFile(cfg *config.Config, installerType string) string {
+	switch installerType {
+	case "msi", "msix":
+		return fmt.Sprintf("%s-%s.%s", cfg.Name, cfg.Version, installerType)
+	default:
+		return fmt.Sprintf("%s-windows-amd64.exe", cfg.Name)
+	}
+}
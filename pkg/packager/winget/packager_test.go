@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
 )
 
 func TestWingetPackager(t *testing.T) {
@@ -257,6 +258,139 @@ func TestCreateInstallerManifest(t *testing.T) {
 	}
 }
 
+func TestCreateInstallerManifest_InstallerTypes(t *testing.T) {
+	packager := New()
+
+	tests := []struct {
+		name     string
+		winget   config.WingetPkgConfig
+		expected []string
+		absent   []string
+	}{
+		{
+			name: "msi",
+			winget: config.WingetPkgConfig{
+				PackageIdentifier: "TestPublisher.TestApp",
+				InstallerType:     "msi",
+				ProductCode:       "{12345678-1234-1234-1234-123456789012}",
+				UpgradeBehavior:   "install",
+				Scope:             "machine",
+			},
+			expected: []string{
+				"InstallerType: msi",
+				"InstallerUrl: https://example.com/testapp-1.0.0.msi",
+				"ProductCode: '{12345678-1234-1234-1234-123456789012}'",
+				"UpgradeBehavior: install",
+				"Scope: machine",
+			},
+			absent: []string{"Silent: /S"},
+		},
+		{
+			name: "msix",
+			winget: config.WingetPkgConfig{
+				PackageIdentifier: "TestPublisher.TestApp",
+				InstallerType:     "msix",
+				PackageFamilyName: "TestPublisher.TestApp_8wekyb3d8bbwe",
+				Scope:             "user",
+			},
+			expected: []string{
+				"InstallerType: msix",
+				"InstallerUrl: https://example.com/testapp-1.0.0.msix",
+				"PackageFamilyName: TestPublisher.TestApp_8wekyb3d8bbwe",
+				"Scope: user",
+			},
+			absent: []string{"Silent: /S", "ProductCode", "UpgradeBehavior"},
+		},
+		{
+			name: "exe default",
+			winget: config.WingetPkgConfig{
+				PackageIdentifier: "TestPublisher.TestApp",
+			},
+			expected: []string{
+				"InstallerType: exe",
+				"InstallerUrl: https://example.com/testapp-windows-amd64.exe",
+				"Silent: /S",
+			},
+			absent: []string{"Scope:", "ProductCode"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Name:      "testapp",
+				Version:   "1.0.0",
+				Packages:  config.PackagesConfig{Winget: tt.winget},
+				Installer: config.InstallerConfig{BaseURL: "https://example.com"},
+			}
+
+			manifestPath := filepath.Join(t.TempDir(), "test.installer.yaml")
+			if err := packager.createInstallerManifest(manifestPath, cfg); err != nil {
+				t.Fatalf("createInstallerManifest() error = %v", err)
+			}
+
+			content, err := os.ReadFile(manifestPath)
+			if err != nil {
+				t.Fatalf("Failed to read installer manifest: %v", err)
+			}
+
+			for _, field := range tt.expected {
+				if !contains(string(content), field) {
+					t.Errorf("Installer manifest missing %q:\n%s", field, content)
+				}
+			}
+			for _, field := range tt.absent {
+				if contains(string(content), field) {
+					t.Errorf("Installer manifest should not contain %q:\n%s", field, content)
+				}
+			}
+		})
+	}
+}
+
+func TestWingetValidate_InstallerOptions(t *testing.T) {
+	packager := New()
+
+	base := config.WingetPkgConfig{
+		PackageIdentifier: "TestPublisher.TestApp",
+		Publisher:         "Test Publisher",
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*config.WingetPkgConfig)
+		wantErr bool
+	}{
+		{"msi", func(w *config.WingetPkgConfig) { w.InstallerType = "msi" }, false},
+		{"msix", func(w *config.WingetPkgConfig) { w.InstallerType = "msix" }, false},
+		{"unknown type", func(w *config.WingetPkgConfig) { w.InstallerType = "nsis-ish" }, true},
+		{"bad scope", func(w *config.WingetPkgConfig) { w.Scope = "global" }, true},
+		{"bad upgrade behavior", func(w *config.WingetPkgConfig) { w.UpgradeBehavior = "replace" }, true},
+		{"uninstall previous", func(w *config.WingetPkgConfig) { w.UpgradeBehavior = "uninstallPrevious" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			winget := base
+			tt.modify(&winget)
+			cfg := &config.Config{
+				Name:     "testapp",
+				Version:  "1.0.0",
+				Binaries: map[string]string{"windows-amd64": "testapp.exe"},
+				Packages: config.PackagesConfig{Winget: winget},
+			}
+
+			err := packager.Validate(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.HasCode(err, errors.CodeInvalidConfig) {
+				t.Errorf("Expected an invalid config error, got %v", err)
+			}
+		})
+	}
+}
+
 func TestCreateLocaleManifest(t *testing.T) {
 	packager := New()
 	
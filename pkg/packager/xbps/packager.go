@@ -0,0 +1,205 @@
+package xbps
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+)
+
+// machines maps Go architectures to the XBPS_TARGET_MACHINE pattern that
+// matches both the glibc and musl variants
+var machines = map[string]string{
+	"amd64":   "x86_64*",
+	"arm64":   "aarch64*",
+	"arm":     "armv7l*",
+	"386":     "i686*",
+	"ppc64le": "ppc64le*",
+}
+
+type Packager struct{}
+
+func New() *Packager {
+	return &Packager{}
+}
+
+func (p *Packager) Name() string {
+	return "xbps"
+}
+
+func (p *Packager) Validate(cfg *config.Config) error {
+	binaries := Binaries(cfg)
+	if len(binaries) == 0 {
+		return errors.NoPlatformBinaryError("a linux amd64, arm64, arm, 386 or ppc64le binary is required for xbps templates")
+	}
+	if cfg.License == "" {
+		return errors.NotConfiguredError("license is required for xbps templates")
+	}
+	if _, err := packager.AssetURL(cfg, "linux-"+binaries[0].Arch); err != nil {
+		return errors.NotConfiguredError("installer.base_url, github.owner and github.repo, or gitlab.release is required for xbps distfiles")
+	}
+	return nil
+}
+
+// Binaries returns the linux binaries Void has a machine for, amd64 first
+func Binaries(cfg *config.Config) []packager.PlatformBinary {
+	var binaries []packager.PlatformBinary
+	for _, binary := range packager.LinuxBinaries(cfg) {
+		if _, ok := machines[binary.Arch]; ok {
+			binaries = append(binaries, binary)
+		}
+	}
+	return binaries
+}
+
+// Machine returns the XBPS_TARGET_MACHINE pattern for a Go architecture,
+// or "" when Void does not support it
+func Machine(goarch string) string {
+	return machines[goarch]
+}
+
+// Version returns the version in the form xbps accepts, which has no
+// hyphens: 1.0.0-rc.1 becomes 1.0.0rc.1
+func Version(cfg *config.Config) string {
+	return strings.ReplaceAll(cfg.Version, "-", "")
+}
+
+func (p *Packager) Pack(ctx context.Context, cfg *config.Config) (string, error) {
+	if len(Binaries(cfg)) == 0 {
+		return "", fmt.Errorf("no linux binary for a Void architecture found")
+	}
+
+	templatePath := filepath.Join("dist", "xbps", "srcpkgs", cfg.Name, "template")
+	if err := os.MkdirAll(filepath.Dir(templatePath), 0755); err != nil {
+		return "", err
+	}
+	if err := p.createTemplate(templatePath, cfg); err != nil {
+		return "", fmt.Errorf("failed to create xbps template: %w", err)
+	}
+	return templatePath, nil
+}
+
+// distfile is one architecture's binary in the template
+type distfile struct {
+	Machine string
+	URL     string
+	SHA256  string
+}
+
+// createTemplate writes the srcpkgs template. Each architecture fetches
+// its release binary, which is installed as it is without extraction.
+func (p *Packager) createTemplate(path string, cfg *config.Config) error {
+	tmpl := `# Template file for '{{.Name}}'
+pkgname={{.Name}}
+version={{.XbpsVersion}}
+revision={{.Revision}}
+archs="{{.Archs}}"
+{{- if .Depends}}
+depends="{{.Depends}}"
+{{- end}}
+short_desc="{{.Summary}}"
+maintainer="{{.Maintainer}}"
+license="{{.LicenseName}}"
+homepage="{{.HomepageURL}}"
+# Prebuilt binaries
+nostrip=yes
+nopie=yes
+
+case "$XBPS_TARGET_MACHINE" in
+{{- range .Distfiles}}
+	{{.Machine}})
+		distfiles="{{.URL}}"
+		checksum={{.SHA256}}
+		;;
+{{- end}}
+esac
+skip_extraction="${distfiles##*/}"
+
+do_install() {
+	vbin "${XBPS_SRCDISTDIR}/${pkgname}-${version}/${distfiles##*/}" ${pkgname}
+}
+`
+
+	t, err := packager.ParseTemplate(cfg, "xbps/template", tmpl)
+	if err != nil {
+		return err
+	}
+
+	data := struct {
+		*config.Config
+		XbpsVersion string
+		Revision    int
+		Archs       string
+		Depends     string
+		Summary     string
+		Maintainer  string
+		LicenseName string
+		HomepageURL string
+		Distfiles   []distfile
+	}{
+		Config:      cfg,
+		XbpsVersion: Version(cfg),
+		Revision:    cfg.Packages.Xbps.Revision,
+		Depends:     strings.Join(cfg.Packages.Xbps.Depends, " "),
+		Summary:     summary(cfg),
+		Maintainer:  maintainer(cfg),
+		LicenseName: cfg.License,
+		HomepageURL: cfg.Homepage,
+	}
+	if data.Revision == 0 {
+		data.Revision = 1
+	}
+	if data.HomepageURL == "" && cfg.GitHub.Owner != "" && cfg.GitHub.Repo != "" {
+		data.HomepageURL = fmt.Sprintf("https://github.com/%s/%s", cfg.GitHub.Owner, cfg.GitHub.Repo)
+	}
+
+	var archs []string
+	for _, binary := range Binaries(cfg) {
+		platform := "linux-" + binary.Arch
+		sum, err := packager.BinarySHA256(cfg, platform)
+		if err != nil {
+			return err
+		}
+		url, err := packager.AssetURL(cfg, platform)
+		if err != nil {
+			return err
+		}
+		archs = append(archs, Machine(binary.Arch))
+		data.Distfiles = append(data.Distfiles, distfile{Machine: Machine(binary.Arch), URL: url, SHA256: sum})
+	}
+	data.Archs = strings.Join(archs, " ")
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return t.Execute(f, data)
+}
+
+// maintainer returns the template maintainer, which Void requires as
+// "Name <email>"; unmaintained templates are orphaned
+func maintainer(cfg *config.Config) string {
+	if cfg.Packages.Xbps.Maintainer != "" {
+		return cfg.Packages.Xbps.Maintainer
+	}
+	for _, person := range cfg.Maintainers() {
+		if person.Email != "" {
+			return person.String()
+		}
+	}
+	return "Orphaned <orphan@voidlinux.org>"
+}
+
+// summary returns the first line of the description without the trailing
+// period xlint rejects
+func summary(cfg *config.Config) string {
+	line, _, _ := strings.Cut(cfg.Description, "\n")
+	return strings.TrimSuffix(strings.TrimSpace(line), ".")
+}
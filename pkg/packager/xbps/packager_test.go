@@ -0,0 +1,114 @@
+package xbps
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/errors"
+	"github.com/scttfrdmn/bagboy/pkg/packager"
+)
+
+func TestXbpsPackager(t *testing.T) {
+	testDir := t.TempDir()
+	binaries := make(map[string]string)
+	for _, platform := range []string{"linux-amd64", "linux-arm64", "linux-riscv64", "darwin-arm64"} {
+		path := filepath.Join(testDir, "testapp-"+platform)
+		if err := os.WriteFile(path, []byte("fake binary"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		binaries[platform] = path
+	}
+
+	cfg := &config.Config{
+		Name:        "testapp",
+		Version:     "1.0.0-rc.1",
+		Description: "Test application.\nLonger description.",
+		Author:      "Test Author <test@example.com>",
+		Homepage:    "https://github.com/test/testapp",
+		License:     "MIT",
+		Binaries:    binaries,
+		GitHub:      config.GitHubConfig{Owner: "test", Repo: "testapp"},
+		Packages: config.PackagesConfig{
+			Xbps: config.XbpsConfig{Depends: []string{"git", "ca-certificates"}},
+		},
+	}
+
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(testDir)
+
+	p := New()
+	if err := p.Validate(cfg); err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+	output, err := p.Pack(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	if output != filepath.Join("dist", "xbps", "srcpkgs", "testapp", "template") {
+		t.Errorf("Unexpected output path %s", output)
+	}
+
+	template, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, _ := packager.BinarySHA256(cfg, "linux-amd64")
+	for _, want := range []string{
+		"pkgname=testapp",
+		"version=1.0.0rc.1",
+		"revision=1",
+		`archs="x86_64* aarch64*"`,
+		`depends="git ca-certificates"`,
+		`short_desc="Test application"`,
+		`maintainer="Test Author <test@example.com>"`,
+		`license="MIT"`,
+		"\tx86_64*)\n\t\tdistfiles=\"https://github.com/test/testapp/releases/download/v1.0.0-rc.1/testapp-linux-amd64\"\n\t\tchecksum=" + sum,
+		"aarch64*)",
+		`vbin "${XBPS_SRCDISTDIR}/${pkgname}-${version}/${distfiles##*/}" ${pkgname}`,
+	} {
+		if !strings.Contains(string(template), want) {
+			t.Errorf("Template missing %q:\n%s", want, template)
+		}
+	}
+	if strings.Contains(string(template), "riscv64") {
+		t.Error("Expected architectures Void does not support to be left out")
+	}
+}
+
+func TestXbpsValidate_NoLinuxBinary(t *testing.T) {
+	cfg := &config.Config{Name: "testapp", Binaries: map[string]string{"darwin-arm64": "testapp"}}
+	if err := New().Validate(cfg); err == nil {
+		t.Error("Expected an error without a linux binary")
+	}
+}
+
+func TestXbpsValidate_Distfiles(t *testing.T) {
+	cfg := &config.Config{Name: "testapp", Binaries: map[string]string{"linux-amd64": "testapp"}}
+	if err := New().Validate(cfg); !errors.HasCode(err, errors.CodeNotConfigured) {
+		t.Errorf("Expected a configuration error without a license, got %v", err)
+	}
+	cfg.License = "MIT"
+	if err := New().Validate(cfg); !errors.HasCode(err, errors.CodeNotConfigured) {
+		t.Errorf("Expected a configuration error without a download URL, got %v", err)
+	}
+	cfg.GitHub = config.GitHubConfig{Owner: "test", Repo: "testapp"}
+	if err := New().Validate(cfg); err != nil {
+		t.Errorf("Validate() failed: %v", err)
+	}
+}
+
+func TestMaintainer(t *testing.T) {
+	cfg := &config.Config{Name: "testapp"}
+	if got := maintainer(cfg); got != "Orphaned <orphan@voidlinux.org>" {
+		t.Errorf("maintainer() = %s", got)
+	}
+	cfg.Packages.Xbps.Maintainer = "Void Packager <void@example.com>"
+	if got := maintainer(cfg); got != "Void Packager <void@example.com>" {
+		t.Errorf("maintainer() = %s", got)
+	}
+}
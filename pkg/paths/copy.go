@@ -0,0 +1,172 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package paths
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// CopyOptions controls what CopyFile and CopyTree keep from the source.
+// File modes, including exec bits, are always kept.
+type CopyOptions struct {
+	// Timestamps keeps modification times; otherwise copies get the time
+	// they were made
+	Timestamps bool
+	// FollowSymlinks copies what symlinks point at instead of the links
+	FollowSymlinks bool
+}
+
+// modeBits are the parts of a file mode a copy keeps
+const modeBits = fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky
+
+// CopyFile copies src to dst, creating dst's directory. A symlink is
+// recreated as a symlink unless opts.FollowSymlinks is set.
+func CopyFile(src, dst string, opts CopyOptions) error {
+	info, err := stat(src, opts)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return copyEntry(src, dst, info, opts)
+}
+
+// CopyExecutable copies src to dst like CopyFile and leaves dst
+// executable. Copying a file onto itself, such as a binary already built
+// under its asset name, does nothing.
+func CopyExecutable(src, dst string, opts CopyOptions) error {
+	if srcInfo, err := os.Stat(src); err == nil {
+		if dstInfo, err := os.Stat(dst); err == nil && os.SameFile(srcInfo, dstInfo) {
+			return nil
+		}
+	}
+	if err := CopyFile(src, dst, opts); err != nil {
+		return err
+	}
+	return os.Chmod(dst, 0755)
+}
+
+// CopyTree copies the directory src to dst, keeping file and directory
+// modes and, unless opts.FollowSymlinks is set, symlinks as symlinks
+func CopyTree(src, dst string, opts CopyOptions) error {
+	var dirs []string
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, relPath)
+		if info.Mode()&fs.ModeSymlink != 0 && opts.FollowSymlinks {
+			if info, err = os.Stat(path); err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return CopyTree(path, target, opts)
+			}
+		}
+		if info.IsDir() {
+			dirs = append(dirs, path)
+			return os.MkdirAll(target, 0755)
+		}
+		return copyEntry(path, target, info, opts)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Directory modes and times are set last, children first, so that
+	// read-only directories can be filled and writing into a directory
+	// does not change its time
+	for i := len(dirs) - 1; i >= 0; i-- {
+		info, err := os.Stat(dirs[i])
+		if err != nil {
+			return err
+		}
+		relPath, _ := filepath.Rel(src, dirs[i])
+		if err := finish(filepath.Join(dst, relPath), info, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func stat(path string, opts CopyOptions) (os.FileInfo, error) {
+	if opts.FollowSymlinks {
+		return os.Stat(path)
+	}
+	return os.Lstat(path)
+}
+
+// copyEntry copies a single file or symlink described by info
+func copyEntry(src, dst string, info os.FileInfo, opts CopyOptions) error {
+	// Replace rather than overwrite, so a read-only copy from an earlier
+	// run does not get in the way
+	if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		if err := os.Symlink(target, dst); err == nil {
+			return nil
+		}
+		// Symlinks are not always allowed on Windows; copy the target
+		if info, err = os.Stat(src); err != nil {
+			return err
+		}
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return finish(dst, info, opts)
+}
+
+// finish applies the source's mode, which the umask may have narrowed,
+// and its modification time when opts.Timestamps is set
+func finish(dst string, info os.FileInfo, opts CopyOptions) error {
+	if err := os.Chmod(dst, info.Mode()&modeBits); err != nil {
+		return err
+	}
+	if opts.Timestamps {
+		return os.Chtimes(dst, info.ModTime(), info.ModTime())
+	}
+	return nil
+}
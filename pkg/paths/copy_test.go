@@ -0,0 +1,109 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCopyTree(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("exec bits and symlinks need a Unix file system")
+	}
+
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "usr", "bin"), 0755)
+	os.WriteFile(filepath.Join(src, "usr", "bin", "app"), []byte("binary"), 0755)
+	os.WriteFile(filepath.Join(src, "README"), []byte("readme"), 0600)
+	os.Symlink("usr/bin/app", filepath.Join(src, "AppRun"))
+	old := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	os.Chtimes(filepath.Join(src, "README"), old, old)
+
+	dst := filepath.Join(t.TempDir(), "copy")
+	if err := CopyTree(src, dst, CopyOptions{Timestamps: true}); err != nil {
+		t.Fatalf("CopyTree() error = %v", err)
+	}
+
+	if info, err := os.Stat(filepath.Join(dst, "usr", "bin", "app")); err != nil || info.Mode().Perm() != 0755 {
+		t.Errorf("exec bit not kept: %v %v", info.Mode(), err)
+	}
+	info, err := os.Stat(filepath.Join(dst, "README"))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("mode not kept: %v %v", info.Mode(), err)
+	}
+	if !info.ModTime().Equal(old) {
+		t.Errorf("timestamp = %v, want %v", info.ModTime(), old)
+	}
+	if target, err := os.Readlink(filepath.Join(dst, "AppRun")); err != nil || target != "usr/bin/app" {
+		t.Errorf("symlink not kept: %q %v", target, err)
+	}
+
+	followed := filepath.Join(t.TempDir(), "followed")
+	if err := CopyTree(src, followed, CopyOptions{FollowSymlinks: true}); err != nil {
+		t.Fatalf("CopyTree() error = %v", err)
+	}
+	info, err = os.Lstat(filepath.Join(followed, "AppRun"))
+	if err != nil || !info.Mode().IsRegular() || info.Mode().Perm() != 0755 {
+		t.Errorf("followed symlink should be a copy of its target, got %v %v", info.Mode(), err)
+	}
+	if info, _ := os.Stat(filepath.Join(followed, "README")); info.ModTime().Equal(old) {
+		t.Error("timestamps should not be kept by default")
+	}
+}
+
+func TestCopyFileReplacesReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "out", "dst")
+	os.WriteFile(src, []byte("one"), 0444)
+	if err := CopyFile(src, dst, CopyOptions{}); err != nil {
+		t.Fatalf("CopyFile() error = %v", err)
+	}
+	os.Chmod(src, 0644)
+	os.WriteFile(src, []byte("two"), 0444)
+	if err := CopyFile(src, dst, CopyOptions{}); err != nil {
+		t.Fatalf("CopyFile() over a read-only copy error = %v", err)
+	}
+	if data, _ := os.ReadFile(dst); string(data) != "two" {
+		t.Errorf("dst = %q, want %q", data, "two")
+	}
+}
+
+func TestCopyExecutable(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "app")
+	dst := filepath.Join(dir, "dist", "app-linux-amd64")
+	os.WriteFile(src, []byte("binary"), 0644)
+	if err := CopyExecutable(src, dst, CopyOptions{}); err != nil {
+		t.Fatalf("CopyExecutable() error = %v", err)
+	}
+	if info, err := os.Stat(dst); err != nil || info.Mode().Perm() != 0755 {
+		t.Errorf("copy should be executable, got %v %v", info.Mode(), err)
+	}
+
+	// Copying a file onto itself leaves it intact
+	if err := CopyExecutable(dst, dst, CopyOptions{}); err != nil {
+		t.Fatalf("CopyExecutable() onto itself error = %v", err)
+	}
+	if data, _ := os.ReadFile(dst); string(data) != "binary" {
+		t.Errorf("dst = %q after copying onto itself", data)
+	}
+}
@@ -0,0 +1,46 @@
+//go:build windows
+
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package paths
+
+import "strings"
+
+// maxPath is the longest path Windows APIs accept without the \\?\ prefix,
+// less room for an 8.3 file name
+const maxPath = 248
+
+// Long adds the \\?\ prefix to an absolute path too long for MAX_PATH, so
+// Windows tools that support it can open the file
+func Long(path string) string {
+	if len(path) < maxPath || strings.HasPrefix(path, `\\?\`) {
+		return path
+	}
+	path = strings.ReplaceAll(path, "/", `\`)
+	if isUNC(path) {
+		return `\\?\UNC\` + path[2:]
+	}
+	if len(path) >= 3 && path[1] == ':' && path[2] == '\\' {
+		return `\\?\` + path
+	}
+	return path
+}
+
+// isUNC reports whether path is a \\server\share path
+func isUNC(path string) bool {
+	return strings.HasPrefix(path, `\\`) && !strings.HasPrefix(path, `\\?\`)
+}
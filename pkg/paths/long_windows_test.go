@@ -0,0 +1,45 @@
+//go:build windows
+
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package paths
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLong(t *testing.T) {
+	long := `C:\build\` + strings.Repeat("a", 260) + `\app.exe`
+	unc := `\\server\share\` + strings.Repeat("a", 260)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{`C:\build\app.exe`, `C:\build\app.exe`},
+		{long, `\\?\` + long},
+		{`\\?\` + long, `\\?\` + long},
+		{unc, `\\?\UNC\server\share\` + strings.Repeat("a", 260)},
+		{strings.ReplaceAll(long, `\`, "/"), `\\?\` + long},
+	}
+	for _, tt := range tests {
+		if got := Long(tt.path); got != tt.want {
+			t.Errorf("Long(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package paths keeps file paths working on every OS bagboy runs on.
+// Paths in bagboy.yaml use forward slashes; paths handed to external
+// tools must be native, absolute and, on Windows, usable past MAX_PATH;
+// paths inside archives, manifests and scripts must use forward slashes.
+package paths
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Native converts a slash-separated path, as written in bagboy.yaml, to
+// the OS separator
+func Native(path string) string {
+	return filepath.FromSlash(path)
+}
+
+// Slash converts path to forward slashes for archive entries, manifests,
+// URLs and shell scripts
+func Slash(path string) string {
+	return filepath.ToSlash(path)
+}
+
+// Tool returns path as an external tool should be given it: absolute, so
+// it does not depend on the tool's working directory, and in long form
+// on Windows
+func Tool(path string) (string, error) {
+	abs, err := filepath.Abs(Native(path))
+	if err != nil {
+		return "", err
+	}
+	return Long(abs), nil
+}
+
+// Link creates a symlink at link pointing to target, a slash-separated
+// path relative to the link's directory. Where symlinks cannot be created,
+// such as on Windows without developer mode, the target is copied instead.
+func Link(target, link string) error {
+	if err := os.Symlink(Native(target), link); err == nil {
+		return nil
+	}
+	src := filepath.Join(filepath.Dir(link), Native(target))
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(link, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// ZipDir writes the files under dir to a zip at outputPath, with
+// slash-separated entry names whatever the OS. Modes and symlinks are
+// kept.
+func ZipDir(dir, outputPath string) error {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = Slash(relPath)
+		header.Method = zip.Deflate
+		entry, err := w.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		// A zip symlink entry holds the link target as its content
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(entry, Slash(target))
+			return err
+		}
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		_, err = io.Copy(entry, in)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return w.Close()
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package paths
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestZipDir(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "tools", "bin"), 0755)
+	os.WriteFile(filepath.Join(dir, "app.nuspec"), []byte("<package/>"), 0644)
+	os.WriteFile(filepath.Join(dir, "tools", "bin", "app.exe"), []byte("binary"), 0755)
+
+	out := filepath.Join(t.TempDir(), "app.zip")
+	if err := ZipDir(dir, out); err != nil {
+		t.Fatalf("ZipDir() error = %v", err)
+	}
+
+	r, err := zip.OpenReader(out)
+	if err != nil {
+		t.Fatalf("output is not a zip: %v", err)
+	}
+	defer r.Close()
+
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	want := []string{"app.nuspec", "tools/bin/app.exe"}
+	if len(names) != len(want) || names[0] != want[0] || names[1] != want[1] {
+		t.Errorf("entries = %v, want %v", names, want)
+	}
+}
+
+func TestLink(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "usr", "share"), 0755)
+	os.WriteFile(filepath.Join(dir, "usr", "share", "app.png"), []byte("png"), 0644)
+
+	link := filepath.Join(dir, "app.png")
+	if err := Link("usr/share/app.png", link); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	data, err := os.ReadFile(link)
+	if err != nil {
+		t.Fatalf("link does not resolve: %v", err)
+	}
+	if string(data) != "png" {
+		t.Errorf("link content = %q, want %q", data, "png")
+	}
+}
+
+func TestTool(t *testing.T) {
+	got, err := Tool("dist/msi-build/app.wxs")
+	if err != nil {
+		t.Fatalf("Tool() error = %v", err)
+	}
+	if !filepath.IsAbs(got) {
+		t.Errorf("Tool() = %q, want an absolute path", got)
+	}
+	if filepath.Base(got) != "app.wxs" {
+		t.Errorf("Tool() = %q, want it to end in app.wxs", got)
+	}
+}
@@ -0,0 +1,404 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy evaluates compliance rules before a release is published.
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/release"
+	"github.com/scttfrdmn/bagboy/pkg/semver"
+)
+
+// DefaultRegoQuery is evaluated when policy.rego.query is not set
+const DefaultRegoQuery = "data.bagboy.deny"
+
+// SignatureSuffixes are the detached signature files accepted as proof an
+// artifact is signed
+var SignatureSuffixes = []string{".sig", ".asc", ".sigstore.bundle", ".minisig"}
+
+// Input is what the rules are evaluated against. It is also passed to rego
+// policies as input.
+type Input struct {
+	Name          string   `json:"name"`
+	Version       string   `json:"version"`
+	License       string   `json:"license"`
+	Artifacts     []string `json:"artifacts"`
+	LatestVersion string   `json:"latest_version,omitempty"`
+	Image         string   `json:"image,omitempty"`
+}
+
+// Result is the outcome of one rule
+type Result struct {
+	Rule    string `json:"rule"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message"`
+}
+
+// Engine evaluates the configured policy
+type Engine struct {
+	config *config.PolicyConfig
+}
+
+// NewEngine creates an engine for cfg
+func NewEngine(cfg *config.PolicyConfig) *Engine {
+	return &Engine{config: cfg}
+}
+
+// Enabled reports whether any rule is configured
+func (e *Engine) Enabled() bool {
+	c := e.config
+	return c.RequireSigned || c.VersionIncrease || len(c.AllowedLicenses) > 0 || c.NoCriticalCVEs || len(c.Rego.Files) > 0
+}
+
+// Evaluate runs every configured rule. Rule failures are reported in the
+// results; the error is only set when evaluation itself could not run.
+func (e *Engine) Evaluate(ctx context.Context, input Input) ([]Result, error) {
+	var results []Result
+
+	if e.config.RequireSigned {
+		results = append(results, CheckSigned(input.Artifacts, signedNatively))
+	}
+	if e.config.VersionIncrease {
+		results = append(results, CheckVersionIncrease(input.Version, input.LatestVersion))
+	}
+	if len(e.config.AllowedLicenses) > 0 {
+		results = append(results, CheckLicense(input.License, e.config.AllowedLicenses))
+	}
+	if e.config.NoCriticalCVEs {
+		results = append(results, e.checkCVEs(ctx, input.Image))
+	}
+	if len(e.config.Rego.Files) > 0 {
+		result, err := e.evaluateRego(ctx, input)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Failed returns the results that did not pass
+func Failed(results []Result) []Result {
+	var failed []Result
+	for _, result := range results {
+		if !result.Passed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// CheckSigned requires every artifact to have a detached signature next to
+// it, to be listed in a signed checksums file among the artifacts, or to
+// pass native, the platform's own signature check
+func CheckSigned(artifacts []string, native func(string) bool) Result {
+	result := Result{Rule: "require_signed", Passed: true, Message: fmt.Sprintf("%d artifacts signed", len(artifacts))}
+
+	covered := signedChecksums(artifacts)
+	var unsigned []string
+	for _, artifact := range artifacts {
+		if isSignature(artifact) || hasDetachedSignature(artifact) || covered[filepath.Base(artifact)] {
+			continue
+		}
+		if native != nil && native(artifact) {
+			continue
+		}
+		unsigned = append(unsigned, filepath.Base(artifact))
+	}
+
+	if len(unsigned) > 0 {
+		result.Passed = false
+		result.Message = "unsigned artifacts: " + strings.Join(unsigned, ", ")
+	}
+	return result
+}
+
+// CheckVersionIncrease requires version to be greater than latest. With no
+// previous release any valid version passes.
+func CheckVersionIncrease(version, latest string) Result {
+	result := Result{Rule: "version_increase"}
+
+	v, err := semver.Parse(version)
+	if err != nil {
+		result.Message = fmt.Sprintf("version %s is not a semantic version", version)
+		return result
+	}
+
+	if latest == "" {
+		result.Passed = true
+		result.Message = fmt.Sprintf("%s is the first release", version)
+		return result
+	}
+
+	l, err := semver.Parse(latest)
+	if err != nil {
+		result.Message = fmt.Sprintf("latest release %s is not a semantic version", latest)
+		return result
+	}
+
+	if semver.Compare(v, l) <= 0 {
+		result.Message = fmt.Sprintf("version %s is not greater than latest release %s", version, latest)
+		return result
+	}
+
+	result.Passed = true
+	result.Message = fmt.Sprintf("%s > %s", version, latest)
+	return result
+}
+
+// CheckLicense requires the license to be in allowed. SPDX expressions
+// are accepted when every license they name is allowed.
+func CheckLicense(license string, allowed []string) Result {
+	result := Result{Rule: "allowed_licenses"}
+
+	if license == "" {
+		result.Message = "no license declared"
+		return result
+	}
+
+	approved := make(map[string]bool)
+	for _, l := range allowed {
+		approved[strings.ToLower(l)] = true
+	}
+
+	replacer := strings.NewReplacer("(", " ", ")", " ")
+	for _, token := range strings.Fields(replacer.Replace(license)) {
+		switch strings.ToUpper(token) {
+		case "AND", "OR", "WITH":
+			continue
+		}
+		if !approved[strings.ToLower(token)] {
+			result.Message = fmt.Sprintf("license %s is not approved", token)
+			return result
+		}
+	}
+
+	result.Passed = true
+	result.Message = fmt.Sprintf("license %s approved", license)
+	return result
+}
+
+// CountCriticalCVEs reads trivy JSON output and returns the critical
+// vulnerability IDs
+func CountCriticalCVEs(report []byte) ([]string, error) {
+	var parsed struct {
+		Results []struct {
+			Vulnerabilities []struct {
+				VulnerabilityID string `json:"VulnerabilityID"`
+				Severity        string `json:"Severity"`
+			} `json:"Vulnerabilities"`
+		} `json:"Results"`
+	}
+	if err := json.Unmarshal(report, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse trivy report: %w", err)
+	}
+
+	var ids []string
+	for _, result := range parsed.Results {
+		for _, vuln := range result.Vulnerabilities {
+			if strings.EqualFold(vuln.Severity, "CRITICAL") {
+				ids = append(ids, vuln.VulnerabilityID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// ParseRegoResult reads opa eval --format json output. The query value may
+// be a set or list of messages, or a boolean where true means denied.
+func ParseRegoResult(output []byte) ([]string, error) {
+	var parsed struct {
+		Result []struct {
+			Expressions []struct {
+				Value interface{} `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse opa output: %w", err)
+	}
+
+	var violations []string
+	for _, result := range parsed.Result {
+		for _, expr := range result.Expressions {
+			switch value := expr.Value.(type) {
+			case []interface{}:
+				for _, v := range value {
+					violations = append(violations, fmt.Sprint(v))
+				}
+			case map[string]interface{}:
+				for k := range value {
+					violations = append(violations, k)
+				}
+			case bool:
+				if value {
+					violations = append(violations, "denied by policy")
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+func (e *Engine) checkCVEs(ctx context.Context, image string) Result {
+	result := Result{Rule: "no_critical_cves"}
+
+	if image == "" {
+		result.Passed = true
+		result.Message = "no container image built"
+		return result
+	}
+
+	if _, err := exec.LookPath("trivy"); err != nil {
+		result.Message = "trivy not found - install from https://trivy.dev to scan images"
+		return result
+	}
+
+	output, err := exec.CommandContext(ctx, "trivy", "image", "--quiet", "--format", "json", "--severity", "CRITICAL", image).Output()
+	if err != nil {
+		result.Message = fmt.Sprintf("trivy scan of %s failed: %v", image, err)
+		return result
+	}
+
+	ids, err := CountCriticalCVEs(output)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+
+	if len(ids) > 0 {
+		result.Message = fmt.Sprintf("%s has %d critical CVEs: %s", image, len(ids), strings.Join(ids, ", "))
+		return result
+	}
+
+	result.Passed = true
+	result.Message = fmt.Sprintf("%s has no critical CVEs", image)
+	return result
+}
+
+func (e *Engine) evaluateRego(ctx context.Context, input Input) (Result, error) {
+	result := Result{Rule: "rego"}
+
+	if _, err := exec.LookPath("opa"); err != nil {
+		return result, fmt.Errorf("opa not found - install from https://www.openpolicyagent.org to evaluate rego policies")
+	}
+
+	inputFile, err := os.CreateTemp("", "bagboy-policy-*.json")
+	if err != nil {
+		return result, err
+	}
+	defer os.Remove(inputFile.Name())
+
+	if err := json.NewEncoder(inputFile).Encode(input); err != nil {
+		inputFile.Close()
+		return result, err
+	}
+	inputFile.Close()
+
+	query := e.config.Rego.Query
+	if query == "" {
+		query = DefaultRegoQuery
+	}
+
+	args := []string{"eval", "--format", "json", "--input", inputFile.Name()}
+	for _, file := range e.config.Rego.Files {
+		args = append(args, "--data", file)
+	}
+	args = append(args, query)
+
+	output, err := exec.CommandContext(ctx, "opa", args...).Output()
+	if err != nil {
+		return result, fmt.Errorf("opa eval failed: %w", err)
+	}
+
+	violations, err := ParseRegoResult(output)
+	if err != nil {
+		return result, err
+	}
+
+	if len(violations) > 0 {
+		result.Message = strings.Join(violations, "; ")
+		return result, nil
+	}
+
+	result.Passed = true
+	result.Message = fmt.Sprintf("%s allowed", query)
+	return result, nil
+}
+
+func isSignature(path string) bool {
+	for _, suffix := range SignatureSuffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// signedChecksums returns the files listed in the signed checksums files
+// among artifacts
+func signedChecksums(artifacts []string) map[string]bool {
+	covered := make(map[string]bool)
+	for _, artifact := range artifacts {
+		if !release.IsChecksumFile(filepath.Base(artifact)) || !hasDetachedSignature(artifact) {
+			continue
+		}
+		data, err := os.ReadFile(artifact)
+		if err != nil {
+			continue
+		}
+		for name := range release.ParseChecksums(string(data)) {
+			covered[name] = true
+		}
+	}
+	return covered
+}
+
+func hasDetachedSignature(path string) bool {
+	for _, suffix := range SignatureSuffixes {
+		if _, err := os.Stat(path + suffix); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// signedNatively checks embedded code signatures where the host can
+// verify them: codesign on macOS and Authenticode on Windows
+func signedNatively(path string) bool {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("codesign", "--verify", "--strict", path).Run() == nil
+	case "windows":
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".exe" && ext != ".msi" && ext != ".msix" {
+			return false
+		}
+		return exec.Command("signtool", "verify", "/pa", path).Run() == nil
+	}
+	return false
+}
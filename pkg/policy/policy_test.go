@@ -0,0 +1,167 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestCheckSigned(t *testing.T) {
+	dir := t.TempDir()
+	signed := filepath.Join(dir, "myapp_1.0.0_amd64.deb")
+	unsigned := filepath.Join(dir, "myapp-1.0.0.msi")
+	native := filepath.Join(dir, "myapp.exe")
+	for _, path := range []string{signed, signed + ".asc", unsigned, native} {
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	nativeCheck := func(path string) bool { return path == native }
+
+	result := CheckSigned([]string{signed, signed + ".asc", native}, nativeCheck)
+	if !result.Passed {
+		t.Errorf("Expected signed artifacts to pass: %s", result.Message)
+	}
+
+	result = CheckSigned([]string{signed, unsigned}, nativeCheck)
+	if result.Passed || !strings.Contains(result.Message, "myapp-1.0.0.msi") {
+		t.Errorf("Expected unsigned msi to fail, got %+v", result)
+	}
+
+	// A signed checksums file covers the artifacts it lists
+	checksums := filepath.Join(dir, "checksums.txt")
+	os.WriteFile(checksums, []byte("abc123  myapp-1.0.0.msi\n"), 0644)
+	if result := CheckSigned([]string{unsigned, checksums}, nil); result.Passed {
+		t.Error("Expected an unsigned checksums file not to cover the msi")
+	}
+	os.WriteFile(checksums+".sig", []byte("sig"), 0644)
+	if result := CheckSigned([]string{unsigned, checksums}, nil); !result.Passed {
+		t.Errorf("Expected the signed checksums file to cover the msi: %s", result.Message)
+	}
+}
+
+func TestCheckVersionIncrease(t *testing.T) {
+	tests := []struct {
+		version, latest string
+		passed          bool
+	}{
+		{"1.1.0", "1.0.0", true},
+		{"1.0.0", "", true},
+		{"1.0.0", "1.0.0", false},
+		{"1.0.0-rc.1", "1.0.0", false},
+		{"0.9.0", "v1.0.0", false},
+		{"latest", "1.0.0", false},
+	}
+
+	for _, tt := range tests {
+		if got := CheckVersionIncrease(tt.version, tt.latest); got.Passed != tt.passed {
+			t.Errorf("CheckVersionIncrease(%s, %s) passed=%v, expected %v: %s", tt.version, tt.latest, got.Passed, tt.passed, got.Message)
+		}
+	}
+}
+
+func TestCheckLicense(t *testing.T) {
+	allowed := []string{"MIT", "Apache-2.0"}
+	tests := []struct {
+		license string
+		passed  bool
+	}{
+		{"MIT", true},
+		{"apache-2.0", true},
+		{"(MIT OR Apache-2.0)", true},
+		{"GPL-3.0", false},
+		{"MIT AND GPL-3.0", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := CheckLicense(tt.license, allowed); got.Passed != tt.passed {
+			t.Errorf("CheckLicense(%q) passed=%v, expected %v: %s", tt.license, got.Passed, tt.passed, got.Message)
+		}
+	}
+}
+
+func TestCountCriticalCVEs(t *testing.T) {
+	report := `{"Results":[{"Vulnerabilities":[
+		{"VulnerabilityID":"CVE-2026-0001","Severity":"CRITICAL"},
+		{"VulnerabilityID":"CVE-2026-0002","Severity":"HIGH"}
+	]},{"Vulnerabilities":null}]}`
+
+	ids, err := CountCriticalCVEs([]byte(report))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []string{"CVE-2026-0001"}) {
+		t.Errorf("Expected one critical CVE, got %v", ids)
+	}
+}
+
+func TestParseRegoResult(t *testing.T) {
+	tests := []struct {
+		output   string
+		expected []string
+	}{
+		{`{"result":[{"expressions":[{"value":["artifact unsigned"]}]}]}`, []string{"artifact unsigned"}},
+		{`{"result":[{"expressions":[{"value":[]}]}]}`, nil},
+		{`{"result":[{"expressions":[{"value":true}]}]}`, []string{"denied by policy"}},
+		{`{}`, nil},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRegoResult([]byte(tt.output))
+		if err != nil {
+			t.Errorf("ParseRegoResult(%s) error: %v", tt.output, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("ParseRegoResult(%s) = %v, expected %v", tt.output, got, tt.expected)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	engine := NewEngine(&config.PolicyConfig{})
+	if engine.Enabled() {
+		t.Error("Expected empty policy to be disabled")
+	}
+
+	engine = NewEngine(&config.PolicyConfig{
+		VersionIncrease: true,
+		AllowedLicenses: []string{"MIT"},
+		NoCriticalCVEs:  true,
+	})
+	results, err := engine.Evaluate(context.Background(), Input{Version: "1.0.0", LatestVersion: "1.1.0", License: "MIT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+
+	failed := Failed(results)
+	if len(failed) != 1 || failed[0].Rule != "version_increase" {
+		t.Errorf("Expected only version_increase to fail, got %+v", failed)
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package prebuilt packs from archives built by another tool, such as
+// goreleaser or make, instead of raw binaries. Each platform's archive is
+// found by glob and its binary extracted so every packager can use it.
+package prebuilt
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// Artifact is a pre-built archive and the binary extracted from it
+type Artifact struct {
+	Platform string
+	Archive  string
+	Binary   string
+}
+
+// Enabled reports whether cfg packs from pre-built archives
+func Enabled(cfg *config.Config) bool {
+	return len(cfg.Prebuilt.Archives) > 0
+}
+
+// Dir returns the directory archives are matched in, dist by default
+func Dir(cfg *config.Config) string {
+	if cfg.Prebuilt.Dir != "" {
+		return cfg.Prebuilt.Dir
+	}
+	return "dist"
+}
+
+// Find matches every platform's glob in the prebuilt directory. Each
+// pattern must match exactly one file.
+func Find(cfg *config.Config) (map[string]string, error) {
+	dir := Dir(cfg)
+	archives := make(map[string]string)
+	for _, platform := range sortedPlatforms(cfg.Prebuilt.Archives) {
+		pattern := cfg.Prebuilt.Archives[platform]
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern %q: %w", platform, pattern, err)
+		}
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("%s: no file in %s matches %q", platform, dir, pattern)
+		case 1:
+			archives[platform] = matches[0]
+		default:
+			return nil, fmt.Errorf("%s: %q matches %d files (%s); make the pattern more specific", platform, pattern, len(matches), strings.Join(matches, ", "))
+		}
+	}
+	return archives, nil
+}
+
+// Extract finds each platform's archive and extracts its binary into
+// workDir. Files that are not archives are used as the binary directly.
+func Extract(cfg *config.Config, workDir string) ([]Artifact, error) {
+	archives, err := Find(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts []Artifact
+	for _, platform := range sortedPlatforms(archives) {
+		archive := archives[platform]
+		artifact := Artifact{Platform: platform, Archive: archive, Binary: archive}
+
+		if isArchive(archive) {
+			name := binaryName(cfg, platform)
+			dest := filepath.Join(workDir, platform, path.Base(name))
+			if err := extractBinary(archive, name, dest); err != nil {
+				return nil, fmt.Errorf("%s: %w", platform, err)
+			}
+			artifact.Binary = dest
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, nil
+}
+
+// Apply records the extracted binaries in cfg.Binaries. Platforms listed
+// in binaries keep their configured path.
+func Apply(cfg *config.Config, artifacts []Artifact) {
+	if cfg.Binaries == nil {
+		cfg.Binaries = make(map[string]string)
+	}
+	for _, artifact := range artifacts {
+		if _, ok := cfg.Binaries[artifact.Platform]; !ok {
+			cfg.Binaries[artifact.Platform] = artifact.Binary
+		}
+	}
+}
+
+// binaryName returns the path of the binary inside the archive. Without
+// prebuilt.binary it is the project name, with .exe on Windows.
+func binaryName(cfg *config.Config, platform string) string {
+	name := cfg.Prebuilt.Binary
+	if name == "" {
+		name = cfg.Name
+	}
+	if strings.HasPrefix(platform, "windows-") && !strings.HasSuffix(name, ".exe") {
+		name += ".exe"
+	}
+	return name
+}
+
+func isArchive(file string) bool {
+	for _, ext := range []string{".tar.gz", ".tgz", ".tar", ".zip"} {
+		if strings.HasSuffix(file, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesEntry reports whether an archive entry is the binary. A name with
+// a slash must match the full entry path; otherwise the base name is
+// compared, since archives often nest files in a versioned directory.
+func matchesEntry(entry, name string) bool {
+	entry = strings.TrimPrefix(path.Clean(entry), "./")
+	if strings.Contains(name, "/") {
+		return entry == strings.TrimPrefix(path.Clean(name), "./")
+	}
+	return path.Base(entry) == name
+}
+
+func extractBinary(archive, name, dest string) error {
+	if strings.HasSuffix(archive, ".zip") {
+		return extractFromZip(archive, name, dest)
+	}
+	return extractFromTar(archive, name, dest)
+}
+
+func extractFromTar(archive, name, dest string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if !strings.HasSuffix(archive, ".tar") {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", archive, err)
+		}
+		defer gr.Close()
+		r = gr
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return fmt.Errorf("%s not found in %s (set prebuilt.binary)", name, archive)
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", archive, err)
+		}
+		if header.Typeflag == tar.TypeReg && matchesEntry(header.Name, name) {
+			return writeBinary(dest, tr)
+		}
+	}
+}
+
+func extractFromZip(archive, name, dest string) error {
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", archive, err)
+	}
+	defer zr.Close()
+
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() || !matchesEntry(file.Name, name) {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		return writeBinary(dest, rc)
+	}
+	return fmt.Errorf("%s not found in %s (set prebuilt.binary)", name, archive)
+}
+
+func writeBinary(dest string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func sortedPlatforms(m map[string]string) []string {
+	platforms := make([]string, 0, len(m))
+	for platform := range m {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prebuilt
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gw.Close()
+}
+
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	zw.Close()
+}
+
+func TestExtract(t *testing.T) {
+	dist := t.TempDir()
+	writeTarGz(t, filepath.Join(dist, "myapp_1.0.0_linux_amd64.tar.gz"), map[string]string{
+		"myapp_1.0.0_linux_amd64/README.md": "readme",
+		"myapp_1.0.0_linux_amd64/myapp":     "linux binary",
+	})
+	writeZip(t, filepath.Join(dist, "myapp_1.0.0_windows_amd64.zip"), map[string]string{
+		"myapp.exe": "windows binary",
+	})
+	if err := os.WriteFile(filepath.Join(dist, "myapp-darwin-arm64"), []byte("darwin binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Name: "myapp",
+		Binaries: map[string]string{
+			"darwin-arm64": "build/myapp-darwin-arm64",
+		},
+		Prebuilt: config.PrebuiltConfig{
+			Dir: dist,
+			Archives: map[string]string{
+				"linux-amd64":   "*_linux_amd64.tar.gz",
+				"windows-amd64": "*_windows_amd64.zip",
+				"darwin-arm64":  "myapp-darwin-arm64",
+			},
+		},
+	}
+
+	artifacts, err := Extract(cfg, t.TempDir())
+	if err != nil {
+		t.Fatalf("Extract() error: %v", err)
+	}
+	if len(artifacts) != 3 {
+		t.Fatalf("Expected 3 artifacts, got %d", len(artifacts))
+	}
+
+	expected := map[string]string{
+		"darwin-arm64":  "darwin binary",
+		"linux-amd64":   "linux binary",
+		"windows-amd64": "windows binary",
+	}
+	for _, artifact := range artifacts {
+		content, err := os.ReadFile(artifact.Binary)
+		if err != nil {
+			t.Errorf("%s: %v", artifact.Platform, err)
+			continue
+		}
+		if string(content) != expected[artifact.Platform] {
+			t.Errorf("%s: extracted %q", artifact.Platform, content)
+		}
+	}
+
+	Apply(cfg, artifacts)
+	if cfg.Binaries["darwin-arm64"] != "build/myapp-darwin-arm64" {
+		t.Error("Expected configured binary to take precedence")
+	}
+	if filepath.Base(cfg.Binaries["windows-amd64"]) != "myapp.exe" {
+		t.Errorf("Unexpected windows binary %s", cfg.Binaries["windows-amd64"])
+	}
+}
+
+func TestExtractErrors(t *testing.T) {
+	dist := t.TempDir()
+	writeTarGz(t, filepath.Join(dist, "myapp_linux_amd64.tar.gz"), map[string]string{"other": "x"})
+	writeTarGz(t, filepath.Join(dist, "myapp_linux_arm64.tar.gz"), map[string]string{"myapp": "x"})
+
+	tests := []struct {
+		name     string
+		archives map[string]string
+		binary   string
+		expected string
+	}{
+		{"no match", map[string]string{"linux-amd64": "*.rpm"}, "", "no file"},
+		{"ambiguous", map[string]string{"linux-amd64": "*.tar.gz"}, "", "matches 2 files"},
+		{"missing binary", map[string]string{"linux-amd64": "*_amd64.tar.gz"}, "", "myapp not found"},
+		{"explicit path", map[string]string{"linux-arm64": "*_arm64.tar.gz"}, "bin/myapp", "bin/myapp not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Name:     "myapp",
+				Prebuilt: config.PrebuiltConfig{Dir: dist, Archives: tt.archives, Binary: tt.binary},
+			}
+			_, err := Extract(cfg, t.TempDir())
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
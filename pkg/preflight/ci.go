@@ -0,0 +1,78 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"context"
+)
+
+// CIRun is a workflow run or check run on a commit
+type CIRun struct {
+	Name       string
+	Status     string // queued, in_progress or completed
+	Conclusion string // success, failure, cancelled, ... once completed
+	URL        string
+}
+
+// CIAccess lists the CI runs on a commit, newest first
+type CIAccess interface {
+	CIRuns(ctx context.Context, fullRepo, ref string) ([]CIRun, error)
+}
+
+// passed are the conclusions that do not hold back a release
+var passed = map[string]bool{"success": true, "neutral": true, "skipped": true}
+
+// CheckCI verifies that each required workflow or check run has passed on
+// commit ref of fullRepo. A run still in progress fails too: the release
+// waits for a green build.
+func CheckCI(ctx context.Context, client CIAccess, fullRepo, ref string, required []string) []Check {
+	runs, err := client.CIRuns(ctx, fullRepo, ref)
+	if err != nil {
+		return []Check{{Name: "ci status", Status: StatusFail, Message: "cannot list checks on " + shortSHA(ref) + ": " + err.Error()}}
+	}
+
+	var checks []Check
+	for _, name := range required {
+		check := Check{Name: name, Status: StatusFail, Message: "not run on " + shortSHA(ref)}
+		for _, run := range runs {
+			if run.Name != name {
+				continue
+			}
+			switch {
+			case run.Status != "completed":
+				check.Message = "still " + run.Status
+			case passed[run.Conclusion]:
+				check.Status, check.Message = StatusPass, run.Conclusion
+			default:
+				check.Message = run.Conclusion
+				if run.URL != "" {
+					check.Message += " - " + run.URL
+				}
+			}
+			break
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+type fakeCI struct {
+	runs []CIRun
+	err  error
+}
+
+func (f fakeCI) CIRuns(ctx context.Context, fullRepo, ref string) ([]CIRun, error) {
+	return f.runs, f.err
+}
+
+func TestCheckCI(t *testing.T) {
+	client := fakeCI{runs: []CIRun{
+		{Name: "CI", Status: "completed", Conclusion: "success"},
+		{Name: "Lint", Status: "completed", Conclusion: "failure", URL: "https://github.com/acme/myapp/actions/runs/2"},
+		{Name: "Release", Status: "in_progress"},
+		// An older run of CI, listed after the newest
+		{Name: "CI", Status: "completed", Conclusion: "failure"},
+	}}
+	sha := "0123456789abcdef0123456789abcdef01234567"
+
+	checks := CheckCI(context.Background(), client, "acme/myapp", sha, []string{"CI", "Lint", "Release", "Docs"})
+	want := []Check{
+		{Name: "CI", Status: StatusPass, Message: "success"},
+		{Name: "Lint", Status: StatusFail, Message: "failure - https://github.com/acme/myapp/actions/runs/2"},
+		{Name: "Release", Status: StatusFail, Message: "still in_progress"},
+		{Name: "Docs", Status: StatusFail, Message: "not run on 0123456789ab"},
+	}
+	if len(checks) != len(want) {
+		t.Fatalf("CheckCI() = %+v, want %+v", checks, want)
+	}
+	for i := range want {
+		if checks[i] != want[i] {
+			t.Errorf("checks[%d] = %+v, want %+v", i, checks[i], want[i])
+		}
+	}
+	if !Failed(checks) {
+		t.Error("Expected the checks to fail")
+	}
+
+	if checks := CheckCI(context.Background(), client, "acme/myapp", sha, []string{"CI"}); Failed(checks) {
+		t.Errorf("Expected a green build to pass: %+v", checks)
+	}
+
+	checks = CheckCI(context.Background(), fakeCI{err: fmt.Errorf("404 Not Found")}, "acme/myapp", sha, []string{"CI"})
+	if len(checks) != 1 || checks[0].Status != StatusFail {
+		t.Errorf("Expected an API error to fail, got %+v", checks)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/naming"
+)
+
+// storeFormats are reviewed by stores that reject trademarks in names
+var storeFormats = []string{"snap", "winget", "msix", "chocolatey"}
+
+// LintNames checks the package name against the naming rules of the
+// registries formats publish to, or every enabled format when formats is
+// empty. Only checks with something to say are returned: a name a format
+// will change warns with the name it uses instead, one it cannot use
+// fails with what to change, and a trademark in a store name warns.
+func LintNames(cfg *config.Config, formats []string) []Check {
+	building := func(format string) bool {
+		if len(formats) == 0 {
+			return cfg.Packages.FormatEnabled(format)
+		}
+		return slices.Contains(formats, format)
+	}
+
+	var checks []Check
+	// lint reports a name the registry rejects, or changes from want
+	lint := func(label, want, fixed string, err error) {
+		switch {
+		case err != nil:
+			checks = append(checks, Check{Name: label, Status: StatusFail, Message: err.Error()})
+		case fixed != want:
+			checks = append(checks, Check{Name: label, Status: StatusWarn, Message: fmt.Sprintf("%s is published as %s", cfg.Name, fixed)})
+		}
+	}
+	if building("deb") {
+		fixed, err := naming.Deb(cfg.Name)
+		lint("deb name", cfg.Name, fixed, err)
+	}
+	if building("snap") {
+		fixed, err := naming.Snap(cfg.Name)
+		lint("snap name", cfg.Name, fixed, err)
+	}
+	if building("npm") {
+		want := cfg.Name
+		if scope := strings.TrimPrefix(cfg.Packages.NPM.Scope, "@"); scope != "" {
+			want = "@" + scope + "/" + cfg.Name
+		}
+		fixed, err := naming.NPM(cfg.Name, cfg.Packages.NPM.Scope)
+		lint("npm name", want, fixed, err)
+	}
+	if id := cfg.Packages.Winget.PackageIdentifier; id != "" && building("winget") {
+		if err := naming.WingetIdentifier(id); err != nil {
+			checks = append(checks, Check{Name: "winget identifier", Status: StatusFail, Message: err.Error()})
+		}
+	}
+
+	var stores []string
+	for _, format := range storeFormats {
+		if building(format) {
+			stores = append(stores, format)
+		}
+	}
+	if mark := naming.Trademark(cfg.Name); mark != "" && len(stores) > 0 {
+		checks = append(checks, Check{
+			Name:    "trademark",
+			Status:  StatusWarn,
+			Message: fmt.Sprintf("%s contains %q, which %s may reject without the trademark owner's permission", cfg.Name, mark, strings.Join(stores, ", ")),
+		})
+	}
+	return checks
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestLintNames(t *testing.T) {
+	cfg := &config.Config{Name: "myapp"}
+	if checks := LintNames(cfg, nil); len(checks) != 0 {
+		t.Errorf("a valid name reported %v", checks)
+	}
+
+	cfg.Name = "My_App"
+	checks := LintNames(cfg, []string{"deb", "snap", "npm"})
+	if len(checks) != 3 || Failed(checks) {
+		t.Fatalf("checks = %v, want three warnings", checks)
+	}
+	if checks[0].Message != "My_App is published as my-app" {
+		t.Errorf("deb message = %q", checks[0].Message)
+	}
+
+	// Only the formats being built are linted
+	if checks := LintNames(cfg, []string{"brew"}); len(checks) != 0 {
+		t.Errorf("brew reported %v", checks)
+	}
+
+	cfg.Name = "http"
+	if checks := LintNames(cfg, []string{"npm"}); !Failed(checks) || !strings.Contains(checks[0].Message, "packages.npm.scope") {
+		t.Errorf("reserved npm name checks = %v", checks)
+	}
+	cfg.Packages.NPM.Scope = "acme"
+	if checks := LintNames(cfg, []string{"npm"}); len(checks) != 0 {
+		t.Errorf("scoped npm name reported %v", checks)
+	}
+
+	cfg.Packages.Winget.PackageIdentifier = "myapp"
+	if checks := LintNames(cfg, []string{"winget"}); !Failed(checks) {
+		t.Errorf("winget identifier without a publisher passed: %v", checks)
+	}
+
+	cfg = &config.Config{Name: "docker-helper"}
+	checks = LintNames(cfg, []string{"snap"})
+	if len(checks) != 1 || checks[0].Status != StatusWarn || !strings.Contains(checks[0].Message, `"docker"`) {
+		t.Errorf("trademark checks = %v", checks)
+	}
+}
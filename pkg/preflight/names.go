@@ -0,0 +1,218 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// NameFormats are the formats whose registries CheckNames looks the
+// package name up on, in order
+var NameFormats = []string{"pypi", "npm", "cargo", "chocolatey"}
+
+// DefaultRegistries are the public registries of NameFormats
+var DefaultRegistries = map[string]string{
+	"pypi":       "https://pypi.org",
+	"npm":        "https://registry.npmjs.org",
+	"cargo":      "https://crates.io",
+	"chocolatey": "https://community.chocolatey.org",
+}
+
+var registryNames = map[string]string{
+	"pypi": "PyPI", "npm": "npm", "cargo": "crates.io", "chocolatey": "Chocolatey",
+}
+
+// pep503 runs collapse to one - in a normalized PyPI name
+var pep503 = regexp.MustCompile(`[-_.]+`)
+
+// NameChecker looks up the package name on public registries, so a name
+// someone else holds is found before the first publish fails
+type NameChecker struct {
+	HTTP       *http.Client
+	Registries map[string]string // base URL per format
+}
+
+// NewNameChecker returns a checker for the public registries
+func NewNameChecker() *NameChecker {
+	return &NameChecker{
+		HTTP:       &http.Client{Timeout: 15 * time.Second},
+		Registries: DefaultRegistries,
+	}
+}
+
+// CheckNames reports, for each enabled format in NameFormats, whether the
+// package name is free on its registry or held by the account configured
+// under accounts:. A name held by someone else, or a registry that cannot
+// be reached, is a warning.
+func (n *NameChecker) CheckNames(ctx context.Context, cfg *config.Config) []Check {
+	var checks []Check
+	for _, format := range NameFormats {
+		if !cfg.Packages.FormatEnabled(format) {
+			continue
+		}
+		name := PackageName(format, cfg.Name)
+		check := Check{Name: registryNames[format] + " name"}
+
+		owners, taken, err := n.lookup(ctx, format, name)
+		account := cfg.Accounts[format]
+		switch {
+		case err != nil:
+			check.Status, check.Message = StatusWarn, fmt.Sprintf("could not check %s: %v", name, err)
+		case !taken:
+			check.Status, check.Message = StatusPass, fmt.Sprintf("%s is available", name)
+		case account != "" && containsFold(owners, account):
+			check.Status, check.Message = StatusPass, fmt.Sprintf("%s is owned by %s", name, account)
+		case len(owners) > 0:
+			check.Status, check.Message = StatusWarn, fmt.Sprintf("%s is taken (owners: %s) - rename the package, or set accounts.%s if it is yours", name, strings.Join(owners, ", "), format)
+		default:
+			check.Status, check.Message = StatusWarn, fmt.Sprintf("%s is taken - rename the package if it is not yours", name)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// PackageName is name as the format's registry knows it
+func PackageName(format, name string) string {
+	switch format {
+	case "pypi":
+		return pep503.ReplaceAllString(strings.ToLower(name), "-")
+	case "npm", "chocolatey":
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// lookup returns the owners of name on the format's registry and whether
+// the name is taken at all
+func (n *NameChecker) lookup(ctx context.Context, format, name string) ([]string, bool, error) {
+	base := strings.TrimSuffix(n.Registries[format], "/")
+	switch format {
+	case "pypi":
+		var project struct {
+			Ownership struct {
+				Roles []struct {
+					User string `json:"user"`
+				} `json:"roles"`
+			} `json:"ownership"`
+		}
+		taken, err := n.getJSON(ctx, base+"/pypi/"+url.PathEscape(name)+"/json", &project)
+		var owners []string
+		for _, role := range project.Ownership.Roles {
+			owners = append(owners, role.User)
+		}
+		return owners, taken, err
+
+	case "npm":
+		var pkg struct {
+			Maintainers []struct {
+				Name string `json:"name"`
+			} `json:"maintainers"`
+		}
+		taken, err := n.getJSON(ctx, base+"/"+url.PathEscape(name), &pkg)
+		var owners []string
+		for _, maintainer := range pkg.Maintainers {
+			owners = append(owners, maintainer.Name)
+		}
+		return owners, taken, err
+
+	case "cargo":
+		var crate struct {
+			Users []struct {
+				Login string `json:"login"`
+			} `json:"users"`
+		}
+		taken, err := n.getJSON(ctx, base+"/api/v1/crates/"+url.PathEscape(name)+"/owners", &crate)
+		var owners []string
+		for _, user := range crate.Users {
+			owners = append(owners, user.Login)
+		}
+		return owners, taken, err
+
+	case "chocolatey":
+		body, err := n.get(ctx, base+"/api/v2/FindPackagesById()?id="+url.QueryEscape("'"+name+"'"), "application/atom+xml")
+		if err != nil {
+			return nil, false, err
+		}
+		var feed struct {
+			Entries []struct {
+				Owners string `xml:"properties>Owners"`
+			} `xml:"entry"`
+		}
+		if err := xml.Unmarshal(body, &feed); err != nil {
+			return nil, false, err
+		}
+		if len(feed.Entries) == 0 {
+			return nil, false, nil
+		}
+		return strings.FieldsFunc(feed.Entries[0].Owners, func(r rune) bool { return r == ',' || r == ' ' }), true, nil
+	}
+	return nil, false, fmt.Errorf("no registry for %s", format)
+}
+
+// getJSON decodes the document at u into v, reporting false for a 404
+func (n *NameChecker) getJSON(ctx context.Context, u string, v interface{}) (bool, error) {
+	body, err := n.get(ctx, u, "application/json")
+	if err != nil || body == nil {
+		return false, err
+	}
+	return true, json.Unmarshal(body, v)
+}
+
+// get returns the body at u, or nil for a 404
+func (n *NameChecker) get(ctx context.Context, u, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	// crates.io refuses requests without a User-Agent
+	req.Header.Set("User-Agent", "bagboy (https://github.com/scttfrdmn/bagboy)")
+	req.Header.Set("Accept", accept)
+	resp, err := n.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s returned %s", u, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestCheckNames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/pypi/my-app/json":
+			w.Write([]byte(`{"ownership": {"roles": [{"role": "Owner", "user": "Me"}]}}`))
+		case r.URL.Path == "/my_app":
+			w.Write([]byte(`{"maintainers": [{"name": "someone"}]}`))
+		case r.URL.Path == "/api/v1/crates/my_app/owners":
+			if r.Header.Get("User-Agent") == "" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			http.NotFound(w, r)
+		case strings.HasPrefix(r.URL.Path, "/api/v2/FindPackagesById"):
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	checker := &NameChecker{HTTP: server.Client(), Registries: map[string]string{}}
+	for _, format := range NameFormats {
+		checker.Registries[format] = server.URL
+	}
+
+	cfg := &config.Config{Name: "my_app", Accounts: map[string]string{"pypi": "me", "npm": "me"}}
+	checks := checker.CheckNames(context.Background(), cfg)
+	got := make(map[string]Check)
+	for _, check := range checks {
+		got[check.Name] = check
+	}
+
+	if check := got["PyPI name"]; check.Status != StatusPass || !strings.Contains(check.Message, "my-app is owned by me") {
+		t.Errorf("PyPI check = %+v, want the normalized name owned by the account", check)
+	}
+	if check := got["npm name"]; check.Status != StatusWarn || !strings.Contains(check.Message, "owners: someone") {
+		t.Errorf("npm check = %+v, want a warning naming the owner", check)
+	}
+	if check := got["crates.io name"]; check.Status != StatusPass || !strings.Contains(check.Message, "available") {
+		t.Errorf("crates.io check = %+v, want available", check)
+	}
+	if check := got["Chocolatey name"]; check.Status != StatusWarn || !strings.Contains(check.Message, "could not check") {
+		t.Errorf("Chocolatey check = %+v, want a warning for the failed lookup", check)
+	}
+
+	// Disabled formats are not looked up
+	cfg.Packages.Enabled = []string{"deb"}
+	if checks := checker.CheckNames(context.Background(), cfg); len(checks) != 0 {
+		t.Errorf("CheckNames() = %+v, want no checks for disabled formats", checks)
+	}
+}
+
+func TestCheckNames_Chocolatey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") != "'myapp'" {
+			w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`))
+			return
+		}
+		w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom" xmlns:m="http://schemas.microsoft.com/ado/2007/08/dataservices/metadata" xmlns:d="http://schemas.microsoft.com/ado/2007/08/dataservices">
+<entry><m:properties><d:Owners>alice, bob</d:Owners></m:properties></entry></feed>`))
+	}))
+	defer server.Close()
+
+	checker := &NameChecker{HTTP: server.Client(), Registries: map[string]string{"chocolatey": server.URL}}
+	cfg := &config.Config{Name: "MyApp", Accounts: map[string]string{"chocolatey": "bob"}}
+	cfg.Packages.Enabled = []string{"chocolatey"}
+
+	checks := checker.CheckNames(context.Background(), cfg)
+	if len(checks) != 1 || checks[0].Status != StatusPass || !strings.Contains(checks[0].Message, "owned by bob") {
+		t.Errorf("CheckNames() = %+v, want myapp owned by bob", checks)
+	}
+
+	cfg.Name = "other"
+	checks = checker.CheckNames(context.Background(), cfg)
+	if len(checks) != 1 || checks[0].Status != StatusPass || !strings.Contains(checks[0].Message, "available") {
+		t.Errorf("CheckNames() = %+v, want other available", checks)
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	for _, tt := range []struct{ format, name, want string }{
+		{"pypi", "My_App.cli", "my-app-cli"},
+		{"npm", "MyApp", "myapp"},
+		{"cargo", "my_app", "my_app"},
+	} {
+		if got := PackageName(tt.format, tt.name); got != tt.want {
+			t.Errorf("PackageName(%s, %s) = %q, want %q", tt.format, tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package preflight validates credentials and access before a publish run
+// starts building packages, so every problem is reported up front.
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// Check statuses
+const (
+	StatusPass = "pass"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+// dockerHubAuthKey is the key docker login uses for Docker Hub
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// Check is the outcome of one preflight check
+type Check struct {
+	Name    string
+	Status  string
+	Message string
+}
+
+// GitHubAccess is the part of the GitHub client preflight needs
+type GitHubAccess interface {
+	Authenticated(ctx context.Context) (string, []string, error)
+	RepoAccess(ctx context.Context, fullRepo string) (exists, push bool, err error)
+}
+
+// Repos names the repositories publish writes to
+type Repos struct {
+	Tap    string
+	Bucket string
+}
+
+// Failed reports whether any check failed
+func Failed(checks []Check) bool {
+	for _, check := range checks {
+		if check.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsGitHub reports whether publishing cfg talks to GitHub
+func NeedsGitHub(cfg *config.Config) bool {
+	gh := cfg.GitHub
+	return gh.Release.Enabled || gh.Tap.Enabled || gh.Bucket.Enabled || (gh.Winget.Enabled && gh.Winget.AutoPR)
+}
+
+// CheckGitHub validates the token and write access to every repository
+// publish will touch. client is nil when no token could be loaded.
+func CheckGitHub(ctx context.Context, cfg *config.Config, client GitHubAccess, repos Repos) []Check {
+	var checks []Check
+	add := func(name, status, format string, args ...interface{}) {
+		checks = append(checks, Check{Name: name, Status: status, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if cfg.GitHub.TokenEnv == "" {
+		add("github token", StatusFail, "github.token_env is not set")
+		return checks
+	}
+	if os.Getenv(cfg.GitHub.TokenEnv) == "" || client == nil {
+		add("github token", StatusFail, "%s is not set", cfg.GitHub.TokenEnv)
+		return checks
+	}
+
+	login, scopes, err := client.Authenticated(ctx)
+	if err != nil {
+		add("github token", StatusFail, "%s was rejected: %v", cfg.GitHub.TokenEnv, err)
+		return checks
+	}
+	add("github token", StatusPass, "authenticated as %s", login)
+
+	// Classic tokens list their scopes; fine-grained tokens are checked
+	// through the repository permissions below
+	if len(scopes) > 0 && !hasScope(scopes, "repo") && !hasScope(scopes, "public_repo") {
+		add("github scopes", StatusFail, "token has scopes %s; repo or public_repo is required", strings.Join(scopes, ", "))
+	}
+
+	checkRepo := func(name, fullRepo string, autoCreate bool) {
+		exists, push, err := client.RepoAccess(ctx, fullRepo)
+		switch {
+		case err != nil:
+			add(name, StatusFail, "%s: %v", fullRepo, err)
+		case !exists && autoCreate:
+			add(name, StatusWarn, "%s does not exist and will be created", fullRepo)
+		case !exists:
+			add(name, StatusFail, "%s does not exist (enable auto_create or create it)", fullRepo)
+		case !push:
+			add(name, StatusFail, "token cannot push to %s", fullRepo)
+		default:
+			add(name, StatusPass, "%s writable", fullRepo)
+		}
+	}
+
+	if cfg.GitHub.Release.Enabled {
+		checkRepo("release repository", cfg.GitHub.Owner+"/"+cfg.GitHub.Repo, false)
+	}
+	if cfg.GitHub.Tap.Enabled && cfg.GitHub.Tap.AutoCommit {
+		checkRepo("homebrew tap", repos.Tap, cfg.GitHub.Tap.AutoCreate)
+	}
+	if cfg.GitHub.Bucket.Enabled && cfg.GitHub.Bucket.AutoCommit {
+		checkRepo("scoop bucket", repos.Bucket, cfg.GitHub.Bucket.AutoCreate)
+	}
+
+	return checks
+}
+
+// CheckRegistries verifies docker has credentials for every registry in
+// packages.docker.registries
+func CheckRegistries(cfg *config.Config) []Check {
+	registries := cfg.Packages.Docker.Registries
+	if len(registries) == 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(DockerConfigPath())
+	if err != nil {
+		return []Check{{Name: "registry credentials", Status: StatusFail, Message: "no docker credentials found - run docker login"}}
+	}
+
+	var checks []Check
+	for _, registry := range registries {
+		host := RegistryHost(registry)
+		status, message := RegistryCredentials(data, host)
+		checks = append(checks, Check{Name: "registry " + host, Status: status, Message: message})
+	}
+	return checks
+}
+
+// DockerConfigPath returns the docker CLI config file, honouring
+// DOCKER_CONFIG
+func DockerConfigPath() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// RegistryHost returns the registry host of an image repository. Names
+// without a registry host are on Docker Hub.
+func RegistryHost(repository string) string {
+	first := strings.SplitN(repository, "/", 2)[0]
+	if !strings.ContainsAny(first, ".:") && first != "localhost" {
+		return "docker.io"
+	}
+	return first
+}
+
+// RegistryCredentials checks a docker config.json for credentials for
+// host. A global credsStore without an entry for host is only a warning,
+// since the store is not queried.
+func RegistryCredentials(dockerConfig []byte, host string) (string, string) {
+	var parsed struct {
+		Auths       map[string]json.RawMessage `json:"auths"`
+		CredHelpers map[string]string          `json:"credHelpers"`
+		CredsStore  string                     `json:"credsStore"`
+	}
+	if err := json.Unmarshal(dockerConfig, &parsed); err != nil {
+		return StatusFail, fmt.Sprintf("invalid docker config: %v", err)
+	}
+
+	keys := []string{host, "https://" + host}
+	if host == "docker.io" {
+		keys = append(keys, dockerHubAuthKey, "index.docker.io")
+	}
+
+	for _, key := range keys {
+		if helper, ok := parsed.CredHelpers[key]; ok {
+			return StatusPass, "credential helper " + helper
+		}
+		if _, ok := parsed.Auths[key]; ok {
+			return StatusPass, "logged in"
+		}
+	}
+
+	if parsed.CredsStore != "" {
+		return StatusWarn, fmt.Sprintf("no login recorded; relying on credential store %s", parsed.CredsStore)
+	}
+	return StatusFail, fmt.Sprintf("not logged in - run docker login %s", host)
+}
+
+func hasScope(scopes []string, want string) bool {
+	for _, scope := range scopes {
+		if scope == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+type fakeGitHub struct {
+	authErr error
+	scopes  []string
+	repos   map[string]bool // repo -> push
+}
+
+func (f *fakeGitHub) Authenticated(ctx context.Context) (string, []string, error) {
+	return "octocat", f.scopes, f.authErr
+}
+
+func (f *fakeGitHub) RepoAccess(ctx context.Context, fullRepo string) (bool, bool, error) {
+	push, exists := f.repos[fullRepo]
+	return exists, push, nil
+}
+
+func statuses(checks []Check) map[string]string {
+	out := make(map[string]string)
+	for _, check := range checks {
+		out[check.Name] = check.Status
+	}
+	return out
+}
+
+func TestCheckGitHub(t *testing.T) {
+	t.Setenv("TEST_GITHUB_TOKEN", "token")
+
+	cfg := &config.Config{GitHub: config.GitHubConfig{
+		Owner:    "me",
+		Repo:     "app",
+		TokenEnv: "TEST_GITHUB_TOKEN",
+		Release:  config.ReleaseConfig{Enabled: true},
+		Tap:      config.TapConfig{Enabled: true, AutoCommit: true},
+		Bucket:   config.BucketConfig{Enabled: true, AutoCommit: true, AutoCreate: true},
+	}}
+	repos := Repos{Tap: "me/homebrew-tap", Bucket: "me/scoop-bucket"}
+	client := &fakeGitHub{scopes: []string{"read:org"}, repos: map[string]bool{"me/app": true}}
+
+	checks := CheckGitHub(context.Background(), cfg, client, repos)
+	expected := map[string]string{
+		"github token":       StatusPass,
+		"github scopes":      StatusFail,
+		"release repository": StatusPass,
+		"homebrew tap":       StatusFail,
+		"scoop bucket":       StatusWarn,
+	}
+	if got := statuses(checks); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("CheckGitHub() = %v, expected %v", got, expected)
+	}
+	if !Failed(checks) {
+		t.Error("Expected failures to be reported")
+	}
+
+	// Every problem is reported, not just the first
+	client = &fakeGitHub{repos: map[string]bool{"me/app": false, "me/homebrew-tap": true}}
+	checks = CheckGitHub(context.Background(), cfg, client, repos)
+	if got := statuses(checks); got["release repository"] != StatusFail || got["homebrew tap"] != StatusPass {
+		t.Errorf("Unexpected checks: %v", got)
+	}
+
+	client = &fakeGitHub{authErr: fmt.Errorf("401 Bad credentials")}
+	if checks := CheckGitHub(context.Background(), cfg, client, repos); len(checks) != 1 || checks[0].Status != StatusFail {
+		t.Errorf("Expected a single token failure, got %v", checks)
+	}
+
+	t.Setenv("TEST_GITHUB_TOKEN", "")
+	if checks := CheckGitHub(context.Background(), cfg, nil, repos); !Failed(checks) {
+		t.Error("Expected missing token to fail")
+	}
+}
+
+func TestRegistryCredentials(t *testing.T) {
+	dockerConfig := []byte(`{
+		"auths": {"https://index.docker.io/v1/": {}, "ghcr.io": {"auth": "x"}},
+		"credHelpers": {"123456789012.dkr.ecr.us-east-1.amazonaws.com": "ecr-login"}
+	}`)
+
+	tests := []struct {
+		host, status string
+	}{
+		{"docker.io", StatusPass},
+		{"ghcr.io", StatusPass},
+		{"123456789012.dkr.ecr.us-east-1.amazonaws.com", StatusPass},
+		{"quay.io", StatusFail},
+	}
+	for _, tt := range tests {
+		if status, message := RegistryCredentials(dockerConfig, tt.host); status != tt.status {
+			t.Errorf("RegistryCredentials(%s) = %s (%s), expected %s", tt.host, status, message, tt.status)
+		}
+	}
+
+	if status, _ := RegistryCredentials([]byte(`{"credsStore":"desktop"}`), "quay.io"); status != StatusWarn {
+		t.Errorf("Expected credsStore to warn, got %s", status)
+	}
+}
+
+func TestRegistryHost(t *testing.T) {
+	tests := map[string]string{
+		"myuser/myapp":                 "docker.io",
+		"docker.io/myuser/myapp":       "docker.io",
+		"ghcr.io/myuser/myapp":         "ghcr.io",
+		"localhost:5000/myapp":         "localhost:5000",
+		"registry.example.com/app/sub": "registry.example.com",
+	}
+	for repository, expected := range tests {
+		if got := RegistryHost(repository); got != expected {
+			t.Errorf("RegistryHost(%s) = %s, expected %s", repository, got, expected)
+		}
+	}
+}
+
+func TestCheckRegistries(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+
+	cfg := &config.Config{}
+	if checks := CheckRegistries(cfg); checks != nil {
+		t.Errorf("Expected no checks without registries, got %v", checks)
+	}
+
+	cfg.Packages.Docker.Registries = []string{"ghcr.io/me/app"}
+	if checks := CheckRegistries(cfg); !Failed(checks) {
+		t.Error("Expected missing docker config to fail")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"auths":{"ghcr.io":{}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if checks := CheckRegistries(cfg); Failed(checks) {
+		t.Errorf("Expected ghcr.io login to pass, got %v", checks)
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AliasesFile lists the assets left out of a release because they are
+// byte-identical to another asset
+const AliasesFile = "aliases.txt"
+
+// WriteAliases writes one "<alias>  <asset>" line per duplicate by base
+// name, sorted, for assets uploaded once under the name of the first
+func WriteAliases(path string, duplicates map[string]string) error {
+	var lines []string
+	for alias, asset := range duplicates {
+		lines = append(lines, fmt.Sprintf("%s  %s", filepath.Base(alias), filepath.Base(asset)))
+	}
+	sort.Strings(lines)
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// WriteChecksums writes sha256sum output for files to path, one line per
+// file by base name, sorted so the output is reproducible
+func WriteChecksums(path string, files []string) error {
+	var lines []string
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to checksum %s: %w", file, err)
+		}
+		lines = append(lines, fmt.Sprintf("%s  %s", hex.EncodeToString(h.Sum(nil)), filepath.Base(file)))
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i][66:] < lines[j][66:] })
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
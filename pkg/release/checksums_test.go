@@ -0,0 +1,66 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteChecksums(t *testing.T) {
+	dir := t.TempDir()
+	b := filepath.Join(dir, "b.txt")
+	a := filepath.Join(dir, "a.txt")
+	os.WriteFile(b, []byte("world"), 0644)
+	os.WriteFile(a, []byte("hello"), 0644)
+
+	out := filepath.Join(dir, "checksums.txt")
+	if err := WriteChecksums(out, []string{b, a}); err != nil {
+		t.Fatalf("WriteChecksums failed: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824  a.txt\n" +
+		"486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7  b.txt\n"
+	if string(data) != want {
+		t.Errorf("checksums = %q, want %q", data, want)
+	}
+
+	if err := WriteChecksums(out, []string{filepath.Join(dir, "missing")}); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestWriteAliases(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, AliasesFile)
+	err := WriteAliases(out, map[string]string{
+		filepath.Join("dist", "c.zip"): filepath.Join("dist", "a.zip"),
+		filepath.Join("dist", "b.zip"): filepath.Join("dist", "a.zip"),
+	})
+	if err != nil {
+		t.Fatalf("WriteAliases failed: %v", err)
+	}
+	data, _ := os.ReadFile(out)
+	if want := "b.zip  a.zip\nc.zip  a.zip\n"; string(data) != want {
+		t.Errorf("aliases = %q, want %q", data, want)
+	}
+}
@@ -0,0 +1,340 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Contents lists the files inside a package, with their sizes, and the
+// dependencies it declares
+type Contents struct {
+	Files   map[string]int64 `json:"files"`
+	Depends []string         `json:"depends,omitempty"`
+}
+
+// HasContents reports whether ReadContents can list the files of an asset
+func HasContents(name string) bool {
+	lower := strings.ToLower(name)
+	for _, ext := range []string{".deb", ".rpm", ".zip", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(lower, ext) && !strings.HasSuffix(lower, ".src.rpm") {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadContents lists the files in a deb, rpm, zip or gzipped tarball.
+// Only the package headers of an rpm are read, so any payload
+// compression is supported; deb data must be gzipped or uncompressed.
+func ReadContents(name string, data []byte) (*Contents, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".deb"):
+		return debContents(data)
+	case strings.HasSuffix(lower, ".rpm"):
+		return rpmContents(data)
+	case strings.HasSuffix(lower, ".zip"):
+		return zipContents(data)
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		contents := &Contents{Files: make(map[string]int64)}
+		return contents, readTar(bytes.NewReader(data), "gz", func(header *tar.Header, _ io.Reader) error {
+			if header.Typeflag == tar.TypeReg {
+				contents.Files[cleanPath(header.Name)] = header.Size
+			}
+			return nil
+		})
+	}
+	return nil, fmt.Errorf("%s: unsupported package type", name)
+}
+
+func zipContents(data []byte) (*Contents, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	contents := &Contents{Files: make(map[string]int64)}
+	for _, f := range zr.File {
+		if !f.FileInfo().IsDir() {
+			contents.Files[cleanPath(f.Name)] = int64(f.UncompressedSize64)
+		}
+	}
+	return contents, nil
+}
+
+// debContents reads the ar archive: the Depends of control.tar and the
+// files of data.tar
+func debContents(data []byte) (*Contents, error) {
+	if !bytes.HasPrefix(data, []byte("!<arch>\n")) {
+		return nil, fmt.Errorf("not a deb archive")
+	}
+	contents := &Contents{Files: make(map[string]int64)}
+
+	offset := 8
+	for offset+60 <= len(data) {
+		header := data[offset : offset+60]
+		name := strings.TrimSuffix(strings.TrimSpace(string(header[0:16])), "/")
+		size, err := strconv.Atoi(strings.TrimSpace(string(header[48:58])))
+		if err != nil || offset+60+size > len(data) {
+			return nil, fmt.Errorf("corrupt deb member %s", name)
+		}
+		member := data[offset+60 : offset+60+size]
+		offset += 60 + size + size%2
+
+		base, compression, _ := strings.Cut(name, ".tar")
+		compression = strings.TrimPrefix(compression, ".")
+		switch base {
+		case "control":
+			err = readTar(bytes.NewReader(member), compression, func(header *tar.Header, r io.Reader) error {
+				if cleanPath(header.Name) != "control" {
+					return nil
+				}
+				control, err := io.ReadAll(r)
+				if err != nil {
+					return err
+				}
+				contents.Depends = debDepends(string(control))
+				return nil
+			})
+		case "data":
+			err = readTar(bytes.NewReader(member), compression, func(header *tar.Header, _ io.Reader) error {
+				if header.Typeflag == tar.TypeReg {
+					contents.Files[cleanPath(header.Name)] = header.Size
+				}
+				return nil
+			})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return contents, nil
+}
+
+// debDepends returns the Pre-Depends and Depends of a control file
+func debDepends(control string) []string {
+	var depends []string
+	for _, line := range strings.Split(control, "\n") {
+		field, value, ok := strings.Cut(line, ":")
+		if !ok || (field != "Depends" && field != "Pre-Depends") {
+			continue
+		}
+		for _, dep := range strings.Split(value, ",") {
+			if dep = strings.TrimSpace(dep); dep != "" {
+				depends = append(depends, dep)
+			}
+		}
+	}
+	return depends
+}
+
+func readTar(r io.Reader, compression string, visit func(*tar.Header, io.Reader) error) error {
+	switch compression {
+	case "":
+	case "gz":
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	default:
+		return fmt.Errorf("unsupported compression %s", compression)
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := visit(header, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// RPM header tags read by rpmContents
+const (
+	rpmTagFileSizes      = 1028
+	rpmTagRequireName    = 1049
+	rpmTagRequireFlags   = 1050
+	rpmTagRequireVersion = 1113
+	rpmTagDirIndexes     = 1116
+	rpmTagBaseNames      = 1117
+	rpmTagDirNames       = 1118
+	rpmTagLongFileSizes  = 5008
+)
+
+// rpmContents reads the file list and requirements from the main header,
+// after the 96 byte lead and the signature header
+func rpmContents(data []byte) (*Contents, error) {
+	if len(data) < 96 || !bytes.HasPrefix(data, []byte{0xed, 0xab, 0xee, 0xdb}) {
+		return nil, fmt.Errorf("not an rpm package")
+	}
+	_, end, err := rpmHeader(data, 96)
+	if err != nil {
+		return nil, fmt.Errorf("signature header: %w", err)
+	}
+	// The signature header is padded to a multiple of 8 bytes
+	tags, _, err := rpmHeader(data, (end+7)/8*8)
+	if err != nil {
+		return nil, fmt.Errorf("header: %w", err)
+	}
+
+	contents := &Contents{Files: make(map[string]int64)}
+	names, dirs, indexes := tags.strings(rpmTagBaseNames), tags.strings(rpmTagDirNames), tags.ints(rpmTagDirIndexes)
+	sizes := tags.ints(rpmTagLongFileSizes)
+	if sizes == nil {
+		sizes = tags.ints(rpmTagFileSizes)
+	}
+	for i, name := range names {
+		if i >= len(indexes) || int(indexes[i]) >= len(dirs) {
+			return nil, fmt.Errorf("corrupt file list")
+		}
+		var size int64
+		if i < len(sizes) {
+			size = sizes[i]
+		}
+		contents.Files[cleanPath(dirs[indexes[i]]+name)] = size
+	}
+
+	requires, flags, versions := tags.strings(rpmTagRequireName), tags.ints(rpmTagRequireFlags), tags.strings(rpmTagRequireVersion)
+	for i, name := range requires {
+		if strings.HasPrefix(name, "rpmlib(") {
+			continue
+		}
+		if i < len(versions) && versions[i] != "" && i < len(flags) {
+			name += " " + rpmOperator(flags[i]) + " " + versions[i]
+		}
+		contents.Depends = append(contents.Depends, name)
+	}
+	return contents, nil
+}
+
+// rpmOperator turns the comparison bits of a require's flags into the
+// operator, e.g. >=
+func rpmOperator(flags int64) string {
+	var op string
+	if flags&2 != 0 {
+		op += "<"
+	}
+	if flags&4 != 0 {
+		op += ">"
+	}
+	if flags&8 != 0 {
+		op += "="
+	}
+	return op
+}
+
+// rpmTags holds the raw entries of an rpm header by tag
+type rpmTags map[int]rpmEntry
+
+type rpmEntry struct {
+	kind  uint32
+	count int
+	data  []byte
+}
+
+// rpmHeader parses the header at offset and returns its entries and the
+// offset just past it
+func rpmHeader(data []byte, offset int) (rpmTags, int, error) {
+	if offset+16 > len(data) || !bytes.HasPrefix(data[offset:], []byte{0x8e, 0xad, 0xe8, 0x01}) {
+		return nil, 0, fmt.Errorf("bad header magic")
+	}
+	count := int(binary.BigEndian.Uint32(data[offset+8:]))
+	size := int(binary.BigEndian.Uint32(data[offset+12:]))
+	store := offset + 16 + count*16
+	end := store + size
+	if count > len(data) || size > len(data) || end > len(data) {
+		return nil, 0, fmt.Errorf("truncated header")
+	}
+
+	tags := make(rpmTags)
+	for i := 0; i < count; i++ {
+		entry := data[offset+16+i*16:]
+		tag := int(binary.BigEndian.Uint32(entry))
+		start := int(binary.BigEndian.Uint32(entry[8:]))
+		if start > size {
+			return nil, 0, fmt.Errorf("tag %d out of range", tag)
+		}
+		tags[tag] = rpmEntry{
+			kind:  binary.BigEndian.Uint32(entry[4:]),
+			count: int(binary.BigEndian.Uint32(entry[12:])),
+			data:  data[store+start : end],
+		}
+	}
+	return tags, end, nil
+}
+
+// strings returns a string or string array entry
+func (t rpmTags) strings(tag int) []string {
+	entry, ok := t[tag]
+	if !ok || (entry.kind != 6 && entry.kind != 8 && entry.kind != 9) {
+		return nil
+	}
+	values := make([]string, 0, entry.count)
+	data := entry.data
+	for len(values) < entry.count {
+		value, rest, ok := bytes.Cut(data, []byte{0})
+		if !ok {
+			break
+		}
+		values = append(values, string(value))
+		data = rest
+	}
+	return values
+}
+
+// ints returns an int32 or int64 array entry
+func (t rpmTags) ints(tag int) []int64 {
+	entry, ok := t[tag]
+	if !ok {
+		return nil
+	}
+	width := map[uint32]int{4: 4, 5: 8}[entry.kind]
+	if width == 0 || len(entry.data) < entry.count*width {
+		return nil
+	}
+	values := make([]int64, entry.count)
+	for i := range values {
+		if width == 4 {
+			values[i] = int64(int32(binary.BigEndian.Uint32(entry.data[i*4:])))
+		} else {
+			values[i] = int64(binary.BigEndian.Uint64(entry.data[i*8:]))
+		}
+	}
+	return values
+}
+
+// cleanPath makes a path inside a package relative to its root
+func cleanPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// tarGz returns a gzipped tarball of files
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+// deb returns an ar archive laid out like a deb
+func deb(t *testing.T, control string, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteString("!<arch>\n")
+	for _, member := range []struct {
+		name string
+		data []byte
+	}{
+		{"debian-binary", []byte("2.0\n")},
+		{"control.tar.gz", tarGz(t, map[string]string{"./control": control})},
+		{"data.tar.gz", tarGz(t, files)},
+	} {
+		fmt.Fprintf(&buf, "%-16s%-12s%-6s%-6s%-8s%-10d`\n", member.name, "0", "0", "0", "100644", len(member.data))
+		buf.Write(member.data)
+		if len(member.data)%2 == 1 {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
+
+type rpmTestEntry struct {
+	tag, kind, count int
+	data             []byte
+}
+
+func rpmTestHeader(entries []rpmTestEntry) []byte {
+	var index, store bytes.Buffer
+	for _, entry := range entries {
+		for store.Len()%4 != 0 {
+			store.WriteByte(0)
+		}
+		binary.Write(&index, binary.BigEndian, []uint32{uint32(entry.tag), uint32(entry.kind), uint32(store.Len()), uint32(entry.count)})
+		store.Write(entry.data)
+	}
+	header := []byte{0x8e, 0xad, 0xe8, 0x01, 0, 0, 0, 0}
+	header = binary.BigEndian.AppendUint32(header, uint32(len(entries)))
+	header = binary.BigEndian.AppendUint32(header, uint32(store.Len()))
+	return append(append(header, index.Bytes()...), store.Bytes()...)
+}
+
+func rpmStrings(values ...string) []byte {
+	return []byte(strings.Join(values, "\x00") + "\x00")
+}
+
+func rpmInts(values ...uint32) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, values)
+	return buf.Bytes()
+}
+
+func TestReadContents_Deb(t *testing.T) {
+	data := deb(t, "Package: myapp\nVersion: 1.0.0\nDepends: libc6, libssl3 (>= 3.0)\n", map[string]string{
+		"./usr/bin/myapp":                    "binary",
+		"./usr/share/doc/myapp/copyright.gz": "license",
+	})
+
+	contents, err := ReadContents("myapp_1.0.0_amd64.deb", data)
+	if err != nil {
+		t.Fatalf("ReadContents failed: %v", err)
+	}
+	expected := map[string]int64{"usr/bin/myapp": 6, "usr/share/doc/myapp/copyright.gz": 7}
+	if !reflect.DeepEqual(contents.Files, expected) {
+		t.Errorf("Files = %v, expected %v", contents.Files, expected)
+	}
+	if !reflect.DeepEqual(contents.Depends, []string{"libc6", "libssl3 (>= 3.0)"}) {
+		t.Errorf("Depends = %v", contents.Depends)
+	}
+}
+
+func TestReadContents_RPM(t *testing.T) {
+	lead := append([]byte{0xed, 0xab, 0xee, 0xdb}, make([]byte, 92)...)
+	signature := rpmTestHeader(nil)
+	header := rpmTestHeader([]rpmTestEntry{
+		{rpmTagFileSizes, 4, 2, rpmInts(6, 7)},
+		{rpmTagRequireName, 8, 3, rpmStrings("glibc", "rpmlib(CompressedFileNames)", "openssl-libs")},
+		{rpmTagRequireFlags, 4, 3, rpmInts(0, 16777224, 12)},
+		{rpmTagRequireVersion, 8, 3, rpmStrings("", "3.0.4-1", "3.0")},
+		{rpmTagDirIndexes, 4, 2, rpmInts(0, 1)},
+		{rpmTagBaseNames, 8, 2, rpmStrings("myapp", "LICENSE")},
+		{rpmTagDirNames, 8, 2, rpmStrings("/usr/bin/", "/usr/share/licenses/myapp/")},
+	})
+	data := append(append(lead, signature...), header...)
+
+	contents, err := ReadContents("myapp-1.0.0-1.x86_64.rpm", data)
+	if err != nil {
+		t.Fatalf("ReadContents failed: %v", err)
+	}
+	expected := map[string]int64{"usr/bin/myapp": 6, "usr/share/licenses/myapp/LICENSE": 7}
+	if !reflect.DeepEqual(contents.Files, expected) {
+		t.Errorf("Files = %v, expected %v", contents.Files, expected)
+	}
+	if !reflect.DeepEqual(contents.Depends, []string{"glibc", "openssl-libs >= 3.0"}) {
+		t.Errorf("Depends = %v", contents.Depends)
+	}
+
+	if _, err := ReadContents("broken.rpm", data[:100]); err == nil {
+		t.Error("Expected an error for a truncated rpm")
+	}
+}
+
+func TestReadContents_Zip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("myapp/myapp.exe")
+	w.Write([]byte("binary"))
+	zw.Create("myapp/")
+	zw.Close()
+
+	contents, err := ReadContents("myapp-windows-amd64.zip", buf.Bytes())
+	if err != nil {
+		t.Fatalf("ReadContents failed: %v", err)
+	}
+	if !reflect.DeepEqual(contents.Files, map[string]int64{"myapp/myapp.exe": 6}) {
+		t.Errorf("Files = %v", contents.Files)
+	}
+}
+
+func TestCompare_Contents(t *testing.T) {
+	from := Snapshot{
+		Tag:    "v1.0.0",
+		Assets: []Asset{{Name: "myapp_1.0.0_amd64.deb", Size: 100}},
+		Contents: map[string]*Contents{"myapp_1.0.0_amd64.deb": {
+			Files:   map[string]int64{"usr/bin/myapp": 100, "usr/share/man/man1/myapp.1.gz": 10, "usr/share/doc/myapp-1.0.0/README": 5},
+			Depends: []string{"libc6", "libssl1.1"},
+		}},
+	}
+	to := Snapshot{
+		Tag:    "v1.1.0",
+		Assets: []Asset{{Name: "myapp_1.1.0_amd64.deb", Size: 150}},
+		Contents: map[string]*Contents{"myapp_1.1.0_amd64.deb": {
+			Files:   map[string]int64{"usr/bin/myapp": 140, "usr/share/doc/myapp-1.1.0/README": 5, "etc/myapp.conf": 3},
+			Depends: []string{"libc6", "libssl3"},
+		}},
+	}
+
+	report := Compare(from, to)
+	if len(report.Contents) != 1 {
+		t.Fatalf("Expected one package change, got %+v", report.Contents)
+	}
+	expected := ContentChange{
+		Name:    "myapp_{version}_amd64.deb",
+		Added:   []string{"etc/myapp.conf"},
+		Removed: []string{"usr/share/man/man1/myapp.1.gz"},
+		Resized: []string{"usr/bin/myapp +40 bytes (+40.0%)"},
+		Depends: []string{"+ libssl3", "- libssl1.1"},
+	}
+	if !reflect.DeepEqual(report.Contents[0], expected) {
+		t.Errorf("Contents = %+v, expected %+v", report.Contents[0], expected)
+	}
+
+	name, growth := report.Growth()
+	if name != "myapp_1.1.0_amd64.deb" || growth != 0.5 {
+		t.Errorf("Growth() = %s, %v", name, growth)
+	}
+
+	var out bytes.Buffer
+	report.Write(&out)
+	for _, want := range []string{"(+50 bytes (+50.0%))", "+ etc/myapp.conf", "- libssl1.1 (depends)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestLocalSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	debPath := filepath.Join(dir, "myapp_1.1.0_amd64.deb")
+	os.WriteFile(debPath, deb(t, "Depends: libc6\n", map[string]string{"./usr/bin/myapp": "binary"}), 0644)
+	formula := filepath.Join(dir, "myapp.rb")
+	os.WriteFile(formula, []byte("class Myapp < Formula\nend\n"), 0644)
+
+	snapshot, err := LocalSnapshot("v1.1.0", []string{debPath, formula}, config.DependenciesConfig{})
+	if err != nil {
+		t.Fatalf("LocalSnapshot failed: %v", err)
+	}
+	if len(snapshot.Assets) != 2 || snapshot.Assets[0].SHA256 == "" {
+		t.Errorf("Unexpected assets %+v", snapshot.Assets)
+	}
+	if snapshot.Manifests["myapp.rb"] == "" {
+		t.Error("Expected the formula as a manifest")
+	}
+	if contents := snapshot.Contents["myapp_1.1.0_amd64.deb"]; contents == nil || contents.Files["usr/bin/myapp"] != 6 {
+		t.Errorf("Unexpected contents %+v", contents)
+	}
+}
@@ -0,0 +1,513 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package release compares published releases for audit trails.
+package release
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// maxManifestSize is the largest file read as a manifest
+const maxManifestSize = 1 << 20
+
+// maxDiffLines bounds the line diff; larger manifests are only reported as
+// changed
+const maxDiffLines = 5000
+
+// Change statuses
+const (
+	Added     = "added"
+	Removed   = "removed"
+	Changed   = "changed"
+	Unchanged = "unchanged"
+)
+
+// Asset is a release asset as seen by diff
+type Asset struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// Snapshot is everything compared for one release
+type Snapshot struct {
+	Tag          string                    `json:"tag"`
+	Assets       []Asset                   `json:"assets"`
+	Manifests    map[string]string         `json:"-"`
+	Dependencies config.DependenciesConfig `json:"-"`
+	// Contents lists the files inside packages, keyed by asset name
+	Contents map[string]*Contents `json:"-"`
+}
+
+// AssetChange is the difference for one asset. Versions in asset names are
+// normalised so myapp-1.0.0.msi and myapp-1.1.0.msi compare as one asset.
+type AssetChange struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Old    *Asset `json:"old,omitempty"`
+	New    *Asset `json:"new,omitempty"`
+}
+
+// ManifestDiff is a line diff of a package manifest
+type ManifestDiff struct {
+	Name string `json:"name"`
+	Diff string `json:"diff"`
+}
+
+// ContentChange is the difference inside one package: files added,
+// removed or resized and declared dependencies added or removed
+type ContentChange struct {
+	Name    string   `json:"name"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Resized []string `json:"resized,omitempty"`
+	Depends []string `json:"depends,omitempty"`
+}
+
+// Report is the result of comparing two releases
+type Report struct {
+	From         string          `json:"from"`
+	To           string          `json:"to"`
+	Assets       []AssetChange   `json:"assets"`
+	Manifests    []ManifestDiff  `json:"manifests,omitempty"`
+	Contents     []ContentChange `json:"contents,omitempty"`
+	Dependencies []string        `json:"dependencies,omitempty"`
+}
+
+// LocalSnapshot collects the packages built for tag, such as the files in
+// dist/, for comparison with the previous release before publishing
+func LocalSnapshot(tag string, files []string, dependencies config.DependenciesConfig) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		Tag:          tag,
+		Manifests:    make(map[string]string),
+		Dependencies: dependencies,
+		Contents:     make(map[string]*Contents),
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Base(file)
+		sum := sha256.Sum256(data)
+		snapshot.Assets = append(snapshot.Assets, Asset{Name: name, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])})
+
+		switch {
+		case IsManifest(name) && len(data) <= maxManifestSize:
+			snapshot.Manifests[name] = string(data)
+		case HasContents(name):
+			// Packages that can't be read are compared by size only
+			if contents, err := ReadContents(name, data); err == nil {
+				snapshot.Contents[name] = contents
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+// Compare diffs two snapshots
+func Compare(from, to Snapshot) Report {
+	report := Report{From: from.Tag, To: to.Tag}
+
+	oldAssets := indexAssets(from)
+	newAssets := indexAssets(to)
+
+	for _, key := range unionKeys(oldAssets, newAssets) {
+		oldAsset, inOld := oldAssets[key]
+		newAsset, inNew := newAssets[key]
+
+		change := AssetChange{Name: key}
+		switch {
+		case !inOld:
+			change.Status = Added
+			change.New = &newAsset
+		case !inNew:
+			change.Status = Removed
+			change.Old = &oldAsset
+		default:
+			change.Old, change.New = &oldAsset, &newAsset
+			change.Status = Unchanged
+			if oldAsset.Size != newAsset.Size || oldAsset.SHA256 != newAsset.SHA256 {
+				change.Status = Changed
+			}
+		}
+		report.Assets = append(report.Assets, change)
+	}
+
+	oldManifests := normaliseManifests(from)
+	newManifests := normaliseManifests(to)
+	for _, key := range unionKeys(oldManifests, newManifests) {
+		oldContent := oldManifests[key]
+		newContent := newManifests[key]
+		if oldContent == newContent {
+			continue
+		}
+		report.Manifests = append(report.Manifests, ManifestDiff{
+			Name: key,
+			Diff: UnifiedDiff(oldContent, newContent, from.Tag+"/"+key, to.Tag+"/"+key),
+		})
+	}
+
+	oldContents := normaliseContents(from)
+	newContents := normaliseContents(to)
+	for _, key := range unionKeys(oldContents, newContents) {
+		oldContent, inOld := oldContents[key]
+		newContent, inNew := newContents[key]
+		if !inOld || !inNew {
+			continue
+		}
+		if change := compareContents(key, oldContent, newContent, from.Tag, to.Tag); change != nil {
+			report.Contents = append(report.Contents, *change)
+		}
+	}
+
+	report.Dependencies = DependencyChanges(from.Dependencies, to.Dependencies)
+	return report
+}
+
+// compareContents diffs the files and dependencies of one package, or
+// returns nil when they are the same
+func compareContents(name string, from, to *Contents, fromTag, toTag string) *ContentChange {
+	change := &ContentChange{Name: name}
+	oldFiles := make(map[string]int64)
+	for file, size := range from.Files {
+		oldFiles[versionless(file, fromTag)] = size
+	}
+	newFiles := make(map[string]int64)
+	for file, size := range to.Files {
+		newFiles[versionless(file, toTag)] = size
+	}
+	for _, file := range unionKeys(oldFiles, newFiles) {
+		oldSize, inOld := oldFiles[file]
+		newSize, inNew := newFiles[file]
+		switch {
+		case !inOld:
+			change.Added = append(change.Added, file)
+		case !inNew:
+			change.Removed = append(change.Removed, file)
+		case oldSize != newSize:
+			change.Resized = append(change.Resized, fmt.Sprintf("%s %s", file, sizeChange(oldSize, newSize)))
+		}
+	}
+
+	oldDepends, newDepends := toSet(from.Depends), toSet(to.Depends)
+	for _, dep := range sortedKeys(newDepends) {
+		if !oldDepends[dep] {
+			change.Depends = append(change.Depends, "+ "+dep)
+		}
+	}
+	for _, dep := range sortedKeys(oldDepends) {
+		if !newDepends[dep] {
+			change.Depends = append(change.Depends, "- "+dep)
+		}
+	}
+
+	if len(change.Added)+len(change.Removed)+len(change.Resized)+len(change.Depends) == 0 {
+		return nil
+	}
+	return change
+}
+
+// Growth returns the largest size increase of an asset present in both
+// releases, as a fraction of its old size, and the asset's name
+func (r Report) Growth() (string, float64) {
+	var name string
+	var growth float64
+	for _, asset := range r.Assets {
+		if asset.Status != Changed || asset.Old.Size == 0 {
+			continue
+		}
+		if g := float64(asset.New.Size-asset.Old.Size) / float64(asset.Old.Size); g > growth {
+			name, growth = asset.New.Name, g
+		}
+	}
+	return name, growth
+}
+
+// Changed reports whether anything differs between the two releases
+func (r Report) Changed() bool {
+	for _, asset := range r.Assets {
+		if asset.Status != Unchanged {
+			return true
+		}
+	}
+	return len(r.Manifests) > 0 || len(r.Contents) > 0 || len(r.Dependencies) > 0
+}
+
+// Write prints the report as text
+func (r Report) Write(w io.Writer) {
+	fmt.Fprintf(w, "Release diff %s → %s\n\nAssets:\n", r.From, r.To)
+	for _, asset := range r.Assets {
+		switch asset.Status {
+		case Added:
+			fmt.Fprintf(w, "  + %-40s %s\n", asset.New.Name, assetDetail(*asset.New))
+		case Removed:
+			fmt.Fprintf(w, "  - %-40s %s\n", asset.Old.Name, assetDetail(*asset.Old))
+		case Changed:
+			fmt.Fprintf(w, "  ~ %-40s %s → %s (%s)\n", asset.New.Name, assetDetail(*asset.Old), assetDetail(*asset.New), sizeChange(asset.Old.Size, asset.New.Size))
+		default:
+			fmt.Fprintf(w, "    %-40s %s\n", asset.New.Name, assetDetail(*asset.New))
+		}
+	}
+
+	if len(r.Manifests) > 0 {
+		fmt.Fprintln(w, "\nManifests:")
+		for _, manifest := range r.Manifests {
+			fmt.Fprintln(w, manifest.Diff)
+		}
+	}
+
+	if len(r.Contents) > 0 {
+		fmt.Fprintln(w, "\nPackage contents:")
+		for _, change := range r.Contents {
+			fmt.Fprintf(w, "  %s\n", change.Name)
+			for _, file := range change.Added {
+				fmt.Fprintf(w, "    + %s\n", file)
+			}
+			for _, file := range change.Removed {
+				fmt.Fprintf(w, "    - %s\n", file)
+			}
+			for _, file := range change.Resized {
+				fmt.Fprintf(w, "    ~ %s\n", file)
+			}
+			for _, dep := range change.Depends {
+				fmt.Fprintf(w, "    %s (depends)\n", dep)
+			}
+		}
+	}
+	if len(r.Dependencies) > 0 {
+		fmt.Fprintln(w, "\nDependencies:")
+		for _, change := range r.Dependencies {
+			fmt.Fprintf(w, "  %s\n", change)
+		}
+	}
+}
+
+func assetDetail(asset Asset) string {
+	if asset.SHA256 == "" {
+		return fmt.Sprintf("%d bytes", asset.Size)
+	}
+	sum := asset.SHA256
+	if len(sum) > 12 {
+		sum = sum[:12]
+	}
+	return fmt.Sprintf("%d bytes sha256:%s", asset.Size, sum)
+}
+
+// sizeChange describes a size change, e.g. +1024 bytes (+12.5%)
+func sizeChange(from, to int64) string {
+	delta := to - from
+	if from == 0 {
+		return fmt.Sprintf("%+d bytes", delta)
+	}
+	return fmt.Sprintf("%+d bytes (%+.1f%%)", delta, float64(delta)*100/float64(from))
+}
+
+// IsManifest reports whether an asset is a package manifest worth diffing
+func IsManifest(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".rb", ".json", ".yaml", ".yml", ".nuspec", ".spec", ".nix", ".py":
+		return true
+	}
+	return false
+}
+
+// IsChecksumFile reports whether an asset holds sha256sum output
+func IsChecksumFile(name string) bool {
+	lower := strings.ToLower(name)
+	return lower == "checksums.txt" || strings.HasSuffix(lower, "sha256sums") ||
+		strings.HasSuffix(lower, "checksums.txt") || strings.HasSuffix(lower, ".sha256sum")
+}
+
+// ParseChecksums reads sha256sum output into a map of file name to digest
+func ParseChecksums(content string) map[string]string {
+	sums := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return sums
+}
+
+// DependencyChanges lists added, removed and changed dependencies
+func DependencyChanges(from, to config.DependenciesConfig) []string {
+	var changes []string
+
+	listChanges := func(kind string, oldMap, newMap map[string][]string) {
+		for _, key := range unionKeys(oldMap, newMap) {
+			oldSet := toSet(oldMap[key])
+			newSet := toSet(newMap[key])
+			for _, dep := range sortedKeys(newSet) {
+				if !oldSet[dep] {
+					changes = append(changes, fmt.Sprintf("+ %s.%s: %s", kind, key, dep))
+				}
+			}
+			for _, dep := range sortedKeys(oldSet) {
+				if !newSet[dep] {
+					changes = append(changes, fmt.Sprintf("- %s.%s: %s", kind, key, dep))
+				}
+			}
+		}
+	}
+
+	listChanges("system", from.System, to.System)
+	listChanges("package_managers", from.PackageManagers, to.PackageManagers)
+
+	for _, key := range unionKeys(from.Runtime, to.Runtime) {
+		oldVersion, inOld := from.Runtime[key]
+		newVersion, inNew := to.Runtime[key]
+		switch {
+		case !inOld:
+			changes = append(changes, fmt.Sprintf("+ runtime.%s: %s", key, newVersion))
+		case !inNew:
+			changes = append(changes, fmt.Sprintf("- runtime.%s: %s", key, oldVersion))
+		case oldVersion != newVersion:
+			changes = append(changes, fmt.Sprintf("~ runtime.%s: %s → %s", key, oldVersion, newVersion))
+		}
+	}
+
+	return changes
+}
+
+// UnifiedDiff returns a line diff of a and b with +/- prefixes, without
+// hunk context trimming; manifests are small enough to show whole
+func UnifiedDiff(a, b, nameA, nameB string) string {
+	oldLines := splitLines(a)
+	newLines := splitLines(b)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", nameA, nameB)
+
+	if len(oldLines) > maxDiffLines || len(newLines) > maxDiffLines {
+		sb.WriteString("(files differ; too large to diff)\n")
+		return sb.String()
+	}
+
+	// Longest common subsequence table
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			sb.WriteString(" " + oldLines[i] + "\n")
+			i++
+			j++
+		case j < len(newLines) && (i == len(oldLines) || lcs[i][j+1] >= lcs[i+1][j]):
+			sb.WriteString("+" + newLines[j] + "\n")
+			j++
+		default:
+			sb.WriteString("-" + oldLines[i] + "\n")
+			i++
+		}
+	}
+
+	return sb.String()
+}
+
+// indexAssets keys assets by name with the release version replaced, so
+// the same artifact of two versions lines up
+func indexAssets(snapshot Snapshot) map[string]Asset {
+	index := make(map[string]Asset)
+	for _, asset := range snapshot.Assets {
+		index[versionless(asset.Name, snapshot.Tag)] = asset
+	}
+	return index
+}
+
+func normaliseManifests(snapshot Snapshot) map[string]string {
+	manifests := make(map[string]string)
+	for name, content := range snapshot.Manifests {
+		manifests[versionless(name, snapshot.Tag)] = content
+	}
+	return manifests
+}
+
+func normaliseContents(snapshot Snapshot) map[string]*Contents {
+	contents := make(map[string]*Contents)
+	for name, content := range snapshot.Contents {
+		contents[versionless(name, snapshot.Tag)] = content
+	}
+	return contents
+}
+
+func versionless(name, tag string) string {
+	version := strings.TrimPrefix(tag, "v")
+	if version == "" {
+		return name
+	}
+	return strings.ReplaceAll(name, version, "{version}")
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool)
+	for k := range a {
+		seen[k] = true
+	}
+	for k := range b {
+		seen[k] = true
+	}
+	return sortedKeys(seen)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestCompare(t *testing.T) {
+	from := Snapshot{
+		Tag: "v1.0.0",
+		Assets: []Asset{
+			{Name: "myapp_1.0.0_amd64.deb", Size: 100, SHA256: "aaa"},
+			{Name: "myapp-1.0.0.msi", Size: 200, SHA256: "bbb"},
+			{Name: "myapp.rb", Size: 10},
+			{Name: "myapp-1.0.0.snap", Size: 300},
+		},
+		Manifests: map[string]string{"myapp.rb": "class Myapp < Formula\n  version \"1.0.0\"\nend\n"},
+		Dependencies: config.DependenciesConfig{
+			System:  map[string][]string{"apt": {"libc6"}},
+			Runtime: map[string]string{"node": "18"},
+		},
+	}
+	to := Snapshot{
+		Tag: "v1.1.0",
+		Assets: []Asset{
+			{Name: "myapp_1.1.0_amd64.deb", Size: 100, SHA256: "aaa"},
+			{Name: "myapp-1.1.0.msi", Size: 250, SHA256: "ccc"},
+			{Name: "myapp.rb", Size: 10},
+			{Name: "myapp_1.1.0_arm64.deb", Size: 90},
+		},
+		Manifests: map[string]string{"myapp.rb": "class Myapp < Formula\n  version \"1.1.0\"\nend\n"},
+		Dependencies: config.DependenciesConfig{
+			System:  map[string][]string{"apt": {"libc6", "libssl3"}},
+			Runtime: map[string]string{"node": "20"},
+		},
+	}
+
+	report := Compare(from, to)
+
+	statuses := map[string]string{}
+	for _, asset := range report.Assets {
+		statuses[asset.Name] = asset.Status
+	}
+	expected := map[string]string{
+		"myapp_{version}_amd64.deb": Unchanged,
+		"myapp-{version}.msi":       Changed,
+		"myapp.rb":                  Unchanged,
+		"myapp-{version}.snap":      Removed,
+		"myapp_{version}_arm64.deb": Added,
+	}
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("Asset statuses = %v, expected %v", statuses, expected)
+	}
+
+	if len(report.Manifests) != 1 {
+		t.Fatalf("Expected one manifest diff, got %d", len(report.Manifests))
+	}
+	diff := report.Manifests[0].Diff
+	if !strings.Contains(diff, "-  version \"1.0.0\"") || !strings.Contains(diff, "+  version \"1.1.0\"") {
+		t.Errorf("Unexpected manifest diff:\n%s", diff)
+	}
+
+	expectedDeps := []string{"+ system.apt: libssl3", "~ runtime.node: 18 → 20"}
+	if !reflect.DeepEqual(report.Dependencies, expectedDeps) {
+		t.Errorf("Dependencies = %v, expected %v", report.Dependencies, expectedDeps)
+	}
+
+	if !report.Changed() {
+		t.Error("Expected report to have changes")
+	}
+
+	var out bytes.Buffer
+	report.Write(&out)
+	if !strings.Contains(out.String(), "+ myapp_1.1.0_arm64.deb") {
+		t.Errorf("Expected added asset in output:\n%s", out.String())
+	}
+}
+
+func TestCompare_Identical(t *testing.T) {
+	snapshot := Snapshot{Tag: "v1.0.0", Assets: []Asset{{Name: "myapp-1.0.0.msi", Size: 1}}}
+	if Compare(snapshot, snapshot).Changed() {
+		t.Error("Expected no changes between identical releases")
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	content := "abc123  myapp-linux-amd64\nDEF456 *myapp.exe\n\nnot a checksum line here\n"
+	expected := map[string]string{"myapp-linux-amd64": "abc123", "myapp.exe": "def456"}
+	if got := ParseChecksums(content); !reflect.DeepEqual(got, expected) {
+		t.Errorf("ParseChecksums() = %v, expected %v", got, expected)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	diff := UnifiedDiff("a\nb\nc\n", "a\nc\nd\n", "old", "new")
+	expected := "--- old\n+++ new\n a\n-b\n c\n+d\n"
+	if diff != expected {
+		t.Errorf("UnifiedDiff() =\n%s\nexpected\n%s", diff, expected)
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package requirements
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"runtime"
@@ -33,6 +34,8 @@ type Requirement struct {
 	WindowsInstall string
 	Required    bool
 	Description string
+	MinVersion  Version                                 // oldest supported version; zero accepts any
+	Detect      func(ctx context.Context) (Tool, bool) // finds the tool and its version; defaults to Command on PATH
 }
 
 // RequirementChecker checks system requirements for package formats
@@ -67,9 +70,11 @@ func (rc *RequirementChecker) initializeRequirements() {
 	rc.requirements["msi"] = []Requirement{
 		{
 			Name:        "WiX Toolset",
-			Command:     "candle",
+			Command:     "wix",
+			Detect:      WiX,
+			MinVersion:  Version{Major: 3},
 			Required:    true,
-			Description: "Windows Installer XML toolset",
+			Description: "Windows Installer XML toolset (v4+ wix, or v3 candle and light)",
 			WindowsInstall: "Download from https://wixtoolset.org/",
 			MacInstall:  "Not available on macOS",
 			LinuxInstall: "Not available on Linux",
@@ -120,12 +125,24 @@ func (rc *RequirementChecker) initializeRequirements() {
 		{
 			Name:        "Docker",
 			Command:     "docker",
+			Detect:      Docker,
 			Required:    true,
 			Description: "Docker container platform",
 			MacInstall:  "brew install --cask docker",
 			LinuxInstall: "curl -fsSL https://get.docker.com | sh",
 			WindowsInstall: "Download Docker Desktop from docker.com",
 		},
+		{
+			Name:        "Docker 24+",
+			Command:     "docker",
+			Detect:      Docker,
+			MinVersion:  DockerBuildxVersion,
+			Required:    false,
+			Description: "Builds images with buildx (older releases use the classic builder)",
+			MacInstall:  "brew upgrade --cask docker",
+			LinuxInstall: "curl -fsSL https://get.docker.com | sh",
+			WindowsInstall: "Update Docker Desktop",
+		},
 	}
 
 	// Snap requirements
@@ -133,14 +150,51 @@ func (rc *RequirementChecker) initializeRequirements() {
 		{
 			Name:        "snapcraft",
 			Command:     "snapcraft",
+			Detect:      func(ctx context.Context) (Tool, bool) { return Detect(ctx, "snapcraft", "--version") },
+			MinVersion:  Version{Major: 7},
 			Required:    true,
-			Description: "Snap package builder",
+			Description: "Snap package builder (7+ for core22, 8+ for core24)",
 			LinuxInstall: "sudo snap install snapcraft --classic",
 			MacInstall:  "Not available on macOS",
 			WindowsInstall: "Not available on Windows",
 		},
 	}
 
+	// Conda requirements
+	rc.requirements["conda"] = []Requirement{
+		{
+			Name:        "rattler-build",
+			Command:     "rattler-build",
+			Required:    false,
+			Description: "Conda package builder (optional, the recipe is written without it; conda-build also works)",
+			MacInstall:  "brew install rattler-build",
+			LinuxInstall: "pixi global install rattler-build",
+			WindowsInstall: "pixi global install rattler-build",
+		},
+	}
+
+	// Delta update requirements
+	rc.requirements["delta"] = []Requirement{
+		{
+			Name:        "makedeltarpm",
+			Command:     "makedeltarpm",
+			Required:    false,
+			Description: "Delta RPM generator (for rpm deltas)",
+			LinuxInstall: "sudo yum install deltarpm",
+			MacInstall:  "Not available on macOS",
+			WindowsInstall: "Not available on Windows",
+		},
+		{
+			Name:        "WiX pyro",
+			Command:     "pyro",
+			Required:    false,
+			Description: "WiX v3 patch builder (for MSP patches)",
+			WindowsInstall: "Download WiX Toolset v3 from https://wixtoolset.org/",
+			MacInstall:  "Not available on macOS",
+			LinuxInstall: "Not available on Linux",
+		},
+	}
+
 	// Code signing requirements
 	rc.requirements["signing"] = []Requirement{
 		{
@@ -171,6 +225,8 @@ type RequirementStatus struct {
 	Available    bool
 	Missing      []Requirement
 	Optional     []Requirement
+	Outdated     []Requirement   // installed but older than MinVersion
+	Detected     map[string]Tool // by requirement name, for tools with a known version
 	Instructions []string
 }
 
@@ -187,11 +243,24 @@ func (rc *RequirementChecker) checkFormatRequirements(format string) Requirement
 	}
 	
 	for _, req := range requirements {
-		if rc.isCommandAvailable(req.Command) {
+		tool, found := rc.detect(req)
+		if found && tool.Version != (Version{}) {
+			if status.Detected == nil {
+				status.Detected = make(map[string]Tool)
+			}
+			status.Detected[req.Name] = tool
+		}
+		if found && rc.isRecentEnough(tool, req) {
 			continue
 		}
 		
-		if req.Required {
+		if found {
+			// Installed, but too old
+			status.Outdated = append(status.Outdated, req)
+			if req.Required {
+				status.Available = false
+			}
+		} else if req.Required {
 			status.Available = false
 			status.Missing = append(status.Missing, req)
 		} else {
@@ -208,6 +277,23 @@ func (rc *RequirementChecker) checkFormatRequirements(format string) Requirement
 	return status
 }
 
+// detect finds the tool for req, with its version when req has a detector
+func (rc *RequirementChecker) detect(req Requirement) (Tool, bool) {
+	if req.Detect == nil {
+		return Tool{Command: req.Command}, rc.isCommandAvailable(req.Command)
+	}
+	return req.Detect(context.Background())
+}
+
+// isRecentEnough reports whether tool meets req.MinVersion. A tool whose
+// version could not be read is given the benefit of the doubt.
+func (rc *RequirementChecker) isRecentEnough(tool Tool, req Requirement) bool {
+	if req.MinVersion == (Version{}) || tool.Version == (Version{}) {
+		return true
+	}
+	return tool.Version.AtLeast(req.MinVersion)
+}
+
 func (rc *RequirementChecker) isCommandAvailable(command string) bool {
 	if command == "" {
 		return true
@@ -244,7 +330,17 @@ func (rc *RequirementChecker) PrintRequirementReport(results map[string]Requirem
 		
 		if status.Available && len(status.Missing) == 0 {
 			fmt.Println("  ✅ Ready to build")
+			for _, req := range status.Outdated {
+				fmt.Printf("  ⚠️  %s is older than %s - %s\n", status.Detected[req.Name], req.MinVersion, req.Description)
+			}
 		} else {
+			if len(status.Outdated) > 0 {
+				fmt.Println("  ❌ Outdated dependencies:")
+				for _, req := range status.Outdated {
+					fmt.Printf("    • %s is older than %s - %s\n", status.Detected[req.Name], req.MinVersion, req.Description)
+				}
+			}
+			
 			if len(status.Missing) > 0 {
 				fmt.Println("  ❌ Missing required dependencies:")
 				for _, req := range status.Missing {
@@ -260,6 +356,14 @@ func (rc *RequirementChecker) PrintRequirementReport(results map[string]Requirem
 			}
 		}
 		
+		found := make(map[string]bool)
+		for _, req := range rc.requirements[format] {
+			if tool, ok := status.Detected[req.Name]; ok && !found[tool.String()] {
+				found[tool.String()] = true
+				fmt.Printf("  🔍 Found %s\n", tool)
+			}
+		}
+		
 		if len(status.Instructions) > 0 {
 			fmt.Println("  📝 Installation instructions:")
 			for _, instruction := range status.Instructions {
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requirements
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// detectTimeout bounds how long a tool may take to print its version
+const detectTimeout = 10 * time.Second
+
+// Version is a tool's major.minor.patch version
+type Version struct {
+	Major, Minor, Patch int
+}
+
+var versionPattern = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseVersion reads the first dotted version number in s, such as the
+// output of "docker version" or "wix --version"
+func ParseVersion(s string) (Version, bool) {
+	m := versionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, false
+	}
+	var v Version
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	v.Patch, _ = strconv.Atoi(m[3])
+	return v, true
+}
+
+// AtLeast reports whether v is min or newer
+func (v Version) AtLeast(min Version) bool {
+	if v.Major != min.Major {
+		return v.Major > min.Major
+	}
+	if v.Minor != min.Minor {
+		return v.Minor > min.Minor
+	}
+	return v.Patch >= min.Patch
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Tool is an installed tool and the version it reported
+type Tool struct {
+	Command string
+	Path    string
+	Version Version // zero when the tool did not print one
+}
+
+func (t Tool) String() string {
+	if t.Version == (Version{}) {
+		return t.Command
+	}
+	return t.Command + " " + t.Version.String()
+}
+
+// Detect looks command up on PATH and reads its version from the output of
+// running it with args. It reports false when command is not installed;
+// a tool that runs but prints no version is returned with a zero Version.
+func Detect(ctx context.Context, command string, args ...string) (Tool, bool) {
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return Tool{}, false
+	}
+	tool := Tool{Command: command, Path: path}
+
+	ctx, cancel := context.WithTimeout(ctx, detectTimeout)
+	defer cancel()
+	// Some tools, such as candle -?, exit non-zero after printing their
+	// version, so the output is read either way
+	output, _ := exec.CommandContext(ctx, path, args...).CombinedOutput()
+	tool.Version, _ = ParseVersion(string(output))
+	return tool, true
+}
+
+// DockerBuildxVersion is the first Docker release bagboy builds images
+// with buildx on; older releases use the classic builder
+var DockerBuildxVersion = Version{Major: 24}
+
+// Docker returns the installed Docker client
+func Docker(ctx context.Context) (Tool, bool) {
+	return Detect(ctx, "docker", "version", "--format", "{{.Client.Version}}")
+}
+
+// Buildx reports whether Docker is DockerBuildxVersion or newer and has
+// the buildx plugin
+func Buildx(ctx context.Context) bool {
+	docker, ok := Docker(ctx)
+	if !ok || !docker.Version.AtLeast(DockerBuildxVersion) {
+		return false
+	}
+	return exec.CommandContext(ctx, docker.Path, "buildx", "version").Run() == nil
+}
+
+// WiX returns the installed WiX Toolset. v4 and later ship a single wix
+// command; v3 ships candle and light, and is reported as candle.
+func WiX(ctx context.Context) (Tool, bool) {
+	if wix, ok := Detect(ctx, "wix", "--version"); ok {
+		if wix.Version.Major == 0 {
+			wix.Version.Major = 4
+		}
+		return wix, true
+	}
+	if _, err := exec.LookPath("light"); err != nil {
+		return Tool{}, false
+	}
+	candle, ok := Detect(ctx, "candle", "-?")
+	if ok && candle.Version.Major == 0 {
+		candle.Version.Major = 3
+	}
+	return candle, ok
+}
+
+// snapBases are the core bases and the oldest snapcraft supporting each,
+// newest first
+var snapBases = []struct {
+	Base      string
+	Snapcraft Version
+}{
+	{"core24", Version{Major: 8}},
+	{"core22", Version{Major: 7}},
+	{"core20", Version{Major: 4}},
+}
+
+// DefaultSnapBase is used when snapcraft is not installed
+const DefaultSnapBase = "core22"
+
+// SnapBase returns the newest core base the installed snapcraft supports
+func SnapBase(ctx context.Context) string {
+	snapcraft, ok := Detect(ctx, "snapcraft", "--version")
+	if !ok || snapcraft.Version == (Version{}) {
+		return DefaultSnapBase
+	}
+	return snapBaseFor(snapcraft.Version)
+}
+
+func snapBaseFor(snapcraft Version) string {
+	for _, base := range snapBases {
+		if snapcraft.AtLeast(base.Snapcraft) {
+			return base.Base
+		}
+	}
+	return snapBases[len(snapBases)-1].Base
+}
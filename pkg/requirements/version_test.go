@@ -0,0 +1,150 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requirements
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeTool writes an executable script named name to dir that prints
+// output and exits with code
+func fakeTool(t *testing.T, dir, name, output string, code int) {
+	t.Helper()
+	script := fmt.Sprintf("#!/bin/sh\necho '%s'\nexit %d\n", output, code)
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := map[string]Version{
+		"24.0.7":          {24, 0, 7},
+		"snapcraft 8.0.1": {8, 0, 1},
+		"4.0.4+a8592982":  {4, 0, 4},
+		"Windows Installer XML Toolset Compiler version 3.11.2.4516": {3, 11, 2},
+		"github.com/docker/buildx v0.11":                             {0, 11, 0},
+	}
+	for input, want := range tests {
+		got, ok := ParseVersion(input)
+		if !ok || got != want {
+			t.Errorf("ParseVersion(%q) = %v, %v; want %v", input, got, ok, want)
+		}
+	}
+	if _, ok := ParseVersion("no version here"); ok {
+		t.Error("ParseVersion should fail without a version")
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	v := Version{24, 0, 7}
+	for _, min := range []Version{{24, 0, 0}, {23, 9, 9}, {24, 0, 7}} {
+		if !v.AtLeast(min) {
+			t.Errorf("%v.AtLeast(%v) = false", v, min)
+		}
+	}
+	for _, min := range []Version{{25, 0, 0}, {24, 1, 0}, {24, 0, 8}} {
+		if v.AtLeast(min) {
+			t.Errorf("%v.AtLeast(%v) = true", v, min)
+		}
+	}
+}
+
+func TestSnapBaseFor(t *testing.T) {
+	tests := map[Version]string{
+		{8, 0, 1}: "core24",
+		{7, 5, 0}: "core22",
+		{6, 1, 0}: "core20",
+		{3, 0, 0}: "core20",
+	}
+	for version, want := range tests {
+		if got := snapBaseFor(version); got != want {
+			t.Errorf("snapBaseFor(%v) = %s, want %s", version, got, want)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PATH", dir)
+	ctx := context.Background()
+
+	if _, ok := Detect(ctx, "docker", "version"); ok {
+		t.Fatal("Detect found a tool that is not installed")
+	}
+
+	fakeTool(t, dir, "docker", "23.0.1", 0)
+	docker, ok := Docker(ctx)
+	if !ok || docker.Version != (Version{23, 0, 1}) || docker.String() != "docker 23.0.1" {
+		t.Errorf("Docker() = %+v, %v", docker, ok)
+	}
+	if Buildx(ctx) {
+		t.Error("Buildx() should be false before Docker 24")
+	}
+
+	fakeTool(t, dir, "docker", "24.0.7", 0)
+	if !Buildx(ctx) {
+		t.Error("Buildx() should be true on Docker 24 with buildx")
+	}
+}
+
+func TestWiX(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PATH", dir)
+	ctx := context.Background()
+
+	if _, ok := WiX(ctx); ok {
+		t.Fatal("WiX() found a toolset that is not installed")
+	}
+
+	// v3 prints its version from candle -? and exits non-zero
+	fakeTool(t, dir, "candle", "Windows Installer XML Toolset Compiler version 3.11.2.4516", 1)
+	fakeTool(t, dir, "light", "", 0)
+	wix, ok := WiX(ctx)
+	if !ok || wix.Command != "candle" || wix.Version.Major != 3 {
+		t.Errorf("WiX() = %+v, %v; want candle 3", wix, ok)
+	}
+
+	// v4 wins when both are installed
+	fakeTool(t, dir, "wix", "4.0.4+a8592982", 0)
+	wix, ok = WiX(ctx)
+	if !ok || wix.Command != "wix" || wix.Version != (Version{4, 0, 4}) {
+		t.Errorf("WiX() = %+v, %v; want wix 4.0.4", wix, ok)
+	}
+}
+
+func TestCheckFormatRequirements_Outdated(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PATH", dir)
+	fakeTool(t, dir, "snapcraft", "snapcraft 6.1", 0)
+
+	status := NewRequirementChecker().checkFormatRequirements("snap")
+	if status.Available || len(status.Outdated) != 1 || len(status.Missing) != 0 {
+		t.Errorf("snapcraft 6.1 should be reported outdated: %+v", status)
+	}
+	if tool := status.Detected["snapcraft"]; tool.Version != (Version{6, 1, 0}) {
+		t.Errorf("Detected snapcraft %v", tool)
+	}
+
+	fakeTool(t, dir, "snapcraft", "snapcraft 8.0.1", 0)
+	if status := NewRequirementChecker().checkFormatRequirements("snap"); !status.Available || len(status.Outdated) != 0 {
+		t.Errorf("snapcraft 8.0.1 should be accepted: %+v", status)
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sbom writes software bills of materials for a release's
+// binaries and packages in SPDX 2.3 and CycloneDX 1.5 JSON. The modules
+// compiled into Go binaries are read from their build info.
+package sbom
+
+import (
+	"crypto/sha256"
+	"debug/buildinfo"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// Document formats
+const (
+	SPDX      = "spdx"
+	CycloneDX = "cyclonedx"
+)
+
+// Formats are the document formats in the order they are written
+var Formats = []string{SPDX, CycloneDX}
+
+// Extensions are the file name suffixes of each format
+var Extensions = map[string]string{
+	SPDX:      ".spdx.json",
+	CycloneDX: ".cdx.json",
+}
+
+// Component is a piece of software an SBOM lists
+type Component struct {
+	Name    string
+	Version string
+	PURL    string // package URL, e.g. pkg:golang/golang.org/x/sys@v0.20.0
+	SHA256  string // hex digest, for files
+	License string // SPDX expression, when known
+}
+
+// Document describes one subject, a binary or a package, and the
+// components it contains
+type Document struct {
+	Subject    Component
+	Components []Component
+	Created    time.Time
+}
+
+// ForBinary describes the binary at path. For a Go binary the subject
+// carries the main module and the components are the modules compiled
+// in; other binaries list only themselves.
+func ForBinary(cfg *config.Config, path string) (Document, error) {
+	doc, err := forFile(cfg, path)
+	if err != nil {
+		return doc, err
+	}
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return doc, nil
+	}
+	if info.Main.Path != "" {
+		doc.Subject.PURL = purl(info.Main.Path, cfg.Version)
+	}
+	doc.Components = append(doc.Components, Component{
+		Name:    "stdlib",
+		Version: info.GoVersion,
+		PURL:    purl("stdlib", info.GoVersion),
+	})
+	for _, dep := range info.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		doc.Components = append(doc.Components, Component{Name: dep.Path, Version: dep.Version, PURL: purl(dep.Path, dep.Version)})
+	}
+	return doc, nil
+}
+
+// ForPackage describes the package at path containing binary, whose
+// components it lists. binary may be empty when the package holds no
+// single binary, such as a manifest.
+func ForPackage(cfg *config.Config, path, binary string) (Document, error) {
+	doc, err := forFile(cfg, path)
+	if err != nil || binary == "" {
+		return doc, err
+	}
+	contents, err := ForBinary(cfg, binary)
+	if err != nil {
+		return doc, err
+	}
+	contents.Subject.Name = cfg.Name
+	doc.Components = append([]Component{contents.Subject}, contents.Components...)
+	return doc, nil
+}
+
+func forFile(cfg *config.Config, path string) (Document, error) {
+	sum, err := fileSHA256(path)
+	if err != nil {
+		return Document{}, err
+	}
+	return Document{
+		Subject: Component{Name: filepath.Base(path), Version: cfg.Version, SHA256: sum, License: cfg.License},
+		Created: created(),
+	}, nil
+}
+
+// Write writes doc in each format to base plus the format's extension
+// and returns the files written
+func Write(doc Document, base string, formats []string) ([]string, error) {
+	if len(formats) == 0 {
+		formats = Formats
+	}
+	if err := os.MkdirAll(filepath.Dir(base), 0755); err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, format := range formats {
+		var data []byte
+		var err error
+		switch format {
+		case SPDX:
+			data, err = doc.SPDX()
+		case CycloneDX:
+			data, err = doc.CycloneDX()
+		default:
+			err = fmt.Errorf("unknown SBOM format %q (spdx or cyclonedx)", format)
+		}
+		if err != nil {
+			return nil, err
+		}
+		path := base + Extensions[format]
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return nil, err
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// Subject is a file to describe and the binary inside it, if any
+type Subject struct {
+	Path   string
+	Binary string
+}
+
+// Generate writes SBOMs in cfg's formats for every binary into dir, as
+// <name>-<platform>.spdx.json and so on, and for each subject next to its
+// file, as attest expects. It returns the files written.
+func Generate(cfg *config.Config, dir string, subjects []Subject) ([]string, error) {
+	platforms := make([]string, 0, len(cfg.Binaries))
+	for platform := range cfg.Binaries {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	var files []string
+	for _, platform := range platforms {
+		doc, err := ForBinary(cfg, cfg.Binaries[platform])
+		if err != nil {
+			return nil, err
+		}
+		written, err := Write(doc, filepath.Join(dir, cfg.Name+"-"+platform), cfg.SBOM.Formats)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, written...)
+	}
+	for _, subject := range subjects {
+		doc, err := ForPackage(cfg, subject.Path, subject.Binary)
+		if err != nil {
+			return nil, err
+		}
+		written, err := Write(doc, subject.Path, cfg.SBOM.Formats)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, written...)
+	}
+	return files, nil
+}
+
+// purl returns the Go package URL of a module version
+func purl(module, version string) string {
+	if version == "" || version == "(devel)" {
+		return "pkg:golang/" + module
+	}
+	return "pkg:golang/" + module + "@" + version
+}
+
+// created is the document time, from SOURCE_DATE_EPOCH when set so
+// rebuilt SBOMs are identical
+func created() time.Time {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC()
+	}
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// digest is a stable identifier for the document, for namespaces and
+// serial numbers
+func (d Document) digest() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n", d.Subject.Name, d.Subject.Version, d.Subject.SHA256)
+	for _, c := range d.Components {
+		fmt.Fprintf(h, "%s@%s\n", c.Name, c.Version)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// uuid formats the digest as an RFC 4122 name-based UUID
+func (d Document) uuid() string {
+	sum := d.digest()
+	b := []byte(sum[:32])
+	b[12] = '5'
+	b[16] = "89ab"[strings.IndexByte("0123456789abcdef", b[16])%4]
+	return fmt.Sprintf("%s-%s-%s-%s-%s", b[0:8], b[8:12], b[12:16], b[16:20], b[20:32])
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
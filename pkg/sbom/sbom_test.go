@@ -0,0 +1,154 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestForBinary(t *testing.T) {
+	// The test binary is a Go binary with build info
+	self, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	cfg := &config.Config{Name: "myapp", Version: "1.2.3", License: "MIT"}
+
+	doc, err := ForBinary(cfg, self)
+	if err != nil {
+		t.Fatalf("ForBinary() error = %v", err)
+	}
+	if doc.Subject.SHA256 == "" || doc.Subject.License != "MIT" {
+		t.Errorf("Subject = %+v", doc.Subject)
+	}
+	purls := make(map[string]bool)
+	for _, c := range doc.Components {
+		purls[strings.Split(c.PURL, "@")[0]] = true
+	}
+	for _, want := range []string{"pkg:golang/stdlib", "pkg:golang/gopkg.in/yaml.v3"} {
+		if !purls[want] {
+			t.Errorf("Components missing %s: %+v", want, doc.Components)
+		}
+	}
+
+	// Anything else lists only itself
+	script := filepath.Join(t.TempDir(), "myapp")
+	os.WriteFile(script, []byte("#!/bin/sh\n"), 0755)
+	doc, err = ForBinary(cfg, script)
+	if err != nil || len(doc.Components) != 0 {
+		t.Errorf("ForBinary(script) = %+v, %v; want no components", doc, err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "myapp")
+	pkg := filepath.Join(dir, "myapp_1.0.0_amd64.deb")
+	os.WriteFile(binary, []byte("binary"), 0755)
+	os.WriteFile(pkg, []byte("package"), 0644)
+
+	cfg := &config.Config{Name: "myapp", Version: "1.0.0", License: "Apache-2.0"}
+	doc, err := ForPackage(cfg, pkg, binary)
+	if err != nil {
+		t.Fatalf("ForPackage() error = %v", err)
+	}
+	files, err := Write(doc, pkg, nil)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if strings.Join(files, ",") != pkg+".spdx.json,"+pkg+".cdx.json" {
+		t.Errorf("Write() = %v", files)
+	}
+
+	var spdx struct {
+		SPDXVersion   string `json:"spdxVersion"`
+		CreationInfo  struct{ Created string }
+		Packages      []struct{ Name string }
+		Relationships []struct{ RelationshipType string }
+	}
+	data, _ := os.ReadFile(files[0])
+	if err := json.Unmarshal(data, &spdx); err != nil {
+		t.Fatalf("invalid SPDX: %v", err)
+	}
+	if spdx.SPDXVersion != "SPDX-2.3" || spdx.CreationInfo.Created != "2023-11-14T22:13:20Z" {
+		t.Errorf("SPDX header = %+v", spdx)
+	}
+	if len(spdx.Packages) != 2 || spdx.Packages[0].Name != "myapp_1.0.0_amd64.deb" || spdx.Packages[1].Name != "myapp" {
+		t.Errorf("SPDX packages = %+v, want the package containing the binary", spdx.Packages)
+	}
+	if spdx.Relationships[0].RelationshipType != "DESCRIBES" || spdx.Relationships[1].RelationshipType != "CONTAINS" {
+		t.Errorf("SPDX relationships = %+v", spdx.Relationships)
+	}
+
+	var cdx struct {
+		BOMFormat    string
+		SpecVersion  string
+		SerialNumber string
+		Metadata     struct{ Component struct{ Name string } }
+		Components   []struct{ Type, Name string }
+	}
+	data, _ = os.ReadFile(files[1])
+	if err := json.Unmarshal(data, &cdx); err != nil {
+		t.Fatalf("invalid CycloneDX: %v", err)
+	}
+	if cdx.BOMFormat != "CycloneDX" || cdx.SpecVersion != "1.5" || !strings.HasPrefix(cdx.SerialNumber, "urn:uuid:") {
+		t.Errorf("CycloneDX header = %+v", cdx)
+	}
+	if cdx.Metadata.Component.Name != "myapp_1.0.0_amd64.deb" || len(cdx.Components) != 1 || cdx.Components[0].Type != "application" {
+		t.Errorf("CycloneDX components = %+v", cdx)
+	}
+
+	// Rewriting gives identical documents
+	first, _ := os.ReadFile(files[0])
+	Write(doc, pkg, []string{SPDX})
+	if second, _ := os.ReadFile(files[0]); !bytes.Equal(first, second) {
+		t.Error("SPDX output is not reproducible")
+	}
+
+	if _, err := Write(doc, pkg, []string{"swid"}); err == nil {
+		t.Error("expected an unknown format error")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	linux := filepath.Join(dir, "myapp-linux")
+	pkg := filepath.Join(dir, "pkgs", "myapp.rpm")
+	os.WriteFile(linux, []byte("binary"), 0755)
+	os.MkdirAll(filepath.Dir(pkg), 0755)
+	os.WriteFile(pkg, []byte("package"), 0644)
+
+	cfg := &config.Config{Name: "myapp", Version: "1.0.0", Binaries: map[string]string{"linux-amd64": linux}}
+	cfg.SBOM.Formats = []string{CycloneDX}
+
+	files, err := Generate(cfg, filepath.Join(dir, "dist"), []Subject{{Path: pkg, Binary: linux}})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	want := []string{filepath.Join(dir, "dist", "myapp-linux-amd64.cdx.json"), pkg + ".cdx.json"}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("Generate() = %v, want %v", files, want)
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schedule supports release trains: preparing a release ahead of
+// time and finalizing it at a scheduled moment.
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// timeLayouts are the accepted --at formats, tried in order
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// ParseTime parses a scheduled publish time. Times without a zone are
+// interpreted in loc. The time must be after now.
+func ParseTime(value string, now time.Time, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range timeLayouts {
+		at, err := time.ParseInLocation(layout, value, loc)
+		if err != nil {
+			continue
+		}
+		if !at.After(now) {
+			return time.Time{}, fmt.Errorf("scheduled time %s is in the past", at.Format(time.RFC3339))
+		}
+		return at, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (expected RFC 3339, e.g. 2026-03-01T09:00Z)", value)
+}
+
+// StagingBranch returns the branch used to stage tap and bucket updates
+// for a scheduled release
+func StagingBranch(cfg *config.Config) string {
+	return fmt.Sprintf("bagboy/release-v%s", cfg.Version)
+}
+
+// Wait blocks until at, printing a reminder every interval. It returns
+// early with the context error if ctx is cancelled.
+func Wait(ctx context.Context, at time.Time, interval time.Duration) error {
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		case <-ticker.C:
+			fmt.Printf("⏳ Publishing in %s\n", time.Until(at).Round(time.Second))
+		}
+	}
+}
+
+// Cron returns a GitHub Actions cron expression firing at the given time.
+// Actions schedules are evaluated in UTC.
+func Cron(at time.Time) string {
+	at = at.UTC()
+	return fmt.Sprintf("%d %d %d %d *", at.Minute(), at.Hour(), at.Day(), int(at.Month()))
+}
+
+// WorkflowPath returns where the scheduled release workflow is written
+func WorkflowPath(cfg *config.Config) string {
+	return filepath.Join(".github", "workflows", fmt.Sprintf("bagboy-release-v%s.yml", cfg.Version))
+}
+
+// WriteWorkflow generates a GitHub Actions workflow that finalizes the
+// release at the scheduled time. Actions may start scheduled jobs a few
+// minutes late under load.
+func WriteWorkflow(path string, cfg *config.Config, at time.Time) error {
+	tmpl := `# Generated by bagboy - finalizes the scheduled release of {{.Name}} v{{.Version}}
+# Scheduled for {{.At}}. Remove this workflow once the release is out.
+name: Scheduled release v{{.Version}}
+
+on:
+  schedule:
+    - cron: '{{.Cron}}'
+  workflow_dispatch:
+
+jobs:
+  publish:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@v5
+        with:
+          go-version: stable
+      - name: Install bagboy
+        run: go install github.com/scttfrdmn/bagboy/cmd/bagboy@latest
+      - name: Finalize release
+        # Cron has no year field, so guard against firing again next year
+        run: |
+          if [ "${{"{{"}} github.event_name {{"}}"}}" = "schedule" ] && [ "$(date -u +%Y)" != "{{.Year}}" ]; then
+            echo "Not scheduled for this year"
+            exit 0
+          fi
+          bagboy publish --finalize
+        env:
+          {{.TokenEnv}}: ${{"{{"}} secrets.{{.TokenEnv}} {{"}}"}}
+`
+
+	tokenEnv := cfg.GitHub.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = "GITHUB_TOKEN"
+	}
+
+	t, err := template.New("workflow").Parse(tmpl)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return t.Execute(f, struct {
+		Name     string
+		Version  string
+		At       string
+		Year     int
+		Cron     string
+		TokenEnv string
+	}{cfg.Name, cfg.Version, at.UTC().Format(time.RFC3339), at.UTC().Year(), Cron(at), tokenEnv})
+}
+
+// Poll calls done every interval until it reports true. It returns early
+// with done's error, or the context error if ctx is cancelled.
+func Poll(ctx context.Context, interval time.Duration, done func(context.Context) (bool, error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		ok, err := done(ctx)
+		if err != nil || ok {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// PromoteWorkflowPath returns where the manifest promotion workflow is
+// written
+func PromoteWorkflowPath() string {
+	return filepath.Join(".github", "workflows", "bagboy-promote.yml")
+}
+
+// WritePromoteWorkflow generates a GitHub Actions workflow that runs
+// bagboy promote when a release is published or a prerelease is promoted
+// to a full release, merging the manifest pull requests held back for it
+func WritePromoteWorkflow(path string, cfg *config.Config) error {
+	tmpl := `# Generated by bagboy - merges the tap and bucket pull requests held back
+# for a release as soon as it is published
+name: Promote manifests
+
+on:
+  release:
+    types: [released]
+  workflow_dispatch:
+
+jobs:
+  promote:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          fetch-depth: 0
+      - uses: actions/setup-go@v5
+        with:
+          go-version: stable
+      - name: Install bagboy
+        run: go install github.com/scttfrdmn/bagboy/cmd/bagboy@latest
+      - name: Promote manifests
+        run: bagboy promote
+        env:
+          {{.TokenEnv}}: ${{"{{"}} secrets.{{.TokenEnv}} {{"}}"}}
+`
+	tokenEnv := cfg.GitHub.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = "GITHUB_TOKEN"
+	}
+
+	t, err := template.New("workflow").Parse(tmpl)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return t.Execute(f, struct{ TokenEnv string }{tokenEnv})
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	expected := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"2026-03-01T09:00Z", false},
+		{"2026-03-01T09:00:00Z", false},
+		{"2026-03-01T10:00:00+01:00", false},
+		{"2026-03-01T09:00", false},
+		{"2026-03-01 09:00", false},
+		{"2025-03-01T09:00Z", true},
+		{"next tuesday", true},
+	}
+
+	for _, tt := range tests {
+		at, err := ParseTime(tt.value, now, time.UTC)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTime(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !at.Equal(expected) {
+			t.Errorf("ParseTime(%q) = %v, expected %v", tt.value, at, expected)
+		}
+	}
+}
+
+func TestCron(t *testing.T) {
+	at := time.Date(2026, 3, 1, 10, 30, 0, 0, time.FixedZone("CET", 3600))
+	if got := Cron(at); got != "30 9 1 3 *" {
+		t.Errorf("Cron() = %s, expected '30 9 1 3 *'", got)
+	}
+}
+
+func TestStagingBranch(t *testing.T) {
+	cfg := &config.Config{Version: "1.2.0"}
+	if got := StagingBranch(cfg); got != "bagboy/release-v1.2.0" {
+		t.Errorf("StagingBranch() = %s", got)
+	}
+}
+
+func TestWait(t *testing.T) {
+	if err := Wait(context.Background(), time.Now().Add(10*time.Millisecond), time.Hour); err != nil {
+		t.Errorf("Wait() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Wait(ctx, time.Now().Add(time.Hour), time.Hour); err == nil {
+		t.Error("Expected error from cancelled context")
+	}
+}
+
+func TestWriteWorkflow(t *testing.T) {
+	cfg := &config.Config{
+		Name:    "testapp",
+		Version: "1.0.0",
+		GitHub:  config.GitHubConfig{TokenEnv: "RELEASE_TOKEN"},
+	}
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	path := filepath.Join(t.TempDir(), WorkflowPath(cfg))
+	if err := WriteWorkflow(path, cfg, at); err != nil {
+		t.Fatalf("WriteWorkflow failed: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read workflow: %v", err)
+	}
+
+	for _, want := range []string{
+		"cron: '0 9 1 3 *'",
+		"bagboy publish --finalize",
+		"RELEASE_TOKEN: ${{ secrets.RELEASE_TOKEN }}",
+		`!= "2026"`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Workflow missing %q:\n%s", want, content)
+		}
+	}
+}
+
+func TestPoll(t *testing.T) {
+	calls := 0
+	err := Poll(context.Background(), time.Millisecond, func(context.Context) (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Poll() = %v after %d calls, expected nil after 3", err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Poll(ctx, time.Hour, func(context.Context) (bool, error) { return false, nil }); err == nil {
+		t.Error("Expected error from cancelled context")
+	}
+}
+
+func TestWritePromoteWorkflow(t *testing.T) {
+	cfg := &config.Config{GitHub: config.GitHubConfig{TokenEnv: "RELEASE_TOKEN"}}
+	path := filepath.Join(t.TempDir(), PromoteWorkflowPath())
+	if err := WritePromoteWorkflow(path, cfg); err != nil {
+		t.Fatalf("WritePromoteWorkflow failed: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read workflow: %v", err)
+	}
+	for _, want := range []string{
+		"types: [released]",
+		"run: bagboy promote",
+		"RELEASE_TOKEN: ${{ secrets.RELEASE_TOKEN }}",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Workflow missing %q:\n%s", want, content)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package secrets scans generated manifests, scripts and Dockerfiles for
+// credentials that were templated into them by mistake, so they are caught
+// before the files are uploaded.
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// maxFileSize is the largest file scanned; anything bigger is a binary
+// or archive, not a generated text file
+const maxFileSize = 16 << 20
+
+// Finding is one suspected secret
+type Finding struct {
+	Path  string
+	Line  int
+	Rule  string
+	Match string // redacted
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s (%s)", f.Path, f.Line, f.Rule, f.Match)
+}
+
+type rule struct {
+	name    string
+	pattern *regexp.Regexp
+	group   int // submatch holding the secret, 0 for the whole match
+}
+
+var rules = []rule{
+	{"private key", regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )*PRIVATE KEY(?: BLOCK)?-----`), 0},
+	{"GitHub token", regexp.MustCompile(`\b(?:gh[opsur]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`), 0},
+	{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`), 0},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`), 0},
+	{"npm token", regexp.MustCompile(`\bnpm_[A-Za-z0-9]{36}\b`), 0},
+	{"PyPI token", regexp.MustCompile(`\bpypi-AgE[A-Za-z0-9_-]{50,}`), 0},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`), 0},
+	{"credentials in URL", regexp.MustCompile(`[a-z][a-z0-9+.-]*://[^/\s:@'"]+:([^/\s:@'"]+)@`), 1},
+	{"token in URL", regexp.MustCompile(`(?i)[?&](?:access_token|token|api_key|apikey|password|secret|sig)=([^&\s'"]{16,})`), 1},
+}
+
+// secretEnv matches the names of environment variables that hold secrets
+var secretEnv = regexp.MustCompile(`(?i)(TOKEN|SECRET|PASSWORD|PASSWD|API_?KEY|ACCESS_KEY|PRIVATE_KEY|CREDENTIALS)`)
+
+// Scanner finds secrets in files
+type Scanner struct {
+	allow  []*regexp.Regexp
+	ignore []string
+	values map[string]string // secret value -> environment variable
+}
+
+// NewScanner creates a scanner for cfg. Besides well-known token formats
+// it looks for the values of secret-looking environment variables, such
+// as GITHUB_TOKEN in CI, and of tokenEnv.
+func NewScanner(cfg *config.SecretsConfig, tokenEnv string) (*Scanner, error) {
+	s := &Scanner{ignore: cfg.Ignore, values: make(map[string]string)}
+	for _, pattern := range cfg.Allow {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("policy.secrets.allow: %w", err)
+		}
+		s.allow = append(s.allow, re)
+	}
+	for _, env := range os.Environ() {
+		name, value, _ := strings.Cut(env, "=")
+		if (secretEnv.MatchString(name) || name == tokenEnv) && len(value) >= 8 {
+			s.values[value] = name
+		}
+	}
+	return s, nil
+}
+
+// Scan checks the files and, recursively, the directories in paths.
+// Binary files are skipped.
+func (s *Scanner) Scan(paths []string) ([]Finding, error) {
+	seen := make(map[string]bool)
+	var findings []Finding
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != root && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if seen[path] || s.ignored(path) {
+				return nil
+			}
+			seen[path] = true
+			found, err := s.ScanFile(path)
+			findings = append(findings, found...)
+			return err
+		})
+		if err != nil {
+			return findings, err
+		}
+	}
+	return findings, nil
+}
+
+func (s *Scanner) ignored(path string) bool {
+	for _, pattern := range s.ignore {
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, filepath.Base(path)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ScanFile checks one file
+func (s *Scanner) ScanFile(path string) ([]Finding, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() || info.Size() > maxFileSize {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return nil, nil
+	}
+	return s.scan(path, string(data)), nil
+}
+
+func (s *Scanner) scan(path, content string) []Finding {
+	var findings []Finding
+	for i, line := range strings.Split(content, "\n") {
+		for _, r := range rules {
+			for _, m := range r.pattern.FindAllStringSubmatch(line, -1) {
+				secret := m[r.group]
+				if placeholder(secret) || s.allowed(m[0]) {
+					continue
+				}
+				findings = append(findings, Finding{Path: path, Line: i + 1, Rule: r.name, Match: redact(secret)})
+			}
+		}
+		for value, name := range s.values {
+			if strings.Contains(line, value) && !s.allowed(value) {
+				findings = append(findings, Finding{Path: path, Line: i + 1, Rule: "value of $" + name, Match: redact(value)})
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].Rule < findings[j].Rule
+	})
+	return findings
+}
+
+func (s *Scanner) allowed(match string) bool {
+	for _, re := range s.allow {
+		if re.MatchString(match) {
+			return true
+		}
+	}
+	return false
+}
+
+// placeholder reports whether secret is a template reference filled in at
+// install time rather than a value, e.g. $TOKEN or {{ .Token }}
+func placeholder(secret string) bool {
+	return strings.HasPrefix(secret, "$") || strings.HasPrefix(secret, "{{") || strings.HasPrefix(secret, "%") || strings.HasPrefix(secret, "<")
+}
+
+// redact keeps enough of a secret to recognise it
+func redact(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", 8)
+}
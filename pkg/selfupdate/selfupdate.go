@@ -0,0 +1,315 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package selfupdate replaces the running bagboy with an official
+// release. The release's checksums.txt must carry a signify (or legacy
+// minisign) signature by the release key before the binary it lists is
+// trusted.
+package selfupdate
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// The official releases
+const (
+	Owner = "scttfrdmn"
+	Repo  = "bagboy"
+)
+
+// PublicKey is the signify public key that signs the official releases'
+// checksums.txt. Release builds set it with
+// -X github.com/scttfrdmn/bagboy/pkg/selfupdate.PublicKey=<key>; other
+// builds need a key passed to selfupdate.
+var PublicKey string
+
+// maxDownload bounds how much of any one asset is read
+const maxDownload = 512 << 20
+
+// Updater finds and downloads releases of bagboy from GitHub
+type Updater struct {
+	HTTP        *http.Client
+	API         string // GitHub API base, https://api.github.com
+	DownloadURL string // release download base, https://github.com
+	Owner       string
+	Repo        string
+	PublicKey   string // signify public key, as the .pub file or its key line
+}
+
+// New returns an updater for the official releases
+func New() *Updater {
+	return &Updater{
+		HTTP:        http.DefaultClient,
+		API:         "https://api.github.com",
+		DownloadURL: "https://github.com",
+		Owner:       Owner,
+		Repo:        Repo,
+		PublicKey:   PublicKey,
+	}
+}
+
+// AssetName is the release asset of the binary for platform, such as
+// bagboy-linux-amd64 or bagboy-windows-amd64.exe
+func AssetName(platform string) string {
+	name := "bagboy-" + platform
+	if strings.HasPrefix(platform, "windows-") {
+		name += ".exe"
+	}
+	return name
+}
+
+// Platform is the os-arch of the running binary
+func Platform() string {
+	return runtime.GOOS + "-" + runtime.GOARCH
+}
+
+// Latest returns the tag of the latest release, which GitHub takes to be
+// the newest that is neither a draft nor a prerelease
+func (u *Updater) Latest(ctx context.Context) (string, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/latest", strings.TrimSuffix(u.API, "/"), u.Owner, u.Repo)
+	body, err := u.get(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.Unmarshal(body, &release); err != nil {
+		return "", fmt.Errorf("failed to read latest release: %w", err)
+	}
+	if release.TagName == "" {
+		return "", fmt.Errorf("latest release of %s/%s has no tag", u.Owner, u.Repo)
+	}
+	return release.TagName, nil
+}
+
+// Download fetches the binary for platform from the release tagged tag and
+// returns it once checksums.txt is signed by the public key and lists the
+// binary's SHA-256
+func (u *Updater) Download(ctx context.Context, tag, platform string) ([]byte, error) {
+	if strings.TrimSpace(u.PublicKey) == "" {
+		return nil, fmt.Errorf("no release public key - this build of bagboy was not built for self-update; pass --public-key")
+	}
+	key, err := ParsePublicKey(u.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	base := fmt.Sprintf("%s/%s/%s/releases/download/%s/", strings.TrimSuffix(u.DownloadURL, "/"), u.Owner, u.Repo, tag)
+	checksums, err := u.get(ctx, base+"checksums.txt")
+	if err != nil {
+		return nil, err
+	}
+	// signify signatures are .sig, minisign's .minisig
+	var signature []byte
+	for _, ext := range []string{".sig", ".minisig"} {
+		if signature, err = u.get(ctx, base+"checksums.txt"+ext); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("release %s has no checksums.txt signature: %w", tag, err)
+	}
+	if err := Verify(key, checksums, signature); err != nil {
+		return nil, fmt.Errorf("checksums.txt of %s: %w", tag, err)
+	}
+
+	asset := AssetName(platform)
+	want, ok := parseChecksums(string(checksums))[asset]
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s", tag, asset)
+	}
+	binary, err := u.get(ctx, base+asset)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(binary)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return nil, fmt.Errorf("%s SHA-256 is %s, checksums.txt says %s", asset, got, want)
+	}
+	return binary, nil
+}
+
+func (u *Updater) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(url, "/repos/") {
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	resp, err := u.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDownload+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxDownload {
+		return nil, fmt.Errorf("GET %s: larger than %d bytes", url, maxDownload)
+	}
+	return body, nil
+}
+
+// Replace writes binary over the executable at exe. The new file is
+// written beside it and renamed into place, so an interrupted update
+// leaves the old binary. Windows cannot replace a running executable, so
+// it is moved to exe.old first.
+func Replace(exe string, binary []byte) error {
+	dir := filepath.Dir(exe)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(exe)+".new-*")
+	if err != nil {
+		return fmt.Errorf("cannot write to %s: %w", dir, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp.Name(), exe); err != nil {
+			os.Rename(old, exe)
+			return err
+		}
+		return nil
+	}
+	return os.Rename(tmp.Name(), exe)
+}
+
+// signify and legacy minisign keys and signatures are base64 of a
+// two-byte algorithm, an eight-byte key number and the key or signature
+const (
+	algorithm    = "Ed"
+	keyNumLength = 8
+)
+
+// ParsePublicKey reads a signify or minisign public key, either a .pub
+// file or only its base64 line
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	data, err := decodeLine(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	if len(data) != 2+keyNumLength+ed25519.PublicKeySize || string(data[:2]) != algorithm {
+		return nil, fmt.Errorf("invalid public key: not a signify or minisign Ed25519 key")
+	}
+	return ed25519.PublicKey(data[2+keyNumLength:]), nil
+}
+
+// Verify checks a signify signature, or a minisign signature made with
+// -l, of message
+func Verify(key ed25519.PublicKey, message, signature []byte) error {
+	data, err := decodeLine(string(signature))
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	if len(data) != 2+keyNumLength+ed25519.SignatureSize {
+		return fmt.Errorf("invalid signature")
+	}
+	if string(data[:2]) == "ED" {
+		return fmt.Errorf("prehashed minisign signatures are not supported - sign with signify or minisign -l")
+	}
+	if string(data[:2]) != algorithm {
+		return fmt.Errorf("unsupported signature algorithm %q", data[:2])
+	}
+	if !ed25519.Verify(key, message, data[2+keyNumLength:]) {
+		return fmt.Errorf("signature verification failed")
+	}
+	return nil
+}
+
+// decodeLine decodes the first base64 line that is not a comment
+func decodeLine(s string) ([]byte, error) {
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.Contains(line, "comment:") {
+			continue
+		}
+		return base64.StdEncoding.DecodeString(line)
+	}
+	return nil, fmt.Errorf("empty")
+}
+
+// parseChecksums reads sha256sum output into file -> hex digest
+func parseChecksums(content string) map[string]string {
+	sums := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			sums[strings.TrimPrefix(fields[1], "*")] = fields[0]
+		}
+	}
+	return sums
+}
+
+// Managed reports the package manager that installed exe, when it is one
+// that should do the updating instead
+func Managed(exe string) string {
+	path := filepath.ToSlash(exe)
+	for marker, manager := range map[string]string{
+		"/Cellar/":          "Homebrew",
+		"/homebrew/":        "Homebrew",
+		"/scoop/apps/":      "Scoop",
+		"/chocolatey/":      "Chocolatey",
+		"/nix/store/":       "Nix",
+		"/snap/":            "Snap",
+		"/WinGet/Packages/": "winget",
+	} {
+		if strings.Contains(path, marker) {
+			return manager
+		}
+	}
+	return ""
+}
+
+// Current reports whether the executable at exe is already binary
+func Current(exe string, binary []byte) bool {
+	current, err := os.ReadFile(exe)
+	return err == nil && bytes.Equal(current, binary)
+}
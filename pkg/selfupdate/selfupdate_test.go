@@ -0,0 +1,175 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selfupdate
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// signifyKey returns a key pair in signify's format
+func signifyKey(t *testing.T) (string, ed25519.PrivateKey) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyNum := []byte("12345678")
+	pub := "untrusted comment: bagboy public key\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte(algorithm), keyNum...), public...)) + "\n"
+	return pub, private
+}
+
+func sign(private ed25519.PrivateKey, message []byte) []byte {
+	sig := ed25519.Sign(private, message)
+	return []byte("untrusted comment: verify with bagboy.pub\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte(algorithm), "12345678"...), sig...)) + "\n")
+}
+
+// release serves a fake GitHub with release v1.1.0 of bagboy
+func release(t *testing.T, private ed25519.PrivateKey, binary []byte) *httptest.Server {
+	t.Helper()
+	sum := sha256.Sum256(binary)
+	checksums := []byte(hex.EncodeToString(sum[:]) + "  bagboy-linux-amd64\n")
+	assets := map[string][]byte{
+		"checksums.txt":      checksums,
+		"checksums.txt.sig":  sign(private, checksums),
+		"bagboy-linux-amd64": binary,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/scttfrdmn/bagboy/releases/latest" {
+			w.Write([]byte(`{"tag_name": "v1.1.0"}`))
+			return
+		}
+		name, ok := strings.CutPrefix(r.URL.Path, "/scttfrdmn/bagboy/releases/download/v1.1.0/")
+		if content, found := assets[name]; ok && found {
+			w.Write(content)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testUpdater(server *httptest.Server, publicKey string) *Updater {
+	u := New()
+	u.HTTP = server.Client()
+	u.API = server.URL
+	u.DownloadURL = server.URL
+	u.PublicKey = publicKey
+	return u
+}
+
+func TestUpdater(t *testing.T) {
+	publicKey, private := signifyKey(t)
+	server := release(t, private, []byte("new bagboy"))
+	u := testUpdater(server, publicKey)
+	ctx := context.Background()
+
+	tag, err := u.Latest(ctx)
+	if err != nil || tag != "v1.1.0" {
+		t.Fatalf("Latest() = %q, %v", tag, err)
+	}
+
+	binary, err := u.Download(ctx, tag, "linux-amd64")
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if string(binary) != "new bagboy" {
+		t.Errorf("Download() = %q", binary)
+	}
+
+	if _, err := u.Download(ctx, tag, "darwin-arm64"); err == nil {
+		t.Error("Expected an error for a platform missing from checksums.txt")
+	}
+}
+
+func TestUpdater_Untrusted(t *testing.T) {
+	_, private := signifyKey(t)
+	otherKey, _ := signifyKey(t)
+	server := release(t, private, []byte("new bagboy"))
+
+	if _, err := testUpdater(server, otherKey).Download(context.Background(), "v1.1.0", "linux-amd64"); err == nil {
+		t.Error("Expected a release signed by another key to be rejected")
+	}
+	if _, err := testUpdater(server, "").Download(context.Background(), "v1.1.0", "linux-amd64"); err == nil {
+		t.Error("Expected an error without a public key")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	publicKey, private := signifyKey(t)
+	key, err := ParsePublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("checksums")
+
+	if err := Verify(key, message, sign(private, message)); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if err := Verify(key, []byte("tampered"), sign(private, message)); err == nil {
+		t.Error("Expected a tampered message to fail")
+	}
+
+	// minisign's default prehashed signatures need BLAKE2b
+	prehashed := ed25519.Sign(private, message)
+	line := base64.StdEncoding.EncodeToString(append(append([]byte("ED"), "12345678"...), prehashed...))
+	if err := Verify(key, message, []byte(line)); err == nil || !strings.Contains(err.Error(), "minisign -l") {
+		t.Errorf("Expected prehashed signatures to be refused, got %v", err)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "bagboy")
+	if err := os.WriteFile(exe, []byte("old bagboy"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Replace(exe, []byte("new bagboy")); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if !Current(exe, []byte("new bagboy")) {
+		t.Error("Executable not replaced")
+	}
+	entries, _ := os.ReadDir(filepath.Dir(exe))
+	if len(entries) != 1 {
+		t.Errorf("Temporary files left behind: %v", entries)
+	}
+}
+
+func TestManaged(t *testing.T) {
+	for exe, want := range map[string]string{
+		"/opt/homebrew/Cellar/bagboy/0.7.0/bin/bagboy":     "Homebrew",
+		"C:/Users/me/scoop/apps/bagboy/current/bagboy.exe": "Scoop",
+		"/usr/local/bin/bagboy":                            "",
+		"/home/me/go/bin/bagboy":                           "",
+	} {
+		if got := Managed(exe); got != want {
+			t.Errorf("Managed(%s) = %q, want %q", exe, got, want)
+		}
+	}
+}
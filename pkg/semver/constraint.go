@@ -0,0 +1,99 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Comparison operators a Constraint may use
+var operators = []string{">=", "<=", ">", "<", "="}
+
+// Comparison is one operator and version, such as >=1.2.0
+type Comparison struct {
+	Op      string
+	Version Version
+}
+
+// String formats the comparison as in a constraint, e.g. >=1.2.0
+func (c Comparison) String() string {
+	return c.Op + c.Version.String()
+}
+
+// Constraint is a comma-separated list of comparisons a version must all
+// satisfy, such as ">=1.2.0, <2"
+type Constraint []Comparison
+
+// ParseConstraint parses a constraint. A version without an operator
+// must match exactly; an empty constraint allows any version.
+func ParseConstraint(s string) (Constraint, error) {
+	var c Constraint
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		op := "="
+		for _, candidate := range operators {
+			if strings.HasPrefix(part, candidate) {
+				op = candidate
+				part = strings.TrimSpace(strings.TrimPrefix(part, candidate))
+				break
+			}
+		}
+		v, err := Parse(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint %q: %w", s, err)
+		}
+		c = append(c, Comparison{Op: op, Version: v})
+	}
+	return c, nil
+}
+
+// Check reports whether v satisfies every comparison
+func (c Constraint) Check(v Version) bool {
+	for _, comparison := range c {
+		n := Compare(v, comparison.Version)
+		var ok bool
+		switch comparison.Op {
+		case ">=":
+			ok = n >= 0
+		case "<=":
+			ok = n <= 0
+		case ">":
+			ok = n > 0
+		case "<":
+			ok = n < 0
+		default:
+			ok = n == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the constraint as ParseConstraint reads it
+func (c Constraint) String() string {
+	parts := make([]string, len(c))
+	for i, comparison := range c {
+		parts[i] = comparison.String()
+	}
+	return strings.Join(parts, ", ")
+}
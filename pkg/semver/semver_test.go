@@ -0,0 +1,141 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semver
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Version
+		wantErr  bool
+	}{
+		{"1.2.3", Version{Major: 1, Minor: 2, Patch: 3}, false},
+		{"v1.2.3", Version{Major: 1, Minor: 2, Patch: 3}, false},
+		{"1.2", Version{Major: 1, Minor: 2}, false},
+		{"1.2.3-rc.1", Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1"}, false},
+		{"1.2.3-beta+build.5", Version{Major: 1, Minor: 2, Patch: 3, Prerelease: "beta", Build: "build.5"}, false},
+		{"", Version{}, true},
+		{"1.2.3.4", Version{}, true},
+		{"1.x.3", Version{}, true},
+		{"1.2.3-", Version{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("Parse(%q) = %+v, expected %+v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	if got := MustParse("v1.2.3-rc.1+abc").String(); got != "1.2.3-rc.1+abc" {
+		t.Errorf("String() = %s", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// Ordered per the semver 2.0 precedence example
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.1.0",
+		"2.0.0",
+	}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		a, b := MustParse(ordered[i]), MustParse(ordered[i+1])
+		if Compare(a, b) != -1 || Compare(b, a) != 1 {
+			t.Errorf("Expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+
+	if Compare(MustParse("1.0.0+a"), MustParse("1.0.0+b")) != 0 {
+		t.Error("Build metadata should be ignored")
+	}
+}
+
+func TestLess(t *testing.T) {
+	versions := []string{"v1.10.0", "v1.2.0", "not-a-version", "v1.2.0-rc.1"}
+	sort.Slice(versions, func(i, j int) bool { return Less(versions[i], versions[j]) })
+
+	expected := []string{"not-a-version", "v1.2.0-rc.1", "v1.2.0", "v1.10.0"}
+	for i := range expected {
+		if versions[i] != expected[i] {
+			t.Fatalf("Sorted = %v, expected %v", versions, expected)
+		}
+	}
+}
+
+func TestIsPrerelease(t *testing.T) {
+	if !MustParse("1.0.0-rc.1").IsPrerelease() {
+		t.Error("Expected prerelease")
+	}
+	if MustParse("1.0.0+build").IsPrerelease() {
+		t.Error("Build metadata is not a prerelease")
+	}
+}
+
+func TestConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{">=1.2.0", "1.2.0", true},
+		{">=1.2.0", "1.10.0", true},
+		{">=1.2.0", "1.1.9", false},
+		{">=1.2, <2", "1.9.9", true},
+		{">=1.2, <2", "2.0.0", false},
+		{">1.2.0", "1.2.0", false},
+		{"<=1.2.0", "1.2.0-rc.1", true},
+		{"1.3.0", "1.3.0", true},
+		{"=1.3", "1.3.1", false},
+		{"", "0.0.1", true},
+	}
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+		if err != nil {
+			t.Fatalf("ParseConstraint(%q) error = %v", tt.constraint, err)
+		}
+		if got := c.Check(MustParse(tt.version)); got != tt.want {
+			t.Errorf("%q.Check(%s) = %v, want %v", tt.constraint, tt.version, got, tt.want)
+		}
+	}
+
+	if c, _ := ParseConstraint(">= 1.2 ,<2"); c.String() != ">=1.2.0, <2.0.0" {
+		t.Errorf("String() = %q", c.String())
+	}
+	if _, err := ParseConstraint(">=one"); err == nil {
+		t.Error("Expected an invalid version to fail")
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// SignChecksums signs the checksums file at path with GPG and cosign as
+// signing.checksums asks, and returns the signatures to publish with it
+func (s *Signer) SignChecksums(ctx context.Context, path string) ([]string, error) {
+	var files []string
+	if s.config.Signing.Checksums.GPG {
+		sigPath, err := s.signChecksumsGPG(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, sigPath)
+	}
+	if s.config.Signing.Checksums.Cosign {
+		bundle, err := s.signChecksumsCosign(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, bundle)
+	}
+	return files, nil
+}
+
+func (s *Signer) signChecksumsGPG(ctx context.Context, path string) (string, error) {
+	keyID := s.config.Signing.Linux.GPGKeyID
+	if keyID == "" {
+		keyID = os.Getenv("GPG_KEY_ID")
+	}
+	if keyID == "" {
+		return "", fmt.Errorf("signing.checksums.gpg needs signing.linux.gpg_key_id or GPG_KEY_ID")
+	}
+
+	sigPath := path + ".asc"
+	cmd := exec.CommandContext(ctx, "gpg", "--batch", "--yes", "--detach-sign", "--armor",
+		"--local-user", keyID, "--output", sigPath, path)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("gpg signing failed: %w\nOutput: %s", err, output)
+	}
+	return sigPath, nil
+}
+
+func (s *Signer) signChecksumsCosign(ctx context.Context, path string) (string, error) {
+	if _, err := exec.LookPath("cosign"); err != nil {
+		return "", fmt.Errorf("cosign not found - install with: go install github.com/sigstore/cosign/v2/cmd/cosign@latest")
+	}
+
+	bundle := path + ".sigstore.bundle"
+	cmd := exec.CommandContext(ctx, "cosign", "sign-blob", "--yes", "--bundle", bundle, path)
+	if s.config.Signing.Sigstore.OIDCIssuer != "" {
+		cmd.Env = append(os.Environ(), "COSIGN_OIDC_ISSUER="+s.config.Signing.Sigstore.OIDCIssuer)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("cosign signing failed: %w\nOutput: %s", err, output)
+	}
+	return bundle, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestSignChecksums(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts in place of gpg and cosign")
+	}
+
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "bin")
+	os.MkdirAll(bin, 0755)
+	// The fakes write a placeholder to the --output or --bundle path
+	script := "#!/bin/sh\nwhile [ $# -gt 0 ]; do case $1 in --output|--bundle) out=$2;; esac; shift; done\nprintf sig > \"$out\"\n"
+	os.WriteFile(filepath.Join(bin, "gpg"), []byte(script), 0755)
+	os.WriteFile(filepath.Join(bin, "cosign"), []byte(script), 0755)
+	t.Setenv("PATH", bin)
+	t.Setenv("GPG_KEY_ID", "")
+
+	checksums := filepath.Join(dir, ChecksumsFile)
+	os.WriteFile(checksums, []byte("abc  myapp.tar.gz\n"), 0644)
+
+	cfg := &config.Config{Name: "myapp"}
+	signer := NewSigner(cfg)
+	if files, err := signer.SignChecksums(context.Background(), checksums); err != nil || len(files) != 0 {
+		t.Errorf("SignChecksums() = %v, %v; want nothing signed by default", files, err)
+	}
+
+	cfg.Signing.Checksums = config.ChecksumSigningConfig{GPG: true, Cosign: true}
+	if _, err := signer.SignChecksums(context.Background(), checksums); err == nil || !strings.Contains(err.Error(), "gpg_key_id") {
+		t.Fatalf("expected missing GPG key error, got %v", err)
+	}
+
+	cfg.Signing.Linux.GPGKeyID = "ABCD1234"
+	files, err := signer.SignChecksums(context.Background(), checksums)
+	if err != nil {
+		t.Fatalf("SignChecksums failed: %v", err)
+	}
+	want := []string{checksums + ".asc", checksums + ".sigstore.bundle"}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("SignChecksums() = %v, want %v", files, want)
+	}
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			t.Errorf("signature not written: %v", err)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/release"
+)
+
+// Lightweight signing tools for signing.minisign
+const (
+	ToolMinisign = "minisign"
+	ToolSignify  = "signify"
+)
+
+// ChecksumsFile is the checksum list written and signed for each release
+const ChecksumsFile = "checksums.txt"
+
+// MinisignTool returns the configured tool, minisign by default
+func MinisignTool(cfg *config.Config) string {
+	if cfg.Signing.Minisign.Tool == "" {
+		return ToolMinisign
+	}
+	return cfg.Signing.Minisign.Tool
+}
+
+// minisignCommand finds the tool's executable. Debian and Ubuntu ship
+// signify as signify-openbsd.
+func minisignCommand(tool string) (string, error) {
+	names := []string{tool}
+	if tool == ToolSignify {
+		names = append(names, "signify-openbsd")
+	}
+	for _, name := range names {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s not found - install it from your package manager", tool)
+}
+
+// MinisignSignatureExt is the extension of signatures made by tool
+func MinisignSignatureExt(tool string) string {
+	if tool == ToolSignify {
+		return ".sig"
+	}
+	return ".minisig"
+}
+
+// MinisignPublicKey returns where the public key is kept and published from
+func MinisignPublicKey(cfg *config.Config) string {
+	if cfg.Signing.Minisign.PublicKey != "" {
+		return cfg.Signing.Minisign.PublicKey
+	}
+	dir := cfg.Signing.Keys.Dir
+	if dir == "" {
+		dir = "keys"
+	}
+	return filepath.Join(dir, cfg.Name+".pub")
+}
+
+// MinisignSecretKey returns the secret key path, outside the repository
+// by default so it is not committed
+func MinisignSecretKey(cfg *config.Config) (string, error) {
+	return secretKeyFor(cfg, MinisignTool(cfg))
+}
+
+func secretKeyFor(cfg *config.Config, tool string) (string, error) {
+	path := cfg.Signing.Minisign.SecretKey
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		if tool == ToolSignify {
+			return filepath.Join(home, ".signify", cfg.Name+".sec"), nil
+		}
+		return filepath.Join(home, ".minisign", cfg.Name+".key"), nil
+	}
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, path[2:])
+	}
+	return path, nil
+}
+
+// GenerateMinisignKey creates a key pair for tool, refusing to replace an
+// existing secret key. A minisign key is protected with
+// BAGBOY_MINISIGN_PASSWORD when set; signify keys are generated without a
+// passphrase so CI can use them.
+func GenerateMinisignKey(ctx context.Context, cfg *config.Config, tool string) (string, string, error) {
+	command, err := minisignCommand(tool)
+	if err != nil {
+		return "", "", err
+	}
+	secretKey, err := secretKeyFor(cfg, tool)
+	if err != nil {
+		return "", "", err
+	}
+	publicKey := MinisignPublicKey(cfg)
+	if _, err := os.Stat(secretKey); err == nil {
+		return "", "", fmt.Errorf("%s already exists - remove it to generate a new key", secretKey)
+	}
+	if err := os.MkdirAll(filepath.Dir(secretKey), 0700); err != nil {
+		return "", "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(publicKey), 0755); err != nil {
+		return "", "", err
+	}
+
+	var cmd *exec.Cmd
+	password := os.Getenv("BAGBOY_MINISIGN_PASSWORD")
+	if tool == ToolSignify {
+		cmd = exec.CommandContext(ctx, command, "-G", "-n", "-p", publicKey, "-s", secretKey,
+			"-c", cfg.Name+" release signing key")
+	} else if password != "" {
+		cmd = exec.CommandContext(ctx, command, "-G", "-p", publicKey, "-s", secretKey)
+		cmd.Stdin = strings.NewReader(password + "\n" + password + "\n")
+	} else {
+		cmd = exec.CommandContext(ctx, command, "-G", "-W", "-p", publicKey, "-s", secretKey)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", "", fmt.Errorf("%s key generation failed: %w\nOutput: %s", tool, err, output)
+	}
+	return publicKey, secretKey, nil
+}
+
+// SignWithMinisign writes a detached minisign or signify signature next
+// to path and returns its path
+func (s *Signer) SignWithMinisign(ctx context.Context, path string) (string, error) {
+	tool := MinisignTool(s.config)
+	command, err := minisignCommand(tool)
+	if err != nil {
+		return "", err
+	}
+	secretKey, cleanup, err := s.minisignSecretKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	sigPath := path + MinisignSignatureExt(tool)
+	var cmd *exec.Cmd
+	if tool == ToolSignify {
+		cmd = exec.CommandContext(ctx, command, "-S", "-s", secretKey, "-m", path, "-x", sigPath)
+	} else {
+		comment := fmt.Sprintf("%s %s %s", s.config.Name, s.config.Version, filepath.Base(path))
+		cmd = exec.CommandContext(ctx, command, "-S", "-s", secretKey, "-m", path, "-x", sigPath, "-t", comment)
+		if password := os.Getenv("BAGBOY_MINISIGN_PASSWORD"); password != "" {
+			cmd.Stdin = strings.NewReader(password + "\n")
+		}
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("%s signing failed: %w\nOutput: %s", tool, err, output)
+	}
+	return sigPath, nil
+}
+
+// minisignSecretKey returns the secret key file, writing
+// BAGBOY_MINISIGN_SECRET_KEY to a private temporary file when set
+func (s *Signer) minisignSecretKey(ctx context.Context) (string, func(), error) {
+	if key := os.Getenv("BAGBOY_MINISIGN_SECRET_KEY"); key != "" {
+		return tempKey(ctx, "minisign", "secret.key", key)
+	}
+
+	path, err := MinisignSecretKey(s.config)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", nil, fmt.Errorf("secret key %s not found - run 'bagboy keys generate --tool %s' or set BAGBOY_MINISIGN_SECRET_KEY", path, MinisignTool(s.config))
+	}
+	return path, func() {}, nil
+}
+
+// SignRelease writes checksums.txt for assets into dir, signs it and, with
+// signing.minisign.artifacts, every asset. It returns the files to publish
+// alongside the assets: the checksums, the signatures and the public key.
+func (s *Signer) SignRelease(ctx context.Context, assets []string, dir string) ([]string, error) {
+	publicKey := MinisignPublicKey(s.config)
+	if _, err := os.Stat(publicKey); err != nil {
+		return nil, fmt.Errorf("public key %s not found - run 'bagboy keys generate --tool %s'", publicKey, MinisignTool(s.config))
+	}
+
+	checksums := filepath.Join(dir, ChecksumsFile)
+	if err := release.WriteChecksums(checksums, assets); err != nil {
+		return nil, err
+	}
+
+	toSign := []string{checksums}
+	if s.config.Signing.Minisign.Artifacts {
+		toSign = append(toSign, assets...)
+	}
+	files := []string{checksums}
+	for _, path := range toSign {
+		sigPath, err := s.SignWithMinisign(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, sigPath)
+	}
+	return append(files, publicKey), nil
+}
+
+func (s *Signer) checkMinisign() SigningStatus {
+	tool := MinisignTool(s.config)
+	var issues []string
+	var steps []string
+
+	if _, err := minisignCommand(tool); err != nil {
+		issues = append(issues, tool+" not found")
+		steps = append(steps, "Install "+tool+" from your package manager")
+	}
+	if os.Getenv("BAGBOY_MINISIGN_SECRET_KEY") == "" {
+		if path, err := MinisignSecretKey(s.config); err != nil || !fileExists(path) {
+			issues = append(issues, "Secret key not found")
+			steps = append(steps, "Generate a key: bagboy keys generate --tool "+tool)
+		}
+	}
+	if !fileExists(MinisignPublicKey(s.config)) {
+		issues = append(issues, "Public key not found")
+		steps = append(steps, "Commit the public key from bagboy keys generate, or set signing.minisign.public_key")
+	}
+
+	return SigningStatus{
+		Platform:   strings.ToUpper(tool[:1]) + tool[1:],
+		Required:   false,
+		Available:  len(issues) == 0,
+		Issues:     issues,
+		SetupSteps: steps,
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
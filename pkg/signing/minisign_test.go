@@ -0,0 +1,93 @@
+package signing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestMinisignKeyPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := &config.Config{Name: "myapp"}
+	if got := MinisignPublicKey(cfg); got != filepath.Join("keys", "myapp.pub") {
+		t.Errorf("public key = %q", got)
+	}
+	if got, _ := MinisignSecretKey(cfg); got != filepath.Join(home, ".minisign", "myapp.key") {
+		t.Errorf("minisign secret key = %q", got)
+	}
+
+	cfg.Signing.Minisign.Tool = ToolSignify
+	if got, _ := MinisignSecretKey(cfg); got != filepath.Join(home, ".signify", "myapp.sec") {
+		t.Errorf("signify secret key = %q", got)
+	}
+
+	cfg.Signing.Keys.Dir = "sigs"
+	cfg.Signing.Minisign.SecretKey = "~/keys/release.sec"
+	if got := MinisignPublicKey(cfg); got != filepath.Join("sigs", "myapp.pub") {
+		t.Errorf("public key with keys dir = %q", got)
+	}
+	if got, _ := MinisignSecretKey(cfg); got != filepath.Join(home, "keys", "release.sec") {
+		t.Errorf("expanded secret key = %q", got)
+	}
+}
+
+func TestMinisignSignatureExt(t *testing.T) {
+	if ext := MinisignSignatureExt(ToolMinisign); ext != ".minisig" {
+		t.Errorf("minisign ext = %q", ext)
+	}
+	if ext := MinisignSignatureExt(ToolSignify); ext != ".sig" {
+		t.Errorf("signify ext = %q", ext)
+	}
+}
+
+func TestSignRelease(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script in place of minisign")
+	}
+
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "bin")
+	os.MkdirAll(bin, 0755)
+	// The fake minisign writes a placeholder to the -x signature path
+	script := "#!/bin/sh\nwhile [ $# -gt 0 ]; do case $1 in -x) x=$2;; esac; shift; done\nprintf sig > \"$x\"\n"
+	os.WriteFile(filepath.Join(bin, "minisign"), []byte(script), 0755)
+	t.Setenv("PATH", bin)
+	t.Setenv("BAGBOY_MINISIGN_SECRET_KEY", "secret")
+
+	asset := filepath.Join(dir, "myapp.tar.gz")
+	os.WriteFile(asset, []byte("data"), 0644)
+
+	cfg := &config.Config{Name: "myapp", Version: "1.0.0"}
+	cfg.Signing.Minisign.PublicKey = filepath.Join(dir, "myapp.pub")
+	signer := NewSigner(cfg)
+
+	if _, err := signer.SignRelease(context.Background(), []string{asset}, dir); err == nil ||
+		!strings.Contains(err.Error(), "public key") {
+		t.Fatalf("expected missing public key error, got %v", err)
+	}
+
+	os.WriteFile(cfg.Signing.Minisign.PublicKey, []byte("untrusted comment: key\nRWQ\n"), 0644)
+	cfg.Signing.Minisign.Artifacts = true
+	files, err := signer.SignRelease(context.Background(), []string{asset}, dir)
+	if err != nil {
+		t.Fatalf("SignRelease failed: %v", err)
+	}
+
+	checksums := filepath.Join(dir, ChecksumsFile)
+	want := []string{checksums, checksums + ".minisig", asset + ".minisig", cfg.Signing.Minisign.PublicKey}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("files = %v, want %v", files, want)
+	}
+	for _, f := range want {
+		if _, err := os.Stat(f); err != nil {
+			t.Errorf("%s not written: %v", f, err)
+		}
+	}
+}
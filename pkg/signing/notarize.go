@@ -0,0 +1,214 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Notarization statuses reported by notarytool
+const (
+	NotarizationInProgress = "In Progress"
+	NotarizationAccepted   = "Accepted"
+)
+
+// Submission is a notarization request for one signed binary
+type Submission struct {
+	ID        string    `json:"id"`
+	Artifact  string    `json:"artifact"`
+	SHA256    string    `json:"sha256"`
+	Status    string    `json:"status"`
+	Submitted time.Time `json:"submitted"`
+}
+
+// NotarizationCache remembers submissions by the SHA-256 of the binary,
+// so an unchanged binary is never submitted to Apple twice
+type NotarizationCache struct {
+	path        string
+	Submissions map[string]Submission `json:"submissions"`
+}
+
+// NotarizationCachePath returns the file submissions are kept in, or ""
+// when BAGBOY_NO_CACHE is set. BAGBOY_CACHE_DIR overrides the user cache
+// directory.
+func NotarizationCachePath() string {
+	if os.Getenv("BAGBOY_NO_CACHE") != "" {
+		return ""
+	}
+	if dir := os.Getenv("BAGBOY_CACHE_DIR"); dir != "" {
+		return filepath.Join(dir, "notarization.json")
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "bagboy", "notarization.json")
+}
+
+// LoadNotarizationCache reads the submissions recorded so far
+func LoadNotarizationCache() (*NotarizationCache, error) {
+	cache := &NotarizationCache{path: NotarizationCachePath(), Submissions: map[string]Submission{}}
+	if cache.path == "" {
+		return cache, nil
+	}
+	data, err := os.ReadFile(cache.path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("invalid notarization cache %s: %w", cache.path, err)
+	}
+	if cache.Submissions == nil {
+		cache.Submissions = map[string]Submission{}
+	}
+	return cache, nil
+}
+
+// Lookup returns the submission for a binary's SHA-256
+func (c *NotarizationCache) Lookup(sum string) (Submission, bool) {
+	submission, ok := c.Submissions[sum]
+	return submission, ok
+}
+
+// Record stores a submission and saves the cache
+func (c *NotarizationCache) Record(submission Submission) error {
+	c.Submissions[submission.SHA256] = submission
+	if c.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	tmp := fmt.Sprintf("%s.%d", c.path, os.Getpid())
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+// List returns the submissions, newest first
+func (c *NotarizationCache) List() []Submission {
+	submissions := make([]Submission, 0, len(c.Submissions))
+	for _, submission := range c.Submissions {
+		submissions = append(submissions, submission)
+	}
+	sort.Slice(submissions, func(i, j int) bool {
+		return submissions[i].Submitted.After(submissions[j].Submitted)
+	})
+	return submissions
+}
+
+// notarized reports whether binaryPath was already notarized or is still
+// being notarized, in which case it is signed already and must not be
+// signed again
+func notarized(binaryPath string) (Submission, bool) {
+	sum, err := fileSHA256(binaryPath)
+	if err != nil {
+		return Submission{}, false
+	}
+	cache, err := LoadNotarizationCache()
+	if err != nil {
+		return Submission{}, false
+	}
+	submission, ok := cache.Lookup(sum)
+	if !ok || (submission.Status != NotarizationAccepted && submission.Status != NotarizationInProgress) {
+		return Submission{}, false
+	}
+	return submission, true
+}
+
+// notarytool runs xcrun notarytool with the Apple ID credentials and
+// decodes its JSON output
+func notarytool(ctx context.Context, args ...string) (Submission, error) {
+	args = append([]string{"notarytool"}, args...)
+	args = append(args,
+		"--apple-id", os.Getenv("APPLE_ID"),
+		"--password", os.Getenv("APPLE_APP_PASSWORD"),
+		"--team-id", os.Getenv("APPLE_TEAM_ID"),
+		"--output-format", "json")
+	cmd := exec.CommandContext(ctx, "xcrun", args...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			output = append(output, exitErr.Stderr...)
+		}
+		return Submission{}, fmt.Errorf("notarytool %s failed: %w\nOutput: %s", args[1], err, output)
+	}
+
+	var result struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return Submission{}, fmt.Errorf("unexpected notarytool output: %s", output)
+	}
+	return Submission{ID: result.ID, Status: result.Status}, nil
+}
+
+// NotarizationStatus refreshes the submissions still in progress from
+// Apple and returns every recorded submission
+func (s *Signer) NotarizationStatus(ctx context.Context) ([]Submission, error) {
+	cache, err := LoadNotarizationCache()
+	if err != nil {
+		return nil, err
+	}
+	for _, submission := range cache.List() {
+		if submission.Status != NotarizationInProgress {
+			continue
+		}
+		info, err := notarytool(ctx, "info", submission.ID)
+		if err != nil {
+			return nil, err
+		}
+		submission.Status = info.Status
+		if err := cache.Record(submission); err != nil {
+			return nil, err
+		}
+	}
+	return cache.List(), nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
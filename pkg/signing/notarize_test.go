@@ -0,0 +1,113 @@
+package signing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+// fakeNotarytool puts zip and xcrun scripts on PATH. xcrun logs each
+// notarytool subcommand to the returned file and reports submission abc.
+func fakeNotarytool(t *testing.T, finalStatus string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts in place of zip and xcrun")
+	}
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "bin")
+	os.MkdirAll(bin, 0755)
+	log := filepath.Join(dir, "notarytool.log")
+
+	os.WriteFile(filepath.Join(bin, "zip"), []byte("#!/bin/sh\n: > \"$2\"\n"), 0755)
+	xcrun := "#!/bin/sh\necho \"$2\" >> " + log + "\n" +
+		"case $2 in submit) status='In Progress';; *) status='" + finalStatus + "';; esac\n" +
+		"echo \"{\\\"id\\\": \\\"abc\\\", \\\"status\\\": \\\"$status\\\"}\"\n"
+	os.WriteFile(filepath.Join(bin, "xcrun"), []byte(xcrun), 0755)
+
+	t.Setenv("PATH", bin)
+	t.Setenv("APPLE_ID", "dev@example.com")
+	t.Setenv("APPLE_APP_PASSWORD", "password")
+	t.Setenv("BAGBOY_CACHE_DIR", filepath.Join(dir, "cache"))
+	t.Setenv("BAGBOY_NO_CACHE", "")
+	return log
+}
+
+func readLog(path string) string {
+	data, _ := os.ReadFile(path)
+	return strings.Join(strings.Fields(string(data)), " ")
+}
+
+func TestNotarizeSkipsUnchangedBinaries(t *testing.T) {
+	log := fakeNotarytool(t, NotarizationAccepted)
+	binary := filepath.Join(t.TempDir(), "myapp-darwin-arm64")
+	os.WriteFile(binary, []byte("signed binary"), 0755)
+	signer := NewSigner(&config.Config{Name: "myapp"})
+	ctx := context.Background()
+
+	if err := signer.notarizeMacOSBinary(ctx, binary); err != nil {
+		t.Fatalf("notarizeMacOSBinary failed: %v", err)
+	}
+	if got := readLog(log); got != "submit wait" {
+		t.Errorf("Expected submit and wait, got %q", got)
+	}
+
+	if err := signer.notarizeMacOSBinary(ctx, binary); err != nil {
+		t.Fatalf("notarizeMacOSBinary failed: %v", err)
+	}
+	if got := readLog(log); got != "submit wait" {
+		t.Errorf("Unchanged binary should not be resubmitted, got %q", got)
+	}
+	if submission, ok := notarized(binary); !ok || submission.ID != "abc" {
+		t.Errorf("Expected recorded submission abc, got %+v", submission)
+	}
+
+	os.WriteFile(binary, []byte("rebuilt binary"), 0755)
+	if err := signer.notarizeMacOSBinary(ctx, binary); err != nil {
+		t.Fatalf("notarizeMacOSBinary failed: %v", err)
+	}
+	if got := readLog(log); got != "submit wait submit wait" {
+		t.Errorf("Changed binary should be resubmitted, got %q", got)
+	}
+}
+
+func TestNotarizeWaitsForPendingSubmission(t *testing.T) {
+	log := fakeNotarytool(t, "Invalid")
+	binary := filepath.Join(t.TempDir(), "myapp-darwin-arm64")
+	os.WriteFile(binary, []byte("signed binary"), 0755)
+	sum, _ := fileSHA256(binary)
+
+	cache, _ := LoadNotarizationCache()
+	cache.Record(Submission{ID: "abc", Artifact: binary, SHA256: sum, Status: NotarizationInProgress, Submitted: time.Now()})
+
+	err := NewSigner(nil).notarizeMacOSBinary(context.Background(), binary)
+	if err == nil || !strings.Contains(err.Error(), "notarytool log abc") {
+		t.Errorf("Expected an Invalid notarization error, got %v", err)
+	}
+	if got := readLog(log); got != "wait" {
+		t.Errorf("Expected to wait on the pending submission, got %q", got)
+	}
+}
+
+func TestNotarizationStatus(t *testing.T) {
+	log := fakeNotarytool(t, NotarizationAccepted)
+	cache, _ := LoadNotarizationCache()
+	cache.Record(Submission{ID: "abc", Artifact: "dist/a", SHA256: "1", Status: NotarizationInProgress, Submitted: time.Now()})
+	cache.Record(Submission{ID: "old", Artifact: "dist/b", SHA256: "2", Status: NotarizationAccepted, Submitted: time.Now().Add(-time.Hour)})
+
+	submissions, err := NewSigner(nil).NotarizationStatus(context.Background())
+	if err != nil {
+		t.Fatalf("NotarizationStatus failed: %v", err)
+	}
+	if len(submissions) != 2 || submissions[0].ID != "abc" || submissions[0].Status != NotarizationAccepted {
+		t.Errorf("Unexpected submissions %+v", submissions)
+	}
+	if got := readLog(log); got != "info" {
+		t.Errorf("Only the pending submission should be queried, got %q", got)
+	}
+}
@@ -24,8 +24,10 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/paths"
 )
 
 // Signer handles code signing for different platforms
@@ -128,6 +130,16 @@ func (s *Signer) CheckSigningSetup() map[string]SigningStatus {
 		results["git"] = status
 	}
 
+	// Check minisign or signify
+	if s.config != nil && s.config.Signing.Minisign.Enabled {
+		results[MinisignTool(s.config)] = s.checkMinisign()
+	}
+
+	// Check SSH signing
+	if s.config != nil && s.config.Signing.SSH.Enabled {
+		results["ssh"] = s.checkSSH()
+	}
+
 	return results
 }
 
@@ -297,6 +309,13 @@ func (s *Signer) signMacOSBinary(ctx context.Context, binaryPath string) error {
 		return fmt.Errorf("APPLE_DEVELOPER_ID environment variable not set")
 	}
 	
+	// A binary notarized by an earlier run is signed already, and signing
+	// it again would change it and force a new submission
+	if submission, ok := notarized(binaryPath); ok && s.shouldNotarize() {
+		fmt.Printf("✅ Already signed: %s (notarization %s)\n", binaryPath, submission.ID)
+		return s.notarizeMacOSBinary(ctx, binaryPath)
+	}
+
 	// Sign the binary
 	cmd := exec.CommandContext(ctx, "codesign", 
 		"--sign", identity,
@@ -374,30 +393,76 @@ func (s *Signer) notarizeMacOSBinary(ctx context.Context, binaryPath string) err
 		return nil
 	}
 	
-	// Create a zip for notarization
-	zipPath := strings.TrimSuffix(binaryPath, filepath.Ext(binaryPath)) + ".zip"
-	zipCmd := exec.CommandContext(ctx, "zip", "-r", zipPath, binaryPath)
-	if err := zipCmd.Run(); err != nil {
-		return fmt.Errorf("failed to create zip for notarization: %w", err)
+	sum, err := fileSHA256(binaryPath)
+	if err != nil {
+		return err
 	}
-	defer os.Remove(zipPath)
-	
-	// Submit for notarization
-	fmt.Println("🔄 Submitting for notarization...")
-	cmd := exec.CommandContext(ctx, "xcrun", "notarytool", "submit", zipPath,
-		"--apple-id", appleID,
-		"--password", appPassword,
-		"--team-id", os.Getenv("APPLE_TEAM_ID"),
-		"--wait")
-	
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("notarization failed: %w\nOutput: %s", err, output)
+	cache, err := LoadNotarizationCache()
+	if err != nil {
+		return err
 	}
-	
+
+	submission, ok := cache.Lookup(sum)
+	switch {
+	case ok && submission.Status == NotarizationAccepted:
+		fmt.Printf("✅ Already notarized: %s (%s)\n", binaryPath, submission.ID)
+		return nil
+	case ok && submission.Status == NotarizationInProgress:
+		fmt.Printf("🔄 Waiting for earlier notarization %s...\n", submission.ID)
+	default:
+		if submission, err = s.submitNotarization(ctx, binaryPath, sum); err != nil {
+			return err
+		}
+		if err := cache.Record(submission); err != nil {
+			return err
+		}
+	}
+
+	result, err := notarytool(ctx, "wait", submission.ID)
+	if err != nil {
+		return err
+	}
+	submission.Status = result.Status
+	if err := cache.Record(submission); err != nil {
+		return err
+	}
+	if submission.Status != NotarizationAccepted {
+		return fmt.Errorf("notarization %s: %s - see xcrun notarytool log %s", submission.ID, submission.Status, submission.ID)
+	}
+
 	fmt.Printf("✅ Notarized macOS binary: %s\n", binaryPath)
 	return nil
 }
 
+// submitNotarization zips the binary and submits it without waiting, so
+// the submission is recorded even if this run is interrupted
+func (s *Signer) submitNotarization(ctx context.Context, binaryPath, sum string) (Submission, error) {
+	// Zip the binary in a directory of its own, so binaries signed in
+	// parallel never share a zip
+	dir, cleanup, err := workDir(ctx, "notarize")
+	if err != nil {
+		return Submission{}, err
+	}
+	defer cleanup()
+
+	zipPath := filepath.Join(dir, strings.TrimSuffix(filepath.Base(binaryPath), filepath.Ext(binaryPath))+".zip")
+	zipCmd := exec.CommandContext(ctx, "zip", "-j", zipPath, binaryPath)
+	if output, err := zipCmd.CombinedOutput(); err != nil {
+		return Submission{}, fmt.Errorf("failed to create zip for notarization: %w\nOutput: %s", err, output)
+	}
+
+	fmt.Println("🔄 Submitting for notarization...")
+	submission, err := notarytool(ctx, "submit", zipPath)
+	if err != nil {
+		return Submission{}, err
+	}
+	submission.Artifact = binaryPath
+	submission.SHA256 = sum
+	submission.Status = NotarizationInProgress
+	submission.Submitted = time.Now().UTC()
+	return submission, nil
+}
+
 // PrintSigningReport prints a formatted signing status report
 func (s *Signer) PrintSigningReport(results map[string]SigningStatus) {
 	fmt.Println("🔐 Code Signing Status Check")
@@ -438,6 +503,8 @@ func (s *Signer) PrintSigningReport(results map[string]SigningStatus) {
 	fmt.Println("   • Sigstore: Keyless signing with transparency log")
 	fmt.Println("   • SignPath.io: Cloud-based signing service")
 	fmt.Println("   • Git: Commit and tag verification")
+	fmt.Println("   • Minisign/signify: Small keys for verifying checksums without GPG")
+	fmt.Println("   • SSH: Sign releases and tags with your existing SSH key")
 }
 
 func (s *Signer) SignWithSigstore(ctx context.Context, binaryPath string) error {
@@ -525,12 +592,21 @@ func (s *Signer) SignWithSignPath(ctx context.Context, binaryPath string) error
 	}
 
 	// Wait for signing completion and download
-	signedPath, err := s.waitAndDownloadSigned(ctx, signingRequestID, binaryPath, apiToken)
+	dir, cleanup, err := workDir(ctx, "signpath")
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	signedPath, err := s.waitAndDownloadSigned(ctx, signingRequestID, binaryPath, dir, apiToken)
 	if err != nil {
 		return fmt.Errorf("failed to download signed binary: %w", err)
 	}
+	if err := replaceFile(signedPath, binaryPath); err != nil {
+		return fmt.Errorf("failed to replace %s with the signed binary: %w", binaryPath, err)
+	}
 
-	fmt.Printf("✅ Signed with SignPath.io: %s\n", signedPath)
+	fmt.Printf("✅ Signed with SignPath.io: %s\n", binaryPath)
 	return nil
 }
 
@@ -558,7 +634,7 @@ func (s *Signer) submitSignPathRequest(ctx context.Context, artifactID, apiToken
 	return "mock-signing-request-67890", nil
 }
 
-func (s *Signer) waitAndDownloadSigned(ctx context.Context, signingRequestID, originalPath, apiToken string) (string, error) {
+func (s *Signer) waitAndDownloadSigned(ctx context.Context, signingRequestID, originalPath, dir, apiToken string) (string, error) {
 	fmt.Printf("⏳ Waiting for SignPath.io signing completion...\n")
 	
 	// In production, would poll:
@@ -569,7 +645,7 @@ func (s *Signer) waitAndDownloadSigned(ctx context.Context, signingRequestID, or
 	// GET https://app.signpath.io/API/v1/{organizationId}/SigningRequests/{signingRequestId}/SignedArtifact
 	
 	// For now, just copy the original file to simulate signing
-	signedPath := originalPath + ".signpath-signed"
+	signedPath := filepath.Join(dir, filepath.Base(originalPath))
 	if err := s.copyFile(originalPath, signedPath); err != nil {
 		return "", err
 	}
@@ -579,11 +655,7 @@ func (s *Signer) waitAndDownloadSigned(ctx context.Context, signingRequestID, or
 }
 
 func (s *Signer) copyFile(src, dst string) error {
-	data, err := os.ReadFile(src)
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(dst, data, 0755)
+	return paths.CopyExecutable(src, dst, paths.CopyOptions{FollowSymlinks: true})
 }
 
 func (s *Signer) checkSigstore() SigningStatus {
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/scttfrdmn/bagboy/pkg/config"
 )
@@ -245,3 +246,61 @@ func TestSigningConfigValidation(t *testing.T) {
 		t.Error("Expected macOS signing status")
 	}
 }
+
+func TestSignWithSignPath_LeavesNothingBehind(t *testing.T) {
+	testDir := t.TempDir()
+	testBinary := filepath.Join(testDir, "testapp.exe")
+	os.WriteFile(testBinary, []byte("fake binary"), 0755)
+	t.Setenv("SIGNPATH_API_TOKEN", "token")
+
+	cfg := &config.Config{
+		Name:    "testapp",
+		Version: "1.0.0",
+		Signing: config.SigningConfig{
+			SignPath: config.SignPathConfig{Enabled: true, OrganizationID: "org", ProjectSlug: "app"},
+		},
+	}
+	if err := NewSigner(cfg).SignWithSignPath(context.Background(), testBinary); err != nil {
+		t.Fatalf("SignWithSignPath failed: %v", err)
+	}
+
+	entries, _ := os.ReadDir(testDir)
+	if len(entries) != 1 || entries[0].Name() != "testapp.exe" {
+		t.Errorf("Expected only the signed binary in %s, got %v", testDir, entries)
+	}
+	if info, _ := os.Stat(testBinary); info.Mode().Perm() != 0755 {
+		t.Errorf("Expected mode 0755 to be kept, got %v", info.Mode().Perm())
+	}
+}
+
+func TestWorkDir_RemovedOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	dir, cleanup, err := workDir(ctx, "test")
+	if err != nil {
+		t.Fatalf("workDir failed: %v", err)
+	}
+	defer cleanup()
+
+	other, otherCleanup, err := workDir(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("workDir failed: %v", err)
+	}
+	defer otherCleanup()
+	if dir == other {
+		t.Fatal("Expected a unique directory per operation")
+	}
+
+	cancel()
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected %s to be removed after cancellation", dir)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("Other operation's directory should remain: %v", err)
+	}
+}
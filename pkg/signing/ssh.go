@@ -0,0 +1,285 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/audit"
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/release"
+)
+
+// SSHNamespace is the ssh-keygen -Y namespace for release files. Git
+// signs tags in the "git" namespace.
+const SSHNamespace = "file"
+
+// SSHKey returns the SSH signing key, the first of ~/.ssh/id_ed25519,
+// id_ecdsa and id_rsa that exists unless signing.ssh.key is set
+func SSHKey(cfg *config.Config) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if key := cfg.Signing.SSH.Key; key != "" {
+		if strings.HasPrefix(key, "~/") {
+			key = filepath.Join(home, key[2:])
+		}
+		return key, nil
+	}
+	for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+		key := filepath.Join(home, ".ssh", name)
+		if fileExists(key) {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("no SSH key found in %s - set signing.ssh.key", filepath.Join(home, ".ssh"))
+}
+
+// SSHIdentity returns the principal recorded in allowed_signers
+func SSHIdentity(cfg *config.Config) (string, error) {
+	if cfg.Signing.SSH.Identity != "" {
+		return cfg.Signing.SSH.Identity, nil
+	}
+	for _, person := range cfg.Maintainers() {
+		if person.Email != "" {
+			return person.Email, nil
+		}
+	}
+	return "", fmt.Errorf("signing.ssh.identity is required (no maintainer email configured)")
+}
+
+// AllowedSignersPath returns where the allowed_signers file is kept and
+// published from
+func AllowedSignersPath(cfg *config.Config) string {
+	if cfg.Signing.SSH.AllowedSigners != "" {
+		return cfg.Signing.SSH.AllowedSigners
+	}
+	dir := cfg.Signing.Keys.Dir
+	if dir == "" {
+		dir = "keys"
+	}
+	return filepath.Join(dir, "allowed_signers")
+}
+
+// sshPublicKey returns the "<type> <key>" part of the public key for key,
+// read from key.pub or derived from the private key
+func sshPublicKey(ctx context.Context, key string) (string, error) {
+	var data []byte
+	if strings.HasSuffix(key, ".pub") {
+		var err error
+		if data, err = os.ReadFile(key); err != nil {
+			return "", err
+		}
+	} else if pub, err := os.ReadFile(key + ".pub"); err == nil {
+		data = pub
+	} else {
+		output, err := exec.CommandContext(ctx, "ssh-keygen", "-y", "-f", key).Output()
+		if err != nil {
+			return "", fmt.Errorf("failed to read public key for %s: %w", key, err)
+		}
+		data = output
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return "", fmt.Errorf("%s is not an SSH public key", key)
+	}
+	return fields[0] + " " + fields[1], nil
+}
+
+// WriteAllowedSigners adds the signing key to the allowed_signers file
+// for the file and git namespaces, keeping the other signers already
+// listed, and returns the file's path
+func WriteAllowedSigners(ctx context.Context, cfg *config.Config) (string, error) {
+	s := NewSigner(cfg)
+	key, cleanup, err := s.sshKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	publicKey, err := sshPublicKey(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	identity, err := SSHIdentity(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	path := AllowedSignersPath(cfg)
+	var lines []string
+	if data, err := os.ReadFile(path); err == nil {
+		for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+			if line != "" && !strings.Contains(line, publicKey) {
+				lines = append(lines, line)
+			}
+		}
+	}
+	lines = append(lines, fmt.Sprintf(`%s namespaces="%s,git" %s`, identity, SSHNamespace, publicKey))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// sshKey returns the signing key file, writing BAGBOY_SSH_SIGNING_KEY to
+// a private temporary file when set
+func (s *Signer) sshKey(ctx context.Context) (string, func(), error) {
+	if key := os.Getenv("BAGBOY_SSH_SIGNING_KEY"); key != "" {
+		return tempKey(ctx, "ssh", "id_signing", key)
+	}
+
+	key, err := SSHKey(s.config)
+	if err != nil {
+		return "", nil, err
+	}
+	if !fileExists(key) {
+		return "", nil, fmt.Errorf("SSH key %s not found - set signing.ssh.key or BAGBOY_SSH_SIGNING_KEY", key)
+	}
+	return key, func() {}, nil
+}
+
+// SignWithSSH writes a detached SSH signature to path.sig and returns
+// its path
+func (s *Signer) SignWithSSH(ctx context.Context, path string) (string, error) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		return "", fmt.Errorf("ssh-keygen not found - install OpenSSH 8.1 or later")
+	}
+	key, cleanup, err := s.sshKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	// ssh-keygen will not replace an existing signature
+	sigPath := path + ".sig"
+	os.Remove(sigPath)
+
+	cmd := exec.CommandContext(ctx, "ssh-keygen", "-Y", "sign", "-f", key, "-n", SSHNamespace, path)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("SSH signing failed: %w\nOutput: %s", err, output)
+	}
+	return sigPath, nil
+}
+
+// SignReleaseSSH writes checksums.txt for assets into dir, signs it and,
+// with signing.ssh.artifacts, every asset. It returns the files to publish
+// alongside the assets: the checksums, the signatures and allowed_signers.
+func (s *Signer) SignReleaseSSH(ctx context.Context, assets []string, dir string) ([]string, error) {
+	allowedSigners := AllowedSignersPath(s.config)
+	if !fileExists(allowedSigners) {
+		return nil, fmt.Errorf("%s not found - run 'bagboy keys generate --tool ssh'", allowedSigners)
+	}
+
+	checksums := filepath.Join(dir, ChecksumsFile)
+	if err := release.WriteChecksums(checksums, assets); err != nil {
+		return nil, err
+	}
+
+	toSign := []string{checksums}
+	if s.config.Signing.SSH.Artifacts {
+		toSign = append(toSign, assets...)
+	}
+	files := []string{checksums}
+	for _, path := range toSign {
+		sigPath, err := s.SignWithSSH(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, sigPath)
+	}
+	return append(files, allowedSigners), nil
+}
+
+// SignTagWithSSH creates tag signed with the SSH key and pushes it to
+// origin, so the release is created from a tag GitHub shows as verified.
+// A tag that already exists must carry a valid signature.
+func (s *Signer) SignTagWithSSH(ctx context.Context, tag string) error {
+	allowedSigners, err := filepath.Abs(AllowedSignersPath(s.config))
+	if err != nil {
+		return err
+	}
+
+	if exec.CommandContext(ctx, "git", "rev-parse", "-q", "--verify", "refs/tags/"+tag).Run() == nil {
+		verify := exec.CommandContext(ctx, "git", "-c", "gpg.ssh.allowedSignersFile="+allowedSigners, "tag", "-v", tag)
+		if output, err := verify.CombinedOutput(); err != nil {
+			return fmt.Errorf("tag %s already exists without a valid SSH signature - delete it or sign it: %s", tag, bytes.TrimSpace(output))
+		}
+		return nil
+	}
+
+	key, cleanup, err := s.sshKey(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	cmd := exec.CommandContext(ctx, "git", "-c", "gpg.format=ssh", "-c", "user.signingkey="+key,
+		"tag", "-s", tag, "-m", fmt.Sprintf("Release %s", tag))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git tag signing failed: %w\nOutput: %s", err, output)
+	}
+	output, err := exec.CommandContext(ctx, "git", "push", "origin", "refs/tags/"+tag).CombinedOutput()
+	audit.Result(ctx, audit.TagPush, "origin", tag, "", err)
+	if err != nil {
+		return fmt.Errorf("failed to push tag %s: %w\nOutput: %s", tag, err, output)
+	}
+	return nil
+}
+
+func (s *Signer) checkSSH() SigningStatus {
+	var issues []string
+	var steps []string
+
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		issues = append(issues, "ssh-keygen not found")
+		steps = append(steps, "Install OpenSSH 8.1 or later")
+	}
+	if os.Getenv("BAGBOY_SSH_SIGNING_KEY") == "" {
+		if key, err := SSHKey(s.config); err != nil || !fileExists(key) {
+			issues = append(issues, "SSH key not found")
+			steps = append(steps, "Create a key with ssh-keygen -t ed25519, or set signing.ssh.key")
+		}
+	}
+	if !fileExists(AllowedSignersPath(s.config)) {
+		issues = append(issues, "allowed_signers not found")
+		steps = append(steps, "Write it with: bagboy keys generate --tool ssh")
+	}
+	if s.config.Signing.SSH.Tags {
+		steps = append(steps, "Add the key to GitHub as a signing key so tags show as verified")
+	}
+
+	return SigningStatus{
+		Platform:   "SSH",
+		Required:   false,
+		Available:  len(issues) == 0,
+		Issues:     issues,
+		SetupSteps: steps,
+	}
+}
@@ -0,0 +1,105 @@
+package signing
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestSSHKeyPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := &config.Config{Name: "myapp"}
+	if _, err := SSHKey(cfg); err == nil {
+		t.Error("expected error with no SSH key")
+	}
+
+	os.MkdirAll(filepath.Join(home, ".ssh"), 0700)
+	os.WriteFile(filepath.Join(home, ".ssh", "id_rsa"), []byte("key"), 0600)
+	os.WriteFile(filepath.Join(home, ".ssh", "id_ed25519"), []byte("key"), 0600)
+	if key, _ := SSHKey(cfg); key != filepath.Join(home, ".ssh", "id_ed25519") {
+		t.Errorf("SSHKey = %q, want id_ed25519", key)
+	}
+
+	cfg.Signing.SSH.Key = "~/keys/release"
+	if key, _ := SSHKey(cfg); key != filepath.Join(home, "keys", "release") {
+		t.Errorf("SSHKey = %q", key)
+	}
+
+	if path := AllowedSignersPath(cfg); path != filepath.Join("keys", "allowed_signers") {
+		t.Errorf("AllowedSignersPath = %q", path)
+	}
+
+	if _, err := SSHIdentity(cfg); err == nil {
+		t.Error("expected error with no identity")
+	}
+	cfg.Authors = []config.AuthorConfig{{Name: "Jane Doe", Email: "jane@example.com"}}
+	if identity, _ := SSHIdentity(cfg); identity != "jane@example.com" {
+		t.Errorf("SSHIdentity = %q", identity)
+	}
+}
+
+func TestSignReleaseSSH(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+
+	dir := t.TempDir()
+	key := filepath.Join(dir, "id_ed25519")
+	if output, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", key).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen failed: %v\n%s", err, output)
+	}
+
+	cfg := &config.Config{Name: "myapp", Version: "1.0.0"}
+	cfg.Signing.SSH.Key = key
+	cfg.Signing.SSH.Identity = "release@example.com"
+	cfg.Signing.SSH.AllowedSigners = filepath.Join(dir, "keys", "allowed_signers")
+	signer := NewSigner(cfg)
+
+	asset := filepath.Join(dir, "myapp.tar.gz")
+	os.WriteFile(asset, []byte("data"), 0644)
+	ctx := context.Background()
+
+	if _, err := signer.SignReleaseSSH(ctx, []string{asset}, dir); err == nil {
+		t.Fatal("expected error without allowed_signers")
+	}
+
+	// An unrelated signer is kept, and writing twice does not duplicate the key
+	os.MkdirAll(filepath.Dir(cfg.Signing.SSH.AllowedSigners), 0755)
+	os.WriteFile(cfg.Signing.SSH.AllowedSigners, []byte("other@example.com ssh-ed25519 AAAAother\n"), 0644)
+	for i := 0; i < 2; i++ {
+		if _, err := WriteAllowedSigners(ctx, cfg); err != nil {
+			t.Fatalf("WriteAllowedSigners failed: %v", err)
+		}
+	}
+	data, _ := os.ReadFile(cfg.Signing.SSH.AllowedSigners)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], `release@example.com namespaces="file,git" ssh-ed25519 `) {
+		t.Fatalf("allowed_signers = %q", data)
+	}
+
+	files, err := signer.SignReleaseSSH(ctx, []string{asset}, dir)
+	if err != nil {
+		t.Fatalf("SignReleaseSSH failed: %v", err)
+	}
+	checksums := filepath.Join(dir, ChecksumsFile)
+	want := []string{checksums, checksums + ".sig", cfg.Signing.SSH.AllowedSigners}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("files = %v, want %v", files, want)
+	}
+
+	message, _ := os.Open(checksums)
+	defer message.Close()
+	verify := exec.Command("ssh-keygen", "-Y", "verify", "-f", cfg.Signing.SSH.AllowedSigners,
+		"-I", "release@example.com", "-n", SSHNamespace, "-s", checksums+".sig")
+	verify.Stdin = message
+	if output, err := verify.CombinedOutput(); err != nil {
+		t.Errorf("signature did not verify: %v\n%s", err, output)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signing
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// workDir creates a private temporary directory for one signing
+// operation, so parallel operations never share a file and nothing is
+// written next to the artifacts being signed. The directory is removed by
+// the returned cleanup, or as soon as ctx is cancelled.
+func workDir(ctx context.Context, operation string) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "bagboy-"+operation+"-*")
+	if err != nil {
+		return "", nil, err
+	}
+	remove := func() { os.RemoveAll(dir) }
+	stop := context.AfterFunc(ctx, remove)
+	return dir, func() {
+		stop()
+		remove()
+	}, nil
+}
+
+// tempKey writes a key from the environment to a private file in a work
+// directory and returns its path
+func tempKey(ctx context.Context, operation, name, key string) (string, func(), error) {
+	dir, cleanup, err := workDir(ctx, operation)
+	if err != nil {
+		return "", nil, err
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(strings.TrimSpace(key)+"\n"), 0600); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return path, cleanup, nil
+}
+
+// replaceFile replaces dst with the contents of src. The copy goes to a
+// uniquely named file next to dst first and is renamed over it, so dst is
+// never left half-written.
+func replaceFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := os.Stat(dst)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+"-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, in); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Chmod(f.Name(), info.Mode().Perm()); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), dst); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
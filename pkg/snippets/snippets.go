@@ -0,0 +1,203 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snippets writes install instructions for the formats a project
+// publishes, from its configuration.
+package snippets
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/gomodule"
+)
+
+// Snippet is how to install the project with one package manager
+type Snippet struct {
+	Format  string
+	Manager string
+	Command string
+}
+
+// Generate returns install snippets for every channel cfg publishes to.
+// assets are the release asset names; the deb and rpm snippets download
+// the package from the GitHub release when one is among them. Registries
+// that every project could publish to, such as npm or Snap, are only
+// included when listed in packages.enabled.
+func Generate(cfg *config.Config, assets []string) []Snippet {
+	var snippets []Snippet
+	add := func(format, manager, command string, args ...interface{}) {
+		snippets = append(snippets, Snippet{Format: format, Manager: manager, Command: fmt.Sprintf(command, args...)})
+	}
+	name := cfg.Name
+
+	if cfg.GitHub.Tap.Enabled && cfg.GitHub.Tap.Repo != "" && cfg.Packages.FormatEnabled("brew") {
+		owner, repo, _ := strings.Cut(cfg.GitHub.Tap.Repo, "/")
+		add("brew", "Homebrew", "brew install %s/%s/%s", owner, strings.TrimPrefix(repo, "homebrew-"), name)
+	}
+	if cfg.GitHub.Bucket.Enabled && cfg.GitHub.Bucket.Repo != "" && cfg.Packages.FormatEnabled("scoop") {
+		owner, _, _ := strings.Cut(cfg.GitHub.Bucket.Repo, "/")
+		add("scoop", "Scoop", "scoop bucket add %s https://github.com/%s\nscoop install %s", owner, cfg.GitHub.Bucket.Repo, name)
+	}
+	if id := cfg.Packages.Winget.PackageIdentifier; id != "" && cfg.Packages.FormatEnabled("winget") {
+		add("winget", "Winget", "winget install --id %s", id)
+	}
+	if listed(cfg, "chocolatey") {
+		add("chocolatey", "Chocolatey", "choco install %s", name)
+	}
+
+	if ppa := cfg.Packages.Deb.PPA.Target; ppa != "" && cfg.Packages.FormatEnabled("deb") {
+		add("deb", "Ubuntu (PPA)", "sudo add-apt-repository %s\nsudo apt update\nsudo apt install %s", ppa, name)
+	} else if deb := findAsset(assets, ".deb"); deb != "" {
+		add("deb", "Debian/Ubuntu", "curl -LO %s\nsudo apt install ./%s", releaseURL(cfg, deb), deb)
+	}
+	if project := cfg.Packages.RPM.COPR.Project; project != "" && cfg.Packages.FormatEnabled("rpm") {
+		add("rpm", "Fedora (COPR)", "sudo dnf copr enable %s\nsudo dnf install %s", project, name)
+	} else if rpm := findAsset(assets, ".rpm"); rpm != "" && !strings.HasSuffix(rpm, ".src.rpm") {
+		add("rpm", "Fedora/RHEL", "sudo dnf install %s", releaseURL(cfg, rpm))
+	}
+	if listed(cfg, "snap") {
+		add("snap", "Snap", "sudo snap install %s", name)
+	}
+	if registries := cfg.Packages.Docker.Registries; len(registries) > 0 && cfg.Packages.FormatEnabled("docker") {
+		add("docker", "Docker", "docker run --rm %s:%s --help", strings.TrimSuffix(registries[0], "/"), cfg.Version)
+	}
+
+	if listed(cfg, "npm") {
+		add("npm", "npm", "npm install -g %s", name)
+	}
+	if listed(cfg, "pypi") {
+		add("pypi", "pip", "pipx install %s", name)
+	}
+	if listed(cfg, "cargo") {
+		add("cargo", "Cargo", "cargo install %s", name)
+	}
+	if listed(cfg, "gem") || cfg.Packages.Gem.Name != "" {
+		gem := cfg.Packages.Gem.Name
+		if gem == "" {
+			gem = name
+		}
+		add("gem", "RubyGems", "gem install %s", gem)
+	}
+	if cfg.GoModule.Enabled {
+		dir := cfg.GoModule.Dir
+		if dir == "" {
+			dir = "."
+		}
+		if module, err := gomodule.ModulePath(filepath.Join(dir, "go.mod")); err == nil {
+			if cfg.GoModule.Package != "" {
+				module = path.Join(module, cfg.GoModule.Package)
+			}
+			add("go", "Go", "go install %s@latest", module)
+		}
+	}
+
+	if installer := installerURL(cfg); installer != "" && cfg.Packages.FormatEnabled("installer") {
+		add("installer", "Shell", "curl -fsSL %s/install.sh | sh", installer)
+		if hasWindows(cfg) {
+			add("installer", "PowerShell", "irm %s/install.ps1 | iex", installer)
+		}
+	}
+	return snippets
+}
+
+// Markdown writes snippets as a markdown section per package manager
+func Markdown(w io.Writer, snippets []Snippet) {
+	for i, snippet := range snippets {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "### %s\n\n```sh\n%s\n```\n", snippet.Manager, snippet.Command)
+	}
+}
+
+// listed reports whether packages.enabled names format explicitly
+func listed(cfg *config.Config, format string) bool {
+	for _, enabled := range cfg.Packages.Enabled {
+		if enabled == format {
+			return cfg.Packages.FormatEnabled(format)
+		}
+	}
+	return false
+}
+
+func findAsset(assets []string, ext string) string {
+	for _, asset := range assets {
+		if strings.HasSuffix(asset, ext) {
+			return filepath.Base(asset)
+		}
+	}
+	return ""
+}
+
+func releaseURL(cfg *config.Config, asset string) string {
+	return fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", cfg.GitHub.Owner, cfg.GitHub.Repo, cfg.Tag(), asset)
+}
+
+// installerURL is where install.sh is published: the latest GitHub
+// release, so the snippet does not go stale, or else installer.base_url
+func installerURL(cfg *config.Config) string {
+	if cfg.GitHub.Release.Enabled && cfg.GitHub.Owner != "" && cfg.GitHub.Repo != "" {
+		return fmt.Sprintf("https://github.com/%s/%s/releases/latest/download", cfg.GitHub.Owner, cfg.GitHub.Repo)
+	}
+	return strings.TrimSuffix(cfg.Installer.BaseURL, "/")
+}
+
+func hasWindows(cfg *config.Config) bool {
+	for platform := range cfg.Binaries {
+		if strings.HasPrefix(platform, "windows-") {
+			return true
+		}
+	}
+	return false
+}
+
+// Markers delimit the section of a file that Update replaces
+const (
+	StartMarker = "<!-- bagboy snippets start -->"
+	EndMarker   = "<!-- bagboy snippets end -->"
+)
+
+// Update replaces the text between StartMarker and EndMarker in the file
+// at path with snippets as markdown, leaving the rest of the file as is
+func Update(path string, snippets []Snippet) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	start := bytes.Index(data, []byte(StartMarker))
+	end := bytes.Index(data, []byte(EndMarker))
+	if start < 0 || end < start {
+		return fmt.Errorf("%s has no %s ... %s section", path, StartMarker, EndMarker)
+	}
+
+	var b bytes.Buffer
+	b.Write(data[:start+len(StartMarker)])
+	b.WriteString("\n")
+	Markdown(&b, snippets)
+	b.Write(data[end:])
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b.Bytes(), info.Mode().Perm())
+}
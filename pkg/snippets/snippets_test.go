@@ -0,0 +1,116 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snippets
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module github.com/acme/myapp\n"), 0644)
+
+	cfg := &config.Config{
+		Name:     "myapp",
+		Version:  "1.2.0",
+		Binaries: map[string]string{"linux-amd64": "bin/myapp", "windows-amd64": "bin/myapp.exe"},
+		GitHub: config.GitHubConfig{
+			Owner:   "acme",
+			Repo:    "myapp",
+			Release: config.ReleaseConfig{Enabled: true},
+			Tap:     config.TapConfig{Enabled: true, Repo: "acme/homebrew-tap"},
+			Bucket:  config.BucketConfig{Enabled: true, Repo: "acme/scoop-bucket"},
+		},
+		GoModule: config.GoModuleConfig{Enabled: true, Dir: dir, Package: "cmd/myapp"},
+	}
+	cfg.Packages.Winget.PackageIdentifier = "Acme.MyApp"
+	cfg.Packages.RPM.COPR.Project = "acme/myapp"
+	cfg.Packages.Docker.Registries = []string{"ghcr.io/acme/myapp"}
+
+	got := map[string]string{}
+	var managers []string
+	for _, snippet := range Generate(cfg, []string{"dist/myapp_1.2.0_amd64.deb", "dist/myapp-1.2.0-1.x86_64.rpm"}) {
+		got[snippet.Manager] = snippet.Command
+		managers = append(managers, snippet.Manager)
+	}
+
+	want := map[string]string{
+		"Homebrew":      "brew install acme/tap/myapp",
+		"Scoop":         "scoop bucket add acme https://github.com/acme/scoop-bucket\nscoop install myapp",
+		"Winget":        "winget install --id Acme.MyApp",
+		"Debian/Ubuntu": "curl -LO https://github.com/acme/myapp/releases/download/v1.2.0/myapp_1.2.0_amd64.deb\nsudo apt install ./myapp_1.2.0_amd64.deb",
+		"Fedora (COPR)": "sudo dnf copr enable acme/myapp\nsudo dnf install myapp",
+		"Docker":        "docker run --rm ghcr.io/acme/myapp:1.2.0 --help",
+		"Go":            "go install github.com/acme/myapp/cmd/myapp@latest",
+		"Shell":         "curl -fsSL https://github.com/acme/myapp/releases/latest/download/install.sh | sh",
+		"PowerShell":    "irm https://github.com/acme/myapp/releases/latest/download/install.ps1 | iex",
+	}
+	for manager, command := range want {
+		if got[manager] != command {
+			t.Errorf("%s = %q, want %q", manager, got[manager], command)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("managers = %v", managers)
+	}
+
+	// Opt-in registries need packages.enabled, which also drops the rest
+	cfg.Packages.Enabled = []string{"npm", "cargo"}
+	got = map[string]string{}
+	for _, snippet := range Generate(cfg, nil) {
+		got[snippet.Manager] = snippet.Command
+	}
+	if got["npm"] != "npm install -g myapp" || got["Cargo"] != "cargo install myapp" {
+		t.Errorf("opt-in snippets = %v", got)
+	}
+	if len(got) != 3 {
+		t.Errorf("Expected only npm, Cargo and Go snippets, got %v", got)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var b strings.Builder
+	Markdown(&b, []Snippet{{Manager: "Homebrew", Command: "brew install acme/tap/myapp"}, {Manager: "Cargo", Command: "cargo install myapp"}})
+	want := "### Homebrew\n\n```sh\nbrew install acme/tap/myapp\n```\n\n### Cargo\n\n```sh\ncargo install myapp\n```\n"
+	if b.String() != want {
+		t.Errorf("Markdown = %q, want %q", b.String(), want)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	readme := filepath.Join(t.TempDir(), "README.md")
+	os.WriteFile(readme, []byte("# myapp\n\n## Install\n"+StartMarker+"\nold\n"+EndMarker+"\n\n## Usage\n"), 0644)
+
+	if err := Update(readme, []Snippet{{Manager: "Cargo", Command: "cargo install myapp"}}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	data, _ := os.ReadFile(readme)
+	want := "# myapp\n\n## Install\n" + StartMarker + "\n### Cargo\n\n```sh\ncargo install myapp\n```\n" + EndMarker + "\n\n## Usage\n"
+	if string(data) != want {
+		t.Errorf("README = %q, want %q", data, want)
+	}
+
+	os.WriteFile(readme, []byte("# myapp\n"), 0644)
+	if err := Update(readme, nil); err == nil {
+		t.Error("Expected error without markers")
+	}
+}
@@ -0,0 +1,409 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spdx parses SPDX license expressions and translates them into
+// the license fields each package format expects.
+package spdx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// License describes a known SPDX license identifier
+type License struct {
+	ID         string
+	OSI        bool
+	Classifier string // PyPI trove classifier
+	Debian     string // short name in debian/copyright
+	Common     string // file under /usr/share/common-licenses on Debian
+}
+
+var licenses = []License{
+	{ID: "0BSD", OSI: true, Classifier: "License :: OSI Approved :: Zero-Clause BSD (0BSD)", Debian: "0BSD"},
+	{ID: "AGPL-3.0-only", OSI: true, Classifier: "License :: OSI Approved :: GNU Affero General Public License v3", Debian: "AGPL-3"},
+	{ID: "AGPL-3.0-or-later", OSI: true, Classifier: "License :: OSI Approved :: GNU Affero General Public License v3 or later (AGPLv3+)", Debian: "AGPL-3+"},
+	{ID: "Apache-2.0", OSI: true, Classifier: "License :: OSI Approved :: Apache Software License", Debian: "Apache-2.0", Common: "Apache-2.0"},
+	{ID: "Artistic-2.0", OSI: true, Classifier: "License :: OSI Approved :: Artistic License", Debian: "Artistic-2.0"},
+	{ID: "BSD-2-Clause", OSI: true, Classifier: "License :: OSI Approved :: BSD License", Debian: "BSD-2-clause"},
+	{ID: "BSD-3-Clause", OSI: true, Classifier: "License :: OSI Approved :: BSD License", Debian: "BSD-3-clause"},
+	{ID: "BSL-1.0", OSI: true, Classifier: "License :: OSI Approved :: Boost Software License 1.0 (BSL-1.0)", Debian: "BSL-1.0"},
+	{ID: "BUSL-1.1", Debian: "BUSL-1.1"},
+	{ID: "CC-BY-4.0", Debian: "CC-BY-4.0"},
+	{ID: "CC0-1.0", Classifier: "License :: CC0 1.0 Universal (CC0 1.0) Public Domain Dedication", Debian: "CC0-1.0", Common: "CC0-1.0"},
+	{ID: "EPL-2.0", OSI: true, Classifier: "License :: OSI Approved :: Eclipse Public License 2.0 (EPL-2.0)", Debian: "EPL-2.0"},
+	{ID: "GPL-2.0-only", OSI: true, Classifier: "License :: OSI Approved :: GNU General Public License v2 (GPLv2)", Debian: "GPL-2", Common: "GPL-2"},
+	{ID: "GPL-2.0-or-later", OSI: true, Classifier: "License :: OSI Approved :: GNU General Public License v2 or later (GPLv2+)", Debian: "GPL-2+", Common: "GPL-2"},
+	{ID: "GPL-3.0-only", OSI: true, Classifier: "License :: OSI Approved :: GNU General Public License v3 (GPLv3)", Debian: "GPL-3", Common: "GPL-3"},
+	{ID: "GPL-3.0-or-later", OSI: true, Classifier: "License :: OSI Approved :: GNU General Public License v3 or later (GPLv3+)", Debian: "GPL-3+", Common: "GPL-3"},
+	{ID: "ISC", OSI: true, Classifier: "License :: OSI Approved :: ISC License (ISCL)", Debian: "ISC"},
+	{ID: "LGPL-2.1-only", OSI: true, Classifier: "License :: OSI Approved :: GNU Lesser General Public License v2 (LGPLv2)", Debian: "LGPL-2.1", Common: "LGPL-2.1"},
+	{ID: "LGPL-2.1-or-later", OSI: true, Classifier: "License :: OSI Approved :: GNU Lesser General Public License v2 or later (LGPLv2+)", Debian: "LGPL-2.1+", Common: "LGPL-2.1"},
+	{ID: "LGPL-3.0-only", OSI: true, Classifier: "License :: OSI Approved :: GNU Lesser General Public License v3 (LGPLv3)", Debian: "LGPL-3", Common: "LGPL-3"},
+	{ID: "LGPL-3.0-or-later", OSI: true, Classifier: "License :: OSI Approved :: GNU Lesser General Public License v3 or later (LGPLv3+)", Debian: "LGPL-3+", Common: "LGPL-3"},
+	{ID: "MIT", OSI: true, Classifier: "License :: OSI Approved :: MIT License", Debian: "Expat"},
+	{ID: "MPL-2.0", OSI: true, Classifier: "License :: OSI Approved :: Mozilla Public License 2.0 (MPL 2.0)", Debian: "MPL-2.0", Common: "MPL-2.0"},
+	{ID: "Unlicense", OSI: true, Classifier: "License :: OSI Approved :: The Unlicense (Unlicense)", Debian: "Unlicense"},
+	{ID: "Zlib", OSI: true, Classifier: "License :: OSI Approved :: zlib/libpng License", Debian: "Zlib"},
+}
+
+// deprecated maps deprecated identifiers to their replacements
+var deprecated = map[string]string{
+	"AGPL-3.0":  "AGPL-3.0-only",
+	"GPL-2.0":   "GPL-2.0-only",
+	"GPL-3.0":   "GPL-3.0-only",
+	"LGPL-2.1":  "LGPL-2.1-only",
+	"LGPL-3.0":  "LGPL-3.0-only",
+	"GPL-2.0+":  "GPL-2.0-or-later",
+	"GPL-3.0+":  "GPL-3.0-or-later",
+	"LGPL-2.1+": "LGPL-2.1-or-later",
+	"LGPL-3.0+": "LGPL-3.0-or-later",
+	"AGPL-3.0+": "AGPL-3.0-or-later",
+}
+
+var exceptions = []string{
+	"Autoconf-exception-3.0",
+	"Classpath-exception-2.0",
+	"GCC-exception-3.1",
+	"LLVM-exception",
+	"OpenSSL-exception",
+}
+
+const licenseRefPrefix = "LicenseRef-"
+
+// Lookup returns the known license for id, ignoring case
+func Lookup(id string) (License, bool) {
+	for _, license := range licenses {
+		if strings.EqualFold(license.ID, id) {
+			return license, true
+		}
+	}
+	return License{}, false
+}
+
+// Expression is a parsed SPDX license expression. A leaf names one license
+// with an optional exception; other nodes join their operands with AND or
+// OR.
+type Expression struct {
+	Op        string // "", "AND" or "OR"
+	Operands  []*Expression
+	License   string
+	Exception string
+}
+
+// Parse parses an SPDX license expression. Identifiers are matched without
+// regard to case and returned in their canonical form.
+func Parse(s string) (*Expression, error) {
+	p := &parser{tokens: tokenize(s)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty license expression")
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in license expression %q", p.tokens[p.pos], s)
+	}
+	return expr, nil
+}
+
+// Normalize returns the canonical form of an expression, or s unchanged
+// when it does not parse
+func Normalize(s string) string {
+	expr, err := Parse(s)
+	if err != nil {
+		return s
+	}
+	return expr.String()
+}
+
+// Check parses s and returns warnings for deprecated, unknown and non-OSI
+// identifiers
+func Check(s string) ([]string, error) {
+	if _, err := Parse(s); err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	for _, token := range tokenize(s) {
+		if replacement, ok := lookupDeprecated(token); ok {
+			warnings = append(warnings, fmt.Sprintf("license %s is deprecated; use %s", token, replacement))
+		}
+	}
+
+	expr, _ := Parse(s)
+	expr.walk(func(leaf *Expression) {
+		license, known := Lookup(leaf.License)
+		switch {
+		case strings.HasPrefix(leaf.License, licenseRefPrefix):
+			warnings = append(warnings, fmt.Sprintf("custom license %s is not OSI approved", leaf.License))
+		case !known:
+			warnings = append(warnings, fmt.Sprintf("unknown license identifier %s", leaf.License))
+		case !license.OSI:
+			warnings = append(warnings, fmt.Sprintf("license %s is not OSI approved", leaf.License))
+		}
+		if leaf.Exception != "" && canonicalException(leaf.Exception) == "" {
+			warnings = append(warnings, fmt.Sprintf("unknown license exception %s", leaf.Exception))
+		}
+	})
+	return warnings, nil
+}
+
+// Licenses returns the license identifiers in the expression, in order and
+// without duplicates
+func (e *Expression) Licenses() []string {
+	seen := make(map[string]bool)
+	var ids []string
+	e.walk(func(leaf *Expression) {
+		if !seen[leaf.License] {
+			seen[leaf.License] = true
+			ids = append(ids, leaf.License)
+		}
+	})
+	return ids
+}
+
+// String renders the canonical SPDX form, which is also what RPM License
+// tags use
+func (e *Expression) String() string {
+	return e.format(func(leaf *Expression) string {
+		if leaf.Exception != "" {
+			return leaf.License + " WITH " + leaf.Exception
+		}
+		return leaf.License
+	}, "AND", "OR")
+}
+
+// Debian renders the expression with the short names and lowercase
+// operators of the machine-readable debian/copyright format
+func (e *Expression) Debian() string {
+	return e.format(func(leaf *Expression) string {
+		name := leaf.License
+		if license, ok := Lookup(name); ok {
+			name = license.Debian
+		}
+		if leaf.Exception != "" {
+			exception := strings.SplitN(leaf.Exception, "-exception", 2)[0]
+			return fmt.Sprintf("%s with %s exception", name, exception)
+		}
+		return name
+	}, "and", "or")
+}
+
+// NuGet returns the expression for a nuspec <license type="expression">
+// element. NuGet only accepts known SPDX identifiers, so ok is false when
+// the expression names a custom or unknown license and a licenseUrl must
+// be used instead.
+func (e *Expression) NuGet() (string, bool) {
+	for _, id := range e.Licenses() {
+		if _, known := Lookup(id); !known {
+			return "", false
+		}
+	}
+	return e.String(), true
+}
+
+// Classifiers returns the PyPI trove classifiers for the licenses in the
+// expression
+func (e *Expression) Classifiers() []string {
+	seen := make(map[string]bool)
+	var classifiers []string
+	for _, id := range e.Licenses() {
+		license, ok := Lookup(id)
+		if !ok || license.Classifier == "" || seen[license.Classifier] {
+			continue
+		}
+		seen[license.Classifier] = true
+		classifiers = append(classifiers, license.Classifier)
+	}
+	return classifiers
+}
+
+func (e *Expression) walk(fn func(leaf *Expression)) {
+	if e.Op == "" {
+		fn(e)
+		return
+	}
+	for _, operand := range e.Operands {
+		operand.walk(fn)
+	}
+}
+
+func (e *Expression) format(leaf func(*Expression) string, and, or string) string {
+	if e.Op == "" {
+		return leaf(e)
+	}
+
+	op := and
+	if e.Op == "OR" {
+		op = or
+	}
+
+	parts := make([]string, len(e.Operands))
+	for i, operand := range e.Operands {
+		part := operand.format(leaf, and, or)
+		// AND binds tighter than OR, so OR inside AND needs parentheses
+		if operand.Op != "" && operand.Op != e.Op {
+			part = "(" + part + ")"
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, " "+op+" ")
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *parser) parseOr() (*Expression, error) {
+	return p.parseBinary("OR", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (*Expression, error) {
+	return p.parseBinary("AND", p.parseWith)
+}
+
+func (p *parser) parseBinary(op string, operand func() (*Expression, error)) (*Expression, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []*Expression{first}
+	for strings.EqualFold(p.peek(), op) {
+		p.next()
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, next)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return &Expression{Op: op, Operands: operands}, nil
+}
+
+func (p *parser) parseWith() (*Expression, error) {
+	expr, err := p.parseSimple()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(p.peek(), "WITH") {
+		return expr, nil
+	}
+	p.next()
+	if expr.Op != "" {
+		return nil, fmt.Errorf("WITH must follow a single license")
+	}
+	exception := p.next()
+	if !isIdentifier(exception) {
+		return nil, fmt.Errorf("expected exception after WITH")
+	}
+	if canonical := canonicalException(exception); canonical != "" {
+		exception = canonical
+	}
+	expr.Exception = exception
+	return expr, nil
+}
+
+func (p *parser) parseSimple() (*Expression, error) {
+	token := p.next()
+	switch {
+	case token == "(":
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return expr, nil
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of license expression")
+	case !isIdentifier(token):
+		return nil, fmt.Errorf("unexpected %q in license expression", token)
+	}
+	return &Expression{License: canonicalID(token)}, nil
+}
+
+func tokenize(s string) []string {
+	s = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(s)
+	return strings.Fields(s)
+}
+
+func isIdentifier(token string) bool {
+	if token == "" {
+		return false
+	}
+	switch strings.ToUpper(token) {
+	case "AND", "OR", "WITH", "(", ")":
+		return false
+	}
+	for _, r := range strings.TrimSuffix(token, "+") {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// canonicalID returns the canonical spelling of id, replacing deprecated
+// identifiers
+func canonicalID(id string) string {
+	if replacement, ok := lookupDeprecated(id); ok {
+		return replacement
+	}
+	if license, ok := Lookup(id); ok {
+		return license.ID
+	}
+	if len(id) > len(licenseRefPrefix) && strings.EqualFold(id[:len(licenseRefPrefix)], licenseRefPrefix) {
+		return licenseRefPrefix + id[len(licenseRefPrefix):]
+	}
+	return id
+}
+
+func lookupDeprecated(id string) (string, bool) {
+	for old, replacement := range deprecated {
+		if strings.EqualFold(old, id) {
+			return replacement, true
+		}
+	}
+	return "", false
+}
+
+func canonicalException(id string) string {
+	for _, exception := range exceptions {
+		if strings.EqualFold(exception, id) {
+			return exception
+		}
+	}
+	return ""
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spdx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		debian   string
+		wantErr  bool
+	}{
+		{input: "MIT", expected: "MIT", debian: "Expat"},
+		{input: "apache-2.0", expected: "Apache-2.0", debian: "Apache-2.0"},
+		{input: "MIT OR Apache-2.0", expected: "MIT OR Apache-2.0", debian: "Expat or Apache-2.0"},
+		{input: "(MIT OR Apache-2.0) AND BSD-3-Clause", expected: "(MIT OR Apache-2.0) AND BSD-3-Clause", debian: "(Expat or Apache-2.0) and BSD-3-clause"},
+		{input: "((MIT))", expected: "MIT", debian: "Expat"},
+		{input: "GPL-2.0+", expected: "GPL-2.0-or-later", debian: "GPL-2+"},
+		{input: "GPL-2.0-or-later WITH classpath-exception-2.0", expected: "GPL-2.0-or-later WITH Classpath-exception-2.0", debian: "GPL-2+ with Classpath exception"},
+		{input: "LicenseRef-Proprietary", expected: "LicenseRef-Proprietary", debian: "LicenseRef-Proprietary"},
+		{input: "", wantErr: true},
+		{input: "MIT License", wantErr: true},
+		{input: "MIT OR", wantErr: true},
+		{input: "(MIT", wantErr: true},
+		{input: "(MIT OR ISC) WITH LLVM-exception", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expr, err := Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := expr.String(); got != tt.expected {
+				t.Errorf("String() = %q, expected %q", got, tt.expected)
+			}
+			if got := expr.Debian(); got != tt.debian {
+				t.Errorf("Debian() = %q, expected %q", got, tt.debian)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		input    string
+		warnings []string
+	}{
+		{"MIT OR Apache-2.0", nil},
+		{"GPL-3.0", []string{"deprecated"}},
+		{"CC0-1.0", []string{"not OSI approved"}},
+		{"Foo-1.0", []string{"unknown license identifier Foo-1.0"}},
+		{"LicenseRef-Internal", []string{"custom license"}},
+		{"MIT WITH Made-Up-exception", []string{"unknown license exception"}},
+	}
+
+	for _, tt := range tests {
+		warnings, err := Check(tt.input)
+		if err != nil {
+			t.Errorf("Check(%q) error: %v", tt.input, err)
+			continue
+		}
+		if len(warnings) != len(tt.warnings) {
+			t.Errorf("Check(%q) = %v, expected %d warnings", tt.input, warnings, len(tt.warnings))
+			continue
+		}
+		for i, want := range tt.warnings {
+			if !strings.Contains(warnings[i], want) {
+				t.Errorf("Check(%q) warning %q does not mention %q", tt.input, warnings[i], want)
+			}
+		}
+	}
+}
+
+func TestFormatTranslations(t *testing.T) {
+	expr, err := Parse("MIT OR Apache-2.0 OR BSD-2-Clause OR BSD-3-Clause")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"License :: OSI Approved :: MIT License",
+		"License :: OSI Approved :: Apache Software License",
+		"License :: OSI Approved :: BSD License",
+	}
+	if got := expr.Classifiers(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Classifiers() = %v, expected %v", got, expected)
+	}
+
+	if nuget, ok := expr.NuGet(); !ok || nuget != expr.String() {
+		t.Errorf("NuGet() = %q, %v", nuget, ok)
+	}
+
+	custom, _ := Parse("MIT AND LicenseRef-Internal")
+	if _, ok := custom.NuGet(); ok {
+		t.Error("Expected custom license to need a licenseUrl")
+	}
+}
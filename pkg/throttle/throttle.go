@@ -0,0 +1,54 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package throttle applies the performance: limits from bagboy.yaml so a
+// release can run on a shared CI runner without starving its neighbours.
+package throttle
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/upload"
+)
+
+// Apply lowers bagboy's scheduling priority, which the packaging tools it
+// runs inherit, and sets the upload parallelism and bandwidth cap
+func Apply(cfg config.PerformanceConfig) error {
+	rate, err := config.ParseBandwidth(cfg.BandwidthLimit)
+	if err != nil {
+		return err
+	}
+	upload.SetBandwidthLimit(rate)
+	upload.Parallel = max(cfg.MaxParallelUploads, 1)
+
+	if cfg.Nice > 0 {
+		if err := setNice(cfg.Nice); err != nil {
+			return fmt.Errorf("failed to set niceness %d: %w", cfg.Nice, err)
+		}
+	}
+	return nil
+}
+
+// Packagers returns how many packagers may run at once, one per CPU unless
+// limited
+func Packagers(cfg config.PerformanceConfig) int {
+	if cfg.MaxParallelPackagers > 0 {
+		return cfg.MaxParallelPackagers
+	}
+	return runtime.NumCPU()
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/scttfrdmn/bagboy/pkg/config"
+	"github.com/scttfrdmn/bagboy/pkg/upload"
+)
+
+func TestApply(t *testing.T) {
+	defer func() {
+		upload.Parallel = 1
+		upload.SetBandwidthLimit(0)
+	}()
+
+	if err := Apply(config.PerformanceConfig{MaxParallelUploads: 4, BandwidthLimit: "5MB/s"}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if upload.Parallel != 4 {
+		t.Errorf("upload.Parallel = %d, want 4", upload.Parallel)
+	}
+
+	if err := Apply(config.PerformanceConfig{}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if upload.Parallel != 1 {
+		t.Errorf("upload.Parallel = %d, want 1 by default", upload.Parallel)
+	}
+
+	if err := Apply(config.PerformanceConfig{BandwidthLimit: "fast"}); err == nil {
+		t.Error("Expected an invalid bandwidth limit to fail")
+	}
+}
+
+func TestPackagers(t *testing.T) {
+	if got := Packagers(config.PerformanceConfig{}); got != runtime.NumCPU() {
+		t.Errorf("Packagers() = %d, want one per CPU by default", got)
+	}
+	if got := Packagers(config.PerformanceConfig{MaxParallelPackagers: 6}); got != 6 {
+		t.Errorf("Packagers() = %d, want 6", got)
+	}
+}
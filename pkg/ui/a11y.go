@@ -0,0 +1,179 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ui
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Accessible is set in screen-reader mode: no spinners, progress bars,
+// box drawing or emoji, just plain lines with explicit status words
+var Accessible bool
+
+// statusWords replace the emoji that start a line in accessible mode
+var statusWords = []struct{ emoji, word string }{
+	{"✅", "Success:"},
+	{"❌", "Error:"},
+	{"⚠️", "Warning:"},
+	{"⚠", "Warning:"},
+	{"💡", "Tip:"},
+	{"🔍", "Dry run:"},
+}
+
+var (
+	restoreMu sync.Mutex
+	restorers []func()
+)
+
+// EnableAccessible switches to accessible mode and filters everything
+// written to stdout and stderr through Plain. Call CloseAccessible before
+// exiting so the last lines are written.
+func EnableAccessible() error {
+	Accessible = true
+	for _, f := range []**os.File{&os.Stdout, &os.Stderr} {
+		restore, err := filter(f)
+		if err != nil {
+			return err
+		}
+		restoreMu.Lock()
+		restorers = append(restorers, restore)
+		restoreMu.Unlock()
+	}
+	return nil
+}
+
+// CloseAccessible writes any filtered output still pending and restores
+// stdout and stderr
+func CloseAccessible() {
+	restoreMu.Lock()
+	defer restoreMu.Unlock()
+	for _, restore := range restorers {
+		restore()
+	}
+	restorers = nil
+}
+
+// filter points *f at a pipe whose lines are made Plain before they reach
+// the original file
+func filter(f **os.File) (func(), error) {
+	original := *f
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	*f = w
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		copyPlain(original, r)
+	}()
+	return func() {
+		*f = original
+		w.Close()
+		<-done
+	}, nil
+}
+
+func copyPlain(w io.Writer, r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		if line, ok := Plain(scanner.Text()); ok {
+			io.WriteString(w, line+"\n")
+		}
+	}
+}
+
+// Plain rewrites one line of output for a screen reader. Progress redraws
+// keep only their final state, a leading emoji becomes a status word,
+// other emoji are dropped, and lines that are only box drawing or rules
+// are left out (ok is false).
+func Plain(line string) (string, bool) {
+	if i := strings.LastIndex(strings.TrimRight(line, " \r"), "\r"); i >= 0 {
+		line = line[i+1:]
+	}
+	line = strings.TrimRight(line, " \r")
+
+	trimmed := strings.TrimSpace(line)
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	if trimmed == "" {
+		return "", true
+	}
+	if strings.Trim(trimmed, "─━═=┌┐└┘├┤┬┴┼│ ") == "" {
+		return "", false
+	}
+
+	var words []string
+	for {
+		rest := strings.TrimLeft(trimmed, " ")
+		matched := false
+		for _, status := range statusWords {
+			if strings.HasPrefix(rest, status.emoji) {
+				words = append(words, status.word)
+				trimmed = strings.TrimPrefix(rest, status.emoji)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			r := []rune(rest)
+			if len(r) > 0 && isEmoji(r[0]) {
+				trimmed = string(r[1:])
+				continue
+			}
+			trimmed = rest
+			break
+		}
+	}
+
+	var b strings.Builder
+	for _, r := range trimmed {
+		switch {
+		case isEmoji(r):
+		case r == '•':
+			b.WriteRune('-')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	text := strings.Join(strings.Fields(b.String()), " ")
+	if len(words) > 0 {
+		text = strings.Join(words[:1], " ") + " " + text
+	}
+	return indent + text, true
+}
+
+// isEmoji reports whether r is a pictograph or one of the marks that
+// modify one
+func isEmoji(r rune) bool {
+	switch {
+	case r == 0xFE0F || r == 0x200D:
+		return true
+	case r >= 0x1F000 && r <= 0x1FAFF:
+		return true
+	case r >= 0x2600 && r <= 0x27BF:
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF, r >= 0x2300 && r <= 0x23FF:
+		return true
+	}
+	return r == 'ℹ'
+}
@@ -18,9 +18,13 @@ package ui
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 // ProgressBar represents a simple progress bar
@@ -55,11 +59,18 @@ func (pb *ProgressBar) Increment() {
 // Finish completes the progress bar
 func (pb *ProgressBar) Finish() {
 	pb.current = pb.total
+	if Accessible {
+		fmt.Printf("%s: %d of %d done\n", pb.prefix, pb.current, pb.total)
+		return
+	}
 	pb.render()
 	fmt.Println()
 }
 
 func (pb *ProgressBar) render() {
+	if Accessible {
+		return
+	}
 	percent := float64(pb.current) / float64(pb.total)
 	filled := int(percent * float64(pb.width))
 	
@@ -87,6 +98,10 @@ func NewSpinner(message string) *Spinner {
 
 // Start starts the spinner
 func (s *Spinner) Start() {
+	if Accessible {
+		fmt.Println(s.message)
+		return
+	}
 	s.active = true
 	go func() {
 		for s.active {
@@ -99,6 +114,9 @@ func (s *Spinner) Start() {
 
 // Stop stops the spinner
 func (s *Spinner) Stop() {
+	if Accessible {
+		return
+	}
 	s.active = false
 	fmt.Print("\r" + strings.Repeat(" ", len(s.message)+10) + "\r")
 }
@@ -126,7 +144,7 @@ func Info(message string) {
 // Header displays a section header
 func Header(message string) {
 	fmt.Printf("\n🎯 %s\n", message)
-	fmt.Println(strings.Repeat("─", len(message)+4))
+	fmt.Println(strings.Repeat("─", DisplayWidth(message)+4))
 }
 
 // Confirm prompts for user confirmation
@@ -157,82 +175,234 @@ func Select(message string, options []string) int {
 
 // Table displays data in a table format
 type Table struct {
-	headers []string
-	rows    [][]string
-	widths  []int
+	headers  []string
+	rows     [][]string
+	widths   []int
+	maxWidth int
 }
 
-// NewTable creates a new table
+// NewTable creates a new table that fits the terminal it is printed to
 func NewTable(headers []string) *Table {
 	widths := make([]int, len(headers))
 	for i, header := range headers {
-		widths[i] = len(header)
+		widths[i] = DisplayWidth(header)
 	}
 	return &Table{
-		headers: headers,
-		widths:  widths,
+		headers:  headers,
+		widths:   widths,
+		maxWidth: TerminalWidth(),
 	}
 }
 
+// SetMaxWidth sets the widest the printed table may be; 0 means no limit
+func (t *Table) SetMaxWidth(width int) {
+	t.maxWidth = width
+}
+
 // AddRow adds a row to the table
 func (t *Table) AddRow(row []string) {
 	for i, cell := range row {
-		if i < len(t.widths) && len(cell) > t.widths[i] {
-			t.widths[i] = len(cell)
+		if i < len(t.widths) && DisplayWidth(cell) > t.widths[i] {
+			t.widths[i] = DisplayWidth(cell)
 		}
 	}
 	t.rows = append(t.rows, row)
 }
 
+// SortBy orders the rows by the given column, keeping the order of rows
+// that compare equal
+func (t *Table) SortBy(column int) {
+	sort.SliceStable(t.rows, func(i, j int) bool {
+		return cell(t.rows[i], column) < cell(t.rows[j], column)
+	})
+}
+
+func cell(row []string, column int) string {
+	if column < len(row) {
+		return row[column]
+	}
+	return ""
+}
+
+// fitWidths shrinks the widest columns until the table fits maxWidth, but
+// no column below its header or minColumnWidth
+func (t *Table) fitWidths() []int {
+	widths := append([]int(nil), t.widths...)
+	if t.maxWidth <= 0 {
+		return widths
+	}
+	// Each column is padded by a space either side and followed by a border
+	total := 1
+	for _, width := range widths {
+		total += width + 3
+	}
+	for total > t.maxWidth {
+		widest := -1
+		for i, width := range widths {
+			if width > max(DisplayWidth(t.headers[i]), minColumnWidth) && (widest < 0 || width > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+	return widths
+}
+
+// minColumnWidth is the narrowest a column is shrunk to
+const minColumnWidth = 12
+
 // Print prints the table
 func (t *Table) Print() {
-	// Print header
-	fmt.Print("┌")
-	for i, width := range t.widths {
-		fmt.Print(strings.Repeat("─", width+2))
-		if i < len(t.widths)-1 {
-			fmt.Print("┬")
-		}
+	t.Fprint(os.Stdout)
+}
+
+// Fprint writes the table to w, truncating cells that do not fit. In
+// accessible mode each row is written in full as one sentence instead.
+func (t *Table) Fprint(w io.Writer) {
+	if Accessible {
+		t.sentences(w)
+		return
 	}
-	fmt.Println("┐")
-	
-	// Print header row
-	fmt.Print("│")
-	for i, header := range t.headers {
-		fmt.Printf(" %-*s │", t.widths[i], header)
+	widths := t.fitWidths()
+	border := func(left, middle, right string) {
+		fmt.Fprint(w, left)
+		for i, width := range widths {
+			fmt.Fprint(w, strings.Repeat("─", width+2))
+			if i < len(widths)-1 {
+				fmt.Fprint(w, middle)
+			}
+		}
+		fmt.Fprintln(w, right)
 	}
-	fmt.Println()
-	
-	// Print separator
-	fmt.Print("├")
-	for i, width := range t.widths {
-		fmt.Print(strings.Repeat("─", width+2))
-		if i < len(t.widths)-1 {
-			fmt.Print("┼")
+	line := func(row []string) {
+		fmt.Fprint(w, "│")
+		for i, width := range widths {
+			text := Truncate(cell(row, i), width)
+			fmt.Fprintf(w, " %s%s │", text, strings.Repeat(" ", width-DisplayWidth(text)))
 		}
+		fmt.Fprintln(w)
 	}
-	fmt.Println("┤")
-	
-	// Print rows
+
+	border("┌", "┬", "┐")
+	line(t.headers)
+	border("├", "┼", "┤")
 	for _, row := range t.rows {
-		fmt.Print("│")
-		for i, cell := range row {
-			if i < len(t.widths) {
-				fmt.Printf(" %-*s │", t.widths[i], cell)
+		line(row)
+	}
+	border("└", "┴", "┘")
+}
+
+// sentences writes each row as "Header: value, Header: value." leaving
+// out empty cells
+func (t *Table) sentences(w io.Writer) {
+	for _, row := range t.rows {
+		var parts []string
+		for i, header := range t.headers {
+			if value := strings.TrimSpace(cell(row, i)); value != "" {
+				parts = append(parts, header+": "+value)
 			}
 		}
-		fmt.Println()
+		fmt.Fprintln(w, strings.Join(parts, ", ")+".")
 	}
-	
-	// Print bottom border
-	fmt.Print("└")
-	for i, width := range t.widths {
-		fmt.Print(strings.Repeat("─", width+2))
-		if i < len(t.widths)-1 {
-			fmt.Print("┴")
+}
+
+// Markdown writes the table as a GitHub-flavored markdown table, in full
+func (t *Table) Markdown(w io.Writer) {
+	line := func(row []string) {
+		fmt.Fprint(w, "|")
+		for i := range t.headers {
+			text := strings.ReplaceAll(cell(row, i), "|", "\\|")
+			fmt.Fprintf(w, " %s |", strings.ReplaceAll(text, "\n", " "))
+		}
+		fmt.Fprintln(w)
+	}
+
+	line(t.headers)
+	fmt.Fprint(w, "|")
+	for range t.headers {
+		fmt.Fprint(w, " --- |")
+	}
+	fmt.Fprintln(w)
+	for _, row := range t.rows {
+		line(row)
+	}
+}
+
+// Truncate shortens s to width columns with an ellipsis. Paths lose their
+// middle so the file name stays visible; other text loses its end.
+func Truncate(s string, width int) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return strings.Repeat("…", width)
+	}
+	runes := []rune(s)
+	budget := width - 1 // for the ellipsis
+
+	// Keep the file name of a path, or at least its last two thirds
+	tail := len(runes)
+	if strings.ContainsAny(s, "/\\") && !strings.Contains(s, " ") {
+		keep := budget * 2 / 3
+		if name := DisplayWidth(s[strings.LastIndexAny(s, "/\\"):]); name > keep && name <= budget-4 {
+			keep = name
 		}
+		for used := 0; tail > 0 && used+runeWidth(runes[tail-1]) <= keep; tail-- {
+			used += runeWidth(runes[tail-1])
+		}
+		budget -= DisplayWidth(string(runes[tail:]))
+	}
+	head, used := 0, 0
+	for head < tail && used+runeWidth(runes[head]) <= budget {
+		used += runeWidth(runes[head])
+		head++
+	}
+	return string(runes[:head]) + "…" + string(runes[tail:])
+}
+
+// DisplayWidth is how many terminal columns s takes up
+func DisplayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// runeWidth is 2 for wide characters such as emoji and CJK, 0 for
+// combining marks and variation selectors, and 1 otherwise
+func runeWidth(r rune) int {
+	switch {
+	case r == 0xFE0F || r == 0x200D || unicode.Is(unicode.Mn, r):
+		return 0
+	case r >= 0x1100 && r <= 0x115F,
+		r >= 0x2E80 && r <= 0xA4CF,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1FAFF,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return 2
+	}
+	switch r {
+	case '⌚', '⌛', '⏩', '⏪', '⏫', '⏬', '⏰', '⏳', '☔', '☕', '⚡', '⚪', '⚫', '⚽', '⛔', '✅', '✊', '✋', '✨', '❌', '❎', '❓', '❔', '❕', '❗', '➕', '➖', '➗', '⬛', '⬜', '⭐', '⭕':
+		return 2
+	}
+	return 1
+}
+
+// TerminalWidth returns the width of the terminal from COLUMNS or stdout,
+// or 0 when output is not a terminal
+func TerminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
 	}
-	fmt.Println("┘")
+	return ttyWidth()
 }
 
 // PrintBanner prints a welcome banner
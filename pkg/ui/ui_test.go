@@ -71,6 +71,106 @@ func TestTable(t *testing.T) {
 	}
 }
 
+func TestTableFitsWidth(t *testing.T) {
+	table := NewTable([]string{"Format", "Output Path", "Status"})
+	table.SetMaxWidth(80)
+	table.AddRow([]string{"deb", "dist/debian/pool/main/t/testapp/testapp_1.2.3_amd64.deb", "✅ Success"})
+	table.AddRow([]string{"rpm", "", "⚠️  Skipped: tool missing (rpmbuild not found - install rpm-build package)"})
+
+	var buf bytes.Buffer
+	table.Fprint(&buf)
+
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		if width := DisplayWidth(line); width > 80 {
+			t.Errorf("Expected lines no wider than 80, got %d: %s", width, line)
+		}
+	}
+	if !strings.Contains(buf.String(), "testapp_1.2.3_amd64.deb") {
+		t.Errorf("Expected the file name to survive truncation, got:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "…") {
+		t.Errorf("Expected truncated cells to end in an ellipsis, got:\n%s", buf.String())
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"a long status message", 10, "a long st…"},
+		{"dist/brew/Formula/testapp.rb", 16, "dist…/testapp.rb"},
+		{"✅ Success", 5, "✅ S…"},
+	}
+	for _, tt := range tests {
+		got := Truncate(tt.input, tt.width)
+		if DisplayWidth(got) > tt.width {
+			t.Errorf("Truncate(%q, %d) = %q is wider than %d", tt.input, tt.width, got, tt.width)
+		}
+		if got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestTableSortAndMarkdown(t *testing.T) {
+	table := NewTable([]string{"Format", "Status"})
+	table.AddRow([]string{"scoop", "ok"})
+	table.AddRow([]string{"brew", "a|b"})
+	table.SortBy(0)
+
+	var buf bytes.Buffer
+	table.Markdown(&buf)
+
+	want := "| Format | Status |\n| --- | --- |\n| brew | a\\|b |\n| scoop | ok |\n"
+	if buf.String() != want {
+		t.Errorf("Expected markdown:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
+
+func TestPlain(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		keep  bool
+	}{
+		{"✅ Created brew formula: dist/brew/app.rb", "Success: Created brew formula: dist/brew/app.rb", true},
+		{"⚠️  Signing failed: no identity", "Warning: Signing failed: no identity", true},
+		{"ℹ️  💡 Run 'bagboy init' to create a new configuration", "Tip: Run 'bagboy init' to create a new configuration", true},
+		{"\n🎯 Validating Configuration", "Validating Configuration", true},
+		{"────────────────────", "", false},
+		{"================================", "", false},
+		{"\r  app.tar.gz  10.0%\r  app.tar.gz 100.0%  ", "  app.tar.gz 100.0%", true},
+		{"• Create all package formats", "- Create all package formats", true},
+		{"  version: 1.0.0", "  version: 1.0.0", true},
+	}
+	for _, tt := range tests {
+		got, keep := Plain(strings.TrimPrefix(tt.input, "\n"))
+		if got != tt.want || keep != tt.keep {
+			t.Errorf("Plain(%q) = %q, %v, want %q, %v", tt.input, got, keep, tt.want, tt.keep)
+		}
+	}
+}
+
+func TestTableAccessible(t *testing.T) {
+	Accessible = true
+	defer func() { Accessible = false }()
+
+	table := NewTable([]string{"Format", "Output Path", "Status"})
+	table.AddRow([]string{"brew", "dist/brew/app.rb", "✅ Success"})
+	table.AddRow([]string{"rpm", "", "⚠️  Skipped: tool missing"})
+
+	var buf bytes.Buffer
+	table.Fprint(&buf)
+
+	want := "Format: brew, Output Path: dist/brew/app.rb, Status: ✅ Success.\nFormat: rpm, Status: ⚠️  Skipped: tool missing.\n"
+	if buf.String() != want {
+		t.Errorf("Expected one sentence per row:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
+
 func TestIsInteractive(t *testing.T) {
 	// This test is environment-dependent, just ensure it doesn't panic
 	result := IsInteractive()
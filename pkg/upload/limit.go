@@ -0,0 +1,150 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upload
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Parallel is how many uploads Each runs at once
+var Parallel = 1
+
+// bandwidth caps the combined rate of every throttled upload; nil is
+// unlimited
+var bandwidth *Limiter
+
+// SetBandwidthLimit caps the combined upload rate at bytesPerSecond, or
+// removes the cap when it is 0
+func SetBandwidthLimit(bytesPerSecond int64) {
+	if bytesPerSecond <= 0 {
+		bandwidth = nil
+		return
+	}
+	bandwidth = NewLimiter(bytesPerSecond)
+}
+
+// Throttle returns r limited to the bandwidth cap, shared with every
+// other throttled upload
+func Throttle(r io.Reader) io.Reader {
+	if bandwidth == nil {
+		return r
+	}
+	return bandwidth.Reader(r)
+}
+
+// Limiter paces reads to a number of bytes per second
+type Limiter struct {
+	rate int64
+	mu   sync.Mutex
+	next time.Time // when the bytes reserved so far have been paid for
+}
+
+// NewLimiter returns a limiter allowing bytesPerSecond
+func NewLimiter(bytesPerSecond int64) *Limiter {
+	return &Limiter{rate: bytesPerSecond}
+}
+
+// wait blocks until n more bytes fit within the rate
+func (l *Limiter) wait(n int) {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(float64(n) / float64(l.rate) * float64(time.Second)))
+	delay := l.next.Sub(now)
+	l.mu.Unlock()
+	time.Sleep(delay)
+}
+
+// Reader returns r limited to the limiter's rate
+func (l *Limiter) Reader(r io.Reader) io.Reader {
+	return &limitedReader{r: r, l: l}
+}
+
+type limitedReader struct {
+	r io.Reader
+	l *Limiter
+}
+
+func (r *limitedReader) Read(b []byte) (int, error) {
+	// Small reads keep the pace smooth at low rates
+	if max := int(r.l.rate / 10); max > 0 && len(b) > max {
+		b = b[:max]
+	}
+	n, err := r.r.Read(b)
+	if n > 0 {
+		r.l.wait(n)
+	}
+	return n, err
+}
+
+// Each calls fn for items 0 to n-1, up to Parallel at a time, and returns
+// the first error. Items not yet started when one fails are skipped.
+func Each(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	workers := Parallel
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	items := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range items {
+				if err := fn(ctx, i); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case items <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(items)
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		// The caller's context ended
+		return ctx.Err()
+	}
+	return firstErr
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(100 << 10) // 100 KiB/s
+	start := time.Now()
+	n, err := io.Copy(io.Discard, l.Reader(bytes.NewReader(make([]byte, 30<<10))))
+	if err != nil || n != 30<<10 {
+		t.Fatalf("Copy = %d, %v", n, err)
+	}
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("30 KiB at 100 KiB/s took %s, want about 300ms", elapsed)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	r := bytes.NewReader(nil)
+	SetBandwidthLimit(0)
+	if Throttle(r) != io.Reader(r) {
+		t.Error("Expected no throttling without a limit")
+	}
+
+	SetBandwidthLimit(1 << 20)
+	defer SetBandwidthLimit(0)
+	if _, ok := Throttle(r).(*limitedReader); !ok {
+		t.Error("Expected a throttled reader with a limit")
+	}
+}
+
+func TestEach(t *testing.T) {
+	old := Parallel
+	defer func() { Parallel = old }()
+	Parallel = 3
+
+	var running, peak, done int32
+	err := Each(context.Background(), 10, func(ctx context.Context, i int) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for p := atomic.LoadInt32(&peak); n > p && !atomic.CompareAndSwapInt32(&peak, p, n); p = atomic.LoadInt32(&peak) {
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&done, 1)
+		return nil
+	})
+	if err != nil || done != 10 {
+		t.Errorf("Each = %v after %d items, want all 10", err, done)
+	}
+	if peak > 3 {
+		t.Errorf("%d uploads ran at once, want at most 3", peak)
+	}
+
+	// The first error stops items that have not started
+	Parallel = 1
+	done = 0
+	err = Each(context.Background(), 10, func(ctx context.Context, i int) error {
+		atomic.AddInt32(&done, 1)
+		if i == 2 {
+			return errors.New("upload failed")
+		}
+		return nil
+	})
+	if err == nil || err.Error() != "upload failed" || done > 4 {
+		t.Errorf("Each = %v after %d items, want the error after 3", err, done)
+	}
+}
@@ -0,0 +1,459 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upload
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scttfrdmn/bagboy/pkg/audit"
+)
+
+// DefaultPartSize is the size of each part of a multipart upload
+const DefaultPartSize = 16 << 20
+
+// unsignedPayload skips hashing bodies, which S3 allows over HTTPS
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3 uploads objects to an S3 bucket or an S3-compatible service such as
+// R2 or MinIO, signing requests with AWS Signature Version 4
+type S3 struct {
+	Bucket       string
+	Region       string
+	Endpoint     string // path-style endpoint for S3-compatible services; empty for AWS
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+
+	PartSize int64
+	// StateDir records in-progress multipart uploads so a later run
+	// resumes them instead of starting over
+	StateDir string
+	HTTP     *http.Client
+	Out      io.Writer
+
+	now func() time.Time
+}
+
+// NewS3FromEnv reads credentials the way the AWS CLI does:
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN. region
+// defaults to AWS_REGION, AWS_DEFAULT_REGION, then us-east-1.
+func NewS3FromEnv(bucket, region, endpoint string) (*S3, error) {
+	s := &S3{
+		Bucket:       bucket,
+		Region:       region,
+		Endpoint:     strings.TrimSuffix(endpoint, "/"),
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		PartSize:     DefaultPartSize,
+		HTTP:         http.DefaultClient,
+		Out:          os.Stdout,
+	}
+	if s.AccessKey == "" || s.SecretKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required to upload to S3")
+	}
+	if s.Region == "" {
+		s.Region = os.Getenv("AWS_REGION")
+	}
+	if s.Region == "" {
+		s.Region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if s.Region == "" {
+		s.Region = "us-east-1"
+	}
+	return s, nil
+}
+
+// S3URL returns the URL of key in bucket: virtual-hosted on AWS, or
+// path-style under endpoint
+func S3URL(bucket, region, endpoint, key string) string {
+	key = escapeKey(key)
+	if endpoint != "" {
+		return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(endpoint, "/"), bucket, key)
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, region, key)
+}
+
+// Upload uploads the file at path to key. Files above
+// LargeAssetThreshold are sent as a multipart upload, retrying parts
+// individually and resuming an upload an earlier run left unfinished.
+func (s *S3) Upload(ctx context.Context, path, key string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	var requestID string
+	if info.Size() <= LargeAssetThreshold {
+		err = Retry(ctx, DefaultAttempts, func(int) error {
+			f, err := os.Open(path)
+			if err != nil {
+				return Permanent(err)
+			}
+			defer f.Close()
+			_, header, err := s.do(ctx, http.MethodPut, key, nil, f, info.Size())
+			requestID = audit.RequestID(header)
+			return err
+		})
+	} else {
+		requestID, err = s.uploadMultipart(ctx, path, key, info)
+	}
+	audit.Result(ctx, audit.ObjectUpload, S3URL(s.Bucket, s.Region, s.Endpoint, key), filepath.Base(path), requestID, err)
+	return err
+}
+
+// multipartState is what StateDir records about an unfinished upload
+type multipartState struct {
+	UploadID string    `json:"upload_id"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mod_time"`
+	PartSize int64     `json:"part_size"`
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+	Size       int64  `xml:"Size,omitempty"`
+}
+
+// uploadMultipart returns the ID of the request completing the upload
+func (s *S3) uploadMultipart(ctx context.Context, path, key string, info os.FileInfo) (string, error) {
+	partSize := s.PartSize
+	if partSize <= 0 {
+		partSize = DefaultPartSize
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	statePath := s.statePath(key)
+	uploaded := make(map[int]completedPart)
+	var state multipartState
+	if data, err := os.ReadFile(statePath); err == nil && json.Unmarshal(data, &state) == nil &&
+		state.Size == info.Size() && state.ModTime.Equal(info.ModTime()) && state.PartSize == partSize {
+		parts, err := s.listParts(ctx, key, state.UploadID)
+		if err == nil {
+			for _, part := range parts {
+				uploaded[part.PartNumber] = part
+			}
+			fmt.Fprintf(s.out(), "  Resuming upload of %s (%d part(s) already uploaded)\n", filepath.Base(path), len(parts))
+		} else {
+			state.UploadID = ""
+		}
+	} else {
+		state = multipartState{}
+	}
+
+	if state.UploadID == "" {
+		var result struct {
+			UploadID string `xml:"UploadId"`
+		}
+		err := Retry(ctx, DefaultAttempts, func(int) error {
+			body, _, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, 0)
+			if err != nil {
+				return err
+			}
+			return xml.Unmarshal(body, &result)
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to start multipart upload: %w", err)
+		}
+		state = multipartState{UploadID: result.UploadID, Size: info.Size(), ModTime: info.ModTime(), PartSize: partSize}
+		if err := s.saveState(statePath, state); err != nil {
+			return "", err
+		}
+	}
+
+	progress := NewProgress(s.out(), filepath.Base(path), info.Size())
+	count := int((info.Size() + partSize - 1) / partSize)
+	parts := make([]completedPart, 0, count)
+	var sent int64
+	for number := 1; number <= count; number++ {
+		offset := int64(number-1) * partSize
+		length := partSize
+		if offset+length > info.Size() {
+			length = info.Size() - offset
+		}
+
+		if part, ok := uploaded[number]; ok && part.Size == length {
+			parts = append(parts, completedPart{PartNumber: number, ETag: part.ETag})
+			sent += length
+			progress.Set(sent)
+			continue
+		}
+
+		query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {state.UploadID}}
+		var etag string
+		err := Retry(ctx, DefaultAttempts, func(int) error {
+			progress.Set(sent)
+			body := progress.Reader(io.NewSectionReader(f, offset, length))
+			_, header, err := s.do(ctx, http.MethodPut, key, query, body, length)
+			etag = header.Get("ETag")
+			return err
+		})
+		if err != nil {
+			progress.Done()
+			return "", fmt.Errorf("failed to upload part %d of %d (run again to resume): %w", number, count, err)
+		}
+		parts = append(parts, completedPart{PartNumber: number, ETag: etag})
+		sent += length
+	}
+	progress.Set(sent)
+	progress.Done()
+
+	complete, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return "", err
+	}
+	var requestID string
+	err = Retry(ctx, DefaultAttempts, func(int) error {
+		body, header, err := s.do(ctx, http.MethodPost, key, url.Values{"uploadId": {state.UploadID}}, bytes.NewReader(complete), int64(len(complete)))
+		requestID = audit.RequestID(header)
+		if err != nil {
+			return err
+		}
+		// Completion can fail after S3 has sent 200 OK
+		if bytes.Contains(body, []byte("<Error>")) {
+			return &StatusError{Code: http.StatusInternalServerError, Body: errorMessage(body)}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to complete multipart upload (run again to resume): %w", err)
+	}
+
+	os.Remove(statePath)
+	return requestID, nil
+}
+
+// listParts returns the parts already uploaded to an unfinished upload
+func (s *S3) listParts(ctx context.Context, key, uploadID string) ([]completedPart, error) {
+	var parts []completedPart
+	marker := ""
+	for {
+		query := url.Values{"uploadId": {uploadID}}
+		if marker != "" {
+			query.Set("part-number-marker", marker)
+		}
+		body, _, err := s.do(ctx, http.MethodGet, key, query, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Parts                []completedPart `xml:"Part"`
+			IsTruncated          bool            `xml:"IsTruncated"`
+			NextPartNumberMarker string          `xml:"NextPartNumberMarker"`
+		}
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, err
+		}
+		parts = append(parts, result.Parts...)
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		marker = result.NextPartNumberMarker
+	}
+}
+
+// do sends a signed request for key and returns the response body and
+// headers. Error responses are returned as a *StatusError.
+func (s *S3) do(ctx context.Context, method, key string, query url.Values, body io.Reader, length int64) ([]byte, http.Header, error) {
+	u, err := url.Parse(S3URL(s.Bucket, s.Region, s.Endpoint, key))
+	if err != nil {
+		return nil, nil, Permanent(err)
+	}
+	u.RawQuery = canonicalQuery(query)
+
+	if body == nil {
+		body = http.NoBody
+	} else {
+		body = Throttle(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, nil, Permanent(err)
+	}
+	req.ContentLength = length
+	s.sign(req)
+
+	client := s.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, resp.Header, &StatusError{Code: resp.StatusCode, Body: errorMessage(data)}
+	}
+	return data, resp.Header, nil
+}
+
+// sign adds an AWS Signature Version 4 Authorization header. The host,
+// range and x-amz-* headers are signed; the payload is unsigned unless
+// x-amz-content-sha256 is already set.
+func (s *S3) sign(req *http.Request) {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	t := now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+
+	req.Header.Set("x-amz-date", amzDate)
+	payloadHash := req.Header.Get("x-amz-content-sha256")
+	if payloadHash == "" {
+		payloadHash = unsignedPayload
+		req.Header.Set("x-amz-content-sha256", payloadHash)
+	}
+	if s.SessionToken != "" {
+		req.Header.Set("x-amz-security-token", s.SessionToken)
+	}
+
+	values := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "range" {
+			values[lower] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + values[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKey, scope, signedHeaders, signature))
+}
+
+func (s *S3) statePath(key string) string {
+	dir := s.StateDir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	sum := sha256.Sum256([]byte(s.Bucket + "/" + key))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+func (s *S3) saveState(path string, state multipartState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (s *S3) out() io.Writer {
+	if s.Out == nil {
+		return io.Discard
+	}
+	return s.Out
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// escapeKey percent-encodes each segment of an object key as SigV4
+// requires, leaving the separating slashes
+func escapeKey(key string) string {
+	segments := strings.Split(strings.TrimPrefix(key, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.QueryEscape(segment), "+", "%20")
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery encodes query sorted by key with spaces as %20
+func canonicalQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+// errorMessage extracts the Message of an S3 XML error
+func errorMessage(body []byte) string {
+	var s3Err struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if xml.Unmarshal(body, &s3Err) == nil && s3Err.Code != "" {
+		return s3Err.Code + ": " + s3Err.Message
+	}
+	return strings.TrimSpace(string(body))
+}
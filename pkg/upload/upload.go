@@ -0,0 +1,200 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upload moves large release assets reliably: progress display,
+// retries with backoff for transient failures, and resumable S3 multipart
+// uploads.
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// LargeAssetThreshold is the size above which uploads show progress and
+// S3 uploads switch to multipart
+const LargeAssetThreshold = 100 << 20
+
+// DefaultAttempts is how many times a transient failure is retried
+const DefaultAttempts = 5
+
+// Backoff is the wait before the first retry; it doubles each attempt
+var Backoff = 2 * time.Second
+
+// StatusError is an HTTP error response
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("%d %s", e.Code, http.StatusText(e.Code))
+	}
+	return fmt.Sprintf("%d %s: %s", e.Code, http.StatusText(e.Code), e.Body)
+}
+
+// permanentError marks an error that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so Retry returns it without retrying
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Transient reports whether err is worth retrying: network errors, server
+// errors, timeouts and rate limiting. Other HTTP errors and errors marked
+// Permanent are not.
+func Transient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.Code >= 500 || status.Code == http.StatusRequestTimeout || status.Code == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// Retry calls fn until it succeeds, returns an error that is not
+// Transient, or has been tried attempts times, backing off between tries
+func Retry(ctx context.Context, attempts int, fn func(attempt int) error) error {
+	wait := Backoff
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = fn(attempt); err == nil || !Transient(err) {
+			return err
+		}
+		if attempt == attempts {
+			break
+		}
+		fmt.Printf("⚠️  Attempt %d/%d failed: %v - retrying in %s\n", attempt, attempts, err, wait)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+	return fmt.Errorf("giving up after %d attempts: %w", attempts, err)
+}
+
+// Progress prints the progress of one upload on a single line
+type Progress struct {
+	w       io.Writer
+	name    string
+	total   int64
+	start   time.Time
+	mu      sync.Mutex
+	done    int64
+	printed time.Time
+}
+
+// NewProgress reports progress of uploading total bytes of name to w
+func NewProgress(w io.Writer, name string, total int64) *Progress {
+	return &Progress{w: w, name: name, total: total, start: time.Now()}
+}
+
+// Add records n more bytes sent
+func (p *Progress) Add(n int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done += n
+	if time.Since(p.printed) >= 500*time.Millisecond || p.done >= p.total {
+		p.printed = time.Now()
+		p.print()
+	}
+}
+
+// Set resets the bytes sent, e.g. when a part is retried
+func (p *Progress) Set(n int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done = n
+}
+
+// Done ends the progress line
+func (p *Progress) Done() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.print()
+	fmt.Fprintln(p.w)
+}
+
+func (p *Progress) print() {
+	percent := 100.0
+	if p.total > 0 {
+		percent = float64(p.done) * 100 / float64(p.total)
+	}
+	rate := float64(p.done) / time.Since(p.start).Seconds()
+	fmt.Fprintf(p.w, "\r  %s %5.1f%% (%s / %s) %s/s  ", p.name, percent, FormatBytes(p.done), FormatBytes(p.total), FormatBytes(int64(rate)))
+}
+
+// Reader returns r, counting bytes read from it as sent
+func (p *Progress) Reader(r io.Reader) io.Reader {
+	return &progressReader{r: r, p: p}
+}
+
+type progressReader struct {
+	r io.Reader
+	p *Progress
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.p.Add(int64(n))
+	return n, err
+}
+
+// FormatBytes formats n as a human readable size
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
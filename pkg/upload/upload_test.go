@@ -0,0 +1,129 @@
+/*
+Copyright 2026 Scott Friedman
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func shortBackoff(t *testing.T) {
+	t.Helper()
+	old := Backoff
+	Backoff = time.Millisecond
+	t.Cleanup(func() { Backoff = old })
+}
+
+func TestTransient(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("connection reset by peer"), true},
+		{&StatusError{Code: 503}, true},
+		{&StatusError{Code: 429}, true},
+		{&StatusError{Code: 408}, true},
+		{&StatusError{Code: 403}, false},
+		{&StatusError{Code: 422}, false},
+		{fmt.Errorf("wrapped: %w", &StatusError{Code: 502}), true},
+		{Permanent(errors.New("no such file")), false},
+		{context.Canceled, false},
+	}
+	for _, tt := range tests {
+		if got := Transient(tt.err); got != tt.want {
+			t.Errorf("Transient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	shortBackoff(t)
+
+	calls := 0
+	err := Retry(context.Background(), 5, func(attempt int) error {
+		calls++
+		if attempt != calls {
+			t.Errorf("attempt = %d, want %d", attempt, calls)
+		}
+		if attempt < 3 {
+			return errors.New("connection reset")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Retry = %v after %d calls, want success after 3", err, calls)
+	}
+
+	calls = 0
+	err = Retry(context.Background(), 5, func(int) error {
+		calls++
+		return &StatusError{Code: 401}
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("Retry of a permanent error = %v after %d calls, want failure after 1", err, calls)
+	}
+
+	calls = 0
+	err = Retry(context.Background(), 3, func(int) error {
+		calls++
+		return &StatusError{Code: 500}
+	})
+	var status *StatusError
+	if !errors.As(err, &status) || calls != 3 || !strings.Contains(err.Error(), "3 attempts") {
+		t.Errorf("Retry = %v after %d calls, want the last error after 3", err, calls)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	var out bytes.Buffer
+	p := NewProgress(&out, "app.tar.gz", 2048)
+	if _, err := io.Copy(io.Discard, p.Reader(strings.NewReader(strings.Repeat("x", 2048)))); err != nil {
+		t.Fatal(err)
+	}
+	p.Done()
+	if !strings.Contains(out.String(), "app.tar.gz 100.0% (2.0 KiB / 2.0 KiB)") {
+		t.Errorf("Unexpected progress output %q", out.String())
+	}
+
+	// A nil Progress is a no-op
+	var none *Progress
+	none.Add(1)
+	none.Set(0)
+	none.Done()
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:          "0 B",
+		1023:       "1023 B",
+		1536:       "1.5 KiB",
+		100 << 20:  "100.0 MiB",
+		1288490188: "1.2 GiB",
+	}
+	for n, want := range tests {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}